@@ -5,6 +5,7 @@ import (
 
 	"control/internal/app"
 	controlclient "control/internal/client"
+	"control/internal/transcriptexport"
 	"control/internal/types"
 )
 
@@ -27,7 +28,33 @@ type sessionCommandClient interface {
 	StreamTail(ctx context.Context, id, stream string) (<-chan types.LogEvent, func(), error)
 	SendMessage(ctx context.Context, sessionID string, req controlclient.SendSessionRequest) (*controlclient.SendSessionResponse, error)
 	ListApprovals(ctx context.Context, sessionID string) ([]*types.Approval, error)
+	ListAllApprovals(ctx context.Context) ([]*types.PendingApproval, error)
 	ApproveSession(ctx context.Context, sessionID string, req controlclient.ApproveSessionRequest) error
+	TestNotification(ctx context.Context, req types.NotificationTestRequest) (*types.NotificationTestResult, error)
+	ListWorktrees(ctx context.Context, workspaceID string) ([]*types.Worktree, error)
+	WorktreeOperation(ctx context.Context, workspaceID, worktreeID string, op types.WorktreeOperation, req types.WorktreeOperationRequest) (*types.WorktreeOperationResult, error)
+	PruneWorktrees(ctx context.Context, workspaceID string) (*types.WorktreeOperationResult, error)
+	UpdateWorktree(ctx context.Context, workspaceID, worktreeID string, worktree *types.Worktree) (*types.Worktree, error)
+	ScanWorkspaces(ctx context.Context, req types.WorkspaceScanRequest) (*types.WorkspaceScanResult, error)
+	ExportWorkspaces(ctx context.Context) (*types.WorkspaceExport, error)
+	ImportWorkspaces(ctx context.Context, export *types.WorkspaceExport, allowLocalData bool) (*types.WorkspaceImportResult, error)
+	ListSessionCheckpoints(ctx context.Context, sessionID string) ([]*types.SessionCheckpoint, error)
+	RestoreSessionCheckpoint(ctx context.Context, sessionID, checkpointID string, req types.SessionCheckpointRestoreRequest) (*types.SessionCheckpointRestoreResult, error)
+	SessionFinalizeDraft(ctx context.Context, sessionID string) (*types.FinalizeDraft, error)
+	FinalizeSession(ctx context.Context, sessionID string, req types.FinalizeRequest) (*types.FinalizeResult, error)
+	WorkflowRunFinalizeDraft(ctx context.Context, runID string) (*types.FinalizeDraft, error)
+	FinalizeWorkflowRun(ctx context.Context, runID string, req types.FinalizeRequest) (*types.FinalizeResult, error)
+	ListSnippets(ctx context.Context, workspaceID string) ([]*types.Snippet, error)
+	CreateSnippet(ctx context.Context, snippet *types.Snippet) (*types.Snippet, error)
+	UpdateSnippet(ctx context.Context, id string, snippet *types.Snippet) (*types.Snippet, error)
+	DeleteSnippet(ctx context.Context, id string) error
+	RenderSnippet(ctx context.Context, id string, values map[string]string) (*controlclient.RenderSnippetResponse, error)
+	ListApprovalRules(ctx context.Context) ([]*types.ApprovalRule, error)
+	CreateApprovalRule(ctx context.Context, rule *types.ApprovalRule) (*types.ApprovalRule, error)
+	UpdateApprovalRule(ctx context.Context, id string, rule *types.ApprovalRule) (*types.ApprovalRule, error)
+	DeleteApprovalRule(ctx context.Context, id string) error
+	ExportTranscript(ctx context.Context, id string, format transcriptexport.Format, options transcriptexport.Options) ([]byte, error)
+	ExportTranscriptDocument(ctx context.Context, id string, options transcriptexport.Options) (*transcriptexport.Document, error)
 }
 
 type daemonVersionClient interface {
@@ -134,10 +161,114 @@ func (c *controlClientAdapter) ListApprovals(ctx context.Context, sessionID stri
 	return c.client.ListApprovals(ctx, sessionID)
 }
 
+func (c *controlClientAdapter) ListAllApprovals(ctx context.Context) ([]*types.PendingApproval, error) {
+	return c.client.ListAllApprovals(ctx)
+}
+
 func (c *controlClientAdapter) ApproveSession(ctx context.Context, sessionID string, req controlclient.ApproveSessionRequest) error {
 	return c.client.ApproveSession(ctx, sessionID, req)
 }
 
+func (c *controlClientAdapter) TestNotification(ctx context.Context, req types.NotificationTestRequest) (*types.NotificationTestResult, error) {
+	return c.client.TestNotification(ctx, req)
+}
+
+func (c *controlClientAdapter) ListWorktrees(ctx context.Context, workspaceID string) ([]*types.Worktree, error) {
+	return c.client.ListWorktrees(ctx, workspaceID)
+}
+
+func (c *controlClientAdapter) WorktreeOperation(ctx context.Context, workspaceID, worktreeID string, op types.WorktreeOperation, req types.WorktreeOperationRequest) (*types.WorktreeOperationResult, error) {
+	return c.client.WorktreeOperation(ctx, workspaceID, worktreeID, op, req)
+}
+
+func (c *controlClientAdapter) PruneWorktrees(ctx context.Context, workspaceID string) (*types.WorktreeOperationResult, error) {
+	return c.client.PruneWorktrees(ctx, workspaceID)
+}
+
+func (c *controlClientAdapter) UpdateWorktree(ctx context.Context, workspaceID, worktreeID string, worktree *types.Worktree) (*types.Worktree, error) {
+	return c.client.UpdateWorktree(ctx, workspaceID, worktreeID, worktree)
+}
+
+func (c *controlClientAdapter) ScanWorkspaces(ctx context.Context, req types.WorkspaceScanRequest) (*types.WorkspaceScanResult, error) {
+	return c.client.ScanWorkspaces(ctx, req)
+}
+
+func (c *controlClientAdapter) ExportWorkspaces(ctx context.Context) (*types.WorkspaceExport, error) {
+	return c.client.ExportWorkspaces(ctx)
+}
+
+func (c *controlClientAdapter) ImportWorkspaces(ctx context.Context, export *types.WorkspaceExport, allowLocalData bool) (*types.WorkspaceImportResult, error) {
+	return c.client.ImportWorkspaces(ctx, export, allowLocalData)
+}
+
+func (c *controlClientAdapter) ListSessionCheckpoints(ctx context.Context, sessionID string) ([]*types.SessionCheckpoint, error) {
+	return c.client.ListSessionCheckpoints(ctx, sessionID)
+}
+
+func (c *controlClientAdapter) RestoreSessionCheckpoint(ctx context.Context, sessionID, checkpointID string, req types.SessionCheckpointRestoreRequest) (*types.SessionCheckpointRestoreResult, error) {
+	return c.client.RestoreSessionCheckpoint(ctx, sessionID, checkpointID, req)
+}
+
+func (c *controlClientAdapter) SessionFinalizeDraft(ctx context.Context, sessionID string) (*types.FinalizeDraft, error) {
+	return c.client.SessionFinalizeDraft(ctx, sessionID)
+}
+
+func (c *controlClientAdapter) FinalizeSession(ctx context.Context, sessionID string, req types.FinalizeRequest) (*types.FinalizeResult, error) {
+	return c.client.FinalizeSession(ctx, sessionID, req)
+}
+
+func (c *controlClientAdapter) WorkflowRunFinalizeDraft(ctx context.Context, runID string) (*types.FinalizeDraft, error) {
+	return c.client.WorkflowRunFinalizeDraft(ctx, runID)
+}
+
+func (c *controlClientAdapter) FinalizeWorkflowRun(ctx context.Context, runID string, req types.FinalizeRequest) (*types.FinalizeResult, error) {
+	return c.client.FinalizeWorkflowRun(ctx, runID, req)
+}
+
+func (c *controlClientAdapter) ListSnippets(ctx context.Context, workspaceID string) ([]*types.Snippet, error) {
+	return c.client.ListSnippets(ctx, workspaceID)
+}
+
+func (c *controlClientAdapter) CreateSnippet(ctx context.Context, snippet *types.Snippet) (*types.Snippet, error) {
+	return c.client.CreateSnippet(ctx, snippet)
+}
+
+func (c *controlClientAdapter) UpdateSnippet(ctx context.Context, id string, snippet *types.Snippet) (*types.Snippet, error) {
+	return c.client.UpdateSnippet(ctx, id, snippet)
+}
+
+func (c *controlClientAdapter) DeleteSnippet(ctx context.Context, id string) error {
+	return c.client.DeleteSnippet(ctx, id)
+}
+
+func (c *controlClientAdapter) RenderSnippet(ctx context.Context, id string, values map[string]string) (*controlclient.RenderSnippetResponse, error) {
+	return c.client.RenderSnippet(ctx, id, values)
+}
+
+func (c *controlClientAdapter) ListApprovalRules(ctx context.Context) ([]*types.ApprovalRule, error) {
+	return c.client.ListApprovalRules(ctx)
+}
+
+func (c *controlClientAdapter) CreateApprovalRule(ctx context.Context, rule *types.ApprovalRule) (*types.ApprovalRule, error) {
+	return c.client.CreateApprovalRule(ctx, rule)
+}
+
+func (c *controlClientAdapter) UpdateApprovalRule(ctx context.Context, id string, rule *types.ApprovalRule) (*types.ApprovalRule, error) {
+	return c.client.UpdateApprovalRule(ctx, id, rule)
+}
+
+func (c *controlClientAdapter) DeleteApprovalRule(ctx context.Context, id string) error {
+	return c.client.DeleteApprovalRule(ctx, id)
+}
+
+func (c *controlClientAdapter) ExportTranscript(ctx context.Context, id string, format transcriptexport.Format, options transcriptexport.Options) ([]byte, error) {
+	return c.client.ExportTranscript(ctx, id, format, options)
+}
+
+func (c *controlClientAdapter) ExportTranscriptDocument(ctx context.Context, id string, options transcriptexport.Options) (*transcriptexport.Document, error) {
+	return c.client.ExportTranscriptDocument(ctx, id, options)
+}
+
 func (c *controlClientAdapter) ShutdownDaemon(ctx context.Context) error {
 	return c.client.ShutdownDaemon(ctx)
 }
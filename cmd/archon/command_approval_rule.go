@@ -0,0 +1,330 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"control/internal/types"
+)
+
+type ApprovalRuleCommand struct {
+	stdout    io.Writer
+	stderr    io.Writer
+	newClient sessionClientFactory
+}
+
+func NewApprovalRuleCommand(stdout, stderr io.Writer, newClient sessionClientFactory) *ApprovalRuleCommand {
+	return &ApprovalRuleCommand{
+		stdout:    stdout,
+		stderr:    stderr,
+		newClient: newClient,
+	}
+}
+
+func (c *ApprovalRuleCommand) Run(args []string) error {
+	if len(args) == 0 {
+		return errors.New("approval-rule requires a subcommand: list, add, edit, enable, disable, remove")
+	}
+	switch args[0] {
+	case "list":
+		return c.runList(args[1:])
+	case "add":
+		return c.runAdd(args[1:])
+	case "edit":
+		return c.runEdit(args[1:])
+	case "enable":
+		return c.runSetDisabled("enable", args[1:], false)
+	case "disable":
+		return c.runSetDisabled("disable", args[1:], true)
+	case "remove":
+		return c.runRemove(args[1:])
+	default:
+		return fmt.Errorf("unknown approval-rule subcommand %q", args[0])
+	}
+}
+
+func (c *ApprovalRuleCommand) connect(ctx context.Context) (sessionCommandClient, error) {
+	client, err := c.newClient()
+	if err != nil {
+		return nil, err
+	}
+	if err := client.EnsureDaemon(ctx); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (c *ApprovalRuleCommand) runList(args []string) error {
+	fs := flag.NewFlagSet("approval-rule list", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	emitJSON := fs.Bool("json", false, "emit machine-readable JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx := context.Background()
+	client, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	rules, err := client.ListApprovalRules(ctx)
+	if err != nil {
+		return err
+	}
+	if *emitJSON {
+		encoded, err := json.MarshalIndent(rules, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(c.stdout, string(encoded))
+		return nil
+	}
+	writer := tabwriter.NewWriter(c.stdout, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "ID\tNAME\tSCOPE\tMATCH\tDECISION")
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		decision := string(rule.Decision)
+		if rule.Disabled {
+			decision += " (disabled)"
+		}
+		name := rule.Name
+		if name == "" {
+			name = "-"
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", rule.ID, name, approvalRuleScopeSummary(rule), approvalRuleMatchSummary(rule), decision)
+	}
+	return writer.Flush()
+}
+
+func (c *ApprovalRuleCommand) runAdd(args []string) error {
+	fs := flag.NewFlagSet("approval-rule add", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	rule := &types.ApprovalRule{}
+	bindApprovalRuleFlags(fs, rule)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	if strings.TrimSpace(string(rule.Decision)) == "" {
+		return errors.New("approval-rule add requires --decision")
+	}
+	ctx := context.Background()
+	client, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	created, err := client.CreateApprovalRule(ctx, rule)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(c.stdout, "added approval rule %s: %s %s\n", created.ID, created.Decision, approvalRuleMatchSummary(created))
+	return nil
+}
+
+func (c *ApprovalRuleCommand) runEdit(args []string) error {
+	fs := flag.NewFlagSet("approval-rule edit", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	edits := &types.ApprovalRule{}
+	bindApprovalRuleFlags(fs, edits)
+	if err := fs.Parse(reorderFlagsBeforePositional(args)); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("approval-rule edit requires a rule id or name")
+	}
+	ctx := context.Background()
+	client, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	rule, err := resolveApprovalRule(ctx, client, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	// Only the flags that were passed replace fields; pass an empty value to
+	// clear a matcher.
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "name":
+			rule.Name = edits.Name
+		case "scope":
+			rule.Scope = edits.Scope
+		case "workspace":
+			rule.WorkspaceID = edits.WorkspaceID
+		case "provider":
+			rule.Provider = edits.Provider
+		case "session":
+			rule.SessionID = edits.SessionID
+		case "method":
+			rule.Method = edits.Method
+		case "prefix":
+			rule.CommandPrefix = edits.CommandPrefix
+		case "regex":
+			rule.CommandRegex = edits.CommandRegex
+		case "path":
+			rule.PathGlobs = edits.PathGlobs
+		case "access":
+			rule.Access = edits.Access
+		case "decision":
+			rule.Decision = edits.Decision
+		}
+	})
+	updated, err := client.UpdateApprovalRule(ctx, rule.ID, rule)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(c.stdout, "updated approval rule %s: %s %s\n", updated.ID, updated.Decision, approvalRuleMatchSummary(updated))
+	return nil
+}
+
+func (c *ApprovalRuleCommand) runSetDisabled(name string, args []string, disabled bool) error {
+	fs := flag.NewFlagSet("approval-rule "+name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return fmt.Errorf("approval-rule %s requires a rule id or name", name)
+	}
+	ctx := context.Background()
+	client, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	rule, err := resolveApprovalRule(ctx, client, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	rule.Disabled = disabled
+	if _, err := client.UpdateApprovalRule(ctx, rule.ID, rule); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(c.stdout, "%sd approval rule %s\n", name, rule.ID)
+	return nil
+}
+
+func (c *ApprovalRuleCommand) runRemove(args []string) error {
+	fs := flag.NewFlagSet("approval-rule remove", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("approval-rule remove requires a rule id or name")
+	}
+	ctx := context.Background()
+	client, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	rule, err := resolveApprovalRule(ctx, client, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if err := client.DeleteApprovalRule(ctx, rule.ID); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(c.stdout, "removed approval rule %s\n", rule.ID)
+	return nil
+}
+
+func bindApprovalRuleFlags(fs *flag.FlagSet, rule *types.ApprovalRule) {
+	fs.StringVar(&rule.Name, "name", "", "label shown in lists and the transcript")
+	fs.Func("scope", "global, provider, workspace or session (inferred from the ids when omitted)", func(value string) error {
+		rule.Scope = types.ApprovalRuleScope(value)
+		return nil
+	})
+	fs.StringVar(&rule.WorkspaceID, "workspace", "", "apply to sessions in this workspace")
+	fs.StringVar(&rule.Provider, "provider", "", "apply to sessions of this provider")
+	fs.StringVar(&rule.SessionID, "session", "", "apply to this session only")
+	fs.StringVar(&rule.Method, "method", "", "approval kind (command, file_change, user_input, plan) or provider method")
+	fs.StringVar(&rule.CommandPrefix, "prefix", "", "match simple commands starting with these words")
+	fs.StringVar(&rule.CommandRegex, "regex", "", "match commands against this regular expression")
+	fs.Func("path", "file path glob every changed path must match (repeatable)", func(value string) error {
+		if strings.TrimSpace(value) != "" {
+			rule.PathGlobs = append(rule.PathGlobs, value)
+		}
+		return nil
+	})
+	fs.Func("access", "match only sessions running with this access level", func(value string) error {
+		rule.Access = types.AccessLevel(value)
+		return nil
+	})
+	fs.Func("decision", "allow_once, allow_always, deny or ask", func(value string) error {
+		rule.Decision = types.ApprovalRuleDecision(value)
+		return nil
+	})
+}
+
+// resolveApprovalRule finds a rule by id, or by its unique name.
+func resolveApprovalRule(ctx context.Context, client sessionCommandClient, ref string) (*types.ApprovalRule, error) {
+	ref = strings.TrimSpace(ref)
+	rules, err := client.ListApprovalRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if rule != nil && rule.ID == ref {
+			return rule, nil
+		}
+	}
+	var match *types.ApprovalRule
+	for _, rule := range rules {
+		if rule == nil || rule.Name != ref {
+			continue
+		}
+		if match != nil {
+			return nil, fmt.Errorf("approval rule name %q is ambiguous; pass the rule id", ref)
+		}
+		match = rule
+	}
+	if match == nil {
+		return nil, fmt.Errorf("approval rule %q not found", ref)
+	}
+	return match, nil
+}
+
+func approvalRuleScopeSummary(rule *types.ApprovalRule) string {
+	switch rule.Scope {
+	case types.ApprovalRuleScopeWorkspace:
+		return "workspace (" + rule.WorkspaceID + ")"
+	case types.ApprovalRuleScopeProvider:
+		return "provider (" + rule.Provider + ")"
+	case types.ApprovalRuleScopeSession:
+		return "session (" + rule.SessionID + ")"
+	default:
+		return string(rule.Scope)
+	}
+}
+
+func approvalRuleMatchSummary(rule *types.ApprovalRule) string {
+	parts := []string{}
+	if rule.Method != "" {
+		parts = append(parts, "method="+rule.Method)
+	}
+	if rule.CommandPrefix != "" {
+		parts = append(parts, fmt.Sprintf("prefix=%q", rule.CommandPrefix))
+	}
+	if rule.CommandRegex != "" {
+		parts = append(parts, "regex="+rule.CommandRegex)
+	}
+	if len(rule.PathGlobs) > 0 {
+		parts = append(parts, "path="+strings.Join(rule.PathGlobs, ","))
+	}
+	if rule.Access != "" {
+		parts = append(parts, "access="+string(rule.Access))
+	}
+	if len(parts) == 0 {
+		return "any"
+	}
+	return strings.Join(parts, " ")
+}
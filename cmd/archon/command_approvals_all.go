@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// ApprovalsAllCommand lists pending approvals across every session for
+// `approvals --all` and hands any other invocation to the per-session
+// approvals command.
+type ApprovalsAllCommand struct {
+	stdout    io.Writer
+	stderr    io.Writer
+	newClient sessionClientFactory
+	next      commandRunner
+	now       func() time.Time
+}
+
+func NewApprovalsAllCommand(stdout, stderr io.Writer, newClient sessionClientFactory, next commandRunner) *ApprovalsAllCommand {
+	return &ApprovalsAllCommand{
+		stdout:    stdout,
+		stderr:    stderr,
+		newClient: newClient,
+		next:      next,
+		now:       time.Now,
+	}
+}
+
+func (c *ApprovalsAllCommand) Run(args []string) error {
+	if !hasAllApprovalsFlag(args) {
+		if c.next == nil {
+			return fmt.Errorf("approvals requires a session id or --all")
+		}
+		return c.next.Run(args)
+	}
+	fs := flag.NewFlagSet("approvals", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Bool("all", false, "list pending approvals of every session")
+	emitJSON := fs.Bool("json", false, "emit machine-readable JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("approvals --all does not take a session id")
+	}
+	ctx := context.Background()
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	if err := client.EnsureDaemon(ctx); err != nil {
+		return err
+	}
+	approvals, err := client.ListAllApprovals(ctx)
+	if err != nil {
+		return err
+	}
+	if *emitJSON {
+		encoded, err := json.MarshalIndent(approvals, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(c.stdout, string(encoded))
+		return nil
+	}
+	writer := tabwriter.NewWriter(c.stdout, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "SESSION\tREQUEST_ID\tPROVIDER\tWORKSPACE\tAGE\tPREVIEW")
+	for _, approval := range approvals {
+		if approval == nil {
+			continue
+		}
+		workspace := approvalsAllField(approval.WorkspaceName, approval.WorkspaceID, "-")
+		preview := approvalsAllField(approval.Preview, approval.Method)
+		_, _ = fmt.Fprintf(writer, "%s\t%d\t%s\t%s\t%s\t%s\n",
+			approval.SessionID,
+			approval.RequestID,
+			approvalsAllField(approval.Provider, "-"),
+			workspace,
+			approvalAge(c.now(), approval.CreatedAt),
+			truncateApprovalPreview(preview, 80),
+		)
+	}
+	return writer.Flush()
+}
+
+func hasAllApprovalsFlag(args []string) bool {
+	for _, arg := range args {
+		switch arg {
+		case "--all", "-all", "--all=true", "-all=true":
+			return true
+		}
+	}
+	return false
+}
+
+func approvalAge(now, createdAt time.Time) string {
+	if createdAt.IsZero() {
+		return "-"
+	}
+	age := now.Sub(createdAt)
+	switch {
+	case age < time.Minute:
+		return "<1m"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age/time.Minute))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age/time.Hour))
+	default:
+		return fmt.Sprintf("%dd", int(age/(24*time.Hour)))
+	}
+}
+
+func truncateApprovalPreview(value string, limit int) string {
+	value = strings.Join(strings.Fields(value), " ")
+	runes := []rune(value)
+	if limit <= 0 || len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+func approvalsAllField(values ...string) string {
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
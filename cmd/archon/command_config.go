@@ -100,12 +100,36 @@ type effectiveDebugConfig struct {
 }
 
 type effectiveNotificationsConfig struct {
-	Enabled              bool     `json:"enabled" toml:"enabled"`
-	Triggers             []string `json:"triggers,omitempty" toml:"triggers,omitempty"`
-	Methods              []string `json:"methods,omitempty" toml:"methods,omitempty"`
-	ScriptCommands       []string `json:"script_commands,omitempty" toml:"script_commands,omitempty"`
-	ScriptTimeoutSeconds int      `json:"script_timeout_seconds" toml:"script_timeout_seconds"`
-	DedupeWindowSeconds  int      `json:"dedupe_window_seconds" toml:"dedupe_window_seconds"`
+	Enabled                bool                                 `json:"enabled" toml:"enabled"`
+	Triggers               []string                             `json:"triggers,omitempty" toml:"triggers,omitempty"`
+	Methods                []string                             `json:"methods,omitempty" toml:"methods,omitempty"`
+	ScriptCommands         []string                             `json:"script_commands,omitempty" toml:"script_commands,omitempty"`
+	ScriptTimeoutSeconds   int                                  `json:"script_timeout_seconds" toml:"script_timeout_seconds"`
+	DedupeWindowSeconds    int                                  `json:"dedupe_window_seconds" toml:"dedupe_window_seconds"`
+	Webhooks               []effectiveNotificationWebhookConfig `json:"webhooks,omitempty" toml:"webhooks,omitempty"`
+	WebhookRetries         int                                  `json:"webhook_retries" toml:"webhook_retries"`
+	WebhookTimeoutSeconds  int                                  `json:"webhook_timeout_seconds" toml:"webhook_timeout_seconds"`
+	LongRunningTurnMinutes int                                  `json:"long_running_turn_minutes" toml:"long_running_turn_minutes"`
+	QuietHoursStart        string                               `json:"quiet_hours_start,omitempty" toml:"quiet_hours_start,omitempty"`
+	QuietHoursEnd          string                               `json:"quiet_hours_end,omitempty" toml:"quiet_hours_end,omitempty"`
+	RateLimits             []effectiveNotificationRateLimit     `json:"rate_limits,omitempty" toml:"rate_limits,omitempty"`
+	BatchWindowSeconds     int                                  `json:"batch_window_seconds" toml:"batch_window_seconds"`
+	DigestTime             string                               `json:"digest_time,omitempty" toml:"digest_time,omitempty"`
+}
+
+type effectiveNotificationRateLimit struct {
+	Trigger       string `json:"trigger" toml:"trigger"`
+	Max           int    `json:"max" toml:"max"`
+	WindowSeconds int    `json:"window_seconds,omitempty" toml:"window_seconds,omitempty"`
+}
+
+// effectiveNotificationWebhookConfig omits secrets and header values; config
+// output is routinely pasted into bug reports.
+type effectiveNotificationWebhookConfig struct {
+	URL       string `json:"url" toml:"url"`
+	Format    string `json:"format,omitempty" toml:"format,omitempty"`
+	SecretEnv string `json:"secret_env,omitempty" toml:"secret_env,omitempty"`
+	Signed    bool   `json:"signed" toml:"signed"`
 }
 
 type effectiveGuidedWorkflowsConfig struct {
@@ -295,13 +319,21 @@ func (c *ConfigCommand) buildOutput(defaults bool, scopes map[string]struct{}) (
 			StreamDebug: coreCfg.StreamDebugEnabled(),
 		}
 		out.Notifications = &effectiveNotificationsConfig{
-			Enabled:              coreCfg.NotificationsEnabled(),
-			Triggers:             coreCfg.NotificationTriggers(),
-			Methods:              coreCfg.NotificationMethods(),
-			ScriptCommands:       coreCfg.NotificationScriptCommands(),
-			ScriptTimeoutSeconds: coreCfg.NotificationScriptTimeoutSeconds(),
-			DedupeWindowSeconds:  coreCfg.NotificationDedupeWindowSeconds(),
-		}
+			Enabled:                coreCfg.NotificationsEnabled(),
+			Triggers:               coreCfg.NotificationTriggers(),
+			Methods:                coreCfg.NotificationMethods(),
+			ScriptCommands:         coreCfg.NotificationScriptCommands(),
+			ScriptTimeoutSeconds:   coreCfg.NotificationScriptTimeoutSeconds(),
+			DedupeWindowSeconds:    coreCfg.NotificationDedupeWindowSeconds(),
+			Webhooks:               effectiveNotificationWebhooks(coreCfg.NotificationWebhooks()),
+			WebhookRetries:         coreCfg.NotificationWebhookRetries(),
+			WebhookTimeoutSeconds:  coreCfg.NotificationWebhookTimeoutSeconds(),
+			LongRunningTurnMinutes: coreCfg.NotificationLongRunningTurnMinutes(),
+			RateLimits:             effectiveNotificationRateLimits(coreCfg.NotificationRateLimits()),
+			BatchWindowSeconds:     coreCfg.NotificationBatchWindowSeconds(),
+			DigestTime:             coreCfg.NotificationDigestTime(),
+		}
+		out.Notifications.QuietHoursStart, out.Notifications.QuietHoursEnd = coreCfg.NotificationQuietHours()
 		out.GuidedWorkflows = &effectiveGuidedWorkflowsConfig{
 			Enabled:         coreCfg.GuidedWorkflowsEnabled(),
 			AutoStart:       coreCfg.GuidedWorkflowsAutoStart(),
@@ -484,11 +516,17 @@ func projectedConfigPayload(payload configOutput, scopes map[string]struct{}) an
 				StreamDebug: false,
 			},
 			Notifications: effectiveNotificationsConfig{
-				Enabled:              true,
-				Triggers:             []string{"turn.completed", "session.failed", "session.killed", "session.exited"},
-				Methods:              []string{"auto"},
-				ScriptTimeoutSeconds: 10,
-				DedupeWindowSeconds:  5,
+				Enabled: true,
+				Triggers: []string{
+					"turn.completed", "session.failed", "session.killed", "session.exited",
+					"approval.pending", "workflow.decision_needed", "provider.rate_limited",
+				},
+				Methods:                []string{"auto"},
+				ScriptTimeoutSeconds:   10,
+				DedupeWindowSeconds:    5,
+				WebhookRetries:         3,
+				WebhookTimeoutSeconds:  10,
+				LongRunningTurnMinutes: 20,
 			},
 			GuidedWorkflows: effectiveGuidedWorkflowsConfig{
 				Enabled:         false,
@@ -607,3 +645,34 @@ func scopeSelected(scopes map[string]struct{}, scope string) bool {
 	_, ok := scopes[scope]
 	return ok
 }
+
+func effectiveNotificationRateLimits(limits []config.CoreNotificationRateLimitConfig) []effectiveNotificationRateLimit {
+	if len(limits) == 0 {
+		return nil
+	}
+	out := make([]effectiveNotificationRateLimit, 0, len(limits))
+	for _, limit := range limits {
+		out = append(out, effectiveNotificationRateLimit{
+			Trigger:       limit.Trigger,
+			Max:           limit.Max,
+			WindowSeconds: limit.WindowSeconds,
+		})
+	}
+	return out
+}
+
+func effectiveNotificationWebhooks(webhooks []config.CoreNotificationWebhookConfig) []effectiveNotificationWebhookConfig {
+	if len(webhooks) == 0 {
+		return nil
+	}
+	out := make([]effectiveNotificationWebhookConfig, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		out = append(out, effectiveNotificationWebhookConfig{
+			URL:       webhook.URL,
+			Format:    webhook.Format,
+			SecretEnv: webhook.SecretEnv,
+			Signed:    strings.TrimSpace(webhook.Secret) != "" || webhook.SecretEnv != "",
+		})
+	}
+	return out
+}
@@ -121,6 +121,14 @@ func runDaemonProcess(background bool) error {
 	if err != nil {
 		return err
 	}
+	snippetsPath, err := config.SnippetsPath()
+	if err != nil {
+		return err
+	}
+	approvalRulesPath, err := config.ApprovalRulesPath()
+	if err != nil {
+		return err
+	}
 	repositoryPaths := store.RepositoryPaths{
 		WorkspacesPath:        workspacesPath,
 		WorkflowTemplatesPath: workflowTemplatesPath,
@@ -130,6 +138,8 @@ func runDaemonProcess(background bool) error {
 		SessionIndexPath:      sessionsIndexPath,
 		ApprovalsPath:         approvalsPath,
 		NotesPath:             notesPath,
+		SnippetsPath:          snippetsPath,
+		ApprovalRulesPath:     approvalRulesPath,
 		DBPath:                storagePath,
 	}
 	repository, err := store.OpenRepository(repositoryPaths, store.RepositoryBackendBbolt)
@@ -151,6 +161,8 @@ func runDaemonProcess(background bool) error {
 		Sessions:          repository.SessionIndex(),
 		Approvals:         repository.Approvals(),
 		Notes:             repository.Notes(),
+		Snippets:          repository.Snippets(),
+		ApprovalRules:     repository.ApprovalRules(),
 	}
 	coreCfg, err := config.LoadCoreConfig()
 	if err != nil {
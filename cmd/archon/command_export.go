@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"control/internal/app"
+	"control/internal/config"
+	"control/internal/transcriptexport"
+)
+
+type ExportCommand struct {
+	stdout    io.Writer
+	stderr    io.Writer
+	newClient sessionClientFactory
+}
+
+func NewExportCommand(stdout, stderr io.Writer, newClient sessionClientFactory) *ExportCommand {
+	return &ExportCommand{
+		stdout:    stdout,
+		stderr:    stderr,
+		newClient: newClient,
+	}
+}
+
+func (c *ExportCommand) Run(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	formatName := fs.String("format", "md", "output format: md, html or json")
+	output := fs.String("output", "", "write the transcript to this file instead of stdout")
+	fs.StringVar(output, "o", "", "shorthand for --output")
+	defaults := transcriptexport.DefaultOptions()
+	reasoning := fs.Bool("reasoning", defaults.Reasoning, "include reasoning blocks")
+	tools := fs.Bool("tools", defaults.ToolCalls, "include tool calls and command output")
+	approvals := fs.Bool("approvals", defaults.Approvals, "include pending approvals")
+	timestamps := fs.Bool("timestamps", defaults.Timestamps, "include message timestamps")
+	theme := fs.String("theme", "", "html theme id (default: the configured UI theme)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("export requires a session id")
+	}
+	id := fs.Arg(0)
+	// Allow flags after the session id as well.
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	format, err := transcriptexport.ParseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+	options := transcriptexport.Options{
+		Reasoning:  *reasoning,
+		ToolCalls:  *tools,
+		Approvals:  *approvals,
+		Timestamps: *timestamps,
+	}
+
+	ctx := context.Background()
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	if err := client.EnsureDaemon(ctx); err != nil {
+		return err
+	}
+	var body []byte
+	if format == transcriptexport.FormatHTML {
+		// The daemon does not know the UI theme, so HTML is rendered here.
+		doc, err := client.ExportTranscriptDocument(ctx, id, options)
+		if err != nil {
+			return err
+		}
+		body = []byte(transcriptexport.HTML(*doc, app.TranscriptExportTheme(exportThemeID(*theme))))
+	} else {
+		body, err = client.ExportTranscript(ctx, id, format, options)
+		if err != nil {
+			return err
+		}
+	}
+	if *output == "" {
+		_, err := c.stdout.Write(body)
+		return err
+	}
+	if err := os.WriteFile(*output, body, 0o644); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(c.stdout, "exported %s to %s\n", id, *output)
+	return nil
+}
+
+func exportThemeID(explicit string) string {
+	if explicit = strings.TrimSpace(explicit); explicit != "" {
+		return explicit
+	}
+	uiConfig, err := config.LoadUIConfig()
+	if err != nil {
+		return ""
+	}
+	return uiConfig.ThemeName()
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"control/internal/types"
+)
+
+type FinalizeCommand struct {
+	stdout    io.Writer
+	stderr    io.Writer
+	newClient sessionClientFactory
+}
+
+func NewFinalizeCommand(stdout, stderr io.Writer, newClient sessionClientFactory) *FinalizeCommand {
+	return &FinalizeCommand{
+		stdout:    stdout,
+		stderr:    stderr,
+		newClient: newClient,
+	}
+}
+
+func (c *FinalizeCommand) Run(args []string) error {
+	fs := flag.NewFlagSet("finalize", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	run := fs.Bool("run", false, "treat the id as a workflow run id")
+	message := fs.String("message", "", "commit message (default: the drafted message)")
+	messageFile := fs.String("message-file", "", "read the commit message from a file")
+	dryRun := fs.Bool("dry-run", false, "print the drafted commit message without committing")
+	prPath := fs.String("pr", "", "write the pull request description to this file (- for stdout)")
+	emitJSON := fs.Bool("json", false, "emit machine-readable JSON")
+	id := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		id, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if id == "" && fs.NArg() > 0 {
+		id = fs.Arg(0)
+	}
+	if id == "" {
+		return errors.New("finalize requires a session id (or --run with a workflow run id)")
+	}
+	if *message != "" && *messageFile != "" {
+		return errors.New("use either --message or --message-file")
+	}
+	if *messageFile != "" {
+		data, err := os.ReadFile(*messageFile)
+		if err != nil {
+			return err
+		}
+		*message = string(data)
+	}
+
+	ctx := context.Background()
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	if err := client.EnsureDaemon(ctx); err != nil {
+		return err
+	}
+	if *dryRun {
+		var draft *types.FinalizeDraft
+		if *run {
+			draft, err = client.WorkflowRunFinalizeDraft(ctx, id)
+		} else {
+			draft, err = client.SessionFinalizeDraft(ctx, id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := c.writePullRequest(*prPath, draft.PullRequest); err != nil {
+			return err
+		}
+		if *emitJSON {
+			return c.writeJSON(draft)
+		}
+		printFinalizeDraft(c.stdout, draft)
+		return nil
+	}
+	req := types.FinalizeRequest{Message: *message, PullRequest: *prPath != ""}
+	var result *types.FinalizeResult
+	if *run {
+		result, err = client.FinalizeWorkflowRun(ctx, id, req)
+	} else {
+		result, err = client.FinalizeSession(ctx, id, req)
+	}
+	if err != nil {
+		return err
+	}
+	if err := c.writePullRequest(*prPath, result.PullRequest); err != nil {
+		return err
+	}
+	if *emitJSON {
+		return c.writeJSON(result)
+	}
+	if *prPath != "-" {
+		printFinalizeResult(c.stdout, result, *prPath)
+	}
+	return nil
+}
+
+func (c *FinalizeCommand) writePullRequest(path, markdown string) error {
+	switch path {
+	case "":
+		return nil
+	case "-":
+		_, err := io.WriteString(c.stdout, markdown)
+		return err
+	default:
+		return os.WriteFile(path, []byte(markdown), 0o644)
+	}
+}
+
+func (c *FinalizeCommand) writeJSON(value any) error {
+	encoded, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(c.stdout, string(encoded))
+	return nil
+}
+
+func printFinalizeDraft(output io.Writer, draft *types.FinalizeDraft) {
+	_, _ = fmt.Fprintf(output, "repository: %s\n", draft.Root)
+	if draft.Branch != "" {
+		_, _ = fmt.Fprintf(output, "branch: %s\n", draft.Branch)
+	}
+	if draft.Diff != nil {
+		paths := make([]string, 0, len(draft.Diff.Files)+len(draft.Diff.Untracked))
+		for _, file := range draft.Diff.Files {
+			paths = append(paths, file.Path)
+		}
+		paths = append(paths, draft.Diff.Untracked...)
+		printWorktreePaths(output, "changed", paths)
+	}
+	source := draft.MessageSource
+	if draft.MessageError != "" {
+		source += " (provider failed: " + draft.MessageError + ")"
+	}
+	_, _ = fmt.Fprintf(output, "message (%s):\n\n%s\n", source, draft.Message)
+}
+
+func printFinalizeResult(output io.Writer, result *types.FinalizeResult, prPath string) {
+	commit := result.Commit
+	if len(commit) > 12 {
+		commit = commit[:12]
+	}
+	subject, _, _ := strings.Cut(result.Message, "\n")
+	if result.Branch != "" {
+		_, _ = fmt.Fprintf(output, "committed %s on %s: %s\n", commit, result.Branch, subject)
+	} else {
+		_, _ = fmt.Fprintf(output, "committed %s: %s\n", commit, subject)
+	}
+	printWorktreePaths(output, "files", result.Files)
+	for _, linked := range result.Linked {
+		if linked.Error != "" {
+			_, _ = fmt.Fprintf(output, "failed in linked repo %s: %s\n", linked.Name, linked.Error)
+			continue
+		}
+		commit := linked.Commit
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		_, _ = fmt.Fprintf(output, "committed %s in linked repo %s\n", commit, linked.Name)
+		printWorktreePaths(output, "files", linked.Files)
+	}
+	if prPath != "" {
+		_, _ = fmt.Fprintf(output, "pull request description written to %s\n", prPath)
+	}
+}
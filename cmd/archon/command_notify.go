@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"control/internal/types"
+)
+
+type NotifyCommand struct {
+	stdout    io.Writer
+	stderr    io.Writer
+	newClient sessionClientFactory
+}
+
+func NewNotifyCommand(stdout, stderr io.Writer, newClient sessionClientFactory) *NotifyCommand {
+	return &NotifyCommand{
+		stdout:    stdout,
+		stderr:    stderr,
+		newClient: newClient,
+	}
+}
+
+func (c *NotifyCommand) Run(args []string) error {
+	if len(args) == 0 {
+		return errors.New("notify requires a subcommand: test")
+	}
+	switch args[0] {
+	case "test":
+		return c.runTest(args[1:])
+	default:
+		return fmt.Errorf("unknown notify subcommand %q", args[0])
+	}
+}
+
+func (c *NotifyCommand) runTest(args []string) error {
+	fs := flag.NewFlagSet("notify test", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	trigger := fs.String("trigger", string(types.NotificationTriggerTurnCompleted), "notification trigger to simulate")
+	workspaceID := fs.String("workspace", "", "resolve notification settings for this workspace")
+	worktreeID := fs.String("worktree", "", "resolve notification settings for this worktree")
+	sessionID := fs.String("session", "", "resolve notification settings for this session")
+	emitJSON := fs.Bool("json", false, "emit machine-readable JSON result")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	if err := client.EnsureDaemon(ctx); err != nil {
+		return err
+	}
+	result, err := client.TestNotification(ctx, types.NotificationTestRequest{
+		Trigger:     types.NotificationTrigger(strings.TrimSpace(*trigger)),
+		WorkspaceID: strings.TrimSpace(*workspaceID),
+		WorktreeID:  strings.TrimSpace(*worktreeID),
+		SessionID:   strings.TrimSpace(*sessionID),
+	})
+	if err != nil {
+		return err
+	}
+	if *emitJSON {
+		encoded, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(c.stdout, string(encoded))
+	} else {
+		printNotificationTestResult(c.stdout, result)
+	}
+	if !result.Delivered {
+		if result.Error != "" {
+			return errors.New(result.Error)
+		}
+		return errors.New("test notification was not delivered")
+	}
+	return nil
+}
+
+func printNotificationTestResult(output io.Writer, result *types.NotificationTestResult) {
+	methods := make([]string, 0, len(result.Methods))
+	for _, method := range result.Methods {
+		methods = append(methods, string(method))
+	}
+	_, _ = fmt.Fprintf(output, "trigger:   %s\n", result.Event.Trigger)
+	_, _ = fmt.Fprintf(output, "methods:   %s\n", strings.Join(methods, ", "))
+	_, _ = fmt.Fprintf(output, "webhooks:  %d\n", result.Webhooks)
+	_, _ = fmt.Fprintf(output, "scripts:   %d\n", result.ScriptCommands)
+	if result.Delivered {
+		_, _ = fmt.Fprintln(output, "delivered: yes")
+		return
+	}
+	_, _ = fmt.Fprintln(output, "delivered: no")
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"control/internal/types"
+)
+
+type RollbackCommand struct {
+	stdout    io.Writer
+	stderr    io.Writer
+	newClient sessionClientFactory
+}
+
+func NewRollbackCommand(stdout, stderr io.Writer, newClient sessionClientFactory) *RollbackCommand {
+	return &RollbackCommand{
+		stdout:    stdout,
+		stderr:    stderr,
+		newClient: newClient,
+	}
+}
+
+func (c *RollbackCommand) Run(args []string) error {
+	fs := flag.NewFlagSet("rollback", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	turn := fs.String("turn", "", "restore the checkpoint taken at the start of this turn (number or turn id; default: latest turn)")
+	checkpointID := fs.String("checkpoint", "", "restore this checkpoint id")
+	notify := fs.Bool("notify", false, "tell the agent which changes were reverted")
+	force := fs.Bool("force", false, "restore even when other sessions work in the same repository")
+	list := fs.Bool("list", false, "list checkpoints instead of restoring")
+	emitJSON := fs.Bool("json", false, "emit machine-readable JSON")
+	// Allow `archon rollback <session> --turn 3` as well as flags first.
+	sessionID := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		sessionID, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if sessionID == "" && fs.NArg() > 0 {
+		sessionID = fs.Arg(0)
+	}
+	if sessionID == "" {
+		return errors.New("rollback requires a session id")
+	}
+	if *turn != "" && *checkpointID != "" {
+		return errors.New("use either --turn or --checkpoint")
+	}
+
+	ctx := context.Background()
+	client, err := c.newClient()
+	if err != nil {
+		return err
+	}
+	if err := client.EnsureDaemon(ctx); err != nil {
+		return err
+	}
+	checkpoints, err := client.ListSessionCheckpoints(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if *list {
+		if *emitJSON {
+			return c.writeJSON(checkpoints)
+		}
+		printCheckpoints(c.stdout, checkpoints)
+		return nil
+	}
+	checkpoint, err := selectCheckpoint(checkpoints, *turn, *checkpointID)
+	if err != nil {
+		return err
+	}
+	result, err := client.RestoreSessionCheckpoint(ctx, sessionID, checkpoint.ID, types.SessionCheckpointRestoreRequest{Notify: *notify, Force: *force})
+	if err != nil {
+		return err
+	}
+	if *emitJSON {
+		return c.writeJSON(result)
+	}
+	printCheckpointRestoreResult(c.stdout, result)
+	if result.NotifyError != "" {
+		return fmt.Errorf("tree restored, but notifying the session failed: %s", result.NotifyError)
+	}
+	return nil
+}
+
+func (c *RollbackCommand) writeJSON(value any) error {
+	encoded, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(c.stdout, string(encoded))
+	return nil
+}
+
+// selectCheckpoint picks the checkpoint named by id, by turn number or turn
+// id, or the latest turn checkpoint when neither is given.
+func selectCheckpoint(checkpoints []*types.SessionCheckpoint, turn, id string) (*types.SessionCheckpoint, error) {
+	turn = strings.TrimSpace(turn)
+	id = strings.TrimSpace(id)
+	var latest *types.SessionCheckpoint
+	for _, checkpoint := range checkpoints {
+		if checkpoint == nil {
+			continue
+		}
+		switch {
+		case id != "":
+			if checkpoint.ID == id {
+				return checkpoint, nil
+			}
+		case turn != "":
+			if checkpoint.TurnID == turn || (checkpoint.Turn > 0 && strconv.Itoa(checkpoint.Turn) == turn) {
+				return checkpoint, nil
+			}
+		case checkpoint.Turn > 0:
+			latest = checkpoint
+		}
+	}
+	switch {
+	case id != "":
+		return nil, fmt.Errorf("checkpoint %s not found", id)
+	case turn != "":
+		return nil, fmt.Errorf("no checkpoint for turn %s", turn)
+	case latest == nil:
+		return nil, errors.New("session has no checkpoints")
+	}
+	return latest, nil
+}
+
+func printCheckpoints(output io.Writer, checkpoints []*types.SessionCheckpoint) {
+	writer := tabwriter.NewWriter(output, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "ID\tTURN\tTURN ID\tCREATED\tLABEL")
+	for _, checkpoint := range checkpoints {
+		if checkpoint == nil {
+			continue
+		}
+		turn := "-"
+		if checkpoint.Turn > 0 {
+			turn = strconv.Itoa(checkpoint.Turn)
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", checkpoint.ID, turn, checkpoint.TurnID,
+			checkpoint.CreatedAt.Local().Format(time.DateTime), checkpoint.Label)
+	}
+	_ = writer.Flush()
+}
+
+func printCheckpointRestoreResult(output io.Writer, result *types.SessionCheckpointRestoreResult) {
+	if result.Checkpoint != nil && result.Checkpoint.Turn > 0 {
+		_, _ = fmt.Fprintf(output, "restored start of turn %d (%s)\n", result.Checkpoint.Turn, result.Checkpoint.ID)
+	} else if result.Checkpoint != nil {
+		_, _ = fmt.Fprintf(output, "restored checkpoint %s\n", result.Checkpoint.ID)
+	}
+	printWorktreePaths(output, "reverted", result.Reverted)
+	printWorktreePaths(output, "removed", result.Removed)
+	if result.BackupID != "" {
+		_, _ = fmt.Fprintf(output, "undo with: --checkpoint %s\n", result.BackupID)
+	}
+	if result.Notified {
+		_, _ = fmt.Fprintln(output, "session notified")
+	}
+}
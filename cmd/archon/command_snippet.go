@@ -0,0 +1,341 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"control/internal/types"
+)
+
+type SnippetCommand struct {
+	stdout    io.Writer
+	stderr    io.Writer
+	stdin     io.Reader
+	newClient sessionClientFactory
+}
+
+func NewSnippetCommand(stdout, stderr io.Writer, stdin io.Reader, newClient sessionClientFactory) *SnippetCommand {
+	return &SnippetCommand{
+		stdout:    stdout,
+		stderr:    stderr,
+		stdin:     stdin,
+		newClient: newClient,
+	}
+}
+
+func (c *SnippetCommand) Run(args []string) error {
+	if len(args) == 0 {
+		return errors.New("snippet requires a subcommand: list, show, add, edit, remove, render")
+	}
+	switch args[0] {
+	case "list":
+		return c.runList(args[1:])
+	case "show":
+		return c.runShow(args[1:])
+	case "add":
+		return c.runAdd(args[1:])
+	case "edit":
+		return c.runEdit(args[1:])
+	case "remove":
+		return c.runRemove(args[1:])
+	case "render":
+		return c.runRender(args[1:])
+	default:
+		return fmt.Errorf("unknown snippet subcommand %q", args[0])
+	}
+}
+
+func (c *SnippetCommand) connect(ctx context.Context) (sessionCommandClient, error) {
+	client, err := c.newClient()
+	if err != nil {
+		return nil, err
+	}
+	if err := client.EnsureDaemon(ctx); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (c *SnippetCommand) runList(args []string) error {
+	fs := flag.NewFlagSet("snippet list", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	workspaceID := fs.String("workspace", "", "list the snippets available in this workspace")
+	emitJSON := fs.Bool("json", false, "emit machine-readable JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx := context.Background()
+	client, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	snippets, err := client.ListSnippets(ctx, *workspaceID)
+	if err != nil {
+		return err
+	}
+	if *emitJSON {
+		return c.writeJSON(snippets)
+	}
+	writer := tabwriter.NewWriter(c.stdout, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "NAME\tSCOPE\tVARIABLES\tID")
+	for _, snippet := range snippets {
+		if snippet == nil {
+			continue
+		}
+		scope := string(snippet.Scope)
+		if snippet.Source == types.SnippetSourceWorkflow {
+			scope = "workflow"
+		} else if snippet.Scope == types.SnippetScopeWorkspace {
+			scope += " (" + snippet.WorkspaceID + ")"
+		}
+		variables := strings.Join(snippet.Variables, ",")
+		if variables == "" {
+			variables = "-"
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", snippet.Name, scope, variables, snippet.ID)
+	}
+	return writer.Flush()
+}
+
+func (c *SnippetCommand) runShow(args []string) error {
+	fs := flag.NewFlagSet("snippet show", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	workspaceID := fs.String("workspace", "", "resolve the name in this workspace")
+	emitJSON := fs.Bool("json", false, "emit machine-readable JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("snippet show requires a name or id")
+	}
+	ctx := context.Background()
+	client, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	snippet, err := resolveSnippet(ctx, client, *workspaceID, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *emitJSON {
+		return c.writeJSON(snippet)
+	}
+	_, _ = fmt.Fprintln(c.stdout, snippet.Body)
+	return nil
+}
+
+func (c *SnippetCommand) runAdd(args []string) error {
+	fs := flag.NewFlagSet("snippet add", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	workspaceID := fs.String("workspace", "", "store the snippet in this workspace instead of globally")
+	description := fs.String("description", "", "one-line description")
+	body := fs.String("body", "", "snippet text with optional {{var}} placeholders")
+	bodyFile := fs.String("body-file", "", "read the snippet text from a file (- for stdin)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("snippet add requires a name")
+	}
+	text, err := c.readBody(*body, *bodyFile)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(text) == "" {
+		return errors.New("snippet add requires --body or --body-file")
+	}
+	ctx := context.Background()
+	client, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	created, err := client.CreateSnippet(ctx, &types.Snippet{
+		Name:        fs.Arg(0),
+		Description: *description,
+		Body:        text,
+		WorkspaceID: strings.TrimSpace(*workspaceID),
+	})
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(c.stdout, "added snippet %s (%s)\n", created.Name, created.ID)
+	return nil
+}
+
+func (c *SnippetCommand) runEdit(args []string) error {
+	fs := flag.NewFlagSet("snippet edit", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	workspaceID := fs.String("workspace", "", "resolve the name in this workspace")
+	name := fs.String("name", "", "rename the snippet")
+	description := fs.String("description", "", "replace the description")
+	body := fs.String("body", "", "replace the snippet text")
+	bodyFile := fs.String("body-file", "", "replace the snippet text from a file (- for stdin)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("snippet edit requires a name or id")
+	}
+	text, err := c.readBody(*body, *bodyFile)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	client, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	snippet, err := resolveSnippet(ctx, client, *workspaceID, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	updated, err := client.UpdateSnippet(ctx, snippet.ID, &types.Snippet{Name: *name, Description: *description, Body: text})
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(c.stdout, "updated snippet %s (%s)\n", updated.Name, updated.ID)
+	return nil
+}
+
+func (c *SnippetCommand) runRemove(args []string) error {
+	fs := flag.NewFlagSet("snippet remove", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	workspaceID := fs.String("workspace", "", "resolve the name in this workspace")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("snippet remove requires a name or id")
+	}
+	ctx := context.Background()
+	client, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	snippet, err := resolveSnippet(ctx, client, *workspaceID, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if err := client.DeleteSnippet(ctx, snippet.ID); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(c.stdout, "removed snippet %s (%s)\n", snippet.Name, snippet.ID)
+	return nil
+}
+
+func (c *SnippetCommand) runRender(args []string) error {
+	fs := flag.NewFlagSet("snippet render", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	workspaceID := fs.String("workspace", "", "resolve the name in this workspace")
+	values := snippetValuesFlag{}
+	fs.Var(&values, "var", "placeholder value as name=value (repeatable)")
+	// render only has value flags, so --var may follow the snippet name.
+	if err := fs.Parse(reorderFlagsBeforePositional(args)); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("snippet render requires a name or id")
+	}
+	ctx := context.Background()
+	client, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	snippet, err := resolveSnippet(ctx, client, *workspaceID, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	rendered, err := client.RenderSnippet(ctx, snippet.ID, values)
+	if err != nil {
+		return err
+	}
+	if len(rendered.Missing) > 0 {
+		return fmt.Errorf("missing values for: %s (pass --var name=value)", strings.Join(rendered.Missing, ", "))
+	}
+	_, _ = fmt.Fprintln(c.stdout, rendered.Text)
+	return nil
+}
+
+func (c *SnippetCommand) readBody(body, bodyFile string) (string, error) {
+	if body != "" && bodyFile != "" {
+		return "", errors.New("use either --body or --body-file")
+	}
+	if bodyFile == "" {
+		return body, nil
+	}
+	var data []byte
+	var err error
+	if bodyFile == "-" {
+		data, err = io.ReadAll(c.stdin)
+	} else {
+		data, err = os.ReadFile(bodyFile)
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\n"), nil
+}
+
+func (c *SnippetCommand) writeJSON(value any) error {
+	encoded, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(c.stdout, string(encoded))
+	return nil
+}
+
+// resolveSnippet finds a snippet by id, or by name among the snippets
+// available in workspaceID.
+func resolveSnippet(ctx context.Context, client sessionCommandClient, workspaceID, ref string) (*types.Snippet, error) {
+	ref = strings.TrimSpace(ref)
+	snippets, err := client.ListSnippets(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, snippet := range snippets {
+		if snippet != nil && snippet.ID == ref {
+			return snippet, nil
+		}
+	}
+	var match *types.Snippet
+	for _, snippet := range snippets {
+		if snippet == nil || snippet.Name != ref {
+			continue
+		}
+		if match != nil {
+			return nil, fmt.Errorf("snippet name %q is ambiguous; pass --workspace or the snippet id", ref)
+		}
+		match = snippet
+	}
+	if match == nil {
+		return nil, fmt.Errorf("snippet %q not found", ref)
+	}
+	return match, nil
+}
+
+type snippetValuesFlag map[string]string
+
+func (f snippetValuesFlag) String() string {
+	parts := make([]string, 0, len(f))
+	for name, value := range f {
+		parts = append(parts, name+"="+value)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (f snippetValuesFlag) Set(raw string) error {
+	name, value, ok := strings.Cut(raw, "=")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("invalid --var %q: expected name=value", raw)
+	}
+	f[strings.TrimSpace(name)] = value
+	return nil
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"control/internal/types"
+)
+
+type WorkspaceCommand struct {
+	stdout    io.Writer
+	stderr    io.Writer
+	stdin     io.Reader
+	newClient sessionClientFactory
+}
+
+func NewWorkspaceCommand(stdout, stderr io.Writer, stdin io.Reader, newClient sessionClientFactory) *WorkspaceCommand {
+	return &WorkspaceCommand{
+		stdout:    stdout,
+		stderr:    stderr,
+		stdin:     stdin,
+		newClient: newClient,
+	}
+}
+
+func (c *WorkspaceCommand) Run(args []string) error {
+	if len(args) == 0 {
+		return errors.New("workspace requires a subcommand: scan, export, import")
+	}
+	switch args[0] {
+	case "scan":
+		return c.runScan(args[1:])
+	case "export":
+		return c.runExport(args[1:])
+	case "import":
+		return c.runImport(args[1:])
+	default:
+		return fmt.Errorf("unknown workspace subcommand %q", args[0])
+	}
+}
+
+func (c *WorkspaceCommand) connect(ctx context.Context) (sessionCommandClient, error) {
+	client, err := c.newClient()
+	if err != nil {
+		return nil, err
+	}
+	if err := client.EnsureDaemon(ctx); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (c *WorkspaceCommand) runScan(args []string) error {
+	fs := flag.NewFlagSet("workspace scan", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	depth := fs.Int("depth", 0, "maximum directory depth to search (default 4)")
+	apply := fs.Bool("import", false, "register the repositories that are not workspaces yet")
+	emitJSON := fs.Bool("json", false, "emit machine-readable JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("workspace scan requires a directory")
+	}
+	ctx := context.Background()
+	client, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	result, err := client.ScanWorkspaces(ctx, types.WorkspaceScanRequest{Root: fs.Arg(0), MaxDepth: *depth})
+	if err != nil {
+		return err
+	}
+	if !*apply {
+		if *emitJSON {
+			return c.writeJSON(result)
+		}
+		return printWorkspaceScan(c.stdout, result)
+	}
+	imported, err := client.ImportWorkspaces(ctx, result.Export(), false)
+	if err != nil {
+		return err
+	}
+	if *emitJSON {
+		return c.writeJSON(imported)
+	}
+	printWorkspaceImport(c.stdout, imported)
+	return nil
+}
+
+func (c *WorkspaceCommand) runExport(args []string) error {
+	fs := flag.NewFlagSet("workspace export", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	output := fs.String("output", "", "write the export to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	ctx := context.Background()
+	client, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	export, err := client.ExportWorkspaces(ctx)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		return c.writeJSON(export)
+	}
+	encoded, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*output, append(encoded, '\n'), 0o644); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(c.stdout, "exported %d workspaces, %d worktrees, %d groups to %s\n",
+		len(export.Workspaces), len(export.Worktrees), len(export.Groups), *output)
+	return nil
+}
+
+func (c *WorkspaceCommand) runImport(args []string) error {
+	fs := flag.NewFlagSet("workspace import", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	emitJSON := fs.Bool("json", false, "emit machine-readable JSON result")
+	allowLocalData := fs.Bool("allow-local-data", false, "import cmd: environment variables and notification scripts, which run on this machine, and file:/env: variables, instructions, context files, additional directories and the session subpath, which expose local data to agents")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("workspace import requires a file (- for stdin)")
+	}
+	var data []byte
+	var err error
+	if fs.Arg(0) == "-" {
+		data, err = io.ReadAll(c.stdin)
+	} else {
+		data, err = os.ReadFile(fs.Arg(0))
+	}
+	if err != nil {
+		return err
+	}
+	var export types.WorkspaceExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return fmt.Errorf("invalid workspace export: %w", err)
+	}
+	ctx := context.Background()
+	client, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	result, err := client.ImportWorkspaces(ctx, &export, *allowLocalData)
+	if err != nil {
+		return err
+	}
+	if *emitJSON {
+		return c.writeJSON(result)
+	}
+	printWorkspaceImport(c.stdout, result)
+	return nil
+}
+
+func (c *WorkspaceCommand) writeJSON(value any) error {
+	encoded, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(c.stdout, string(encoded))
+	return nil
+}
+
+func printWorkspaceScan(output io.Writer, result *types.WorkspaceScanResult) error {
+	writer := tabwriter.NewWriter(output, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "NAME\tGROUP\tWORKTREES\tSTATUS\tPATH")
+	for _, candidate := range result.Candidates {
+		if candidate == nil {
+			continue
+		}
+		status := "new"
+		if candidate.ExistingWorkspaceID != "" {
+			status = "registered (" + candidate.ExistingWorkspaceID + ")"
+		}
+		group := candidate.Group
+		if group == "" {
+			group = "-"
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%d\t%s\t%s\n", candidate.Name, group, len(candidate.Worktrees), status, candidate.RepoPath)
+	}
+	return writer.Flush()
+}
+
+func printWorkspaceImport(output io.Writer, result *types.WorkspaceImportResult) {
+	_, _ = fmt.Fprintf(output, "imported %d workspaces, %d worktrees, %d groups\n",
+		len(result.Workspaces), len(result.Worktrees), len(result.Groups))
+	for _, ws := range result.Workspaces {
+		_, _ = fmt.Fprintf(output, "  workspace: %s %s (%s)\n", ws.ID, ws.Name, ws.RepoPath)
+	}
+	for _, command := range result.Commands {
+		_, _ = fmt.Fprintf(output, "  runs command: %s\n", command)
+	}
+	for _, read := range result.LocalReads {
+		_, _ = fmt.Fprintf(output, "  reads local data: %s\n", read)
+	}
+	for _, reason := range result.Skipped {
+		_, _ = fmt.Fprintf(output, "  skipped: %s\n", reason)
+	}
+}
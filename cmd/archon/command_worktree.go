@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"control/internal/types"
+)
+
+type WorktreeCommand struct {
+	stdout    io.Writer
+	stderr    io.Writer
+	newClient sessionClientFactory
+}
+
+func NewWorktreeCommand(stdout, stderr io.Writer, newClient sessionClientFactory) *WorktreeCommand {
+	return &WorktreeCommand{
+		stdout:    stdout,
+		stderr:    stderr,
+		newClient: newClient,
+	}
+}
+
+func (c *WorktreeCommand) Run(args []string) error {
+	if len(args) == 0 {
+		return errors.New("worktree requires a subcommand: list, env, remove, prune, merge, rebase, push")
+	}
+	switch args[0] {
+	case "list":
+		return c.runList(args[1:])
+	case "env":
+		return c.runEnv(args[1:])
+	case "prune":
+		return c.runPrune(args[1:])
+	case "remove", "merge", "rebase", "push":
+		return c.runOperation(types.WorktreeOperation(args[0]), args[1:])
+	default:
+		return fmt.Errorf("unknown worktree subcommand %q", args[0])
+	}
+}
+
+func (c *WorktreeCommand) connect(ctx context.Context) (sessionCommandClient, error) {
+	client, err := c.newClient()
+	if err != nil {
+		return nil, err
+	}
+	if err := client.EnsureDaemon(ctx); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+func (c *WorktreeCommand) runList(args []string) error {
+	fs := flag.NewFlagSet("worktree list", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	emitJSON := fs.Bool("json", false, "emit machine-readable JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("worktree list requires a workspace id")
+	}
+	ctx := context.Background()
+	client, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	worktrees, err := client.ListWorktrees(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *emitJSON {
+		return c.writeJSON(worktrees)
+	}
+	writer := tabwriter.NewWriter(c.stdout, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "ID\tNAME\tPATH")
+	for _, wt := range worktrees {
+		if wt == nil {
+			continue
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\n", wt.ID, wt.Name, wt.Path)
+	}
+	return writer.Flush()
+}
+
+func (c *WorktreeCommand) runEnv(args []string) error {
+	fs := flag.NewFlagSet("worktree env", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 3 {
+		return errors.New("worktree env requires a workspace id, a worktree id and a profile name (- to use the workspace's)")
+	}
+	ctx := context.Background()
+	client, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	wt, err := client.UpdateWorktree(ctx, fs.Arg(0), fs.Arg(1), &types.Worktree{EnvProfile: fs.Arg(2)})
+	if err != nil {
+		return err
+	}
+	if wt.EnvProfile == "" {
+		_, _ = fmt.Fprintf(c.stdout, "%s: using the workspace env profile\n", wt.Name)
+		return nil
+	}
+	_, _ = fmt.Fprintf(c.stdout, "%s: env profile %s\n", wt.Name, wt.EnvProfile)
+	return nil
+}
+
+func (c *WorktreeCommand) runPrune(args []string) error {
+	fs := flag.NewFlagSet("worktree prune", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	emitJSON := fs.Bool("json", false, "emit machine-readable JSON result")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return errors.New("worktree prune requires a workspace id")
+	}
+	ctx := context.Background()
+	client, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	result, err := client.PruneWorktrees(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return c.report(result, *emitJSON)
+}
+
+func (c *WorktreeCommand) runOperation(op types.WorktreeOperation, args []string) error {
+	fs := flag.NewFlagSet("worktree "+string(op), flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	emitJSON := fs.Bool("json", false, "emit machine-readable JSON result")
+	req := types.WorktreeOperationRequest{}
+	var linkedTargets stringList
+	switch op {
+	case types.WorktreeOperationRemove:
+		fs.BoolVar(&req.Force, "force", false, "remove even if the worktree has uncommitted changes")
+	case types.WorktreeOperationMerge:
+		fs.Var(&linkedTargets, "linked-target", "REPO=BRANCH target of a linked repo (repeatable; default: its checked-out branch)")
+	case types.WorktreeOperationRebase:
+		fs.StringVar(&req.Target, "onto", "", "rebase onto this ref instead of the workspace's main branch")
+		fs.Var(&linkedTargets, "linked-target", "REPO=REF target of a linked repo (repeatable; default: its checked-out branch)")
+	case types.WorktreeOperationPush:
+		fs.StringVar(&req.Remote, "remote", "", "remote to push to (default origin)")
+		fs.BoolVar(&req.Force, "force", false, "push with --force-with-lease")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		return fmt.Errorf("worktree %s requires a workspace id and a worktree id", op)
+	}
+	for _, entry := range linkedTargets {
+		repo, target, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(repo) == "" || strings.TrimSpace(target) == "" {
+			return fmt.Errorf("invalid --linked-target %q: expected REPO=REF", entry)
+		}
+		if req.LinkedTargets == nil {
+			req.LinkedTargets = map[string]string{}
+		}
+		req.LinkedTargets[strings.TrimSpace(repo)] = strings.TrimSpace(target)
+	}
+	ctx := context.Background()
+	client, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+	result, err := client.WorktreeOperation(ctx, fs.Arg(0), fs.Arg(1), op, req)
+	if err != nil {
+		return err
+	}
+	return c.report(result, *emitJSON)
+}
+
+func (c *WorktreeCommand) report(result *types.WorktreeOperationResult, emitJSON bool) error {
+	if emitJSON {
+		if err := c.writeJSON(result); err != nil {
+			return err
+		}
+	} else {
+		printWorktreeOperationResult(c.stdout, result)
+	}
+	switch result.Status {
+	case types.WorktreeOperationOK:
+		return nil
+	case types.WorktreeOperationConflict:
+		return fmt.Errorf("%s aborted: %d conflicting files", result.Operation, len(result.Conflicts))
+	case types.WorktreeOperationDirty:
+		return fmt.Errorf("%s refused: %d uncommitted changes", result.Operation, len(result.DirtyFiles))
+	default:
+		return fmt.Errorf("%s %s", result.Operation, result.Status)
+	}
+}
+
+func (c *WorktreeCommand) writeJSON(value any) error {
+	encoded, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(c.stdout, string(encoded))
+	return nil
+}
+
+func printWorktreeOperationResult(output io.Writer, result *types.WorktreeOperationResult) {
+	_, _ = fmt.Fprintf(output, "%s: %s\n", result.Operation, result.Status)
+	if result.Branch != "" {
+		_, _ = fmt.Fprintf(output, "branch:  %s\n", result.Branch)
+	}
+	if result.Target != "" {
+		_, _ = fmt.Fprintf(output, "target:  %s\n", result.Target)
+	}
+	printWorktreePaths(output, "conflict", result.Conflicts)
+	printWorktreePaths(output, "dirty", result.DirtyFiles)
+	printWorktreePaths(output, "pruned", result.Pruned)
+	if result.Status == types.WorktreeOperationRejected && strings.TrimSpace(result.Output) != "" {
+		_, _ = fmt.Fprintln(output, strings.TrimSpace(result.Output))
+	}
+	for _, linked := range result.Linked {
+		_, _ = fmt.Fprintf(output, "  linked %s: %s\n", linked.Path, linked.Status)
+		if linked.Status == types.WorktreeOperationFailed && strings.TrimSpace(linked.Output) != "" {
+			_, _ = fmt.Fprintf(output, "    %s\n", strings.TrimSpace(linked.Output))
+		}
+	}
+}
+
+func printWorktreePaths(output io.Writer, label string, paths []string) {
+	for _, path := range paths {
+		_, _ = fmt.Fprintf(output, "  %s: %s\n", label, path)
+	}
+}
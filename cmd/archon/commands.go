@@ -61,8 +61,16 @@ func buildCommands(wiring commandWiring) map[string]commandRunner {
 		"interrupt": NewInterruptCommand(wiring.stdout, wiring.stderr, wiring.newSessionClient),
 		"send":      NewSendCommand(wiring.stdout, wiring.stderr, os.Stdin, wiring.newSessionClient),
 		"tail":      NewTailCommand(wiring.stdout, wiring.stderr, wiring.newSessionClient),
-		"approvals": NewApprovalsCommand(wiring.stdout, wiring.stderr, wiring.newSessionClient),
+		"approvals": NewApprovalsAllCommand(wiring.stdout, wiring.stderr, wiring.newSessionClient, NewApprovalsCommand(wiring.stdout, wiring.stderr, wiring.newSessionClient)),
 		"approve":   NewApproveCommand(wiring.stdout, wiring.stderr, wiring.newSessionClient),
+		"approval-rule": NewApprovalRuleCommand(wiring.stdout, wiring.stderr, wiring.newSessionClient),
+		"notify":    NewNotifyCommand(wiring.stdout, wiring.stderr, wiring.newSessionClient),
+		"workspace": NewWorkspaceCommand(wiring.stdout, wiring.stderr, os.Stdin, wiring.newSessionClient),
+		"worktree":  NewWorktreeCommand(wiring.stdout, wiring.stderr, wiring.newSessionClient),
+		"rollback":  NewRollbackCommand(wiring.stdout, wiring.stderr, wiring.newSessionClient),
+		"finalize":  NewFinalizeCommand(wiring.stdout, wiring.stderr, wiring.newSessionClient),
+		"snippet":   NewSnippetCommand(wiring.stdout, wiring.stderr, os.Stdin, wiring.newSessionClient),
+		"export":    NewExportCommand(wiring.stdout, wiring.stderr, wiring.newSessionClient),
 		"ui":        NewUICommand(wiring.stderr, wiring.newUIClient, wiring.configureUILogging, wiring.version),
 		"version": NewVersionCommand(wiring.stdout, wiring.stderr),
 	}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	controlclient "control/internal/client"
+	"control/internal/transcriptexport"
 	"control/internal/types"
 )
 
@@ -682,6 +683,416 @@ func TestKillCommandDaemonError(t *testing.T) {
 	}
 }
 
+// --- Notify command tests ---
+
+// TestNotifyTestCommandForwardsScopeAndPrintsResult asserts scope flags reach the daemon.
+func TestNotifyTestCommandForwardsScopeAndPrintsResult(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	fake := &fakeCommandClient{
+		testNotificationResp: &types.NotificationTestResult{
+			Delivered: true,
+			Event:     types.NotificationEvent{Trigger: types.NotificationTriggerSessionFailed},
+			Methods:   []types.NotificationMethod{types.NotificationMethodWebhook},
+			Webhooks:  2,
+		},
+	}
+	cmd := NewNotifyCommand(stdout, &bytes.Buffer{}, fixedSessionFactory(fake))
+
+	err := cmd.Run([]string{"test", "--trigger", "session.failed", "--workspace", "ws-1"})
+	if err != nil {
+		t.Fatalf("expected success, got err=%v", err)
+	}
+	if fake.ensureDaemonCalls != 1 || fake.testNotificationCalls != 1 {
+		t.Fatalf("unexpected calls: ensure=%d test=%d", fake.ensureDaemonCalls, fake.testNotificationCalls)
+	}
+	if fake.testNotificationReq.Trigger != types.NotificationTriggerSessionFailed || fake.testNotificationReq.WorkspaceID != "ws-1" {
+		t.Fatalf("unexpected request: %#v", fake.testNotificationReq)
+	}
+	if !strings.Contains(stdout.String(), "webhooks:  2") || !strings.Contains(stdout.String(), "delivered: yes") {
+		t.Fatalf("unexpected output: %q", stdout.String())
+	}
+}
+
+// TestNotifyTestCommandFailsWhenNotDelivered asserts delivery errors surface as command errors.
+func TestNotifyTestCommandFailsWhenNotDelivered(t *testing.T) {
+	fake := &fakeCommandClient{
+		testNotificationResp: &types.NotificationTestResult{Error: "webhook https://example.com failed"},
+	}
+	cmd := NewNotifyCommand(&bytes.Buffer{}, &bytes.Buffer{}, fixedSessionFactory(fake))
+
+	err := cmd.Run([]string{"test"})
+	if err == nil || !strings.Contains(err.Error(), "webhook https://example.com failed") {
+		t.Fatalf("expected delivery error, got %v", err)
+	}
+}
+
+// TestNotifyCommandRequiresSubcommand asserts a bare notify fails without daemon contact.
+func TestNotifyCommandRequiresSubcommand(t *testing.T) {
+	fake := &fakeCommandClient{}
+	cmd := NewNotifyCommand(&bytes.Buffer{}, &bytes.Buffer{}, fixedSessionFactory(fake))
+
+	if err := cmd.Run(nil); err == nil {
+		t.Fatal("expected error for missing subcommand")
+	}
+	if fake.ensureDaemonCalls != 0 {
+		t.Fatalf("expected no daemon contact, got %d ensureDaemonCalls", fake.ensureDaemonCalls)
+	}
+}
+
+// --- Worktree command tests ---
+
+// TestWorktreeRebaseCommandForwardsTarget asserts flags and ids reach the daemon.
+func TestWorktreeRebaseCommandForwardsTarget(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	fake := &fakeCommandClient{
+		worktreeOpResp: &types.WorktreeOperationResult{
+			Operation: types.WorktreeOperationRebase,
+			Status:    types.WorktreeOperationOK,
+			Branch:    "feature",
+			Target:    "develop",
+		},
+	}
+	cmd := NewWorktreeCommand(stdout, &bytes.Buffer{}, fixedSessionFactory(fake))
+
+	if err := cmd.Run([]string{"rebase", "--onto", "develop", "ws-1", "wt-1"}); err != nil {
+		t.Fatalf("expected success, got err=%v", err)
+	}
+	if fake.worktreeOpCalls != 1 || fake.worktreeOp != types.WorktreeOperationRebase {
+		t.Fatalf("unexpected calls: %d op=%q", fake.worktreeOpCalls, fake.worktreeOp)
+	}
+	if fake.worktreeOpWorkspace != "ws-1" || fake.worktreeOpWorktree != "wt-1" || fake.worktreeOpReq.Target != "develop" {
+		t.Fatalf("unexpected request: %s/%s %#v", fake.worktreeOpWorkspace, fake.worktreeOpWorktree, fake.worktreeOpReq)
+	}
+	if !strings.Contains(stdout.String(), "rebase: ok") || !strings.Contains(stdout.String(), "target:  develop") {
+		t.Fatalf("unexpected output: %q", stdout.String())
+	}
+}
+
+// TestWorktreeEnvCommandSelectsProfile asserts the profile reaches the daemon.
+func TestWorktreeEnvCommandSelectsProfile(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	fake := &fakeCommandClient{}
+	cmd := NewWorktreeCommand(stdout, &bytes.Buffer{}, fixedSessionFactory(fake))
+
+	if err := cmd.Run([]string{"env", "ws-1", "wt-1", "ci"}); err != nil {
+		t.Fatalf("expected success, got err=%v", err)
+	}
+	if fake.worktreeOpWorkspace != "ws-1" || fake.worktreeOpWorktree != "wt-1" || fake.updatedWorktree == nil || fake.updatedWorktree.EnvProfile != "ci" {
+		t.Fatalf("unexpected update: %s/%s %#v", fake.worktreeOpWorkspace, fake.worktreeOpWorktree, fake.updatedWorktree)
+	}
+	if stdout.String() != "feature: env profile ci\n" {
+		t.Fatalf("unexpected output: %q", stdout.String())
+	}
+}
+
+func TestWorkspaceScanCommandImportsNewRepositories(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	fake := &fakeCommandClient{
+		scanResp: &types.WorkspaceScanResult{
+			Root: "/src",
+			Candidates: []*types.WorkspaceScanCandidate{
+				{Name: "api", RepoPath: "/src/backend/api", Group: "backend", Worktrees: []*types.GitWorktree{{Path: "/src/backend/api-fix", Branch: "fix"}}},
+				{Name: "web", RepoPath: "/src/web", ExistingWorkspaceID: "ws-1"},
+			},
+		},
+	}
+	cmd := NewWorkspaceCommand(stdout, &bytes.Buffer{}, strings.NewReader(""), fixedSessionFactory(fake))
+
+	if err := cmd.Run([]string{"scan", "/src"}); err != nil {
+		t.Fatalf("expected success, got err=%v", err)
+	}
+	if !strings.Contains(stdout.String(), "registered (ws-1)") || fake.importedExport != nil {
+		t.Fatalf("expected a listing without import, got %q", stdout.String())
+	}
+
+	stdout.Reset()
+	if err := cmd.Run([]string{"scan", "--import", "/src"}); err != nil {
+		t.Fatalf("expected success, got err=%v", err)
+	}
+	export := fake.importedExport
+	if export == nil || len(export.Workspaces) != 1 || len(export.Worktrees) != 1 || len(export.Groups) != 1 {
+		t.Fatalf("expected only the new repository to be imported, got %#v", export)
+	}
+	if export.Groups[0].Name != "backend" || export.Workspaces[0].GroupIDs[0] != export.Groups[0].ID || export.Worktrees[0].Name != "fix" {
+		t.Fatalf("unexpected export %#v", export)
+	}
+	if !strings.HasPrefix(stdout.String(), "imported 1 workspaces, 0 worktrees, 0 groups\n") {
+		t.Fatalf("unexpected output: %q", stdout.String())
+	}
+}
+
+func TestWorkspaceImportCommandAllowsCommandsOnlyWhenAsked(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	fake := &fakeCommandClient{}
+	export := `{"version":1,"workspaces":[{"id":"ws-1","name":"api","repo_path":"~/src/api"}]}`
+	cmd := NewWorkspaceCommand(stdout, &bytes.Buffer{}, strings.NewReader(export), fixedSessionFactory(fake))
+	if err := cmd.Run([]string{"import", "-"}); err != nil {
+		t.Fatalf("expected success, got err=%v", err)
+	}
+	if fake.importedExport == nil || fake.importAllowLocalData {
+		t.Fatalf("expected an import without local data, got %#v allow=%v", fake.importedExport, fake.importAllowLocalData)
+	}
+
+	cmd = NewWorkspaceCommand(stdout, &bytes.Buffer{}, strings.NewReader(export), fixedSessionFactory(fake))
+	if err := cmd.Run([]string{"import", "--allow-local-data", "-"}); err != nil {
+		t.Fatalf("expected success, got err=%v", err)
+	}
+	if !fake.importAllowLocalData {
+		t.Fatalf("expected --allow-local-data to be passed through")
+	}
+}
+
+func TestSnippetRenderCommandResolvesNameAndVars(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	fake := &fakeCommandClient{
+		snippets: []*types.Snippet{{ID: "snip_1", Name: "review", Body: "Review {{file}} for {{concern}}"}},
+	}
+	cmd := NewSnippetCommand(stdout, &bytes.Buffer{}, strings.NewReader(""), fixedSessionFactory(fake))
+
+	if err := cmd.Run([]string{"render", "review", "--var", "file=api.go"}); err == nil || !strings.Contains(err.Error(), "concern") {
+		t.Fatalf("expected missing variable error, got %v", err)
+	}
+	if err := cmd.Run([]string{"render", "--var", "file=api.go", "--var", "concern=errors", "review"}); err != nil {
+		t.Fatalf("expected success, got err=%v", err)
+	}
+	if fake.renderSnippetID != "snip_1" || stdout.String() != "Review api.go for errors\n" {
+		t.Fatalf("unexpected render id=%q output=%q", fake.renderSnippetID, stdout.String())
+	}
+
+	if err := cmd.Run([]string{"add", "--workspace", "ws-1", "--body", "Commit your work", "commit"}); err != nil {
+		t.Fatalf("expected add success, got err=%v", err)
+	}
+	if fake.createdSnippet == nil || fake.createdSnippet.Name != "commit" || fake.createdSnippet.WorkspaceID != "ws-1" {
+		t.Fatalf("unexpected created snippet %#v", fake.createdSnippet)
+	}
+}
+
+func TestApprovalRuleCommandAddEditAndDisable(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	fake := &fakeCommandClient{
+		approvalRules: []*types.ApprovalRule{{
+			ID:            "rule_1",
+			Name:          "tests",
+			Scope:         types.ApprovalRuleScopeWorkspace,
+			WorkspaceID:   "ws-1",
+			Method:        "command",
+			CommandPrefix: "go test",
+			Decision:      types.ApprovalRuleAllowOnce,
+		}},
+	}
+	cmd := NewApprovalRuleCommand(stdout, &bytes.Buffer{}, fixedSessionFactory(fake))
+
+	if err := cmd.Run([]string{"add", "--provider", "opencode", "--path", "docs/**", "--path", "*.md"}); err == nil || !strings.Contains(err.Error(), "--decision") {
+		t.Fatalf("expected missing decision error, got %v", err)
+	}
+	if err := cmd.Run([]string{"add", "--provider", "opencode", "--path", "docs/**", "--path", "*.md", "--decision", "allow_always"}); err != nil {
+		t.Fatalf("expected add success, got err=%v", err)
+	}
+	created := fake.createdApprovalRule
+	if created == nil || created.Provider != "opencode" || len(created.PathGlobs) != 2 || created.Decision != types.ApprovalRuleAllowAlways {
+		t.Fatalf("unexpected created rule %#v", created)
+	}
+
+	if err := cmd.Run([]string{"edit", "tests", "--prefix", "", "--regex", "^go (test|vet) "}); err != nil {
+		t.Fatalf("expected edit success, got err=%v", err)
+	}
+	updated := fake.updatedApprovalRule
+	if updated == nil || updated.ID != "rule_1" || updated.CommandPrefix != "" || updated.CommandRegex != "^go (test|vet) " || updated.WorkspaceID != "ws-1" {
+		t.Fatalf("expected edit to replace only passed fields, got %#v", updated)
+	}
+
+	if err := cmd.Run([]string{"disable", "rule_1"}); err != nil {
+		t.Fatalf("expected disable success, got err=%v", err)
+	}
+	if !fake.updatedApprovalRule.Disabled || !strings.HasSuffix(stdout.String(), "disabled approval rule rule_1\n") {
+		t.Fatalf("unexpected disable result %#v output=%q", fake.updatedApprovalRule, stdout.String())
+	}
+	if err := cmd.Run([]string{"remove", "missing"}); err == nil {
+		t.Fatalf("expected unknown rule error")
+	}
+}
+
+func TestExportCommandPassesFormatAndOptions(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	fake := &fakeCommandClient{}
+	cmd := NewExportCommand(stdout, &bytes.Buffer{}, fixedSessionFactory(fake))
+
+	if err := cmd.Run([]string{"s1", "--reasoning", "--tools=false"}); err != nil {
+		t.Fatalf("expected success, got err=%v", err)
+	}
+	if fake.exportID != "s1" || fake.exportFormat != transcriptexport.FormatMarkdown {
+		t.Fatalf("unexpected export request %q %q", fake.exportID, fake.exportFormat)
+	}
+	if !fake.exportOptions.Reasoning || fake.exportOptions.ToolCalls || !fake.exportOptions.Approvals {
+		t.Fatalf("unexpected export options %#v", fake.exportOptions)
+	}
+	if stdout.String() != "# Session s1\n" {
+		t.Fatalf("unexpected output %q", stdout.String())
+	}
+
+	stdout.Reset()
+	path := filepath.Join(t.TempDir(), "s1.html")
+	if err := cmd.Run([]string{"--format", "html", "--theme", "nordic", "-o", path, "s1"}); err != nil {
+		t.Fatalf("expected success, got err=%v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	if !strings.Contains(string(data), "&lt;fix it&gt;") || !strings.Contains(string(data), "#2e3440") {
+		t.Fatalf("expected escaped html in the nordic theme, got %q", string(data))
+	}
+
+	if err := cmd.Run([]string{"--format", "pdf", "s1"}); err == nil {
+		t.Fatalf("expected unsupported format error")
+	}
+}
+
+// TestWorktreeMergeCommandFailsOnConflicts asserts conflicts are listed and fail the command.
+func TestWorktreeMergeCommandFailsOnConflicts(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	fake := &fakeCommandClient{
+		worktreeOpResp: &types.WorktreeOperationResult{
+			Operation: types.WorktreeOperationMerge,
+			Status:    types.WorktreeOperationConflict,
+			Conflicts: []string{"main.go"},
+		},
+	}
+	cmd := NewWorktreeCommand(stdout, &bytes.Buffer{}, fixedSessionFactory(fake))
+
+	err := cmd.Run([]string{"merge", "ws-1", "wt-1"})
+	if err == nil || !strings.Contains(err.Error(), "1 conflicting files") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+	if !strings.Contains(stdout.String(), "conflict: main.go") {
+		t.Fatalf("expected conflicting path in output, got %q", stdout.String())
+	}
+}
+
+// --- Rollback command tests ---
+
+func rollbackTestCheckpoints() []*types.SessionCheckpoint {
+	return []*types.SessionCheckpoint{
+		{ID: "100", TurnID: "turn-a", Turn: 1},
+		{ID: "200", TurnID: "turn-b", Turn: 2},
+		{ID: "300", Label: "before restore of 100"},
+	}
+}
+
+// TestRollbackCommandRestoresTurnAfterSessionID asserts `rollback <id> --turn N` picks that turn.
+func TestRollbackCommandRestoresTurnAfterSessionID(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	fake := &fakeCommandClient{
+		checkpoints: rollbackTestCheckpoints(),
+		checkpointRestore: &types.SessionCheckpointRestoreResult{
+			Checkpoint: &types.SessionCheckpoint{ID: "100", Turn: 1},
+			BackupID:   "400",
+			Reverted:   []string{"main.go"},
+			Notified:   true,
+		},
+	}
+	cmd := NewRollbackCommand(stdout, &bytes.Buffer{}, fixedSessionFactory(fake))
+
+	if err := cmd.Run([]string{"s1", "--turn", "1", "--notify"}); err != nil {
+		t.Fatalf("expected success, got err=%v", err)
+	}
+	if fake.checkpointRestoreID != "100" || !fake.checkpointRestoreReq.Notify {
+		t.Fatalf("unexpected restore request: %q %#v", fake.checkpointRestoreID, fake.checkpointRestoreReq)
+	}
+	for _, want := range []string{"restored start of turn 1", "reverted: main.go", "--checkpoint 400", "session notified"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("expected %q in output: %q", want, stdout.String())
+		}
+	}
+}
+
+// TestRollbackCommandDefaultsToLatestTurn asserts the newest turn checkpoint is used without --turn.
+func TestRollbackCommandDefaultsToLatestTurn(t *testing.T) {
+	fake := &fakeCommandClient{
+		checkpoints:       rollbackTestCheckpoints(),
+		checkpointRestore: &types.SessionCheckpointRestoreResult{Checkpoint: &types.SessionCheckpoint{ID: "200", Turn: 2}},
+	}
+	cmd := NewRollbackCommand(&bytes.Buffer{}, &bytes.Buffer{}, fixedSessionFactory(fake))
+
+	if err := cmd.Run([]string{"s1"}); err != nil {
+		t.Fatalf("expected success, got err=%v", err)
+	}
+	if fake.checkpointRestoreID != "200" {
+		t.Fatalf("expected latest turn checkpoint, got %q", fake.checkpointRestoreID)
+	}
+	if err := cmd.Run([]string{"--turn", "turn-x", "s1"}); err == nil || !strings.Contains(err.Error(), "no checkpoint for turn turn-x") {
+		t.Fatalf("expected unknown turn error, got %v", err)
+	}
+}
+
+// TestFinalizeCommandCommitsRunAndWritesPullRequest asserts --run commits the
+// run with the given message and writes the description file.
+func TestFinalizeCommandCommitsRunAndWritesPullRequest(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	fake := &fakeCommandClient{finalizeResult: &types.FinalizeResult{
+		Branch:      "feature",
+		Commit:      "0123456789abcdef",
+		Message:     "feat: add parser\n\nbody",
+		Files:       []string{"parser.go"},
+		PullRequest: "# feat: add parser\n",
+	}}
+	cmd := NewFinalizeCommand(stdout, &bytes.Buffer{}, fixedSessionFactory(fake))
+	prPath := filepath.Join(t.TempDir(), "pr.md")
+
+	if err := cmd.Run([]string{"--run", "--message", "feat: add parser", "--pr", prPath, "run-1"}); err != nil {
+		t.Fatalf("expected success, got err=%v", err)
+	}
+	if !fake.finalizeRun || fake.finalizeID != "run-1" || fake.finalizeReq.Message != "feat: add parser" || !fake.finalizeReq.PullRequest {
+		t.Fatalf("unexpected finalize request: run=%v id=%q %#v", fake.finalizeRun, fake.finalizeID, fake.finalizeReq)
+	}
+	if data, err := os.ReadFile(prPath); err != nil || string(data) != "# feat: add parser\n" {
+		t.Fatalf("expected pull request file, got %q (%v)", data, err)
+	}
+	if !strings.Contains(stdout.String(), "committed 0123456789ab on feature: feat: add parser") {
+		t.Fatalf("unexpected output: %q", stdout.String())
+	}
+}
+
+// TestFinalizeCommandDryRunPrintsDraft asserts --dry-run never commits.
+func TestFinalizeCommandDryRunPrintsDraft(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	fake := &fakeCommandClient{finalizeDraft: &types.FinalizeDraft{
+		Root:          "/repo",
+		Diff:          &types.GitDiff{Untracked: []string{"new.go"}},
+		Message:       "feat: add new",
+		MessageSource: types.FinalizeMessageSourceSummary,
+	}}
+	cmd := NewFinalizeCommand(stdout, &bytes.Buffer{}, fixedSessionFactory(fake))
+
+	if err := cmd.Run([]string{"s1", "--dry-run"}); err != nil {
+		t.Fatalf("expected success, got err=%v", err)
+	}
+	if fake.finalizeRun || fake.finalizeReq.Message != "" {
+		t.Fatalf("expected a session draft without commit, got %#v", fake.finalizeReq)
+	}
+	for _, want := range []string{"changed: new.go", "message (summary):", "feat: add new"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Fatalf("expected %q in output: %q", want, stdout.String())
+		}
+	}
+}
+
+// TestWorktreeCommandRequiresIDs asserts missing ids fail without daemon contact.
+func TestWorktreeCommandRequiresIDs(t *testing.T) {
+	fake := &fakeCommandClient{}
+	cmd := NewWorktreeCommand(&bytes.Buffer{}, &bytes.Buffer{}, fixedSessionFactory(fake))
+
+	if err := cmd.Run([]string{"remove", "ws-1"}); err == nil {
+		t.Fatal("expected error for missing worktree id")
+	}
+	if err := cmd.Run(nil); err == nil {
+		t.Fatal("expected error for missing subcommand")
+	}
+	if fake.ensureDaemonCalls != 0 {
+		t.Fatalf("expected no daemon contact, got %d ensureDaemonCalls", fake.ensureDaemonCalls)
+	}
+}
+
 // --- Interrupt command tests ---
 
 // TestInterruptCommandSuccess asserts interrupt exits silently on success.
@@ -781,6 +1192,39 @@ func TestApprovalsCommandEmptyListTable(t *testing.T) {
 	}
 }
 
+func TestApprovalsAllCommandListsEverySession(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	now := time.Date(2026, 4, 24, 12, 30, 0, 0, time.UTC)
+	fake := &fakeCommandClient{
+		allApprovals: []*types.PendingApproval{
+			{Approval: types.Approval{SessionID: "s1", RequestID: 1, Method: "item/commandExecution/requestApproval", CreatedAt: now.Add(-5 * time.Minute)}, Provider: "codex", WorkspaceName: "api", Preview: "go test ./..."},
+			{Approval: types.Approval{SessionID: "s2", RequestID: 4, Method: "session/request_permission", CreatedAt: now.Add(-2 * time.Hour)}, Provider: "opencode"},
+		},
+	}
+	cmd := NewApprovalsAllCommand(stdout, &bytes.Buffer{}, fixedSessionFactory(fake), NewApprovalsCommand(stdout, &bytes.Buffer{}, fixedSessionFactory(fake)))
+	cmd.now = func() time.Time { return now }
+
+	if err := cmd.Run([]string{"--all"}); err != nil {
+		t.Fatalf("expected success, got err=%v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "SESSION") {
+		t.Fatalf("unexpected table %q", stdout.String())
+	}
+	if fields := strings.Fields(lines[1]); strings.Join(fields, " ") != "s1 1 codex api 5m go test ./..." {
+		t.Fatalf("unexpected first row %q", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); strings.Join(fields, " ") != "s2 4 opencode - 2h session/request_permission" {
+		t.Fatalf("unexpected second row %q", lines[2])
+	}
+	if fake.listApprovalsCalls != 0 {
+		t.Fatalf("expected --all not to list a single session")
+	}
+	if err := cmd.Run([]string{"--all", "s1"}); err == nil {
+		t.Fatalf("expected --all with a session id to fail")
+	}
+}
+
 // TestApprovalsCommandNonEmptyListTable asserts non-empty list renders table rows.
 func TestApprovalsCommandNonEmptyListTable(t *testing.T) {
 	stdout := &bytes.Buffer{}
@@ -2766,6 +3210,51 @@ type fakeCommandClient struct {
 	approveSessionCalls int
 	approveSessionIDArg string
 	approveSessionReq   controlclient.ApproveSessionRequest
+	allApprovals        []*types.PendingApproval
+
+	testNotificationErr   error
+	testNotificationResp  *types.NotificationTestResult
+	testNotificationCalls int
+	testNotificationReq   types.NotificationTestRequest
+
+	worktreesResp        []*types.Worktree
+	worktreeOpErr        error
+	worktreeOpResp       *types.WorktreeOperationResult
+	worktreeOpCalls      int
+	worktreeOpWorkspace  string
+	worktreeOpWorktree   string
+	worktreeOp           types.WorktreeOperation
+	worktreeOpReq        types.WorktreeOperationRequest
+	updatedWorktree      *types.Worktree
+	scanResp             *types.WorkspaceScanResult
+	importedExport       *types.WorkspaceExport
+	importAllowLocalData bool
+	pruneWorktreesCalls  int
+	pruneWorktreesWSArg  string
+
+	checkpoints          []*types.SessionCheckpoint
+	checkpointRestoreID  string
+	checkpointRestoreReq types.SessionCheckpointRestoreRequest
+	checkpointRestore    *types.SessionCheckpointRestoreResult
+	finalizeDraft        *types.FinalizeDraft
+	finalizeResult       *types.FinalizeResult
+	finalizeID           string
+	finalizeRun          bool
+	finalizeReq          types.FinalizeRequest
+
+	snippets        []*types.Snippet
+	createdSnippet  *types.Snippet
+	renderSnippetID string
+	renderValues    map[string]string
+
+	approvalRules       []*types.ApprovalRule
+	createdApprovalRule *types.ApprovalRule
+	updatedApprovalRule *types.ApprovalRule
+	deletedApprovalRule string
+
+	exportID      string
+	exportFormat  transcriptexport.Format
+	exportOptions transcriptexport.Options
 
 	shutdownErr error
 	healthErr   error
@@ -2918,6 +3407,10 @@ func (f *fakeCommandClient) ListApprovals(_ context.Context, sessionID string) (
 	return f.listApprovalsResp, nil
 }
 
+func (f *fakeCommandClient) ListAllApprovals(context.Context) ([]*types.PendingApproval, error) {
+	return f.allApprovals, nil
+}
+
 func (f *fakeCommandClient) ApproveSession(_ context.Context, sessionID string, req controlclient.ApproveSessionRequest) error {
 	f.approveSessionCalls++
 	f.approveSessionIDArg = sessionID
@@ -2925,6 +3418,182 @@ func (f *fakeCommandClient) ApproveSession(_ context.Context, sessionID string,
 	return f.approveSessionErr
 }
 
+func (f *fakeCommandClient) TestNotification(_ context.Context, req types.NotificationTestRequest) (*types.NotificationTestResult, error) {
+	f.testNotificationCalls++
+	f.testNotificationReq = req
+	if f.testNotificationErr != nil {
+		return nil, f.testNotificationErr
+	}
+	if f.testNotificationResp == nil {
+		return nil, errors.New("testNotificationResp not configured")
+	}
+	return f.testNotificationResp, nil
+}
+
+func (f *fakeCommandClient) ListWorktrees(context.Context, string) ([]*types.Worktree, error) {
+	return f.worktreesResp, nil
+}
+
+func (f *fakeCommandClient) WorktreeOperation(_ context.Context, workspaceID, worktreeID string, op types.WorktreeOperation, req types.WorktreeOperationRequest) (*types.WorktreeOperationResult, error) {
+	f.worktreeOpCalls++
+	f.worktreeOpWorkspace = workspaceID
+	f.worktreeOpWorktree = worktreeID
+	f.worktreeOp = op
+	f.worktreeOpReq = req
+	if f.worktreeOpErr != nil {
+		return nil, f.worktreeOpErr
+	}
+	if f.worktreeOpResp == nil {
+		return nil, errors.New("worktreeOpResp not configured")
+	}
+	return f.worktreeOpResp, nil
+}
+
+func (f *fakeCommandClient) PruneWorktrees(_ context.Context, workspaceID string) (*types.WorktreeOperationResult, error) {
+	f.pruneWorktreesCalls++
+	f.pruneWorktreesWSArg = workspaceID
+	if f.worktreeOpResp == nil {
+		return nil, errors.New("worktreeOpResp not configured")
+	}
+	return f.worktreeOpResp, nil
+}
+
+func (f *fakeCommandClient) UpdateWorktree(_ context.Context, workspaceID, worktreeID string, worktree *types.Worktree) (*types.Worktree, error) {
+	f.worktreeOpWorkspace = workspaceID
+	f.worktreeOpWorktree = worktreeID
+	f.updatedWorktree = worktree
+	return &types.Worktree{ID: worktreeID, Name: "feature", EnvProfile: worktree.EnvProfile}, nil
+}
+
+func (f *fakeCommandClient) ScanWorkspaces(context.Context, types.WorkspaceScanRequest) (*types.WorkspaceScanResult, error) {
+	if f.scanResp == nil {
+		return nil, errors.New("scanResp not configured")
+	}
+	return f.scanResp, nil
+}
+
+func (f *fakeCommandClient) ExportWorkspaces(context.Context) (*types.WorkspaceExport, error) {
+	return &types.WorkspaceExport{Version: types.WorkspaceExportVersion}, nil
+}
+
+func (f *fakeCommandClient) ImportWorkspaces(_ context.Context, export *types.WorkspaceExport, allowLocalData bool) (*types.WorkspaceImportResult, error) {
+	f.importedExport = export
+	f.importAllowLocalData = allowLocalData
+	result := &types.WorkspaceImportResult{}
+	for _, ws := range export.Workspaces {
+		result.Workspaces = append(result.Workspaces, &types.Workspace{ID: "ws-new", Name: ws.Name, RepoPath: ws.RepoPath})
+	}
+	return result, nil
+}
+
+func (f *fakeCommandClient) ListSessionCheckpoints(context.Context, string) ([]*types.SessionCheckpoint, error) {
+	return f.checkpoints, nil
+}
+
+func (f *fakeCommandClient) RestoreSessionCheckpoint(_ context.Context, _ string, checkpointID string, req types.SessionCheckpointRestoreRequest) (*types.SessionCheckpointRestoreResult, error) {
+	f.checkpointRestoreID = checkpointID
+	f.checkpointRestoreReq = req
+	if f.checkpointRestore == nil {
+		return nil, errors.New("checkpointRestore not configured")
+	}
+	return f.checkpointRestore, nil
+}
+
+func (f *fakeCommandClient) SessionFinalizeDraft(_ context.Context, sessionID string) (*types.FinalizeDraft, error) {
+	f.finalizeID = sessionID
+	if f.finalizeDraft == nil {
+		return nil, errors.New("finalizeDraft not configured")
+	}
+	return f.finalizeDraft, nil
+}
+
+func (f *fakeCommandClient) FinalizeSession(_ context.Context, sessionID string, req types.FinalizeRequest) (*types.FinalizeResult, error) {
+	f.finalizeID = sessionID
+	f.finalizeReq = req
+	if f.finalizeResult == nil {
+		return nil, errors.New("finalizeResult not configured")
+	}
+	return f.finalizeResult, nil
+}
+
+func (f *fakeCommandClient) WorkflowRunFinalizeDraft(ctx context.Context, runID string) (*types.FinalizeDraft, error) {
+	f.finalizeRun = true
+	return f.SessionFinalizeDraft(ctx, runID)
+}
+
+func (f *fakeCommandClient) FinalizeWorkflowRun(ctx context.Context, runID string, req types.FinalizeRequest) (*types.FinalizeResult, error) {
+	f.finalizeRun = true
+	return f.FinalizeSession(ctx, runID, req)
+}
+
+func (f *fakeCommandClient) ListSnippets(context.Context, string) ([]*types.Snippet, error) {
+	return f.snippets, nil
+}
+
+func (f *fakeCommandClient) CreateSnippet(_ context.Context, snippet *types.Snippet) (*types.Snippet, error) {
+	f.createdSnippet = snippet
+	created := *snippet
+	created.ID = "snip_new"
+	return &created, nil
+}
+
+func (f *fakeCommandClient) UpdateSnippet(_ context.Context, id string, snippet *types.Snippet) (*types.Snippet, error) {
+	updated := *snippet
+	updated.ID = id
+	return &updated, nil
+}
+
+func (f *fakeCommandClient) DeleteSnippet(context.Context, string) error {
+	return nil
+}
+
+func (f *fakeCommandClient) RenderSnippet(_ context.Context, id string, values map[string]string) (*controlclient.RenderSnippetResponse, error) {
+	f.renderSnippetID = id
+	f.renderValues = values
+	for _, snippet := range f.snippets {
+		if snippet.ID == id {
+			text, missing := types.RenderSnippet(snippet.Body, values)
+			return &controlclient.RenderSnippetResponse{Text: text, Missing: missing}, nil
+		}
+	}
+	return nil, errors.New("snippet not found")
+}
+
+func (f *fakeCommandClient) ListApprovalRules(context.Context) ([]*types.ApprovalRule, error) {
+	return f.approvalRules, nil
+}
+
+func (f *fakeCommandClient) CreateApprovalRule(_ context.Context, rule *types.ApprovalRule) (*types.ApprovalRule, error) {
+	f.createdApprovalRule = rule
+	created := *rule
+	created.ID = "rule_new"
+	return &created, nil
+}
+
+func (f *fakeCommandClient) UpdateApprovalRule(_ context.Context, id string, rule *types.ApprovalRule) (*types.ApprovalRule, error) {
+	updated := *rule
+	updated.ID = id
+	f.updatedApprovalRule = &updated
+	return &updated, nil
+}
+
+func (f *fakeCommandClient) DeleteApprovalRule(_ context.Context, id string) error {
+	f.deletedApprovalRule = id
+	return nil
+}
+
+func (f *fakeCommandClient) ExportTranscript(_ context.Context, id string, format transcriptexport.Format, options transcriptexport.Options) ([]byte, error) {
+	f.exportID, f.exportFormat, f.exportOptions = id, format, options
+	return []byte("# Session " + id + "\n"), nil
+}
+
+func (f *fakeCommandClient) ExportTranscriptDocument(_ context.Context, id string, options transcriptexport.Options) (*transcriptexport.Document, error) {
+	f.exportID, f.exportFormat, f.exportOptions = id, transcriptexport.FormatJSON, options
+	return &transcriptexport.Document{SessionID: id, Entries: []transcriptexport.Entry{
+		{Kind: transcriptexport.EntryMessage, Role: "user", Text: "<fix it>"},
+	}}, nil
+}
+
 func (f *fakeCommandClient) ShutdownDaemon(context.Context) error {
 	return f.shutdownErr
 }
@@ -24,8 +24,16 @@ Commands:
   interrupt stop the in-flight turn for a session
   send     send a message to a session
   tail     show recent session output (use --follow to stream live)
-  approvals list pending approvals for a session
+  approvals list pending approvals for a session, or --all sessions
   approve   respond to a pending approval
+  approval-rule list, add, edit, enable, disable or remove auto-approval rules
+  notify   send a test notification through the configured methods
+  workspace scan a directory for repositories, or export/import workspaces
+  worktree list, env, remove, prune, merge, rebase or push workspace worktrees
+  rollback restore a session's working tree to a turn checkpoint
+  finalize commit a finished session or workflow run and draft its PR description
+  snippet  list, show, add, edit, remove or render prompt snippets
+  export   export a session transcript as Markdown, HTML or JSON
   ui       run terminal UI
   version  print CLI build metadata
   help     show help
@@ -55,7 +63,24 @@ Examples:
   archon send <id> --input-items items.json --json
   archon interrupt <id>
   archon approvals <id>
+  archon approvals --all
   archon approve <id> --request-id 1 --decision allow_once
+  archon approval-rule add --workspace <workspace-id> --method command --prefix "go test" --decision allow_once
+  archon approval-rule add --provider opencode --method file_change --path "docs/**" --decision allow_always
+  archon notify test --trigger session.failed
+  archon workspace scan ~/src --import
+  archon workspace export --output team.json
+  archon workspace import team.json
+  archon worktree rebase <workspace-id> <worktree-id>
+  archon worktree remove --force <workspace-id> <worktree-id>
+  archon worktree env <workspace-id> <worktree-id> ci
+  archon rollback <id> --list
+  archon rollback <id> --turn 3 --notify
+  archon finalize <id> --dry-run
+  archon finalize --run <run-id> --message-file msg.txt --pr pr.md
+  archon snippet add review --body "Review {{file}} for {{concern}}"
+  archon snippet render review --var file=api.go --var concern=errors
+  archon export <id> --format html --reasoning -o session.html
 `
 
 var rootCommandAliases = map[string]string{
@@ -2,6 +2,7 @@ package app
 
 type activeInputContext struct {
 	input  *TextInput
+	header InputHeaderProvider
 	footer InputFooterProvider
 	frame  InputPanelFrame
 }
@@ -9,6 +10,7 @@ type activeInputContext struct {
 func (c activeInputContext) panel() InputPanel {
 	return InputPanel{
 		Input:  c.input,
+		Header: c.header,
 		Footer: c.footer,
 		Frame:  c.frame,
 	}
@@ -25,6 +27,7 @@ func (m *Model) activeInputContext() (activeInputContext, bool) {
 		}
 		return activeInputContext{
 			input:  m.chatInput,
+			header: InputHeaderFunc(m.composeAttachmentChips),
 			footer: InputFooterFunc(m.composeControlsLine),
 			frame:  m.inputFrame(InputFrameTargetCompose),
 		}, true
@@ -36,6 +39,22 @@ func (m *Model) activeInputContext() (activeInputContext, bool) {
 			input:  m.approvalInput,
 			footer: InputFooterFunc(m.approvalResponseFooter),
 		}, true
+	case uiModeFinalize:
+		if m.finalizeInput == nil {
+			return activeInputContext{}, false
+		}
+		return activeInputContext{
+			input:  m.finalizeInput,
+			footer: InputFooterFunc(m.finalizeFooter),
+		}, true
+	case uiModeApprovalRules:
+		if m.approvalRulesInput == nil {
+			return activeInputContext{}, false
+		}
+		return activeInputContext{
+			input:  m.approvalRulesInput,
+			footer: InputFooterFunc(m.approvalRulesFooter),
+		}, true
 	case uiModeAddNote:
 		if m.noteInput == nil {
 			return activeInputContext{}, false
@@ -57,6 +76,14 @@ func (m *Model) activeInputContext() (activeInputContext, bool) {
 			input:  m.recentsReplyInput,
 			footer: InputFooterFunc(m.recentsReplyFooter),
 		}, true
+	case uiModeTiles:
+		if m.tilesReplySessionID == "" || m.tilesReplyInput == nil {
+			return activeInputContext{}, false
+		}
+		return activeInputContext{
+			input:  m.tilesReplyInput,
+			footer: InputFooterFunc(m.sessionTilesReplyFooter),
+		}, true
 	default:
 		return activeInputContext{}, false
 	}
@@ -7,6 +7,7 @@ import (
 	"control/internal/client"
 	"control/internal/daemon/transcriptdomain"
 	"control/internal/guidedworkflows"
+	"control/internal/transcriptexport"
 	"control/internal/types"
 )
 
@@ -249,6 +250,60 @@ type SessionPinAPI interface {
 	PinSessionMessage(ctx context.Context, sessionID string, req client.PinSessionNoteRequest) (*types.Note, error)
 }
 
+type WorktreeLifecycleAPI interface {
+	WorktreeOperation(ctx context.Context, workspaceID, worktreeID string, op types.WorktreeOperation, req types.WorktreeOperationRequest) (*types.WorktreeOperationResult, error)
+}
+
+type SessionCheckpointAPI interface {
+	ListSessionCheckpoints(ctx context.Context, sessionID string) ([]*types.SessionCheckpoint, error)
+	RestoreSessionCheckpoint(ctx context.Context, sessionID, checkpointID string, req types.SessionCheckpointRestoreRequest) (*types.SessionCheckpointRestoreResult, error)
+}
+
+type FinalizeAPI interface {
+	SessionFinalizeDraft(ctx context.Context, sessionID string) (*types.FinalizeDraft, error)
+	FinalizeSession(ctx context.Context, sessionID string, req types.FinalizeRequest) (*types.FinalizeResult, error)
+	WorkflowRunFinalizeDraft(ctx context.Context, runID string) (*types.FinalizeDraft, error)
+	FinalizeWorkflowRun(ctx context.Context, runID string, req types.FinalizeRequest) (*types.FinalizeResult, error)
+}
+
+type ApprovalRuleAPI interface {
+	ListApprovalRules(ctx context.Context) ([]*types.ApprovalRule, error)
+	CreateApprovalRule(ctx context.Context, rule *types.ApprovalRule) (*types.ApprovalRule, error)
+	UpdateApprovalRule(ctx context.Context, id string, rule *types.ApprovalRule) (*types.ApprovalRule, error)
+	DeleteApprovalRule(ctx context.Context, id string) error
+}
+
+type ApprovalInboxAPI interface {
+	ListAllApprovals(ctx context.Context) ([]*types.PendingApproval, error)
+	ApprovalStream(ctx context.Context) (<-chan transcriptdomain.TranscriptEvent, func(), error)
+}
+
+type GitDiffAPI interface {
+	WorktreeDiff(ctx context.Context, worktreeID, base string) (*types.GitDiff, error)
+	SessionDiff(ctx context.Context, sessionID, base string) (*types.GitDiff, error)
+}
+
+type SessionUsageAPI interface {
+	SessionUsage(ctx context.Context, sessionID string) (*types.SessionUsage, error)
+}
+
+type SessionSymbolAPI interface {
+	SearchSessionSymbols(ctx context.Context, sessionID, query string, limit int) ([]types.CodeSymbol, error)
+}
+
+type SnippetListAPI interface {
+	ListSnippets(ctx context.Context, workspaceID string) ([]*types.Snippet, error)
+}
+
+type SessionForkAPI interface {
+	ForkSession(ctx context.Context, id string, req client.ForkSessionRequest) (*types.Session, error)
+}
+
+type TranscriptExportAPI interface {
+	ExportTranscript(ctx context.Context, id string, format transcriptexport.Format, options transcriptexport.Options) ([]byte, error)
+	ExportTranscriptDocument(ctx context.Context, id string, options transcriptexport.Options) (*transcriptexport.Document, error)
+}
+
 type NotesAPI interface {
 	NoteListAPI
 	NoteCreateAPI
@@ -525,6 +580,90 @@ func (a *ClientAPI) PinSessionMessage(ctx context.Context, sessionID string, req
 	return a.client.PinSessionMessage(ctx, sessionID, req)
 }
 
+func (a *ClientAPI) WorktreeOperation(ctx context.Context, workspaceID, worktreeID string, op types.WorktreeOperation, req types.WorktreeOperationRequest) (*types.WorktreeOperationResult, error) {
+	return a.client.WorktreeOperation(ctx, workspaceID, worktreeID, op, req)
+}
+
+func (a *ClientAPI) ListSessionCheckpoints(ctx context.Context, sessionID string) ([]*types.SessionCheckpoint, error) {
+	return a.client.ListSessionCheckpoints(ctx, sessionID)
+}
+
+func (a *ClientAPI) RestoreSessionCheckpoint(ctx context.Context, sessionID, checkpointID string, req types.SessionCheckpointRestoreRequest) (*types.SessionCheckpointRestoreResult, error) {
+	return a.client.RestoreSessionCheckpoint(ctx, sessionID, checkpointID, req)
+}
+
+func (a *ClientAPI) SessionFinalizeDraft(ctx context.Context, sessionID string) (*types.FinalizeDraft, error) {
+	return a.client.SessionFinalizeDraft(ctx, sessionID)
+}
+
+func (a *ClientAPI) FinalizeSession(ctx context.Context, sessionID string, req types.FinalizeRequest) (*types.FinalizeResult, error) {
+	return a.client.FinalizeSession(ctx, sessionID, req)
+}
+
+func (a *ClientAPI) WorkflowRunFinalizeDraft(ctx context.Context, runID string) (*types.FinalizeDraft, error) {
+	return a.client.WorkflowRunFinalizeDraft(ctx, runID)
+}
+
+func (a *ClientAPI) FinalizeWorkflowRun(ctx context.Context, runID string, req types.FinalizeRequest) (*types.FinalizeResult, error) {
+	return a.client.FinalizeWorkflowRun(ctx, runID, req)
+}
+
+func (a *ClientAPI) WorktreeDiff(ctx context.Context, worktreeID, base string) (*types.GitDiff, error) {
+	return a.client.WorktreeDiff(ctx, worktreeID, base)
+}
+
+func (a *ClientAPI) SessionDiff(ctx context.Context, sessionID, base string) (*types.GitDiff, error) {
+	return a.client.SessionDiff(ctx, sessionID, base)
+}
+
+func (a *ClientAPI) SessionUsage(ctx context.Context, sessionID string) (*types.SessionUsage, error) {
+	return a.client.SessionUsage(ctx, sessionID)
+}
+
+func (a *ClientAPI) SearchSessionSymbols(ctx context.Context, sessionID, query string, limit int) ([]types.CodeSymbol, error) {
+	return a.client.SearchSessionSymbols(ctx, sessionID, query, limit)
+}
+
+func (a *ClientAPI) ListAllApprovals(ctx context.Context) ([]*types.PendingApproval, error) {
+	return a.client.ListAllApprovals(ctx)
+}
+
+func (a *ClientAPI) ApprovalStream(ctx context.Context) (<-chan transcriptdomain.TranscriptEvent, func(), error) {
+	return a.client.ApprovalStream(ctx)
+}
+
+func (a *ClientAPI) ListApprovalRules(ctx context.Context) ([]*types.ApprovalRule, error) {
+	return a.client.ListApprovalRules(ctx)
+}
+
+func (a *ClientAPI) CreateApprovalRule(ctx context.Context, rule *types.ApprovalRule) (*types.ApprovalRule, error) {
+	return a.client.CreateApprovalRule(ctx, rule)
+}
+
+func (a *ClientAPI) UpdateApprovalRule(ctx context.Context, id string, rule *types.ApprovalRule) (*types.ApprovalRule, error) {
+	return a.client.UpdateApprovalRule(ctx, id, rule)
+}
+
+func (a *ClientAPI) DeleteApprovalRule(ctx context.Context, id string) error {
+	return a.client.DeleteApprovalRule(ctx, id)
+}
+
+func (a *ClientAPI) ListSnippets(ctx context.Context, workspaceID string) ([]*types.Snippet, error) {
+	return a.client.ListSnippets(ctx, workspaceID)
+}
+
+func (a *ClientAPI) ForkSession(ctx context.Context, id string, req client.ForkSessionRequest) (*types.Session, error) {
+	return a.client.ForkSession(ctx, id, req)
+}
+
+func (a *ClientAPI) ExportTranscript(ctx context.Context, id string, format transcriptexport.Format, options transcriptexport.Options) ([]byte, error) {
+	return a.client.ExportTranscript(ctx, id, format, options)
+}
+
+func (a *ClientAPI) ExportTranscriptDocument(ctx context.Context, id string, options transcriptexport.Options) (*transcriptexport.Document, error) {
+	return a.client.ExportTranscriptDocument(ctx, id, options)
+}
+
 func (a *ClientAPI) GetAppState(ctx context.Context) (*types.AppState, error) {
 	return a.client.GetAppState(ctx)
 }
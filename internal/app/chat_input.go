@@ -83,6 +83,13 @@ func (c *TextInput) SetPlaceholder(value string) {
 	c.input.Placeholder = value
 }
 
+func (c *TextInput) Placeholder() string {
+	if c == nil {
+		return ""
+	}
+	return c.input.Placeholder
+}
+
 func (c *TextInput) SetValue(value string) {
 	if c == nil {
 		return
@@ -4,8 +4,6 @@ import (
 	"strings"
 
 	tea "charm.land/bubbletea/v2"
-
-	"control/internal/types"
 )
 
 type ChatInputAddonController struct {
@@ -103,51 +101,10 @@ func (c *ChatInputAddonController) openComposeOptionPicker(m *Model, target comp
 	if provider == "" {
 		return false
 	}
-	catalog := m.providerOptionCatalog(provider)
-	if catalog == nil {
-		return false
-	}
-	options := make([]selectOption, 0, 8)
-	selectedID := ""
-	current := m.composeRuntimeOptions()
-	switch target {
-	case composeOptionModel:
-		for _, model := range catalog.Models {
-			value := strings.TrimSpace(model)
-			if value == "" {
-				continue
-			}
-			options = append(options, selectOption{id: value, label: value})
-		}
-		if current != nil {
-			selectedID = strings.TrimSpace(current.Model)
-		}
-	case composeOptionReasoning:
-		model := ""
-		if current != nil {
-			model = current.Model
-		}
-		for _, level := range m.modelReasoningLevels(provider, model) {
-			value := strings.TrimSpace(string(level))
-			if value == "" {
-				continue
-			}
-			options = append(options, selectOption{id: value, label: value})
-		}
-		if current != nil {
-			selectedID = strings.TrimSpace(string(current.Reasoning))
-		}
-	case composeOptionAccess:
-		for _, level := range catalog.AccessLevels {
-			value := strings.TrimSpace(string(level))
-			if value == "" {
-				continue
-			}
-			options = append(options, selectOption{id: value, label: value})
-		}
-		if current != nil {
-			selectedID = strings.TrimSpace(string(current.Access))
-		}
+	values, selectedID := m.composeRuntimeOptionValues(provider, target)
+	options := make([]selectOption, 0, len(values))
+	for _, value := range values {
+		options = append(options, selectOption{id: value, label: value})
 	}
 	if len(options) == 0 {
 		return false
@@ -230,45 +187,7 @@ func (c *ChatInputAddonController) applyComposeOptionSelection(m *Model, value s
 	if target == composeOptionNone {
 		return nil
 	}
-	options := m.composeRuntimeOptions()
-	if options == nil {
-		return nil
-	}
-	value = strings.TrimSpace(value)
-	if value == "" {
-		return nil
-	}
-	switch target {
-	case composeOptionModel:
-		options.Model = value
-		m.normalizeComposeRuntimeOptionsForModel(m.composeProvider(), options)
-	case composeOptionReasoning:
-		options.Reasoning = types.ReasoningLevel(value)
-	case composeOptionAccess:
-		options.Access = types.AccessLevel(value)
-	}
-	provider := m.composeProvider()
-	m.setComposeDefaultForProvider(provider, options)
-	saveDefaults := m.requestAppStateSaveCmd()
-	if m.newSession != nil {
-		m.newSession.runtimeOptions = types.CloneRuntimeOptions(options)
-		m.setStatusMessage("session options updated")
-		return saveDefaults
-	}
-	sessionID := strings.TrimSpace(m.composeSessionID())
-	if sessionID == "" {
-		if saveDefaults != nil {
-			return saveDefaults
-		}
-		return nil
-	}
-	m.setSessionRuntimeOptionsLocal(sessionID, options)
-	update := updateSessionRuntimeCmd(m.sessionAPI, sessionID, options)
-	m.setStatusMessage("updating session options")
-	if saveDefaults != nil {
-		return tea.Batch(update, saveDefaults)
-	}
-	return update
+	return m.applyComposeRuntimeOption(target, value)
 }
 
 func (c *ChatInputAddonController) composeOptionPopupPlacement(m *Model) (string, int, int) {
@@ -358,7 +358,7 @@ func (t *ChatTranscript) AppendItem(item map[string]any) {
 	case "agentMessageEnd":
 		t.FinishAgentBlock()
 	case "userMessage":
-		if text := extractContentText(item["content"]); text != "" {
+		if text := extractUserMessageText(item["content"]); text != "" {
 			t.appendUserMessageWithMetaAt(text, createdAt, metadata.turnID, metadata.providerMessageID)
 			return
 		}
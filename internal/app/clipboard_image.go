@@ -0,0 +1,121 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"control/internal/config"
+	"control/internal/types"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+const clipboardImageReadTimeout = 5 * time.Second
+
+var errClipboardNoImage = errors.New("no image in clipboard")
+
+// ClipboardImageReader reads a PNG image from the system clipboard.
+// Terminals only paste text, so images are fetched from the clipboard
+// helper of the desktop environment instead.
+type ClipboardImageReader interface {
+	ReadImage(context.Context) ([]byte, error)
+}
+
+type defaultClipboardImageReader struct{}
+
+type clipboardImageCommand struct {
+	name string
+	args []string
+}
+
+// clipboardImageCommands lists the helpers tried in order for the current
+// platform and display server.
+var clipboardImageCommands = func() []clipboardImageCommand {
+	if runtime.GOOS == "darwin" {
+		return []clipboardImageCommand{{name: "pngpaste", args: []string{"-"}}}
+	}
+	var commands []clipboardImageCommand
+	if strings.TrimSpace(os.Getenv("WAYLAND_DISPLAY")) != "" {
+		commands = append(commands, clipboardImageCommand{name: "wl-paste", args: []string{"--no-newline", "--type", "image/png"}})
+	}
+	if strings.TrimSpace(os.Getenv("DISPLAY")) != "" {
+		commands = append(commands, clipboardImageCommand{name: "xclip", args: []string{"-selection", "clipboard", "-t", "image/png", "-o"}})
+	}
+	return commands
+}
+
+func (defaultClipboardImageReader) ReadImage(ctx context.Context) ([]byte, error) {
+	commands := clipboardImageCommands()
+	if len(commands) == 0 {
+		if missingDisplay() {
+			return nil, errors.New("no GUI clipboard available (DISPLAY/WAYLAND_DISPLAY unset)")
+		}
+		return nil, errors.New("image paste is not supported on this platform")
+	}
+	var missing []string
+	for _, command := range commands {
+		path, err := exec.LookPath(command.name)
+		if err != nil {
+			missing = append(missing, command.name)
+			continue
+		}
+		out, err := exec.CommandContext(ctx, path, command.args...).Output()
+		if err != nil || !isPNGData(out) {
+			return nil, errClipboardNoImage
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("install %s to paste images", strings.Join(missing, " or "))
+}
+
+func isPNGData(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n"))
+}
+
+func WithClipboardImageReader(reader ClipboardImageReader) ModelOption {
+	return func(m *Model) {
+		if m == nil || reader == nil {
+			return
+		}
+		m.clipboardImages = reader
+	}
+}
+
+var composeAttachmentsDir = config.AttachmentsDir
+
+// pasteClipboardImageCmd saves the clipboard image under the attachments
+// directory and attaches the saved file.
+func (m *Model) pasteClipboardImageCmd() tea.Cmd {
+	reader := m.clipboardImages
+	if reader == nil {
+		reader = defaultClipboardImageReader{}
+	}
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), clipboardImageReadTimeout)
+		defer cancel()
+		data, err := reader.ReadImage(ctx)
+		if err != nil {
+			return composeAttachmentPastedMsg{err: err}
+		}
+		dir, err := composeAttachmentsDir()
+		if err != nil {
+			return composeAttachmentPastedMsg{err: err}
+		}
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return composeAttachmentPastedMsg{err: err}
+		}
+		path := filepath.Join(dir, "paste-"+time.Now().UTC().Format("20060102-150405.000")+".png")
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			return composeAttachmentPastedMsg{err: err}
+		}
+		return composeAttachmentPastedMsg{attachment: types.NewInputAttachment(path)}
+	}
+}
@@ -0,0 +1,153 @@
+package app
+
+import (
+	"slices"
+	"strings"
+)
+
+const (
+	commandPaletteRecentLimit = 8
+	commandPaletteMaxWidth    = 76
+	commandPaletteMaxRows     = 14
+)
+
+// commandPaletteEntry is a keybinding command as listed in the palette:
+// its current key and whether it applies in the current mode.
+type commandPaletteEntry struct {
+	Command   string
+	Title     string
+	Key       string
+	Available bool
+}
+
+// CommandPaletteController lists every keybinding command in a fuzzy
+// picker, recently used commands first.
+type CommandPaletteController struct {
+	picker  *SelectPicker
+	open    bool
+	entries map[string]commandPaletteEntry
+	recent  []string
+}
+
+func NewCommandPaletteController() *CommandPaletteController {
+	return &CommandPaletteController{
+		picker:  NewSelectPicker(commandPaletteMaxWidth, commandPaletteMaxRows),
+		entries: map[string]commandPaletteEntry{},
+	}
+}
+
+func (c *CommandPaletteController) IsOpen() bool {
+	return c != nil && c.open
+}
+
+// Open shows entries, moving recently used commands to the top in the order
+// they were last used. The remaining entries keep their given order.
+func (c *CommandPaletteController) Open(entries []commandPaletteEntry) {
+	if c == nil {
+		return
+	}
+	c.entries = make(map[string]commandPaletteEntry, len(entries))
+	for _, entry := range entries {
+		c.entries[entry.Command] = entry
+	}
+	ordered := make([]commandPaletteEntry, 0, len(entries))
+	for _, command := range c.recent {
+		if entry, ok := c.entries[command]; ok {
+			ordered = append(ordered, entry)
+		}
+	}
+	for _, entry := range entries {
+		if !slices.Contains(c.recent, entry.Command) {
+			ordered = append(ordered, entry)
+		}
+	}
+	options := make([]selectOption, 0, len(ordered))
+	for _, entry := range ordered {
+		options = append(options, selectOption{
+			id:     entry.Command,
+			label:  c.optionLabel(entry),
+			search: entry.Title + " " + entry.Command + " " + entry.Key,
+		})
+	}
+	c.picker.SetQuery("")
+	c.picker.SetOptions(options)
+	if len(ordered) > 0 {
+		c.picker.SelectID(ordered[0].Command)
+	}
+	c.open = true
+}
+
+func (c *CommandPaletteController) Close() {
+	if c == nil {
+		return
+	}
+	c.open = false
+	c.picker.SetQuery("")
+}
+
+func (c *CommandPaletteController) Picker() *SelectPicker {
+	if c == nil {
+		return nil
+	}
+	return c.picker
+}
+
+func (c *CommandPaletteController) Selected() (commandPaletteEntry, bool) {
+	if c == nil || c.picker == nil {
+		return commandPaletteEntry{}, false
+	}
+	entry, ok := c.entries[c.picker.SelectedID()]
+	return entry, ok
+}
+
+// RecordUse moves command to the front of the recently used list.
+func (c *CommandPaletteController) RecordUse(command string) {
+	if c == nil || strings.TrimSpace(command) == "" {
+		return
+	}
+	next := []string{command}
+	for _, existing := range c.recent {
+		if existing != command && len(next) < commandPaletteRecentLimit {
+			next = append(next, existing)
+		}
+	}
+	c.recent = next
+}
+
+func (c *CommandPaletteController) Recent() []string {
+	if c == nil {
+		return nil
+	}
+	return append([]string(nil), c.recent...)
+}
+
+// View renders the palette centered in the given area.
+func (c *CommandPaletteController) View(maxWidth, maxHeight int) (string, int, int) {
+	if !c.IsOpen() {
+		return "", 0, 0
+	}
+	width := max(32, min(commandPaletteMaxWidth, maxWidth-4))
+	rows := max(3, min(commandPaletteMaxRows, maxHeight-6))
+	c.picker.SetSize(width, rows)
+	lines := []string{
+		settingsMenuTitleStyle.Render(" COMMANDS "),
+		c.picker.View(),
+		settingsMenuHintStyle.Render("type to filter  enter run  esc close"),
+	}
+	block := settingsMenuBorderStyle.Render(strings.Join(lines, "\n"))
+	return centerOverlayPlacement(block, maxWidth, maxHeight)
+}
+
+func (c *CommandPaletteController) optionLabel(entry commandPaletteEntry) string {
+	label := entry.Title
+	if entry.Key != "" {
+		label += " [" + entry.Key + "]"
+	}
+	if slices.Contains(c.recent, entry.Command) {
+		label += " recent"
+	}
+	if !entry.Available {
+		label += " (unavailable here)"
+	}
+	return label
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+func commandPaletteEntryFor(t *testing.T, m *Model, command string) commandPaletteEntry {
+	t.Helper()
+	for _, entry := range m.commandPaletteEntries() {
+		if entry.Command == command {
+			return entry
+		}
+	}
+	t.Fatalf("expected palette entry for %s", command)
+	return commandPaletteEntry{}
+}
+
+func TestCommandPaletteOpensOnHotkey(t *testing.T) {
+	m := NewModel(nil)
+	m.resize(120, 40)
+
+	nextModel, _ := m.Update(tea.KeyPressMsg{Code: 'p', Mod: tea.ModCtrl})
+	next := asModel(t, nextModel)
+	if !next.commandPalette.IsOpen() {
+		t.Fatalf("expected command palette to open")
+	}
+	if _, ok := next.commandPalette.Selected(); !ok {
+		t.Fatalf("expected a selected command")
+	}
+
+	nextModel, _ = next.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	next = asModel(t, nextModel)
+	if next.commandPalette.IsOpen() {
+		t.Fatalf("expected esc to close the palette")
+	}
+}
+
+func TestCommandPaletteEntriesShowBoundKeysAndAvailability(t *testing.T) {
+	m := NewModel(nil)
+	m.applyKeybindings(NewKeybindings(map[string]string{
+		KeyCommandRefresh: "ctrl+y",
+	}))
+
+	refresh := commandPaletteEntryFor(t, &m, KeyCommandRefresh)
+	if refresh.Key != "ctrl+y" || refresh.Title != "Refresh" || !refresh.Available {
+		t.Fatalf("unexpected refresh entry %#v", refresh)
+	}
+	submit := commandPaletteEntryFor(t, &m, KeyCommandInputSubmit)
+	if submit.Available {
+		t.Fatalf("expected compose-only command to be unavailable in normal mode")
+	}
+	for _, entry := range m.commandPaletteEntries() {
+		if entry.Command == KeyCommandCommandPalette {
+			t.Fatalf("expected the palette to not list itself")
+		}
+	}
+}
+
+func TestCommandPaletteRunReplaysKeyAndRecordsRecent(t *testing.T) {
+	m := NewModel(nil)
+	m.applyKeybindings(NewKeybindings(map[string]string{
+		KeyCommandRefresh: "ctrl+y",
+	}))
+	m.commandPalette.Open(m.commandPaletteEntries())
+
+	cmd := m.runCommandPaletteEntry(commandPaletteEntryFor(t, &m, KeyCommandRefresh))
+	if cmd == nil {
+		t.Fatalf("expected a command replaying the key binding")
+	}
+	key, ok := cmd().(tea.KeyPressMsg)
+	if !ok || key.String() != "ctrl+y" {
+		t.Fatalf("expected ctrl+y key press, got %#v", key)
+	}
+	if m.commandPalette.IsOpen() {
+		t.Fatalf("expected palette to close after running a command")
+	}
+
+	m.commandPalette.Open(m.commandPaletteEntries())
+	entry, ok := m.commandPalette.Selected()
+	if !ok || entry.Command != KeyCommandRefresh {
+		t.Fatalf("expected recently used command first, got %#v", entry)
+	}
+
+	if cmd := m.runCommandPaletteEntry(commandPaletteEntryFor(t, &m, KeyCommandInputSubmit)); cmd != nil {
+		t.Fatalf("expected unavailable command to not run")
+	}
+	if !m.commandPalette.IsOpen() {
+		t.Fatalf("expected palette to stay open for unavailable command")
+	}
+}
+
+func TestKeyPressForBindingRoundTripsDefaults(t *testing.T) {
+	for command, binding := range defaultKeybindingByCommand {
+		key, ok := keyPressForBinding(binding)
+		if !ok {
+			t.Fatalf("expected %s binding %q to be replayable", command, binding)
+		}
+		if key.String() != binding {
+			t.Fatalf("expected %q, got %q", binding, key.String())
+		}
+	}
+}
@@ -115,6 +115,32 @@ func fetchNotesCmd(api NoteListAPI, scope noteScopeTarget) tea.Cmd {
 	}
 }
 
+func fetchGitDiffCmd(api GitDiffAPI, target diffPanelTarget) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		defer cancel()
+		var (
+			diff *types.GitDiff
+			err  error
+		)
+		if target.SessionID != "" {
+			diff, err = api.SessionDiff(ctx, target.SessionID, "")
+		} else {
+			diff, err = api.WorktreeDiff(ctx, target.WorktreeID, "")
+		}
+		return gitDiffMsg{target: target, diff: diff, err: err}
+	}
+}
+
+func fetchSessionUsageCmd(api SessionUsageAPI, sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+		defer cancel()
+		usage, err := api.SessionUsage(ctx, sessionID)
+		return sessionUsageMsg{sessionID: sessionID, usage: usage, err: err}
+	}
+}
+
 func notesPanelReflowCmd() tea.Cmd {
 	return func() tea.Msg {
 		return notesPanelReflowMsg{}
@@ -552,6 +578,75 @@ func deleteWorktreeCmd(api WorktreeDeleteAPI, workspaceID, worktreeID string) te
 	}
 }
 
+func worktreeOperationCmd(api WorktreeLifecycleAPI, workspaceID, worktreeID string, op types.WorktreeOperation, req types.WorktreeOperationRequest) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		result, err := api.WorktreeOperation(ctx, workspaceID, worktreeID, op, req)
+		return worktreeOperationMsg{workspaceID: workspaceID, worktreeID: worktreeID, op: op, result: result, err: err}
+	}
+}
+
+func fetchFinalizeDraftCmd(api FinalizeAPI, target finalizeTarget) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		var (
+			draft *types.FinalizeDraft
+			err   error
+		)
+		if target.runID != "" {
+			draft, err = api.WorkflowRunFinalizeDraft(ctx, target.runID)
+		} else {
+			draft, err = api.SessionFinalizeDraft(ctx, target.sessionID)
+		}
+		return finalizeDraftMsg{target: target, draft: draft, err: err}
+	}
+}
+
+func finalizeCmd(api FinalizeAPI, target finalizeTarget, message string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+		defer cancel()
+		req := types.FinalizeRequest{Message: message, PullRequest: true}
+		var (
+			result *types.FinalizeResult
+			err    error
+		)
+		if target.runID != "" {
+			result, err = api.FinalizeWorkflowRun(ctx, target.runID, req)
+		} else {
+			result, err = api.FinalizeSession(ctx, target.sessionID, req)
+		}
+		return finalizeResultMsg{target: target, result: result, err: err}
+	}
+}
+
+// restoreTurnCheckpointCmd rolls the session's working tree back to the
+// checkpoint taken when turnID started and tells the agent what changed.
+func restoreTurnCheckpointCmd(api SessionCheckpointAPI, sessionID, turnID string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		checkpoints, err := api.ListSessionCheckpoints(ctx, sessionID)
+		if err != nil {
+			return checkpointRestoreMsg{sessionID: sessionID, turnID: turnID, err: err}
+		}
+		checkpointID := ""
+		for _, checkpoint := range checkpoints {
+			if checkpoint != nil && checkpoint.TurnID == turnID {
+				checkpointID = checkpoint.ID
+				break
+			}
+		}
+		if checkpointID == "" {
+			return checkpointRestoreMsg{sessionID: sessionID, turnID: turnID, err: errors.New("no checkpoint for turn " + turnID)}
+		}
+		result, err := api.RestoreSessionCheckpoint(ctx, sessionID, checkpointID, types.SessionCheckpointRestoreRequest{Notify: true})
+		return checkpointRestoreMsg{sessionID: sessionID, turnID: turnID, result: result, err: err}
+	}
+}
+
 func fetchHistoryCmdWithContext(api SessionHistoryAPI, id, key string, lines int, parent context.Context) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := commandWithTimeout(parent, 8*time.Second)
@@ -638,6 +733,28 @@ func fetchRecentsPreviewCmd(api SessionHistoryAPI, id, revision string, lines in
 	}
 }
 
+func fetchSessionTileSnapshotCmd(api SessionTranscriptSnapshotAPI, id string, lines int) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		resp, err := api.GetTranscriptSnapshot(ctx, id, lines)
+		return sessionTileSnapshotMsg{id: id, snapshot: resp, err: err}
+	}
+}
+
+func openSessionTileStreamCmd(api SessionTranscriptStreamAPI, id, afterRevision string) tea.Cmd {
+	return func() tea.Msg {
+		ch, cancel, err := api.TranscriptStream(context.Background(), id, afterRevision)
+		return sessionTileStreamMsg{id: id, ch: ch, cancel: cancel, err: err}
+	}
+}
+
+func sessionTileReconnectCmd(id string, delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return sessionTileReconnectMsg{id: id}
+	})
+}
+
 func openTranscriptStreamCmd(api SessionTranscriptStreamAPI, id, afterRevision string) tea.Cmd {
 	return openTranscriptStreamCmdWithContextAndRequest(api, id, afterRevision, nil, transcriptStreamOpenRequest{})
 }
@@ -802,11 +919,21 @@ func undismissSessionCmd(api SessionUndismissAPI, id string) tea.Cmd {
 }
 
 func sendSessionCmd(api SessionSendAPI, id, text string, token int) tea.Cmd {
+	return sendSessionInputCmd(api, id, text, nil, nil, token)
+}
+
+// sendSessionInputCmd sends text with compose attachments and mentions as
+// structured input items; plain text goes as the text field alone.
+func sendSessionInputCmd(api SessionSendAPI, id, text string, attachments []types.InputAttachment, mentions []types.InputMention, token int) tea.Cmd {
 	return func() tea.Msg {
-		log.Printf("ui send: id=%s text_len=%d", id, len(text))
+		log.Printf("ui send: id=%s text_len=%d attachments=%d mentions=%d", id, len(text), len(attachments), len(mentions))
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 		defer cancel()
-		resp, err := api.SendMessage(ctx, id, client.SendSessionRequest{Text: text})
+		req := client.SendSessionRequest{Text: text}
+		if len(attachments) > 0 || len(mentions) > 0 {
+			req.Input = composeSendInput(text, attachments, mentions)
+		}
+		resp, err := api.SendMessage(ctx, id, req)
 		turnID := ""
 		if resp != nil {
 			turnID = resp.TurnID
@@ -858,18 +985,22 @@ func startSessionCmd(api WorkspaceSessionStartAPI, workspaceID, worktreeID, prov
 	return startSessionCmdWithContext(api, workspaceID, worktreeID, provider, text, runtimeOptions, nil)
 }
 
+func sessionStartTimeout(provider string) time.Duration {
+	switch strings.ToLower(strings.TrimSpace(provider)) {
+	case "opencode", "kilocode":
+		// OpenCode/Kilo cold starts can take longer on first run.
+		return 90 * time.Second
+	case "hermes":
+		// Hermes ACP startup includes MCP server initialization.
+		return 90 * time.Second
+	default:
+		return 8 * time.Second
+	}
+}
+
 func startSessionCmdWithContext(api WorkspaceSessionStartAPI, workspaceID, worktreeID, provider, text string, runtimeOptions *types.SessionRuntimeOptions, parent context.Context) tea.Cmd {
 	return func() tea.Msg {
-		timeout := 8 * time.Second
-		switch strings.ToLower(strings.TrimSpace(provider)) {
-		case "opencode", "kilocode":
-			// OpenCode/Kilo cold starts can take longer on first run.
-			timeout = 90 * time.Second
-		case "hermes":
-			// Hermes ACP startup includes MCP server initialization.
-			timeout = 90 * time.Second
-		}
-		ctx, cancel := commandWithTimeout(parent, timeout)
+		ctx, cancel := commandWithTimeout(parent, sessionStartTimeout(provider))
 		defer cancel()
 		req := client.StartSessionRequest{
 			Provider:       provider,
@@ -880,3 +1011,15 @@ func startSessionCmdWithContext(api WorkspaceSessionStartAPI, workspaceID, workt
 		return startSessionMsg{session: session, prompt: text, err: err}
 	}
 }
+
+func forkSessionCmd(api SessionForkAPI, id, provider, text string, parent context.Context) tea.Cmd {
+	return func() tea.Msg {
+		if api == nil {
+			return startSessionMsg{err: errors.New("session fork api is unavailable")}
+		}
+		ctx, cancel := commandWithTimeout(parent, sessionStartTimeout(provider))
+		defer cancel()
+		session, err := api.ForkSession(ctx, id, client.ForkSessionRequest{Text: text})
+		return startSessionMsg{session: session, err: err}
+	}
+}
@@ -0,0 +1,158 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"control/internal/types"
+
+	tea "charm.land/bubbletea/v2"
+	xansi "github.com/charmbracelet/x/ansi"
+)
+
+func (m *Model) addComposeAttachment(attachment types.InputAttachment) bool {
+	if m == nil || strings.TrimSpace(attachment.Path) == "" {
+		return false
+	}
+	for _, existing := range m.composeAttachments {
+		if existing.Path == attachment.Path {
+			m.setStatusMessage(attachment.DisplayName() + " is already attached")
+			return false
+		}
+	}
+	m.composeAttachments = append(m.composeAttachments, attachment)
+	m.setStatusMessage("attached " + attachment.DisplayName())
+	m.resize(m.width, m.height)
+	return true
+}
+
+func (m *Model) removeLastComposeAttachment() bool {
+	if m == nil || len(m.composeAttachments) == 0 {
+		return false
+	}
+	last := m.composeAttachments[len(m.composeAttachments)-1]
+	m.composeAttachments = m.composeAttachments[:len(m.composeAttachments)-1]
+	m.setStatusMessage("removed " + last.DisplayName())
+	m.resize(m.width, m.height)
+	return true
+}
+
+func (m *Model) clearComposeAttachments() {
+	if m == nil || len(m.composeAttachments) == 0 {
+		return
+	}
+	m.composeAttachments = nil
+	m.resize(m.width, m.height)
+}
+
+// attachComposeFileSearchSelection attaches the file highlighted in the @
+// popup instead of mentioning it, removing the typed fragment.
+func (m *Model) attachComposeFileSearchSelection() tea.Cmd {
+	controller := m.composeFileSearchController()
+	if m == nil || controller == nil || m.chatInput == nil {
+		return nil
+	}
+	fragment, fragmentOK := controller.Fragment()
+	candidate, candidateOK := controller.SelectedCandidate()
+	if !fragmentOK || !candidateOK {
+		m.setValidationStatus("type @ and pick a file to attach")
+		return nil
+	}
+	path := strings.TrimSpace(candidate.Path)
+	if !filepath.IsAbs(path) {
+		m.setValidationStatus("cannot attach " + candidate.DisplayPath + ": path is not absolute")
+		return nil
+	}
+	m.chatInput.ReplaceRuneRange(fragment.Start, fragment.End, "")
+	m.addComposeAttachment(types.NewInputAttachment(path))
+	return m.closeComposeFileSearchCmd()
+}
+
+// composeAttachmentFromPaste treats a paste of a single absolute path to an
+// existing image as an attachment, which is what terminals send when an
+// image is dropped onto them.
+func composeAttachmentFromPaste(content string) (types.InputAttachment, bool) {
+	path := strings.TrimSpace(content)
+	if path == "" || strings.ContainsAny(path, "\n\r") {
+		return types.InputAttachment{}, false
+	}
+	path = strings.Trim(path, `'"`)
+	path = strings.ReplaceAll(path, `\ `, " ")
+	if strings.HasPrefix(path, "file://") {
+		path = strings.TrimPrefix(path, "file://")
+	}
+	if !filepath.IsAbs(path) {
+		return types.InputAttachment{}, false
+	}
+	attachment := types.NewInputAttachment(path)
+	if attachment.Kind != types.InputAttachmentImage {
+		return types.InputAttachment{}, false
+	}
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return types.InputAttachment{}, false
+	}
+	return attachment, true
+}
+
+func (m *Model) applyComposeAttachmentPasted(msg composeAttachmentPastedMsg) {
+	if msg.err != nil {
+		m.setStatusWarning("image paste failed: " + msg.err.Error())
+		return
+	}
+	if m.mode != uiModeCompose {
+		return
+	}
+	m.addComposeAttachment(msg.attachment)
+}
+
+// composeAttachmentChips renders the pending attachments as one line of
+// chips above the compose input.
+func (m *Model) composeAttachmentChips() string {
+	if m == nil || len(m.composeAttachments) == 0 {
+		return ""
+	}
+	chips := make([]string, 0, len(m.composeAttachments))
+	for _, attachment := range m.composeAttachments {
+		chips = append(chips, "["+string(attachment.Kind)+": "+attachment.DisplayName()+"]")
+	}
+	line := strings.Join(chips, " ")
+	if width := m.viewport.Width(); width > 0 {
+		line = xansi.Truncate(line, width, "…")
+	}
+	return line
+}
+
+// composeSendInput builds the send input items for text, attachments and
+// mentions.
+func composeSendInput(text string, attachments []types.InputAttachment, mentions []types.InputMention) []map[string]any {
+	input := make([]map[string]any, 0, len(attachments)+len(mentions)+1)
+	if strings.TrimSpace(text) != "" {
+		input = append(input, map[string]any{"type": "text", "text": text})
+	}
+	for _, attachment := range attachments {
+		input = append(input, attachment.InputItem())
+	}
+	for _, mention := range mentions {
+		input = append(input, mention.InputItem())
+	}
+	return input
+}
+
+// composeEchoText is the text echoed locally for a sent message, noting its
+// attachments below the text.
+func composeEchoText(text string, attachments []types.InputAttachment) string {
+	if len(attachments) == 0 {
+		return text
+	}
+	names := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		names = append(names, attachment.DisplayName())
+	}
+	note := types.AttachmentNote(names)
+	if strings.TrimSpace(text) == "" {
+		return note
+	}
+	return text + "\n" + note
+}
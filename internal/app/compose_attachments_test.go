@@ -0,0 +1,199 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"control/internal/client"
+	"control/internal/types"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+type recordingSessionSendAPI struct {
+	requests []client.SendSessionRequest
+}
+
+func (s *recordingSessionSendAPI) SendMessage(_ context.Context, _ string, req client.SendSessionRequest) (*client.SendSessionResponse, error) {
+	s.requests = append(s.requests, req)
+	return &client.SendSessionResponse{OK: true, TurnID: "turn-1"}, nil
+}
+
+type stubClipboardImageReader struct {
+	data []byte
+	err  error
+}
+
+func (s stubClipboardImageReader) ReadImage(context.Context) ([]byte, error) {
+	return s.data, s.err
+}
+
+func TestComposeAttachKeyAttachesFileSearchSelection(t *testing.T) {
+	m := newPhase0ModelWithSession("codex")
+	api := newComposeFileSearchAPITestStub()
+	api.candidates["ma"] = []types.FileSearchCandidate{{Path: "/repo/main.go", DisplayPath: "main.go"}}
+	m.fileSearchAPI = api
+	m.enterCompose("s1")
+	m.chatInput.SetValue("see ")
+
+	for _, key := range []tea.KeyPressMsg{{Code: '@', Text: "@"}, {Code: 'm', Text: "m"}, {Code: 'a', Text: "a"}} {
+		nextModel, cmd := m.Update(key)
+		m = asModel(t, nextModel)
+		runModelCmd(t, &m, cmd)
+	}
+	advanceTick(t, &m)
+	if !m.composeFileSearch.Open() {
+		t.Fatalf("expected compose file autocomplete popup to open")
+	}
+
+	pressKey(t, &m, tea.KeyPressMsg{Code: 't', Mod: tea.ModCtrl})
+
+	if len(m.composeAttachments) != 1 || m.composeAttachments[0].Path != "/repo/main.go" {
+		t.Fatalf("expected main.go attached, got %#v", m.composeAttachments)
+	}
+	if got := m.chatInput.Value(); got != "see " {
+		t.Fatalf("expected @ fragment removed, got %q", got)
+	}
+	if m.composeFileSearch.Open() {
+		t.Fatalf("expected popup to close after attaching")
+	}
+	if got := m.composeAttachmentChips(); got != "[file: main.go]" {
+		t.Fatalf("unexpected chips: %q", got)
+	}
+}
+
+func TestComposeAttachmentChipsRenderAboveInput(t *testing.T) {
+	m := newPhase0ModelWithSession("codex")
+	m.enterCompose("s1")
+	before, ok := m.activeInputPanelLayout()
+	if !ok {
+		t.Fatalf("expected compose input layout")
+	}
+	m.addComposeAttachment(types.NewInputAttachment("/tmp/screen.png"))
+
+	layout, ok := m.activeInputPanelLayout()
+	if !ok {
+		t.Fatalf("expected compose input layout")
+	}
+	if got := layout.InputStartRow(); got != 1 {
+		t.Fatalf("expected input below one chip row, got %d", got)
+	}
+	if got, want := layout.LineCount(), before.LineCount()+1; got != want {
+		t.Fatalf("expected line count %d, got %d", want, got)
+	}
+	line, _ := layout.View()
+	if first := strings.Split(line, "\n")[0]; first != "[image: screen.png]" {
+		t.Fatalf("expected chips on the first line, got %q", first)
+	}
+}
+
+func TestComposeBackspaceOnEmptyInputRemovesLastAttachment(t *testing.T) {
+	m := newPhase0ModelWithSession("codex")
+	m.enterCompose("s1")
+	m.addComposeAttachment(types.NewInputAttachment("/tmp/a.png"))
+	m.addComposeAttachment(types.NewInputAttachment("/tmp/b.log"))
+
+	pressKey(t, &m, tea.KeyPressMsg{Code: tea.KeyBackspace})
+
+	if len(m.composeAttachments) != 1 || m.composeAttachments[0].Path != "/tmp/a.png" {
+		t.Fatalf("expected last attachment removed, got %#v", m.composeAttachments)
+	}
+}
+
+func TestComposePasteOfImagePathAttachesImage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "drop.png")
+	if err := os.WriteFile(path, []byte("png"), 0o600); err != nil {
+		t.Fatalf("write image: %v", err)
+	}
+	m := newPhase0ModelWithSession("codex")
+	m.enterCompose("s1")
+
+	pressKey(t, &m, tea.PasteMsg{Content: "'" + path + "'"})
+
+	if len(m.composeAttachments) != 1 || m.composeAttachments[0].Kind != types.InputAttachmentImage {
+		t.Fatalf("expected dropped image attached, got %#v", m.composeAttachments)
+	}
+	if got := m.chatInput.Value(); got != "" {
+		t.Fatalf("expected path not to be inserted, got %q", got)
+	}
+
+	pressKey(t, &m, tea.PasteMsg{Content: "/not/an/image.txt"})
+	if got := m.chatInput.Value(); got != "/not/an/image.txt" {
+		t.Fatalf("expected plain paste for non-image path, got %q", got)
+	}
+}
+
+func TestPasteClipboardImageCmdSavesAttachment(t *testing.T) {
+	dir := t.TempDir()
+	original := composeAttachmentsDir
+	composeAttachmentsDir = func() (string, error) { return dir, nil }
+	t.Cleanup(func() { composeAttachmentsDir = original })
+
+	m := newPhase0ModelWithSession("codex")
+	m.clipboardImages = stubClipboardImageReader{data: []byte("\x89PNG\r\n\x1a\ndata")}
+	m.enterCompose("s1")
+
+	msg, ok := m.pasteClipboardImageCmd()().(composeAttachmentPastedMsg)
+	if !ok || msg.err != nil {
+		t.Fatalf("expected pasted attachment, got %#v", msg)
+	}
+	if filepath.Dir(msg.attachment.Path) != dir || msg.attachment.Kind != types.InputAttachmentImage {
+		t.Fatalf("unexpected pasted attachment: %#v", msg.attachment)
+	}
+	m.applyComposeAttachmentPasted(msg)
+	if len(m.composeAttachments) != 1 {
+		t.Fatalf("expected pasted image attached, got %#v", m.composeAttachments)
+	}
+
+	m.clipboardImages = stubClipboardImageReader{err: errClipboardNoImage}
+	msg, _ = m.pasteClipboardImageCmd()().(composeAttachmentPastedMsg)
+	m.applyComposeAttachmentPasted(msg)
+	if !strings.Contains(m.status, "no image in clipboard") {
+		t.Fatalf("expected paste failure status, got %q", m.status)
+	}
+}
+
+func TestSubmitComposeInputSendsAttachments(t *testing.T) {
+	m := newPhase0ModelWithSession("codex")
+	m.enterCompose("s1")
+	m.addComposeAttachment(types.NewInputAttachment("/tmp/screen.png"))
+
+	if cmd := m.submitComposeInput(""); cmd == nil {
+		t.Fatalf("expected attachment-only message to be sent")
+	}
+	if len(m.composeAttachments) != 0 {
+		t.Fatalf("expected attachments cleared after send, got %#v", m.composeAttachments)
+	}
+	blocks := m.currentBlocks()
+	if len(blocks) == 0 || blocks[len(blocks)-1].Text != "[attached: screen.png]" {
+		t.Fatalf("expected local echo with attachment note, got %#v", blocks)
+	}
+
+	api := &recordingSessionSendAPI{}
+	attachment := types.NewInputAttachment("/tmp/screen.png")
+	sendSessionInputCmd(api, "s1", "look", []types.InputAttachment{attachment}, nil, 1)()
+	if len(api.requests) != 1 {
+		t.Fatalf("expected one send request, got %d", len(api.requests))
+	}
+	input := api.requests[0].Input
+	if len(input) != 2 || input[0]["text"] != "look" || input[1]["type"] != types.InputItemTypeAttachment || input[1]["path"] != "/tmp/screen.png" {
+		t.Fatalf("unexpected send input: %#v", input)
+	}
+}
+
+func TestSubmitComposeInputRejectsAttachmentsForNewSession(t *testing.T) {
+	m := newPhase0ModelWithSession("codex")
+	m.newSession = &newSessionTarget{workspaceID: "ws1", provider: "codex"}
+	m.mode = uiModeCompose
+	m.composeAttachments = []types.InputAttachment{types.NewInputAttachment("/tmp/screen.png")}
+
+	if cmd := m.submitComposeInput("hello"); cmd != nil {
+		t.Fatalf("expected new session start to be rejected")
+	}
+	if !strings.Contains(m.status, "attachments") {
+		t.Fatalf("expected attachment validation status, got %q", m.status)
+	}
+}
@@ -0,0 +1,292 @@
+package app
+
+import (
+	"strings"
+
+	"control/internal/types"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// ComposeMentionController is the popup for @session:, @note:, @run: and
+// @sym: mentions. It sits beside the file autocomplete, which stops at the
+// colon of a mention prefix.
+type ComposeMentionController struct {
+	picker     *SelectPicker
+	fragment   composeMentionFragment
+	open       bool
+	loading    bool
+	candidates map[string]types.InputMention
+	ids        []string
+}
+
+func NewComposeMentionController(width, height int) *ComposeMentionController {
+	return &ComposeMentionController{
+		picker:     NewSelectPicker(width, height),
+		candidates: map[string]types.InputMention{},
+	}
+}
+
+func (c *ComposeMentionController) SetSize(width, height int) {
+	if c == nil || c.picker == nil {
+		return
+	}
+	c.picker.SetSize(width, height)
+}
+
+// Show opens the popup for fragment. loading marks candidates that are
+// still being fetched.
+func (c *ComposeMentionController) Show(fragment composeMentionFragment, candidates []types.InputMention, loading bool) {
+	if c == nil {
+		return
+	}
+	c.fragment = fragment
+	c.open = true
+	c.loading = loading
+	c.SetCandidates(candidates)
+}
+
+func (c *ComposeMentionController) SetCandidates(candidates []types.InputMention) {
+	if c == nil || c.picker == nil {
+		return
+	}
+	c.candidates = map[string]types.InputMention{}
+	c.ids = c.ids[:0]
+	options := make([]selectOption, 0, max(1, len(candidates)))
+	for _, mention := range candidates {
+		token := mention.Token()
+		if _, exists := c.candidates[token]; exists {
+			continue
+		}
+		c.candidates[token] = mention
+		c.ids = append(c.ids, token)
+		label := strings.TrimSpace(mention.Label)
+		if label == "" {
+			label = mention.ID
+		}
+		options = append(options, selectOption{id: token, label: label, search: label + " " + token})
+	}
+	if len(options) == 0 {
+		label := " (no matches)"
+		if c.loading {
+			label = " (searching...)"
+		}
+		options = append(options, selectOption{label: label})
+	}
+	c.picker.SetQuery("")
+	c.picker.SetOptions(options)
+}
+
+func (c *ComposeMentionController) ApplyResults(kind types.InputMentionKind, query string, candidates []types.InputMention) bool {
+	if c == nil || !c.open {
+		return false
+	}
+	if current, ok := types.InputMentionKindForPrefix(c.fragment.Prefix); !ok || current != kind || c.fragment.Query != query {
+		return false
+	}
+	c.loading = false
+	c.SetCandidates(candidates)
+	return true
+}
+
+func (c *ComposeMentionController) Close() {
+	if c == nil {
+		return
+	}
+	c.open = false
+	c.loading = false
+	c.fragment = composeMentionFragment{}
+	c.candidates = map[string]types.InputMention{}
+	c.ids = nil
+	if c.picker != nil {
+		c.picker.SetOptions(nil)
+	}
+}
+
+func (c *ComposeMentionController) Open() bool {
+	return c != nil && c.open
+}
+
+func (c *ComposeMentionController) Fragment() composeMentionFragment {
+	if c == nil {
+		return composeMentionFragment{}
+	}
+	return c.fragment
+}
+
+func (c *ComposeMentionController) Move(delta int) {
+	if c == nil || c.picker == nil || !c.open {
+		return
+	}
+	c.picker.Move(delta)
+}
+
+func (c *ComposeMentionController) HandleClick(row int) bool {
+	if c == nil || c.picker == nil || !c.open {
+		return false
+	}
+	return c.picker.HandleClick(row)
+}
+
+func (c *ComposeMentionController) Selected() (types.InputMention, bool) {
+	if c == nil || c.picker == nil || !c.open {
+		return types.InputMention{}, false
+	}
+	mention, ok := c.candidates[c.picker.SelectedID()]
+	return mention, ok
+}
+
+func (c *ComposeMentionController) View() string {
+	if c == nil || c.picker == nil || !c.open {
+		return ""
+	}
+	return c.picker.View()
+}
+
+func (m *Model) syncComposeMentionAfterInput() tea.Cmd {
+	controller := m.composeMention
+	if m == nil || controller == nil {
+		return nil
+	}
+	if m.mode != uiModeCompose || m.chatInput == nil || m.composeOptionPickerOpen() {
+		controller.Close()
+		return nil
+	}
+	fragment, ok := activeComposeMentionFragment(m.chatInput.Value(), m.chatInput.CursorRuneIndex())
+	if !ok {
+		controller.Close()
+		return nil
+	}
+	provider, ok := m.composeMentionRegistryOrDefault().ProviderForPrefix(fragment.Prefix)
+	if !ok {
+		controller.Close()
+		return nil
+	}
+	if _, picked := m.recordedComposeMention("@" + fragment.Prefix + ":" + fragment.Query); picked {
+		controller.Close()
+		return nil
+	}
+	if controller.Open() && controller.Fragment() == fragment {
+		return nil
+	}
+	candidates, cmd := provider.Candidates(m, fragment.Query)
+	controller.Show(fragment, candidates, cmd != nil)
+	return cmd
+}
+
+func (m *Model) applyComposeMentionCandidates(msg composeMentionCandidatesMsg) {
+	if msg.err != nil {
+		m.setStatusWarning("mention search failed: " + msg.err.Error())
+	}
+	m.composeMention.ApplyResults(msg.kind, msg.query, msg.mentions)
+}
+
+// applyComposeMentionSelection replaces the typed fragment with the compact
+// token and remembers the mention so it is sent as a structured item.
+func (m *Model) applyComposeMentionSelection() tea.Cmd {
+	controller := m.composeMention
+	if m == nil || controller == nil || m.chatInput == nil {
+		return nil
+	}
+	mention, ok := controller.Selected()
+	fragment := controller.Fragment()
+	controller.Close()
+	if !ok {
+		return nil
+	}
+	replacement := mention.Token()
+	valueRunes := []rune(m.chatInput.Value())
+	if fragment.End >= len(valueRunes) || valueRunes[fragment.End] != ' ' {
+		replacement += " "
+	}
+	if !m.chatInput.ReplaceRuneRange(fragment.Start, fragment.End, replacement) {
+		return nil
+	}
+	m.rememberComposeMention(mention)
+	return nil
+}
+
+func (m *Model) rememberComposeMention(mention types.InputMention) {
+	for i, existing := range m.composeMentions {
+		if existing.Token() == mention.Token() {
+			m.composeMentions[i] = mention
+			return
+		}
+	}
+	m.composeMentions = append(m.composeMentions, mention)
+}
+
+// composeMentionsForSend returns the mentions whose tokens are still in
+// text: ones picked from the popup, and typed tokens whose IDs a provider
+// recognizes.
+func (m *Model) composeMentionsForSend(text string) []types.InputMention {
+	var out []types.InputMention
+	seen := map[string]struct{}{}
+	for _, token := range composeMentionTokens(text) {
+		if _, ok := seen[token]; ok {
+			continue
+		}
+		seen[token] = struct{}{}
+		if mention, ok := m.recordedComposeMention(token); ok {
+			out = append(out, mention)
+			continue
+		}
+		prefix, id, _ := strings.Cut(strings.TrimPrefix(token, "@"), ":")
+		provider, ok := m.composeMentionRegistryOrDefault().ProviderForPrefix(prefix)
+		if !ok {
+			continue
+		}
+		if mention, ok := provider.Resolve(m, id); ok {
+			out = append(out, mention)
+		}
+	}
+	return out
+}
+
+func (m *Model) recordedComposeMention(token string) (types.InputMention, bool) {
+	for _, mention := range m.composeMentions {
+		if mention.Token() == token {
+			return mention, true
+		}
+	}
+	return types.InputMention{}, false
+}
+
+func (m *Model) composeMentionPopupPlacement() (string, int, int) {
+	controller := m.composeMention
+	if m == nil || controller == nil || !controller.Open() {
+		return "", 0, 0
+	}
+	view := controller.View()
+	if strings.TrimSpace(view) == "" {
+		return "", 0, 0
+	}
+	height := len(strings.Split(view, "\n"))
+	row := m.composeControlsRow() - height
+	if row < 1 {
+		row = 1
+	}
+	return view, m.resolveMouseLayout().rightStart, row
+}
+
+func (m *Model) handleComposeMentionKey(key string) (bool, tea.Cmd) {
+	controller := m.composeMention
+	if controller == nil || !controller.Open() {
+		return false, nil
+	}
+	switch key {
+	case "esc":
+		controller.Close()
+		return true, nil
+	case "tab", "enter":
+		return true, m.applyComposeMentionSelection()
+	case "up":
+		controller.Move(-1)
+		return true, nil
+	case "down":
+		controller.Move(1)
+		return true, nil
+	default:
+		return false, nil
+	}
+}
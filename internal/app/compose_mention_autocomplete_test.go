@@ -0,0 +1,152 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"control/internal/guidedworkflows"
+	"control/internal/types"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+type stubSessionSymbolAPI struct {
+	symbols []types.CodeSymbol
+	queries []string
+}
+
+func (s *stubSessionSymbolAPI) SearchSessionSymbols(_ context.Context, _ string, query string, _ int) ([]types.CodeSymbol, error) {
+	s.queries = append(s.queries, query)
+	return s.symbols, nil
+}
+
+func newComposeMentionTestModel() Model {
+	m := newPhase0ModelWithSession("codex")
+	m.sessions = append(m.sessions, &types.Session{
+		ID:        "s2",
+		Provider:  "claude",
+		Status:    types.SessionStatusExited,
+		CreatedAt: time.Now().UTC(),
+		Title:     "Auth refactor",
+	})
+	m.sessionMeta["s2"] = &types.SessionMeta{SessionID: "s2", WorkspaceID: "ws1"}
+	return m
+}
+
+func TestActiveComposeMentionFragment(t *testing.T) {
+	cases := []struct {
+		value  string
+		ok     bool
+		prefix string
+		query  string
+	}{
+		{value: "see @session:au", ok: true, prefix: "session", query: "au"},
+		{value: "@sym:", ok: true, prefix: "sym", query: ""},
+		{value: "see @main.go", ok: false},
+		{value: "mail@note:x", ok: false},
+		{value: "@session:au done", ok: false},
+	}
+	for _, tc := range cases {
+		fragment, ok := activeComposeMentionFragment(tc.value, len([]rune(tc.value)))
+		if ok != tc.ok {
+			t.Fatalf("%q: expected ok=%v, got %v", tc.value, tc.ok, ok)
+		}
+		if ok && (fragment.Prefix != tc.prefix || fragment.Query != tc.query) {
+			t.Fatalf("%q: unexpected fragment %#v", tc.value, fragment)
+		}
+	}
+}
+
+func TestComposeSessionMentionSelectionSendsStructuredMention(t *testing.T) {
+	m := newComposeMentionTestModel()
+	m.enterCompose("s1")
+	m.chatInput.SetValue("compare with @session:auth")
+	runModelCmd(t, &m, m.syncComposeMentionAfterInput())
+
+	if !m.composeMention.Open() {
+		t.Fatalf("expected mention popup to open")
+	}
+	if view := m.composeMention.View(); !strings.Contains(view, "Auth refactor") || strings.Contains(view, "Session ·") {
+		t.Fatalf("expected other sessions only, got %q", view)
+	}
+
+	pressKey(t, &m, tea.KeyPressMsg{Code: tea.KeyTab})
+
+	if got := m.chatInput.Value(); got != "compare with @session:s2 " {
+		t.Fatalf("expected compact token inserted, got %q", got)
+	}
+	if m.composeMention.Open() {
+		t.Fatalf("expected popup to close after selection")
+	}
+	mentions := m.composeMentionsForSend(m.chatInput.Value())
+	if len(mentions) != 1 || mentions[0].Kind != types.InputMentionSession || mentions[0].ID != "s2" {
+		t.Fatalf("unexpected mentions: %#v", mentions)
+	}
+
+	if cmd := m.submitComposeInput(strings.TrimSpace(m.chatInput.Value())); cmd == nil {
+		t.Fatalf("expected message with mention to be sent")
+	}
+	if len(m.composeMentions) != 0 {
+		t.Fatalf("expected recorded mentions cleared after send, got %#v", m.composeMentions)
+	}
+
+	api := &recordingSessionSendAPI{}
+	sendSessionInputCmd(api, "s1", "compare with @session:s2", nil, mentions, 1)()
+	input := api.requests[0].Input
+	if len(input) != 2 || input[1]["type"] != types.InputItemTypeMention || input[1]["kind"] != "session" || input[1]["id"] != "s2" {
+		t.Fatalf("unexpected send input: %#v", input)
+	}
+}
+
+func TestComposeSymbolMentionUsesAsyncCandidates(t *testing.T) {
+	m := newComposeMentionTestModel()
+	api := &stubSessionSymbolAPI{symbols: []types.CodeSymbol{
+		{Name: "Start", Kind: "method", Container: "Server", Path: "/repo/server.go", Line: 12},
+	}}
+	m.symbolAPI = api
+	m.enterCompose("s1")
+	m.chatInput.SetValue("explain @sym:Sta")
+	runModelCmd(t, &m, m.syncComposeMentionAfterInput())
+
+	if len(api.queries) != 1 || api.queries[0] != "Sta" {
+		t.Fatalf("expected one symbol query, got %#v", api.queries)
+	}
+	pressKey(t, &m, tea.KeyPressMsg{Code: tea.KeyEnter})
+
+	if got := m.chatInput.Value(); got != "explain @sym:Server.Start " {
+		t.Fatalf("expected symbol token inserted, got %q", got)
+	}
+	mentions := m.composeMentionsForSend(m.chatInput.Value())
+	if len(mentions) != 1 || mentions[0].Path != "/repo/server.go" || mentions[0].Line != 12 {
+		t.Fatalf("expected symbol location carried on mention, got %#v", mentions)
+	}
+}
+
+func TestComposeMentionsForSendResolvesTypedTokens(t *testing.T) {
+	m := newComposeMentionTestModel()
+	m.workflowRuns = []*guidedworkflows.WorkflowRun{{ID: "gwf-1", TemplateName: "Fix bug"}}
+	m.enterCompose("s1")
+
+	mentions := m.composeMentionsForSend("see @run:gwf-1 and @session:s2, not @run:missing or @sym:Foo")
+	if len(mentions) != 2 {
+		t.Fatalf("expected two resolved mentions, got %#v", mentions)
+	}
+	if mentions[0].Kind != types.InputMentionRun || mentions[1].ID != "s2" {
+		t.Fatalf("unexpected mentions: %#v", mentions)
+	}
+}
+
+func TestSubmitComposeInputRejectsMentionsForNewSession(t *testing.T) {
+	m := newComposeMentionTestModel()
+	m.newSession = &newSessionTarget{workspaceID: "ws1", provider: "codex"}
+	m.mode = uiModeCompose
+
+	if cmd := m.submitComposeInput("continue @session:s2"); cmd != nil {
+		t.Fatalf("expected new session start to be rejected")
+	}
+	if !strings.Contains(m.status, "mentions") {
+		t.Fatalf("expected mention validation status, got %q", m.status)
+	}
+}
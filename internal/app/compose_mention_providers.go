@@ -0,0 +1,245 @@
+package app
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"control/internal/client"
+	"control/internal/guidedworkflows"
+	"control/internal/types"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+const composeMentionLimit = 20
+
+// composeMentionProvider supplies candidates for one @<prefix>: mention kind.
+// Candidates returns matches it can answer locally, or a command that
+// delivers them later as a composeMentionCandidatesMsg. Resolve turns a
+// token typed without the popup into a mention when the ID is known.
+type composeMentionProvider interface {
+	Kind() types.InputMentionKind
+	Candidates(m *Model, query string) ([]types.InputMention, tea.Cmd)
+	Resolve(m *Model, id string) (types.InputMention, bool)
+}
+
+type composeMentionRegistry struct {
+	providers map[types.InputMentionKind]composeMentionProvider
+}
+
+func newDefaultComposeMentionRegistry() *composeMentionRegistry {
+	registry := &composeMentionRegistry{providers: map[types.InputMentionKind]composeMentionProvider{}}
+	registry.Register(sessionMentionProvider{})
+	registry.Register(noteMentionProvider{})
+	registry.Register(runMentionProvider{})
+	registry.Register(symbolMentionProvider{})
+	return registry
+}
+
+func (r *composeMentionRegistry) Register(provider composeMentionProvider) {
+	if r == nil || provider == nil {
+		return
+	}
+	r.providers[provider.Kind()] = provider
+}
+
+func (r *composeMentionRegistry) ProviderForPrefix(prefix string) (composeMentionProvider, bool) {
+	if r == nil {
+		return nil, false
+	}
+	kind, ok := types.InputMentionKindForPrefix(prefix)
+	if !ok {
+		return nil, false
+	}
+	provider, ok := r.providers[kind]
+	return provider, ok
+}
+
+func WithComposeMentionProvider(provider composeMentionProvider) ModelOption {
+	return func(m *Model) {
+		if m == nil || provider == nil {
+			return
+		}
+		if m.composeMentionProviders == nil {
+			m.composeMentionProviders = newDefaultComposeMentionRegistry()
+		}
+		m.composeMentionProviders.Register(provider)
+	}
+}
+
+func (m *Model) composeMentionRegistryOrDefault() *composeMentionRegistry {
+	if m.composeMentionProviders == nil {
+		m.composeMentionProviders = newDefaultComposeMentionRegistry()
+	}
+	return m.composeMentionProviders
+}
+
+func mentionMatchesQuery(query string, fields ...string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return true
+	}
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), query) {
+			return true
+		}
+	}
+	return false
+}
+
+type sessionMentionProvider struct{}
+
+func (sessionMentionProvider) Kind() types.InputMentionKind { return types.InputMentionSession }
+
+func (sessionMentionProvider) Candidates(m *Model, query string) ([]types.InputMention, tea.Cmd) {
+	current := m.composeSessionID()
+	var out []types.InputMention
+	for _, session := range m.sessions {
+		if session == nil || session.ID == current {
+			continue
+		}
+		title := ResolveSessionTitle(session, m.sessionMeta[session.ID], "")
+		if !mentionMatchesQuery(query, title, session.ID) {
+			continue
+		}
+		out = append(out, types.InputMention{Kind: types.InputMentionSession, ID: session.ID, Label: title + " · " + session.Provider})
+		if len(out) == composeMentionLimit {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (sessionMentionProvider) Resolve(m *Model, id string) (types.InputMention, bool) {
+	for _, session := range m.sessions {
+		if session != nil && session.ID == id {
+			return types.InputMention{Kind: types.InputMentionSession, ID: id}, true
+		}
+	}
+	return types.InputMention{}, false
+}
+
+type noteMentionProvider struct{}
+
+func (noteMentionProvider) Kind() types.InputMentionKind { return types.InputMentionNote }
+
+// Candidates lists notes from the daemon so notes outside the open notes
+// panel can be mentioned too.
+func (noteMentionProvider) Candidates(m *Model, query string) ([]types.InputMention, tea.Cmd) {
+	if m.notesAPI == nil {
+		return noteMentions(m.notes, query), nil
+	}
+	api := m.notesAPI
+	return nil, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+		defer cancel()
+		notes, err := api.ListNotes(ctx, client.ListNotesRequest{})
+		return composeMentionCandidatesMsg{kind: types.InputMentionNote, query: query, mentions: noteMentions(notes, query), err: err}
+	}
+}
+
+func (noteMentionProvider) Resolve(m *Model, id string) (types.InputMention, bool) {
+	for _, note := range m.notes {
+		if note != nil && note.ID == id {
+			return types.InputMention{Kind: types.InputMentionNote, ID: id}, true
+		}
+	}
+	return types.InputMention{}, false
+}
+
+func noteMentions(notes []*types.Note, query string) []types.InputMention {
+	var out []types.InputMention
+	for _, note := range notes {
+		if note == nil {
+			continue
+		}
+		label := strings.TrimSpace(note.Title)
+		if label == "" {
+			label = truncateText(strings.Join(strings.Fields(note.Body), " "), 60)
+		}
+		if !mentionMatchesQuery(query, label, note.Body, note.ID) {
+			continue
+		}
+		out = append(out, types.InputMention{Kind: types.InputMentionNote, ID: note.ID, Label: label})
+		if len(out) == composeMentionLimit {
+			break
+		}
+	}
+	return out
+}
+
+type runMentionProvider struct{}
+
+func (runMentionProvider) Kind() types.InputMentionKind { return types.InputMentionRun }
+
+func (runMentionProvider) Candidates(m *Model, query string) ([]types.InputMention, tea.Cmd) {
+	runs := make([]*guidedworkflows.WorkflowRun, 0, len(m.workflowRuns))
+	for _, run := range m.workflowRuns {
+		if run != nil {
+			runs = append(runs, run)
+		}
+	}
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].CreatedAt.After(runs[j].CreatedAt) })
+	var out []types.InputMention
+	for _, run := range runs {
+		label := strings.TrimSpace(run.TemplateName)
+		if label == "" {
+			label = run.TemplateID
+		}
+		if !mentionMatchesQuery(query, label, run.ID, run.DisplayUserPrompt) {
+			continue
+		}
+		out = append(out, types.InputMention{Kind: types.InputMentionRun, ID: run.ID, Label: label + " · " + string(run.Status)})
+		if len(out) == composeMentionLimit {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (runMentionProvider) Resolve(m *Model, id string) (types.InputMention, bool) {
+	for _, run := range m.workflowRuns {
+		if run != nil && run.ID == id {
+			return types.InputMention{Kind: types.InputMentionRun, ID: id}, true
+		}
+	}
+	return types.InputMention{}, false
+}
+
+type symbolMentionProvider struct{}
+
+func (symbolMentionProvider) Kind() types.InputMentionKind { return types.InputMentionSymbol }
+
+// Candidates searches the daemon's symbol index of the compose session's
+// working directory.
+func (symbolMentionProvider) Candidates(m *Model, query string) ([]types.InputMention, tea.Cmd) {
+	sessionID := m.composeSessionID()
+	if m.symbolAPI == nil || sessionID == "" || strings.TrimSpace(query) == "" {
+		return nil, nil
+	}
+	api := m.symbolAPI
+	return nil, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+		defer cancel()
+		symbols, err := api.SearchSessionSymbols(ctx, sessionID, query, composeMentionLimit)
+		mentions := make([]types.InputMention, 0, len(symbols))
+		for _, symbol := range symbols {
+			mentions = append(mentions, types.InputMention{
+				Kind:  types.InputMentionSymbol,
+				ID:    symbol.QualifiedName(),
+				Label: symbol.Kind + " " + symbol.QualifiedName() + " · " + symbol.Path,
+				Path:  symbol.Path,
+				Line:  symbol.Line,
+			})
+		}
+		return composeMentionCandidatesMsg{kind: types.InputMentionSymbol, query: query, mentions: mentions, err: err}
+	}
+}
+
+// Resolve cannot locate a symbol from its name alone; symbols must be
+// picked from the popup.
+func (symbolMentionProvider) Resolve(*Model, string) (types.InputMention, bool) {
+	return types.InputMention{}, false
+}
@@ -82,3 +82,65 @@ func replaceComposeFileSearchFragment(input *TextInput, fragment composeFileSear
 	}
 	return input.ReplaceRuneRange(fragment.Start, fragment.End, replacement)
 }
+
+// composeMentionFragment is an @<prefix>:<query> structured mention being
+// typed at the cursor.
+type composeMentionFragment struct {
+	Start  int
+	End    int
+	Prefix string
+	Query  string
+}
+
+func activeComposeMentionFragment(value string, cursor int) (composeMentionFragment, bool) {
+	runes := []rune(value)
+	cursor = clamp(cursor, 0, len(runes))
+	start := -1
+	for i := cursor - 1; i >= 0; i-- {
+		if runes[i] == '@' {
+			start = i
+			break
+		}
+		if unicode.IsSpace(runes[i]) {
+			break
+		}
+	}
+	if start < 0 {
+		return composeMentionFragment{}, false
+	}
+	if start > 0 && !isComposeFileSearchTriggerBoundary(runes[start-1]) {
+		return composeMentionFragment{}, false
+	}
+	prefix, query, ok := strings.Cut(string(runes[start+1:cursor]), ":")
+	if !ok {
+		return composeMentionFragment{}, false
+	}
+	if _, ok := types.InputMentionKindForPrefix(prefix); !ok {
+		return composeMentionFragment{}, false
+	}
+	end := cursor
+	for end < len(runes) && !unicode.IsSpace(runes[end]) {
+		end++
+	}
+	return composeMentionFragment{Start: start, End: end, Prefix: prefix, Query: query}, true
+}
+
+// composeMentionTokens returns the @<prefix>:<id> tokens in text.
+func composeMentionTokens(text string) []string {
+	var tokens []string
+	for _, field := range strings.Fields(text) {
+		field = strings.TrimRight(field, ",;.!?)]}\"'`")
+		field = strings.TrimLeft(field, "([{<\"'`")
+		if !strings.HasPrefix(field, "@") {
+			continue
+		}
+		prefix, id, ok := strings.Cut(field[1:], ":")
+		if !ok || strings.TrimSpace(id) == "" {
+			continue
+		}
+		if _, ok := types.InputMentionKindForPrefix(prefix); ok {
+			tokens = append(tokens, field)
+		}
+	}
+	return tokens
+}
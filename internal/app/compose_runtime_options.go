@@ -129,6 +129,88 @@ func (m *Model) composeRuntimeOptions() *types.SessionRuntimeOptions {
 	return m.resolveComposeRuntimeOptions(provider, out)
 }
 
+// composeRuntimeOptionValues lists the values the provider catalog offers
+// for target along with the value currently in effect.
+func (m *Model) composeRuntimeOptionValues(provider string, target composeOptionKind) ([]string, string) {
+	catalog := m.providerOptionCatalog(provider)
+	if catalog == nil {
+		return nil, ""
+	}
+	current := m.composeRuntimeOptions()
+	if current == nil {
+		current = &types.SessionRuntimeOptions{}
+	}
+	var values []string
+	selected := ""
+	switch target {
+	case composeOptionModel:
+		values = append(values, catalog.Models...)
+		selected = current.Model
+	case composeOptionReasoning:
+		for _, level := range m.modelReasoningLevels(provider, current.Model) {
+			values = append(values, string(level))
+		}
+		selected = string(current.Reasoning)
+	case composeOptionAccess:
+		for _, level := range catalog.AccessLevels {
+			values = append(values, string(level))
+		}
+		selected = string(current.Access)
+	}
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			out = append(out, value)
+		}
+	}
+	return out, strings.TrimSpace(selected)
+}
+
+// applyComposeRuntimeOption sets one runtime option for the session being
+// composed, or for the pending new session, and remembers it as the
+// provider default.
+func (m *Model) applyComposeRuntimeOption(target composeOptionKind, value string) tea.Cmd {
+	options := m.composeRuntimeOptions()
+	if options == nil {
+		return nil
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	switch target {
+	case composeOptionModel:
+		options.Model = value
+		m.normalizeComposeRuntimeOptionsForModel(m.composeProvider(), options)
+	case composeOptionReasoning:
+		options.Reasoning = types.ReasoningLevel(value)
+	case composeOptionAccess:
+		options.Access = types.AccessLevel(value)
+	}
+	provider := m.composeProvider()
+	m.setComposeDefaultForProvider(provider, options)
+	saveDefaults := m.requestAppStateSaveCmd()
+	if m.newSession != nil {
+		m.newSession.runtimeOptions = types.CloneRuntimeOptions(options)
+		m.setStatusMessage("session options updated")
+		return saveDefaults
+	}
+	sessionID := strings.TrimSpace(m.composeSessionID())
+	if sessionID == "" {
+		if saveDefaults != nil {
+			return saveDefaults
+		}
+		return nil
+	}
+	m.setSessionRuntimeOptionsLocal(sessionID, options)
+	update := updateSessionRuntimeCmd(m.sessionAPI, sessionID, options)
+	m.setStatusMessage("updating session options")
+	if saveDefaults != nil {
+		return tea.Batch(update, saveDefaults)
+	}
+	return update
+}
+
 func (m *Model) composeDefaultsForProvider(provider string) *types.SessionRuntimeOptions {
 	provider = strings.ToLower(strings.TrimSpace(provider))
 	if provider == "" || m.appState.ComposeDefaultsByProvider == nil {
@@ -224,7 +306,7 @@ func (m *Model) composeControlsRow() int {
 	if row, ok := layout.FooterStartRow(); ok {
 		return start + row
 	}
-	return start + layout.InputLineCount()
+	return start + layout.InputStartRow() + layout.InputLineCount()
 }
 
 func (m *Model) guidedWorkflowSetupInputPanelLayout() (InputPanelLayout, bool) {
@@ -0,0 +1,555 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"control/internal/guidedworkflows"
+	"control/internal/types"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// composeSlashCommand is an Archon action typed in compose as "/name args".
+// Anything else that looks like a slash command is sent to the provider.
+type composeSlashCommand struct {
+	name    string
+	usage   string
+	summary string
+	// runOnPick submits the command as soon as it, or its argument, is
+	// picked with enter in the autocomplete popup.
+	runOnPick bool
+	arguments func(m *Model) []selectOption
+	run       func(m *Model, args string) (tea.Cmd, bool)
+}
+
+var composeSlashCommandList = []composeSlashCommand{
+	{name: "model", usage: "<model>", summary: "switch model", runOnPick: true,
+		arguments: composeRuntimeOptionArguments(composeOptionModel), run: composeRuntimeOptionCommand(composeOptionModel)},
+	{name: "reasoning", usage: "<level>", summary: "set reasoning effort", runOnPick: true,
+		arguments: composeRuntimeOptionArguments(composeOptionReasoning), run: composeRuntimeOptionCommand(composeOptionReasoning)},
+	{name: "access", usage: "<level>", summary: "set access level", runOnPick: true,
+		arguments: composeRuntimeOptionArguments(composeOptionAccess), run: composeRuntimeOptionCommand(composeOptionAccess)},
+	{name: "interrupt", summary: "interrupt the running turn", runOnPick: true, run: runComposeInterruptCommand},
+	{name: "fork", usage: "[instruction]", summary: "continue in a new session", run: runComposeForkCommand},
+	{name: "title", usage: "<title>", summary: "rename the session", run: runComposeTitleCommand},
+	{name: "pin", summary: "pin the latest reply as a note", runOnPick: true, run: runComposePinCommand},
+	{name: "note", usage: "<text>", summary: "add a session note", run: runComposeNoteCommand},
+	{name: "workflow", usage: "<template> [prompt]", summary: "start a guided workflow",
+		arguments: composeWorkflowTemplateArguments, run: runComposeWorkflowCommand},
+	{name: "snip", usage: "[name]", summary: "insert a prompt snippet", run: runComposeSnipCommand},
+}
+
+func lookupComposeSlashCommand(name string) (composeSlashCommand, bool) {
+	for _, command := range composeSlashCommandList {
+		if command.name == name {
+			return command, true
+		}
+	}
+	return composeSlashCommand{}, false
+}
+
+func isComposeSlashName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if unicode.IsLetter(r) || (i > 0 && (unicode.IsDigit(r) || r == '-' || r == '_' || r == ':')) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// parseComposeSlashInput splits "/name args" into its parts. Paths such as
+// "/usr/bin" and text starting with "//" are not commands.
+func parseComposeSlashInput(text string) (string, string, bool) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "/") {
+		return "", "", false
+	}
+	name, args := text[1:], ""
+	if i := strings.IndexFunc(name, unicode.IsSpace); i >= 0 {
+		name, args = name[:i], name[i:]
+	}
+	if !isComposeSlashName(name) {
+		return "", "", false
+	}
+	return name, strings.TrimSpace(args), true
+}
+
+// interceptComposeSlashCommand runs Archon slash commands typed in compose.
+// It returns the text to send when the input is not an Archon command; a
+// leading "//" escapes a command name so it reaches the provider verbatim.
+func (m *Model) interceptComposeSlashCommand(text string) (string, bool, tea.Cmd) {
+	trimmed := strings.TrimSpace(text)
+	if strings.HasPrefix(trimmed, "//") {
+		if name, _, ok := parseComposeSlashInput(trimmed[1:]); ok && name != "" {
+			return trimmed[1:], false, nil
+		}
+		return text, false, nil
+	}
+	name, args, ok := parseComposeSlashInput(trimmed)
+	if !ok {
+		return text, false, nil
+	}
+	command, known := lookupComposeSlashCommand(name)
+	if !known {
+		return text, false, nil
+	}
+	cmd, done := command.run(m, args)
+	if done && m.chatInput != nil {
+		m.chatInput.Clear()
+	}
+	return text, true, cmd
+}
+
+// composeSlashPassthroughStatus describes a slash command passed through
+// to a provider that interprets its own slash commands.
+func composeSlashPassthroughStatus(provider, text string) string {
+	name, _, ok := parseComposeSlashInput(text)
+	if !ok || !types.Capabilities(provider).SupportsSlashCommands {
+		return ""
+	}
+	return "sending /" + name + " to " + provider
+}
+
+func composeRuntimeOptionArguments(target composeOptionKind) func(m *Model) []selectOption {
+	return func(m *Model) []selectOption {
+		values, current := m.composeRuntimeOptionValues(m.composeProvider(), target)
+		options := make([]selectOption, 0, len(values))
+		for _, value := range values {
+			label := value
+			if value == current {
+				label += " (current)"
+			}
+			options = append(options, selectOption{id: value, label: label, search: value})
+		}
+		return options
+	}
+}
+
+func composeRuntimeOptionCommand(target composeOptionKind) func(m *Model, args string) (tea.Cmd, bool) {
+	return func(m *Model, args string) (tea.Cmd, bool) {
+		label := composeOptionLabel(target)
+		if m.composeProvider() == "" {
+			m.setValidationStatus("select a session to change its " + label)
+			return nil, false
+		}
+		if args == "" {
+			return m.requestComposeOptionPicker(target), true
+		}
+		values, _ := m.composeRuntimeOptionValues(m.composeProvider(), target)
+		if len(values) > 0 && !slices.Contains(values, args) {
+			m.setValidationStatus("unknown " + label + " " + args + ": use one of " + strings.Join(values, ", "))
+			return nil, false
+		}
+		return m.applyComposeRuntimeOption(target, args), true
+	}
+}
+
+func runComposeInterruptCommand(m *Model, _ string) (tea.Cmd, bool) {
+	cmd := m.requestComposeInterruptCmd()
+	return cmd, cmd != nil
+}
+
+func (m *Model) composeSlashSessionID(command string) string {
+	if m.newSession != nil {
+		m.setValidationStatus("/" + command + " needs a started session")
+		return ""
+	}
+	sessionID := strings.TrimSpace(m.composeSessionID())
+	if sessionID == "" {
+		m.setValidationStatus("select a session to use /" + command)
+	}
+	return sessionID
+}
+
+func runComposeForkCommand(m *Model, args string) (tea.Cmd, bool) {
+	sessionID := m.composeSlashSessionID("fork")
+	if sessionID == "" {
+		return nil, false
+	}
+	provider := m.providerForSessionID(sessionID)
+	m.clearComposeDraft(sessionID)
+	m.setStatusMessage("forking session")
+	ctx := m.replaceRequestScope(requestScopeSessionStart)
+	return forkSessionCmd(m.forkAPI, sessionID, provider, args, ctx), true
+}
+
+func runComposeTitleCommand(m *Model, args string) (tea.Cmd, bool) {
+	sessionID := m.composeSlashSessionID("title")
+	if sessionID == "" {
+		return nil, false
+	}
+	if args == "" {
+		m.setValidationStatus("usage: /title <title>")
+		return nil, false
+	}
+	m.setStatusMessage("renaming session")
+	return updateSessionCmd(m.sessionAPI, sessionID, args), true
+}
+
+func runComposePinCommand(m *Model, _ string) (tea.Cmd, bool) {
+	if m.composeSlashSessionID("pin") == "" {
+		return nil, false
+	}
+	for i := len(m.contentBlocks) - 1; i >= 0; i-- {
+		if m.contentBlocks[i].Role == ChatRoleAgent && strings.TrimSpace(m.contentBlocks[i].Text) != "" {
+			return m.pinBlockByIndex(i), true
+		}
+	}
+	m.setValidationStatus("no reply to pin yet")
+	return nil, false
+}
+
+func runComposeNoteCommand(m *Model, args string) (tea.Cmd, bool) {
+	sessionID := m.composeSlashSessionID("note")
+	if sessionID == "" {
+		return nil, false
+	}
+	if args == "" {
+		m.setValidationStatus("usage: /note <text>")
+		return nil, false
+	}
+	if m.notesAPI == nil {
+		m.setValidationStatus("notes are unavailable")
+		return nil, false
+	}
+	m.setStatusMessage("saving note")
+	return createNoteCmd(m.notesAPI, m.noteScopeForSession(sessionID, "", ""), args), true
+}
+
+func runComposeWorkflowCommand(m *Model, args string) (tea.Cmd, bool) {
+	sessionID := m.composeSlashSessionID("workflow")
+	if sessionID == "" {
+		return nil, false
+	}
+	templateID, prompt, _ := strings.Cut(args, " ")
+	if templateID == "" {
+		m.setValidationStatus("usage: /workflow <template> [prompt]")
+		return nil, false
+	}
+	if templates := m.composeSlash.templates; templates != nil && !slices.ContainsFunc(templates, func(template guidedworkflows.WorkflowTemplate) bool {
+		return template.ID == templateID
+	}) {
+		m.setValidationStatus("unknown workflow template " + templateID)
+		return nil, false
+	}
+	launch := guidedWorkflowLaunchContext{
+		sessionID:  sessionID,
+		templateID: templateID,
+		userPrompt: strings.TrimSpace(prompt),
+	}
+	if meta := m.sessionMeta[sessionID]; meta != nil {
+		launch.workspaceID = meta.WorkspaceID
+		launch.worktreeID = meta.WorktreeID
+	}
+	if m.chatInput != nil {
+		m.chatInput.Clear()
+	}
+	m.clearComposeDraft(sessionID)
+	m.exitCompose("")
+	return m.startGuidedWorkflowWithContext(launch), true
+}
+
+func runComposeSnipCommand(m *Model, _ string) (tea.Cmd, bool) {
+	m.setValidationStatus("pick a snippet from the /snip popup")
+	return nil, false
+}
+
+func composeWorkflowTemplateArguments(m *Model) []selectOption {
+	options := make([]selectOption, 0, len(m.composeSlash.templates))
+	for _, template := range m.composeSlash.templates {
+		label := template.ID
+		if name := strings.TrimSpace(template.Name); name != "" && name != template.ID {
+			label += " · " + name
+		}
+		options = append(options, selectOption{id: template.ID, label: label, search: template.ID + " " + template.Name})
+	}
+	return options
+}
+
+// composeSlashFragment is the part of a leading slash command being
+// completed: the command name, or the first argument of Command.
+type composeSlashFragment struct {
+	Start   int
+	End     int
+	Query   string
+	Command string
+}
+
+// activeComposeSlashFragment finds the command name or first argument under
+// the cursor when the input starts with "/".
+func activeComposeSlashFragment(value string, cursor int) (composeSlashFragment, bool) {
+	runes := []rune(value)
+	cursor = clamp(cursor, 0, len(runes))
+	if cursor == 0 || runes[0] != '/' {
+		return composeSlashFragment{}, false
+	}
+	before := string(runes[1:cursor])
+	if strings.HasPrefix(before, "/") || strings.ContainsAny(before, "\n\t") {
+		return composeSlashFragment{}, false
+	}
+	end := cursor
+	for end < len(runes) && !unicode.IsSpace(runes[end]) {
+		end++
+	}
+	name, arg, hasArg := strings.Cut(before, " ")
+	if !hasArg {
+		if name != "" && !isComposeSlashName(name) {
+			return composeSlashFragment{}, false
+		}
+		return composeSlashFragment{Start: 0, End: end, Query: name}, true
+	}
+	if !isComposeSlashName(name) || strings.Contains(arg, " ") {
+		return composeSlashFragment{}, false
+	}
+	start := 1 + len([]rune(name)) + 1
+	return composeSlashFragment{Start: start, End: end, Query: arg, Command: name}, true
+}
+
+// ComposeSlashController is the autocomplete popup for compose slash
+// commands and their arguments.
+type ComposeSlashController struct {
+	picker           *SelectPicker
+	fragment         composeSlashFragment
+	open             bool
+	templates        []guidedworkflows.WorkflowTemplate
+	templatesLoading bool
+}
+
+func NewComposeSlashController(width, height int) *ComposeSlashController {
+	return &ComposeSlashController{picker: NewSelectPicker(width, height)}
+}
+
+func (c *ComposeSlashController) SetSize(width, height int) {
+	if c == nil || c.picker == nil {
+		return
+	}
+	c.picker.SetSize(width, height)
+}
+
+func (c *ComposeSlashController) Open() bool {
+	return c != nil && c.open
+}
+
+func (c *ComposeSlashController) Close() {
+	if c == nil {
+		return
+	}
+	c.open = false
+	c.fragment = composeSlashFragment{}
+	if c.picker != nil {
+		c.picker.SetOptions(nil)
+	}
+}
+
+func (c *ComposeSlashController) show(fragment composeSlashFragment, options []selectOption) {
+	query := strings.ToLower(fragment.Query)
+	filtered := make([]selectOption, 0, len(options))
+	for _, option := range options {
+		search := option.search
+		if search == "" {
+			search = option.id
+		}
+		if query == "" || strings.Contains(strings.ToLower(search), query) {
+			filtered = append(filtered, option)
+		}
+	}
+	if len(filtered) == 0 {
+		c.Close()
+		return
+	}
+	c.fragment = fragment
+	c.open = true
+	c.picker.SetQuery("")
+	c.picker.SetOptions(filtered)
+}
+
+func (c *ComposeSlashController) Move(delta int) {
+	if c == nil || c.picker == nil || !c.open {
+		return
+	}
+	c.picker.Move(delta)
+}
+
+func (c *ComposeSlashController) HandleClick(row int) bool {
+	if c == nil || c.picker == nil || !c.open {
+		return false
+	}
+	return c.picker.HandleClick(row)
+}
+
+func (c *ComposeSlashController) SelectedID() string {
+	if c == nil || c.picker == nil || !c.open {
+		return ""
+	}
+	return c.picker.SelectedID()
+}
+
+func (c *ComposeSlashController) View() string {
+	if c == nil || c.picker == nil || !c.open {
+		return ""
+	}
+	return c.picker.View()
+}
+
+func composeSlashCommandOptions() []selectOption {
+	options := make([]selectOption, 0, len(composeSlashCommandList))
+	for _, command := range composeSlashCommandList {
+		label := "/" + command.name
+		if command.usage != "" {
+			label += " " + command.usage
+		}
+		label += " · " + command.summary
+		options = append(options, selectOption{id: command.name, label: label, search: command.name})
+	}
+	return options
+}
+
+func (m *Model) syncComposeSlashAfterInput() tea.Cmd {
+	controller := m.composeSlash
+	if m == nil || controller == nil {
+		return nil
+	}
+	if m.mode != uiModeCompose || m.chatInput == nil || m.composeOptionPickerOpen() || m.composeSnippet.Open() || m.composeSnippet.Prompting() {
+		controller.Close()
+		return nil
+	}
+	value := m.chatInput.Value()
+	cursor := m.chatInput.CursorRuneIndex()
+	if _, ok := activeComposeSnippetFragment(value, cursor); ok {
+		controller.Close()
+		return nil
+	}
+	fragment, ok := activeComposeSlashFragment(value, cursor)
+	if !ok {
+		controller.Close()
+		return nil
+	}
+	if controller.Open() && controller.fragment == fragment {
+		return nil
+	}
+	if fragment.Command == "" {
+		controller.show(fragment, composeSlashCommandOptions())
+		return nil
+	}
+	command, known := lookupComposeSlashCommand(fragment.Command)
+	if !known || command.arguments == nil {
+		controller.Close()
+		return nil
+	}
+	controller.show(fragment, command.arguments(m))
+	if command.name == "workflow" && controller.templates == nil && !controller.templatesLoading {
+		controller.templatesLoading = true
+		return fetchComposeSlashTemplatesCmd(m.guidedWorkflowTemplateAPI)
+	}
+	return nil
+}
+
+func fetchComposeSlashTemplatesCmd(api GuidedWorkflowTemplateAPI) tea.Cmd {
+	return func() tea.Msg {
+		if api == nil {
+			return composeSlashTemplatesMsg{err: errors.New("guided workflow api is unavailable")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		templates, err := api.ListWorkflowTemplates(ctx)
+		return composeSlashTemplatesMsg{templates: templates, err: err}
+	}
+}
+
+func (m *Model) applyComposeSlashTemplates(msg composeSlashTemplatesMsg) {
+	controller := m.composeSlash
+	if controller == nil {
+		return
+	}
+	controller.templatesLoading = false
+	if msg.err != nil {
+		m.setStatusWarning("workflow templates unavailable: " + msg.err.Error())
+		return
+	}
+	controller.templates = msg.templates
+	if controller.templates == nil {
+		controller.templates = []guidedworkflows.WorkflowTemplate{}
+	}
+	if controller.Open() && controller.fragment.Command == "workflow" {
+		controller.show(controller.fragment, composeWorkflowTemplateArguments(m))
+	}
+}
+
+// applyComposeSlashSelection completes the picked command or argument. With
+// submit set, commands that need nothing more are run right away.
+func (m *Model) applyComposeSlashSelection(submit bool) tea.Cmd {
+	controller := m.composeSlash
+	if controller == nil || m.chatInput == nil {
+		return nil
+	}
+	id := controller.SelectedID()
+	fragment := controller.fragment
+	controller.Close()
+	if id == "" {
+		return nil
+	}
+	replacement := id + " "
+	commandName := fragment.Command
+	if commandName == "" {
+		replacement = "/" + replacement
+		commandName = id
+	}
+	if !m.chatInput.ReplaceRuneRange(fragment.Start, fragment.End, replacement) {
+		return nil
+	}
+	command, _ := lookupComposeSlashCommand(commandName)
+	if !submit || !command.runOnPick || (fragment.Command == "" && command.arguments != nil) {
+		return m.syncComposeSlashAfterInput()
+	}
+	return m.submitComposeInput(m.chatInput.Value())
+}
+
+func (m *Model) composeSlashPopupPlacement() (string, int, int) {
+	controller := m.composeSlash
+	if m == nil || controller == nil || !controller.Open() {
+		return "", 0, 0
+	}
+	view := controller.View()
+	if strings.TrimSpace(view) == "" {
+		return "", 0, 0
+	}
+	height := len(strings.Split(view, "\n"))
+	row := m.composeControlsRow() - height
+	if row < 1 {
+		row = 1
+	}
+	return view, m.resolveMouseLayout().rightStart, row
+}
+
+func (m *Model) handleComposeSlashKey(key string) (bool, tea.Cmd) {
+	controller := m.composeSlash
+	if controller == nil || !controller.Open() {
+		return false, nil
+	}
+	switch key {
+	case "esc":
+		controller.Close()
+		return true, nil
+	case "tab":
+		return true, m.applyComposeSlashSelection(false)
+	case "enter":
+		return true, m.applyComposeSlashSelection(true)
+	case "up":
+		controller.Move(-1)
+		return true, nil
+	case "down":
+		controller.Move(1)
+		return true, nil
+	default:
+		return false, nil
+	}
+}
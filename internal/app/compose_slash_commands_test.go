@@ -0,0 +1,194 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"control/internal/client"
+	"control/internal/types"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+type stubSessionForkAPI struct {
+	ids      []string
+	requests []client.ForkSessionRequest
+}
+
+func (s *stubSessionForkAPI) ForkSession(_ context.Context, id string, req client.ForkSessionRequest) (*types.Session, error) {
+	s.ids = append(s.ids, id)
+	s.requests = append(s.requests, req)
+	return &types.Session{ID: "s2", Provider: "codex", Status: types.SessionStatusRunning}, nil
+}
+
+func TestParseComposeSlashInput(t *testing.T) {
+	cases := []struct {
+		text string
+		ok   bool
+		name string
+		args string
+	}{
+		{text: "/model gpt-5.1-codex", ok: true, name: "model", args: "gpt-5.1-codex"},
+		{text: "  /fork  try the other approach ", ok: true, name: "fork", args: "try the other approach"},
+		{text: "/compact", ok: true, name: "compact"},
+		{text: "/usr/bin/env", ok: false},
+		{text: "//model", ok: false},
+		{text: "fix /model", ok: false},
+	}
+	for _, tc := range cases {
+		name, args, ok := parseComposeSlashInput(tc.text)
+		if ok != tc.ok || name != tc.name || args != tc.args {
+			t.Fatalf("%q: got (%q, %q, %v)", tc.text, name, args, ok)
+		}
+	}
+}
+
+func TestActiveComposeSlashFragment(t *testing.T) {
+	cases := []struct {
+		value   string
+		ok      bool
+		start   int
+		query   string
+		command string
+	}{
+		{value: "/", ok: true},
+		{value: "/mo", ok: true, query: "mo"},
+		{value: "/model gp", ok: true, start: 7, query: "gp", command: "model"},
+		{value: "/workflow fix now", ok: false},
+		{value: "/usr/bin", ok: false},
+		{value: "//model", ok: false},
+		{value: "model", ok: false},
+	}
+	for _, tc := range cases {
+		fragment, ok := activeComposeSlashFragment(tc.value, len([]rune(tc.value)))
+		if ok != tc.ok {
+			t.Fatalf("%q: expected ok=%v, got %v", tc.value, tc.ok, ok)
+		}
+		if ok && (fragment.Start != tc.start || fragment.Query != tc.query || fragment.Command != tc.command) {
+			t.Fatalf("%q: unexpected fragment %#v", tc.value, fragment)
+		}
+	}
+}
+
+func TestComposeSlashModelPopupAppliesPickedModel(t *testing.T) {
+	m := newPhase0ModelWithSession("codex")
+	m.enterCompose("s1")
+	m.chatInput.SetValue("/mod")
+	runModelCmd(t, &m, m.syncComposeSlashAfterInput())
+	if !m.composeSlash.Open() || !strings.Contains(m.composeSlash.View(), "/model") {
+		t.Fatalf("expected command popup with /model, got %q", m.composeSlash.View())
+	}
+
+	pressKey(t, &m, tea.KeyPressMsg{Code: tea.KeyTab})
+	if got := m.chatInput.Value(); got != "/model " {
+		t.Fatalf("expected command completed, got %q", got)
+	}
+	runModelCmd(t, &m, m.syncComposeSlashAfterInput())
+	if view := m.composeSlash.View(); !strings.Contains(view, "gpt-5.4-codex") {
+		t.Fatalf("expected model arguments listed, got %q", view)
+	}
+
+	_, handled, _ := m.interceptComposeSlashCommand("/model gpt-5.4-codex")
+	if !handled {
+		t.Fatalf("expected /model to be handled")
+	}
+	if meta := m.sessionMeta["s1"]; meta == nil || meta.RuntimeOptions == nil || meta.RuntimeOptions.Model != "gpt-5.4-codex" {
+		t.Fatalf("expected session model updated, got %#v", m.sessionMeta["s1"])
+	}
+	if got := m.chatInput.Value(); got != "" {
+		t.Fatalf("expected input cleared, got %q", got)
+	}
+}
+
+func TestComposeSlashModelRejectsUnknownValue(t *testing.T) {
+	m := newPhase0ModelWithSession("codex")
+	m.enterCompose("s1")
+	m.chatInput.SetValue("/model nope")
+
+	_, handled, cmd := m.interceptComposeSlashCommand("/model nope")
+
+	if !handled || cmd != nil {
+		t.Fatalf("expected handled command without action")
+	}
+	if !strings.Contains(m.status, "unknown model nope") {
+		t.Fatalf("expected validation status, got %q", m.status)
+	}
+	if got := m.chatInput.Value(); got != "/model nope" {
+		t.Fatalf("expected input kept for correction, got %q", got)
+	}
+}
+
+func TestComposeSlashForkStartsSessionFromParent(t *testing.T) {
+	m := newPhase0ModelWithSession("codex")
+	api := &stubSessionForkAPI{}
+	m.forkAPI = api
+	m.enterCompose("s1")
+
+	_, handled, cmd := m.interceptComposeSlashCommand("/fork try the other approach")
+	if !handled || cmd == nil {
+		t.Fatalf("expected fork command")
+	}
+	msg, ok := cmd().(startSessionMsg)
+	if !ok || msg.err != nil || msg.session == nil || msg.session.ID != "s2" {
+		t.Fatalf("expected started fork session, got %#v", msg)
+	}
+	if len(api.ids) != 1 || api.ids[0] != "s1" || api.requests[0].Text != "try the other approach" {
+		t.Fatalf("unexpected fork calls %#v %#v", api.ids, api.requests)
+	}
+}
+
+func TestComposeSlashNoteRequiresText(t *testing.T) {
+	m := newPhase0ModelWithSession("codex")
+	m.enterCompose("s1")
+
+	_, handled, cmd := m.interceptComposeSlashCommand("/note")
+
+	if !handled || cmd != nil {
+		t.Fatalf("expected /note without text to be rejected")
+	}
+	if m.status != "usage: /note <text>" {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+}
+
+func TestComposeSlashUnknownCommandPassesThroughToProvider(t *testing.T) {
+	m := newPhase0ModelWithSession("claude")
+	m.enterCompose("s1")
+
+	text, handled, _ := m.interceptComposeSlashCommand("/compact")
+	if handled || text != "/compact" {
+		t.Fatalf("expected /compact to pass through, got %q handled=%v", text, handled)
+	}
+	if status := composeSlashPassthroughStatus("claude", text); status != "sending /compact to claude" {
+		t.Fatalf("unexpected passthrough status %q", status)
+	}
+	if status := composeSlashPassthroughStatus("codex", text); status != "" {
+		t.Fatalf("expected no passthrough status for codex, got %q", status)
+	}
+
+	escaped, handled, _ := m.interceptComposeSlashCommand("//model")
+	if handled || escaped != "/model" {
+		t.Fatalf("expected //model to send /model, got %q handled=%v", escaped, handled)
+	}
+}
+
+func TestComposeSlashWorkflowOpensLauncherWithTemplate(t *testing.T) {
+	m := newPhase0ModelWithSession("codex")
+	m.enterCompose("s1")
+
+	_, handled, _ := m.interceptComposeSlashCommand("/workflow solid_phase_delivery fix the flaky test")
+
+	if !handled {
+		t.Fatalf("expected /workflow to be handled")
+	}
+	if m.mode != uiModeGuidedWorkflow {
+		t.Fatalf("expected guided workflow mode, got %v", m.mode)
+	}
+	if m.guidedWorkflow.templateID != "solid_phase_delivery" {
+		t.Fatalf("expected template preselected, got %q", m.guidedWorkflow.templateID)
+	}
+	if got := m.guidedWorkflowPromptInput.Value(); got != "fix the flaky test" {
+		t.Fatalf("expected prompt prefilled, got %q", got)
+	}
+}
@@ -0,0 +1,388 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"control/internal/types"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+const composeSnippetTrigger = "/snip"
+
+// composeSnippetFragment is a "/snip [query]" span of the compose input.
+type composeSnippetFragment struct {
+	Start int
+	End   int
+	Query string
+}
+
+// composeSnippetPrompt collects {{var}} values for a picked snippet. While
+// it is active the compose input holds the value being typed and the draft
+// is kept aside.
+type composeSnippetPrompt struct {
+	snippet     *types.Snippet
+	fragment    composeSnippetFragment
+	draft       string
+	placeholder string
+	variables   []string
+	values      map[string]string
+}
+
+// ComposeSnippetController is the popup opened by /snip in compose.
+type ComposeSnippetController struct {
+	picker      *SelectPicker
+	fragment    composeSnippetFragment
+	open        bool
+	loading     bool
+	workspaceID string
+	loaded      bool
+	snippets    []*types.Snippet
+	prompt      *composeSnippetPrompt
+}
+
+func NewComposeSnippetController(width, height int) *ComposeSnippetController {
+	return &ComposeSnippetController{picker: NewSelectPicker(width, height)}
+}
+
+func (c *ComposeSnippetController) SetSize(width, height int) {
+	if c == nil || c.picker == nil {
+		return
+	}
+	c.picker.SetSize(width, height)
+}
+
+func (c *ComposeSnippetController) Open() bool {
+	return c != nil && c.open
+}
+
+func (c *ComposeSnippetController) Prompting() bool {
+	return c != nil && c.prompt != nil
+}
+
+func (c *ComposeSnippetController) Close() {
+	if c == nil {
+		return
+	}
+	c.open = false
+	c.loading = false
+	c.fragment = composeSnippetFragment{}
+	if c.picker != nil {
+		c.picker.SetOptions(nil)
+	}
+}
+
+// show opens the popup for fragment. It reports whether snippets for
+// workspaceID still need to be loaded.
+func (c *ComposeSnippetController) show(fragment composeSnippetFragment, workspaceID string) bool {
+	needsLoad := !c.loaded || c.workspaceID != workspaceID
+	c.fragment = fragment
+	c.open = true
+	c.workspaceID = workspaceID
+	c.loading = needsLoad
+	if needsLoad {
+		c.loaded = false
+		c.snippets = nil
+	}
+	c.refresh()
+	return needsLoad
+}
+
+func (c *ComposeSnippetController) setSnippets(workspaceID string, snippets []*types.Snippet) bool {
+	if c == nil || c.workspaceID != workspaceID {
+		return false
+	}
+	c.snippets = snippets
+	c.loaded = true
+	c.loading = false
+	if c.open {
+		c.refresh()
+	}
+	return true
+}
+
+func (c *ComposeSnippetController) refresh() {
+	if c.picker == nil {
+		return
+	}
+	query := strings.ToLower(strings.TrimSpace(c.fragment.Query))
+	options := make([]selectOption, 0, len(c.snippets))
+	for _, snippet := range c.snippets {
+		if snippet == nil {
+			continue
+		}
+		name := types.SnippetLabel(snippet)
+		if query != "" && !strings.Contains(strings.ToLower(name+" "+snippet.Description), query) {
+			continue
+		}
+		label := name
+		if len(snippet.Variables) > 0 {
+			label += " {" + strings.Join(snippet.Variables, ", ") + "}"
+		}
+		if snippet.Source == types.SnippetSourceWorkflow {
+			label += " · workflow"
+		}
+		if description := strings.TrimSpace(snippet.Description); description != "" {
+			label += " · " + description
+		}
+		options = append(options, selectOption{id: snippet.ID, label: label, search: name})
+	}
+	if len(options) == 0 {
+		label := " (no snippets)"
+		if c.loading {
+			label = " (loading...)"
+		}
+		options = append(options, selectOption{label: label})
+	}
+	c.picker.SetQuery("")
+	c.picker.SetOptions(options)
+}
+
+func (c *ComposeSnippetController) Move(delta int) {
+	if c == nil || c.picker == nil || !c.open {
+		return
+	}
+	c.picker.Move(delta)
+}
+
+func (c *ComposeSnippetController) HandleClick(row int) bool {
+	if c == nil || c.picker == nil || !c.open {
+		return false
+	}
+	return c.picker.HandleClick(row)
+}
+
+func (c *ComposeSnippetController) Selected() (*types.Snippet, bool) {
+	if c == nil || c.picker == nil || !c.open {
+		return nil, false
+	}
+	id := c.picker.SelectedID()
+	for _, snippet := range c.snippets {
+		if snippet != nil && snippet.ID == id {
+			return snippet, true
+		}
+	}
+	return nil, false
+}
+
+func (c *ComposeSnippetController) View() string {
+	if c == nil || c.picker == nil || !c.open {
+		return ""
+	}
+	return c.picker.View()
+}
+
+// activeComposeSnippetFragment finds a "/snip" trigger, optionally followed
+// by one space and a name query, ending at cursor.
+func activeComposeSnippetFragment(value string, cursor int) (composeSnippetFragment, bool) {
+	runes := []rune(value)
+	cursor = clamp(cursor, 0, len(runes))
+	wordStart := cursor
+	for wordStart > 0 && !unicode.IsSpace(runes[wordStart-1]) {
+		wordStart--
+	}
+	trigger := []rune(composeSnippetTrigger)
+	start, query := -1, ""
+	if string(runes[wordStart:cursor]) == composeSnippetTrigger {
+		start = wordStart
+	} else if triggerStart := wordStart - len(trigger) - 1; triggerStart >= 0 && runes[wordStart-1] == ' ' &&
+		string(runes[triggerStart:wordStart-1]) == composeSnippetTrigger &&
+		(triggerStart == 0 || unicode.IsSpace(runes[triggerStart-1])) {
+		start = triggerStart
+		query = string(runes[wordStart:cursor])
+	}
+	if start < 0 {
+		return composeSnippetFragment{}, false
+	}
+	end := cursor
+	for end < len(runes) && !unicode.IsSpace(runes[end]) {
+		end++
+	}
+	return composeSnippetFragment{Start: start, End: end, Query: query}, true
+}
+
+func (m *Model) composeWorkspaceID() string {
+	if m.newSession != nil {
+		return strings.TrimSpace(m.newSession.workspaceID)
+	}
+	if meta := m.sessionMeta[m.composeSessionID()]; meta != nil {
+		return strings.TrimSpace(meta.WorkspaceID)
+	}
+	return ""
+}
+
+func (m *Model) syncComposeSnippetAfterInput() tea.Cmd {
+	controller := m.composeSnippet
+	if m == nil || controller == nil || controller.Prompting() {
+		return nil
+	}
+	if m.mode != uiModeCompose || m.chatInput == nil || m.composeOptionPickerOpen() {
+		controller.Close()
+		return nil
+	}
+	fragment, ok := activeComposeSnippetFragment(m.chatInput.Value(), m.chatInput.CursorRuneIndex())
+	if !ok {
+		controller.Close()
+		return nil
+	}
+	if controller.Open() && controller.fragment == fragment {
+		return nil
+	}
+	workspaceID := m.composeWorkspaceID()
+	if !controller.show(fragment, workspaceID) || m.snippetAPI == nil {
+		return nil
+	}
+	api := m.snippetAPI
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
+		defer cancel()
+		snippets, err := api.ListSnippets(ctx, workspaceID)
+		return composeSnippetsLoadedMsg{workspaceID: workspaceID, snippets: snippets, err: err}
+	}
+}
+
+func (m *Model) applyComposeSnippetsLoaded(msg composeSnippetsLoadedMsg) {
+	if msg.err != nil {
+		m.setStatusWarning("snippets unavailable: " + msg.err.Error())
+		return
+	}
+	m.composeSnippet.setSnippets(msg.workspaceID, msg.snippets)
+}
+
+// applyComposeSnippetSelection inserts the picked snippet in place of the
+// /snip fragment, first prompting for its variables when it has any.
+func (m *Model) applyComposeSnippetSelection() {
+	controller := m.composeSnippet
+	if controller == nil || m.chatInput == nil {
+		return
+	}
+	snippet, ok := controller.Selected()
+	fragment := controller.fragment
+	controller.Close()
+	if !ok {
+		return
+	}
+	variables := types.SnippetVariables(snippet.Body)
+	if len(variables) == 0 {
+		m.insertComposeSnippet(fragment, snippet.Body)
+		return
+	}
+	controller.prompt = &composeSnippetPrompt{
+		snippet:     snippet,
+		fragment:    fragment,
+		draft:       m.chatInput.Value(),
+		placeholder: m.chatInput.Placeholder(),
+		variables:   variables,
+		values:      map[string]string{},
+	}
+	m.chatInput.SetValue("")
+	m.promptComposeSnippetVariable()
+}
+
+func (m *Model) promptComposeSnippetVariable() {
+	prompt := m.composeSnippet.prompt
+	name := prompt.variables[len(prompt.values)]
+	m.chatInput.SetPlaceholder("{{" + name + "}}")
+	m.setStatusMessage(fmt.Sprintf("snippet %s: value for {{%s}} (%d/%d) · enter to confirm, esc to cancel",
+		types.SnippetLabel(prompt.snippet), name, len(prompt.values)+1, len(prompt.variables)))
+}
+
+// submitComposeSnippetVariable records the typed value and either prompts
+// for the next variable or inserts the rendered snippet.
+func (m *Model) submitComposeSnippetVariable() {
+	prompt := m.composeSnippet.prompt
+	prompt.values[prompt.variables[len(prompt.values)]] = m.chatInput.Value()
+	if len(prompt.values) < len(prompt.variables) {
+		m.chatInput.SetValue("")
+		m.promptComposeSnippetVariable()
+		return
+	}
+	rendered, _ := types.RenderSnippet(prompt.snippet.Body, prompt.values)
+	m.restoreComposeSnippetDraft()
+	m.insertComposeSnippet(prompt.fragment, rendered)
+}
+
+func (m *Model) cancelComposeSnippetPrompt() bool {
+	if m.composeSnippet == nil || !m.composeSnippet.Prompting() {
+		return false
+	}
+	m.restoreComposeSnippetDraft()
+	m.setStatusMessage("snippet canceled")
+	return true
+}
+
+func (m *Model) restoreComposeSnippetDraft() {
+	prompt := m.composeSnippet.prompt
+	m.composeSnippet.prompt = nil
+	if m.chatInput == nil {
+		return
+	}
+	m.chatInput.SetPlaceholder(prompt.placeholder)
+	m.chatInput.SetValue(prompt.draft)
+}
+
+func (m *Model) insertComposeSnippet(fragment composeSnippetFragment, text string) {
+	if !m.chatInput.ReplaceRuneRange(fragment.Start, fragment.End, text) {
+		m.setStatusWarning("snippet could not be inserted")
+		return
+	}
+	m.setStatusMessage("snippet inserted")
+}
+
+func (m *Model) composeSnippetPopupPlacement() (string, int, int) {
+	controller := m.composeSnippet
+	if m == nil || controller == nil || !controller.Open() {
+		return "", 0, 0
+	}
+	view := controller.View()
+	if strings.TrimSpace(view) == "" {
+		return "", 0, 0
+	}
+	height := len(strings.Split(view, "\n"))
+	row := m.composeControlsRow() - height
+	if row < 1 {
+		row = 1
+	}
+	return view, m.resolveMouseLayout().rightStart, row
+}
+
+func (m *Model) handleComposeSnippetKey(key string) (bool, tea.Cmd) {
+	controller := m.composeSnippet
+	if controller == nil {
+		return false, nil
+	}
+	if controller.Prompting() {
+		switch key {
+		case "enter":
+			m.submitComposeSnippetVariable()
+			return true, nil
+		case "esc":
+			m.cancelComposeSnippetPrompt()
+			return true, nil
+		}
+		return false, nil
+	}
+	if !controller.Open() {
+		return false, nil
+	}
+	switch key {
+	case "esc":
+		controller.Close()
+		return true, nil
+	case "tab", "enter":
+		m.applyComposeSnippetSelection()
+		return true, nil
+	case "up":
+		controller.Move(-1)
+		return true, nil
+	case "down":
+		controller.Move(1)
+		return true, nil
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,136 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"control/internal/types"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+type stubSnippetListAPI struct {
+	snippets   []*types.Snippet
+	workspaces []string
+}
+
+func (s *stubSnippetListAPI) ListSnippets(_ context.Context, workspaceID string) ([]*types.Snippet, error) {
+	s.workspaces = append(s.workspaces, workspaceID)
+	return s.snippets, nil
+}
+
+func newComposeSnippetTestModel() (Model, *stubSnippetListAPI) {
+	m := newPhase0ModelWithSession("codex")
+	api := &stubSnippetListAPI{snippets: []*types.Snippet{
+		{ID: "snip_1", Name: "standup", Body: "Summarize today's changes."},
+		{ID: "snip_2", Name: "review", Body: "Review {{file}} for {{concern}}.", Variables: []string{"file", "concern"}},
+	}}
+	m.snippetAPI = api
+	return m, api
+}
+
+func TestActiveComposeSnippetFragment(t *testing.T) {
+	cases := []struct {
+		value string
+		ok    bool
+		start int
+		query string
+	}{
+		{value: "/snip", ok: true, start: 0},
+		{value: "please /snip rev", ok: true, start: 7, query: "rev"},
+		{value: "please /snip ", ok: true, start: 7},
+		{value: "path/snip", ok: false},
+		{value: "/snip review now", ok: false},
+		{value: "/snippet", ok: false},
+	}
+	for _, tc := range cases {
+		fragment, ok := activeComposeSnippetFragment(tc.value, len([]rune(tc.value)))
+		if ok != tc.ok {
+			t.Fatalf("%q: expected ok=%v, got %v", tc.value, tc.ok, ok)
+		}
+		if ok && (fragment.Start != tc.start || fragment.Query != tc.query) {
+			t.Fatalf("%q: unexpected fragment %#v", tc.value, fragment)
+		}
+	}
+}
+
+func TestComposeSnippetWithoutVariablesReplacesTrigger(t *testing.T) {
+	m, api := newComposeSnippetTestModel()
+	m.enterCompose("s1")
+	m.chatInput.SetValue("first /snip stand")
+	runModelCmd(t, &m, m.syncComposeSnippetAfterInput())
+
+	if !m.composeSnippet.Open() {
+		t.Fatalf("expected snippet popup to open")
+	}
+	if len(api.workspaces) != 1 || api.workspaces[0] != "ws1" {
+		t.Fatalf("expected snippets loaded for the session workspace, got %#v", api.workspaces)
+	}
+	if view := m.composeSnippet.View(); !strings.Contains(view, "standup") || strings.Contains(view, "review") {
+		t.Fatalf("expected popup filtered by query, got %q", view)
+	}
+
+	pressKey(t, &m, tea.KeyPressMsg{Code: tea.KeyTab})
+
+	if got := m.chatInput.Value(); got != "first Summarize today's changes." {
+		t.Fatalf("expected snippet body inserted, got %q", got)
+	}
+	if m.composeSnippet.Open() {
+		t.Fatalf("expected popup closed after insertion")
+	}
+}
+
+func TestComposeSnippetPromptsForVariables(t *testing.T) {
+	m, _ := newComposeSnippetTestModel()
+	m.enterCompose("s1")
+	m.chatInput.SetValue("/snip rev")
+	runModelCmd(t, &m, m.syncComposeSnippetAfterInput())
+	pressKey(t, &m, tea.KeyPressMsg{Code: tea.KeyEnter})
+
+	if !m.composeSnippet.Prompting() || m.chatInput.Value() != "" {
+		t.Fatalf("expected variable prompt with empty input, got %q", m.chatInput.Value())
+	}
+	if placeholder := m.chatInput.Placeholder(); placeholder != "{{file}}" {
+		t.Fatalf("expected prompt for file, got %q", placeholder)
+	}
+	m.chatInput.SetValue("api.go")
+	pressKey(t, &m, tea.KeyPressMsg{Code: tea.KeyEnter})
+	if placeholder := m.chatInput.Placeholder(); placeholder != "{{concern}}" {
+		t.Fatalf("expected prompt for concern, got %q", placeholder)
+	}
+	m.chatInput.SetValue("races")
+	pressKey(t, &m, tea.KeyPressMsg{Code: tea.KeyEnter})
+
+	if m.composeSnippet.Prompting() {
+		t.Fatalf("expected prompt finished")
+	}
+	if got := m.chatInput.Value(); got != "Review api.go for races." {
+		t.Fatalf("expected rendered snippet, got %q", got)
+	}
+}
+
+func TestComposeSnippetPromptEscRestoresDraft(t *testing.T) {
+	m, _ := newComposeSnippetTestModel()
+	m.enterCompose("s1")
+	placeholder := m.chatInput.Placeholder()
+	m.chatInput.SetValue("draft /snip review")
+	runModelCmd(t, &m, m.syncComposeSnippetAfterInput())
+	pressKey(t, &m, tea.KeyPressMsg{Code: tea.KeyTab})
+	m.chatInput.SetValue("half typed")
+
+	pressKey(t, &m, tea.KeyPressMsg{Code: tea.KeyEscape})
+
+	if m.composeSnippet.Prompting() {
+		t.Fatalf("expected prompt canceled")
+	}
+	if got := m.chatInput.Value(); got != "draft /snip review" {
+		t.Fatalf("expected draft restored, got %q", got)
+	}
+	if got := m.chatInput.Placeholder(); got != placeholder {
+		t.Fatalf("expected placeholder restored, got %q", got)
+	}
+	if m.mode != uiModeCompose {
+		t.Fatalf("expected esc to stay in compose, got mode %v", m.mode)
+	}
+}
@@ -20,6 +20,8 @@ const (
 	ContextMenuWorkspaceOpenNotes
 	ContextMenuWorkspaceAddNote
 	ContextMenuWorkspaceAddWorktree
+	ContextMenuWorkspaceToggleIsolation
+	ContextMenuWorkspaceToggleIsolatedCleanup
 	ContextMenuWorkspaceStartGuidedWorkflow
 	ContextMenuWorkspaceCopyPath
 	ContextMenuWorkspaceDelete
@@ -28,6 +30,10 @@ const (
 	ContextMenuWorktreeAddNote
 	ContextMenuWorktreeStartGuidedWorkflow
 	ContextMenuWorktreeCopyPath
+	ContextMenuWorktreeMerge
+	ContextMenuWorktreeRebase
+	ContextMenuWorktreePush
+	ContextMenuWorktreeRemove
 	ContextMenuWorktreeDelete
 	ContextMenuSessionChat
 	ContextMenuSessionRename
@@ -38,6 +44,9 @@ const (
 	ContextMenuSessionKill
 	ContextMenuSessionInterrupt
 	ContextMenuSessionCopyID
+	ContextMenuSessionFinalize
+	ContextMenuSessionExportTranscript
+	ContextMenuSessionCopyTranscript
 	ContextMenuWorkflowOpen
 	ContextMenuWorkflowCreateFollowUp
 	ContextMenuWorkflowRename
@@ -45,6 +54,7 @@ const (
 	ContextMenuWorkflowDismiss
 	ContextMenuWorkflowUndismiss
 	ContextMenuWorkflowCopyID
+	ContextMenuWorkflowFinalize
 )
 
 type contextMenuItem struct {
@@ -190,6 +200,8 @@ func (c *ContextMenuController) openWorkspace(id, label, copyLabel string, x, y
 		{Label: "Open Notes", Action: ContextMenuWorkspaceOpenNotes},
 		{Label: "Add Note", Action: ContextMenuWorkspaceAddNote},
 		{Label: "Add Worktree", Action: ContextMenuWorkspaceAddWorktree},
+		{Label: "Toggle Session Isolation", Action: ContextMenuWorkspaceToggleIsolation},
+		{Label: "Toggle Isolated Worktree Cleanup", Action: ContextMenuWorkspaceToggleIsolatedCleanup},
 		{Label: "Start Guided Workflow", Action: ContextMenuWorkspaceStartGuidedWorkflow},
 		{Label: copyLabel, Action: ContextMenuWorkspaceCopyPath},
 		{Label: "Delete Workspace", Action: ContextMenuWorkspaceDelete},
@@ -219,6 +231,10 @@ func (c *ContextMenuController) OpenWorktree(worktreeID, workspaceID, label stri
 		{Label: "Add Note", Action: ContextMenuWorktreeAddNote},
 		{Label: "Start Guided Workflow", Action: ContextMenuWorktreeStartGuidedWorkflow},
 		{Label: "Copy Worktree Path", Action: ContextMenuWorktreeCopyPath},
+		{Label: "Merge into Main Branch", Action: ContextMenuWorktreeMerge},
+		{Label: "Rebase onto Main Branch", Action: ContextMenuWorktreeRebase},
+		{Label: "Push Branch", Action: ContextMenuWorktreePush},
+		{Label: "Remove Worktree from Disk", Action: ContextMenuWorktreeRemove},
 		{Label: "Delete Worktree", Action: ContextMenuWorktreeDelete},
 	}
 	c.selected = 0
@@ -257,6 +273,9 @@ func (c *ContextMenuController) openSession(sessionID, workspaceID, worktreeID,
 		{Label: "Dismiss Session", Action: ContextMenuSessionDismiss},
 		{Label: "Kill Session", Action: ContextMenuSessionKill},
 		{Label: "Interrupt Session", Action: ContextMenuSessionInterrupt},
+		{Label: "Commit Changes", Action: ContextMenuSessionFinalize},
+		{Label: "Export Transcript", Action: ContextMenuSessionExportTranscript},
+		{Label: "Copy Transcript", Action: ContextMenuSessionCopyTranscript},
 		{Label: copyLabel, Action: ContextMenuSessionCopyID},
 	}
 	c.selected = 0
@@ -321,6 +340,7 @@ func (c *ContextMenuController) openWorkflow(
 	c.items = append(c.items,
 		contextMenuItem{Label: "Rename Workflow", Action: ContextMenuWorkflowRename},
 		contextMenuItem{Label: "Stop Workflow", Action: ContextMenuWorkflowStop},
+		contextMenuItem{Label: "Commit Changes", Action: ContextMenuWorkflowFinalize},
 		visibilityAction,
 		contextMenuItem{Label: copyLabel, Action: ContextMenuWorkflowCopyID},
 	)
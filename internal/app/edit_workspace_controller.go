@@ -25,7 +25,13 @@ type EditWorkspaceController struct {
 	sub         string
 	dirs        string
 	name        string
-	groupIDs    []string
+	envProfiles string
+	envProfile  string
+	// instructions keeps the workspace's text with its line breaks; the
+	// input only shows a single-line rendering of it.
+	instructions string
+	contextFiles string
+	groupIDs     []string
 }
 
 func NewEditWorkspaceController(width int) *EditWorkspaceController {
@@ -51,6 +57,10 @@ func (c *EditWorkspaceController) Enter(workspaceID string, workspace *types.Wor
 	c.sub = ""
 	c.dirs = ""
 	c.name = ""
+	c.envProfiles = ""
+	c.envProfile = ""
+	c.instructions = ""
+	c.contextFiles = ""
 	c.groupIDs = nil
 	if c.workspaceID == "" {
 		return false
@@ -71,6 +81,10 @@ func (c *EditWorkspaceController) Enter(workspaceID string, workspace *types.Wor
 		c.dirs = strings.Join(workspace.AdditionalDirectories, ", ")
 	}
 	c.name = strings.TrimSpace(workspace.Name)
+	c.envProfiles = types.FormatEnvProfiles(workspace.EnvProfiles)
+	c.envProfile = strings.TrimSpace(workspace.EnvProfile)
+	c.instructions = strings.TrimSpace(workspace.Instructions)
+	c.contextFiles = strings.Join(workspace.ContextFiles, ", ")
 	c.groupIDs = append([]string(nil), workspace.GroupIDs...)
 	c.prepareInput()
 	if c.input != nil {
@@ -86,6 +100,10 @@ func (c *EditWorkspaceController) Exit() {
 	c.sub = ""
 	c.dirs = ""
 	c.name = ""
+	c.envProfiles = ""
+	c.envProfile = ""
+	c.instructions = ""
+	c.contextFiles = ""
 	c.groupIDs = nil
 	if c.input != nil {
 		c.input.SetValue("")
@@ -98,7 +116,7 @@ func (c *EditWorkspaceController) Exit() {
 }
 
 func (c *EditWorkspaceController) Update(msg tea.Msg, host editWorkspaceHost) (bool, tea.Cmd) {
-	if c.step == 4 {
+	if c.step == 8 {
 		return c.updateGroupPickerStep(msg, host)
 	}
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
@@ -144,8 +162,12 @@ func (c *EditWorkspaceController) View() string {
 		renderAddField(c.input, c.step, "Session Subpath", c.sub, 1),
 		renderAddField(c.input, c.step, "Additional Dirs", c.dirs, 2),
 		renderAddField(c.input, c.step, "Name", c.name, 3),
+		renderAddField(c.input, c.step, "Env Profiles", c.envProfiles, 4),
+		renderAddField(c.input, c.step, "Env Profile", c.envProfile, 5),
+		renderAddField(c.input, c.step, "Instructions", singleLineInstructions(c.instructions), 6),
+		renderAddField(c.input, c.step, "Context Files", c.contextFiles, 7),
 	}
-	if c.step == 4 {
+	if c.step == 8 {
 		lines = append(lines, "Groups:")
 		if c.groupPicker != nil {
 			lines = append(lines, c.groupPicker.View())
@@ -185,6 +207,45 @@ func (c *EditWorkspaceController) advance(host editWorkspaceHost) tea.Cmd {
 	case 3:
 		c.name = strings.TrimSpace(c.value())
 		c.step = 4
+		c.prepareInput()
+		host.setStatus("edit workspace: env profiles (optional, e.g. dev: TOKEN=cmd:pass show x; ci: TOKEN=env:CI_TOKEN)")
+		return nil
+	case 4:
+		raw := strings.TrimSpace(c.value())
+		if _, err := types.ParseEnvProfiles(raw); err != nil {
+			host.setStatus(err.Error())
+			return nil
+		}
+		c.envProfiles = raw
+		c.step = 5
+		c.prepareInput()
+		host.setStatus("edit workspace: active env profile (optional)")
+		return nil
+	case 5:
+		name := strings.TrimSpace(c.value())
+		if name != "" {
+			profiles, _ := types.ParseEnvProfiles(c.envProfiles)
+			if _, ok := types.FindEnvProfile(profiles, name); !ok {
+				host.setStatus("env profile " + name + " not found")
+				return nil
+			}
+		}
+		c.envProfile = name
+		c.step = 6
+		c.prepareInput()
+		host.setStatus("edit workspace: standing instructions for every session (optional)")
+		return nil
+	case 6:
+		if value := strings.TrimSpace(c.value()); value != singleLineInstructions(c.instructions) {
+			c.instructions = value
+		}
+		c.step = 7
+		c.prepareInput()
+		host.setStatus("edit workspace: context files (optional, relative to the repo)")
+		return nil
+	case 7:
+		c.contextFiles = strings.TrimSpace(c.value())
+		c.step = 8
 		if c.input != nil {
 			c.input.Blur()
 		}
@@ -198,7 +259,7 @@ func (c *EditWorkspaceController) advance(host editWorkspaceHost) tea.Cmd {
 		}
 		host.setStatus("edit workspace: groups (optional)")
 		return nil
-	case 4:
+	case 8:
 		if strings.TrimSpace(c.workspaceID) == "" {
 			host.setStatus("no workspace selected")
 			return nil
@@ -212,6 +273,10 @@ func (c *EditWorkspaceController) advance(host editWorkspaceHost) tea.Cmd {
 			SessionSubpath:           c.sub,
 			AdditionalDirectoriesRaw: c.dirs,
 			Name:                     c.name,
+			EnvProfilesRaw:           c.envProfiles,
+			EnvProfile:               c.envProfile,
+			Instructions:             c.instructions,
+			ContextFilesRaw:          c.contextFiles,
 			GroupIDs:                 c.groupIDs,
 		}))
 	default:
@@ -236,6 +301,18 @@ func (c *EditWorkspaceController) prepareInput() {
 	case 3:
 		c.input.SetPlaceholder("optional name")
 		c.input.SetValue(c.name)
+	case 4:
+		c.input.SetPlaceholder("dev: API_KEY=env:DEV_KEY, DB=file:~/.db-url; ci: TOKEN=cmd:pass show ci (optional)")
+		c.input.SetValue(c.envProfiles)
+	case 5:
+		c.input.SetPlaceholder("profile name (optional)")
+		c.input.SetValue(c.envProfile)
+	case 6:
+		c.input.SetPlaceholder("Run make test before finishing (optional)")
+		c.input.SetValue(singleLineInstructions(c.instructions))
+	case 7:
+		c.input.SetPlaceholder("docs/ARCHITECTURE.md, CONVENTIONS.md (optional)")
+		c.input.SetValue(c.contextFiles)
 	default:
 		c.input.SetValue("")
 	}
@@ -247,3 +324,7 @@ func (c *EditWorkspaceController) value() string {
 	}
 	return c.input.Value()
 }
+
+func singleLineInstructions(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -22,6 +22,7 @@ func TestEditWorkspaceControllerSubmitsAllWorkspaceFields(t *testing.T) {
 		SessionSubpath:        "packages/pennies",
 		AdditionalDirectories: []string{"../backend", "../shared"},
 		Name:                  "Repo",
+		Instructions:          "Run make test.\nKeep commits small.",
 		GroupIDs:              []string{"g1"},
 	})
 	if !ok {
@@ -68,7 +69,6 @@ func TestEditWorkspaceControllerSubmitsAllWorkspaceFields(t *testing.T) {
 	}
 
 	controller.input.SetValue("Renamed Repo")
-	// Step 3 → step 4 (group picker)
 	handled, cmd = controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host)
 	if !handled {
 		t.Fatalf("expected enter to be handled")
@@ -77,10 +77,33 @@ func TestEditWorkspaceControllerSubmitsAllWorkspaceFields(t *testing.T) {
 		t.Fatalf("expected no async command after name step")
 	}
 	if controller.step != 4 {
+		t.Fatalf("expected env profiles step, got %d", controller.step)
+	}
+
+	controller.input.SetValue("dev: MODE=dev; ci: TOKEN=cmd:pass show ci")
+	controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host)
+	if controller.step != 5 {
+		t.Fatalf("expected active env profile step, got %d", controller.step)
+	}
+	controller.input.SetValue("ci")
+	controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host)
+	if controller.step != 6 {
+		t.Fatalf("expected instructions step, got %d", controller.step)
+	}
+	if got := controller.input.Value(); got != "Run make test. Keep commits small." {
+		t.Fatalf("expected single-line instructions in input, got %q", got)
+	}
+	controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host)
+	if controller.step != 7 {
+		t.Fatalf("expected context files step, got %d", controller.step)
+	}
+	controller.input.SetValue("docs/ARCH.md, CONVENTIONS.md")
+	controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host)
+	if controller.step != 8 {
 		t.Fatalf("expected group picker step, got %d", controller.step)
 	}
 
-	// Step 4: Enter confirms group picker (pre-selected groups remain)
+	// Step 8: Enter confirms group picker (pre-selected groups remain)
 	handled, cmd = controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host)
 	if !handled {
 		t.Fatalf("expected enter to be handled on group picker step")
@@ -109,6 +132,37 @@ func TestEditWorkspaceControllerSubmitsAllWorkspaceFields(t *testing.T) {
 	if host.updatePatch.GroupIDs == nil || len(*host.updatePatch.GroupIDs) != 1 || (*host.updatePatch.GroupIDs)[0] != "g1" {
 		t.Fatalf("expected pre-selected group g1 in patch, got %#v", host.updatePatch.GroupIDs)
 	}
+	if host.updatePatch.EnvProfiles == nil || len(*host.updatePatch.EnvProfiles) != 2 || (*host.updatePatch.EnvProfiles)[1].Vars[0].Source != types.EnvSourceCommand {
+		t.Fatalf("expected parsed env profiles in patch, got %#v", host.updatePatch.EnvProfiles)
+	}
+	if host.updatePatch.EnvProfile == nil || *host.updatePatch.EnvProfile != "ci" {
+		t.Fatalf("expected active env profile ci, got %#v", host.updatePatch.EnvProfile)
+	}
+	if host.updatePatch.Instructions == nil || *host.updatePatch.Instructions != "Run make test.\nKeep commits small." {
+		t.Fatalf("expected unchanged instructions to keep their line breaks, got %#v", host.updatePatch.Instructions)
+	}
+	if host.updatePatch.ContextFiles == nil || len(*host.updatePatch.ContextFiles) != 2 || (*host.updatePatch.ContextFiles)[1] != "CONVENTIONS.md" {
+		t.Fatalf("expected context files in patch, got %#v", host.updatePatch.ContextFiles)
+	}
+}
+
+func TestEditWorkspaceControllerRejectsInvalidEnvProfiles(t *testing.T) {
+	controller := NewEditWorkspaceController(80)
+	host := &stubEditWorkspaceHost{}
+	controller.Enter("ws1", &types.Workspace{ID: "ws1", RepoPath: "/tmp/repo"})
+	controller.step = 4
+	controller.input.SetValue("dev: NOT A VAR")
+	controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host)
+	if controller.step != 4 || !strings.Contains(host.status, "invalid env var") {
+		t.Fatalf("expected to stay on env profiles step, got step %d status %q", controller.step, host.status)
+	}
+	controller.input.SetValue("dev: MODE=dev")
+	controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host)
+	controller.input.SetValue("prod")
+	controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host)
+	if controller.step != 5 || host.status != "env profile prod not found" {
+		t.Fatalf("expected unknown active profile to be rejected, got step %d status %q", controller.step, host.status)
+	}
 }
 
 func TestEditWorkspaceControllerAllowsIDWithoutPrefill(t *testing.T) {
@@ -285,8 +339,8 @@ func TestEditWorkspaceControllerAdvanceRequiresWorkspaceIDOnSubmit(t *testing.T)
 		t.Fatalf("expected controller to enter")
 	}
 
-	// The workspace ID check now happens at step 4 (group picker confirm).
-	controller.step = 4
+	// The workspace ID check now happens at step 8 (group picker confirm).
+	controller.step = 8
 	controller.workspaceID = "   "
 	handled, cmd := controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host)
 	if !handled {
@@ -399,9 +453,13 @@ func TestEditWorkspaceControllerGroupPickerPreSelectsExistingGroups(t *testing.T
 	controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host) // step 2 → 3
 	controller.input.SetValue("Repo")
 	controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host) // step 3 → 4
+	controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host) // step 4 → 5
+	controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host) // step 5 → 6
+	controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host) // step 6 → 7
+	controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host) // step 7 → 8
 
-	if controller.step != 4 {
-		t.Fatalf("expected step 4, got %d", controller.step)
+	if controller.step != 8 {
+		t.Fatalf("expected step 8, got %d", controller.step)
 	}
 
 	// Confirm without changing selection — pre-selected g2 should carry through
@@ -438,7 +496,11 @@ func TestEditWorkspaceControllerGroupPickerToggle(t *testing.T) {
 	controller.input.SetValue("")
 	controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host)
 	controller.input.SetValue("Repo")
-	controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host) // → step 4
+	controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host)
+	controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host)
+	controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host)
+	controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host)
+	controller.Update(tea.KeyPressMsg{Code: tea.KeyEnter}, host) // → step 8
 
 	// Toggle first group
 	controller.Update(tea.KeyPressMsg{Code: ' '}, host)
@@ -41,6 +41,8 @@ type guidedWorkflowLaunchContext struct {
 	followUpRunID    string
 	followUpRunLabel string
 	dependencyLocked bool
+	templateID       string
+	userPrompt       string
 }
 
 type guidedWorkflowTemplateOption struct {
@@ -119,7 +121,7 @@ func (c *GuidedWorkflowUIController) Enter(context guidedWorkflowLaunchContext)
 	}
 	c.stage = guidedWorkflowStageLauncher
 	c.context = context
-	c.templateID = ""
+	c.templateID = strings.TrimSpace(context.templateID)
 	c.templateName = ""
 	c.templatePicker.Reset()
 	c.provider = ""
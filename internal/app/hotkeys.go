@@ -16,6 +16,7 @@ const (
 	HotkeyConfirm
 	HotkeyApproval
 	HotkeyGuidedWorkflow
+	HotkeyTiles
 )
 
 type Hotkey struct {
@@ -35,11 +36,14 @@ func DefaultHotkeys() []Hotkey {
 		{Key: "ctrl+b", Command: KeyCommandToggleSidebar, Label: "sidebar", Context: HotkeyGlobal, Priority: 10},
 		{Key: "ctrl+o", Command: KeyCommandToggleNotesPanel, Label: "notes panel", Context: HotkeyGlobal, Priority: 10},
 		{Key: "ctrl+l", Command: KeyCommandToggleContextPanel, Label: "context panel", Context: HotkeyGlobal, Priority: 10},
+		{Key: "ctrl+x", Command: KeyCommandToggleDiffPanel, Label: "diff panel", Context: HotkeyGlobal, Priority: 10},
+		{Key: "[/]", Label: "diff file", Context: HotkeySidebar, Priority: 61},
 		{Key: "ctrl+d", Command: KeyCommandToggleDebugStreams, Label: "debug streams", Context: HotkeyGlobal, Priority: 10},
 		{Key: "J/K", Label: "debug scroll", Context: HotkeySidebar, Priority: 58},
 		{Key: "shift+pgup/pgdn", Label: "debug page", Context: HotkeySidebar, Priority: 59},
 		{Key: "shift+home/end", Label: "debug top/bottom", Context: HotkeySidebar, Priority: 60},
 		{Key: "ctrl+m", Command: KeyCommandMenu, Label: "menu", Context: HotkeyGlobal, Priority: 11},
+		{Key: "ctrl+p", Command: KeyCommandCommandPalette, Label: "commands", Context: HotkeyGlobal, Priority: 11},
 		{Key: "esc", Command: KeyCommandOpenSettings, Label: "settings", Context: HotkeyGlobal, Priority: 12},
 		{Key: "q", Command: KeyCommandQuit, Label: "quit", Context: HotkeyGlobal, Priority: 90},
 		{Key: "a", Command: KeyCommandAddWorkspace, Label: "add workspace", Context: HotkeySidebar, Priority: 20},
@@ -56,6 +60,9 @@ func DefaultHotkeys() []Hotkey {
 		{Key: "d", Command: KeyCommandDismissSelection, Label: "dismiss/delete", Context: HotkeySidebar, Priority: 31},
 		{Key: "u", Command: KeyCommandUndismissSession, Label: "undismiss", Context: HotkeySidebar, Priority: 32},
 		{Key: "D", Command: KeyCommandToggleDismissed, Label: "toggle dismissed", Context: HotkeySidebar, Priority: 33},
+		{Key: "T", Command: KeyCommandToggleTiles, Label: "tile sessions", Context: HotkeySidebar, Priority: 33},
+		{Key: "R", Command: KeyCommandOpenApprovalRules, Label: "approval rules", Context: HotkeySidebar, Priority: 33},
+		{Key: "A", Command: KeyCommandOpenApprovalInbox, Label: "approval inbox", Context: HotkeySidebar, Priority: 33},
 		{Key: "ctrl+g", Command: KeyCommandCopySelectionIDs, Label: "copy ids", Context: HotkeySidebar, Priority: 34},
 		{Key: "x", Command: KeyCommandKillSession, Label: "kill", Context: HotkeySidebar, Priority: 34},
 		{Key: "i", Command: KeyCommandInterruptSession, Label: "interrupt/stop", Context: HotkeySidebar, Priority: 35},
@@ -88,6 +95,15 @@ func DefaultHotkeys() []Hotkey {
 		{Key: "a/v/p", Label: "checkpoint action", Context: HotkeyGuidedWorkflow, Priority: 12},
 		{Key: "r", Label: "refresh timeline", Context: HotkeyGuidedWorkflow, Priority: 13},
 		{Key: "esc", Label: "close", Context: HotkeyGuidedWorkflow, Priority: 14},
+		{Key: "tab/←/→/↑/↓", Label: "focus tile", Context: HotkeyTiles, Priority: 10},
+		{Key: "c", Label: "reply", Context: HotkeyTiles, Priority: 11},
+		{Key: "y", Command: KeyCommandApprove, Label: "approve", Context: HotkeyTiles, Priority: 12},
+		{Key: "x", Command: KeyCommandDecline, Label: "decline", Context: HotkeyTiles, Priority: 13},
+		{Key: "i", Label: "interrupt", Context: HotkeyTiles, Priority: 14},
+		{Key: "enter", Label: "open", Context: HotkeyTiles, Priority: 15},
+		{Key: "+/-", Label: "columns", Context: HotkeyTiles, Priority: 16},
+		{Key: "d", Label: "remove tile", Context: HotkeyTiles, Priority: 17},
+		{Key: "esc", Label: "close", Context: HotkeyTiles, Priority: 18},
 		{Key: "esc", Label: "cancel", Context: HotkeyAddWorkspace, Priority: 10},
 		{Key: "enter", Label: "continue", Context: HotkeyAddWorkspace, Priority: 11},
 		{Key: "esc", Label: "cancel", Context: HotkeyAddWorktree, Priority: 10},
@@ -106,6 +122,8 @@ func DefaultHotkeys() []Hotkey {
 		{Key: "ctrl+a", Command: KeyCommandInputSelectAll, Label: "select all", Context: HotkeyChatInput, Priority: 17},
 		{Key: "ctrl+z", Command: KeyCommandInputUndo, Label: "undo", Context: HotkeyChatInput, Priority: 18},
 		{Key: "ctrl+y", Command: KeyCommandInputRedo, Label: "redo", Context: HotkeyChatInput, Priority: 19},
+		{Key: "ctrl+t", Command: KeyCommandComposeAttach, Label: "attach file", Context: HotkeyChatInput, Priority: 20},
+		{Key: "alt+v", Command: KeyCommandComposePasteImage, Label: "paste image", Context: HotkeyChatInput, Priority: 21},
 	}
 }
 
@@ -142,6 +160,12 @@ func (r DefaultHotkeyResolver) ActiveContexts(m *Model) []HotkeyContext {
 	if m.mode == uiModeGuidedWorkflow {
 		return []HotkeyContext{HotkeyGlobal, HotkeyGuidedWorkflow}
 	}
+	if m.mode == uiModeTiles {
+		if m.tilesReplySessionID != "" {
+			return []HotkeyContext{HotkeyChatInput}
+		}
+		return []HotkeyContext{HotkeyGlobal, HotkeyTiles}
+	}
 	contexts := []HotkeyContext{HotkeyGlobal}
 	switch m.mode {
 	case uiModeAddWorkspace:
@@ -19,8 +19,22 @@ func (f InputFooterFunc) InputFooter() string {
 	return strings.TrimRight(f(), "\n")
 }
 
+type InputHeaderProvider interface {
+	InputHeader() string
+}
+
+type InputHeaderFunc func() string
+
+func (f InputHeaderFunc) InputHeader() string {
+	if f == nil {
+		return ""
+	}
+	return strings.TrimRight(f(), "\n")
+}
+
 type InputPanel struct {
 	Input  *TextInput
+	Header InputHeaderProvider
 	Footer InputFooterProvider
 	Frame  InputPanelFrame
 }
@@ -5,6 +5,7 @@ import "charm.land/lipgloss/v2"
 type InputPanelLayout struct {
 	line       string
 	scrollable bool
+	headerRows int
 	inputLines int
 	footerRows int
 }
@@ -21,6 +22,10 @@ func BuildInputPanelLayout(panel InputPanel) InputPanelLayout {
 		inputLines += maxInt(0, panel.Frame.VerticalInsetLines())
 	}
 
+	header := ""
+	if panel.Header != nil {
+		header = panel.Header.InputHeader()
+	}
 	footer := ""
 	if panel.Footer != nil {
 		footer = panel.Footer.InputFooter()
@@ -31,6 +36,10 @@ func BuildInputPanelLayout(panel InputPanel) InputPanelLayout {
 		scrollable: panel.Input.CanScroll(),
 		inputLines: inputLines,
 	}
+	if header != "" {
+		layout.headerRows = maxInt(1, lipgloss.Height(header))
+		layout.line = header + "\n" + layout.line
+	}
 	if footer == "" {
 		return layout
 	}
@@ -45,18 +54,23 @@ func (l InputPanelLayout) View() (line string, scrollable bool) {
 }
 
 func (l InputPanelLayout) LineCount() int {
-	return l.inputLines + l.footerRows
+	return l.headerRows + l.inputLines + l.footerRows
 }
 
 func (l InputPanelLayout) InputLineCount() int {
 	return l.inputLines
 }
 
+// InputStartRow is the row of the input below the panel header.
+func (l InputPanelLayout) InputStartRow() int {
+	return l.headerRows
+}
+
 func (l InputPanelLayout) FooterStartRow() (int, bool) {
 	if l.footerRows == 0 {
 		return 0, false
 	}
-	return l.inputLines, true
+	return l.headerRows + l.inputLines, true
 }
 
 func maxInt(a, b int) int {
@@ -3,9 +3,12 @@ package app
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
+
+	"control/internal/types"
 )
 
 func itemsToBlocks(items []map[string]any) []ChatBlock {
@@ -52,6 +55,40 @@ func extractContentText(raw any) string {
 	return strings.Join(parts, " ")
 }
 
+// extractUserMessageText is the text of user message content, with expanded
+// mention context collapsed to its tokens, followed by a note naming its
+// attachments. Codex echoes attached images as localImage items; the other
+// providers keep the attachment items that were sent.
+func extractUserMessageText(raw any) string {
+	text := types.CollapseMentionContext(extractContentText(raw))
+	items, _ := raw.([]any)
+	var names []string
+	for _, entry := range items {
+		m, ok := entry.(map[string]any)
+		if !ok {
+			continue
+		}
+		if attachment, ok := types.InputAttachmentFromItem(m); ok {
+			names = append(names, attachment.DisplayName())
+			continue
+		}
+		if typ, _ := m["type"].(string); typ == "localImage" {
+			if path := strings.TrimSpace(asString(m["path"])); path != "" {
+				names = append(names, filepath.Base(path))
+			}
+		}
+	}
+	note := types.AttachmentNote(names)
+	switch {
+	case note == "":
+		return text
+	case text == "":
+		return note
+	default:
+		return text + "\n" + note
+	}
+}
+
 func extractCommand(raw any) string {
 	switch value := raw.(type) {
 	case string:
@@ -102,10 +102,12 @@ func keybindingScopesFor(command, boundKey, defaultKey string) []string {
 		return []string{keyScopeNormal, keyScopeGuidedWorkflowSetupInput}
 	case KeyCommandToggleNotesPanel:
 		return []string{keyScopeNormal, keyScopeComposeInput, keyScopeNotesMode, keyScopeAddNoteInput, keyScopeGuidedWorkflowSetupInput}
-	case KeyCommandToggleContextPanel:
+	case KeyCommandToggleContextPanel, KeyCommandToggleDiffPanel:
 		return []string{keyScopeNormal, keyScopeComposeInput, keyScopeNotesMode, keyScopeAddNoteInput, keyScopeGuidedWorkflowSetupInput}
 	case KeyCommandToggleDebugStreams:
 		return []string{keyScopeNormal, keyScopeComposeInput}
+	case KeyCommandCommandPalette:
+		return []string{keyScopeNormal, keyScopeComposeInput, keyScopeNotesMode, keyScopeMessageSelect}
 	case KeyCommandCopySelectionIDs, KeyCommandCopySessionID:
 		return []string{keyScopeNormal, keyScopeComposeInput}
 	case KeyCommandToggleMessageSelect:
@@ -118,7 +120,8 @@ func keybindingScopesFor(command, boundKey, defaultKey string) []string {
 			scopes = append(scopes, keyScopeNormal, keyScopeComposeInput)
 		}
 		return scopes
-	case KeyCommandComposeModel, KeyCommandComposeReasoning, KeyCommandComposeAccess:
+	case KeyCommandComposeModel, KeyCommandComposeReasoning, KeyCommandComposeAccess,
+		KeyCommandComposeAttach, KeyCommandComposePasteImage:
 		return []string{keyScopeComposeInput}
 	case KeyCommandInputSubmit, KeyCommandInputNewline, KeyCommandInputLineUp, KeyCommandInputLineDown, KeyCommandInputWordLeft, KeyCommandInputWordRight,
 		KeyCommandInputDeleteWordLeft, KeyCommandInputDeleteWordRight, KeyCommandInputSelectAll,
@@ -12,12 +12,16 @@ import (
 
 const (
 	KeyCommandMenu                 = "ui.menu"
+	KeyCommandCommandPalette       = "ui.commandPalette"
 	KeyCommandOpenSettings         = "ui.openSettings"
 	KeyCommandRename               = "ui.rename"
 	KeyCommandQuit                 = "ui.quit"
 	KeyCommandToggleSidebar        = "ui.toggleSidebar"
 	KeyCommandToggleNotesPanel     = "ui.toggleNotesPanel"
 	KeyCommandToggleContextPanel   = "ui.toggleContextPanel"
+	KeyCommandToggleDiffPanel      = "ui.toggleDiffPanel"
+	KeyCommandDiffPanelPrevFile    = "ui.diffPanelPrevFile"
+	KeyCommandDiffPanelNextFile    = "ui.diffPanelNextFile"
 	KeyCommandToggleDebugStreams   = "ui.toggleDebugStreams"
 	KeyCommandDebugPanelUp         = "ui.debugPanelUp"
 	KeyCommandDebugPanelDown       = "ui.debugPanelDown"
@@ -52,6 +56,9 @@ const (
 	KeyCommandDismissSession       = "ui.dismissSession" // legacy alias; normalized to ui.dismissSelection
 	KeyCommandUndismissSession     = "ui.undismissSession"
 	KeyCommandToggleDismissed      = "ui.toggleDismissed"
+	KeyCommandToggleTiles          = "ui.toggleTiles"
+	KeyCommandOpenApprovalRules    = "ui.openApprovalRules"
+	KeyCommandOpenApprovalInbox    = "ui.openApprovalInbox"
 	KeyCommandToggleNotesWorkspace = "ui.toggleNotesWorkspace"
 	KeyCommandToggleNotesWorktree  = "ui.toggleNotesWorktree"
 	KeyCommandToggleNotesSession   = "ui.toggleNotesSession"
@@ -66,6 +73,8 @@ const (
 	KeyCommandComposeModel         = "ui.composeModel"
 	KeyCommandComposeReasoning     = "ui.composeReasoning"
 	KeyCommandComposeAccess        = "ui.composeAccess"
+	KeyCommandComposeAttach        = "ui.composeAttach"
+	KeyCommandComposePasteImage    = "ui.composePasteImage"
 	KeyCommandInputSubmit          = "ui.inputSubmit"
 	KeyCommandInputNewline         = "ui.inputNewline"
 	KeyCommandInputLineUp          = "ui.inputLineUp"
@@ -84,12 +93,16 @@ const (
 
 var defaultKeybindingByCommand = map[string]string{
 	KeyCommandMenu:                 "ctrl+m",
+	KeyCommandCommandPalette:       "ctrl+p",
 	KeyCommandOpenSettings:         "esc",
 	KeyCommandRename:               "m",
 	KeyCommandQuit:                 "q",
 	KeyCommandToggleSidebar:        "ctrl+b",
 	KeyCommandToggleNotesPanel:     "ctrl+o",
 	KeyCommandToggleContextPanel:   "ctrl+l",
+	KeyCommandToggleDiffPanel:      "ctrl+x",
+	KeyCommandDiffPanelPrevFile:    "[",
+	KeyCommandDiffPanelNextFile:    "]",
 	KeyCommandToggleDebugStreams:   "ctrl+d",
 	KeyCommandDebugPanelUp:         "J",
 	KeyCommandDebugPanelDown:       "K",
@@ -122,6 +135,9 @@ var defaultKeybindingByCommand = map[string]string{
 	KeyCommandDismissSelection:     "d",
 	KeyCommandUndismissSession:     "u",
 	KeyCommandToggleDismissed:      "D",
+	KeyCommandToggleTiles:          "T",
+	KeyCommandOpenApprovalRules:    "R",
+	KeyCommandOpenApprovalInbox:    "A",
 	KeyCommandToggleNotesWorkspace: "1",
 	KeyCommandToggleNotesWorktree:  "2",
 	KeyCommandToggleNotesSession:   "3",
@@ -135,6 +151,8 @@ var defaultKeybindingByCommand = map[string]string{
 	KeyCommandComposeModel:         "ctrl+1",
 	KeyCommandComposeReasoning:     "ctrl+2",
 	KeyCommandComposeAccess:        "ctrl+3",
+	KeyCommandComposeAttach:        "ctrl+t",
+	KeyCommandComposePasteImage:    "alt+v",
 	KeyCommandInputSubmit:          "enter",
 	KeyCommandInputNewline:         "shift+enter",
 	KeyCommandInputLineUp:          "up",
@@ -27,6 +27,7 @@ const (
 	sidePanelModeNotes
 	sidePanelModeDebug
 	sidePanelModeContext
+	sidePanelModeDiff
 )
 
 const (
@@ -94,6 +95,8 @@ func (m *Model) activePanelDimensions() (visible bool, main int, width int) {
 		return m.debugPanelVisible && m.debugPanelWidth > 0, m.debugPanelMainWidth, m.debugPanelWidth
 	case sidePanelModeContext:
 		return m.contextPanelVisible && m.contextPanelWidth > 0, m.contextPanelMainWidth, m.contextPanelWidth
+	case sidePanelModeDiff:
+		return m.diffPanelVisible && m.diffPanelWidth > 0, m.diffPanelMainWidth, m.diffPanelWidth
 	case sidePanelModeNotes:
 		return m.notesPanelVisible && m.notesPanelWidth > 0, m.notesPanelMainWidth, m.notesPanelWidth
 	default:
@@ -104,6 +104,18 @@ type notesMsg struct {
 
 type notesPanelReflowMsg struct{}
 
+type gitDiffMsg struct {
+	target diffPanelTarget
+	diff   *types.GitDiff
+	err    error
+}
+
+type sessionUsageMsg struct {
+	sessionID string
+	usage     *types.SessionUsage
+	err       error
+}
+
 type noteCreatedMsg struct {
 	note  *types.Note
 	scope noteScopeTarget
@@ -184,6 +196,23 @@ type recentsPreviewMsg struct {
 	err      error
 }
 
+type sessionTileSnapshotMsg struct {
+	id       string
+	snapshot *transcriptdomain.TranscriptSnapshot
+	err      error
+}
+
+type sessionTileStreamMsg struct {
+	id     string
+	ch     <-chan transcriptdomain.TranscriptEvent
+	cancel func()
+	err    error
+}
+
+type sessionTileReconnectMsg struct {
+	id string
+}
+
 type recentsTurnCompletedMsg struct {
 	id           string
 	expectedTurn string
@@ -258,6 +287,33 @@ type worktreeDeletedMsg struct {
 	err         error
 }
 
+type worktreeOperationMsg struct {
+	workspaceID string
+	worktreeID  string
+	op          types.WorktreeOperation
+	result      *types.WorktreeOperationResult
+	err         error
+}
+
+type finalizeDraftMsg struct {
+	target finalizeTarget
+	draft  *types.FinalizeDraft
+	err    error
+}
+
+type finalizeResultMsg struct {
+	target finalizeTarget
+	result *types.FinalizeResult
+	err    error
+}
+
+type checkpointRestoreMsg struct {
+	sessionID string
+	turnID    string
+	result    *types.SessionCheckpointRestoreResult
+	err       error
+}
+
 type sendMsg struct {
 	id     string
 	turnID string
@@ -343,6 +399,59 @@ type clipboardResultMsg struct {
 	err     error
 }
 
+type composeMentionCandidatesMsg struct {
+	kind     types.InputMentionKind
+	query    string
+	mentions []types.InputMention
+	err      error
+}
+
+type composeSnippetsLoadedMsg struct {
+	workspaceID string
+	snippets    []*types.Snippet
+	err         error
+}
+
+type approvalInboxLoadedMsg struct {
+	approvals []*types.PendingApproval
+	err       error
+}
+
+type approvalInboxStreamMsg struct {
+	ch     <-chan transcriptdomain.TranscriptEvent
+	cancel func()
+	err    error
+}
+
+type approvalInboxEventMsg struct {
+	ch     <-chan transcriptdomain.TranscriptEvent
+	event  transcriptdomain.TranscriptEvent
+	closed bool
+}
+
+type approvalRulesLoadedMsg struct {
+	rules  []*types.ApprovalRule
+	status string
+	err    error
+}
+
+type transcriptExportMsg struct {
+	sessionID string
+	path      string
+	markdown  string
+	err       error
+}
+
+type composeSlashTemplatesMsg struct {
+	templates []guidedworkflows.WorkflowTemplate
+	err       error
+}
+
+type composeAttachmentPastedMsg struct {
+	attachment types.InputAttachment
+	err        error
+}
+
 type fileLinkOpenResultMsg struct {
 	target string
 	err    error
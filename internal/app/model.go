@@ -86,6 +86,10 @@ const (
 	uiModePickNoteMoveWorktree
 	uiModePickNoteMoveSession
 	uiModeGuidedWorkflow
+	uiModeFinalize
+	uiModeApprovalRules
+	uiModeApprovalInbox
+	uiModeTiles
 )
 
 type Model struct {
@@ -99,8 +103,20 @@ type Model struct {
 	sessionSelectionAPI                             SessionSelectionAPI
 	sessionHistoryAPI                               SessionHistoryAPI
 	notesAPI                                        NotesAPI
+	diffAPI                                         GitDiffAPI
+	worktreeLifecycleAPI                            WorktreeLifecycleAPI
+	checkpointAPI                                   SessionCheckpointAPI
+	finalizeAPI                                     FinalizeAPI
+	approvalRulesAPI                                ApprovalRuleAPI
+	approvalInboxAPI                                ApprovalInboxAPI
+	usageAPI                                        SessionUsageAPI
+	symbolAPI                                       SessionSymbolAPI
+	snippetAPI                                      SnippetListAPI
+	forkAPI                                         SessionForkAPI
+	transcriptExportAPI                             TranscriptExportAPI
 	stateAPI                                        StateAPI
 	clipboard                                       ClipboardService
+	clipboardImages                                 ClipboardImageReader
 	fileLinkResolver                                FileLinkResolver
 	fileLinkOpener                                  FileLinkOpener
 	mouseGesturePolicy                              MouseGesturePolicy
@@ -119,6 +135,12 @@ type Model struct {
 	chatAddonController                             *ChatInputAddonController
 	composeFileSearch                               *ComposeFileAutocompleteController
 	composeFileSearchStream                         *ComposeFileSearchStreamController
+	composeAttachments                              []types.InputAttachment
+	composeMention                                  *ComposeMentionController
+	composeMentionProviders                         *composeMentionRegistry
+	composeMentions                                 []types.InputMention
+	composeSnippet                                  *ComposeSnippetController
+	composeSlash                                    *ComposeSlashController
 	chatInput                                       *TextInput
 	guidedWorkflowPromptInput                       *TextInput
 	guidedWorkflowResumeInput                       *TextInput
@@ -191,6 +213,17 @@ type Model struct {
 	approvalResponseSessionID                       string
 	approvalResponseRequestID                       int
 	approvalResponseReturnMode                      uiMode
+	finalizeInput                                   *TextInput
+	finalizeTarget                                  finalizeTarget
+	finalizeDraft                                   *types.FinalizeDraft
+	approvalRulesInput                              *TextInput
+	approvalRules                                   []*types.ApprovalRule
+	approvalRulesLoading                            bool
+	approvalInbox                                   []*types.PendingApproval
+	approvalInboxCursor                             int
+	approvalInboxMarked                             map[string]bool
+	approvalInboxLoading                            bool
+	approvalInboxStreamCancel                       func()
 	approvalResponseReturnFocus                     inputFocus
 	sessionApprovals                                map[string][]*ApprovalRequest
 	sessionApprovalResolutions                      map[string][]*ApprovalResolution
@@ -268,6 +301,9 @@ type Model struct {
 	recentsSelectedSessionID                        string
 	recentsExpandedSessions                         map[string]bool
 	recentsReplySessionID                           string
+	sessionTiles                                    *SessionTilesController
+	tilesReplyInput                                 *TextInput
+	tilesReplySessionID                             string
 	recentsPreviews                                 map[string]recentsPreview
 	recentsCompletionWatching                       map[string]string
 	newSession                                      *newSessionTarget
@@ -301,6 +337,17 @@ type Model struct {
 	contextPanelVisible                             bool
 	contextPanelWidth                               int
 	contextPanelMainWidth                           int
+	sessionUsage                                    map[string]*types.SessionUsage
+	diffPanelOpen                                   bool
+	diffPanelVisible                                bool
+	diffPanelWidth                                  int
+	diffPanelMainWidth                              int
+	diffPanelTarget                                 diffPanelTarget
+	diffPanelDiff                                   *types.GitDiff
+	diffPanelFileIndex                              int
+	diffPanelLoading                                bool
+	diffPanelError                                  string
+	diffPanelViewport                               viewport.Model
 	sidePanelModePolicy                             SidePanelModePolicy
 	threadContextMetricsService                     ThreadContextMetricsService
 	debugPanel                                      debugPanelView
@@ -395,6 +442,7 @@ type Model struct {
 	themeID                                         string
 	themePreferenceStore                            ThemePreferenceStore
 	settingsMenu                                    *SettingsMenuController
+	commandPalette                                  *CommandPaletteController
 	settingsMenuPresenter                           SettingsMenuPresenter
 	settingsMenuEscPolicy                           SettingsMenuEscPolicy
 	settingsMenuHotkeyCatalog                       SettingsMenuHotkeyCatalog
@@ -504,8 +552,10 @@ const (
 	confirmDeleteWorkspace
 	confirmDeleteWorkspaceGroup
 	confirmDeleteWorktree
+	confirmRemoveWorktree
 	confirmDeleteNote
 	confirmDismissSessions
+	confirmRollbackTurn
 )
 
 type confirmAction struct {
@@ -515,6 +565,7 @@ type confirmAction struct {
 	worktreeID  string
 	noteID      string
 	sessionIDs  []string
+	turnID      string
 }
 
 func NewModel(client *client.Client, opts ...ModelOption) Model {
@@ -522,6 +573,7 @@ func NewModel(client *client.Client, opts ...ModelOption) Model {
 	vp.SetContent("No sessions.")
 	notesPanelVP := viewport.New(viewport.WithWidth(minViewportWidth), viewport.WithHeight(minContentHeight-1))
 	notesPanelVP.SetContent("No notes.")
+	diffPanelVP := viewport.New(viewport.WithWidth(minViewportWidth), viewport.WithHeight(minContentHeight-1))
 
 	api := NewClientAPI(client)
 	var transcriptAPI SessionTranscriptAPI
@@ -552,6 +604,17 @@ func NewModel(client *client.Client, opts ...ModelOption) Model {
 		sessionSelectionAPI:                 api,
 		sessionHistoryAPI:                   api,
 		notesAPI:                            api,
+		diffAPI:                             api,
+		worktreeLifecycleAPI:                api,
+		checkpointAPI:                       api,
+		finalizeAPI:                         api,
+		approvalRulesAPI:                    api,
+		approvalInboxAPI:                    api,
+		usageAPI:                            api,
+		symbolAPI:                           api,
+		snippetAPI:                          api,
+		forkAPI:                             api,
+		transcriptExportAPI:                 api,
 		stateAPI:                            api,
 		clipboard:                           defaultClipboardService{},
 		fileLinkResolver:                    defaultFileLinkResolver{},
@@ -561,6 +624,7 @@ func NewModel(client *client.Client, opts ...ModelOption) Model {
 		sidebar:                             NewSidebarController(),
 		viewport:                            vp,
 		notesPanelViewport:                  notesPanelVP,
+		diffPanelViewport:                   diffPanelVP,
 		stream:                              stream,
 		transcriptStream:                    transcriptStream,
 		debugStream:                         debugStream,
@@ -569,7 +633,7 @@ func NewModel(client *client.Client, opts ...ModelOption) Model {
 		metadataStreamRecoveryPolicy:        newDefaultMetadataStreamRecoveryPolicy(),
 		debugStreamSnapshot:                 debugStream,
 		debugPanel:                          debugPanel,
-		threadContextMetricsService:         NewDefaultThreadContextMetricsService(nil),
+		threadContextMetricsService:         NewDefaultThreadContextMetricsService(defaultThreadContextProviderMetricsAdapters()),
 		debugPanelPresenter:                 NewDefaultDebugPanelPresenter(DefaultDebugPanelDisplayPolicy()),
 		debugPanelBlocksRenderer:            NewDefaultDebugPanelBlocksRenderer(),
 		debugPanelInteractionService:        NewDefaultDebugPanelInteractionService(),
@@ -583,6 +647,10 @@ func NewModel(client *client.Client, opts ...ModelOption) Model {
 		chatAddonController:                 NewChatInputAddonController(chatAddon),
 		composeFileSearch:                   NewComposeFileAutocompleteController(minViewportWidth, 8),
 		composeFileSearchStream:             NewComposeFileSearchStreamController(maxEventsPerTick),
+		composeMention:                      NewComposeMentionController(minViewportWidth, 8),
+		composeMentionProviders:             newDefaultComposeMentionRegistry(),
+		composeSnippet:                      NewComposeSnippetController(minViewportWidth, 8),
+		composeSlash:                        NewComposeSlashController(minViewportWidth, 8),
 		chatInput:                           NewTextInput(minViewportWidth, DefaultTextInputConfig()),
 		guidedWorkflowPromptInput:           NewTextInput(minViewportWidth, TextInputConfig{Height: 5, MinHeight: 4, MaxHeight: 10, AutoGrow: true}),
 		guidedWorkflowResumeInput:           NewTextInput(minViewportWidth, TextInputConfig{Height: 4, MinHeight: 3, MaxHeight: 8, AutoGrow: true}),
@@ -595,7 +663,11 @@ func NewModel(client *client.Client, opts ...ModelOption) Model {
 		workspaceMulti:                      NewMultiSelectPicker(minViewportWidth, minContentHeight-1),
 		noteInput:                           NewTextInput(minViewportWidth, DefaultTextInputConfig()),
 		approvalInput:                       NewTextInput(minViewportWidth, DefaultTextInputConfig()),
+		finalizeInput:                       NewTextInput(minViewportWidth, DefaultTextInputConfig()),
+		approvalRulesInput:                  NewTextInput(minViewportWidth, TextInputConfig{Height: 1, SingleLine: true}),
 		recentsReplyInput:                   NewTextInput(minViewportWidth, DefaultTextInputConfig()),
+		tilesReplyInput:                     NewTextInput(minViewportWidth, DefaultTextInputConfig()),
+		sessionTiles:                        NewSessionTilesController(maxEventsPerTick),
 		status:                              "",
 		statusHistory:                       newStatusHistoryStore(statusHistoryMaxEntries),
 		statusHistoryOverlay:                newStatusHistoryOverlayController(),
@@ -721,6 +793,7 @@ func NewModel(client *client.Client, opts ...ModelOption) Model {
 		themeID:                             defaultThemeID,
 		themePreferenceStore:                fileThemePreferenceStore{},
 		settingsMenu:                        NewSettingsMenuController(),
+		commandPalette:                      NewCommandPaletteController(),
 		settingsMenuPresenter:               defaultSettingsMenuPresenter{},
 		settingsMenuEscPolicy:               defaultSettingsMenuEscPolicy{},
 		settingsMenuHotkeyCatalog:           defaultSettingsMenuHotkeyCatalog{},
@@ -812,6 +885,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if handled, cmd := m.reduceSettingsMenu(msg); handled {
 		return m, cmd
 	}
+	if handled, cmd := m.reduceCommandPalette(msg); handled {
+		return m, cmd
+	}
 
 	if m.confirm != nil && m.confirm.IsOpen() {
 		switch msg := msg.(type) {
@@ -890,6 +966,15 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if handled, cmd := m.reduceApprovalResponseMode(msg); handled {
 		return m, cmd
 	}
+	if handled, cmd := m.reduceFinalizeMode(msg); handled {
+		return m, cmd
+	}
+	if handled, cmd := m.reduceApprovalRulesMode(msg); handled {
+		return m, cmd
+	}
+	if handled, cmd := m.reduceApprovalInboxMode(msg); handled {
+		return m, cmd
+	}
 	if handled, cmd := m.reduceWorkspaceEditModes(msg); handled {
 		return m, cmd
 	}
@@ -908,6 +993,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if handled, cmd := m.reduceRecentsMode(msg); handled {
 		return m, cmd
 	}
+	if handled, cmd := m.reduceSessionTilesMode(msg); handled {
+		return m, cmd
+	}
 	if handled, cmd := m.reduceAddNoteMode(msg); handled {
 		return m, cmd
 	}
@@ -1053,10 +1141,16 @@ func (m *Model) applyUIConfig(uiConfig config.UIConfig) {
 	if m.approvalInput != nil {
 		m.approvalInput.SetConfig(cfg)
 	}
+	if m.finalizeInput != nil {
+		m.finalizeInput.SetConfig(cfg)
+	}
 	if m.recentsReplyInput != nil {
 		m.recentsReplyInput.SetConfig(cfg)
 	}
-	inputHeightChanged := m.consumeInputHeightChanges(m.chatInput, m.noteInput, m.approvalInput, m.recentsReplyInput)
+	if m.tilesReplyInput != nil {
+		m.tilesReplyInput.SetConfig(cfg)
+	}
+	inputHeightChanged := m.consumeInputHeightChanges(m.chatInput, m.noteInput, m.approvalInput, m.finalizeInput, m.recentsReplyInput, m.tilesReplyInput)
 	if inputHeightChanged && m.width > 0 && m.height > 0 {
 		m.resize(m.width, m.height)
 		return
@@ -1106,6 +1200,7 @@ func (m *Model) resize(width, height int) {
 	m.resizeWithoutRender(width, height)
 	m.renderViewport()
 	m.renderNotesPanel()
+	m.renderDiffPanel()
 }
 
 func (m *Model) resizeWithoutRender(width, height int) {
@@ -1139,6 +1234,9 @@ func (m *Model) resizeWithoutRender(width, height int) {
 		m.contextPanelVisible = false
 		m.contextPanelWidth = 0
 		m.contextPanelMainWidth = layout.panelMain
+		m.diffPanelVisible = false
+		m.diffPanelWidth = 0
+		m.diffPanelMainWidth = layout.panelMain
 	case sidePanelModeContext:
 		m.contextPanelVisible = layout.panelVisible
 		m.contextPanelWidth = layout.panelWidth
@@ -1149,6 +1247,22 @@ func (m *Model) resizeWithoutRender(width, height int) {
 		m.debugPanelVisible = false
 		m.debugPanelWidth = 0
 		m.debugPanelMainWidth = layout.panelMain
+		m.diffPanelVisible = false
+		m.diffPanelWidth = 0
+		m.diffPanelMainWidth = layout.panelMain
+	case sidePanelModeDiff:
+		m.diffPanelVisible = layout.panelVisible
+		m.diffPanelWidth = layout.panelWidth
+		m.diffPanelMainWidth = layout.panelMain
+		m.notesPanelVisible = false
+		m.notesPanelWidth = 0
+		m.notesPanelMainWidth = layout.panelMain
+		m.debugPanelVisible = false
+		m.debugPanelWidth = 0
+		m.debugPanelMainWidth = layout.panelMain
+		m.contextPanelVisible = false
+		m.contextPanelWidth = 0
+		m.contextPanelMainWidth = layout.panelMain
 	default:
 		m.notesPanelVisible = layout.panelVisible
 		m.notesPanelWidth = layout.panelWidth
@@ -1159,6 +1273,9 @@ func (m *Model) resizeWithoutRender(width, height int) {
 		m.contextPanelVisible = false
 		m.contextPanelWidth = 0
 		m.contextPanelMainWidth = layout.panelMain
+		m.diffPanelVisible = false
+		m.diffPanelWidth = 0
+		m.diffPanelMainWidth = layout.panelMain
 	}
 
 	if m.sidebar != nil {
@@ -1172,6 +1289,13 @@ func (m *Model) resizeWithoutRender(width, height int) {
 		m.notesPanelViewport.SetWidth(0)
 		m.notesPanelViewport.SetHeight(0)
 	}
+	if panelMode == sidePanelModeDiff && layout.panelVisible {
+		m.diffPanelViewport.SetWidth(layout.panelWidth)
+		m.diffPanelViewport.SetHeight(max(1, contentHeight-1))
+	} else {
+		m.diffPanelViewport.SetWidth(0)
+		m.diffPanelViewport.SetHeight(0)
+	}
 	if m.debugPanel != nil {
 		if panelMode == sidePanelModeDebug && layout.panelVisible {
 			m.debugPanel.Resize(layout.panelWidth, max(1, contentHeight-1))
@@ -1221,6 +1345,9 @@ func (m *Model) resizeWithoutRender(width, height int) {
 	}
 	if m.composeFileSearch != nil {
 		m.composeFileSearch.SetSize(mainViewportWidth, 8)
+		m.composeMention.SetSize(mainViewportWidth, 8)
+		m.composeSnippet.SetSize(mainViewportWidth, 8)
+		m.composeSlash.SetSize(mainViewportWidth, 8)
 	}
 	if m.chatInput != nil {
 		m.chatInput.Resize(mainViewportWidth)
@@ -1237,9 +1364,18 @@ func (m *Model) resizeWithoutRender(width, height int) {
 	if m.noteInput != nil {
 		m.noteInput.Resize(mainViewportWidth)
 	}
+	if m.finalizeInput != nil {
+		m.finalizeInput.Resize(mainViewportWidth)
+	}
+	if m.approvalRulesInput != nil {
+		m.approvalRulesInput.Resize(mainViewportWidth)
+	}
 	if m.recentsReplyInput != nil {
 		m.recentsReplyInput.Resize(mainViewportWidth)
 	}
+	if m.tilesReplyInput != nil {
+		m.tilesReplyInput.Resize(mainViewportWidth)
+	}
 	extraLines := 0
 	if inputLines := m.modeInputLineCount(); inputLines > 0 {
 		extraLines = inputLines + 1
@@ -1391,6 +1527,9 @@ func (m *Model) applySelectionState(item *sidebarItem) (handled bool, stateChang
 	if m.mode == uiModeRecents {
 		m.exitRecentsView()
 	}
+	if m.mode == uiModeTiles {
+		m.exitSessionTilesView()
+	}
 	if wsID := item.workspaceID(); wsID != "" && wsID != unassignedWorkspaceID && wsID != m.appState.ActiveWorkspaceID {
 		m.appState.ActiveWorkspaceID = wsID
 		m.hasAppState = true
@@ -2434,6 +2573,13 @@ func (m *Model) handleConfirmChoice(choice confirmChoice) tea.Cmd {
 			}
 			m.setStatusMessage("deleting worktree")
 			return deleteWorktreeCmd(m.workspaceAPI, action.workspaceID, action.worktreeID)
+		case confirmRemoveWorktree:
+			if action.workspaceID == "" || action.worktreeID == "" {
+				m.setValidationStatus("select a worktree to remove")
+				return nil
+			}
+			m.setStatusMessage("removing worktree")
+			return worktreeOperationCmd(m.worktreeLifecycleAPI, action.workspaceID, action.worktreeID, types.WorktreeOperationRemove, types.WorktreeOperationRequest{})
 		case confirmDeleteNote:
 			if strings.TrimSpace(action.noteID) == "" {
 				m.setValidationStatus("select a note to delete")
@@ -2452,6 +2598,13 @@ func (m *Model) handleConfirmChoice(choice confirmChoice) tea.Cmd {
 			}
 			m.setStatusMessage(fmt.Sprintf("dismissing %d sessions", len(action.sessionIDs)))
 			return dismissManySessionsCmd(m.sessionAPI, action.sessionIDs)
+		case confirmRollbackTurn:
+			if len(action.sessionIDs) != 1 || action.turnID == "" {
+				m.setValidationStatus("select a message with a turn to roll back")
+				return nil
+			}
+			m.setStatusMessage("rolling back working tree")
+			return restoreTurnCheckpointCmd(m.checkpointAPI, action.sessionIDs[0], action.turnID)
 		}
 		m.setStatusMessage("confirmed")
 		return nil
@@ -2572,6 +2725,33 @@ func (m *Model) confirmDeleteWorktree(workspaceID, worktreeID string) {
 	m.confirm.Open("Delete Worktree", message, "Delete", "Cancel")
 }
 
+func (m *Model) confirmRemoveWorktree(workspaceID, worktreeID string) {
+	if m.confirm == nil {
+		return
+	}
+	name := ""
+	if wt := m.worktreeByID(worktreeID); wt != nil {
+		name = wt.Name
+	}
+	message := "Remove worktree checkout from disk?"
+	if strings.TrimSpace(name) != "" {
+		message = fmt.Sprintf("Remove worktree %q from disk?", name)
+	}
+	m.pendingConfirm = confirmAction{
+		kind:        confirmRemoveWorktree,
+		workspaceID: workspaceID,
+		worktreeID:  worktreeID,
+	}
+	m.pendingSelectionAction = nil
+	if m.menu != nil {
+		m.menu.CloseAll()
+	}
+	if m.contextMenu != nil {
+		m.contextMenu.Close()
+	}
+	m.confirm.Open("Remove Worktree", message, "Remove", "Cancel")
+}
+
 func (m *Model) confirmDeleteNote(noteID string) {
 	if m.confirm == nil {
 		return
@@ -2680,6 +2860,9 @@ func (m *Model) handleTick(msg tickMsg) tea.Cmd {
 	if cmd := m.consumeTranscriptTick(now); cmd != nil {
 		cmds = append(cmds, cmd)
 	}
+	if cmd := m.consumeSessionTilesTick(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
 	if cmd := m.consumeDebugTick(now); cmd != nil {
 		cmds = append(cmds, cmd)
 	}
@@ -2861,6 +3044,14 @@ func (m *Model) consumeTranscriptTick(now time.Time) tea.Cmd {
 			cmds = append(cmds, cmd)
 		}
 	}
+	if len(tickSignals.CompletionSignals) > 0 {
+		if cmd := m.refreshDiffPanelForSession(sessionID); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		if cmd := m.refreshContextPanelUsage(sessionID); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
 	if cmd := m.maybeRecoverTranscriptFromRevisionRewind(now, sessionID, provider, tickSignals); cmd != nil {
 		cmds = append(cmds, cmd)
 	}
@@ -3750,6 +3941,9 @@ func (m *Model) handleViewportScroll(msg tea.KeyMsg) bool {
 	if m.handleDebugPanelScrollKey(msg) {
 		return true
 	}
+	if m.handleDiffPanelKey(msg) {
+		return true
+	}
 	wasFollowing := m.follow
 	scrolledDown := false
 	switch msg.String() {
@@ -4064,6 +4258,15 @@ func (m *Model) handleMouse(msg tea.MouseMsg) bool {
 	if m.reduceSidebarScrollbarLeftPressMouse(msg, layout) {
 		return true
 	}
+	if m.reduceComposeSnippetLeftPressMouse(msg) {
+		return true
+	}
+	if m.reduceComposeSlashLeftPressMouse(msg) {
+		return true
+	}
+	if m.reduceComposeMentionLeftPressMouse(msg) {
+		return true
+	}
 	if m.reduceComposeFileSearchLeftPressMouse(msg, layout) {
 		return true
 	}
@@ -4082,6 +4285,9 @@ func (m *Model) handleMouse(msg tea.MouseMsg) bool {
 	if m.reduceNotesPanelLeftPressMouse(msg, layout) {
 		return true
 	}
+	if m.reduceSessionTilesLeftPressMouse(msg, layout) {
+		return true
+	}
 	if m.reduceRecentsControlsLeftPressMouse(msg, layout) {
 		return true
 	}
@@ -4156,7 +4362,7 @@ func (m *Model) currentBlocks() []ChatBlock {
 }
 
 func (m *Model) transcriptViewportVisible() bool {
-	return m.mode != uiModeNotes && m.mode != uiModeAddNote && m.mode != uiModeRecents
+	return m.mode != uiModeNotes && m.mode != uiModeAddNote && m.mode != uiModeRecents && m.mode != uiModeTiles
 }
 
 func (m *Model) cacheTranscriptBlocks(key string, blocks []ChatBlock) {
@@ -4389,6 +4595,7 @@ func (m *Model) applyAppState(state *types.AppState) {
 	m.appState.NotesPanelWidth = max(0, m.appState.NotesPanelWidth)
 	m.appState.DebugPanelWidth = max(0, m.appState.DebugPanelWidth)
 	m.appState.ContextPanelWidth = max(0, m.appState.ContextPanelWidth)
+	m.appState.DiffPanelWidth = max(0, m.appState.DiffPanelWidth)
 	m.sidebarSort = m.sidebarSortPolicyOrDefault().Normalize(sidebarSortState{
 		Key:     parseSidebarSortKey(state.SidebarSortKey),
 		Reverse: state.SidebarSortReverse,
@@ -4398,6 +4605,9 @@ func (m *Model) applyAppState(state *types.AppState) {
 	m.composeHistory = importComposeHistory(state.ComposeHistory)
 	m.composeDrafts = importDraftMap(state.ComposeDrafts, composeHistoryMaxSessions)
 	m.noteDrafts = importDraftMap(state.NoteDrafts, composeHistoryMaxSessions)
+	if m.sessionTiles != nil && m.mode != uiModeTiles {
+		m.sessionTiles.ImportAppState(state.Tiles)
+	}
 	m.hasAppState = true
 	if m.menu != nil {
 		if state.ActiveWorkspaceGroupIDs == nil {
@@ -4542,6 +4752,7 @@ func (m *Model) saveAppStateCmd() tea.Cmd {
 	m.syncAppStateRecents()
 	m.syncAppStateSidebarSort()
 	m.syncAppStateSplitLayout()
+	m.syncAppStateSessionTiles()
 	m.appStateSaveSeq++
 	requestSeq := m.appStateSaveSeq
 	state := m.appState
@@ -5082,6 +5293,11 @@ func (m *Model) enterCompose(sessionID string) {
 		m.compose.Enter(sessionID, label)
 	}
 	m.resetComposeHistoryCursor()
+	m.composeAttachments = nil
+	m.composeMentions = nil
+	m.composeMention.Close()
+	m.composeSnippet.Close()
+	m.composeSlash.Close()
 	if m.chatInput != nil {
 		m.chatInput.SetPlaceholder("message")
 		m.restoreComposeDraft(sessionID)
@@ -5112,6 +5328,11 @@ func (m *Model) exitCompose(status string) {
 			m.closeComposeOptionPicker()
 			m.composeFileSearchCloserOrDefault().CloseAsync(m.composeFileSearchServiceOrDefault(), m.resetComposeFileSearch())
 			m.composeInterruptInFlightSessionID = ""
+			m.composeAttachments = nil
+			m.composeMentions = nil
+			m.composeMention.Close()
+			m.composeSnippet.Close()
+			m.composeSlash.Close()
 			if m.compose != nil {
 				m.compose.Exit()
 			}
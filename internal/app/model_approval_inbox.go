@@ -0,0 +1,361 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"control/internal/daemon/transcriptdomain"
+	"control/internal/types"
+)
+
+const approvalInboxTimeout = 10 * time.Second
+
+func (m *Model) enterApprovalInbox() tea.Cmd {
+	if m.approvalInboxAPI == nil {
+		m.setValidationStatus("approval inbox is unavailable")
+		return nil
+	}
+	m.approvalInboxLoading = true
+	m.approvalInboxCursor = 0
+	m.approvalInboxMarked = map[string]bool{}
+	m.mode = uiModeApprovalInbox
+	m.setStatusMessage("loading approval inbox")
+	m.resize(m.width, m.height)
+	return tea.Batch(fetchApprovalInboxCmd(m.approvalInboxAPI), openApprovalInboxStreamCmd(m.approvalInboxAPI))
+}
+
+func (m *Model) exitApprovalInbox(status string) {
+	targetFocus := focusSidebar
+	m.applyModeTransition(modeTransitionRequest{
+		toMode:      uiModeNormal,
+		status:      status,
+		focus:       &targetFocus,
+		forceReflow: true,
+		before: func() {
+			m.closeApprovalInboxStream()
+			m.approvalInbox = nil
+			m.approvalInboxMarked = nil
+			m.approvalInboxCursor = 0
+			m.approvalInboxLoading = false
+		},
+	})
+}
+
+func (m *Model) closeApprovalInboxStream() {
+	if m.approvalInboxStreamCancel != nil {
+		m.approvalInboxStreamCancel()
+	}
+	m.approvalInboxStreamCancel = nil
+}
+
+func (m *Model) applyApprovalInboxLoaded(msg approvalInboxLoadedMsg) {
+	m.approvalInboxLoading = false
+	if m.mode != uiModeApprovalInbox {
+		return
+	}
+	if msg.err != nil {
+		m.setStatusError("approval inbox error: " + msg.err.Error())
+		return
+	}
+	m.approvalInbox = msg.approvals
+	// Drop marks for requests that were resolved elsewhere.
+	present := map[string]bool{}
+	for _, approval := range msg.approvals {
+		if approval != nil {
+			present[approvalInboxKey(approval)] = true
+		}
+	}
+	for key := range m.approvalInboxMarked {
+		if !present[key] {
+			delete(m.approvalInboxMarked, key)
+		}
+	}
+	m.clampApprovalInboxCursor()
+	m.setStatusMessage(fmt.Sprintf("%d pending approval(s)", len(msg.approvals)))
+}
+
+func (m *Model) applyApprovalInboxStream(msg approvalInboxStreamMsg) tea.Cmd {
+	if msg.err != nil {
+		if m.mode == uiModeApprovalInbox {
+			m.setStatusError("approval stream error: " + msg.err.Error() + " (r refreshes)")
+		}
+		return nil
+	}
+	if m.mode != uiModeApprovalInbox {
+		if msg.cancel != nil {
+			msg.cancel()
+		}
+		return nil
+	}
+	m.closeApprovalInboxStream()
+	m.approvalInboxStreamCancel = msg.cancel
+	return waitApprovalInboxEventCmd(msg.ch)
+}
+
+func (m *Model) applyApprovalInboxEvent(msg approvalInboxEventMsg) tea.Cmd {
+	if msg.closed || m.mode != uiModeApprovalInbox {
+		return nil
+	}
+	return tea.Batch(fetchApprovalInboxCmd(m.approvalInboxAPI), waitApprovalInboxEventCmd(msg.ch))
+}
+
+// removeApprovalInboxEntry drops an answered request right away so the list
+// does not depend on the stream to catch up.
+func (m *Model) removeApprovalInboxEntry(sessionID string, requestID int) {
+	if m.mode != uiModeApprovalInbox {
+		return
+	}
+	kept := m.approvalInbox[:0]
+	for _, approval := range m.approvalInbox {
+		if approval != nil && approval.SessionID == sessionID && approval.RequestID == requestID {
+			delete(m.approvalInboxMarked, approvalInboxKey(approval))
+			continue
+		}
+		kept = append(kept, approval)
+	}
+	m.approvalInbox = kept
+	m.clampApprovalInboxCursor()
+}
+
+func (m *Model) reduceApprovalInboxMode(msg tea.Msg) (bool, tea.Cmd) {
+	if m.mode != uiModeApprovalInbox {
+		return false, nil
+	}
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return false, nil
+	}
+	switch m.keyString(keyMsg) {
+	case "esc":
+		m.exitApprovalInbox("")
+		return true, nil
+	case "j", "down":
+		m.moveApprovalInboxCursor(1)
+		return true, nil
+	case "k", "up":
+		m.moveApprovalInboxCursor(-1)
+		return true, nil
+	case " ", "space":
+		approval := m.selectedApprovalInboxEntry()
+		if approval == nil {
+			m.setValidationStatus("no approval selected")
+			return true, nil
+		}
+		key := approvalInboxKey(approval)
+		if m.approvalInboxMarked[key] {
+			delete(m.approvalInboxMarked, key)
+		} else {
+			m.approvalInboxMarked[key] = true
+		}
+		m.moveApprovalInboxCursor(1)
+		return true, nil
+	case "a":
+		if len(m.approvalInboxMarked) > 0 {
+			m.approvalInboxMarked = map[string]bool{}
+			return true, nil
+		}
+		for _, approval := range m.approvalInbox {
+			if approval != nil {
+				m.approvalInboxMarked[approvalInboxKey(approval)] = true
+			}
+		}
+		return true, nil
+	case "y":
+		return true, m.respondApprovalInbox("accept")
+	case "x", "n":
+		return true, m.respondApprovalInbox("decline")
+	case "r":
+		m.approvalInboxLoading = true
+		m.setStatusMessage("refreshing approval inbox")
+		return true, fetchApprovalInboxCmd(m.approvalInboxAPI)
+	case "enter":
+		return true, m.openApprovalInboxSession()
+	default:
+		return false, nil
+	}
+}
+
+// respondApprovalInbox answers the marked requests, or the selected one when
+// nothing is marked. Requests that need a typed response are left for the
+// session view.
+func (m *Model) respondApprovalInbox(decision string) tea.Cmd {
+	targets := m.approvalInboxTargets()
+	if len(targets) == 0 {
+		m.setValidationStatus("no approval selected")
+		return nil
+	}
+	cmds := make([]tea.Cmd, 0, len(targets))
+	skipped := 0
+	for _, approval := range targets {
+		if decision == "accept" && approvalRequestNeedsResponse(approvalFromRecord(&approval.Approval)) {
+			skipped++
+			continue
+		}
+		cmds = append(cmds, approveSessionCmd(m.sessionAPI, approval.SessionID, approval.RequestID, decision, nil))
+	}
+	verb := "approving"
+	if decision != "accept" {
+		verb = "declining"
+	}
+	status := fmt.Sprintf("%s %d request(s)", verb, len(cmds))
+	if skipped > 0 {
+		status += fmt.Sprintf("; %d need a response, press enter to answer in the session", skipped)
+	}
+	m.setStatusMessage(status)
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) approvalInboxTargets() []*types.PendingApproval {
+	if len(m.approvalInboxMarked) == 0 {
+		if approval := m.selectedApprovalInboxEntry(); approval != nil {
+			return []*types.PendingApproval{approval}
+		}
+		return nil
+	}
+	targets := []*types.PendingApproval{}
+	for _, approval := range m.approvalInbox {
+		if approval != nil && m.approvalInboxMarked[approvalInboxKey(approval)] {
+			targets = append(targets, approval)
+		}
+	}
+	return targets
+}
+
+func (m *Model) openApprovalInboxSession() tea.Cmd {
+	approval := m.selectedApprovalInboxEntry()
+	if approval == nil {
+		m.setValidationStatus("no approval selected")
+		return nil
+	}
+	if m.sidebar == nil || !m.sidebar.SelectBySessionID(approval.SessionID) {
+		m.setValidationStatus("session unavailable")
+		return nil
+	}
+	m.exitApprovalInbox("")
+	return m.onSelectionChangedImmediate()
+}
+
+func (m *Model) selectedApprovalInboxEntry() *types.PendingApproval {
+	if m.approvalInboxCursor < 0 || m.approvalInboxCursor >= len(m.approvalInbox) {
+		return nil
+	}
+	return m.approvalInbox[m.approvalInboxCursor]
+}
+
+func (m *Model) moveApprovalInboxCursor(delta int) {
+	m.approvalInboxCursor += delta
+	m.clampApprovalInboxCursor()
+}
+
+func (m *Model) clampApprovalInboxCursor() {
+	if m.approvalInboxCursor >= len(m.approvalInbox) {
+		m.approvalInboxCursor = len(m.approvalInbox) - 1
+	}
+	if m.approvalInboxCursor < 0 {
+		m.approvalInboxCursor = 0
+	}
+}
+
+func (m *Model) approvalInboxHeader() string {
+	if len(m.approvalInboxMarked) > 0 {
+		return fmt.Sprintf("Approval Inbox (%d, %d marked)", len(m.approvalInbox), len(m.approvalInboxMarked))
+	}
+	return fmt.Sprintf("Approval Inbox (%d)", len(m.approvalInbox))
+}
+
+func (m *Model) approvalInboxBody() string {
+	lines := []string{}
+	switch {
+	case m.approvalInboxLoading && len(m.approvalInbox) == 0:
+		lines = append(lines, "Loading pending approvals...")
+	case len(m.approvalInbox) == 0:
+		lines = append(lines, "No pending approvals.")
+	default:
+		now := time.Now()
+		for i, approval := range m.approvalInbox {
+			if approval == nil {
+				continue
+			}
+			cursor := "  "
+			if i == m.approvalInboxCursor {
+				cursor = "> "
+			}
+			mark := "[ ]"
+			if m.approvalInboxMarked[approvalInboxKey(approval)] {
+				mark = "[x]"
+			}
+			session := approvalInboxField(approval.SessionTitle, approval.SessionID)
+			workspace := approvalInboxField(approval.WorkspaceName, approval.WorkspaceID, "unassigned")
+			lines = append(lines,
+				fmt.Sprintf("%s%s %-9s %-16s %-24s %s", cursor, mark, approvalInboxAge(now, approval.CreatedAt), approvalInboxField(approval.Provider, "-"), truncateText(workspace, 24), session),
+				"        "+truncateText(approvalInboxField(approval.Preview, approval.Method), 96),
+			)
+		}
+	}
+	lines = append(lines,
+		"",
+		"j/k move  space mark  a mark all  y approve  x decline  enter open session  r refresh  esc close",
+	)
+	return strings.Join(lines, "\n")
+}
+
+func approvalInboxKey(approval *types.PendingApproval) string {
+	return approval.SessionID + ":" + strconv.Itoa(approval.RequestID)
+}
+
+func approvalInboxAge(now, createdAt time.Time) string {
+	if createdAt.IsZero() {
+		return "-"
+	}
+	age := now.Sub(createdAt)
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age/time.Minute))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age/time.Hour))
+	default:
+		return fmt.Sprintf("%dd ago", int(age/(24*time.Hour)))
+	}
+}
+
+func fetchApprovalInboxCmd(api ApprovalInboxAPI) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), approvalInboxTimeout)
+		defer cancel()
+		approvals, err := api.ListAllApprovals(ctx)
+		return approvalInboxLoadedMsg{approvals: approvals, err: err}
+	}
+}
+
+func openApprovalInboxStreamCmd(api ApprovalInboxAPI) tea.Cmd {
+	return func() tea.Msg {
+		ch, cancel, err := api.ApprovalStream(context.Background())
+		return approvalInboxStreamMsg{ch: ch, cancel: cancel, err: err}
+	}
+}
+
+func waitApprovalInboxEventCmd(ch <-chan transcriptdomain.TranscriptEvent) tea.Cmd {
+	if ch == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		event, ok := <-ch
+		return approvalInboxEventMsg{ch: ch, event: event, closed: !ok}
+	}
+}
+
+func approvalInboxField(values ...string) string {
+	for _, value := range values {
+		if trimmed := strings.TrimSpace(value); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
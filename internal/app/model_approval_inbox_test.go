@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"control/internal/client"
+	"control/internal/daemon/transcriptdomain"
+	"control/internal/types"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+type stubApprovalInboxAPI struct {
+	approvals []*types.PendingApproval
+	events    chan transcriptdomain.TranscriptEvent
+	canceled  bool
+}
+
+func (s *stubApprovalInboxAPI) ListAllApprovals(context.Context) ([]*types.PendingApproval, error) {
+	return s.approvals, nil
+}
+
+func (s *stubApprovalInboxAPI) ApprovalStream(context.Context) (<-chan transcriptdomain.TranscriptEvent, func(), error) {
+	return s.events, func() { s.canceled = true }, nil
+}
+
+type approvalInboxSessionAPI struct {
+	*stubInterruptSessionAPI
+	approved []string
+}
+
+func (s *approvalInboxSessionAPI) ApproveSession(_ context.Context, id string, req client.ApproveSessionRequest) error {
+	s.approved = append(s.approved, id+":"+req.Decision)
+	return nil
+}
+
+func TestApprovalInboxBulkApprovesAndOpensSession(t *testing.T) {
+	m := newPhase0ModelWithSession("codex")
+	m.resize(120, 40)
+	sessionAPI := &approvalInboxSessionAPI{stubInterruptSessionAPI: &stubInterruptSessionAPI{}}
+	m.sessionAPI = sessionAPI
+	userInput, _ := json.Marshal(map[string]any{"questions": []any{map[string]any{"id": "q1", "question": "Which?"}}})
+	api := &stubApprovalInboxAPI{
+		events: make(chan transcriptdomain.TranscriptEvent, 1),
+		approvals: []*types.PendingApproval{
+			{Approval: types.Approval{SessionID: "s1", RequestID: 1, Method: "item/commandExecution/requestApproval", CreatedAt: time.Now().Add(-3 * time.Minute)}, Provider: "codex", WorkspaceName: "Workspace", Preview: "go test ./..."},
+			{Approval: types.Approval{SessionID: "s2", RequestID: 2, Method: "item/fileChange/requestApproval", CreatedAt: time.Now()}, Provider: "claude", Preview: "README.md"},
+			{Approval: types.Approval{SessionID: "s3", RequestID: 3, Method: "tool/requestUserInput", Params: userInput}, Provider: "codex"},
+		},
+	}
+	m.approvalInboxAPI = api
+
+	m.Update(tea.KeyPressMsg{Code: 'A', Text: "A"})
+	if m.mode != uiModeApprovalInbox {
+		t.Fatalf("expected approval inbox mode, got %v", m.mode)
+	}
+	m.Update(fetchApprovalInboxCmd(api)())
+	m.Update(openApprovalInboxStreamCmd(api)())
+	body := m.approvalInboxBody()
+	if !strings.Contains(body, "3m ago") || !strings.Contains(body, "codex") || !strings.Contains(body, "Workspace") || !strings.Contains(body, "go test ./...") || !strings.Contains(body, "README.md") {
+		t.Fatalf("unexpected inbox body %q", body)
+	}
+
+	m.Update(tea.KeyPressMsg{Code: 'a', Text: "a"})
+	_, cmd := m.Update(tea.KeyPressMsg{Code: 'y', Text: "y"})
+	if cmd == nil {
+		t.Fatalf("expected approve commands")
+	}
+	for _, msg := range collectCmdMsgs(cmd) {
+		m.Update(msg)
+	}
+	if strings.Join(sessionAPI.approved, ",") != "s1:accept,s2:accept" {
+		t.Fatalf("expected the marked command and file approvals to be accepted, got %v", sessionAPI.approved)
+	}
+	if len(m.approvalInbox) != 1 || m.approvalInbox[0].SessionID != "s3" {
+		t.Fatalf("expected only the user input request to remain, got %#v", m.approvalInbox)
+	}
+
+	api.approvals = []*types.PendingApproval{
+		{Approval: types.Approval{SessionID: "s1", RequestID: 4, Method: "item/commandExecution/requestApproval"}, Provider: "codex"},
+	}
+	api.events <- transcriptdomain.TranscriptEvent{Kind: transcriptdomain.TranscriptEventApprovalPending, SessionID: "s1"}
+	_, cmd = m.Update(waitApprovalInboxEventCmd(api.events)())
+	// Closing the stream lets the batched wait for the next event return.
+	close(api.events)
+	for _, msg := range collectCmdMsgs(cmd) {
+		if _, ok := msg.(approvalInboxLoadedMsg); ok {
+			m.Update(msg)
+		}
+	}
+	if len(m.approvalInbox) != 1 || m.approvalInbox[0].RequestID != 4 {
+		t.Fatalf("expected a stream event to refresh the inbox, got %#v", m.approvalInbox)
+	}
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if m.mode != uiModeNormal || m.selectedSessionID() != "s1" || !api.canceled {
+		t.Fatalf("expected enter to open s1 and close the stream, got mode=%v session=%q canceled=%v", m.mode, m.selectedSessionID(), api.canceled)
+	}
+}
+
+func collectCmdMsgs(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		return []tea.Msg{msg}
+	}
+	msgs := []tea.Msg{}
+	for _, next := range batch {
+		msgs = append(msgs, collectCmdMsgs(next)...)
+	}
+	return msgs
+}
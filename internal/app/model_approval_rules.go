@@ -0,0 +1,407 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+
+	"control/internal/types"
+)
+
+const approvalRulesTimeout = 10 * time.Second
+
+func (m *Model) enterApprovalRules() tea.Cmd {
+	if m.approvalRulesAPI == nil {
+		m.setValidationStatus("approval rules are unavailable")
+		return nil
+	}
+	if m.approvalRulesInput != nil {
+		m.approvalRulesInput.SetPlaceholder("add allow_once method=command prefix=\"go test\"")
+		m.approvalRulesInput.SetValue("")
+		m.approvalRulesInput.Focus()
+	}
+	if m.input != nil {
+		m.input.FocusChatInput()
+	}
+	m.approvalRulesLoading = true
+	m.mode = uiModeApprovalRules
+	m.setStatusMessage("loading approval rules")
+	m.resize(m.width, m.height)
+	return fetchApprovalRulesCmd(m.approvalRulesAPI)
+}
+
+func (m *Model) exitApprovalRules(status string) {
+	targetFocus := focusSidebar
+	m.applyModeTransition(modeTransitionRequest{
+		toMode:      uiModeNormal,
+		status:      status,
+		focus:       &targetFocus,
+		forceReflow: true,
+		before: func() {
+			m.approvalRules = nil
+			m.approvalRulesLoading = false
+			if m.approvalRulesInput != nil {
+				m.approvalRulesInput.Blur()
+				m.approvalRulesInput.SetPlaceholder("")
+				m.approvalRulesInput.SetValue("")
+			}
+		},
+	})
+}
+
+func (m *Model) cancelApprovalRulesInput() tea.Cmd {
+	m.exitApprovalRules("")
+	return nil
+}
+
+func (m *Model) applyApprovalRulesLoaded(msg approvalRulesLoadedMsg) {
+	m.approvalRulesLoading = false
+	if msg.err != nil {
+		m.setStatusError("approval rules error: " + msg.err.Error())
+		return
+	}
+	if msg.status != "" {
+		m.setStatusInfo(msg.status)
+	}
+	if m.mode != uiModeApprovalRules {
+		return
+	}
+	m.approvalRules = msg.rules
+	if msg.status == "" {
+		m.setStatusMessage(fmt.Sprintf("%d approval rule(s)", len(msg.rules)))
+	}
+}
+
+// submitApprovalRulesInput runs one editor command:
+//
+//	add <decision> key=value...
+//	edit <n> key=value...
+//	toggle <n>
+//	delete <n>
+func (m *Model) submitApprovalRulesInput(text string) tea.Cmd {
+	fields, err := splitApprovalRuleCommand(text)
+	if err != nil {
+		m.setValidationStatus(err.Error())
+		return nil
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	var cmd tea.Cmd
+	switch strings.ToLower(fields[0]) {
+	case "add":
+		cmd, err = m.approvalRulesAdd(fields[1:])
+	case "edit":
+		cmd, err = m.approvalRulesEdit(fields[1:])
+	case "toggle":
+		cmd, err = m.approvalRulesToggle(fields[1:])
+	case "delete", "remove":
+		cmd, err = m.approvalRulesDelete(fields[1:])
+	default:
+		err = fmt.Errorf("unknown command %q: use add, edit, toggle or delete", fields[0])
+	}
+	if err != nil {
+		m.setValidationStatus(err.Error())
+		return nil
+	}
+	if m.approvalRulesInput != nil {
+		m.approvalRulesInput.SetValue("")
+	}
+	return cmd
+}
+
+func (m *Model) approvalRulesAdd(args []string) (tea.Cmd, error) {
+	if len(args) == 0 {
+		return nil, errors.New("add requires a decision: allow_once, allow_always, deny or ask")
+	}
+	rule := &types.ApprovalRule{Decision: types.ApprovalRuleDecision(args[0])}
+	if err := m.applyApprovalRuleFields(rule, args[1:]); err != nil {
+		return nil, err
+	}
+	api := m.approvalRulesAPI
+	return approvalRuleMutationCmd(api, func(ctx context.Context) (string, error) {
+		created, err := api.CreateApprovalRule(ctx, rule)
+		if err != nil {
+			return "", err
+		}
+		return "added approval rule " + types.ApprovalRuleLabel(created), nil
+	}), nil
+}
+
+func (m *Model) approvalRulesEdit(args []string) (tea.Cmd, error) {
+	rule, err := m.approvalRuleAt(args, "edit")
+	if err != nil {
+		return nil, err
+	}
+	if len(args) < 2 {
+		return nil, errors.New("edit requires key=value fields")
+	}
+	edited := *rule
+	edited.PathGlobs = append([]string(nil), rule.PathGlobs...)
+	if err := m.applyApprovalRuleFields(&edited, args[1:]); err != nil {
+		return nil, err
+	}
+	return m.approvalRuleUpdateCmd(&edited, "updated approval rule "+types.ApprovalRuleLabel(&edited)), nil
+}
+
+func (m *Model) approvalRulesToggle(args []string) (tea.Cmd, error) {
+	rule, err := m.approvalRuleAt(args, "toggle")
+	if err != nil {
+		return nil, err
+	}
+	toggled := *rule
+	toggled.Disabled = !rule.Disabled
+	status := "enabled approval rule "
+	if toggled.Disabled {
+		status = "disabled approval rule "
+	}
+	return m.approvalRuleUpdateCmd(&toggled, status+types.ApprovalRuleLabel(&toggled)), nil
+}
+
+func (m *Model) approvalRulesDelete(args []string) (tea.Cmd, error) {
+	rule, err := m.approvalRuleAt(args, "delete")
+	if err != nil {
+		return nil, err
+	}
+	api := m.approvalRulesAPI
+	return approvalRuleMutationCmd(api, func(ctx context.Context) (string, error) {
+		if err := api.DeleteApprovalRule(ctx, rule.ID); err != nil {
+			return "", err
+		}
+		return "deleted approval rule " + types.ApprovalRuleLabel(rule), nil
+	}), nil
+}
+
+func (m *Model) approvalRuleUpdateCmd(rule *types.ApprovalRule, status string) tea.Cmd {
+	api := m.approvalRulesAPI
+	return approvalRuleMutationCmd(api, func(ctx context.Context) (string, error) {
+		if _, err := api.UpdateApprovalRule(ctx, rule.ID, rule); err != nil {
+			return "", err
+		}
+		return status, nil
+	})
+}
+
+// approvalRuleAt returns the rule numbered args[0] in the editor list.
+func (m *Model) approvalRuleAt(args []string, command string) (*types.ApprovalRule, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%s requires a rule number", command)
+	}
+	index, err := strconv.Atoi(args[0])
+	if err != nil || index < 1 || index > len(m.approvalRules) || m.approvalRules[index-1] == nil {
+		return nil, fmt.Errorf("no approval rule %q", args[0])
+	}
+	return m.approvalRules[index-1], nil
+}
+
+// applyApprovalRuleFields sets rule fields from key=value arguments. A
+// scope without its id is filled from the selected session.
+func (m *Model) applyApprovalRuleFields(rule *types.ApprovalRule, args []string) error {
+	pathsSet := false
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return fmt.Errorf("expected key=value, got %q", arg)
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "name":
+			rule.Name = value
+		case "scope":
+			rule.Scope = types.ApprovalRuleScope(value)
+		case "workspace":
+			rule.WorkspaceID = value
+		case "provider":
+			rule.Provider = value
+		case "session":
+			rule.SessionID = value
+		case "method":
+			rule.Method = value
+		case "prefix":
+			rule.CommandPrefix = value
+		case "regex":
+			rule.CommandRegex = value
+		case "path":
+			if !pathsSet {
+				rule.PathGlobs = nil
+				pathsSet = true
+			}
+			if strings.TrimSpace(value) != "" {
+				rule.PathGlobs = append(rule.PathGlobs, value)
+			}
+		case "access":
+			rule.Access = types.AccessLevel(value)
+		case "decision":
+			rule.Decision = types.ApprovalRuleDecision(value)
+		default:
+			return fmt.Errorf("unknown field %q", key)
+		}
+	}
+	return m.fillApprovalRuleScope(rule)
+}
+
+func (m *Model) fillApprovalRuleScope(rule *types.ApprovalRule) error {
+	sessionID := m.selectedSessionID()
+	switch types.ApprovalRuleScope(strings.ToLower(strings.TrimSpace(string(rule.Scope)))) {
+	case types.ApprovalRuleScopeSession:
+		if strings.TrimSpace(rule.SessionID) == "" {
+			if sessionID == "" {
+				return errors.New("select a session for a session rule")
+			}
+			rule.SessionID = sessionID
+		}
+	case types.ApprovalRuleScopeWorkspace:
+		if strings.TrimSpace(rule.WorkspaceID) == "" {
+			meta := m.sessionMeta[sessionID]
+			if meta == nil || strings.TrimSpace(meta.WorkspaceID) == "" {
+				return errors.New("select a session in a workspace for a workspace rule")
+			}
+			rule.WorkspaceID = meta.WorkspaceID
+		}
+	case types.ApprovalRuleScopeProvider:
+		if strings.TrimSpace(rule.Provider) == "" {
+			session := m.sessionByID(sessionID)
+			if session == nil || strings.TrimSpace(session.Provider) == "" {
+				return errors.New("select a session for a provider rule")
+			}
+			rule.Provider = session.Provider
+		}
+	}
+	return nil
+}
+
+func (m *Model) approvalRulesBody() string {
+	lines := []string{}
+	switch {
+	case m.approvalRulesLoading && len(m.approvalRules) == 0:
+		lines = append(lines, "Loading approval rules...")
+	case len(m.approvalRules) == 0:
+		lines = append(lines, "No approval rules. Every approval request asks.")
+	default:
+		for i, rule := range m.approvalRules {
+			if rule == nil {
+				continue
+			}
+			line := fmt.Sprintf("%d. %-12s %s  %s", i+1, rule.Decision, approvalRuleScopeLabel(rule), approvalRuleMatchLabel(rule))
+			if rule.Name != "" {
+				line += "  (" + rule.Name + ")"
+			}
+			if rule.Disabled {
+				line += "  [disabled]"
+			}
+			lines = append(lines, line)
+		}
+	}
+	lines = append(lines,
+		"",
+		"Rules are checked session first, then workspace, provider and global; the first match decides.",
+		"",
+		"add <decision> key=value...   decision: allow_once, allow_always, deny, ask",
+		"edit <n> key=value...         keys: name scope workspace provider session",
+		"toggle <n>                          method prefix regex path access decision",
+		"delete <n>                    scope=workspace|session|provider uses the selected session",
+	)
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) approvalRulesFooter() string {
+	return "enter run  esc close"
+}
+
+func approvalRuleScopeLabel(rule *types.ApprovalRule) string {
+	switch rule.Scope {
+	case types.ApprovalRuleScopeWorkspace:
+		return "workspace:" + rule.WorkspaceID
+	case types.ApprovalRuleScopeProvider:
+		return "provider:" + rule.Provider
+	case types.ApprovalRuleScopeSession:
+		return "session:" + rule.SessionID
+	default:
+		return string(rule.Scope)
+	}
+}
+
+func approvalRuleMatchLabel(rule *types.ApprovalRule) string {
+	parts := []string{}
+	if rule.Method != "" {
+		parts = append(parts, "method="+rule.Method)
+	}
+	if rule.CommandPrefix != "" {
+		parts = append(parts, strconv.Quote(rule.CommandPrefix)+"…")
+	}
+	if rule.CommandRegex != "" {
+		parts = append(parts, "/"+rule.CommandRegex+"/")
+	}
+	if len(rule.PathGlobs) > 0 {
+		parts = append(parts, "path="+strings.Join(rule.PathGlobs, ","))
+	}
+	if rule.Access != "" {
+		parts = append(parts, "access="+string(rule.Access))
+	}
+	if len(parts) == 0 {
+		return "any request"
+	}
+	return strings.Join(parts, " ")
+}
+
+// splitApprovalRuleCommand splits text on spaces; double quotes group
+// words, so prefix="go test" is one argument.
+func splitApprovalRuleCommand(text string) ([]string, error) {
+	var (
+		fields  []string
+		current strings.Builder
+		quoted  bool
+		started bool
+	)
+	for _, r := range text {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			started = true
+		case r == ' ' && !quoted:
+			if started {
+				fields = append(fields, current.String())
+				current.Reset()
+				started = false
+			}
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+	if quoted {
+		return nil, errors.New("unterminated quote")
+	}
+	if started {
+		fields = append(fields, current.String())
+	}
+	return fields, nil
+}
+
+func fetchApprovalRulesCmd(api ApprovalRuleAPI) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), approvalRulesTimeout)
+		defer cancel()
+		rules, err := api.ListApprovalRules(ctx)
+		return approvalRulesLoadedMsg{rules: rules, err: err}
+	}
+}
+
+// approvalRuleMutationCmd runs change and reloads the rules so the editor
+// shows the stored, normalized result.
+func approvalRuleMutationCmd(api ApprovalRuleAPI, change func(ctx context.Context) (string, error)) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), approvalRulesTimeout)
+		defer cancel()
+		status, err := change(ctx)
+		if err != nil {
+			return approvalRulesLoadedMsg{err: err}
+		}
+		rules, err := api.ListApprovalRules(ctx)
+		return approvalRulesLoadedMsg{rules: rules, status: status, err: err}
+	}
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"control/internal/types"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+type stubApprovalRuleAPI struct {
+	rules   []*types.ApprovalRule
+	created []*types.ApprovalRule
+	updated []*types.ApprovalRule
+	deleted []string
+}
+
+func (s *stubApprovalRuleAPI) ListApprovalRules(context.Context) ([]*types.ApprovalRule, error) {
+	return s.rules, nil
+}
+
+func (s *stubApprovalRuleAPI) CreateApprovalRule(_ context.Context, rule *types.ApprovalRule) (*types.ApprovalRule, error) {
+	if err := types.ValidateApprovalRule(rule); err != nil {
+		return nil, err
+	}
+	created := *rule
+	created.ID = "rule_new"
+	s.created = append(s.created, rule)
+	s.rules = append(s.rules, &created)
+	return &created, nil
+}
+
+func (s *stubApprovalRuleAPI) UpdateApprovalRule(_ context.Context, id string, rule *types.ApprovalRule) (*types.ApprovalRule, error) {
+	s.updated = append(s.updated, rule)
+	for i, existing := range s.rules {
+		if existing.ID == id {
+			updated := *rule
+			s.rules[i] = &updated
+		}
+	}
+	return rule, nil
+}
+
+func (s *stubApprovalRuleAPI) DeleteApprovalRule(_ context.Context, id string) error {
+	s.deleted = append(s.deleted, id)
+	return nil
+}
+
+func TestApprovalRulesEditorAddsTogglesAndDeletes(t *testing.T) {
+	m := newPhase0ModelWithSession("opencode")
+	m.resize(120, 40)
+	if m.sidebar == nil || !m.sidebar.SelectBySessionID("s1") {
+		t.Fatalf("expected to select s1")
+	}
+	api := &stubApprovalRuleAPI{rules: []*types.ApprovalRule{{
+		ID:       "rule_1",
+		Scope:    types.ApprovalRuleScopeGlobal,
+		Method:   types.ApprovalKindFileChange,
+		Decision: types.ApprovalRuleDeny,
+	}}}
+	m.approvalRulesAPI = api
+
+	cmd := m.enterApprovalRules()
+	if m.mode != uiModeApprovalRules || cmd == nil {
+		t.Fatalf("expected approval rules mode with a fetch, got mode=%v", m.mode)
+	}
+	m.Update(cmd())
+	if body := m.approvalRulesBody(); !strings.Contains(body, "1. deny") || !strings.Contains(body, "method=file_change") {
+		t.Fatalf("unexpected rules body %q", body)
+	}
+
+	m.approvalRulesInput.SetValue(`add allow_once scope=workspace method=command prefix="go test" name=tests`)
+	_, cmd = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatalf("expected add to call the api")
+	}
+	m.Update(cmd())
+	if len(api.created) != 1 {
+		t.Fatalf("expected a created rule, got %#v", api.created)
+	}
+	created := api.created[0]
+	if created.WorkspaceID != "ws1" || created.CommandPrefix != "go test" || created.Name != "tests" {
+		t.Fatalf("expected the workspace filled from the selected session, got %#v", created)
+	}
+	if len(m.approvalRules) != 2 || m.approvalRulesInput.Value() != "" {
+		t.Fatalf("expected the list to reload and the input to clear, got %d rules input=%q", len(m.approvalRules), m.approvalRulesInput.Value())
+	}
+
+	m.approvalRulesInput.SetValue("toggle 1")
+	_, cmd = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m.Update(cmd())
+	if len(api.updated) != 1 || api.updated[0].ID != "rule_1" || !api.updated[0].Disabled {
+		t.Fatalf("expected rule 1 to be disabled, got %#v", api.updated)
+	}
+	if !strings.Contains(m.approvalRulesBody(), "[disabled]") {
+		t.Fatalf("expected disabled marker in body %q", m.approvalRulesBody())
+	}
+
+	m.approvalRulesInput.SetValue("delete 7")
+	if _, cmd = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter}); cmd != nil {
+		t.Fatalf("expected an out of range rule to be rejected")
+	}
+	m.approvalRulesInput.SetValue("delete 2")
+	_, cmd = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	m.Update(cmd())
+	if len(api.deleted) != 1 || api.deleted[0] != "rule_new" {
+		t.Fatalf("expected rule 2 to be deleted, got %#v", api.deleted)
+	}
+
+	m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	if m.mode != uiModeNormal || m.approvalRules != nil {
+		t.Fatalf("expected esc to close the editor, got mode=%v", m.mode)
+	}
+}
+
+func TestSplitApprovalRuleCommandGroupsQuotedWords(t *testing.T) {
+	fields, err := splitApprovalRuleCommand(`edit 2 prefix="npm run lint"  path=docs/**`)
+	if err != nil {
+		t.Fatalf("split: %v", err)
+	}
+	if len(fields) != 4 || fields[2] != "prefix=npm run lint" || fields[3] != "path=docs/**" {
+		t.Fatalf("unexpected fields %#v", fields)
+	}
+	if _, err := splitApprovalRuleCommand(`add deny prefix="rm`); err == nil {
+		t.Fatalf("expected an unterminated quote error")
+	}
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+)
+
+// confirmRollbackSelectedMessage asks to roll the session's working tree back
+// to the start of the selected message's turn.
+func (m *Model) confirmRollbackSelectedMessage() {
+	if m.messageSelectIndex < 0 || m.messageSelectIndex >= len(m.contentBlocks) {
+		m.setValidationStatus("no message selected")
+		return
+	}
+	sessionID := strings.TrimSpace(m.selectedSessionID())
+	if m.mode == uiModeCompose {
+		if composeID := strings.TrimSpace(m.composeSessionID()); composeID != "" {
+			sessionID = composeID
+		}
+	}
+	if sessionID == "" {
+		m.setValidationStatus("select a session to roll back")
+		return
+	}
+	turnID := m.turnIDForBlockIndex(m.messageSelectIndex)
+	if turnID == "" {
+		m.setValidationStatus("selected message has no turn to roll back to")
+		return
+	}
+	if m.confirm == nil {
+		return
+	}
+	m.pendingConfirm = confirmAction{
+		kind:       confirmRollbackTurn,
+		sessionIDs: []string{sessionID},
+		turnID:     turnID,
+	}
+	m.pendingSelectionAction = nil
+	if m.menu != nil {
+		m.menu.CloseAll()
+	}
+	if m.contextMenu != nil {
+		m.contextMenu.Close()
+	}
+	m.confirm.Open("Roll Back Turn", "Restore the working tree to the start of this turn? The current state is checkpointed first.", "Roll Back", "Cancel")
+}
+
+// turnIDForBlockIndex returns the turn a block belongs to. User messages are
+// often stored before the provider assigns a turn, so they take the turn of
+// the reply that follows them.
+func (m *Model) turnIDForBlockIndex(index int) string {
+	if index < 0 || index >= len(m.contentBlocks) {
+		return ""
+	}
+	if turnID := strings.TrimSpace(m.contentBlocks[index].TurnID); turnID != "" {
+		return turnID
+	}
+	if m.contentBlocks[index].Role != ChatRoleUser {
+		return ""
+	}
+	for _, block := range m.contentBlocks[index+1:] {
+		if block.Role == ChatRoleUser {
+			return ""
+		}
+		if turnID := strings.TrimSpace(block.TurnID); turnID != "" {
+			return turnID
+		}
+	}
+	return ""
+}
+
+func (m *Model) applyCheckpointRestoreResult(msg checkpointRestoreMsg) {
+	if msg.err != nil {
+		m.setStatusError("rollback error: " + msg.err.Error())
+		return
+	}
+	result := msg.result
+	if result == nil {
+		m.setStatusError("rollback error: empty response")
+		return
+	}
+	changed := len(result.Reverted) + len(result.Removed)
+	status := "working tree already matches the start of that turn"
+	if changed > 0 {
+		status = fmt.Sprintf("rolled back %d file(s): %s", changed, summarizeWorktreePaths(append(append([]string{}, result.Reverted...), result.Removed...)))
+	}
+	if result.NotifyError != "" {
+		m.setStatusWarning(status + "; notifying the session failed: " + result.NotifyError)
+		return
+	}
+	m.setStatusInfo(status)
+}
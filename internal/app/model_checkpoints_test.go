@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"control/internal/types"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+type stubSessionCheckpointAPI struct {
+	checkpoints []*types.SessionCheckpoint
+	restored    []string
+	requests    []types.SessionCheckpointRestoreRequest
+}
+
+func (s *stubSessionCheckpointAPI) ListSessionCheckpoints(context.Context, string) ([]*types.SessionCheckpoint, error) {
+	return s.checkpoints, nil
+}
+
+func (s *stubSessionCheckpointAPI) RestoreSessionCheckpoint(_ context.Context, _ string, checkpointID string, req types.SessionCheckpointRestoreRequest) (*types.SessionCheckpointRestoreResult, error) {
+	s.restored = append(s.restored, checkpointID)
+	s.requests = append(s.requests, req)
+	return &types.SessionCheckpointRestoreResult{
+		BackupID: "backup",
+		Reverted: []string{"main.go"},
+		Removed:  []string{"scratch.go"},
+		Notified: req.Notify,
+	}, nil
+}
+
+func TestMessageSelectionRollbackRestoresTurnCheckpointAfterConfirm(t *testing.T) {
+	m := newPhase0ModelWithSession("codex")
+	m.resize(120, 40)
+	api := &stubSessionCheckpointAPI{checkpoints: []*types.SessionCheckpoint{
+		{ID: "100", TurnID: "turn-a", Turn: 1},
+		{ID: "200", TurnID: "turn-b", Turn: 2},
+	}}
+	m.checkpointAPI = api
+	m.applyBlocks([]ChatBlock{
+		{Role: ChatRoleUser, Text: "first"},
+		{Role: ChatRoleAgent, Text: "done", TurnID: "turn-a"},
+		{Role: ChatRoleUser, Text: "second"},
+		{Role: ChatRoleAgent, Text: "done again", TurnID: "turn-b"},
+	})
+	if m.selectedSessionID() != "s1" {
+		t.Fatalf("expected session s1 to be selected, got %q", m.selectedSessionID())
+	}
+	m.enterMessageSelection()
+	m.setMessageSelectionIndex(2)
+
+	handled, cmd := m.reduceMessageSelectionKey(tea.KeyPressMsg{Text: "r"})
+	if !handled || cmd != nil {
+		t.Fatalf("expected rollback to wait for confirmation")
+	}
+	if m.pendingConfirm.kind != confirmRollbackTurn || m.pendingConfirm.turnID != "turn-b" {
+		t.Fatalf("unexpected pending confirm %#v", m.pendingConfirm)
+	}
+	if len(api.restored) != 0 {
+		t.Fatalf("expected no restore before confirmation")
+	}
+
+	cmd = m.handleConfirmChoice(confirmChoiceConfirm)
+	if cmd == nil {
+		t.Fatalf("expected restore command after confirmation")
+	}
+	msg, ok := cmd().(checkpointRestoreMsg)
+	if !ok {
+		t.Fatalf("expected checkpointRestoreMsg")
+	}
+	if len(api.restored) != 1 || api.restored[0] != "200" || !api.requests[0].Notify {
+		t.Fatalf("expected notifying restore of turn-b checkpoint, got %#v %#v", api.restored, api.requests)
+	}
+	m.applyCheckpointRestoreResult(msg)
+	if !strings.Contains(m.status, "rolled back 2 file(s): main.go, scratch.go") {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+}
+
+func TestMessageSelectionRollbackNeedsTurn(t *testing.T) {
+	m := newPhase0ModelWithSession("codex")
+	m.resize(120, 40)
+	m.applyBlocks([]ChatBlock{{Role: ChatRoleUser, Text: "pending"}})
+	m.enterMessageSelection()
+
+	m.reduceMessageSelectionKey(tea.KeyPressMsg{Text: "r"})
+	if m.pendingConfirm.kind != confirmNone {
+		t.Fatalf("expected no confirmation without a turn, got %#v", m.pendingConfirm)
+	}
+	if !strings.Contains(m.status, "no turn") {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+}
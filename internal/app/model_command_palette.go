@@ -0,0 +1,257 @@
+package app
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+var commandPaletteKeyCodes = map[string]rune{
+	"enter":     tea.KeyEnter,
+	"esc":       tea.KeyEscape,
+	"tab":       tea.KeyTab,
+	"space":     tea.KeySpace,
+	"backspace": tea.KeyBackspace,
+	"delete":    tea.KeyDelete,
+	"insert":    tea.KeyInsert,
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+	"home":      tea.KeyHome,
+	"end":       tea.KeyEnd,
+	"pgup":      tea.KeyPgUp,
+	"pgdown":    tea.KeyPgDown,
+	"f1":        tea.KeyF1,
+	"f2":        tea.KeyF2,
+	"f3":        tea.KeyF3,
+	"f4":        tea.KeyF4,
+	"f5":        tea.KeyF5,
+	"f6":        tea.KeyF6,
+	"f7":        tea.KeyF7,
+	"f8":        tea.KeyF8,
+	"f9":        tea.KeyF9,
+	"f10":       tea.KeyF10,
+	"f11":       tea.KeyF11,
+	"f12":       tea.KeyF12,
+}
+
+var commandPaletteKeyMods = map[string]tea.KeyMod{
+	"ctrl":  tea.ModCtrl,
+	"alt":   tea.ModAlt,
+	"shift": tea.ModShift,
+	"meta":  tea.ModMeta,
+	"super": tea.ModSuper,
+}
+
+func (m *Model) reduceCommandPalette(msg tea.Msg) (bool, tea.Cmd) {
+	if m.commandPalette == nil {
+		return false, nil
+	}
+	if !m.commandPalette.IsOpen() {
+		keyMsg, ok := msg.(tea.KeyMsg)
+		if !ok || !m.keyMatchesCommand(keyMsg, KeyCommandCommandPalette, "ctrl+p") {
+			return false, nil
+		}
+		if (m.confirm != nil && m.confirm.IsOpen()) || (m.contextMenu != nil && m.contextMenu.IsOpen()) {
+			return false, nil
+		}
+		if !m.commandAvailable(KeyCommandCommandPalette) {
+			return false, nil
+		}
+		m.commandPalette.Open(m.commandPaletteEntries())
+		return true, nil
+	}
+	switch msg.(type) {
+	case tea.KeyMsg, tea.PasteMsg:
+	case tea.MouseMsg:
+		return true, nil
+	default:
+		return false, nil
+	}
+	picker := m.commandPalette.Picker()
+	arbiter := newPickerKeyboardArbiter(m.keyString, m.keyMatchesCommand, m.pickerPasteNormalizer)
+	handled, cmd := arbiter.Handle(msg, picker, pickerKeyboardHooks{
+		Cancel: func() tea.Cmd {
+			if picker.ClearQuery() {
+				return nil
+			}
+			m.commandPalette.Close()
+			return nil
+		},
+		Confirm: func() tea.Cmd {
+			entry, ok := m.commandPalette.Selected()
+			if !ok {
+				m.setValidationStatus("no command selected")
+				return nil
+			}
+			return m.runCommandPaletteEntry(entry)
+		},
+		MoveUp:   func() { picker.Move(-1) },
+		MoveDown: func() { picker.Move(1) },
+		PageUp:   func() { picker.Move(-commandPaletteMaxRows) },
+		PageDown: func() { picker.Move(commandPaletteMaxRows) },
+	})
+	if handled {
+		return true, cmd
+	}
+	return true, nil
+}
+
+// runCommandPaletteEntry closes the palette and replays the command's
+// current key binding, so the command runs through the same reducers as
+// the hotkey.
+func (m *Model) runCommandPaletteEntry(entry commandPaletteEntry) tea.Cmd {
+	if !entry.Available {
+		m.setValidationStatus(entry.Title + " is not available here")
+		return nil
+	}
+	key, ok := keyPressForBinding(entry.Key)
+	if !ok {
+		m.setValidationStatus("cannot run " + entry.Title + ": unsupported key " + entry.Key)
+		return nil
+	}
+	m.commandPalette.RecordUse(entry.Command)
+	m.commandPalette.Close()
+	return func() tea.Msg { return key }
+}
+
+func (m *Model) commandPaletteEntries() []commandPaletteEntry {
+	commands := KnownKeybindingCommands()
+	entries := make([]commandPaletteEntry, 0, len(commands))
+	for _, command := range commands {
+		if command == KeyCommandCommandPalette || normalizeKeybindingCommand(command) != command {
+			continue
+		}
+		entries = append(entries, commandPaletteEntry{
+			Command:   command,
+			Title:     commandPaletteTitle(command),
+			Key:       m.keyForCommand(command, defaultKeybindingByCommand[command]),
+			Available: m.commandAvailable(command),
+		})
+	}
+	slices.SortStableFunc(entries, func(a, b commandPaletteEntry) int {
+		return strings.Compare(a.Title, b.Title)
+	})
+	return entries
+}
+
+// commandAvailable reports whether command is bound in one of the key scopes
+// of the current mode.
+func (m *Model) commandAvailable(command string) bool {
+	defaultKey := defaultKeybindingByCommand[command]
+	scopes := keybindingScopesFor(command, m.keyForCommand(command, defaultKey), defaultKey)
+	for _, scope := range m.activeKeyScopes() {
+		if slices.Contains(scopes, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// activeKeyScopes maps the current mode to the keybinding scopes used for
+// conflict detection.
+func (m *Model) activeKeyScopes() []string {
+	var scopes []string
+	switch m.mode {
+	case uiModeNormal:
+		scopes = append(scopes, keyScopeNormal)
+	case uiModeCompose:
+		if m.input != nil && m.input.IsChatFocused() {
+			scopes = append(scopes, keyScopeComposeInput)
+		} else {
+			scopes = append(scopes, keyScopeNormal)
+		}
+	case uiModeNotes:
+		scopes = append(scopes, keyScopeNotesMode)
+	case uiModeAddNote:
+		scopes = append(scopes, keyScopeAddNoteInput)
+	case uiModeSearch:
+		scopes = append(scopes, keyScopeSearchInput)
+	case uiModeApprovalResponse:
+		scopes = append(scopes, keyScopeApprovalResponseInput)
+	case uiModeRenameWorktree, uiModeRenameSession, uiModeRenameWorkflow:
+		scopes = append(scopes, keyScopeRenameInput)
+	case uiModeAddWorkspaceGroup, uiModeRenameWorkspaceGroup:
+		scopes = append(scopes, keyScopeWorkspaceGroupInput)
+	case uiModeAddWorkspace, uiModeEditWorkspace:
+		scopes = append(scopes, keyScopeAddWorkspaceInput)
+	case uiModeAddWorktree:
+		scopes = append(scopes, keyScopeAddWorktreeInput)
+	case uiModeRecents:
+		scopes = append(scopes, keyScopeNormal, keyScopeRecentsReplyInput)
+	case uiModeTiles:
+		if m.tilesReplySessionID != "" {
+			scopes = append(scopes, keyScopeRecentsReplyInput)
+		}
+	case uiModeGuidedWorkflow:
+		scopes = append(scopes, keyScopeGuidedWorkflowSetupInput)
+	}
+	if m.messageSelectActive {
+		scopes = append(scopes, keyScopeMessageSelect)
+	}
+	if m.pendingApproval != nil && (m.input == nil || m.input.IsSidebarFocused()) {
+		scopes = append(scopes, keyScopePendingApproval)
+	}
+	return scopes
+}
+
+// commandPaletteTitle turns a command id such as ui.toggleDebugStreams into
+// "Toggle debug streams".
+func commandPaletteTitle(command string) string {
+	name := strings.TrimPrefix(command, "ui.")
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) && i > 0 {
+			b.WriteByte(' ')
+			r = unicode.ToLower(r)
+		}
+		if i == 0 {
+			r = unicode.ToUpper(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// keyPressForBinding builds the key press a binding such as "ctrl+d", "G"
+// or "shift+pgup" stands for. It fails for keys that would not read back as
+// the same binding.
+func keyPressForBinding(binding string) (tea.KeyPressMsg, bool) {
+	binding = strings.TrimSpace(binding)
+	if binding == "" {
+		return tea.KeyPressMsg{}, false
+	}
+	var key tea.Key
+	name := binding
+	if utf8.RuneCountInString(binding) > 1 {
+		parts := strings.Split(binding, "+")
+		name = parts[len(parts)-1]
+		for _, part := range parts[:len(parts)-1] {
+			mod, ok := commandPaletteKeyMods[part]
+			if !ok {
+				return tea.KeyPressMsg{}, false
+			}
+			key.Mod |= mod
+		}
+	}
+	if code, ok := commandPaletteKeyCodes[name]; ok {
+		key.Code = code
+	} else if utf8.RuneCountInString(name) == 1 {
+		r, _ := utf8.DecodeRuneInString(name)
+		key.Code = unicode.ToLower(r)
+		if key.Mod == 0 {
+			key.Text = name
+		}
+	} else {
+		return tea.KeyPressMsg{}, false
+	}
+	msg := tea.KeyPressMsg(key)
+	if msg.String() != binding {
+		return tea.KeyPressMsg{}, false
+	}
+	return msg, true
+}
@@ -95,6 +95,21 @@ func (m *Model) handleWorkspaceContextMenuAction(action ContextMenuAction, targe
 		}
 		m.enterAddWorktree(target.id)
 		return true, nil
+	case ContextMenuWorkspaceToggleIsolation, ContextMenuWorkspaceToggleIsolatedCleanup:
+		workspace := m.workspaceByID(target.id)
+		if workspace == nil {
+			m.setValidationStatus("select a workspace")
+			return true, nil
+		}
+		patch := &types.WorkspacePatch{}
+		if action == ContextMenuWorkspaceToggleIsolation {
+			enabled := !workspace.IsolateSessions
+			patch.IsolateSessions = &enabled
+		} else {
+			enabled := !workspace.CleanupIsolated
+			patch.CleanupIsolated = &enabled
+		}
+		return true, m.updateWorkspaceCmd(workspace.ID, patch)
 	case ContextMenuWorkspaceStartGuidedWorkflow:
 		return true, m.startGuidedWorkflowFromSelectionTarget(
 			SelectionTarget{
@@ -192,6 +207,21 @@ func (m *Model) handleWorktreeContextMenuAction(action ContextMenuAction, target
 			return true, nil
 		}
 		return true, m.copyWithStatusCmd(path, "copied worktree path")
+	case ContextMenuWorktreeMerge, ContextMenuWorktreeRebase, ContextMenuWorktreePush:
+		if target.worktreeID == "" || target.workspaceID == "" {
+			m.setValidationStatus("select a worktree")
+			return true, nil
+		}
+		op := worktreeOperationForContextMenuAction(action)
+		m.setStatusMessage("running worktree " + string(op))
+		return true, worktreeOperationCmd(m.worktreeLifecycleAPI, target.workspaceID, target.worktreeID, op, types.WorktreeOperationRequest{})
+	case ContextMenuWorktreeRemove:
+		if target.worktreeID == "" || target.workspaceID == "" {
+			m.setValidationStatus("select a worktree")
+			return true, nil
+		}
+		m.confirmRemoveWorktree(target.workspaceID, target.worktreeID)
+		return true, nil
 	case ContextMenuWorktreeDelete:
 		if target.worktreeID == "" || target.workspaceID == "" {
 			m.setValidationStatus("select a worktree")
@@ -204,6 +234,21 @@ func (m *Model) handleWorktreeContextMenuAction(action ContextMenuAction, target
 	}
 }
 
+func worktreeOperationForContextMenuAction(action ContextMenuAction) types.WorktreeOperation {
+	switch action {
+	case ContextMenuWorktreeMerge:
+		return types.WorktreeOperationMerge
+	case ContextMenuWorktreeRebase:
+		return types.WorktreeOperationRebase
+	case ContextMenuWorktreePush:
+		return types.WorktreeOperationPush
+	case ContextMenuWorktreeRemove:
+		return types.WorktreeOperationRemove
+	default:
+		return ""
+	}
+}
+
 func (m *Model) handleSessionContextMenuAction(action ContextMenuAction, target contextMenuTarget) (bool, tea.Cmd) {
 	switch action {
 	case ContextMenuSessionChat:
@@ -267,6 +312,26 @@ func (m *Model) handleSessionContextMenuAction(action ContextMenuAction, target
 		}
 		m.setStatusMessage("interrupting " + target.sessionID)
 		return true, interruptSessionCmd(m.sessionAPI, target.sessionID)
+	case ContextMenuSessionFinalize:
+		if target.sessionID == "" {
+			m.setValidationStatus("select a session")
+			return true, nil
+		}
+		return true, m.startFinalize(finalizeTarget{sessionID: target.sessionID, label: target.targetLabel})
+	case ContextMenuSessionExportTranscript:
+		if target.sessionID == "" {
+			m.setValidationStatus("select a session")
+			return true, nil
+		}
+		m.setStatusMessage("exporting transcript")
+		return true, exportTranscriptFileCmd(m.transcriptExportAPI, target.sessionID, m.themeID)
+	case ContextMenuSessionCopyTranscript:
+		if target.sessionID == "" {
+			m.setCopyStatusWarning("select a session")
+			return true, nil
+		}
+		m.setStatusMessage("exporting transcript")
+		return true, exportTranscriptClipboardCmd(m.transcriptExportAPI, target.sessionID)
 	case ContextMenuSessionCopyID:
 		if target.sessionID == "" {
 			m.setCopyStatusWarning("select a session")
@@ -359,6 +424,13 @@ func (m *Model) handleWorkflowContextMenuAction(action ContextMenuAction, target
 		}
 		m.setStatusMessage("stopping workflow " + runID)
 		return true, stopWorkflowRunCmd(m.guidedWorkflowAPI, runID)
+	case ContextMenuWorkflowFinalize:
+		runID := strings.TrimSpace(target.workflowID)
+		if runID == "" {
+			m.setValidationStatus("select a workflow")
+			return true, nil
+		}
+		return true, m.startFinalize(finalizeTarget{runID: runID, label: target.targetLabel})
 	case ContextMenuWorkflowDismiss:
 		runID := strings.TrimSpace(target.workflowID)
 		if runID == "" {
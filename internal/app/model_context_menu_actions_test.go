@@ -61,6 +61,36 @@ func TestWorkspaceContextActionAddWorktreeEntersMode(t *testing.T) {
 	}
 }
 
+func TestWorkspaceContextActionToggleIsolationPatchesWorkspace(t *testing.T) {
+	m := NewModel(nil)
+	api := &captureWorkspaceUpdateAPI{}
+	m.workspaceAPI = api
+	m.workspaces = []*types.Workspace{
+		{ID: "ws1", Name: "Workspace", RepoPath: "/tmp/ws1", IsolateSessions: true},
+	}
+
+	handled, cmd := m.handleWorkspaceContextMenuAction(ContextMenuWorkspaceToggleIsolation, contextMenuTarget{id: "ws1"})
+	if !handled || cmd == nil {
+		t.Fatalf("expected toggle to return an update command")
+	}
+	cmd()
+	if api.lastID != "ws1" || api.lastPatch == nil || api.lastPatch.IsolateSessions == nil || *api.lastPatch.IsolateSessions {
+		t.Fatalf("expected isolation to be disabled, got %#v", api.lastPatch)
+	}
+	if api.lastPatch.CleanupIsolated != nil {
+		t.Fatalf("expected cleanup setting to be left alone")
+	}
+
+	handled, cmd = m.handleWorkspaceContextMenuAction(ContextMenuWorkspaceToggleIsolatedCleanup, contextMenuTarget{id: "ws1"})
+	if !handled || cmd == nil {
+		t.Fatalf("expected cleanup toggle to return an update command")
+	}
+	cmd()
+	if api.lastPatch.CleanupIsolated == nil || !*api.lastPatch.CleanupIsolated {
+		t.Fatalf("expected cleanup to be enabled, got %#v", api.lastPatch)
+	}
+}
+
 func TestWorkspaceContextActionAddWorktreeRequiresSelection(t *testing.T) {
 	m := NewModel(nil)
 
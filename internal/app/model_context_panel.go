@@ -1,11 +1,19 @@
 package app
 
 import (
+	"fmt"
+	"math"
 	"strings"
 
+	tea "charm.land/bubbletea/v2"
+
 	"control/internal/types"
 )
 
+// contextUsageWarningPercent is the context window use from which the panel
+// warns that the session is about to run out of context.
+const contextUsageWarningPercent = 80
+
 func (m *Model) renderContextPanelView() string {
 	data := m.threadContextPanelData()
 	title := strings.TrimSpace(data.ThreadTitle)
@@ -20,6 +28,13 @@ func (m *Model) renderContextPanelView() string {
 		formatContextUsedOrDash(data.Metrics.ContextUsedPct),
 		formatSpendOrDash(data.Metrics.SpendUSD),
 	}
+	if warning := contextUsageWarning(data.Metrics.ContextUsedPct); warning != "" {
+		body = append(body, toastWarningStyle.Render(warning))
+	}
+	if lines := formatSessionInstructions(data.Instructions); len(lines) > 0 {
+		body = append(body, "", headerStyle.Render("Instructions"))
+		body = append(body, lines...)
+	}
 	return strings.Join(body, "\n")
 }
 
@@ -30,10 +45,63 @@ func (m *Model) threadContextPanelData() ThreadContextPanelData {
 		Provider:    m.providerForSessionID(sessionID),
 		Session:     m.sessionByID(sessionID),
 		SessionMeta: m.sessionMetaByID(sessionID),
+		Usage:       m.sessionUsageByID(sessionID),
 	}
 	return m.threadContextMetricsServiceOrDefault().BuildPanelData(input)
 }
 
+func contextUsageWarning(percent *float64) string {
+	if percent == nil || math.IsNaN(*percent) || *percent < contextUsageWarningPercent {
+		return ""
+	}
+	return fmt.Sprintf("context %d%% full, consider compacting or starting a new session", int(math.Round(*percent)))
+}
+
+func (m *Model) batchWithContextUsageRefresh(cmd tea.Cmd) tea.Cmd {
+	usageCmd := m.refreshContextPanelUsage(m.contextPanelSessionID())
+	if cmd != nil && usageCmd != nil {
+		return tea.Batch(cmd, usageCmd)
+	}
+	if usageCmd != nil {
+		return usageCmd
+	}
+	return cmd
+}
+
+// refreshContextPanelUsage fetches the usage of sessionID when the context
+// panel shows it.
+func (m *Model) refreshContextPanelUsage(sessionID string) tea.Cmd {
+	sessionID = strings.TrimSpace(sessionID)
+	if m == nil || m.usageAPI == nil || sessionID == "" || !m.contextPanelEnabled() {
+		return nil
+	}
+	if sessionID != m.contextPanelSessionID() {
+		return nil
+	}
+	return fetchSessionUsageCmd(m.usageAPI, sessionID)
+}
+
+// applySessionUsageResult keeps the last known usage when a fetch fails; the
+// panel falls back to dashes for sessions that never reported any.
+func (m *Model) applySessionUsageResult(msg sessionUsageMsg) {
+	sessionID := strings.TrimSpace(msg.sessionID)
+	if msg.err != nil || msg.usage == nil || sessionID == "" {
+		return
+	}
+	if m.sessionUsage == nil {
+		m.sessionUsage = map[string]*types.SessionUsage{}
+	}
+	m.sessionUsage[sessionID] = msg.usage
+}
+
+func (m *Model) sessionUsageByID(sessionID string) *types.SessionUsage {
+	sessionID = strings.TrimSpace(sessionID)
+	if sessionID == "" || m == nil || m.sessionUsage == nil {
+		return nil
+	}
+	return m.sessionUsage[sessionID]
+}
+
 func (m *Model) contextPanelSessionID() string {
 	if m == nil {
 		return ""
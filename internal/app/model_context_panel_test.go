@@ -109,6 +109,61 @@ func TestRenderContextPanelViewFormatsHeaderAndFallbackMetrics(t *testing.T) {
 	}
 }
 
+func TestRenderContextPanelViewListsAppliedInstructions(t *testing.T) {
+	m := NewModel(nil)
+	now := time.Now().UTC()
+	m.sessions = []*types.Session{{ID: "s1", Provider: "opencode", CreatedAt: now}}
+	m.sessionMeta = map[string]*types.SessionMeta{
+		"s1": {SessionID: "s1", Title: "Refactor API", Instructions: &types.SessionInstructions{
+			Strategy:     types.InstructionStrategyPromptPrefix,
+			Instructions: true,
+			ContextFiles: []string{"docs/ARCH.md"},
+			Missing:      []string{"NOTES.md"},
+			Pending:      true,
+		}},
+	}
+	if m.compose != nil {
+		m.compose.SetSession("s1", "Refactor API")
+	}
+
+	text := xansi.Strip(m.renderContextPanelView())
+	want := "Instructions\nvia prompt prefix, pending first message\nworkspace instructions\ndocs/ARCH.md\nNOTES.md (missing)"
+	if !strings.Contains(text, want) {
+		t.Fatalf("expected applied instructions in panel, got %q", text)
+	}
+}
+
+func TestRenderContextPanelViewShowsSessionUsageAndWarns(t *testing.T) {
+	m := NewModel(nil)
+	now := time.Now().UTC()
+	m.sessions = []*types.Session{{ID: "s1", Provider: "claude", CreatedAt: now}}
+	m.sessionMeta = map[string]*types.SessionMeta{"s1": {SessionID: "s1", Title: "Refactor API"}}
+	if m.compose != nil {
+		m.compose.SetSession("s1", "Refactor API")
+	}
+	cost := 1.5
+	m.applySessionUsageResult(sessionUsageMsg{sessionID: "s1", usage: &types.SessionUsage{
+		SessionID:     "s1",
+		Tokens:        types.SessionTokenUsage{Total: 42000},
+		ContextTokens: 85000,
+		ContextWindow: 100000,
+		CostUSD:       &cost,
+	}})
+
+	text := xansi.Strip(m.renderContextPanelView())
+	if !strings.Contains(text, "42,000 tokens\n85% used\n$1.50 spend") {
+		t.Fatalf("expected usage metrics in panel, got %q", text)
+	}
+	if !strings.Contains(text, "context 85% full") {
+		t.Fatalf("expected context warning in panel, got %q", text)
+	}
+
+	m.applySessionUsageResult(sessionUsageMsg{sessionID: "s1", usage: &types.SessionUsage{SessionID: "s1", ContextTokens: 1000, ContextWindow: 100000}})
+	if text := xansi.Strip(m.renderContextPanelView()); strings.Contains(text, "full") {
+		t.Fatalf("expected no warning below threshold, got %q", text)
+	}
+}
+
 func TestFormatThreadContextValues(t *testing.T) {
 	tokens := int64(1123312)
 	if got := formatTokensOrDash(&tokens); got != "1,123,312 tokens" {
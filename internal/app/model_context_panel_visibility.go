@@ -38,5 +38,8 @@ func (m *Model) toggleContextPanel() tea.Cmd {
 	}
 	m.setStatusMessage(contextPanelToggleStatus(nextHidden))
 	m.resize(m.width, m.height)
+	if !nextHidden {
+		return tea.Batch(m.requestAppStateSaveCmd(), m.refreshContextPanelUsage(m.contextPanelSessionID()))
+	}
 	return m.requestAppStateSaveCmd()
 }
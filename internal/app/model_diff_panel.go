@@ -0,0 +1,398 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	xansi "github.com/charmbracelet/x/ansi"
+
+	"control/internal/types"
+)
+
+// diffPanelTarget identifies what the diff panel compares: a session (resolved
+// through its cwd by the daemon) or a worktree.
+type diffPanelTarget struct {
+	SessionID  string
+	WorktreeID string
+}
+
+func (t diffPanelTarget) IsZero() bool {
+	return t.SessionID == "" && t.WorktreeID == ""
+}
+
+func (t diffPanelTarget) Label() string {
+	switch {
+	case t.SessionID != "":
+		return "session " + t.SessionID
+	case t.WorktreeID != "":
+		return "worktree " + t.WorktreeID
+	default:
+		return "diff"
+	}
+}
+
+func (m *Model) diffPanelTargetForSelection() diffPanelTarget {
+	scope, ok := m.currentNoteScope()
+	if !ok {
+		return diffPanelTarget{}
+	}
+	switch scope.Scope {
+	case types.NoteScopeSession:
+		return diffPanelTarget{SessionID: strings.TrimSpace(scope.SessionID)}
+	case types.NoteScopeWorktree:
+		return diffPanelTarget{WorktreeID: strings.TrimSpace(scope.WorktreeID)}
+	default:
+		return diffPanelTarget{}
+	}
+}
+
+func (m *Model) toggleDiffPanel() tea.Cmd {
+	m.diffPanelOpen = !m.diffPanelOpen
+	if m.diffPanelOpen {
+		// Notes take precedence over the diff panel; close them so the diff
+		// is actually shown.
+		m.notesPanelOpen = false
+		m.setStatusMessage("diff panel opened")
+		m.resizeWithoutRender(m.width, m.height)
+		syncCmd := m.syncDiffPanelToCurrentSelection(true)
+		m.renderDiffPanel()
+		return syncCmd
+	}
+	m.setStatusMessage("diff panel closed")
+	m.resize(m.width, m.height)
+	return nil
+}
+
+func (m *Model) batchWithDiffPanelSync(cmd tea.Cmd) tea.Cmd {
+	panelCmd := m.syncDiffPanelToCurrentSelection(false)
+	if cmd != nil && panelCmd != nil {
+		return tea.Batch(cmd, panelCmd)
+	}
+	if panelCmd != nil {
+		return panelCmd
+	}
+	return cmd
+}
+
+func (m *Model) syncDiffPanelToCurrentSelection(force bool) tea.Cmd {
+	if !m.diffPanelOpen {
+		return nil
+	}
+	target := m.diffPanelTargetForSelection()
+	if target.IsZero() {
+		m.diffPanelTarget = diffPanelTarget{}
+		m.diffPanelDiff = nil
+		m.diffPanelLoading = false
+		m.diffPanelError = ""
+		m.diffPanelFileIndex = 0
+		m.renderDiffPanel()
+		return nil
+	}
+	if !force && target == m.diffPanelTarget {
+		return nil
+	}
+	if target != m.diffPanelTarget {
+		m.diffPanelTarget = target
+		m.diffPanelDiff = nil
+		m.diffPanelFileIndex = 0
+		m.diffPanelViewport.GotoTop()
+	}
+	return m.refreshDiffPanel()
+}
+
+func (m *Model) refreshDiffPanel() tea.Cmd {
+	if !m.diffPanelOpen || m.diffPanelTarget.IsZero() || m.diffAPI == nil {
+		return nil
+	}
+	m.diffPanelLoading = true
+	m.diffPanelError = ""
+	m.renderDiffPanel()
+	return fetchGitDiffCmd(m.diffAPI, m.diffPanelTarget)
+}
+
+// refreshDiffPanelForSession reloads the diff after a turn of sessionID
+// completes, when the panel shows that session or its worktree.
+func (m *Model) refreshDiffPanelForSession(sessionID string) tea.Cmd {
+	sessionID = strings.TrimSpace(sessionID)
+	if !m.diffPanelOpen || sessionID == "" {
+		return nil
+	}
+	target := m.diffPanelTarget
+	switch {
+	case target.SessionID == sessionID:
+	case target.WorktreeID != "":
+		meta := m.sessionMetaByID(sessionID)
+		if meta == nil || strings.TrimSpace(meta.WorktreeID) != target.WorktreeID {
+			return nil
+		}
+	default:
+		return nil
+	}
+	return m.refreshDiffPanel()
+}
+
+func (m *Model) applyGitDiffResult(msg gitDiffMsg) {
+	if msg.target != m.diffPanelTarget {
+		return
+	}
+	m.diffPanelLoading = false
+	if msg.err != nil {
+		m.diffPanelError = msg.err.Error()
+		m.setBackgroundError("diff error: " + msg.err.Error())
+		m.renderDiffPanel()
+		return
+	}
+	m.diffPanelError = ""
+	m.diffPanelDiff = msg.diff
+	m.diffPanelFileIndex = clampDiffFileIndex(msg.diff, m.diffPanelFileIndex)
+	m.renderDiffPanel()
+}
+
+func clampDiffFileIndex(diff *types.GitDiff, index int) int {
+	if diff == nil || len(diff.Files) == 0 || index < 0 {
+		return 0
+	}
+	if index >= len(diff.Files) {
+		return len(diff.Files) - 1
+	}
+	return index
+}
+
+func (m *Model) selectDiffPanelFile(delta int) bool {
+	if m.diffPanelDiff == nil || len(m.diffPanelDiff.Files) == 0 {
+		return false
+	}
+	next := clampDiffFileIndex(m.diffPanelDiff, m.diffPanelFileIndex+delta)
+	if next == m.diffPanelFileIndex {
+		return true
+	}
+	m.diffPanelFileIndex = next
+	m.renderDiffPanel()
+	m.diffPanelViewport.GotoTop()
+	return true
+}
+
+func (m *Model) diffPanelNavigable() bool {
+	return m != nil &&
+		m.diffPanelOpen &&
+		m.diffPanelVisible &&
+		m.mode == uiModeNormal
+}
+
+func (m *Model) handleDiffPanelKey(msg tea.KeyMsg) bool {
+	if !m.diffPanelNavigable() {
+		return false
+	}
+	switch {
+	case m.keyMatchesCommand(msg, KeyCommandDiffPanelPrevFile, "["):
+		return m.selectDiffPanelFile(-1)
+	case m.keyMatchesCommand(msg, KeyCommandDiffPanelNextFile, "]"):
+		return m.selectDiffPanelFile(1)
+	}
+	switch m.keyString(msg) {
+	case "shift+down":
+		m.diffPanelViewport.ScrollDown(1)
+	case "shift+up":
+		m.diffPanelViewport.ScrollUp(1)
+	case "shift+pgdown":
+		m.diffPanelViewport.PageDown()
+	case "shift+pgup":
+		m.diffPanelViewport.PageUp()
+	default:
+		return false
+	}
+	return true
+}
+
+func (m *Model) reduceDiffPanelWheelMouse(msg tea.MouseMsg, layout mouseLayout, delta int) bool {
+	if !m.diffPanelOpen || !layout.panelVisible || layout.panelWidth <= 0 || m.diffPanelViewport.Height() <= 0 {
+		return false
+	}
+	mouse := msg.Mouse()
+	if mouse.X < layout.panelStart || mouse.X >= layout.panelStart+layout.panelWidth {
+		return false
+	}
+	if mouse.Y < 0 || mouse.Y > m.diffPanelViewport.Height() {
+		return false
+	}
+	if delta < 0 {
+		m.diffPanelViewport.ScrollUp(3)
+	} else {
+		m.diffPanelViewport.ScrollDown(3)
+	}
+	return true
+}
+
+func (m *Model) renderDiffPanel() {
+	if !m.diffPanelOpen {
+		return
+	}
+	width := m.diffPanelViewport.Width()
+	if width <= 0 {
+		m.diffPanelViewport.SetContent("")
+		return
+	}
+	var lines []string
+	switch {
+	case m.diffPanelTarget.IsZero():
+		lines = []string{"No git diff for this selection."}
+	case m.diffPanelError != "":
+		lines = []string{"Error loading diff: " + m.diffPanelError}
+	case m.diffPanelDiff == nil:
+		lines = []string{"Loading diff..."}
+	default:
+		lines = renderGitDiffPanelLines(m.diffPanelDiff, m.diffPanelFileIndex, width)
+	}
+	for i, line := range lines {
+		lines[i] = xansi.Truncate(line, width, "…")
+	}
+	m.diffPanelViewport.SetContent(strings.Join(lines, "\n"))
+}
+
+func (m *Model) renderDiffPanelView() string {
+	header := "Diff"
+	if !m.diffPanelTarget.IsZero() {
+		header += " • " + m.diffPanelTarget.Label()
+	}
+	if m.diffPanelLoading && m.diffPanelDiff != nil {
+		header += " • refreshing"
+	}
+	return headerStyle.Render(header) + "\n" + m.diffPanelViewport.View()
+}
+
+// renderGitDiffPanelLines renders the summary, the file list with the
+// selected file marked, and the selected file's hunks.
+func renderGitDiffPanelLines(diff *types.GitDiff, selected, width int) []string {
+	if diff == nil {
+		return nil
+	}
+	base := diff.Base
+	if commit := strings.TrimSpace(diff.BaseCommit); len(commit) >= 7 {
+		base += " (" + commit[:7] + ")"
+	}
+	lines := []string{
+		chatMetaStyle.Render(fmt.Sprintf("base %s • %d files %s %s • %d untracked",
+			base,
+			diff.Stats.Files,
+			diffAddedStyle.Render(fmt.Sprintf("+%d", diff.Stats.Additions)),
+			diffDeletedStyle.Render(fmt.Sprintf("-%d", diff.Stats.Deletions)),
+			diff.Stats.Untracked,
+		)),
+	}
+	if diff.Truncated {
+		lines = append(lines, chatMetaStyle.Render("diff truncated"))
+	}
+	if len(diff.Files) == 0 && len(diff.Untracked) == 0 {
+		return append(lines, "", "No changes.")
+	}
+	lines = append(lines, "")
+	selected = clampDiffFileIndex(diff, selected)
+	for i, file := range diff.Files {
+		label := gitDiffStatusLetter(file.Status) + " " + file.Path
+		if file.OldPath != "" {
+			label = gitDiffStatusLetter(file.Status) + " " + file.OldPath + " → " + file.Path
+		}
+		stats := diffAddedStyle.Render(fmt.Sprintf("+%d", file.Additions)) + " " +
+			diffDeletedStyle.Render(fmt.Sprintf("-%d", file.Deletions))
+		if i == selected {
+			lines = append(lines, "> "+selectedMessageStyle.Render(label)+" "+stats)
+			continue
+		}
+		lines = append(lines, "  "+label+" "+stats)
+	}
+	for _, path := range diff.Untracked {
+		lines = append(lines, "  "+chatMetaStyle.Render("? "+path))
+	}
+	if len(diff.Files) == 0 {
+		return lines
+	}
+
+	file := diff.Files[selected]
+	lines = append(lines, "", headerStyle.Render(file.Path), dividerStyle.Render(strings.Repeat("─", max(1, width))))
+	if file.Binary {
+		return append(lines, chatMetaStyle.Render("Binary file."))
+	}
+	if len(file.Hunks) == 0 {
+		return append(lines, chatMetaStyle.Render("No textual changes."))
+	}
+	highlighter := newDiffSyntaxHighlighter(file.Path)
+	for _, hunk := range file.Hunks {
+		lines = append(lines, diffHunkStyle.Render(hunk.Header))
+		for _, line := range hunk.Lines {
+			text := highlighter.Highlight(line.Text)
+			switch line.Kind {
+			case types.GitDiffLineAdded:
+				lines = append(lines, diffAddedStyle.Render("+")+text)
+			case types.GitDiffLineDeleted:
+				lines = append(lines, diffDeletedStyle.Render("-")+text)
+			default:
+				lines = append(lines, " "+text)
+			}
+		}
+	}
+	return lines
+}
+
+func gitDiffStatusLetter(status types.GitDiffFileStatus) string {
+	switch status {
+	case types.GitDiffFileAdded:
+		return "A"
+	case types.GitDiffFileDeleted:
+		return "D"
+	case types.GitDiffFileRenamed:
+		return "R"
+	case types.GitDiffFileCopied:
+		return "C"
+	default:
+		return "M"
+	}
+}
+
+// diffSyntaxHighlighter colors single diff lines with the lexer matched by
+// the file name. Lines are tokenized independently, so constructs spanning
+// several lines may be colored imprecisely.
+type diffSyntaxHighlighter struct {
+	lexer chroma.Lexer
+	style *chroma.Style
+}
+
+func newDiffSyntaxHighlighter(path string) diffSyntaxHighlighter {
+	lexer := lexers.Match(filepath.Base(path))
+	if lexer == nil {
+		return diffSyntaxHighlighter{}
+	}
+	name := "github"
+	if markdownBackgroundDark() {
+		name = "monokai"
+	}
+	style, err := styles.Get(name).Builder().Transform(func(entry chroma.StyleEntry) chroma.StyleEntry {
+		entry.Background = 0
+		return entry
+	}).Build()
+	if err != nil {
+		return diffSyntaxHighlighter{}
+	}
+	return diffSyntaxHighlighter{lexer: chroma.Coalesce(lexer), style: style}
+}
+
+func (h diffSyntaxHighlighter) Highlight(text string) string {
+	text = strings.ReplaceAll(text, "\t", "    ")
+	if h.lexer == nil || h.style == nil || strings.TrimSpace(text) == "" {
+		return text
+	}
+	iterator, err := h.lexer.Tokenise(nil, text)
+	if err != nil {
+		return text
+	}
+	var out strings.Builder
+	if err := formatters.TTY256.Format(&out, h.style, iterator); err != nil {
+		return text
+	}
+	return strings.TrimRight(out.String(), "\n")
+}
@@ -0,0 +1,150 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	xansi "github.com/charmbracelet/x/ansi"
+
+	"control/internal/types"
+)
+
+type stubGitDiffAPI struct {
+	sessionCalls  []string
+	worktreeCalls []string
+	diff          *types.GitDiff
+}
+
+func (s *stubGitDiffAPI) WorktreeDiff(_ context.Context, worktreeID, _ string) (*types.GitDiff, error) {
+	s.worktreeCalls = append(s.worktreeCalls, worktreeID)
+	return s.diff, nil
+}
+
+func (s *stubGitDiffAPI) SessionDiff(_ context.Context, sessionID, _ string) (*types.GitDiff, error) {
+	s.sessionCalls = append(s.sessionCalls, sessionID)
+	return s.diff, nil
+}
+
+func sampleGitDiff() *types.GitDiff {
+	return &types.GitDiff{
+		Base:       "HEAD",
+		BaseCommit: "0123456789abcdef",
+		Files: []types.GitDiffFile{
+			{
+				Path:      "main.go",
+				Status:    types.GitDiffFileModified,
+				Additions: 1,
+				Deletions: 1,
+				Hunks: []types.GitDiffHunk{{
+					Header: "@@ -1,2 +1,2 @@",
+					Lines: []types.GitDiffLine{
+						{Kind: types.GitDiffLineContext, Text: "package main"},
+						{Kind: types.GitDiffLineDeleted, Text: "func old() {}"},
+						{Kind: types.GitDiffLineAdded, Text: "func updated() {}"},
+					},
+				}},
+			},
+			{Path: "README.md", Status: types.GitDiffFileAdded, Additions: 1, Hunks: []types.GitDiffHunk{{
+				Header: "@@ -0,0 +1 @@",
+				Lines:  []types.GitDiffLine{{Kind: types.GitDiffLineAdded, Text: "# readme"}},
+			}}},
+		},
+		Untracked: []string{"scratch.txt"},
+		Stats:     types.GitDiffStats{Files: 2, Additions: 2, Deletions: 1, Untracked: 1},
+	}
+}
+
+func TestToggleDiffPanelFetchesSelectedSessionDiff(t *testing.T) {
+	m := newPhase0ModelWithSession("codex")
+	api := &stubGitDiffAPI{diff: sampleGitDiff()}
+	m.diffAPI = api
+	if m.sidebar == nil || !m.sidebar.SelectBySessionID("s1") {
+		t.Fatalf("expected session selection")
+	}
+	m.mode = uiModeNormal
+	m.resize(180, 40)
+
+	cmd := m.toggleDiffPanel()
+	if cmd == nil {
+		t.Fatalf("expected fetch command when opening diff panel")
+	}
+	if got := m.activeSidePanelMode(); got != sidePanelModeDiff {
+		t.Fatalf("expected side panel mode diff, got %v", got)
+	}
+	if !m.diffPanelVisible || m.contextPanelVisible {
+		t.Fatalf("expected only the diff panel to be visible")
+	}
+	msg, ok := cmd().(gitDiffMsg)
+	if !ok {
+		t.Fatalf("expected gitDiffMsg")
+	}
+	if len(api.sessionCalls) != 1 || api.sessionCalls[0] != "s1" {
+		t.Fatalf("expected session diff request, got %#v", api.sessionCalls)
+	}
+	m.applyGitDiffResult(msg)
+
+	view := xansi.Strip(m.renderDiffPanelView())
+	for _, want := range []string{"Diff • session s1", "base HEAD (0123456)", "> M main.go +1 -1", "? scratch.txt", "-func old() {}", "+func updated() {}"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected %q in diff panel view:\n%s", want, view)
+		}
+	}
+}
+
+func TestDiffPanelFileNavigationAndRefreshOnCompletion(t *testing.T) {
+	m := newPhase0ModelWithSession("codex")
+	api := &stubGitDiffAPI{diff: sampleGitDiff()}
+	m.diffAPI = api
+	if m.sidebar == nil || !m.sidebar.SelectBySessionID("s1") {
+		t.Fatalf("expected session selection")
+	}
+	m.mode = uiModeNormal
+	m.resize(180, 40)
+	cmd := m.toggleDiffPanel()
+	m.applyGitDiffResult(cmd().(gitDiffMsg))
+
+	if !m.handleDiffPanelKey(tea.KeyPressMsg{Code: ']', Text: "]"}) {
+		t.Fatalf("expected ] to select the next file")
+	}
+	if m.diffPanelFileIndex != 1 {
+		t.Fatalf("expected second file selected, got %d", m.diffPanelFileIndex)
+	}
+	view := xansi.Strip(m.renderDiffPanelView())
+	if !strings.Contains(view, "> A README.md") || !strings.Contains(view, "+# readme") {
+		t.Fatalf("expected README.md hunks after navigation:\n%s", view)
+	}
+	m.handleDiffPanelKey(tea.KeyPressMsg{Code: ']', Text: "]"})
+	if m.diffPanelFileIndex != 1 {
+		t.Fatalf("expected selection to stop at the last file, got %d", m.diffPanelFileIndex)
+	}
+
+	if cmd := m.refreshDiffPanelForSession("other"); cmd != nil {
+		t.Fatalf("expected no refresh for unrelated session")
+	}
+	refresh := m.refreshDiffPanelForSession("s1")
+	if refresh == nil {
+		t.Fatalf("expected refresh when a turn of the shown session completes")
+	}
+	m.applyGitDiffResult(refresh().(gitDiffMsg))
+	if len(api.sessionCalls) != 2 || m.diffPanelFileIndex != 1 {
+		t.Fatalf("expected refetch keeping the selection, calls=%d index=%d", len(api.sessionCalls), m.diffPanelFileIndex)
+	}
+}
+
+func TestDiffPanelTargetsSelectedWorktree(t *testing.T) {
+	m := newPhase0ModelWithSession("codex")
+	m.mode = uiModeNormal
+	m.diffPanelOpen = true
+	m.diffPanelTarget = diffPanelTarget{WorktreeID: "wt1"}
+	m.sessionMeta["s2"] = &types.SessionMeta{SessionID: "s2", WorkspaceID: "ws1", WorktreeID: "wt1"}
+	m.diffAPI = &stubGitDiffAPI{diff: sampleGitDiff()}
+
+	if cmd := m.refreshDiffPanelForSession("s2"); cmd == nil {
+		t.Fatalf("expected refresh for a session in the shown worktree")
+	}
+	if cmd := m.refreshDiffPanelForSession("s1"); cmd != nil {
+		t.Fatalf("expected no refresh for a session outside the worktree")
+	}
+}
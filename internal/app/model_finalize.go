@@ -0,0 +1,189 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"control/internal/types"
+)
+
+const finalizePullRequestPreviewLines = 12
+
+// finalizeTarget names the session or workflow run being finalized. runID
+// takes precedence when set.
+type finalizeTarget struct {
+	sessionID string
+	runID     string
+	label     string
+}
+
+func (t finalizeTarget) id() string {
+	if t.runID != "" {
+		return t.runID
+	}
+	return t.sessionID
+}
+
+func (t finalizeTarget) displayName() string {
+	if label := strings.TrimSpace(t.label); label != "" {
+		return label
+	}
+	return t.id()
+}
+
+func (m *Model) startFinalize(target finalizeTarget) tea.Cmd {
+	if m.finalizeAPI == nil {
+		m.setValidationStatus("finalize is unavailable")
+		return nil
+	}
+	m.setStatusMessage("drafting commit for " + target.displayName())
+	return fetchFinalizeDraftCmd(m.finalizeAPI, target)
+}
+
+func (m *Model) applyFinalizeDraft(msg finalizeDraftMsg) {
+	if msg.err != nil {
+		m.setStatusError("finalize error: " + msg.err.Error())
+		return
+	}
+	if msg.draft == nil {
+		m.setStatusWarning("no finalize draft returned")
+		return
+	}
+	m.enterFinalize(msg.target, msg.draft)
+}
+
+func (m *Model) enterFinalize(target finalizeTarget, draft *types.FinalizeDraft) {
+	m.finalizeTarget = target
+	m.finalizeDraft = draft
+	if m.finalizeInput != nil {
+		m.finalizeInput.SetPlaceholder("Commit message")
+		m.finalizeInput.SetValue(draft.Message)
+		m.finalizeInput.Focus()
+	}
+	if m.input != nil {
+		m.input.FocusChatInput()
+	}
+	m.mode = uiModeFinalize
+	if draft.MessageError != "" {
+		m.setStatusWarning("provider draft failed; using summary: " + draft.MessageError)
+	} else {
+		m.setStatusMessage("review the commit message")
+	}
+	m.resize(m.width, m.height)
+}
+
+func (m *Model) exitFinalize(status string) {
+	targetFocus := focusSidebar
+	m.applyModeTransition(modeTransitionRequest{
+		toMode:      uiModeNormal,
+		status:      status,
+		focus:       &targetFocus,
+		forceReflow: true,
+		before: func() {
+			m.finalizeTarget = finalizeTarget{}
+			m.finalizeDraft = nil
+			if m.finalizeInput != nil {
+				m.finalizeInput.Blur()
+				m.finalizeInput.SetPlaceholder("")
+				m.finalizeInput.SetValue("")
+			}
+		},
+	})
+}
+
+func (m *Model) cancelFinalizeInput() tea.Cmd {
+	m.exitFinalize("finalize canceled")
+	return nil
+}
+
+func (m *Model) submitFinalizeInput(text string) tea.Cmd {
+	message := strings.TrimSpace(text)
+	if message == "" {
+		m.setValidationStatus("commit message is required")
+		return nil
+	}
+	target := m.finalizeTarget
+	if target.id() == "" {
+		m.setValidationStatus("select a session to finalize")
+		return nil
+	}
+	m.exitFinalize("committing " + target.displayName())
+	return finalizeCmd(m.finalizeAPI, target, message)
+}
+
+func (m *Model) applyFinalizeResult(msg finalizeResultMsg) tea.Cmd {
+	if msg.err != nil {
+		m.setStatusError("finalize error: " + msg.err.Error())
+		return nil
+	}
+	if msg.result == nil {
+		m.setStatusWarning("no finalize result returned")
+		return nil
+	}
+	status := "committed " + shortFinalizeCommit(msg.result.Commit)
+	if branch := strings.TrimSpace(msg.result.Branch); branch != "" {
+		status += " on " + branch
+	}
+	if linked := len(msg.result.Linked); linked > 0 {
+		if strings.TrimSpace(msg.result.Commit) == "" {
+			status = "committed"
+		}
+		status += fmt.Sprintf(" (+%d linked repo(s))", linked)
+	}
+	m.setStatusInfo(status)
+	if strings.TrimSpace(msg.result.PullRequest) == "" {
+		return nil
+	}
+	return m.copyWithStatusCmd(msg.result.PullRequest, status+"; pull request description copied")
+}
+
+func (m *Model) finalizeBody() string {
+	draft := m.finalizeDraft
+	if draft == nil {
+		return "Loading finalize draft..."
+	}
+	lines := []string{"Repository: " + draft.Root}
+	if draft.Branch != "" {
+		lines = append(lines, "Branch: "+draft.Branch)
+	}
+	if diff := draft.Diff; diff != nil {
+		for _, linked := range diff.Linked {
+			lines = append(lines, "Linked repository: "+linked.Name+" ("+linked.Root+")")
+		}
+		lines = append(lines, fmt.Sprintf("Changes: %d file(s), +%d -%d", diff.Stats.Files+diff.Stats.Untracked, diff.Stats.Additions, diff.Stats.Deletions))
+		for _, file := range diff.Files {
+			lines = append(lines, fmt.Sprintf("  %s %s (+%d -%d)", file.Status, file.Path, file.Additions, file.Deletions))
+		}
+		for _, path := range diff.Untracked {
+			lines = append(lines, "  added "+path)
+		}
+	}
+	source := "Message drafted by " + draft.MessageSource
+	if draft.MessageError != "" {
+		source += " (provider failed: " + draft.MessageError + ")"
+	}
+	lines = append(lines, "", source)
+	if preview := strings.TrimSpace(draft.PullRequest); preview != "" {
+		previewLines := strings.Split(preview, "\n")
+		lines = append(lines, "", "Pull request description (copied after commit):")
+		if len(previewLines) > finalizePullRequestPreviewLines {
+			previewLines = append(previewLines[:finalizePullRequestPreviewLines], "...")
+		}
+		lines = append(lines, previewLines...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) finalizeFooter() string {
+	return "enter commit  shift+enter newline  esc cancel"
+}
+
+func shortFinalizeCommit(commit string) string {
+	commit = strings.TrimSpace(commit)
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
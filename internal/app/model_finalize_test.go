@@ -0,0 +1,92 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"control/internal/types"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+type stubFinalizeAPI struct {
+	draft    *types.FinalizeDraft
+	sessions []string
+	requests []types.FinalizeRequest
+}
+
+func (s *stubFinalizeAPI) SessionFinalizeDraft(_ context.Context, sessionID string) (*types.FinalizeDraft, error) {
+	s.sessions = append(s.sessions, sessionID)
+	return s.draft, nil
+}
+
+func (s *stubFinalizeAPI) FinalizeSession(_ context.Context, sessionID string, req types.FinalizeRequest) (*types.FinalizeResult, error) {
+	s.sessions = append(s.sessions, sessionID)
+	s.requests = append(s.requests, req)
+	return &types.FinalizeResult{
+		SessionID:   sessionID,
+		Branch:      "feature",
+		Commit:      "0123456789abcdef",
+		Message:     req.Message,
+		PullRequest: "# " + req.Message + "\n",
+	}, nil
+}
+
+func (s *stubFinalizeAPI) WorkflowRunFinalizeDraft(context.Context, string) (*types.FinalizeDraft, error) {
+	return s.draft, nil
+}
+
+func (s *stubFinalizeAPI) FinalizeWorkflowRun(context.Context, string, types.FinalizeRequest) (*types.FinalizeResult, error) {
+	return nil, nil
+}
+
+func TestSessionFinalizeEditsDraftAndCommits(t *testing.T) {
+	m := newPhase0ModelWithSession("codex")
+	m.resize(120, 40)
+	api := &stubFinalizeAPI{draft: &types.FinalizeDraft{
+		SessionID:     "s1",
+		Root:          "/repo",
+		Branch:        "feature",
+		Diff:          &types.GitDiff{Files: []types.GitDiffFile{{Path: "main.go", Status: types.GitDiffFileModified, Additions: 2}}},
+		Message:       "feat: draft",
+		MessageSource: types.FinalizeMessageSourceProvider,
+		PullRequest:   "# feat: draft\n",
+	}}
+	m.finalizeAPI = api
+
+	handled, cmd := m.handleSessionContextMenuAction(ContextMenuSessionFinalize, contextMenuTarget{sessionID: "s1"})
+	if !handled || cmd == nil {
+		t.Fatalf("expected finalize action to fetch a draft")
+	}
+	m.Update(cmd())
+	if m.mode != uiModeFinalize {
+		t.Fatalf("expected finalize mode, got %v", m.mode)
+	}
+	if got := m.finalizeInput.Value(); got != "feat: draft" {
+		t.Fatalf("expected drafted message in input, got %q", got)
+	}
+	if body := m.finalizeBody(); !strings.Contains(body, "modified main.go (+2 -0)") || !strings.Contains(body, "Branch: feature") {
+		t.Fatalf("unexpected finalize body %q", body)
+	}
+
+	m.finalizeInput.SetValue("fix: edited")
+	_, cmd = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatalf("expected submit to commit")
+	}
+	if m.mode != uiModeNormal {
+		t.Fatalf("expected finalize mode to exit, got %v", m.mode)
+	}
+	msg, ok := cmd().(finalizeResultMsg)
+	if !ok {
+		t.Fatalf("expected finalizeResultMsg")
+	}
+	if len(api.requests) != 1 || api.requests[0].Message != "fix: edited" || !api.requests[0].PullRequest {
+		t.Fatalf("unexpected finalize requests %#v", api.requests)
+	}
+	m.applyFinalizeResult(msg)
+	if !strings.Contains(m.status, "committed 0123456789ab on feature") {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+}
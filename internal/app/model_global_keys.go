@@ -8,6 +8,7 @@ type globalKeyOptions struct {
 	AllowToggleSidebar bool
 	AllowToggleNotes   bool
 	AllowToggleContext bool
+	AllowToggleDiff    bool
 	AllowToggleDebug   bool
 }
 
@@ -31,6 +32,9 @@ func (m *Model) reduceGlobalKey(msg tea.KeyMsg, opts globalKeyOptions) (bool, te
 	if opts.AllowToggleContext && m.keyMatchesCommand(msg, KeyCommandToggleContextPanel, "ctrl+l") {
 		return true, m.toggleContextPanel()
 	}
+	if opts.AllowToggleDiff && m.keyMatchesCommand(msg, KeyCommandToggleDiffPanel, "ctrl+x") {
+		return true, m.toggleDiffPanel()
+	}
 	if opts.AllowToggleDebug && m.keyMatchesCommand(msg, KeyCommandToggleDebugStreams, "ctrl+d") {
 		return true, m.toggleDebugStreams()
 	}
@@ -28,6 +28,9 @@ func (m *Model) enterGuidedWorkflow(context guidedWorkflowLaunchContext) {
 	m.guidedWorkflow.SetDependencyOptions(m.guidedWorkflowDependencyOptions())
 	m.guidedWorkflow.BeginTemplateLoad()
 	m.resetGuidedWorkflowPromptInput()
+	if prompt := strings.TrimSpace(context.userPrompt); prompt != "" && m.guidedWorkflowPromptInput != nil {
+		m.guidedWorkflowPromptInput.SetValue(prompt)
+	}
 	m.resetGuidedWorkflowResumeInput()
 	if m.input != nil {
 		m.input.FocusSidebar()
@@ -357,6 +360,7 @@ func (m *Model) reduceGuidedWorkflowMode(msg tea.Msg) (bool, tea.Cmd) {
 			AllowToggleSidebar: true,
 			AllowToggleNotes:   true,
 			AllowToggleContext: true,
+			AllowToggleDiff:    true,
 		}); handled {
 			return true, cmd
 		}
@@ -758,7 +762,7 @@ func (m *Model) openGuidedWorkflowSessionTurn(sessionID, turnID string) tea.Cmd
 	m.setPendingWorkflowTurnFocus(resolvedSessionID, turnID)
 	item := m.selectedItem()
 	m.exitGuidedWorkflow("opened linked session " + resolvedSessionID)
-	return m.batchWithNotesPanelSync(m.loadSelectedSession(item))
+	return m.batchWithContextUsageRefresh(m.batchWithDiffPanelSync(m.batchWithNotesPanelSync(m.loadSelectedSession(item))))
 }
 
 func (m *Model) ensureGuidedWorkflowSessionVisible(sessionID string) {
@@ -1066,6 +1070,7 @@ func (m *Model) handleGuidedWorkflowSetupInput(msg tea.Msg) (bool, tea.Cmd) {
 				AllowToggleSidebar: true,
 				AllowToggleNotes:   true,
 				AllowToggleContext: true,
+				AllowToggleDiff:    true,
 			}); handled {
 				return true, cmd
 			}
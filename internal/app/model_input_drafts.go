@@ -19,6 +19,7 @@ func (m *Model) saveCurrentComposeDraft() bool {
 	if m == nil || m.chatInput == nil {
 		return false
 	}
+	m.cancelComposeSnippetPrompt()
 	return m.setComposeDraft(m.composeSessionID(), m.chatInput.Value())
 }
 
@@ -163,6 +163,7 @@ func (m *Model) reduceMenuAndAppKeys(msg tea.KeyMsg) (bool, tea.Cmd) {
 		AllowToggleSidebar: true,
 		AllowToggleNotes:   true,
 		AllowToggleContext: true,
+		AllowToggleDiff:    true,
 		AllowToggleDebug:   true,
 	}); handled {
 		return true, cmd
@@ -363,6 +364,12 @@ func (m *Model) reduceViewToggleKeys(msg tea.KeyMsg) (bool, tea.Cmd) {
 	case "v":
 		m.enterMessageSelection()
 		return true, nil
+	case "T":
+		return true, m.toggleSessionTilesView()
+	case "R":
+		return true, m.enterApprovalRules()
+	case "A":
+		return true, m.enterApprovalInbox()
 	default:
 		return false, nil
 	}
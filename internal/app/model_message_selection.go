@@ -53,6 +53,9 @@ func (m *Model) reduceMessageSelectionKey(msg tea.KeyMsg) (bool, tea.Cmd) {
 		return true, m.copySelectedMessageCmd()
 	case "p":
 		return true, m.pinSelectedMessage()
+	case "r":
+		m.confirmRollbackSelectedMessage()
+		return true, nil
 	case "e":
 		if m.toggleReasoningByIndex(m.messageSelectIndex) {
 			m.setMessageSelectionStatus()
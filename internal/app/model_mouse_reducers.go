@@ -48,7 +48,7 @@ func (m *Model) mouseOverInput(y int) bool {
 	if lines <= 0 {
 		return false
 	}
-	start := m.viewport.Height() + 2
+	start := m.viewport.Height() + 2 + layout.InputStartRow()
 	end := start + lines - 1
 	return y >= start && y <= end
 }
@@ -119,6 +119,63 @@ func (m *Model) reduceComposeFileSearchLeftPressMouse(msg tea.MouseMsg, _ mouseL
 	return true
 }
 
+func (m *Model) reduceComposeMentionLeftPressMouse(msg tea.MouseMsg) bool {
+	if !isMouseClickMsg(msg) || !m.composeMention.Open() {
+		return false
+	}
+	popup, popupX, row := m.composeMentionPopupPlacement()
+	if popup == "" {
+		m.composeMention.Close()
+		return false
+	}
+	if pickerRow, inside := composePopupClickRow(msg, popup, popupX, row); inside {
+		if m.composeMention.HandleClick(pickerRow) {
+			_ = m.applyComposeMentionSelection()
+		}
+		return true
+	}
+	m.composeMention.Close()
+	return true
+}
+
+func (m *Model) reduceComposeSnippetLeftPressMouse(msg tea.MouseMsg) bool {
+	if !isMouseClickMsg(msg) || !m.composeSnippet.Open() {
+		return false
+	}
+	popup, popupX, row := m.composeSnippetPopupPlacement()
+	if popup == "" {
+		m.composeSnippet.Close()
+		return false
+	}
+	if pickerRow, inside := composePopupClickRow(msg, popup, popupX, row); inside {
+		if m.composeSnippet.HandleClick(pickerRow) {
+			m.applyComposeSnippetSelection()
+		}
+		return true
+	}
+	m.composeSnippet.Close()
+	return true
+}
+
+func (m *Model) reduceComposeSlashLeftPressMouse(msg tea.MouseMsg) bool {
+	if !isMouseClickMsg(msg) || !m.composeSlash.Open() {
+		return false
+	}
+	popup, popupX, row := m.composeSlashPopupPlacement()
+	if popup == "" {
+		m.composeSlash.Close()
+		return false
+	}
+	if pickerRow, inside := composePopupClickRow(msg, popup, popupX, row); inside {
+		if m.composeSlash.HandleClick(pickerRow) {
+			_ = m.applyComposeSlashSelection(false)
+		}
+		return true
+	}
+	m.composeSlash.Close()
+	return true
+}
+
 func composePopupClickRow(msg tea.MouseMsg, popup string, popupX, row int) (int, bool) {
 	lines := strings.Split(popup, "\n")
 	height := len(lines)
@@ -335,6 +392,9 @@ func (m *Model) reduceMouseWheel(msg tea.MouseMsg, layout mouseLayout, delta int
 	if m.reduceNotesPanelWheelMouse(msg, layout, delta) {
 		return true
 	}
+	if m.reduceDiffPanelWheelMouse(msg, layout, delta) {
+		return true
+	}
 	if m.reduceModeWheelMouse(msg, layout, delta) {
 		return true
 	}
@@ -417,6 +417,38 @@ func (m *Model) reduceApprovalResponseMode(msg tea.Msg) (bool, tea.Cmd) {
 	return handled, cmd
 }
 
+func (m *Model) reduceFinalizeMode(msg tea.Msg) (bool, tea.Cmd) {
+	if m.mode != uiModeFinalize {
+		return false, nil
+	}
+	if !isTextInputMsg(msg) {
+		return true, nil
+	}
+	controller := textInputModeController{
+		input:             m.finalizeInput,
+		keyString:         m.keyString,
+		keyMatchesCommand: m.keyMatchesCommand,
+		onCancel:          m.cancelFinalizeInput,
+		onSubmit:          m.submitFinalizeInput,
+	}
+	handled, cmd := controller.Update(msg)
+	if handled && m.consumeInputHeightChanges(m.finalizeInput) {
+		m.resize(m.width, m.height)
+	}
+	return handled, cmd
+}
+
+func (m *Model) reduceApprovalRulesMode(msg tea.Msg) (bool, tea.Cmd) {
+	if m.mode != uiModeApprovalRules {
+		return false, nil
+	}
+	if !isTextInputMsg(msg) {
+		return true, nil
+	}
+	controller := m.newSingleLineInputController(m.approvalRulesInput, m.cancelApprovalRulesInput, m.submitApprovalRulesInput)
+	return controller.Update(msg)
+}
+
 func (m *Model) newSingleLineInputController(input *TextInput, onCancel func() tea.Cmd, onSubmit func(text string) tea.Cmd) textInputModeController {
 	return textInputModeController{
 		input:             input,
@@ -540,6 +572,12 @@ func (m *Model) reduceComposeInputKey(msg tea.Msg) (bool, tea.Cmd) {
 		m.applyPickerPaste(pasteMsg, composePicker)
 		return true, nil
 	}
+	if pasteMsg, ok := msg.(tea.PasteMsg); ok {
+		if attachment, ok := composeAttachmentFromPaste(pasteMsg.Content); ok {
+			m.addComposeAttachment(attachment)
+			return true, nil
+		}
+	}
 	controller := textInputModeController{
 		input:             m.chatInput,
 		keyString:         m.keyString,
@@ -553,6 +591,25 @@ func (m *Model) reduceComposeInputKey(msg tea.Msg) (bool, tea.Cmd) {
 		beforeInputUpdate: m.resetComposeHistoryCursor,
 		shouldPassthrough: m.shouldComposePassthrough,
 		preHandle: func(key string, msg tea.KeyMsg) (bool, tea.Cmd) {
+			if m.keyMatchesCommand(msg, KeyCommandComposeAttach, "ctrl+t") {
+				return true, m.attachComposeFileSearchSelection()
+			}
+			if m.keyMatchesCommand(msg, KeyCommandComposePasteImage, "alt+v") {
+				m.setStatusMessage("reading clipboard image")
+				return true, m.pasteClipboardImageCmd()
+			}
+			if handled, cmd := m.handleComposeSnippetKey(key); handled {
+				return true, cmd
+			}
+			if handled, cmd := m.handleComposeSlashKey(key); handled {
+				return true, cmd
+			}
+			if key == "backspace" && m.chatInput != nil && m.chatInput.Value() == "" && m.removeLastComposeAttachment() {
+				return true, nil
+			}
+			if handled, cmd := m.handleComposeMentionKey(key); handled {
+				return true, cmd
+			}
 			if handled, cmd := m.handleComposeFileSearchKey(key); handled {
 				return true, cmd
 			}
@@ -588,6 +645,7 @@ func (m *Model) reduceComposeInputKey(msg tea.Msg) (bool, tea.Cmd) {
 			if handled, cmd := m.reduceGlobalKey(msg, globalKeyOptions{
 				AllowToggleNotes:   true,
 				AllowToggleContext: true,
+				AllowToggleDiff:    true,
 				AllowToggleDebug:   true,
 			}); handled {
 				return true, cmd
@@ -629,14 +687,7 @@ func (m *Model) reduceComposeInputKey(msg tea.Msg) (bool, tea.Cmd) {
 	if !handled {
 		return handled, cmd
 	}
-	fileSearchCmd := m.syncComposeFileSearchAfterInput()
-	if fileSearchCmd != nil && cmd != nil {
-		return handled, tea.Batch(cmd, fileSearchCmd)
-	}
-	if fileSearchCmd != nil {
-		return handled, fileSearchCmd
-	}
-	return handled, cmd
+	return handled, tea.Batch(cmd, m.syncComposeFileSearchAfterInput(), m.syncComposeMentionAfterInput(), m.syncComposeSnippetAfterInput(), m.syncComposeSlashAfterInput())
 }
 
 func isTextInputMsg(msg tea.Msg) bool {
@@ -683,9 +734,10 @@ func isComposeInputCommand(command string) bool {
 		KeyCommandInputWordLeft, KeyCommandInputWordRight,
 		KeyCommandInputDeleteWordLeft, KeyCommandInputDeleteWordRight,
 		KeyCommandComposeModel, KeyCommandComposeReasoning, KeyCommandComposeAccess,
+		KeyCommandComposeAttach, KeyCommandComposePasteImage,
 		KeyCommandCopySelectionIDs,
 		KeyCommandCopySessionID,
-		KeyCommandToggleNotesPanel, KeyCommandToggleContextPanel, KeyCommandToggleDebugStreams:
+		KeyCommandToggleNotesPanel, KeyCommandToggleContextPanel, KeyCommandToggleDiffPanel, KeyCommandToggleDebugStreams:
 		return true
 	default:
 		return false
@@ -707,17 +759,31 @@ func (m *Model) cancelComposeInput() tea.Cmd {
 }
 
 func (m *Model) submitComposeInput(text string) tea.Cmd {
-	if strings.TrimSpace(text) == "" {
+	text, handled, slashCmd := m.interceptComposeSlashCommand(text)
+	if handled {
+		return slashCmd
+	}
+	attachments := m.composeAttachments
+	if strings.TrimSpace(text) == "" && len(attachments) == 0 {
 		m.setValidationStatus("message is required")
 		return nil
 	}
 	closeCmd := m.closeComposeFileSearchCmd()
+	mentions := m.composeMentionsForSend(text)
 	if m.newSession != nil {
 		target := m.newSession
 		if strings.TrimSpace(target.provider) == "" {
 			m.setValidationStatus("provider is required")
 			return nil
 		}
+		if len(attachments) > 0 {
+			m.setValidationStatus("attachments can be sent once the session has started")
+			return nil
+		}
+		if len(mentions) > 0 {
+			m.setValidationStatus("mentions can be sent once the session has started")
+			return nil
+		}
 		m.resetStreamWithReason(transcriptResetReasonNewSessionStartRequested)
 		m.setContentText("Starting new session...")
 		m.enableFollow(false)
@@ -737,22 +803,30 @@ func (m *Model) submitComposeInput(text string) tea.Cmd {
 		return nil
 	}
 	m.clearComposeDraft(sessionID)
-	m.recordComposeHistory(sessionID, text)
+	if strings.TrimSpace(text) != "" {
+		m.recordComposeHistory(sessionID, text)
+	}
 	saveHistoryCmd := m.requestAppStateSaveCmd()
 	provider := m.providerForSessionID(sessionID)
 	m.enableFollow(false)
 	m.startRequestActivity(sessionID, provider)
 	token := m.nextSendToken()
-	m.registerPendingSend(token, sessionID, provider, text)
-	headerIndex := m.appendUserMessageLocal(provider, text)
+	echo := composeEchoText(text, attachments)
+	m.registerPendingSend(token, sessionID, provider, echo)
+	headerIndex := m.appendUserMessageLocal(provider, echo)
 	m.setStatusMessage("sending message")
+	if status := composeSlashPassthroughStatus(provider, text); status != "" {
+		m.setStatusMessage(status)
+	}
 	if m.chatInput != nil {
 		m.chatInput.Clear()
 	}
+	m.clearComposeAttachments()
+	m.composeMentions = nil
 	if headerIndex >= 0 {
 		m.registerPendingSendHeader(token, sessionID, provider, headerIndex)
 	}
-	send := sendSessionCmd(m.sessionAPI, sessionID, text, token)
+	send := sendSessionInputCmd(m.sessionAPI, sessionID, text, attachments, mentions, token)
 	reconnectCmds := m.sessionBootstrapCoordinatorOrDefault().BuildReconnectCommands(SessionReconnectBootstrapInput{
 		Provider:                  provider,
 		SessionID:                 sessionID,
@@ -0,0 +1,600 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	xansi "github.com/charmbracelet/x/ansi"
+
+	"control/internal/types"
+)
+
+const (
+	sessionTilesSnapshotLines  = 80
+	sessionTilesReconnectDelay = 2 * time.Second
+)
+
+// enterSessionTilesView tiles the sessions marked in the sidebar. Without
+// marks the saved tile set is reopened, falling back to the selected session.
+func (m *Model) enterSessionTilesView() tea.Cmd {
+	if m == nil || m.sessionTiles == nil {
+		return nil
+	}
+	ids := m.sessionTilesCandidateIDs()
+	if len(ids) == 0 {
+		m.setValidationStatus("select sessions to tile")
+		return nil
+	}
+	switch m.mode {
+	case uiModeCompose:
+		_ = m.saveCurrentComposeDraft()
+		m.closeComposeOptionPicker()
+	case uiModeRecents:
+		m.exitRecentsView()
+	}
+	m.sessionTiles.SetSessions(ids)
+	nextFocus := focusSidebar
+	m.applyModeTransition(modeTransitionRequest{
+		toMode:      uiModeTiles,
+		focus:       &nextFocus,
+		forceReflow: true,
+		before:      m.clearSessionTilesReply,
+		status:      fmt.Sprintf("tiling %d sessions", m.sessionTiles.Len()),
+	})
+	return tea.Batch(m.attachSessionTilesCmd(), m.saveSessionTilesCmd())
+}
+
+func (m *Model) exitSessionTilesView() {
+	if m == nil || m.mode != uiModeTiles {
+		return
+	}
+	nextFocus := focusSidebar
+	m.applyModeTransition(modeTransitionRequest{
+		toMode:      uiModeNormal,
+		focus:       &nextFocus,
+		forceReflow: true,
+		before:      m.clearSessionTilesReply,
+	})
+	m.sessionTiles.Detach()
+}
+
+func (m *Model) toggleSessionTilesView() tea.Cmd {
+	if m.mode == uiModeTiles {
+		m.exitSessionTilesView()
+		return nil
+	}
+	return m.enterSessionTilesView()
+}
+
+func (m *Model) sessionTilesCandidateIDs() []string {
+	var ids []string
+	if m.sidebar != nil && m.sidebar.SelectedKeyCount() > 1 {
+		for _, item := range m.sidebar.SelectedItems() {
+			if item != nil && item.isSession() && item.session != nil {
+				ids = append(ids, item.session.ID)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		ids = m.sessionTiles.SessionIDs()
+	}
+	if len(ids) == 0 {
+		if id := m.selectedSessionID(); id != "" {
+			ids = []string{id}
+		}
+	}
+	known := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if m.sessionByID(id) != nil {
+			known = append(known, id)
+		}
+	}
+	return known
+}
+
+// attachSessionTilesCmd starts following the tiles that are not followed
+// yet: it loads a transcript snapshot, opens the transcript stream and
+// refreshes the pending approvals of each.
+func (m *Model) attachSessionTilesCmd() tea.Cmd {
+	cmds := make([]tea.Cmd, 0, m.sessionTiles.Len()*3)
+	for _, tile := range m.sessionTiles.Tiles() {
+		if tile.attached {
+			continue
+		}
+		tile.attached = true
+		tile.turnActive = m.recents != nil && m.recents.IsRunning(tile.SessionID)
+		if m.sessionTranscriptAPI != nil {
+			cmds = append(cmds,
+				fetchSessionTileSnapshotCmd(m.sessionTranscriptAPI, tile.SessionID, sessionTilesSnapshotLines),
+				openSessionTileStreamCmd(m.sessionTranscriptAPI, tile.SessionID, tile.stream.Revision()),
+			)
+		}
+		if m.sessionAPI != nil {
+			cmds = append(cmds, fetchApprovalsCmdWithContext(m.sessionAPI, tile.SessionID, nil))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) attachedSessionTile(sessionID string) *sessionTile {
+	if m == nil || m.mode != uiModeTiles {
+		return nil
+	}
+	tile := m.sessionTiles.Tile(strings.TrimSpace(sessionID))
+	if tile == nil || !tile.attached {
+		return nil
+	}
+	return tile
+}
+
+func (m *Model) applySessionTileSnapshotMsg(msg sessionTileSnapshotMsg) tea.Cmd {
+	tile := m.attachedSessionTile(msg.id)
+	if tile == nil {
+		return nil
+	}
+	if msg.err != nil {
+		if !isCanceledRequestError(msg.err) {
+			m.setBackgroundError("tile transcript error: " + msg.err.Error())
+		}
+		return nil
+	}
+	if msg.snapshot != nil {
+		_, _ = tile.stream.SetSnapshot(*msg.snapshot)
+	}
+	return nil
+}
+
+func (m *Model) applySessionTileStreamMsg(msg sessionTileStreamMsg) tea.Cmd {
+	tile := m.attachedSessionTile(msg.id)
+	if tile == nil {
+		if msg.cancel != nil {
+			msg.cancel()
+		}
+		return nil
+	}
+	if msg.err != nil {
+		if isCanceledRequestError(msg.err) {
+			return nil
+		}
+		m.setBackgroundError("tile transcript stream error: " + msg.err.Error())
+		return sessionTileReconnectCmd(tile.SessionID, sessionTilesReconnectDelay)
+	}
+	tile.stream.SetStream(msg.ch, msg.cancel)
+	return nil
+}
+
+func (m *Model) reconnectSessionTile(sessionID string) tea.Cmd {
+	tile := m.attachedSessionTile(sessionID)
+	if tile == nil || tile.stream.HasStream() || m.sessionTranscriptAPI == nil {
+		return nil
+	}
+	return openSessionTileStreamCmd(m.sessionTranscriptAPI, tile.SessionID, tile.stream.Revision())
+}
+
+// consumeSessionTilesTick drains the tile transcript streams. Streams are
+// closed once the tiled view is left.
+func (m *Model) consumeSessionTilesTick() tea.Cmd {
+	if m.sessionTiles == nil {
+		return nil
+	}
+	if m.mode != uiModeTiles {
+		if m.sessionTiles.HasAttached() {
+			m.sessionTiles.Detach()
+		}
+		return nil
+	}
+	var cmds []tea.Cmd
+	for _, tile := range m.sessionTiles.Tiles() {
+		if !tile.attached {
+			continue
+		}
+		_, closed, _, signals := tile.stream.ConsumeTick()
+		if signals.TurnStarts > 0 {
+			tile.turnActive = true
+		}
+		if len(signals.CompletionSignals) > 0 {
+			tile.turnActive = false
+		}
+		if signals.ApprovalEvents > 0 && m.sessionAPI != nil {
+			cmds = append(cmds, fetchApprovalsCmdWithContext(m.sessionAPI, tile.SessionID, nil))
+		}
+		if closed {
+			cmds = append(cmds, sessionTileReconnectCmd(tile.SessionID, sessionTilesReconnectDelay))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) syncAppStateSessionTiles() bool {
+	if m == nil || m.sessionTiles == nil {
+		return false
+	}
+	next := m.sessionTiles.ExportAppState()
+	if appStateTilesEqual(m.appState.Tiles, next) {
+		return false
+	}
+	m.appState.Tiles = next
+	m.hasAppState = true
+	return true
+}
+
+func (m *Model) saveSessionTilesCmd() tea.Cmd {
+	if !m.syncAppStateSessionTiles() {
+		return nil
+	}
+	return m.requestAppStateSaveCmd()
+}
+
+func (m *Model) sessionTilePendingApproval(sessionID string) *ApprovalRequest {
+	return m.approvalStateServiceOrDefault().LatestRequest(m.sessionApprovals[sessionID])
+}
+
+// sessionTileState summarizes a tiled session: waiting on an approval, in a
+// turn, idle, or the session status once the session is no longer live.
+func (m *Model) sessionTileState(tile *sessionTile, session *types.Session) string {
+	switch {
+	case m.sessionTilePendingApproval(tile.SessionID) != nil:
+		return "approval"
+	case tile.turnActive:
+		return "working"
+	case session == nil:
+		return "unavailable"
+	}
+	switch session.Status {
+	case types.SessionStatusCreated, types.SessionStatusStarting, types.SessionStatusRunning:
+		return "idle"
+	default:
+		return string(session.Status)
+	}
+}
+
+func (m *Model) sessionTilesHeader() string {
+	return fmt.Sprintf("Tiles • %d sessions", m.sessionTiles.Len())
+}
+
+func (m *Model) sessionTilesReplyFooter() string {
+	return "enter send • esc cancel"
+}
+
+func (m *Model) sessionTilesBody() string {
+	tiles := m.sessionTiles.Tiles()
+	if len(tiles) == 0 {
+		return "No sessions tiled."
+	}
+	width := max(1, m.viewport.Width())
+	height := max(1, m.viewport.Height())
+	columns, rows := m.sessionTiles.Columns(), m.sessionTiles.Rows()
+	focused := m.sessionTiles.FocusedSessionID()
+	out := make([]string, 0, rows)
+	for row := range rows {
+		cells := make([]string, 0, columns)
+		for col := range columns {
+			index := row*columns + col
+			if index >= len(tiles) {
+				break
+			}
+			tile := tiles[index]
+			cells = append(cells, m.renderSessionTile(tile, sessionTileSpan(width, columns, col), sessionTileSpan(height, rows, row), tile.SessionID == focused))
+		}
+		out = append(out, lipgloss.JoinHorizontal(lipgloss.Top, cells...))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, out...)
+}
+
+// sessionTileSpan splits total into parts cells, giving the remainder to the
+// leading cells.
+func sessionTileSpan(total, parts, index int) int {
+	size := total / parts
+	if index < total%parts {
+		size++
+	}
+	return size
+}
+
+func (m *Model) renderSessionTile(tile *sessionTile, width, height int, focused bool) string {
+	innerWidth := max(1, width-2)
+	innerHeight := max(1, height-2)
+	session := m.sessionByID(tile.SessionID)
+	title := sessionTitle(session, m.sessionMeta[tile.SessionID])
+	if strings.TrimSpace(title) == "" {
+		title = tile.SessionID
+	}
+	state := m.sessionTileState(tile, session)
+	titleStyle := chatMetaStyle
+	if focused {
+		titleStyle = headerStyle
+	}
+	header := titleStyle.Render(truncateToWidth(title, max(1, innerWidth-xansi.StringWidth(state)-3))) + " • " + sessionTileStateStyle(state).Render(state)
+	lines := []string{header}
+	if approval := m.sessionTilePendingApproval(tile.SessionID); approval != nil {
+		summary := strings.TrimSpace(approval.Summary)
+		if summary == "" {
+			summary = "approval required"
+		}
+		lines = append(lines, toastWarningStyle.Render(truncateToWidth("⚑ "+summary, innerWidth)))
+	}
+	lines = append(lines, m.sessionTileTail(tile, innerWidth, innerHeight-len(lines))...)
+	for i, line := range lines {
+		lines[i] = xansi.Truncate(line, innerWidth, "")
+	}
+	for len(lines) < innerHeight {
+		lines = append(lines, "")
+	}
+	border := lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(dividerStyle.GetForeground())
+	if focused {
+		border = border.BorderForeground(lipgloss.Color(selectedChatBubbleBorderColor))
+	}
+	return border.Render(padLines(lines[:innerHeight], innerWidth))
+}
+
+func sessionTileStateStyle(state string) lipgloss.Style {
+	switch state {
+	case "approval":
+		return toastWarningStyle
+	case "working":
+		return activityStyle
+	default:
+		return chatMetaStyle
+	}
+}
+
+// sessionTileTail renders the last lines of a tile's transcript. Blocks go
+// through the same projection as the main transcript and are rendered with
+// the tiles' block render cache.
+func (m *Model) sessionTileTail(tile *sessionTile, width, height int) []string {
+	if height <= 0 {
+		return nil
+	}
+	blocks := tile.stream.Blocks()
+	if len(blocks) == 0 {
+		blocks = m.transcriptCache[m.cacheKeyForSession(tile.SessionID)]
+	}
+	if len(blocks) == 0 {
+		return []string{chatMetaStyle.Render("waiting for transcript…")}
+	}
+	blocks = m.transcriptRenderProjectorOrDefault().Project(TranscriptRenderProjectionInput{
+		SessionID:   tile.SessionID,
+		Provider:    m.providerForSessionID(tile.SessionID),
+		Blocks:      blocks,
+		Approvals:   m.sessionApprovals[tile.SessionID],
+		Resolutions: m.sessionApprovalResolutions[tile.SessionID],
+		Composer:    m.transcriptComposerOrDefault(),
+		ApplyOverlay: func(sessionID string, next []ChatBlock) []ChatBlock {
+			return m.applyOptimisticOverlay(sessionID, next)
+		},
+	})
+	if len(blocks) > sessionTilesTailBlocks {
+		blocks = blocks[len(blocks)-sessionTilesTailBlocks:]
+	}
+	text, _ := renderChatBlocksWithRendererAndContext(blocks, width, 0, -1, -1, -1, m.sessionTiles.renderer, chatRenderContext{
+		TimestampMode: m.timestampMode,
+		Now:           m.clockNow,
+		ThemeID:       CurrentThemeID(),
+	})
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) > height {
+		lines = lines[len(lines)-height:]
+	}
+	return lines
+}
+
+func (m *Model) clearSessionTilesReply() {
+	m.tilesReplySessionID = ""
+	if m.tilesReplyInput != nil {
+		m.tilesReplyInput.Blur()
+		m.tilesReplyInput.SetValue("")
+	}
+}
+
+func (m *Model) startSessionTileReply() bool {
+	sessionID := m.sessionTiles.FocusedSessionID()
+	if sessionID == "" || m.tilesReplyInput == nil {
+		return false
+	}
+	m.tilesReplySessionID = sessionID
+	m.tilesReplyInput.SetPlaceholder("message " + sessionTitle(m.sessionByID(sessionID), m.sessionMeta[sessionID]))
+	m.tilesReplyInput.SetValue("")
+	m.tilesReplyInput.Focus()
+	if m.input != nil {
+		m.input.FocusChatInput()
+	}
+	m.setStatusMessage("composing into tile")
+	m.resize(m.width, m.height)
+	return true
+}
+
+func (m *Model) cancelSessionTileReply() {
+	m.clearSessionTilesReply()
+	if m.input != nil {
+		m.input.FocusSidebar()
+	}
+	m.resize(m.width, m.height)
+}
+
+func (m *Model) submitSessionTileReply(text string) tea.Cmd {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		m.setValidationStatus("message is required")
+		return nil
+	}
+	sessionID := strings.TrimSpace(m.tilesReplySessionID)
+	if sessionID == "" {
+		m.setValidationStatus("focus a tile to send")
+		return nil
+	}
+	provider := m.providerForSessionID(sessionID)
+	token := m.nextSendToken()
+	m.registerPendingSend(token, sessionID, provider, text)
+	if tile := m.sessionTiles.Tile(sessionID); tile != nil {
+		tile.turnActive = true
+	}
+	m.cancelSessionTileReply()
+	m.setStatusMessage("sending")
+	return sendSessionCmd(m.sessionAPI, sessionID, text, token)
+}
+
+func (m *Model) approveFocusedSessionTile(decision string) tea.Cmd {
+	sessionID := m.sessionTiles.FocusedSessionID()
+	request := m.sessionTilePendingApproval(sessionID)
+	if request == nil {
+		m.setValidationStatus("no pending approval in this tile")
+		return nil
+	}
+	return m.approveRequestForSession(sessionID, decision, request.RequestID)
+}
+
+func (m *Model) interruptFocusedSessionTile() tea.Cmd {
+	sessionID := m.sessionTiles.FocusedSessionID()
+	session := m.sessionByID(sessionID)
+	if session == nil || !isSessionInterruptible(session.Status) {
+		m.setValidationStatus("focus a running session to interrupt")
+		return nil
+	}
+	m.setStatusMessage("interrupting " + sessionID)
+	return interruptSessionCmd(m.sessionAPI, sessionID)
+}
+
+func (m *Model) openFocusedSessionTile() tea.Cmd {
+	sessionID := m.sessionTiles.FocusedSessionID()
+	if sessionID == "" {
+		return nil
+	}
+	if m.sidebar == nil || !m.sidebar.SelectBySessionID(sessionID) {
+		m.setValidationStatus("session unavailable")
+		return nil
+	}
+	m.exitSessionTilesView()
+	return m.onSelectionChangedImmediate()
+}
+
+func (m *Model) removeFocusedSessionTile() tea.Cmd {
+	if !m.sessionTiles.Remove(m.sessionTiles.FocusedSessionID()) {
+		return nil
+	}
+	if m.sessionTiles.Len() == 0 {
+		m.exitSessionTilesView()
+	}
+	return m.saveSessionTilesCmd()
+}
+
+func (m *Model) reduceSessionTilesMode(msg tea.Msg) (bool, tea.Cmd) {
+	if m.mode != uiModeTiles {
+		return false, nil
+	}
+	if m.tilesReplySessionID != "" && m.tilesReplyInput != nil {
+		if !isTextInputMsg(msg) {
+			return false, nil
+		}
+		controller := textInputModeController{
+			input:             m.tilesReplyInput,
+			keyString:         m.keyString,
+			keyMatchesCommand: m.keyMatchesCommand,
+			onCancel: func() tea.Cmd {
+				m.cancelSessionTileReply()
+				m.setStatusMessage("message canceled")
+				return nil
+			},
+			onSubmit: m.submitSessionTileReply,
+		}
+		handled, cmd := controller.Update(msg)
+		if handled && m.consumeInputHeightChanges(m.tilesReplyInput) {
+			m.resize(m.width, m.height)
+		}
+		return handled, cmd
+	}
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return false, nil
+	}
+	switch {
+	case m.keyMatchesCommand(keyMsg, KeyCommandToggleTiles, "T"):
+		m.exitSessionTilesView()
+		return true, nil
+	case m.keyMatchesCommand(keyMsg, KeyCommandApprove, "y"):
+		return true, m.approveFocusedSessionTile("accept")
+	case m.keyMatchesCommand(keyMsg, KeyCommandDecline, "x"):
+		return true, m.approveFocusedSessionTile("decline")
+	case m.keyMatchesCommand(keyMsg, KeyCommandQuit, "q"),
+		m.keyMatchesCommand(keyMsg, KeyCommandToggleSidebar, "ctrl+b"),
+		m.keyMatchesCommand(keyMsg, KeyCommandMenu, "ctrl+m"):
+		return false, nil
+	}
+	focusChanged := false
+	switch m.keyString(keyMsg) {
+	case "esc":
+		m.exitSessionTilesView()
+		return true, nil
+	case "tab":
+		focusChanged = m.sessionTiles.CycleFocus(1)
+	case "shift+tab", "backtab":
+		focusChanged = m.sessionTiles.CycleFocus(-1)
+	case "left", "h":
+		focusChanged = m.sessionTiles.MoveFocus(-1, 0)
+	case "right", "l":
+		focusChanged = m.sessionTiles.MoveFocus(1, 0)
+	case "up", "k":
+		focusChanged = m.sessionTiles.MoveFocus(0, -1)
+	case "down", "j":
+		focusChanged = m.sessionTiles.MoveFocus(0, 1)
+	case "+", "=":
+		focusChanged = m.sessionTiles.AdjustColumns(1)
+	case "-":
+		focusChanged = m.sessionTiles.AdjustColumns(-1)
+	case "c", "r":
+		if !m.startSessionTileReply() {
+			m.setValidationStatus("focus a tile to compose")
+		}
+	case "i":
+		return true, m.interruptFocusedSessionTile()
+	case "d":
+		return true, m.removeFocusedSessionTile()
+	case "enter":
+		return true, m.openFocusedSessionTile()
+	}
+	// Other keys of the normal mode would act on the hidden main transcript,
+	// so they are swallowed.
+	if focusChanged {
+		return true, m.saveSessionTilesCmd()
+	}
+	return true, nil
+}
+
+// reduceSessionTilesLeftPressMouse focuses the tile under a click in the
+// tiled view.
+func (m *Model) reduceSessionTilesLeftPressMouse(msg tea.MouseMsg, layout mouseLayout) bool {
+	if !isMouseClickMsg(msg) || m.mode != uiModeTiles || m.sessionTiles.Len() == 0 {
+		return false
+	}
+	mouse := msg.Mouse()
+	if mouse.X < layout.rightStart || mouse.Y < 1 || mouse.Y > m.viewport.Height() || m.mouseOverInput(mouse.Y) {
+		return false
+	}
+	x, y := mouse.X-layout.rightStart, mouse.Y-1
+	width, height := max(1, m.viewport.Width()), max(1, m.viewport.Height())
+	columns, rows := m.sessionTiles.Columns(), m.sessionTiles.Rows()
+	col, row := sessionTileIndexAt(width, columns, x), sessionTileIndexAt(height, rows, y)
+	if col < 0 || row < 0 {
+		return true
+	}
+	if m.sessionTiles.FocusIndex(row*columns + col) {
+		m.pendingMouseCmd = m.saveSessionTilesCmd()
+	}
+	return true
+}
+
+// sessionTileIndexAt returns the cell of a sessionTileSpan split that
+// contains offset.
+func sessionTileIndexAt(total, parts, offset int) int {
+	start := 0
+	for index := range parts {
+		size := sessionTileSpan(total, parts, index)
+		if offset >= start && offset < start+size {
+			return index
+		}
+		start += size
+	}
+	return -1
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	xansi "github.com/charmbracelet/x/ansi"
+
+	"control/internal/types"
+)
+
+func newSessionTilesTestModel(t *testing.T) *Model {
+	t.Helper()
+	m := NewModel(nil)
+	m.resize(160, 40)
+	m.appState.ActiveWorkspaceGroupIDs = []string{"ungrouped"}
+	m.workspaces = []*types.Workspace{{ID: "ws1", Name: "Workspace", RepoPath: "/tmp/ws1"}}
+	m.worktrees = map[string][]*types.Worktree{}
+	m.sessions = []*types.Session{
+		{ID: "s1", Title: "First", Status: types.SessionStatusRunning},
+		{ID: "s2", Title: "Second", Status: types.SessionStatusRunning},
+		{ID: "s3", Title: "Third", Status: types.SessionStatusExited},
+	}
+	m.sessionMeta = map[string]*types.SessionMeta{
+		"s1": {SessionID: "s1", WorkspaceID: "ws1"},
+		"s2": {SessionID: "s2", WorkspaceID: "ws1"},
+		"s3": {SessionID: "s3", WorkspaceID: "ws1"},
+	}
+	m.applySidebarItems()
+	for _, key := range []string{"sess:s1", "sess:s2"} {
+		if !m.sidebar.SelectByKey(key) || !m.sidebar.ToggleFocusedSelection() {
+			t.Fatalf("expected to mark %s", key)
+		}
+	}
+	return &m
+}
+
+func TestSessionTilesToggleTilesMarkedSessions(t *testing.T) {
+	m := newSessionTilesTestModel(t)
+
+	nextModel, _ := m.Update(keyRune('T'))
+	next := asModel(t, nextModel)
+	if next.mode != uiModeTiles {
+		t.Fatalf("expected tiles mode, got %v", next.mode)
+	}
+	if got := strings.Join(next.sessionTiles.SessionIDs(), ","); got != "s1,s2" {
+		t.Fatalf("expected marked sessions to be tiled, got %q", got)
+	}
+	if next.appState.Tiles == nil || len(next.appState.Tiles.SessionIDs) != 2 {
+		t.Fatalf("expected tile set to be kept in app state, got %#v", next.appState.Tiles)
+	}
+	header, body := next.modeViewContent()
+	if header != "Tiles • 2 sessions" {
+		t.Fatalf("unexpected header %q", header)
+	}
+	if !strings.Contains(body, "First") || !strings.Contains(body, "Second") {
+		t.Fatalf("expected both tiles in body, got %q", body)
+	}
+
+	nextModel, _ = next.Update(tea.KeyPressMsg{Code: tea.KeyTab})
+	next = asModel(t, nextModel)
+	if next.sessionTiles.FocusedSessionID() != "s2" {
+		t.Fatalf("expected tab to focus s2, got %q", next.sessionTiles.FocusedSessionID())
+	}
+	if next.appState.Tiles.FocusedID != "s2" {
+		t.Fatalf("expected focused tile in app state, got %q", next.appState.Tiles.FocusedID)
+	}
+
+	nextModel, _ = next.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	next = asModel(t, nextModel)
+	if next.mode != uiModeNormal || next.sessionTiles.HasAttached() {
+		t.Fatalf("expected esc to leave tiles and detach streams")
+	}
+}
+
+func TestSessionTilesShowPendingApprovalAndState(t *testing.T) {
+	m := newSessionTilesTestModel(t)
+	m.sessionApprovals["s2"] = []*ApprovalRequest{{RequestID: 7, SessionID: "s2", Summary: "run make"}}
+	m.enterSessionTilesView()
+	m.sessionTiles.Tile("s1").turnActive = true
+
+	_, body := m.modeViewContent()
+	body = xansi.Strip(body)
+	if !strings.Contains(body, "working") {
+		t.Fatalf("expected working state for s1, got %q", body)
+	}
+	if !strings.Contains(body, "⚑ run make") || !strings.Contains(body, "approval") {
+		t.Fatalf("expected pending approval in s2 tile, got %q", body)
+	}
+}
+
+func TestSessionTilesReplySendsToFocusedSession(t *testing.T) {
+	m := newSessionTilesTestModel(t)
+	m.enterSessionTilesView()
+	m.sessionTiles.Focus("s2")
+
+	if handled, _ := m.reduceSessionTilesMode(keyRune('c')); !handled || m.tilesReplySessionID != "s2" {
+		t.Fatalf("expected reply to target s2, got %q", m.tilesReplySessionID)
+	}
+	m.tilesReplyInput.SetValue("hello")
+	handled, cmd := m.reduceSessionTilesMode(tea.KeyPressMsg{Code: tea.KeyEnter})
+	if !handled || cmd == nil {
+		t.Fatalf("expected submit to send")
+	}
+	if m.tilesReplySessionID != "" {
+		t.Fatalf("expected reply to close after sending")
+	}
+	if !m.sessionTiles.Tile("s2").turnActive {
+		t.Fatalf("expected sent tile to be working")
+	}
+}
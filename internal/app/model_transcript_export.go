@@ -0,0 +1,75 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"control/internal/config"
+	"control/internal/transcriptexport"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+const transcriptExportTimeout = 30 * time.Second
+
+var transcriptExportsDir = config.ExportsDir
+
+// exportTranscriptFileCmd writes the session transcript as a self-contained
+// HTML page in the current theme under the exports directory.
+func exportTranscriptFileCmd(api TranscriptExportAPI, sessionID, themeID string) tea.Cmd {
+	return func() tea.Msg {
+		if api == nil {
+			return transcriptExportMsg{sessionID: sessionID, err: errors.New("transcript export is unavailable")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), transcriptExportTimeout)
+		defer cancel()
+		doc, err := api.ExportTranscriptDocument(ctx, sessionID, transcriptexport.DefaultOptions())
+		if err != nil {
+			return transcriptExportMsg{sessionID: sessionID, err: err}
+		}
+		dir, err := transcriptExportsDir()
+		if err != nil {
+			return transcriptExportMsg{sessionID: sessionID, err: err}
+		}
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return transcriptExportMsg{sessionID: sessionID, err: err}
+		}
+		name := sessionID + "-" + time.Now().UTC().Format("20060102-150405") + transcriptexport.FormatHTML.Extension()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(transcriptexport.HTML(*doc, TranscriptExportTheme(themeID))), 0o600); err != nil {
+			return transcriptExportMsg{sessionID: sessionID, err: err}
+		}
+		return transcriptExportMsg{sessionID: sessionID, path: path}
+	}
+}
+
+func exportTranscriptClipboardCmd(api TranscriptExportAPI, sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		if api == nil {
+			return transcriptExportMsg{sessionID: sessionID, err: errors.New("transcript export is unavailable")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), transcriptExportTimeout)
+		defer cancel()
+		data, err := api.ExportTranscript(ctx, sessionID, transcriptexport.FormatMarkdown, transcriptexport.DefaultOptions())
+		if err != nil {
+			return transcriptExportMsg{sessionID: sessionID, err: err}
+		}
+		return transcriptExportMsg{sessionID: sessionID, markdown: string(data)}
+	}
+}
+
+func (m *Model) applyTranscriptExport(msg transcriptExportMsg) tea.Cmd {
+	if msg.err != nil {
+		m.setStatusError("transcript export failed: " + msg.err.Error())
+		return nil
+	}
+	if path := strings.TrimSpace(msg.path); path != "" {
+		m.setStatusInfo("transcript exported to " + path)
+		return nil
+	}
+	return m.copyWithStatusCmd(msg.markdown, "transcript copied as markdown")
+}
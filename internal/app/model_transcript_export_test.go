@@ -0,0 +1,83 @@
+package app
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"control/internal/transcriptexport"
+)
+
+type stubTranscriptExportAPI struct {
+	formats []transcriptexport.Format
+}
+
+func (s *stubTranscriptExportAPI) ExportTranscript(_ context.Context, id string, format transcriptexport.Format, _ transcriptexport.Options) ([]byte, error) {
+	s.formats = append(s.formats, format)
+	return []byte("# Session " + id + "\n"), nil
+}
+
+func (s *stubTranscriptExportAPI) ExportTranscriptDocument(_ context.Context, id string, _ transcriptexport.Options) (*transcriptexport.Document, error) {
+	s.formats = append(s.formats, transcriptexport.FormatJSON)
+	return &transcriptexport.Document{SessionID: id, Entries: []transcriptexport.Entry{
+		{Kind: transcriptexport.EntryMessage, Role: "assistant", Text: "Done."},
+	}}, nil
+}
+
+func TestTranscriptExportThemeMatchesPalette(t *testing.T) {
+	if got := TranscriptExportTheme("default"); got != transcriptexport.DefaultTheme() {
+		t.Fatalf("expected default palette to match the export default, got %#v", got)
+	}
+	if got := TranscriptExportTheme("nordic").Background; got != "#2e3440" {
+		t.Fatalf("unexpected nordic background %q", got)
+	}
+}
+
+func TestSessionContextActionExportTranscriptWritesThemedHTML(t *testing.T) {
+	dir := t.TempDir()
+	previous := transcriptExportsDir
+	transcriptExportsDir = func() (string, error) { return dir, nil }
+	defer func() { transcriptExportsDir = previous }()
+	m := NewModel(nil)
+	api := &stubTranscriptExportAPI{}
+	m.transcriptExportAPI = api
+	m.themeID = "nordic"
+
+	handled, cmd := m.handleSessionContextMenuAction(ContextMenuSessionExportTranscript, contextMenuTarget{sessionID: "s1"})
+	if !handled || cmd == nil {
+		t.Fatalf("expected export command")
+	}
+	runModelCmd(t, &m, cmd)
+
+	if !strings.HasPrefix(m.status, "transcript exported to "+dir) {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+	data, err := os.ReadFile(strings.TrimPrefix(m.status, "transcript exported to "))
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	if !strings.Contains(string(data), "Done.") || !strings.Contains(string(data), "#2e3440") {
+		t.Fatalf("expected nordic html export, got %q", string(data))
+	}
+}
+
+func TestSessionContextActionCopyTranscriptCopiesMarkdown(t *testing.T) {
+	clipboard := &testClipboardService{}
+	m := NewModel(nil, WithClipboardService(clipboard))
+	api := &stubTranscriptExportAPI{}
+	m.transcriptExportAPI = api
+
+	handled, cmd := m.handleSessionContextMenuAction(ContextMenuSessionCopyTranscript, contextMenuTarget{sessionID: "s1"})
+	if !handled || cmd == nil {
+		t.Fatalf("expected copy command")
+	}
+	runModelCmd(t, &m, cmd)
+
+	if len(api.formats) != 1 || api.formats[0] != transcriptexport.FormatMarkdown {
+		t.Fatalf("expected markdown export, got %#v", api.formats)
+	}
+	if clipboard.text != "# Session s1" {
+		t.Fatalf("unexpected clipboard text %q", clipboard.text)
+	}
+}
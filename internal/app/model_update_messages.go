@@ -365,6 +365,26 @@ func (m *Model) reduceMutationMessages(msg tea.Msg) (bool, tea.Cmd) {
 			cmds = append(cmds, m.fetchWorktreesForWorkspace(msg.workspaceID))
 		}
 		return true, tea.Batch(cmds...)
+	case worktreeOperationMsg:
+		return true, m.applyWorktreeOperationResult(msg)
+	case finalizeDraftMsg:
+		m.applyFinalizeDraft(msg)
+		return true, nil
+	case finalizeResultMsg:
+		return true, m.applyFinalizeResult(msg)
+	case approvalInboxLoadedMsg:
+		m.applyApprovalInboxLoaded(msg)
+		return true, nil
+	case approvalInboxStreamMsg:
+		return true, m.applyApprovalInboxStream(msg)
+	case approvalInboxEventMsg:
+		return true, m.applyApprovalInboxEvent(msg)
+	case approvalRulesLoadedMsg:
+		m.applyApprovalRulesLoaded(msg)
+		return true, nil
+	case checkpointRestoreMsg:
+		m.applyCheckpointRestoreResult(msg)
+		return true, nil
 	case updateWorkspaceMsg:
 		if msg.err != nil {
 			m.setStatusError("update workspace error: " + msg.err.Error())
@@ -862,6 +882,12 @@ func (m *Model) reduceStateMessages(msg tea.Msg) (bool, tea.Cmd) {
 		}
 		m.setBackgroundStatus("notes updated")
 		return true, nil
+	case gitDiffMsg:
+		m.applyGitDiffResult(msg)
+		return true, nil
+	case sessionUsageMsg:
+		m.applySessionUsageResult(msg)
+		return true, nil
 	case notesPanelReflowMsg:
 		if !m.notesPanelOpen {
 			return true, nil
@@ -961,6 +987,20 @@ func (m *Model) reduceStateMessages(msg tea.Msg) (bool, tea.Cmd) {
 		}
 		m.setCopyStatusInfo(msg.success)
 		return true, nil
+	case composeAttachmentPastedMsg:
+		m.applyComposeAttachmentPasted(msg)
+		return true, nil
+	case composeMentionCandidatesMsg:
+		m.applyComposeMentionCandidates(msg)
+		return true, nil
+	case composeSnippetsLoadedMsg:
+		m.applyComposeSnippetsLoaded(msg)
+		return true, nil
+	case composeSlashTemplatesMsg:
+		m.applyComposeSlashTemplates(msg)
+		return true, nil
+	case transcriptExportMsg:
+		return true, m.applyTranscriptExport(msg)
 	case fileLinkOpenResultMsg:
 		if msg.err != nil {
 			m.setStatusError("open link failed: " + msg.err.Error())
@@ -1013,6 +1053,13 @@ func (m *Model) reduceStateMessages(msg tea.Msg) (bool, tea.Cmd) {
 		return true, m.handleRecentsPreview(msg)
 	case recentsTurnCompletedMsg:
 		return true, m.handleRecentsTurnCompleted(msg)
+	case sessionTileSnapshotMsg:
+		return true, m.applySessionTileSnapshotMsg(msg)
+	case sessionTileStreamMsg:
+		m.applySessionTileStreamMsg(msg)
+		return true, nil
+	case sessionTileReconnectMsg:
+		return true, m.reconnectSessionTile(msg.id)
 	case historyPollMsg:
 		if msg.id == "" || msg.key == "" {
 			return true, nil
@@ -1137,6 +1184,7 @@ func (m *Model) reduceStateMessages(msg tea.Msg) (bool, tea.Cmd) {
 			m.pendingApproval = m.approvalStateServiceOrDefault().LatestRequest(m.sessionApprovals[msg.id])
 			m.refreshVisibleApprovalBlocks(msg.id)
 		}
+		m.removeApprovalInboxEntry(msg.id, msg.requestID)
 		m.setStatusInfo("approval sent")
 		return true, nil
 	case approvalsMsg:
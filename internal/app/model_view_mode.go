@@ -72,6 +72,9 @@ func (m *Model) modeViewContent() (headerText, bodyText string) {
 		headerText = "Chat"
 	case uiModeRecents:
 		headerText = m.recentsHeader()
+	case uiModeTiles:
+		headerText = m.sessionTilesHeader()
+		bodyText = m.sessionTilesBody()
 	case uiModeApprovalResponse:
 		headerText = "Approval Response"
 		bodyText = m.approvalResponseBody()
@@ -97,6 +100,15 @@ func (m *Model) modeViewContent() (headerText, bodyText string) {
 		if m.renameInput != nil {
 			bodyText = m.renameInput.View()
 		}
+	case uiModeFinalize:
+		headerText = "Finalize"
+		bodyText = m.finalizeBody()
+	case uiModeApprovalRules:
+		headerText = "Approval Rules"
+		bodyText = m.approvalRulesBody()
+	case uiModeApprovalInbox:
+		headerText = m.approvalInboxHeader()
+		bodyText = m.approvalInboxBody()
 	case uiModeGuidedWorkflow:
 		headerText = "Guided Workflow"
 		if pickerView := m.guidedWorkflowPickerBodyView(); pickerView != "" {
@@ -48,6 +48,9 @@ func (m *Model) renderRightPaneView() string {
 	case sidePanelModeContext:
 		panelView = m.renderContextPanelView()
 		panelHeight = lipgloss.Height(panelView)
+	case sidePanelModeDiff:
+		panelView = m.renderDiffPanelView()
+		panelHeight = lipgloss.Height(panelView)
 	default:
 		panelView = m.renderNotesPanelView()
 		panelHeight = lipgloss.Height(panelView)
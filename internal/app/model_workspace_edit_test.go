@@ -50,16 +50,24 @@ func TestWorkspaceEditFlowSubmitsPatchThroughModelReducer(t *testing.T) {
 	}
 
 	m.editWorkspace.input.SetValue("Workspace Two")
-	// Step 3 → step 4 (group picker)
 	handled, cmd = m.reduceWorkspaceEditModes(tea.KeyPressMsg{Code: tea.KeyEnter})
 	if !handled || cmd != nil {
-		t.Fatalf("expected name step to advance to group picker without command")
+		t.Fatalf("expected name step to be handled without command")
 	}
-	if m.editWorkspace.step != 4 {
+
+	// Steps 4 to 7: env profiles, active profile, instructions and context
+	// files, left empty.
+	for i := 0; i < 4; i++ {
+		handled, cmd = m.reduceWorkspaceEditModes(tea.KeyPressMsg{Code: tea.KeyEnter})
+		if !handled || cmd != nil {
+			t.Fatalf("expected optional step to be handled without command")
+		}
+	}
+	if m.editWorkspace.step != 8 {
 		t.Fatalf("expected group picker step, got %d", m.editWorkspace.step)
 	}
 
-	// Step 4: Enter confirms group picker
+	// Step 8: Enter confirms group picker
 	handled, cmd = m.reduceWorkspaceEditModes(tea.KeyPressMsg{Code: tea.KeyEnter})
 	if !handled {
 		t.Fatalf("expected final step to be handled")
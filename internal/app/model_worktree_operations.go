@@ -0,0 +1,74 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+
+	"control/internal/types"
+)
+
+func (m *Model) applyWorktreeOperationResult(msg worktreeOperationMsg) tea.Cmd {
+	if msg.err != nil {
+		m.setStatusError(fmt.Sprintf("worktree %s error: %s", msg.op, msg.err.Error()))
+		return nil
+	}
+	result := msg.result
+	if result == nil {
+		m.setStatusError(fmt.Sprintf("worktree %s error: empty response", msg.op))
+		return nil
+	}
+	switch result.Status {
+	case types.WorktreeOperationOK:
+		status := worktreeOperationSuccessStatus(result)
+		if len(result.Linked) > 0 {
+			status += fmt.Sprintf(" (+%d linked repo(s))", len(result.Linked))
+		}
+		m.setStatusInfo(status)
+	case types.WorktreeOperationConflict:
+		m.setStatusWarning(fmt.Sprintf("%s aborted, conflicts: %s", result.Operation, summarizeWorktreePaths(result.Conflicts)))
+		return nil
+	case types.WorktreeOperationDirty:
+		m.setStatusWarning(fmt.Sprintf("%s refused, uncommitted changes: %s", result.Operation, summarizeWorktreePaths(result.DirtyFiles)))
+		return nil
+	default:
+		m.setStatusWarning(fmt.Sprintf("%s %s", result.Operation, result.Status))
+		return nil
+	}
+	if result.Operation != types.WorktreeOperationRemove {
+		return nil
+	}
+	if msg.worktreeID != "" && msg.worktreeID == m.appState.ActiveWorktreeID {
+		m.appState.ActiveWorktreeID = ""
+		m.hasAppState = true
+	}
+	cmds := []tea.Cmd{m.fetchSessionsCmd(false)}
+	if msg.workspaceID != "" {
+		cmds = append(cmds, m.fetchWorktreesForWorkspace(msg.workspaceID))
+	}
+	return tea.Batch(cmds...)
+}
+
+func worktreeOperationSuccessStatus(result *types.WorktreeOperationResult) string {
+	switch result.Operation {
+	case types.WorktreeOperationMerge:
+		return fmt.Sprintf("merged %s into %s", result.Branch, result.Target)
+	case types.WorktreeOperationRebase:
+		return fmt.Sprintf("rebased %s onto %s", result.Branch, result.Target)
+	case types.WorktreeOperationPush:
+		return fmt.Sprintf("pushed %s to %s", result.Branch, result.Target)
+	case types.WorktreeOperationRemove:
+		return "worktree removed"
+	default:
+		return fmt.Sprintf("worktree %s done", result.Operation)
+	}
+}
+
+func summarizeWorktreePaths(paths []string) string {
+	const maxShown = 3
+	if len(paths) <= maxShown {
+		return strings.Join(paths, ", ")
+	}
+	return fmt.Sprintf("%s (+%d more)", strings.Join(paths[:maxShown], ", "), len(paths)-maxShown)
+}
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"control/internal/types"
+)
+
+type stubWorktreeLifecycleAPI struct {
+	calls  []types.WorktreeOperation
+	result *types.WorktreeOperationResult
+}
+
+func (s *stubWorktreeLifecycleAPI) WorktreeOperation(_ context.Context, workspaceID, worktreeID string, op types.WorktreeOperation, _ types.WorktreeOperationRequest) (*types.WorktreeOperationResult, error) {
+	s.calls = append(s.calls, op)
+	return s.result, nil
+}
+
+func TestWorktreeContextActionRebaseRunsOperation(t *testing.T) {
+	m := NewModel(nil)
+	api := &stubWorktreeLifecycleAPI{result: &types.WorktreeOperationResult{
+		Operation: types.WorktreeOperationRebase,
+		Status:    types.WorktreeOperationOK,
+		Branch:    "feature",
+		Target:    "main",
+	}}
+	m.worktreeLifecycleAPI = api
+
+	handled, cmd := m.handleWorktreeContextMenuAction(ContextMenuWorktreeRebase, contextMenuTarget{workspaceID: "ws1", worktreeID: "wt1"})
+	if !handled || cmd == nil {
+		t.Fatalf("expected rebase action to return a command")
+	}
+	msg, ok := cmd().(worktreeOperationMsg)
+	if !ok {
+		t.Fatalf("expected worktreeOperationMsg")
+	}
+	if len(api.calls) != 1 || api.calls[0] != types.WorktreeOperationRebase {
+		t.Fatalf("expected rebase call, got %#v", api.calls)
+	}
+	m.applyWorktreeOperationResult(msg)
+	if m.status != "rebased feature onto main" {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+}
+
+func TestWorktreeContextActionRemoveRequiresConfirmation(t *testing.T) {
+	m := NewModel(nil)
+	api := &stubWorktreeLifecycleAPI{}
+	m.worktreeLifecycleAPI = api
+
+	handled, cmd := m.handleWorktreeContextMenuAction(ContextMenuWorktreeRemove, contextMenuTarget{workspaceID: "ws1", worktreeID: "wt1"})
+	if !handled || cmd != nil {
+		t.Fatalf("expected remove to wait for confirmation")
+	}
+	if m.pendingConfirm.kind != confirmRemoveWorktree || m.pendingConfirm.worktreeID != "wt1" {
+		t.Fatalf("unexpected pending confirm %#v", m.pendingConfirm)
+	}
+	if len(api.calls) != 0 {
+		t.Fatalf("expected no operation before confirmation")
+	}
+}
+
+func TestApplyWorktreeOperationResultReportsConflicts(t *testing.T) {
+	m := NewModel(nil)
+	cmd := m.applyWorktreeOperationResult(worktreeOperationMsg{
+		workspaceID: "ws1",
+		worktreeID:  "wt1",
+		op:          types.WorktreeOperationMerge,
+		result: &types.WorktreeOperationResult{
+			Operation: types.WorktreeOperationMerge,
+			Status:    types.WorktreeOperationConflict,
+			Conflicts: []string{"a.go", "b.go", "c.go", "d.go"},
+		},
+	})
+	if cmd != nil {
+		t.Fatalf("expected no follow-up command for a conflicted merge")
+	}
+	if !strings.Contains(m.status, "merge aborted, conflicts: a.go, b.go, c.go (+1 more)") {
+		t.Fatalf("unexpected status %q", m.status)
+	}
+}
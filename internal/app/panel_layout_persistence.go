@@ -49,6 +49,10 @@ var panelLayoutPersistencePolicies = map[sidePanelMode]PanelLayoutPersistencePol
 		get: func(state *types.AppState) int { return state.ContextPanelWidth },
 		set: func(state *types.AppState, width int) { state.ContextPanelWidth = width },
 	},
+	sidePanelModeDiff: panelWidthAccessors{
+		get: func(state *types.AppState) int { return state.DiffPanelWidth },
+		set: func(state *types.AppState, width int) { state.DiffPanelWidth = width },
+	},
 }
 
 func panelLayoutPersistencePolicy(mode sidePanelMode) PanelLayoutPersistencePolicy {
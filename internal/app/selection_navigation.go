@@ -85,7 +85,7 @@ func (defaultSelectionTransitionService) SelectionChanged(m *Model, delay time.D
 	service.applySelectionFocusTransition(m, item, source, focusPolicy)
 	outcome := service.resolveSelectionTransitionOutcome(m, item, delay)
 	cmd := service.withSelectionStatePersistence(m, outcome)
-	return m.batchWithNotesPanelSync(cmd)
+	return m.batchWithContextUsageRefresh(m.batchWithDiffPanelSync(m.batchWithNotesPanelSync(cmd)))
 }
 
 func (defaultSelectionTransitionService) applySelectionFocusTransition(m *Model, item *sidebarItem, source selectionChangeSource, focusPolicy SelectionFocusPolicy) {
@@ -0,0 +1,279 @@
+package app
+
+import (
+	"math"
+	"slices"
+	"strings"
+
+	"control/internal/types"
+)
+
+const (
+	sessionTilesMaxSessions     = 9
+	sessionTilesMaxColumns      = 4
+	sessionTilesTailBlocks      = 12
+	sessionTilesRenderCacheSize = 512
+)
+
+// sessionTile follows one session of the tiled view with its own transcript
+// stream.
+type sessionTile struct {
+	SessionID  string
+	stream     *TranscriptStreamController
+	attached   bool
+	turnActive bool
+}
+
+// SessionTilesController keeps the sessions of the tiled view, their
+// transcript follow state and the grid layout.
+type SessionTilesController struct {
+	tiles            []*sessionTile
+	focus            int
+	columns          int
+	maxEventsPerTick int
+	renderer         chatBlockRenderer
+}
+
+func NewSessionTilesController(maxEventsPerTick int) *SessionTilesController {
+	return &SessionTilesController{
+		maxEventsPerTick: maxEventsPerTick,
+		renderer:         newCachedChatBlockRenderer(defaultChatBlockRenderer{}, newBlockRenderCache(sessionTilesRenderCacheSize)),
+	}
+}
+
+func (c *SessionTilesController) Len() int {
+	if c == nil {
+		return 0
+	}
+	return len(c.tiles)
+}
+
+func (c *SessionTilesController) Tiles() []*sessionTile {
+	if c == nil {
+		return nil
+	}
+	return append([]*sessionTile(nil), c.tiles...)
+}
+
+func (c *SessionTilesController) SessionIDs() []string {
+	if c == nil {
+		return nil
+	}
+	ids := make([]string, 0, len(c.tiles))
+	for _, tile := range c.tiles {
+		ids = append(ids, tile.SessionID)
+	}
+	return ids
+}
+
+// SetSessions replaces the tiled sessions. Tiles of sessions that stay keep
+// their transcript state; the others are detached.
+func (c *SessionTilesController) SetSessions(ids []string) {
+	if c == nil {
+		return
+	}
+	focused := c.FocusedSessionID()
+	existing := make(map[string]*sessionTile, len(c.tiles))
+	for _, tile := range c.tiles {
+		existing[tile.SessionID] = tile
+	}
+	next := make([]*sessionTile, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || slices.ContainsFunc(next, func(tile *sessionTile) bool { return tile.SessionID == id }) {
+			continue
+		}
+		if len(next) >= sessionTilesMaxSessions {
+			break
+		}
+		tile, ok := existing[id]
+		if !ok {
+			tile = &sessionTile{SessionID: id, stream: NewTranscriptStreamController(c.maxEventsPerTick)}
+		}
+		delete(existing, id)
+		next = append(next, tile)
+	}
+	for _, tile := range existing {
+		tile.stream.Reset()
+	}
+	c.tiles = next
+	c.focus = 0
+	c.Focus(focused)
+}
+
+func (c *SessionTilesController) Tile(sessionID string) *sessionTile {
+	if c == nil {
+		return nil
+	}
+	for _, tile := range c.tiles {
+		if tile.SessionID == sessionID {
+			return tile
+		}
+	}
+	return nil
+}
+
+func (c *SessionTilesController) Focused() *sessionTile {
+	if c == nil || len(c.tiles) == 0 {
+		return nil
+	}
+	return c.tiles[min(c.focus, len(c.tiles)-1)]
+}
+
+func (c *SessionTilesController) FocusedSessionID() string {
+	if tile := c.Focused(); tile != nil {
+		return tile.SessionID
+	}
+	return ""
+}
+
+func (c *SessionTilesController) Focus(sessionID string) bool {
+	if c == nil {
+		return false
+	}
+	for i, tile := range c.tiles {
+		if tile.SessionID == sessionID {
+			changed := c.focus != i
+			c.focus = i
+			return changed
+		}
+	}
+	return false
+}
+
+func (c *SessionTilesController) FocusIndex(index int) bool {
+	if c == nil || index < 0 || index >= len(c.tiles) || index == c.focus {
+		return false
+	}
+	c.focus = index
+	return true
+}
+
+// MoveFocus moves the focus by dx columns and dy rows, staying inside the
+// grid.
+func (c *SessionTilesController) MoveFocus(dx, dy int) bool {
+	if c == nil || len(c.tiles) == 0 {
+		return false
+	}
+	columns := c.Columns()
+	row, col := c.focus/columns, c.focus%columns
+	col = max(0, min(columns-1, col+dx))
+	row = max(0, row+dy)
+	return c.FocusIndex(min(len(c.tiles)-1, row*columns+col))
+}
+
+// CycleFocus moves the focus to the next or previous tile, wrapping around.
+func (c *SessionTilesController) CycleFocus(step int) bool {
+	if c == nil || len(c.tiles) < 2 {
+		return false
+	}
+	return c.FocusIndex(((c.focus+step)%len(c.tiles) + len(c.tiles)) % len(c.tiles))
+}
+
+// Remove drops the tile of sessionID and detaches its stream.
+func (c *SessionTilesController) Remove(sessionID string) bool {
+	if c == nil {
+		return false
+	}
+	for i, tile := range c.tiles {
+		if tile.SessionID != sessionID {
+			continue
+		}
+		tile.stream.Reset()
+		c.tiles = slices.Delete(c.tiles, i, i+1)
+		if c.focus >= len(c.tiles) {
+			c.focus = max(0, len(c.tiles)-1)
+		}
+		return true
+	}
+	return false
+}
+
+// Columns returns the column count of the grid. Without a configured count
+// the grid is kept as square as possible.
+func (c *SessionTilesController) Columns() int {
+	if c == nil || len(c.tiles) == 0 {
+		return 1
+	}
+	columns := c.columns
+	if columns <= 0 {
+		columns = int(math.Ceil(math.Sqrt(float64(len(c.tiles)))))
+	}
+	return max(1, min(min(columns, sessionTilesMaxColumns), len(c.tiles)))
+}
+
+func (c *SessionTilesController) Rows() int {
+	if c == nil || len(c.tiles) == 0 {
+		return 1
+	}
+	columns := c.Columns()
+	return (len(c.tiles) + columns - 1) / columns
+}
+
+func (c *SessionTilesController) AdjustColumns(delta int) bool {
+	if c == nil || delta == 0 {
+		return false
+	}
+	next := max(1, min(sessionTilesMaxColumns, c.Columns()+delta))
+	if next == c.columns {
+		return false
+	}
+	c.columns = next
+	return true
+}
+
+// Detach closes the transcript streams of all tiles. The tiles are followed
+// again once they are reattached.
+func (c *SessionTilesController) Detach() {
+	if c == nil {
+		return
+	}
+	for _, tile := range c.tiles {
+		tile.stream.Reset()
+		tile.attached = false
+	}
+}
+
+func (c *SessionTilesController) HasAttached() bool {
+	if c == nil {
+		return false
+	}
+	for _, tile := range c.tiles {
+		if tile.attached {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *SessionTilesController) ImportAppState(state *types.AppStateTiles) {
+	if c == nil {
+		return
+	}
+	if state == nil {
+		c.SetSessions(nil)
+		c.columns = 0
+		return
+	}
+	c.SetSessions(state.SessionIDs)
+	c.columns = max(0, min(sessionTilesMaxColumns, state.Columns))
+	c.Focus(strings.TrimSpace(state.FocusedID))
+}
+
+func (c *SessionTilesController) ExportAppState() *types.AppStateTiles {
+	if c == nil || len(c.tiles) == 0 {
+		return nil
+	}
+	return &types.AppStateTiles{
+		SessionIDs: c.SessionIDs(),
+		Columns:    c.columns,
+		FocusedID:  c.FocusedSessionID(),
+	}
+}
+
+func appStateTilesEqual(a, b *types.AppStateTiles) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Columns == b.Columns && a.FocusedID == b.FocusedID && slices.Equal(a.SessionIDs, b.SessionIDs)
+}
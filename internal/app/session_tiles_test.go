@@ -0,0 +1,82 @@
+package app
+
+import (
+	"slices"
+	"testing"
+
+	"control/internal/types"
+)
+
+func TestSessionTilesSetSessionsDedupesCapsAndKeepsFocus(t *testing.T) {
+	c := NewSessionTilesController(10)
+	c.SetSessions([]string{"s1", "s2", " s2 ", "", "s3"})
+	if got := c.SessionIDs(); !slices.Equal(got, []string{"s1", "s2", "s3"}) {
+		t.Fatalf("unexpected sessions %#v", got)
+	}
+	kept := c.Tile("s2")
+	c.Focus("s2")
+	c.SetSessions([]string{"s3", "s2"})
+	if c.Tile("s2") != kept {
+		t.Fatalf("expected remaining tile to keep its state")
+	}
+	if c.FocusedSessionID() != "s2" {
+		t.Fatalf("expected focus to stay on s2, got %q", c.FocusedSessionID())
+	}
+
+	ids := make([]string, 0, sessionTilesMaxSessions+2)
+	for i := range sessionTilesMaxSessions + 2 {
+		ids = append(ids, string(rune('a'+i)))
+	}
+	c.SetSessions(ids)
+	if c.Len() != sessionTilesMaxSessions {
+		t.Fatalf("expected %d tiles, got %d", sessionTilesMaxSessions, c.Len())
+	}
+}
+
+func TestSessionTilesGridAndFocusMovement(t *testing.T) {
+	c := NewSessionTilesController(10)
+	c.SetSessions([]string{"s1", "s2", "s3", "s4", "s5"})
+	if c.Columns() != 3 || c.Rows() != 2 {
+		t.Fatalf("expected 3x2 grid, got %dx%d", c.Columns(), c.Rows())
+	}
+	c.MoveFocus(0, 1)
+	if c.FocusedSessionID() != "s4" {
+		t.Fatalf("expected s4 below s1, got %q", c.FocusedSessionID())
+	}
+	c.MoveFocus(2, 0)
+	if c.FocusedSessionID() != "s5" {
+		t.Fatalf("expected focus to clamp to the last tile, got %q", c.FocusedSessionID())
+	}
+	c.CycleFocus(1)
+	if c.FocusedSessionID() != "s1" {
+		t.Fatalf("expected focus to wrap to s1, got %q", c.FocusedSessionID())
+	}
+	if !c.AdjustColumns(-1) || c.Columns() != 2 || c.Rows() != 3 {
+		t.Fatalf("expected 2 columns and 3 rows, got %dx%d", c.Columns(), c.Rows())
+	}
+	c.Focus("s5")
+	if !c.Remove("s5") || c.FocusedSessionID() != "s4" {
+		t.Fatalf("expected focus to move to s4 after removal, got %q", c.FocusedSessionID())
+	}
+}
+
+func TestSessionTilesAppStateRoundTrip(t *testing.T) {
+	c := NewSessionTilesController(10)
+	c.SetSessions([]string{"s1", "s2"})
+	c.AdjustColumns(1)
+	c.Focus("s2")
+	state := c.ExportAppState()
+
+	restored := NewSessionTilesController(10)
+	restored.ImportAppState(state)
+	if !appStateTilesEqual(state, restored.ExportAppState()) {
+		t.Fatalf("expected round trip, got %#v", restored.ExportAppState())
+	}
+	restored.ImportAppState(nil)
+	if restored.Len() != 0 || restored.ExportAppState() != nil {
+		t.Fatalf("expected nil state to clear tiles")
+	}
+	if appStateTilesEqual(state, &types.AppStateTiles{SessionIDs: []string{"s1"}}) {
+		t.Fatalf("expected different tile sets to differ")
+	}
+}
@@ -9,6 +9,7 @@ type SidePanelModePolicy interface {
 type SidePanelModeInput struct {
 	DebugStreamsEnabled bool
 	NotesPanelOpen      bool
+	DiffPanelOpen       bool
 	ContextPanelEnabled bool
 	HasContextSession   bool
 }
@@ -46,6 +47,9 @@ func resolveSidePanelMode(input SidePanelModeInput) sidePanelMode {
 	if input.NotesPanelOpen {
 		return sidePanelModeNotes
 	}
+	if input.DiffPanelOpen {
+		return sidePanelModeDiff
+	}
 	if input.ContextPanelEnabled && input.HasContextSession {
 		return sidePanelModeContext
 	}
@@ -59,6 +63,7 @@ func (m *Model) sidePanelModeInput() SidePanelModeInput {
 	return SidePanelModeInput{
 		DebugStreamsEnabled: m.appState.DebugStreamsEnabled,
 		NotesPanelOpen:      m.notesPanelOpen,
+		DiffPanelOpen:       m.diffPanelOpen,
 		ContextPanelEnabled: m.contextPanelEnabled(),
 		HasContextSession:   strings.TrimSpace(m.contextPanelSessionID()) != "",
 	}
@@ -83,6 +83,9 @@ type themePalette struct {
 	ApproveButtonFg                 string
 	DeclineButtonFg                 string
 	NotesFilterButtonOffFg          string
+	DiffAddedFg                     string
+	DiffDeletedFg                   string
+	DiffHunkFg                      string
 	GuidedWorkflowPromptFrameBorder string
 	ToastInfoFg                     string
 	ToastInfoBg                     string
@@ -153,6 +156,9 @@ var (
 	approveButtonStyle              lipgloss.Style
 	declineButtonStyle              lipgloss.Style
 	notesFilterButtonOffStyle       lipgloss.Style
+	diffAddedStyle                  lipgloss.Style
+	diffDeletedStyle                lipgloss.Style
+	diffHunkStyle                   lipgloss.Style
 	guidedWorkflowPromptFrameStyle  lipgloss.Style
 	toastInfoStyle                  lipgloss.Style
 	toastWarningStyle               lipgloss.Style
@@ -262,6 +268,9 @@ func applyTheme(preset ThemePreset) {
 	approveButtonStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.ApproveButtonFg)).Bold(true).Underline(true)
 	declineButtonStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.DeclineButtonFg)).Bold(true).Underline(true)
 	notesFilterButtonOffStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.NotesFilterButtonOffFg)).Underline(true)
+	diffAddedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.DiffAddedFg))
+	diffDeletedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.DiffDeletedFg))
+	diffHunkStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(p.DiffHunkFg)).Faint(true)
 	guidedWorkflowPromptFrameStyle = lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(lipgloss.Color(p.GuidedWorkflowPromptFrameBorder)).
@@ -350,6 +350,9 @@ func paletteFromScheme(s themeScheme) themePalette {
 	p.ApproveButtonFg = s.Success
 	p.DeclineButtonFg = s.Danger
 	p.NotesFilterButtonOffFg = s.FgMuted
+	p.DiffAddedFg = s.Success
+	p.DiffDeletedFg = s.Danger
+	p.DiffHunkFg = s.Accent
 	p.GuidedWorkflowPromptFrameBorder = s.Workspace
 	p.ToastInfoFg = s.FgStrong
 	p.ToastInfoBg = s.AccentAlt
@@ -443,6 +446,9 @@ func defaultThemePalette() themePalette {
 		ApproveButtonFg:                 "70",
 		DeclineButtonFg:                 "203",
 		NotesFilterButtonOffFg:          "245",
+		DiffAddedFg:                     "70",
+		DiffDeletedFg:                   "203",
+		DiffHunkFg:                      "117",
 		GuidedWorkflowPromptFrameBorder: "69",
 		ToastInfoFg:                     "230",
 		ToastInfoBg:                     "29",
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"charm.land/lipgloss/v2"
+
+	"control/internal/transcriptexport"
+)
+
+// TranscriptExportTheme returns the colors of theme themeID for HTML
+// transcript exports.
+func TranscriptExportTheme(themeID string) transcriptexport.Theme {
+	p := resolveThemePreset(themeID).palette
+	return transcriptexport.Theme{
+		Background:  themeHexColor(p.MainPaneBg),
+		Surface:     themeHexColor(p.SidebarPaneBg),
+		UserSurface: themeHexColor(p.UserBubbleBg),
+		Text:        themeHexColor(p.AgentBubbleFg),
+		Muted:       themeHexColor(p.ChatMetaFg),
+		Accent:      themeHexColor(p.HeaderFg),
+		Border:      themeHexColor(p.DividerFg),
+		User:        themeHexColor(p.UserBubbleBorderFg),
+		Assistant:   themeHexColor(p.AgentBubbleBorderFg),
+		Reasoning:   themeHexColor(p.ReasoningBubbleBorderFg),
+		Tool:        themeHexColor(p.SystemBubbleBorderFg),
+		Approval:    themeHexColor(p.ApprovalBubbleBorderFg),
+	}
+}
+
+// themeHexColor converts a palette value, either "#rrggbb" or an ANSI
+// color number, to "#rrggbb".
+func themeHexColor(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	r, g, b, _ := lipgloss.Color(value).RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
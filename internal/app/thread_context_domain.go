@@ -13,8 +13,9 @@ type ThreadContextMetrics struct {
 }
 
 type ThreadContextPanelData struct {
-	ThreadTitle string
-	Metrics     ThreadContextMetrics
+	ThreadTitle  string
+	Metrics      ThreadContextMetrics
+	Instructions *types.SessionInstructions
 }
 
 type ThreadContextMetricsInput struct {
@@ -22,6 +23,7 @@ type ThreadContextMetricsInput struct {
 	SessionID   string
 	Session     *types.Session
 	SessionMeta *types.SessionMeta
+	Usage       *types.SessionUsage
 }
 
 type ThreadContextProviderMetricsAdapter interface {
@@ -81,5 +83,9 @@ func (s defaultThreadContextMetricsService) BuildPanelData(input ThreadContextMe
 			metrics = adapter.Metrics(input)
 		}
 	}
-	return ThreadContextPanelData{ThreadTitle: title, Metrics: metrics}
+	data := ThreadContextPanelData{ThreadTitle: title, Metrics: metrics}
+	if input.SessionMeta != nil {
+		data.Instructions = types.CloneSessionInstructions(input.SessionMeta.Instructions)
+	}
+	return data
 }
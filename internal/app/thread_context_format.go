@@ -5,6 +5,8 @@ import (
 	"math"
 	"strconv"
 	"strings"
+
+	"control/internal/types"
 )
 
 func formatTokensOrDash(tokens *int64) string {
@@ -52,3 +54,25 @@ func formatIntWithCommas(value int64) string {
 	}
 	return b.String()
 }
+
+func formatSessionInstructions(applied *types.SessionInstructions) []string {
+	if applied == nil {
+		return nil
+	}
+	var lines []string
+	if applied.Instructions || len(applied.ContextFiles) > 0 {
+		via := strings.ReplaceAll(string(applied.Strategy), "_", " ")
+		if applied.Pending {
+			via += ", pending first message"
+		}
+		lines = append(lines, "via "+via)
+	}
+	if applied.Instructions {
+		lines = append(lines, "workspace instructions")
+	}
+	lines = append(lines, applied.ContextFiles...)
+	for _, file := range applied.Missing {
+		lines = append(lines, file+" (missing)")
+	}
+	return lines
+}
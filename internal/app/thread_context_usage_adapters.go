@@ -0,0 +1,47 @@
+package app
+
+import "control/internal/types"
+
+// sessionUsageMetricsAdapter reads the panel metrics from the usage snapshot
+// the daemon keeps for a session. The daemon normalizes every provider's
+// reports into the same snapshot, so one adapter serves all of them; it is
+// registered per provider so a provider can be given its own later.
+type sessionUsageMetricsAdapter struct {
+	provider string
+}
+
+func defaultThreadContextProviderMetricsAdapters() []ThreadContextProviderMetricsAdapter {
+	providers := []string{"codex", "claude", "opencode", "kilocode", "hermes"}
+	adapters := make([]ThreadContextProviderMetricsAdapter, 0, len(providers))
+	for _, provider := range providers {
+		adapters = append(adapters, sessionUsageMetricsAdapter{provider: provider})
+	}
+	return adapters
+}
+
+func (a sessionUsageMetricsAdapter) ProviderName() string {
+	return a.provider
+}
+
+func (a sessionUsageMetricsAdapter) Metrics(input ThreadContextMetricsInput) ThreadContextMetrics {
+	return threadContextMetricsFromUsage(input.Usage)
+}
+
+func threadContextMetricsFromUsage(usage *types.SessionUsage) ThreadContextMetrics {
+	metrics := ThreadContextMetrics{}
+	if usage == nil {
+		return metrics
+	}
+	if usage.Tokens.Total > 0 {
+		total := usage.Tokens.Total
+		metrics.Tokens = &total
+	}
+	if percent, ok := usage.ContextUsedPercent(); ok {
+		metrics.ContextUsedPct = &percent
+	}
+	if usage.CostUSD != nil {
+		spend := *usage.CostUSD
+		metrics.SpendUSD = &spend
+	}
+	return metrics
+}
@@ -26,6 +26,8 @@ type TranscriptTickSignals struct {
 	SnapshotEvents    int
 	FinalizedDedupes  int
 	ControlEvents     int
+	TurnStarts        int
+	ApprovalEvents    int
 	CompletionSignals []TranscriptCompletionSignal
 	RevisionRewind    bool
 	Generation        uint64
@@ -170,6 +172,12 @@ func (c *TranscriptStreamController) ConsumeTick() (changed bool, closed bool, s
 			}
 			if eventSignal {
 				signal = true
+				switch event.Kind {
+				case transcriptdomain.TranscriptEventTurnStarted:
+					signals.TurnStarts++
+				case transcriptdomain.TranscriptEventApprovalPending, transcriptdomain.TranscriptEventApprovalResolved:
+					signals.ApprovalEvents++
+				}
 			}
 			if rewind {
 				signals.RevisionRewind = true
@@ -43,9 +43,13 @@ func defaultTransientOverlayProviders() []TransientOverlayProvider {
 		confirmOverlayProvider{},
 		composeOptionPickerOverlayProvider{},
 		composeFileSearchOverlayProvider{},
+		composeMentionOverlayProvider{},
+		composeSnippetOverlayProvider{},
+		composeSlashOverlayProvider{},
 		loadingOverlayProvider{},
 		statusHistoryOverlayProvider{},
 		settingsMenuOverlayProvider{},
+		commandPaletteOverlayProvider{},
 		toastOverlayProvider{},
 	}
 }
@@ -154,6 +158,45 @@ func (composeFileSearchOverlayProvider) Build(m *Model, _ TransientOverlayContex
 	return LayerOverlay{X: x, Y: y, Block: popup}, true
 }
 
+type composeMentionOverlayProvider struct{}
+
+func (composeMentionOverlayProvider) Build(m *Model, _ TransientOverlayContext) (LayerOverlay, bool) {
+	if m == nil {
+		return LayerOverlay{}, false
+	}
+	popup, x, y := m.composeMentionPopupPlacement()
+	if popup == "" {
+		return LayerOverlay{}, false
+	}
+	return LayerOverlay{X: x, Y: y, Block: popup}, true
+}
+
+type composeSnippetOverlayProvider struct{}
+
+func (composeSnippetOverlayProvider) Build(m *Model, _ TransientOverlayContext) (LayerOverlay, bool) {
+	if m == nil {
+		return LayerOverlay{}, false
+	}
+	popup, x, y := m.composeSnippetPopupPlacement()
+	if popup == "" {
+		return LayerOverlay{}, false
+	}
+	return LayerOverlay{X: x, Y: y, Block: popup}, true
+}
+
+type composeSlashOverlayProvider struct{}
+
+func (composeSlashOverlayProvider) Build(m *Model, _ TransientOverlayContext) (LayerOverlay, bool) {
+	if m == nil {
+		return LayerOverlay{}, false
+	}
+	popup, x, y := m.composeSlashPopupPlacement()
+	if popup == "" {
+		return LayerOverlay{}, false
+	}
+	return LayerOverlay{X: x, Y: y, Block: popup}, true
+}
+
 type statusHistoryOverlayProvider struct{}
 
 func (statusHistoryOverlayProvider) Build(m *Model, ctx TransientOverlayContext) (LayerOverlay, bool) {
@@ -185,6 +228,19 @@ func (settingsMenuOverlayProvider) Build(m *Model, ctx TransientOverlayContext)
 	return LayerOverlay{X: x, Y: y, Block: settingsBlock}, true
 }
 
+type commandPaletteOverlayProvider struct{}
+
+func (commandPaletteOverlayProvider) Build(m *Model, ctx TransientOverlayContext) (LayerOverlay, bool) {
+	if m == nil || !m.commandPalette.IsOpen() {
+		return LayerOverlay{}, false
+	}
+	block, x, y := m.commandPalette.View(m.width, ctx.BodyHeight)
+	if block == "" {
+		return LayerOverlay{}, false
+	}
+	return LayerOverlay{X: x, Y: y, Block: block}, true
+}
+
 type toastOverlayProvider struct{}
 
 func (toastOverlayProvider) Build(m *Model, ctx TransientOverlayContext) (LayerOverlay, bool) {
@@ -11,6 +11,10 @@ type workspaceFormData struct {
 	SessionSubpath           string
 	AdditionalDirectoriesRaw string
 	Name                     string
+	EnvProfilesRaw           string
+	EnvProfile               string
+	Instructions             string
+	ContextFilesRaw          string
 	GroupIDs                 []string
 }
 
@@ -26,12 +30,30 @@ func workspacePatchFromForm(form workspaceFormData) *types.WorkspacePatch {
 	if gids == nil {
 		gids = []string{}
 	}
+	envProfile := strings.TrimSpace(form.EnvProfile)
+	instructions := strings.TrimSpace(form.Instructions)
+	contextFiles := parseAdditionalDirectories(form.ContextFilesRaw)
+	if contextFiles == nil {
+		contextFiles = []string{}
+	}
 
-	return &types.WorkspacePatch{
+	patch := &types.WorkspacePatch{
 		Name:                  &trimmedName,
 		RepoPath:              &trimmedPath,
 		SessionSubpath:        &trimmedSubpath,
 		AdditionalDirectories: &directories,
 		GroupIDs:              &gids,
+		EnvProfile:            &envProfile,
+		Instructions:          &instructions,
+		ContextFiles:          &contextFiles,
+	}
+	// Profiles are validated as they are typed; leave them untouched rather
+	// than clearing them if the raw value does not parse.
+	if profiles, err := types.ParseEnvProfiles(form.EnvProfilesRaw); err == nil {
+		if profiles == nil {
+			profiles = []types.EnvProfile{}
+		}
+		patch.EnvProfiles = &profiles
 	}
+	return patch
 }
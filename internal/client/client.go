@@ -11,12 +11,15 @@ import (
 	"net/url"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	"control/internal/config"
 	"control/internal/guidedworkflows"
+	"control/internal/tracing"
+	"control/internal/transcriptexport"
 	"control/internal/types"
 )
 
@@ -294,6 +297,154 @@ func (c *Client) PinSessionMessage(ctx context.Context, sessionID string, req Pi
 	return &resp, nil
 }
 
+// ListSnippets returns the snippets available in workspaceID, or every
+// stored snippet when workspaceID is empty.
+func (c *Client) ListSnippets(ctx context.Context, workspaceID string) ([]*types.Snippet, error) {
+	path := "/v1/snippets"
+	if workspaceID = strings.TrimSpace(workspaceID); workspaceID != "" {
+		path += "?" + url.Values{"workspace_id": {workspaceID}}.Encode()
+	}
+	var resp SnippetsResponse
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, true, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Snippets, nil
+}
+
+func (c *Client) GetSnippet(ctx context.Context, id string) (*types.Snippet, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, errors.New("snippet id is required")
+	}
+	var resp types.Snippet
+	if err := c.doJSON(ctx, http.MethodGet, "/v1/snippets/"+url.PathEscape(id), nil, true, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) CreateSnippet(ctx context.Context, snippet *types.Snippet) (*types.Snippet, error) {
+	if snippet == nil {
+		return nil, errors.New("snippet is required")
+	}
+	var resp types.Snippet
+	if err := c.doJSON(ctx, http.MethodPost, "/v1/snippets", snippet, true, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) UpdateSnippet(ctx context.Context, id string, snippet *types.Snippet) (*types.Snippet, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, errors.New("snippet id is required")
+	}
+	if snippet == nil {
+		return nil, errors.New("snippet is required")
+	}
+	var resp types.Snippet
+	if err := c.doJSON(ctx, http.MethodPatch, "/v1/snippets/"+url.PathEscape(id), snippet, true, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) DeleteSnippet(ctx context.Context, id string) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return errors.New("snippet id is required")
+	}
+	return c.doJSON(ctx, http.MethodDelete, "/v1/snippets/"+url.PathEscape(id), nil, true, nil)
+}
+
+func (c *Client) RenderSnippet(ctx context.Context, id string, values map[string]string) (*RenderSnippetResponse, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, errors.New("snippet id is required")
+	}
+	var resp RenderSnippetResponse
+	path := "/v1/snippets/" + url.PathEscape(id) + "/render"
+	if err := c.doJSON(ctx, http.MethodPost, path, RenderSnippetRequest{Values: values}, true, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListApprovalRules returns every approval rule in evaluation order.
+func (c *Client) ListApprovalRules(ctx context.Context) ([]*types.ApprovalRule, error) {
+	var resp ApprovalRulesResponse
+	if err := c.doJSON(ctx, http.MethodGet, "/v1/approval-rules", nil, true, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Rules, nil
+}
+
+func (c *Client) GetApprovalRule(ctx context.Context, id string) (*types.ApprovalRule, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, errors.New("approval rule id is required")
+	}
+	var resp types.ApprovalRule
+	if err := c.doJSON(ctx, http.MethodGet, "/v1/approval-rules/"+url.PathEscape(id), nil, true, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) CreateApprovalRule(ctx context.Context, rule *types.ApprovalRule) (*types.ApprovalRule, error) {
+	if rule == nil {
+		return nil, errors.New("approval rule is required")
+	}
+	var resp types.ApprovalRule
+	if err := c.doJSON(ctx, http.MethodPost, "/v1/approval-rules", rule, true, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpdateApprovalRule replaces the editable fields of a rule. Every field is
+// sent, so empty matchers are cleared rather than kept.
+func (c *Client) UpdateApprovalRule(ctx context.Context, id string, rule *types.ApprovalRule) (*types.ApprovalRule, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, errors.New("approval rule id is required")
+	}
+	if rule == nil {
+		return nil, errors.New("approval rule is required")
+	}
+	pathGlobs := rule.PathGlobs
+	if pathGlobs == nil {
+		pathGlobs = []string{}
+	}
+	body := map[string]any{
+		"name":           rule.Name,
+		"scope":          rule.Scope,
+		"workspace_id":   rule.WorkspaceID,
+		"provider":       rule.Provider,
+		"session_id":     rule.SessionID,
+		"method":         rule.Method,
+		"command_prefix": rule.CommandPrefix,
+		"command_regex":  rule.CommandRegex,
+		"path_globs":     pathGlobs,
+		"access":         rule.Access,
+		"decision":       rule.Decision,
+		"disabled":       rule.Disabled,
+	}
+	var resp types.ApprovalRule
+	if err := c.doJSON(ctx, http.MethodPatch, "/v1/approval-rules/"+url.PathEscape(id), body, true, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) DeleteApprovalRule(ctx context.Context, id string) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return errors.New("approval rule id is required")
+	}
+	return c.doJSON(ctx, http.MethodDelete, "/v1/approval-rules/"+url.PathEscape(id), nil, true, nil)
+}
+
 func (c *Client) CreateWorkspaceGroup(ctx context.Context, group *types.WorkspaceGroup) (*types.WorkspaceGroup, error) {
 	if group == nil {
 		return nil, errors.New("workspace group is required")
@@ -396,6 +547,39 @@ func (c *Client) DeleteWorktree(ctx context.Context, workspaceID, worktreeID str
 	return c.doJSON(ctx, http.MethodDelete, path, nil, true, nil)
 }
 
+// WorktreeOperation runs a lifecycle operation (remove, merge, rebase or
+// push) on a worktree. Conflicts and refusals are reported in the result,
+// not as errors.
+func (c *Client) WorktreeOperation(ctx context.Context, workspaceID, worktreeID string, op types.WorktreeOperation, req types.WorktreeOperationRequest) (*types.WorktreeOperationResult, error) {
+	if strings.TrimSpace(workspaceID) == "" {
+		return nil, errors.New("workspace id is required")
+	}
+	if strings.TrimSpace(worktreeID) == "" {
+		return nil, errors.New("worktree id is required")
+	}
+	if strings.TrimSpace(string(op)) == "" {
+		return nil, errors.New("operation is required")
+	}
+	var resp types.WorktreeOperationResult
+	path := fmt.Sprintf("/v1/workspaces/%s/worktrees/%s/%s", workspaceID, worktreeID, op)
+	if err := c.doJSON(ctx, http.MethodPost, path, req, true, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) PruneWorktrees(ctx context.Context, workspaceID string) (*types.WorktreeOperationResult, error) {
+	if strings.TrimSpace(workspaceID) == "" {
+		return nil, errors.New("workspace id is required")
+	}
+	var resp types.WorktreeOperationResult
+	path := fmt.Sprintf("/v1/workspaces/%s/worktrees/prune", workspaceID)
+	if err := c.doJSON(ctx, http.MethodPost, path, nil, true, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 func (c *Client) CreateWorkspace(ctx context.Context, workspace *types.Workspace) (*types.Workspace, error) {
 	if workspace == nil {
 		return nil, errors.New("workspace is required")
@@ -430,6 +614,40 @@ func (c *Client) DeleteWorkspace(ctx context.Context, id string) error {
 	return c.doJSON(ctx, http.MethodDelete, path, nil, true, nil)
 }
 
+func (c *Client) ScanWorkspaces(ctx context.Context, req types.WorkspaceScanRequest) (*types.WorkspaceScanResult, error) {
+	if strings.TrimSpace(req.Root) == "" {
+		return nil, errors.New("scan root is required")
+	}
+	var resp types.WorkspaceScanResult
+	if err := c.doJSON(ctx, http.MethodPost, "/v1/workspaces/scan", req, true, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) ExportWorkspaces(ctx context.Context) (*types.WorkspaceExport, error) {
+	var resp types.WorkspaceExport
+	if err := c.doJSON(ctx, http.MethodGet, "/v1/workspaces/export", nil, true, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) ImportWorkspaces(ctx context.Context, export *types.WorkspaceExport, allowLocalData bool) (*types.WorkspaceImportResult, error) {
+	if export == nil {
+		return nil, errors.New("workspace export is required")
+	}
+	path := "/v1/workspaces/import"
+	if allowLocalData {
+		path += "?" + url.Values{"allow_local_data": {"true"}}.Encode()
+	}
+	var resp types.WorkspaceImportResult
+	if err := c.doJSON(ctx, http.MethodPost, path, export, true, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 func (c *Client) GetAppState(ctx context.Context) (*types.AppState, error) {
 	var state types.AppState
 	if err := c.doJSON(ctx, http.MethodGet, "/v1/state", nil, true, &state); err != nil {
@@ -671,6 +889,17 @@ func (c *Client) UpdateSession(ctx context.Context, id string, req UpdateSession
 	return c.doJSON(ctx, http.MethodPatch, path, req, true, nil)
 }
 
+// ForkSession starts a new session seeded with the recent messages of
+// session id.
+func (c *Client) ForkSession(ctx context.Context, id string, req ForkSessionRequest) (*types.Session, error) {
+	var session types.Session
+	path := fmt.Sprintf("/v1/sessions/%s/fork", strings.TrimSpace(id))
+	if err := c.doJSON(ctx, http.MethodPost, path, req, true, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
 func (c *Client) KillSession(ctx context.Context, id string) error {
 	return c.doJSON(ctx, http.MethodPost, "/v1/sessions/"+id+"/kill", nil, true, nil)
 }
@@ -714,6 +943,30 @@ func (c *Client) GetTranscriptSnapshot(ctx context.Context, id string, lines int
 	return &resp, nil
 }
 
+// ExportTranscript returns the rendered transcript of session id.
+func (c *Client) ExportTranscript(ctx context.Context, id string, format transcriptexport.Format, options transcriptexport.Options) ([]byte, error) {
+	path := transcriptExportPath(id, format, options)
+	return c.doRaw(ctx, http.MethodGet, path, true)
+}
+
+func (c *Client) ExportTranscriptDocument(ctx context.Context, id string, options transcriptexport.Options) (*transcriptexport.Document, error) {
+	var doc transcriptexport.Document
+	if err := c.doJSON(ctx, http.MethodGet, transcriptExportPath(id, transcriptexport.FormatJSON, options), nil, true, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func transcriptExportPath(id string, format transcriptexport.Format, options transcriptexport.Options) string {
+	q := url.Values{}
+	q.Set("format", string(format))
+	q.Set("reasoning", strconv.FormatBool(options.Reasoning))
+	q.Set("tools", strconv.FormatBool(options.ToolCalls))
+	q.Set("approvals", strconv.FormatBool(options.Approvals))
+	q.Set("timestamps", strconv.FormatBool(options.Timestamps))
+	return fmt.Sprintf("/v1/sessions/%s/transcript/export?%s", strings.TrimSpace(id), q.Encode())
+}
+
 func (c *Client) SendMessage(ctx context.Context, id string, req SendSessionRequest) (*SendSessionResponse, error) {
 	path := fmt.Sprintf("/v1/sessions/%s/send", strings.TrimSpace(id))
 	var resp SendSessionResponse
@@ -732,6 +985,15 @@ func (c *Client) ListApprovals(ctx context.Context, id string) ([]*types.Approva
 	return resp.Approvals, nil
 }
 
+// ListAllApprovals returns the pending approvals of every session.
+func (c *Client) ListAllApprovals(ctx context.Context) ([]*types.PendingApproval, error) {
+	var resp PendingApprovalsResponse
+	if err := c.doJSON(ctx, http.MethodGet, "/v1/approvals", nil, true, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Approvals, nil
+}
+
 func (c *Client) ApproveSession(ctx context.Context, id string, req ApproveSessionRequest) error {
 	path := fmt.Sprintf("/v1/sessions/%s/approval", strings.TrimSpace(id))
 	return c.doJSON(ctx, http.MethodPost, path, req, true, nil)
@@ -754,6 +1016,118 @@ func (c *Client) ShutdownDaemon(ctx context.Context) error {
 	return c.doJSON(ctx, http.MethodPost, "/v1/shutdown", nil, true, nil)
 }
 
+// WorktreeDiff returns the working tree changes of a worktree against base;
+// an empty base compares against HEAD.
+func (c *Client) WorktreeDiff(ctx context.Context, worktreeID, base string) (*types.GitDiff, error) {
+	return c.getDiff(ctx, fmt.Sprintf("/v1/worktrees/%s/diff", worktreeID), base)
+}
+
+// SessionDiff returns the working tree changes of the repository containing a
+// session's cwd against base; an empty base compares against HEAD.
+func (c *Client) SessionDiff(ctx context.Context, sessionID, base string) (*types.GitDiff, error) {
+	return c.getDiff(ctx, fmt.Sprintf("/v1/sessions/%s/diff", sessionID), base)
+}
+
+// SessionUsage returns the token usage and spend the session's provider has
+// reported since the daemon started.
+func (c *Client) SessionUsage(ctx context.Context, sessionID string) (*types.SessionUsage, error) {
+	var usage types.SessionUsage
+	path := fmt.Sprintf("/v1/sessions/%s/usage", sessionID)
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, true, &usage); err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+// SearchSessionSymbols searches the daemon's symbol index of the session's
+// working directory.
+func (c *Client) SearchSessionSymbols(ctx context.Context, sessionID, query string, limit int) ([]types.CodeSymbol, error) {
+	var resp struct {
+		Symbols []types.CodeSymbol `json:"symbols"`
+	}
+	values := url.Values{}
+	values.Set("query", query)
+	if limit > 0 {
+		values.Set("limit", fmt.Sprint(limit))
+	}
+	path := fmt.Sprintf("/v1/sessions/%s/symbols?%s", sessionID, values.Encode())
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, true, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Symbols, nil
+}
+
+func (c *Client) ListSessionCheckpoints(ctx context.Context, sessionID string) ([]*types.SessionCheckpoint, error) {
+	var resp struct {
+		Checkpoints []*types.SessionCheckpoint `json:"checkpoints"`
+	}
+	path := fmt.Sprintf("/v1/sessions/%s/checkpoints", sessionID)
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, true, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Checkpoints, nil
+}
+
+func (c *Client) RestoreSessionCheckpoint(ctx context.Context, sessionID, checkpointID string, req types.SessionCheckpointRestoreRequest) (*types.SessionCheckpointRestoreResult, error) {
+	var resp types.SessionCheckpointRestoreResult
+	path := fmt.Sprintf("/v1/sessions/%s/checkpoints/%s/restore", sessionID, checkpointID)
+	if err := c.doJSON(ctx, http.MethodPost, path, req, true, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) SessionFinalizeDraft(ctx context.Context, sessionID string) (*types.FinalizeDraft, error) {
+	return c.getFinalizeDraft(ctx, fmt.Sprintf("/v1/sessions/%s/finalize", sessionID))
+}
+
+func (c *Client) FinalizeSession(ctx context.Context, sessionID string, req types.FinalizeRequest) (*types.FinalizeResult, error) {
+	return c.postFinalize(ctx, fmt.Sprintf("/v1/sessions/%s/finalize", sessionID), req)
+}
+
+func (c *Client) WorkflowRunFinalizeDraft(ctx context.Context, runID string) (*types.FinalizeDraft, error) {
+	return c.getFinalizeDraft(ctx, fmt.Sprintf("/v1/workflow-runs/%s/finalize", runID))
+}
+
+func (c *Client) FinalizeWorkflowRun(ctx context.Context, runID string, req types.FinalizeRequest) (*types.FinalizeResult, error) {
+	return c.postFinalize(ctx, fmt.Sprintf("/v1/workflow-runs/%s/finalize", runID), req)
+}
+
+func (c *Client) getFinalizeDraft(ctx context.Context, path string) (*types.FinalizeDraft, error) {
+	var resp types.FinalizeDraft
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, true, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) postFinalize(ctx context.Context, path string, req types.FinalizeRequest) (*types.FinalizeResult, error) {
+	var resp types.FinalizeResult
+	if err := c.doJSON(ctx, http.MethodPost, path, req, true, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) getDiff(ctx context.Context, path, base string) (*types.GitDiff, error) {
+	if base = strings.TrimSpace(base); base != "" {
+		path += "?" + url.Values{"base": []string{base}}.Encode()
+	}
+	var resp types.GitDiff
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, true, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) TestNotification(ctx context.Context, req types.NotificationTestRequest) (*types.NotificationTestResult, error) {
+	var resp types.NotificationTestResult
+	if err := c.doJSON(ctx, http.MethodPost, "/v1/notifications/test", req, true, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 func (c *Client) ensureDaemon(ctx context.Context, expectedVersion string, restart bool) error {
 	resp, err := c.Health(ctx)
 	if err == nil && resp.OK {
@@ -865,6 +1239,7 @@ func (c *Client) doJSONWithClient(ctx context.Context, method, path string, body
 		}
 		req.Header.Set("Authorization", "Bearer "+c.token)
 	}
+	tracing.Inject(ctx, req.Header)
 
 	if httpClient == nil {
 		httpClient = http.DefaultClient
@@ -884,6 +1259,29 @@ func (c *Client) doJSONWithClient(ctx context.Context, method, path string, body
 	return json.NewDecoder(resp.Body).Decode(out)
 }
 
+func (c *Client) doRaw(ctx context.Context, method, path string, requireAuth bool) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if requireAuth {
+		if err := c.ensureToken(); err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	tracing.Inject(ctx, req.Header)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, decodeAPIError(resp)
+	}
+	return io.ReadAll(resp.Body)
+}
+
 func (c *Client) ensureToken() error {
 	if strings.TrimSpace(c.token) == "" {
 		if err := c.loadToken(); err != nil {
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"control/internal/apicode"
+	"control/internal/tracing"
 )
 
 func TestClientListSessionsWithMetaOptionsIncludesWorkflowOwned(t *testing.T) {
@@ -207,3 +208,30 @@ func TestClientCloudAuthLogoutReturnsAPIErrorOnNon2xx(t *testing.T) {
 		t.Fatalf("expected non-2xx error")
 	}
 }
+
+func TestClientPropagatesTraceparentFromContext(t *testing.T) {
+	var seenTraceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenTraceparent = r.Header.Get(tracing.TraceparentHeader)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"sessions":[],"session_meta":[]}`))
+	}))
+	defer server.Close()
+
+	c := &Client{
+		baseURL: server.URL,
+		token:   "token",
+		http: &http.Client{
+			Timeout: 2 * time.Second,
+		},
+	}
+
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := tracing.ContextWithTraceparent(context.Background(), traceparent)
+	if _, _, err := c.ListSessionsWithMetaOptions(ctx, false, false); err != nil {
+		t.Fatalf("ListSessionsWithMetaOptions error: %v", err)
+	}
+	if seenTraceparent != traceparent {
+		t.Fatalf("expected traceparent %q, got %q", traceparent, seenTraceparent)
+	}
+}
@@ -38,6 +38,23 @@ type ListNotesRequest struct {
 	SessionID   string
 }
 
+type SnippetsResponse struct {
+	Snippets []*types.Snippet `json:"snippets"`
+}
+
+type RenderSnippetRequest struct {
+	Values map[string]string `json:"values,omitempty"`
+}
+
+type RenderSnippetResponse struct {
+	Text    string   `json:"text"`
+	Missing []string `json:"missing,omitempty"`
+}
+
+type ApprovalRulesResponse struct {
+	Rules []*types.ApprovalRule `json:"rules"`
+}
+
 type AvailableWorktreesResponse struct {
 	Worktrees []*types.GitWorktree `json:"worktrees"`
 }
@@ -69,6 +86,12 @@ type UpdateSessionRequest struct {
 	NotificationOverrides *types.NotificationSettingsPatch `json:"notification_overrides,omitempty"`
 }
 
+type ForkSessionRequest struct {
+	Title  string `json:"title,omitempty"`
+	Text   string `json:"text,omitempty"`
+	Blocks int    `json:"blocks,omitempty"`
+}
+
 type ProviderOptionsResponse struct {
 	Options *types.ProviderOptionCatalog `json:"options"`
 }
@@ -118,6 +141,10 @@ type ApprovalsResponse struct {
 	Approvals []*types.Approval `json:"approvals"`
 }
 
+type PendingApprovalsResponse struct {
+	Approvals []*types.PendingApproval `json:"approvals"`
+}
+
 type HealthResponse struct {
 	OK              bool   `json:"ok"`
 	Version         string `json:"version"`
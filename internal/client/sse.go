@@ -15,6 +15,7 @@ import (
 
 	"control/internal/config"
 	"control/internal/daemon/transcriptdomain"
+	"control/internal/tracing"
 	"control/internal/types"
 
 	"log"
@@ -89,6 +90,7 @@ func (c *Client) TailStream(ctx context.Context, id, stream string) (<-chan type
 		return nil, nil, err
 	}
 	req.Header.Set("Authorization", "Bearer "+c.token)
+	tracing.Inject(ctx, req.Header)
 	req.Header.Set("Accept", "text/event-stream")
 
 	httpClient := &http.Client{}
@@ -177,6 +179,7 @@ func (c *Client) TranscriptStream(ctx context.Context, id string, afterRevision
 		return nil, nil, err
 	}
 	req.Header.Set("Authorization", "Bearer "+c.token)
+	tracing.Inject(ctx, req.Header)
 	req.Header.Set("Accept", "text/event-stream")
 
 	httpClient := &http.Client{}
@@ -262,6 +265,7 @@ func (c *Client) FileSearchEvents(ctx context.Context, id string) (<-chan types.
 		return nil, nil, err
 	}
 	req.Header.Set("Authorization", "Bearer "+c.token)
+	tracing.Inject(ctx, req.Header)
 	req.Header.Set("Accept", "text/event-stream")
 
 	httpClient := &http.Client{}
@@ -324,6 +328,7 @@ func (c *Client) DebugStream(ctx context.Context, id string) (<-chan types.Debug
 		return nil, nil, err
 	}
 	req.Header.Set("Authorization", "Bearer "+c.token)
+	tracing.Inject(ctx, req.Header)
 	req.Header.Set("Accept", "text/event-stream")
 
 	httpClient := &http.Client{}
@@ -391,6 +396,7 @@ func (c *Client) MetadataStream(ctx context.Context, afterRevision string) (<-ch
 		return nil, nil, err
 	}
 	req.Header.Set("Authorization", "Bearer "+c.token)
+	tracing.Inject(ctx, req.Header)
 	req.Header.Set("Accept", "text/event-stream")
 	if strings.TrimSpace(afterRevision) != "" {
 		req.Header.Set("Last-Event-ID", strings.TrimSpace(afterRevision))
@@ -467,3 +473,85 @@ func (c *Client) MetadataStream(ctx context.Context, afterRevision string) (<-ch
 	}()
 	return ch, cancel, nil
 }
+
+// ApprovalStream follows approval.pending and approval.resolved events for
+// every session.
+func (c *Client) ApprovalStream(ctx context.Context) (<-chan transcriptdomain.TranscriptEvent, func(), error) {
+	if err := c.ensureToken(); err != nil {
+		return nil, nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	streamURL := fmt.Sprintf("%s/v1/approvals/stream?follow=1", c.baseURL)
+	if streamDebugEnabled() {
+		streamLogf("stream approvals open url=%s", streamURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, streamURL, nil)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	tracing.Inject(ctx, req.Header)
+	req.Header.Set("Accept", "text/event-stream")
+
+	httpClient := &http.Client{}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer func() { _ = resp.Body.Close() }()
+		cancel()
+		if streamDebugEnabled() {
+			streamLogf("stream approvals error status=%d", resp.StatusCode)
+		}
+		return nil, nil, decodeAPIError(resp)
+	}
+
+	ch := make(chan transcriptdomain.TranscriptEvent, 64)
+	go func() {
+		defer close(ch)
+		defer func() { _ = resp.Body.Close() }()
+
+		start := time.Now()
+		count := 0
+		reason := "eof"
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		var dataLines []string
+
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "" {
+				if len(dataLines) == 0 {
+					continue
+				}
+				payload := strings.Join(dataLines, "\n")
+				dataLines = dataLines[:0]
+				var event transcriptdomain.TranscriptEvent
+				if err := json.Unmarshal([]byte(payload), &event); err == nil {
+					select {
+					case ch <- event:
+					default:
+					}
+					count++
+				}
+				continue
+			}
+			if strings.HasPrefix(line, "data:") {
+				dataLines = append(dataLines, strings.TrimSpace(line[len("data:"):]))
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			reason = "scan_error"
+			if streamDebugEnabled() {
+				streamLogf("stream approvals scan error err=%v", err)
+			}
+		}
+		if streamDebugEnabled() {
+			streamLogf("stream approvals close reason=%s count=%d dur=%s", reason, count, time.Since(start))
+		}
+	}()
+	return ch, cancel, nil
+}
@@ -606,3 +606,36 @@ func TestMetadataStreamStopCancelsStream(t *testing.T) {
 		t.Fatalf("timeout waiting for metadata stream shutdown")
 	}
 }
+
+func TestApprovalStreamParsesApprovalEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/approvals/stream" || r.URL.Query().Get("follow") != "1" {
+			t.Fatalf("unexpected stream request %s", r.URL.String())
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher, _ := w.(http.Flusher)
+		_, _ = w.Write([]byte("id: 3\n"))
+		_, _ = w.Write([]byte(`data: {"kind":"approval.pending","session_id":"s2","provider":"codex","revision":"3","approval":{"request_id":4,"state":"pending"}}` + "\n\n"))
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}))
+	defer server.Close()
+
+	c := &Client{baseURL: server.URL, token: "token"}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	ch, stop, err := c.ApprovalStream(ctx)
+	if err != nil {
+		t.Fatalf("ApprovalStream: %v", err)
+	}
+	defer stop()
+	select {
+	case event := <-ch:
+		if event.Kind != "approval.pending" || event.SessionID != "s2" || event.Approval == nil || event.Approval.RequestID != 4 {
+			t.Fatalf("unexpected approval event: %#v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("timeout waiting for approval stream payload")
+	}
+}
@@ -142,6 +142,24 @@ func NotesPath() (string, error) {
 	return filepath.Join(dataDir, "notes.json"), nil
 }
 
+// SnippetsPath returns the path to the prompt snippets file.
+func SnippetsPath() (string, error) {
+	dataDir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "snippets.json"), nil
+}
+
+// ApprovalRulesPath returns the path to the approval rules file.
+func ApprovalRulesPath() (string, error) {
+	dataDir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "approval_rules.json"), nil
+}
+
 // StoragePath returns the path to the transactional metadata database.
 func StoragePath() (string, error) {
 	dataDir, err := DataDir()
@@ -150,3 +168,23 @@ func StoragePath() (string, error) {
 	}
 	return filepath.Join(dataDir, "storage.db"), nil
 }
+
+// ExportsDir returns the directory where transcripts exported from the UI
+// are written.
+func ExportsDir() (string, error) {
+	dataDir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "exports"), nil
+}
+
+// AttachmentsDir returns the directory where pasted compose attachments are
+// saved.
+func AttachmentsDir() (string, error) {
+	dataDir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "attachments"), nil
+}
@@ -128,4 +128,12 @@ func TestPaths(t *testing.T) {
 	if !strings.HasSuffix(storagePath, filepath.Join(".archon", "storage.db")) {
 		t.Fatalf("unexpected storage path: %s", storagePath)
 	}
+
+	attachmentsDir, err := AttachmentsDir()
+	if err != nil {
+		t.Fatalf("AttachmentsDir: %v", err)
+	}
+	if !strings.HasSuffix(attachmentsDir, filepath.Join(".archon", "attachments")) {
+		t.Fatalf("unexpected attachments dir: %s", attachmentsDir)
+	}
 }
@@ -33,6 +33,9 @@ const (
 	defaultGuidedWorkflowsRolloutCommitApproval    = true
 	defaultGuidedWorkflowsRolloutMaxRetryAttempts  = 2
 	maxGuidedWorkflowsRolloutRetryAttempts         = 5
+	defaultTracingEndpoint                         = "http://127.0.0.1:4318/v1/traces"
+	defaultTracingServiceName                      = "archon-daemon"
+	defaultSandboxCommand                          = "bwrap"
 )
 
 var defaultCodexModels = []string{
@@ -48,6 +51,9 @@ var defaultNotificationTriggers = []string{
 	"session.failed",
 	"session.killed",
 	"session.exited",
+	"approval.pending",
+	"workflow.decision_needed",
+	"provider.rate_limited",
 }
 var defaultNotificationMethods = []string{"auto"}
 
@@ -60,6 +66,8 @@ type CoreConfig struct {
 	Notifications   CoreNotificationsConfig   `toml:"notifications"`
 	GuidedWorkflows CoreGuidedWorkflowsConfig `toml:"guided_workflows"`
 	TitleGeneration CoreTitleGenerationConfig `toml:"title_generation"`
+	Tracing         CoreTracingConfig         `toml:"tracing"`
+	Sandbox         CoreSandboxConfig         `toml:"sandbox"`
 }
 
 type CoreDaemonConfig struct {
@@ -82,12 +90,50 @@ type CoreDebugConfig struct {
 }
 
 type CoreNotificationsConfig struct {
-	Enabled              *bool    `toml:"enabled"`
-	Triggers             []string `toml:"triggers"`
-	Methods              []string `toml:"methods"`
-	ScriptCommands       []string `toml:"script_commands"`
-	ScriptTimeoutSeconds int      `toml:"script_timeout_seconds"`
-	DedupeWindowSeconds  int      `toml:"dedupe_window_seconds"`
+	Enabled                *bool                             `toml:"enabled"`
+	Triggers               []string                          `toml:"triggers"`
+	Methods                []string                          `toml:"methods"`
+	ScriptCommands         []string                          `toml:"script_commands"`
+	ScriptTimeoutSeconds   int                               `toml:"script_timeout_seconds"`
+	DedupeWindowSeconds    int                               `toml:"dedupe_window_seconds"`
+	Webhooks               []CoreNotificationWebhookConfig   `toml:"webhooks"`
+	WebhookRetries         *int                              `toml:"webhook_retries"`
+	WebhookTimeoutSeconds  int                               `toml:"webhook_timeout_seconds"`
+	LongRunningTurnMinutes *int                              `toml:"long_running_turn_minutes"`
+	QuietHoursStart        string                            `toml:"quiet_hours_start"`
+	QuietHoursEnd          string                            `toml:"quiet_hours_end"`
+	RateLimits             []CoreNotificationRateLimitConfig `toml:"rate_limits"`
+	BatchWindowSeconds     int                               `toml:"batch_window_seconds"`
+	DigestTime             string                            `toml:"digest_time"`
+}
+
+type CoreNotificationRateLimitConfig struct {
+	Trigger       string `toml:"trigger"`
+	Max           int    `toml:"max"`
+	WindowSeconds int    `toml:"window_seconds"`
+}
+
+type CoreNotificationWebhookConfig struct {
+	URL       string            `toml:"url"`
+	Format    string            `toml:"format"`
+	Headers   map[string]string `toml:"headers"`
+	Secret    string            `toml:"secret"`
+	SecretEnv string            `toml:"secret_env"`
+}
+
+type CoreTracingConfig struct {
+	Enabled     *bool             `toml:"enabled"`
+	Endpoint    string            `toml:"endpoint"`
+	ServiceName string            `toml:"service_name"`
+	Headers     map[string]string `toml:"headers"`
+}
+
+type CoreSandboxConfig struct {
+	Enabled       *bool    `toml:"enabled"`
+	Command       string   `toml:"command"`
+	AllowNetwork  *bool    `toml:"allow_network"`
+	ReadOnlyPaths []string `toml:"read_only_paths"`
+	WritablePaths []string `toml:"writable_paths"`
 }
 
 type CoreGuidedWorkflowsConfig struct {
@@ -242,6 +288,16 @@ func DefaultCoreConfig() CoreConfig {
 			ScriptTimeoutSeconds: 10,
 			DedupeWindowSeconds:  5,
 		},
+		Tracing: CoreTracingConfig{
+			Enabled:     boolPtr(false),
+			Endpoint:    defaultTracingEndpoint,
+			ServiceName: defaultTracingServiceName,
+		},
+		Sandbox: CoreSandboxConfig{
+			Enabled:      boolPtr(false),
+			Command:      defaultSandboxCommand,
+			AllowNetwork: boolPtr(false),
+		},
 		GuidedWorkflows: CoreGuidedWorkflowsConfig{
 			Enabled:         boolPtr(false),
 			AutoStart:       boolPtr(false),
@@ -405,6 +461,134 @@ func (c CoreConfig) NotificationDedupeWindowSeconds() int {
 	return 5
 }
 
+func (c CoreConfig) TracingEnabled() bool {
+	return boolFromPtrWithDefault(c.Tracing.Enabled, false)
+}
+
+func (c CoreConfig) TracingEndpoint() string {
+	if endpoint := strings.TrimSpace(c.Tracing.Endpoint); endpoint != "" {
+		return endpoint
+	}
+	return defaultTracingEndpoint
+}
+
+func (c CoreConfig) TracingServiceName() string {
+	if name := strings.TrimSpace(c.Tracing.ServiceName); name != "" {
+		return name
+	}
+	return defaultTracingServiceName
+}
+
+func (c CoreConfig) TracingHeaders() map[string]string {
+	headers := map[string]string{}
+	for key, value := range c.Tracing.Headers {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		headers[key] = strings.TrimSpace(value)
+	}
+	return headers
+}
+
+func (c CoreConfig) SandboxEnabled() bool {
+	return boolFromPtrWithDefault(c.Sandbox.Enabled, false)
+}
+
+func (c CoreConfig) SandboxCommand() string {
+	if command := strings.TrimSpace(c.Sandbox.Command); command != "" {
+		return command
+	}
+	return defaultSandboxCommand
+}
+
+// SandboxAllowNetwork keeps the network available to read-only sandboxed
+// sessions, which are offline unless it is turned on.
+func (c CoreConfig) SandboxAllowNetwork() bool {
+	return boolFromPtrWithDefault(c.Sandbox.AllowNetwork, false)
+}
+
+func (c CoreConfig) SandboxReadOnlyPaths() []string {
+	return normalizedList(c.Sandbox.ReadOnlyPaths)
+}
+
+func (c CoreConfig) SandboxWritablePaths() []string {
+	return normalizedList(c.Sandbox.WritablePaths)
+}
+
+func (c CoreConfig) NotificationWebhooks() []CoreNotificationWebhookConfig {
+	out := make([]CoreNotificationWebhookConfig, 0, len(c.Notifications.Webhooks))
+	for _, webhook := range c.Notifications.Webhooks {
+		webhook.URL = strings.TrimSpace(webhook.URL)
+		if webhook.URL == "" {
+			continue
+		}
+		webhook.Format = strings.ToLower(strings.TrimSpace(webhook.Format))
+		webhook.SecretEnv = strings.TrimSpace(webhook.SecretEnv)
+		headers := map[string]string{}
+		for key, value := range webhook.Headers {
+			if key = strings.TrimSpace(key); key != "" {
+				headers[key] = value
+			}
+		}
+		webhook.Headers = headers
+		out = append(out, webhook)
+	}
+	return out
+}
+
+func (c CoreConfig) NotificationWebhookRetries() int {
+	if c.Notifications.WebhookRetries == nil || *c.Notifications.WebhookRetries < 0 {
+		return 3
+	}
+	return *c.Notifications.WebhookRetries
+}
+
+func (c CoreConfig) NotificationWebhookTimeoutSeconds() int {
+	if c.Notifications.WebhookTimeoutSeconds > 0 {
+		return c.Notifications.WebhookTimeoutSeconds
+	}
+	return 10
+}
+
+// NotificationLongRunningTurnMinutes is how long a turn may run before a
+// turn.long_running notification fires; 0 disables the watchdog.
+func (c CoreConfig) NotificationLongRunningTurnMinutes() int {
+	if c.Notifications.LongRunningTurnMinutes == nil || *c.Notifications.LongRunningTurnMinutes < 0 {
+		return 20
+	}
+	return *c.Notifications.LongRunningTurnMinutes
+}
+
+func (c CoreConfig) NotificationQuietHours() (string, string) {
+	return strings.TrimSpace(c.Notifications.QuietHoursStart), strings.TrimSpace(c.Notifications.QuietHoursEnd)
+}
+
+func (c CoreConfig) NotificationRateLimits() []CoreNotificationRateLimitConfig {
+	out := make([]CoreNotificationRateLimitConfig, 0, len(c.Notifications.RateLimits))
+	for _, limit := range c.Notifications.RateLimits {
+		limit.Trigger = strings.TrimSpace(limit.Trigger)
+		if limit.Trigger == "" || limit.Max <= 0 {
+			continue
+		}
+		out = append(out, limit)
+	}
+	return out
+}
+
+func (c CoreConfig) NotificationBatchWindowSeconds() int {
+	if c.Notifications.BatchWindowSeconds > 0 {
+		return c.Notifications.BatchWindowSeconds
+	}
+	return 0
+}
+
+// NotificationDigestTime is the "HH:MM" local time the daily digest is sent;
+// empty disables the digest.
+func (c CoreConfig) NotificationDigestTime() string {
+	return strings.TrimSpace(c.Notifications.DigestTime)
+}
+
 func (c CoreConfig) GuidedWorkflowsEnabled() bool {
 	if c.GuidedWorkflows.Enabled == nil {
 		return false
@@ -64,6 +64,29 @@ methods = ["notify-send", "bell"]
 script_commands = ["~/.archon/scripts/notify.sh"]
 script_timeout_seconds = 20
 dedupe_window_seconds = 8
+webhook_retries = 0
+webhook_timeout_seconds = 4
+long_running_turn_minutes = 45
+quiet_hours_start = "22:00"
+quiet_hours_end = "07:00"
+batch_window_seconds = 30
+digest_time = " 18:30 "
+
+[[notifications.rate_limits]]
+trigger = "turn.completed"
+max = 4
+
+[[notifications.rate_limits]]
+trigger = "session.failed"
+max = 0
+
+[[notifications.webhooks]]
+url = " https://hooks.slack.com/services/T/B/X "
+format = " Slack "
+secret_env = " ARCHON_WEBHOOK_SECRET "
+
+[[notifications.webhooks]]
+url = " "
 
 [guided_workflows]
 enabled = true
@@ -188,6 +211,30 @@ command = "/usr/local/bin/gemini"
 	if got := cfg.NotificationScriptCommands(); len(got) != 1 || got[0] != "~/.archon/scripts/notify.sh" {
 		t.Fatalf("unexpected notification script commands: %#v", got)
 	}
+	if got := cfg.NotificationWebhooks(); len(got) != 1 || got[0].URL != "https://hooks.slack.com/services/T/B/X" || got[0].Format != "slack" || got[0].SecretEnv != "ARCHON_WEBHOOK_SECRET" {
+		t.Fatalf("unexpected notification webhooks: %#v", got)
+	}
+	if got := cfg.NotificationWebhookRetries(); got != 0 {
+		t.Fatalf("expected explicit zero webhook retries, got %d", got)
+	}
+	if got := cfg.NotificationWebhookTimeoutSeconds(); got != 4 {
+		t.Fatalf("unexpected notification webhook timeout: %d", got)
+	}
+	if got := cfg.NotificationLongRunningTurnMinutes(); got != 45 {
+		t.Fatalf("unexpected long-running turn minutes: %d", got)
+	}
+	if start, end := cfg.NotificationQuietHours(); start != "22:00" || end != "07:00" {
+		t.Fatalf("unexpected quiet hours: %q-%q", start, end)
+	}
+	if limits := cfg.NotificationRateLimits(); len(limits) != 1 || limits[0].Trigger != "turn.completed" || limits[0].Max != 4 {
+		t.Fatalf("unexpected notification rate limits: %#v", limits)
+	}
+	if got := cfg.NotificationBatchWindowSeconds(); got != 30 {
+		t.Fatalf("unexpected notification batch window: %d", got)
+	}
+	if got := cfg.NotificationDigestTime(); got != "18:30" {
+		t.Fatalf("unexpected notification digest time: %q", got)
+	}
 	if !cfg.GuidedWorkflowsEnabled() {
 		t.Fatalf("expected guided workflows enabled=true")
 	}
@@ -1176,3 +1223,85 @@ func TestGuidedWorkflowsConfigFallbacks(t *testing.T) {
 		t.Fatalf("unexpected default rollout max retry attempts: %d", got)
 	}
 }
+
+func TestTracingConfigDefaultsAndOverrides(t *testing.T) {
+	cfg := DefaultCoreConfig()
+	if cfg.TracingEnabled() {
+		t.Fatalf("expected tracing to be disabled by default")
+	}
+	if got := cfg.TracingEndpoint(); got != "http://127.0.0.1:4318/v1/traces" {
+		t.Fatalf("unexpected default tracing endpoint: %q", got)
+	}
+	if got := cfg.TracingServiceName(); got != "archon-daemon" {
+		t.Fatalf("unexpected default tracing service name: %q", got)
+	}
+
+	t.Setenv("HOME", filepath.Join(t.TempDir(), "home"))
+	path, err := CoreConfigPath()
+	if err != nil {
+		t.Fatalf("CoreConfigPath: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	content := "[tracing]\nenabled = true\nendpoint = \"https://otel.example.com/v1/traces\"\nservice_name = \" \"\n\n[tracing.headers]\nauthorization = \"Bearer abc\"\n\" \" = \"ignored\"\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err = LoadCoreConfig()
+	if err != nil {
+		t.Fatalf("LoadCoreConfig: %v", err)
+	}
+	if !cfg.TracingEnabled() {
+		t.Fatalf("expected tracing to be enabled")
+	}
+	if got := cfg.TracingEndpoint(); got != "https://otel.example.com/v1/traces" {
+		t.Fatalf("unexpected tracing endpoint: %q", got)
+	}
+	if got := cfg.TracingServiceName(); got != "archon-daemon" {
+		t.Fatalf("expected blank service name to fall back, got %q", got)
+	}
+	headers := cfg.TracingHeaders()
+	if len(headers) != 1 || headers["authorization"] != "Bearer abc" {
+		t.Fatalf("unexpected tracing headers: %#v", headers)
+	}
+}
+
+func TestSandboxConfigDefaultsAndOverrides(t *testing.T) {
+	cfg := DefaultCoreConfig()
+	if cfg.SandboxEnabled() || cfg.SandboxAllowNetwork() {
+		t.Fatalf("expected sandbox and its read-only network to be off by default")
+	}
+	if got := cfg.SandboxCommand(); got != "bwrap" {
+		t.Fatalf("unexpected default sandbox command: %q", got)
+	}
+
+	t.Setenv("HOME", filepath.Join(t.TempDir(), "home"))
+	path, err := CoreConfigPath()
+	if err != nil {
+		t.Fatalf("CoreConfigPath: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	content := "[sandbox]\nenabled = true\ncommand = \" \"\nallow_network = true\nread_only_paths = [\"~/.gitconfig\", \" \"]\nwritable_paths = [\"~/.claude\"]\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err = LoadCoreConfig()
+	if err != nil {
+		t.Fatalf("LoadCoreConfig: %v", err)
+	}
+	if !cfg.SandboxEnabled() || !cfg.SandboxAllowNetwork() {
+		t.Fatalf("expected sandbox to be enabled with network")
+	}
+	if got := cfg.SandboxCommand(); got != "bwrap" {
+		t.Fatalf("expected blank command to fall back, got %q", got)
+	}
+	if got := cfg.SandboxReadOnlyPaths(); len(got) != 1 || got[0] != "~/.gitconfig" {
+		t.Fatalf("unexpected read-only paths: %#v", got)
+	}
+	if got := cfg.SandboxWritablePaths(); len(got) != 1 || got[0] != "~/.claude" {
+		t.Fatalf("unexpected writable paths: %#v", got)
+	}
+}
@@ -49,6 +49,9 @@ const (
 	SessionUpdatePlan              = "plan"
 	SessionUpdateCurrentMode       = "current_mode_update"
 	SessionUpdateAvailableCommands = "available_commands_update"
+	// SessionUpdateUsage reports context window use and session cost. It is
+	// an unstable protocol extension and is only surfaced through Raw.
+	SessionUpdateUsage = "usage_update"
 )
 
 // RPCError is a JSON-RPC 2.0 error object. It implements the error interface
@@ -152,7 +155,19 @@ type PromptParams struct {
 }
 
 type PromptResult struct {
-	StopReason string `json:"stopReason"`
+	StopReason string       `json:"stopReason"`
+	Usage      *PromptUsage `json:"usage,omitempty"`
+}
+
+// PromptUsage is the token usage of a prompt turn. Agents only report it
+// when they implement the unstable usage extension.
+type PromptUsage struct {
+	InputTokens       int64 `json:"inputTokens"`
+	OutputTokens      int64 `json:"outputTokens"`
+	TotalTokens       int64 `json:"totalTokens"`
+	ThoughtTokens     int64 `json:"thoughtTokens,omitempty"`
+	CachedReadTokens  int64 `json:"cachedReadTokens,omitempty"`
+	CachedWriteTokens int64 `json:"cachedWriteTokens,omitempty"`
 }
 
 type CancelParams struct {
@@ -163,11 +178,31 @@ type CancelParams struct {
 // tool-call content. Only Text is guaranteed baseline; callers that need other
 // variants should inspect Type and decode the full payload themselves.
 type ContentBlock struct {
-	Type     string `json:"type"`
-	Text     string `json:"text,omitempty"`
+	Type     string            `json:"type"`
+	Text     string            `json:"text,omitempty"`
+	MimeType string            `json:"mimeType,omitempty"`
+	URI      string            `json:"uri,omitempty"`
+	Name     string            `json:"name,omitempty"`
+	Data     string            `json:"data,omitempty"`
+	Resource *EmbeddedResource `json:"resource,omitempty"`
+}
+
+// ContentBlock types beyond text. Agents advertise image and embedded
+// resource support through PromptCapabilities; resource links are baseline.
+const (
+	ContentBlockText         = "text"
+	ContentBlockImage        = "image"
+	ContentBlockResource     = "resource"
+	ContentBlockResourceLink = "resource_link"
+)
+
+// EmbeddedResource is the content of a resource block: Text for text
+// resources, base64 Blob for binary ones.
+type EmbeddedResource struct {
+	URI      string `json:"uri"`
 	MimeType string `json:"mimeType,omitempty"`
-	URI      string `json:"uri,omitempty"`
-	Data     string `json:"data,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
 }
 
 // SessionUpdateNotification is the decoded params of a session/update
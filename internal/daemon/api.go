@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"control/internal/daemon/transcriptdomain"
 	"control/internal/guidedworkflows"
 	"control/internal/logging"
 	"control/internal/types"
@@ -34,8 +35,19 @@ type API struct {
 	WorkflowSessionInterrupt  WorkflowRunSessionInterruptService
 	WorkflowRunStop           WorkflowRunStopCoordinator
 	TitleGeneration           TitleGenerationQueue
+	CommitMessages            CommitMessageGenerator
+	SessionCommitMessages     SessionCommitMessageGenerator
 	MetadataEvents            MetadataEventStreamService
+	ApprovalEvents            ApprovalEventStreamService
+	ApprovalStorage           ApprovalRecordStorage
 	FileSearches              FileSearchService
+	NotificationQueue         NotificationQueueInspector
+	NotificationTester        NotificationTester
+	Usage                     SessionUsageReader
+	Symbols                   SymbolSearcher
+	DaemonMetrics             *daemonMetrics
+	DaemonTracing             *daemonTracing
+	TurnWatchdog              *turnWatchdog
 	Logger                    logging.Logger
 }
 
@@ -113,6 +125,10 @@ type MetadataEventStreamService interface {
 	Subscribe(afterRevision string) (<-chan types.MetadataEvent, func(), error)
 }
 
+type ApprovalEventStreamService interface {
+	Subscribe() (<-chan transcriptdomain.TranscriptEvent, func())
+}
+
 type FileSearchService interface {
 	Start(ctx context.Context, req types.FileSearchStartRequest) (*types.FileSearchSession, error)
 	Update(ctx context.Context, id string, req types.FileSearchUpdateRequest) (*types.FileSearchSession, error)
@@ -206,6 +222,24 @@ func (a *API) newSessionService() *SessionService {
 	if a != nil && a.TitleGeneration != nil {
 		opts = append(opts, WithTitleGenerationQueue(a.TitleGeneration))
 	}
+	if a != nil && a.CommitMessages != nil {
+		opts = append(opts, WithCommitMessageGenerator(a.CommitMessages))
+	}
+	if a != nil && a.SessionCommitMessages != nil {
+		opts = append(opts, WithSessionCommitMessageGenerator(a.SessionCommitMessages))
+	}
+	if a != nil && a.ApprovalStorage != nil {
+		opts = append(opts, WithApprovalStorage(a.ApprovalStorage))
+	}
+	if a != nil && a.DaemonMetrics != nil {
+		opts = append(opts, WithDaemonMetrics(a.DaemonMetrics))
+	}
+	if a != nil && a.DaemonTracing != nil {
+		opts = append(opts, WithDaemonTracing(a.DaemonTracing))
+	}
+	if a != nil && a.TurnWatchdog != nil {
+		opts = append(opts, WithTurnWatchdog(a.TurnWatchdog))
+	}
 	return NewSessionService(a.Manager, a.Stores, a.Logger, opts...)
 }
 
@@ -0,0 +1,101 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"control/internal/logging"
+)
+
+func (a *API) PendingApprovals(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	approvals, err := NewApprovalInboxService(a.Stores).List(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"approvals": approvals})
+}
+
+func (a *API) PendingApprovalsStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	if !isFollowRequest(r) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "follow=1 is required"})
+		return
+	}
+	if a.ApprovalEvents == nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "approval stream unavailable"})
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "streaming unsupported"})
+		return
+	}
+	reqID := logging.NewRequestID()
+	if a.Logger != nil && a.Logger.Enabled(logging.Debug) {
+		a.Logger.Debug("approval_stream_open", logging.F("req_id", reqID))
+	}
+	ch, cancel := a.ApprovalEvents.Subscribe()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	// Flush the headers so clients can start reading before the first event.
+	flusher.Flush()
+	defer a.DaemonMetrics.TrackSSESubscriber("approvals")()
+
+	ctx := r.Context()
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+	reason := "unknown"
+	count := 0
+	defer func() {
+		if a.Logger != nil && a.Logger.Enabled(logging.Debug) {
+			a.Logger.Debug("approval_stream_close",
+				logging.F("req_id", reqID),
+				logging.F("count", count),
+				logging.F("reason", reason),
+			)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			reason = "ctx_done"
+			return
+		case <-heartbeat.C:
+			if !writeSSEHeartbeat(w, flusher) {
+				reason = "heartbeat_write_error"
+				return
+			}
+		case event, ok := <-ch:
+			if !ok {
+				reason = "channel_closed"
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			if event.Revision != "" {
+				_, _ = w.Write([]byte("id: " + string(event.Revision) + "\n"))
+			}
+			_, _ = w.Write([]byte("data: "))
+			_, _ = w.Write(data)
+			_, _ = w.Write([]byte("\n\n"))
+			flusher.Flush()
+			count++
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"control/internal/types"
+)
+
+func (a *API) ApprovalRules(w http.ResponseWriter, r *http.Request) {
+	service := NewApprovalRuleService(a.Stores)
+	switch r.Method {
+	case http.MethodGet:
+		rules, err := service.List(r.Context())
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"rules": rules})
+		return
+	case http.MethodPost:
+		var req types.ApprovalRule
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid json body"})
+			return
+		}
+		rule, err := service.Create(r.Context(), &req)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, rule)
+		return
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+func (a *API) ApprovalRuleByID(w http.ResponseWriter, r *http.Request) {
+	service := NewApprovalRuleService(a.Stores)
+	id := strings.TrimSpace(strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/approval-rules/"), "/"))
+	if id == "" || strings.Contains(id, "/") {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		rule, err := service.Get(r.Context(), id)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, rule)
+		return
+	case http.MethodPatch:
+		// Fields missing from the body keep their current values.
+		existing, err := service.Get(r.Context(), id)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		merged := *existing
+		if err := json.NewDecoder(r.Body).Decode(&merged); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid json body"})
+			return
+		}
+		rule, err := service.Update(r.Context(), id, &merged)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, rule)
+		return
+	case http.MethodDelete:
+		if err := service.Delete(r.Context(), id); err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"ok": true})
+		return
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
@@ -0,0 +1,47 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"control/internal/types"
+)
+
+// sessionCheckpoints serves GET /v1/sessions/:id/checkpoints and
+// POST /v1/sessions/:id/checkpoints/:checkpoint/restore.
+func (a *API) sessionCheckpoints(w http.ResponseWriter, r *http.Request, id string, rest []string) {
+	service := a.newSessionService()
+	switch {
+	case len(rest) == 0:
+		if r.Method != http.MethodGet {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+		checkpoints, err := service.ListCheckpoints(r.Context(), id)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"checkpoints": checkpoints})
+	case len(rest) == 2 && rest[1] == "restore":
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+		var req types.SessionCheckpointRestoreRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid json body"})
+			return
+		}
+		result, err := service.RestoreCheckpoint(r.Context(), id, rest[0], req)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	}
+}
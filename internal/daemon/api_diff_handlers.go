@@ -0,0 +1,41 @@
+package daemon
+
+import (
+	"net/http"
+	"strings"
+)
+
+// WorktreeByID serves worktree-scoped endpoints that do not need the owning
+// workspace id, currently GET /v1/worktrees/:id/diff.
+func (a *API) WorktreeByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/v1/worktrees/")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] != "diff" {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	service := NewWorkspaceService(a.Stores)
+	diff, err := service.WorktreeDiff(r.Context(), parts[0], r.URL.Query().Get("base"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, diff)
+}
+
+func (a *API) sessionDiff(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	diff, err := a.newSessionService().Diff(r.Context(), id, r.URL.Query().Get("base"))
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, diff)
+}
@@ -109,6 +109,7 @@ func (a *API) streamFileSearchEvents(w http.ResponseWriter, r *http.Request, id
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("X-Accel-Buffering", "no")
+	defer a.DaemonMetrics.TrackSSESubscriber("file_search")()
 
 	ctx := r.Context()
 	heartbeat := time.NewTicker(sseHeartbeatInterval)
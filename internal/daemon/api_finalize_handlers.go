@@ -0,0 +1,86 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"control/internal/types"
+)
+
+// sessionFinalize serves GET /v1/sessions/:id/finalize (draft) and
+// POST /v1/sessions/:id/finalize (commit).
+func (a *API) sessionFinalize(w http.ResponseWriter, r *http.Request, id string) {
+	service := a.newSessionService()
+	switch r.Method {
+	case http.MethodGet:
+		draft, err := service.FinalizeDraft(r.Context(), id)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, draft)
+	case http.MethodPost:
+		req, ok := decodeFinalizeRequest(w, r)
+		if !ok {
+			return
+		}
+		result, err := service.Finalize(r.Context(), id, req)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// workflowRunFinalize serves GET and POST /v1/workflow-runs/:id/finalize for
+// the session of a run.
+func (a *API) workflowRunFinalize(w http.ResponseWriter, r *http.Request, id string, runs GuidedWorkflowRunService) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	run, err := runs.GetRun(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, toGuidedWorkflowServiceError(err))
+		return
+	}
+	timeline, err := runs.GetRunTimeline(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, toGuidedWorkflowServiceError(err))
+		return
+	}
+	service := a.newSessionService()
+	if r.Method == http.MethodGet {
+		draft, err := service.FinalizeRunDraft(r.Context(), run, timeline)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, draft)
+		return
+	}
+	req, ok := decodeFinalizeRequest(w, r)
+	if !ok {
+		return
+	}
+	result, err := service.FinalizeRun(r.Context(), run, timeline, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func decodeFinalizeRequest(w http.ResponseWriter, r *http.Request) (types.FinalizeRequest, bool) {
+	var req types.FinalizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid json body"})
+		return req, false
+	}
+	return req, true
+}
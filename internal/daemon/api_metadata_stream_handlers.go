@@ -58,6 +58,7 @@ func (a *API) streamMetadataEvents(w http.ResponseWriter, r *http.Request, servi
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("X-Accel-Buffering", "no")
+	defer a.DaemonMetrics.TrackSSESubscriber("metadata")()
 
 	ctx := r.Context()
 	heartbeat := time.NewTicker(sseHeartbeatInterval)
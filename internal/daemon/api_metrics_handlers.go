@@ -0,0 +1,137 @@
+package daemon
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"control/internal/logging"
+	"control/internal/metrics"
+)
+
+const metricsCollectTimeout = 2 * time.Second
+
+type NotificationQueueInspector interface {
+	QueueDepth() int
+}
+
+func (a *API) MetricsEndpoint(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), metricsCollectTimeout)
+	defer cancel()
+	families := append(a.collectMetricFamilies(ctx), a.metricsRegistry().Gather()...)
+	w.Header().Set("Content-Type", metrics.OpenMetricsContentType)
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead {
+		return
+	}
+	if err := metrics.WriteOpenMetrics(w, families); err != nil && a.Logger != nil {
+		a.Logger.Debug("metrics_write_failed", logging.F("error", err))
+	}
+}
+
+func (a *API) metricsRegistry() *metrics.Registry {
+	if a != nil && a.DaemonMetrics != nil {
+		return a.DaemonMetrics.Registry()
+	}
+	return metrics.NewRegistry()
+}
+
+// collectMetricFamilies reads values owned by live services at scrape time so
+// they never drift from what the JSON endpoints report.
+func (a *API) collectMetricFamilies(ctx context.Context) []metrics.Family {
+	if a == nil {
+		return nil
+	}
+	families := []metrics.Family{a.sessionMetricFamily()}
+	if a.NotificationQueue != nil {
+		families = append(families, metrics.Family{
+			Name:    "archon_notification_queue_depth",
+			Help:    "Notification events waiting to be dispatched.",
+			Kind:    metrics.KindGauge,
+			Samples: []metrics.Sample{{Value: float64(a.NotificationQueue.QueueDepth())}},
+		})
+	}
+	families = append(families, a.workflowRunMetricFamilies(ctx)...)
+	return families
+}
+
+func (a *API) sessionMetricFamily() metrics.Family {
+	family := metrics.Family{
+		Name: "archon_sessions",
+		Help: "Sessions known to the session manager by provider and status.",
+		Kind: metrics.KindGauge,
+	}
+	if a.Manager == nil {
+		return family
+	}
+	type sessionKey struct {
+		provider string
+		status   string
+	}
+	counts := map[sessionKey]int{}
+	for _, session := range a.Manager.ListSessions() {
+		if session == nil {
+			continue
+		}
+		counts[sessionKey{provider: metricsLabel(session.Provider), status: metricsLabel(string(session.Status))}]++
+	}
+	for key, count := range counts {
+		family.Samples = append(family.Samples, metrics.Sample{
+			Labels: []metrics.Label{{Name: "provider", Value: key.provider}, {Name: "status", Value: key.status}},
+			Value:  float64(count),
+		})
+	}
+	metrics.SortSamples(family.Samples)
+	return family
+}
+
+func (a *API) workflowRunMetricFamilies(ctx context.Context) []metrics.Family {
+	service := a.workflowRunMetricsService()
+	if service == nil {
+		return nil
+	}
+	snapshot, err := service.GetRunMetrics(ctx)
+	if err != nil {
+		if a.Logger != nil {
+			a.Logger.Debug("metrics_workflow_run_snapshot_failed", logging.F("error", err))
+		}
+		return nil
+	}
+	if !snapshot.Enabled {
+		return nil
+	}
+	counter := func(name, help string, value int) metrics.Family {
+		return metrics.Family{
+			Name:    name,
+			Help:    help,
+			Kind:    metrics.KindCounter,
+			Samples: []metrics.Sample{{Value: float64(value)}},
+		}
+	}
+	interventions := metrics.Family{
+		Name: "archon_workflow_interventions",
+		Help: "Guided workflow interventions by cause.",
+		Kind: metrics.KindCounter,
+	}
+	for cause, count := range snapshot.InterventionCauses {
+		interventions.Samples = append(interventions.Samples, metrics.Sample{
+			Labels: []metrics.Label{{Name: "cause", Value: metricsLabel(cause)}},
+			Value:  float64(count),
+		})
+	}
+	metrics.SortSamples(interventions.Samples)
+	return []metrics.Family{
+		counter("archon_workflow_runs_started", "Guided workflow runs started.", snapshot.RunsStarted),
+		counter("archon_workflow_runs_completed", "Guided workflow runs completed.", snapshot.RunsCompleted),
+		counter("archon_workflow_runs_failed", "Guided workflow runs failed.", snapshot.RunsFailed),
+		counter("archon_workflow_dispatch_attempts", "Guided workflow step and gate dispatch attempts.", snapshot.DispatchAttempts),
+		counter("archon_workflow_dispatch_retries", "Guided workflow dispatches deferred for retry.", snapshot.DispatchDeferred),
+		counter("archon_workflow_dispatch_failures", "Guided workflow dispatches that failed.", snapshot.DispatchFailures),
+		counter("archon_workflow_turn_events", "Turn events received by the guided workflow processor.", snapshot.TurnEventsReceived),
+		interventions,
+	}
+}
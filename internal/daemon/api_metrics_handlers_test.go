@@ -0,0 +1,156 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"control/internal/guidedworkflows"
+	"control/internal/metrics"
+	"control/internal/store"
+	"control/internal/types"
+)
+
+type stubNotificationQueue struct {
+	depth int
+}
+
+func (s stubNotificationQueue) QueueDepth() int {
+	return s.depth
+}
+
+type stubMetricsWorkflowRunService struct {
+	snapshot guidedworkflows.RunMetricsSnapshot
+}
+
+func (s stubMetricsWorkflowRunService) GetRunMetrics(context.Context) (guidedworkflows.RunMetricsSnapshot, error) {
+	return s.snapshot, nil
+}
+
+func TestMetricsEndpointServesOpenMetricsWithoutToken(t *testing.T) {
+	registry := metrics.NewRegistry()
+	instruments := newDaemonMetrics(registry)
+	instruments.TranscriptHubReconnect("codex")
+	release := instruments.TrackSSESubscriber("transcript")
+	defer release()
+
+	api := &API{
+		DaemonMetrics:     instruments,
+		NotificationQueue: stubNotificationQueue{depth: 3},
+		WorkflowRunMetrics: stubMetricsWorkflowRunService{snapshot: guidedworkflows.RunMetricsSnapshot{
+			Enabled:            true,
+			DispatchAttempts:   7,
+			DispatchDeferred:   2,
+			InterventionCauses: map[string]int{"policy_pause": 1},
+		}},
+	}
+	mux := http.NewServeMux()
+	api.RegisterRoutes(mux)
+	server := httptest.NewServer(TokenAuthMiddleware("token", mux))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("get metrics: %v", err)
+	}
+	defer closeTestCloser(t, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Content-Type"); got != metrics.OpenMetricsContentType {
+		t.Fatalf("unexpected content type %q", got)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	text := string(body)
+	for _, want := range []string{
+		"# TYPE archon_sessions gauge",
+		"archon_notification_queue_depth 3.0",
+		"archon_workflow_dispatch_attempts_total 7.0",
+		"archon_workflow_dispatch_retries_total 2.0",
+		`archon_workflow_interventions_total{cause="policy_pause"} 1.0`,
+		`archon_transcript_hub_reconnects_total{provider="codex"} 1.0`,
+		`archon_sse_subscribers{stream="transcript"} 1.0`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected metrics to contain %q, got:\n%s", want, text)
+		}
+	}
+	if !strings.HasSuffix(text, "# EOF\n") {
+		t.Fatalf("expected EOF terminator, got:\n%s", text)
+	}
+}
+
+func TestMetricsEndpointRejectsPost(t *testing.T) {
+	api := &API{DaemonMetrics: newDaemonMetrics(nil)}
+	rec := httptest.NewRecorder()
+	api.MetricsEndpoint(rec, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", rec.Code)
+	}
+}
+
+func TestDaemonMetricsTurnDurationUsesLatestPendingTurn(t *testing.T) {
+	registry := metrics.NewRegistry()
+	instruments := newDaemonMetrics(registry)
+	started := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	instruments.TurnStarted("s1", "turn-1", "codex", started)
+
+	publisher := newMetricsNotificationPublisher(nil, instruments).(*metricsNotificationPublisher)
+	publisher.now = func() time.Time { return started.Add(90 * time.Second) }
+	publisher.Publish(types.NotificationEvent{Trigger: types.NotificationTriggerTurnCompleted, SessionID: "s1", Status: "completed"})
+
+	family := findMetricFamily(t, registry, "archon_turn_duration_seconds")
+	if len(family.Samples) != 1 || family.Samples[0].Count != 1 || family.Samples[0].Sum != 90 {
+		t.Fatalf("unexpected turn duration samples: %#v", family.Samples)
+	}
+	completed := findMetricFamily(t, registry, "archon_turns_completed")
+	if len(completed.Samples) != 1 || completed.Samples[0].Labels[0].Value != "codex" {
+		t.Fatalf("expected provider to fall back to the started turn, got %#v", completed.Samples)
+	}
+}
+
+func TestDaemonMetricsApprovalWaitIgnoresMissingCreatedAt(t *testing.T) {
+	registry := metrics.NewRegistry()
+	instruments := newDaemonMetrics(registry)
+	now := time.Now().UTC()
+	instruments.ApprovalResolved("codex", "exec", time.Time{}, now)
+	instruments.ApprovalResolved("codex", "exec", now.Add(-time.Minute), now)
+
+	family := findMetricFamily(t, registry, "archon_approval_wait_seconds")
+	if len(family.Samples) != 1 || family.Samples[0].Count != 1 {
+		t.Fatalf("expected a single approval observation, got %#v", family.Samples)
+	}
+}
+
+func TestInstrumentStoresRecordsOperations(t *testing.T) {
+	registry := metrics.NewRegistry()
+	instruments := newDaemonMetrics(registry)
+	stores := instrumentStores(newNotesTestStores(t), instruments)
+
+	if _, err := stores.Notes.List(context.Background(), store.NoteFilter{}); err != nil {
+		t.Fatalf("list notes: %v", err)
+	}
+	family := findMetricFamily(t, registry, "archon_store_operation_duration_seconds")
+	if len(family.Samples) != 1 {
+		t.Fatalf("expected one store latency series, got %#v", family.Samples)
+	}
+	labels := family.Samples[0].Labels
+	if labels[0].Value != "notes" || labels[1].Value != "list" {
+		t.Fatalf("unexpected store labels: %#v", labels)
+	}
+}
+
+func findMetricFamily(t *testing.T, registry *metrics.Registry, name string) metrics.Family {
+	t.Helper()
+	for _, family := range registry.Gather() {
+		if family.Name == name {
+			return family
+		}
+	}
+	t.Fatalf("metric family %q not registered", name)
+	return metrics.Family{}
+}
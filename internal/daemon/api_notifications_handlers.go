@@ -0,0 +1,37 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"control/internal/types"
+)
+
+type NotificationTester interface {
+	SendTestNotification(ctx context.Context, req types.NotificationTestRequest) (types.NotificationTestResult, error)
+}
+
+func (a *API) NotificationTestEndpoint(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	if a.NotificationTester == nil {
+		writeServiceError(w, unavailableError("notifications not available", nil))
+		return
+	}
+	var req types.NotificationTestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid json body"})
+		return
+	}
+	result, err := a.NotificationTester.SendTestNotification(r.Context(), req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
@@ -4,17 +4,29 @@ import "net/http"
 
 func (a *API) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/health", a.Health)
+	mux.HandleFunc("/metrics", a.MetricsEndpoint)
 	mux.HandleFunc("/v1/sessions", a.Sessions)
 	mux.HandleFunc("/v1/sessions/", a.SessionByID)
 	mux.HandleFunc("/v1/file-searches", a.FileSearchesEndpoint)
 	mux.HandleFunc("/v1/file-searches/", a.FileSearchByID)
 	mux.HandleFunc("/v1/providers/", a.ProviderByName)
 	mux.HandleFunc("/v1/workspaces", a.Workspaces)
+	mux.HandleFunc("/v1/workspaces/scan", a.WorkspaceScan)
+	mux.HandleFunc("/v1/workspaces/export", a.WorkspaceExport)
+	mux.HandleFunc("/v1/workspaces/import", a.WorkspaceImport)
 	mux.HandleFunc("/v1/workspaces/", a.WorkspaceByID)
+	mux.HandleFunc("/v1/worktrees/", a.WorktreeByID)
 	mux.HandleFunc("/v1/workspace-groups", a.WorkspaceGroups)
 	mux.HandleFunc("/v1/workspace-groups/", a.WorkspaceGroupByID)
 	mux.HandleFunc("/v1/notes", a.Notes)
 	mux.HandleFunc("/v1/notes/", a.NoteByID)
+	mux.HandleFunc("/v1/snippets", a.Snippets)
+	mux.HandleFunc("/v1/snippets/", a.SnippetByID)
+	mux.HandleFunc("/v1/approvals", a.PendingApprovals)
+	mux.HandleFunc("/v1/approvals/stream", a.PendingApprovalsStream)
+	mux.HandleFunc("/v1/approval-rules", a.ApprovalRules)
+	mux.HandleFunc("/v1/approval-rules/", a.ApprovalRuleByID)
+	mux.HandleFunc("/v1/notifications/test", a.NotificationTestEndpoint)
 	mux.HandleFunc("/v1/state", a.AppState)
 	mux.HandleFunc("/v1/workflow-runs", a.WorkflowRunsEndpoint)
 	mux.HandleFunc("/v1/workflow-templates", a.WorkflowTemplatesEndpoint)
@@ -2,6 +2,7 @@ package daemon
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 	"strings"
 
@@ -131,6 +132,10 @@ func (a *API) SessionByID(w http.ResponseWriter, r *http.Request) {
 			a.transcriptSnapshot(w, r, id)
 			return
 		}
+		if len(parts) == 3 && parts[2] == "export" {
+			a.exportTranscript(w, r, id)
+			return
+		}
 		if len(parts) == 3 && parts[2] == "stream" {
 			if !isFollowRequest(r) {
 				writeJSON(w, http.StatusBadRequest, map[string]string{
@@ -169,6 +174,27 @@ func (a *API) SessionByID(w http.ResponseWriter, r *http.Request) {
 		}
 		writeJSON(w, http.StatusOK, map[string]any{"ok": true})
 		return
+	case "fork":
+		if r.Method != http.MethodPost {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
+				"error": "method not allowed",
+			})
+			return
+		}
+		var req ForkSessionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error": "invalid json body",
+			})
+			return
+		}
+		session, err := service.Fork(r.Context(), id, req)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, session)
+		return
 	case "kill":
 		if r.Method != http.MethodPost {
 			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{
@@ -334,6 +360,21 @@ func (a *API) SessionByID(w http.ResponseWriter, r *http.Request) {
 	case "pins":
 		a.SessionPins(w, r, id)
 		return
+	case "diff":
+		a.sessionDiff(w, r, id)
+		return
+	case "checkpoints":
+		a.sessionCheckpoints(w, r, id, parts[2:])
+		return
+	case "finalize":
+		a.sessionFinalize(w, r, id)
+		return
+	case "usage":
+		a.sessionUsage(w, r, id)
+		return
+	case "symbols":
+		a.sessionSymbols(w, r, id)
+		return
 	default:
 		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
 	}
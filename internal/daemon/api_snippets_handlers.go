@@ -0,0 +1,109 @@
+package daemon
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"control/internal/types"
+)
+
+func (a *API) Snippets(w http.ResponseWriter, r *http.Request) {
+	service := NewSnippetService(a.Stores)
+	switch r.Method {
+	case http.MethodGet:
+		snippets, err := service.List(r.Context(), r.URL.Query().Get("workspace_id"))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"snippets": snippets})
+		return
+	case http.MethodPost:
+		var req types.Snippet
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid json body"})
+			return
+		}
+		snippet, err := service.Create(r.Context(), &req)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, snippet)
+		return
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+func (a *API) SnippetByID(w http.ResponseWriter, r *http.Request) {
+	service := NewSnippetService(a.Stores)
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/snippets/"), "/")
+	id, action, _ := strings.Cut(path, "/")
+	id = strings.TrimSpace(id)
+	if id == "" {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		return
+	}
+	if action == "render" {
+		a.renderSnippet(w, r, service, id)
+		return
+	}
+	if action != "" {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		snippet, err := service.Get(r.Context(), id)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, snippet)
+		return
+	case http.MethodPatch:
+		var req types.Snippet
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid json body"})
+			return
+		}
+		snippet, err := service.Update(r.Context(), id, &req)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, snippet)
+		return
+	case http.MethodDelete:
+		if err := service.Delete(r.Context(), id); err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"ok": true})
+		return
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+func (a *API) renderSnippet(w http.ResponseWriter, r *http.Request, service *SnippetService, id string) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	var req RenderSnippetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid json body"})
+		return
+	}
+	rendered, err := service.Render(r.Context(), id, req.Values)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rendered)
+}
@@ -0,0 +1,97 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"control/internal/store"
+	"control/internal/types"
+)
+
+func TestSnippetsEndpointsResolveScopesAndRender(t *testing.T) {
+	stores := newNotesTestStores(t)
+	stores.Snippets = store.NewFileSnippetStore(filepath.Join(t.TempDir(), "snippets.json"))
+	server := newSnippetsTestServer(stores)
+	defer server.Close()
+	workspaceID := seedWorkspace(t, stores)
+
+	var global types.Snippet
+	doSnippetRequest(t, server, http.MethodPost, "/v1/snippets", types.Snippet{Name: "review", Body: "Review {{file}}."}, http.StatusCreated, &global)
+	if global.Scope != types.SnippetScopeGlobal || len(global.Variables) != 1 || global.Variables[0] != "file" {
+		t.Fatalf("unexpected global snippet: %#v", global)
+	}
+	var local types.Snippet
+	doSnippetRequest(t, server, http.MethodPost, "/v1/snippets", types.Snippet{Name: "review", Body: "Review {{file}} for {{concern}}.", WorkspaceID: workspaceID}, http.StatusCreated, &local)
+	if local.Scope != types.SnippetScopeWorkspace {
+		t.Fatalf("expected workspace scope inferred, got %#v", local)
+	}
+	doSnippetRequest(t, server, http.MethodPost, "/v1/snippets", types.Snippet{Name: "review", Body: "dup"}, http.StatusConflict, nil)
+
+	var listed struct {
+		Snippets []*types.Snippet `json:"snippets"`
+	}
+	doSnippetRequest(t, server, http.MethodGet, "/v1/snippets?workspace_id="+workspaceID, nil, http.StatusOK, &listed)
+	byName := map[string]*types.Snippet{}
+	for _, snippet := range listed.Snippets {
+		if _, dup := byName[snippet.Name]; dup {
+			t.Fatalf("expected one effective snippet per name, got duplicate %q", snippet.Name)
+		}
+		byName[snippet.Name] = snippet
+	}
+	if byName["review"] == nil || byName["review"].ID != local.ID {
+		t.Fatalf("expected workspace snippet to shadow global, got %#v", byName["review"])
+	}
+	workflow := byName["solid_audit"]
+	if workflow == nil || workflow.Source != types.SnippetSourceWorkflow {
+		t.Fatalf("expected workflow prompt snippet, got %#v", workflow)
+	}
+
+	var rendered RenderSnippetResponse
+	doSnippetRequest(t, server, http.MethodPost, "/v1/snippets/"+local.ID+"/render", RenderSnippetRequest{Values: map[string]string{"file": "api.go"}}, http.StatusOK, &rendered)
+	if rendered.Text != "Review api.go for {{concern}}." || len(rendered.Missing) != 1 || rendered.Missing[0] != "concern" {
+		t.Fatalf("unexpected render: %#v", rendered)
+	}
+
+	doSnippetRequest(t, server, http.MethodPatch, "/v1/snippets/"+workflow.ID, types.Snippet{Body: "changed"}, http.StatusBadRequest, nil)
+	doSnippetRequest(t, server, http.MethodDelete, "/v1/snippets/"+global.ID, nil, http.StatusOK, nil)
+	doSnippetRequest(t, server, http.MethodGet, "/v1/snippets/"+global.ID, nil, http.StatusNotFound, nil)
+}
+
+func newSnippetsTestServer(stores *Stores) *httptest.Server {
+	api := &API{Version: "test", Stores: stores}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/snippets", api.Snippets)
+	mux.HandleFunc("/v1/snippets/", api.SnippetByID)
+	return httptest.NewServer(TokenAuthMiddleware("token", mux))
+}
+
+func doSnippetRequest(t *testing.T, server *httptest.Server, method, path string, body any, wantStatus int, out any) {
+	t.Helper()
+	var reader *bytes.Reader
+	if body != nil {
+		raw, _ := json.Marshal(body)
+		reader = bytes.NewReader(raw)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req, _ := http.NewRequest(method, server.URL+path, reader)
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer closeTestCloser(t, resp.Body)
+	if resp.StatusCode != wantStatus {
+		t.Fatalf("%s %s: expected %d, got %d", method, path, wantStatus, resp.StatusCode)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("decode %s %s: %v", method, path, err)
+		}
+	}
+}
@@ -72,6 +72,7 @@ func (a *API) streamTailWithService(w http.ResponseWriter, r *http.Request, id,
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("X-Accel-Buffering", "no")
+	defer a.DaemonMetrics.TrackSSESubscriber("tail")()
 
 	ctx := r.Context()
 	heartbeat := time.NewTicker(sseHeartbeatInterval)
@@ -143,6 +144,7 @@ func (a *API) streamDebugWithService(w http.ResponseWriter, r *http.Request, id
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("X-Accel-Buffering", "no")
+	defer a.DaemonMetrics.TrackSSESubscriber("debug")()
 
 	if len(snapshot) > 0 {
 		for _, event := range snapshot {
@@ -0,0 +1,41 @@
+package daemon
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"control/internal/types"
+)
+
+type SymbolSearchResponse struct {
+	Symbols []types.CodeSymbol `json:"symbols"`
+}
+
+// sessionSymbols serves GET /v1/sessions/:id/symbols?query=&limit=, searching
+// the symbol index of the session's working directory for @sym mentions.
+func (a *API) sessionSymbols(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	if a.Symbols == nil {
+		writeServiceError(w, unavailableError("symbol search is not available", nil))
+		return
+	}
+	cwd, err := a.newSessionService().SessionCwd(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	limit, _ := strconv.Atoi(strings.TrimSpace(r.URL.Query().Get("limit")))
+	symbols, err := a.Symbols.SearchSymbols(r.Context(), cwd, r.URL.Query().Get("query"), limit)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	if symbols == nil {
+		symbols = []types.CodeSymbol{}
+	}
+	writeJSON(w, http.StatusOK, SymbolSearchResponse{Symbols: symbols})
+}
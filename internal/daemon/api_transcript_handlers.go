@@ -4,11 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"control/internal/daemon/transcriptdomain"
 	"control/internal/logging"
+	"control/internal/transcriptexport"
 )
 
 type transcriptSnapshotService interface {
@@ -92,6 +95,7 @@ func (a *API) streamTranscriptWithService(w http.ResponseWriter, r *http.Request
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("X-Accel-Buffering", "no")
+	defer a.DaemonMetrics.TrackSSESubscriber("transcript")()
 
 	ctx := r.Context()
 	heartbeat := time.NewTicker(sseHeartbeatInterval)
@@ -135,3 +139,45 @@ func (a *API) streamTranscriptWithService(w http.ResponseWriter, r *http.Request
 		}
 	}
 }
+
+func transcriptExportOptionsFromQuery(query url.Values) transcriptexport.Options {
+	options := transcriptexport.DefaultOptions()
+	flag := func(key string, value *bool) {
+		if raw, ok := query[key]; ok && len(raw) > 0 {
+			*value = parseBoolQueryValue(raw[0])
+		}
+	}
+	flag("reasoning", &options.Reasoning)
+	flag("tools", &options.ToolCalls)
+	flag("approvals", &options.Approvals)
+	flag("timestamps", &options.Timestamps)
+	return options
+}
+
+func (a *API) exportTranscript(w http.ResponseWriter, r *http.Request, id string) {
+	query := r.URL.Query()
+	format, err := transcriptexport.ParseFormat(query.Get("format"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	lines := 0
+	if raw := strings.TrimSpace(query.Get("lines")); raw != "" {
+		lines = parseLines(raw)
+	}
+	doc, err := a.newSessionService().ExportTranscript(r.Context(), id, transcriptExportOptionsFromQuery(query), lines)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	body, err := transcriptexport.Render(doc, format, transcriptexport.DefaultTheme())
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", `attachment; filename="`+doc.SessionID+format.Extension()+`"`)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,25 @@
+package daemon
+
+import (
+	"net/http"
+
+	"control/internal/types"
+)
+
+// sessionUsage serves the usage snapshot of a session. Sessions whose
+// provider has not reported usage yet get an empty snapshot.
+func (a *API) sessionUsage(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	if a.Usage == nil {
+		writeServiceError(w, unavailableError("session usage is not available", nil))
+		return
+	}
+	usage, ok := a.Usage.SessionUsage(id)
+	if !ok || usage == nil {
+		usage = &types.SessionUsage{SessionID: id}
+	}
+	writeJSON(w, http.StatusOK, usage)
+}
@@ -45,6 +45,11 @@ func (a *API) WorkflowRunsEndpoint(w http.ResponseWriter, r *http.Request) {
 			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid json body"})
 			return
 		}
+		isolated, err := a.provisionIsolatedWorkflowWorktree(r.Context(), &req)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
 		run, err := service.CreateRun(r.Context(), guidedworkflows.CreateRunRequest{
 			TemplateID:             strings.TrimSpace(req.TemplateID),
 			WorkspaceID:            strings.TrimSpace(req.WorkspaceID),
@@ -58,6 +63,9 @@ func (a *API) WorkflowRunsEndpoint(w http.ResponseWriter, r *http.Request) {
 			PolicyOverrides:        a.workflowPolicyResolver().ResolvePolicyOverrides(req.PolicyOverrides),
 		})
 		if err != nil {
+			if isolated != nil {
+				NewWorkspaceService(a.Stores).ReleaseIsolatedWorktree(context.Background(), req.WorkspaceID, isolated)
+			}
 			writeServiceError(w, toGuidedWorkflowServiceError(err))
 			return
 		}
@@ -167,6 +175,10 @@ func (a *API) WorkflowRunByID(w http.ResponseWriter, r *http.Request) {
 	}
 
 	action := strings.TrimSpace(parts[1])
+	if action == "finalize" {
+		a.workflowRunFinalize(w, r, id, service)
+		return
+	}
 	route, ok := a.workflowRunActionRoutes()[action]
 	if !ok {
 		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
@@ -277,6 +289,7 @@ func (a *API) workflowRunActionRoutes() map[string]workflowRunActionRoute {
 					return nil, err
 				}
 				a.syncWorkflowSessionVisibility(run, true)
+				a.cleanupIsolatedWorkflowWorktree(ctx, run)
 				return run, nil
 			},
 		},
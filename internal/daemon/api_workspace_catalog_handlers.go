@@ -0,0 +1,58 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"control/internal/types"
+)
+
+func (a *API) WorkspaceScan(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	var req types.WorkspaceScanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid json body"})
+		return
+	}
+	result, err := NewWorkspaceCatalogService(a.Stores, a.Syncer).Scan(r.Context(), req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (a *API) WorkspaceExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	export, err := NewWorkspaceCatalogService(a.Stores, a.Syncer).Export(r.Context())
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, export)
+}
+
+func (a *API) WorkspaceImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	var req types.WorkspaceExport
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid json body"})
+		return
+	}
+	allowLocalData := parseBoolQueryValue(r.URL.Query().Get("allow_local_data"))
+	result, err := NewWorkspaceCatalogService(a.Stores, a.Syncer).Import(r.Context(), &req, allowLocalData)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
@@ -1,6 +1,7 @@
 package daemon
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -135,6 +136,18 @@ func (a *API) Worktrees(w http.ResponseWriter, r *http.Request, workspaceID stri
 			}
 			writeJSON(w, http.StatusOK, map[string]any{"worktrees": worktrees})
 			return
+		case "prune":
+			if r.Method != http.MethodPost {
+				writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+				return
+			}
+			result, err := service.PruneWorktrees(r.Context(), workspaceID)
+			if err != nil {
+				writeServiceError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, result)
+			return
 		case "create":
 			if r.Method != http.MethodPost {
 				writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
@@ -185,10 +198,46 @@ func (a *API) Worktrees(w http.ResponseWriter, r *http.Request, workspaceID stri
 		a.startSessionForWorkspace(w, r, workspaceID, parts[2])
 		return
 	}
+	if len(parts) == 4 {
+		a.worktreeOperation(w, r, service, workspaceID, parts[2], types.WorktreeOperation(parts[3]))
+		return
+	}
 
 	writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
 }
 
+func (a *API) worktreeOperation(w http.ResponseWriter, r *http.Request, service *WorkspaceSyncService, workspaceID, worktreeID string, op types.WorktreeOperation) {
+	var run func(context.Context, string, string, types.WorktreeOperationRequest) (*types.WorktreeOperationResult, error)
+	switch op {
+	case types.WorktreeOperationRemove:
+		run = service.RemoveWorktree
+	case types.WorktreeOperationMerge:
+		run = service.MergeWorktree
+	case types.WorktreeOperationRebase:
+		run = service.RebaseWorktree
+	case types.WorktreeOperationPush:
+		run = service.PushWorktree
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	var req types.WorktreeOperationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid json body"})
+		return
+	}
+	result, err := run(r.Context(), workspaceID, worktreeID, req)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
 func (a *API) startSessionForWorkspace(w http.ResponseWriter, r *http.Request, workspaceID, worktreeID string) {
 	if r.Method != http.MethodPost {
 		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
@@ -0,0 +1,241 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"control/internal/daemon/transcriptdomain"
+	"control/internal/store"
+	"control/internal/types"
+)
+
+const approvalEventSubscriberBuffer = 64
+
+// approvalEventHub fans approval.pending and approval.resolved transcript
+// events for every session out to inbox subscribers. Unlike the per-session
+// transcript hubs it does not depend on a provider stream being open. Events
+// for a slow subscriber are dropped; the inbox refetches the list on the next
+// event it does receive.
+type approvalEventHub struct {
+	mu           sync.Mutex
+	nextID       int
+	nextRevision uint64
+	subs         map[int]chan transcriptdomain.TranscriptEvent
+}
+
+func newApprovalEventHub() *approvalEventHub {
+	return &approvalEventHub{subs: make(map[int]chan transcriptdomain.TranscriptEvent)}
+}
+
+func (h *approvalEventHub) Subscribe() (<-chan transcriptdomain.TranscriptEvent, func()) {
+	ch := make(chan transcriptdomain.TranscriptEvent, approvalEventSubscriberBuffer)
+	h.mu.Lock()
+	h.nextID++
+	id := h.nextID
+	h.subs[id] = ch
+	h.mu.Unlock()
+	return ch, func() {
+		h.mu.Lock()
+		sub, ok := h.subs[id]
+		if ok {
+			delete(h.subs, id)
+		}
+		h.mu.Unlock()
+		if ok {
+			close(sub)
+		}
+	}
+}
+
+func (h *approvalEventHub) Publish(event transcriptdomain.TranscriptEvent) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	h.nextRevision++
+	event.Revision = transcriptdomain.RevisionToken(strconv.FormatUint(h.nextRevision, 10))
+	for _, sub := range h.subs {
+		select {
+		case sub <- event:
+		default:
+		}
+	}
+	h.mu.Unlock()
+}
+
+// approvalEventStore publishes an approval event whenever a request is first
+// stored or removed, whichever provider path stored it.
+type approvalEventStore struct {
+	next     ApprovalStore
+	sessions SessionIndexStore
+	events   *approvalEventHub
+}
+
+// withApprovalEvents returns a copy of stores whose approval store publishes
+// to events.
+func withApprovalEvents(stores *Stores, events *approvalEventHub) *Stores {
+	if stores == nil || stores.Approvals == nil || events == nil {
+		return stores
+	}
+	out := *stores
+	out.Approvals = &approvalEventStore{next: stores.Approvals, sessions: stores.Sessions, events: events}
+	return &out
+}
+
+func (s *approvalEventStore) List(ctx context.Context) ([]*types.Approval, error) {
+	return s.next.List(ctx)
+}
+
+func (s *approvalEventStore) ListBySession(ctx context.Context, sessionID string) ([]*types.Approval, error) {
+	return s.next.ListBySession(ctx, sessionID)
+}
+
+func (s *approvalEventStore) Get(ctx context.Context, sessionID string, requestID int) (*types.Approval, bool, error) {
+	return s.next.Get(ctx, sessionID, requestID)
+}
+
+func (s *approvalEventStore) Upsert(ctx context.Context, approval *types.Approval) (*types.Approval, error) {
+	existed := false
+	if approval != nil {
+		_, existed, _ = s.next.Get(ctx, approval.SessionID, approval.RequestID)
+	}
+	stored, err := s.next.Upsert(ctx, approval)
+	if err != nil || existed || stored == nil {
+		return stored, err
+	}
+	s.publish(ctx, transcriptdomain.TranscriptEventApprovalPending, stored)
+	return stored, nil
+}
+
+func (s *approvalEventStore) Delete(ctx context.Context, sessionID string, requestID int) error {
+	approval, ok, _ := s.next.Get(ctx, sessionID, requestID)
+	if err := s.next.Delete(ctx, sessionID, requestID); err != nil {
+		return err
+	}
+	if ok {
+		s.publish(ctx, transcriptdomain.TranscriptEventApprovalResolved, approval)
+	}
+	return nil
+}
+
+func (s *approvalEventStore) DeleteSession(ctx context.Context, sessionID string) error {
+	approvals, _ := s.next.ListBySession(ctx, sessionID)
+	if err := s.next.DeleteSession(ctx, sessionID); err != nil {
+		return err
+	}
+	for _, approval := range approvals {
+		s.publish(ctx, transcriptdomain.TranscriptEventApprovalResolved, approval)
+	}
+	return nil
+}
+
+func (s *approvalEventStore) publish(ctx context.Context, kind transcriptdomain.TranscriptEventKind, approval *types.Approval) {
+	if approval == nil {
+		return
+	}
+	now := time.Now().UTC()
+	event := transcriptdomain.TranscriptEvent{
+		Kind:       kind,
+		SessionID:  approval.SessionID,
+		OccurredAt: &now,
+		Approval: &transcriptdomain.ApprovalState{
+			RequestID: approval.RequestID,
+			State:     "pending",
+			Method:    approval.Method,
+		},
+	}
+	if kind == transcriptdomain.TranscriptEventApprovalResolved {
+		event.Approval.State = "resolved"
+	}
+	if s.sessions != nil {
+		if record, ok, err := s.sessions.GetRecord(ctx, approval.SessionID); err == nil && ok && record != nil && record.Session != nil {
+			event.Provider = record.Session.Provider
+		}
+	}
+	s.events.Publish(event)
+}
+
+// ApprovalInboxService lists pending approvals across every session.
+type ApprovalInboxService struct {
+	stores *Stores
+}
+
+func NewApprovalInboxService(stores *Stores) *ApprovalInboxService {
+	return &ApprovalInboxService{stores: stores}
+}
+
+// List returns the pending approvals of sessions that still exist, oldest
+// first. Unlike the per-session listing it does not resync providers.
+func (s *ApprovalInboxService) List(ctx context.Context) ([]*types.PendingApproval, error) {
+	if s == nil || s.stores == nil || s.stores.Approvals == nil {
+		return []*types.PendingApproval{}, nil
+	}
+	approvals, err := s.stores.Approvals.List(ctx)
+	if err != nil {
+		if errors.Is(err, store.ErrApprovalNotFound) {
+			return []*types.PendingApproval{}, nil
+		}
+		return nil, unavailableError(err.Error(), err)
+	}
+	workspaceNames := map[string]string{}
+	if s.stores.Workspaces != nil {
+		if workspaces, err := s.stores.Workspaces.List(ctx); err == nil {
+			for _, workspace := range workspaces {
+				if workspace != nil {
+					workspaceNames[workspace.ID] = workspace.Name
+				}
+			}
+		}
+	}
+	out := make([]*types.PendingApproval, 0, len(approvals))
+	for _, approval := range approvals {
+		if approval == nil {
+			continue
+		}
+		pending := &types.PendingApproval{Approval: *approval, Preview: approvalInboxPreview(approval)}
+		if s.stores.Sessions != nil {
+			record, ok, err := s.stores.Sessions.GetRecord(ctx, approval.SessionID)
+			if err != nil || !ok || record == nil || record.Session == nil {
+				continue
+			}
+			pending.Provider = record.Session.Provider
+			pending.SessionTitle = record.Session.Title
+		}
+		if s.stores.SessionMeta != nil {
+			if meta, ok, err := s.stores.SessionMeta.Get(ctx, approval.SessionID); err == nil && ok && meta != nil {
+				pending.WorkspaceID = meta.WorkspaceID
+				pending.WorkspaceName = workspaceNames[meta.WorkspaceID]
+				if title := strings.TrimSpace(meta.Title); title != "" {
+					pending.SessionTitle = title
+				}
+			}
+		}
+		out = append(out, pending)
+	}
+	return out, nil
+}
+
+// approvalInboxPreview summarizes what an approval asks for: the command,
+// else the paths, else the approval kind or method.
+func approvalInboxPreview(approval *types.Approval) string {
+	params := map[string]any{}
+	if len(approval.Params) > 0 {
+		_ = json.Unmarshal(approval.Params, &params)
+	}
+	subject := approvalRuleSubjectFromParams(approval.Method, params)
+	switch {
+	case subject.Command != "":
+		return subject.Command
+	case len(subject.Paths) > 0:
+		return strings.Join(subject.Paths, ", ")
+	case subject.Kind != "":
+		return strings.ReplaceAll(subject.Kind, "_", " ")
+	default:
+		return approval.Method
+	}
+}
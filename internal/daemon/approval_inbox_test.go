@@ -0,0 +1,123 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"control/internal/daemon/transcriptdomain"
+	"control/internal/types"
+)
+
+func TestApprovalEventStorePublishesPendingAndResolved(t *testing.T) {
+	stores, _ := newApprovalRulesTestStores(t)
+	hub := newApprovalEventHub()
+	stores = withApprovalEvents(stores, hub)
+	events, cancel := hub.Subscribe()
+	defer cancel()
+	ctx := context.Background()
+
+	approval := &types.Approval{SessionID: "s1", RequestID: 7, Method: "item/commandExecution/requestApproval"}
+	if _, err := stores.Approvals.Upsert(ctx, approval); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	if _, err := stores.Approvals.Upsert(ctx, approval); err != nil {
+		t.Fatalf("second upsert: %v", err)
+	}
+	if err := stores.Approvals.Delete(ctx, "s1", 7); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := stores.Approvals.Delete(ctx, "s1", 7); err == nil {
+		t.Fatalf("expected deleting a missing approval to fail")
+	}
+
+	var got []transcriptdomain.TranscriptEvent
+	for len(events) > 0 {
+		got = append(got, <-events)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected one pending and one resolved event, got %#v", got)
+	}
+	if got[0].Kind != transcriptdomain.TranscriptEventApprovalPending || got[0].Provider != "codex" || got[0].Approval == nil || got[0].Approval.RequestID != 7 {
+		t.Fatalf("unexpected pending event: %#v", got[0])
+	}
+	if got[1].Kind != transcriptdomain.TranscriptEventApprovalResolved || got[1].Revision == got[0].Revision {
+		t.Fatalf("unexpected resolved event: %#v", got[1])
+	}
+}
+
+func TestPendingApprovalsEndpointListsAcrossSessions(t *testing.T) {
+	stores, workspaceID := newApprovalRulesTestStores(t)
+	seedSession(t, stores, "s2", workspaceID)
+	hub := newApprovalEventHub()
+	stores = withApprovalEvents(stores, hub)
+	ctx := context.Background()
+	command, _ := json.Marshal(map[string]any{"command": "go test ./..."})
+	fileChange, _ := json.Marshal(map[string]any{"changes": []any{map[string]any{"path": "README.md"}}})
+	for _, approval := range []*types.Approval{
+		{SessionID: "s1", RequestID: 1, Method: "item/commandExecution/requestApproval", Params: command, CreatedAt: time.Now().Add(-time.Minute)},
+		{SessionID: "s2", RequestID: 2, Method: "item/fileChange/requestApproval", Params: fileChange, CreatedAt: time.Now()},
+		{SessionID: "gone", RequestID: 3, Method: "item/commandExecution/requestApproval"},
+	} {
+		if _, err := stores.Approvals.Upsert(ctx, approval); err != nil {
+			t.Fatalf("upsert: %v", err)
+		}
+	}
+
+	api := &API{Version: "test", Stores: stores, ApprovalEvents: hub}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/approvals", api.PendingApprovals)
+	mux.HandleFunc("/v1/approvals/stream", api.PendingApprovalsStream)
+	server := httptest.NewServer(TokenAuthMiddleware("token", mux))
+	defer server.Close()
+
+	var listed struct {
+		Approvals []*types.PendingApproval `json:"approvals"`
+	}
+	doSnippetRequest(t, server, http.MethodGet, "/v1/approvals", nil, http.StatusOK, &listed)
+	if len(listed.Approvals) != 2 {
+		t.Fatalf("expected approvals of existing sessions only, got %#v", listed.Approvals)
+	}
+	first, second := listed.Approvals[0], listed.Approvals[1]
+	if first.SessionID != "s1" || first.Provider != "codex" || first.WorkspaceID != workspaceID || first.WorkspaceName != "test" || first.Preview != "go test ./..." {
+		t.Fatalf("unexpected first approval: %#v", first)
+	}
+	if second.SessionID != "s2" || second.Preview != "README.md" {
+		t.Fatalf("unexpected second approval: %#v", second)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/v1/approvals/stream?follow=1", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected stream status %d", resp.StatusCode)
+	}
+	if err := stores.Approvals.Delete(ctx, "s2", 2); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		var event transcriptdomain.TranscriptEvent
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+			t.Fatalf("decode event: %v", err)
+		}
+		if event.Kind != transcriptdomain.TranscriptEventApprovalResolved || event.SessionID != "s2" {
+			t.Fatalf("unexpected streamed event: %#v", event)
+		}
+		return
+	}
+	t.Fatalf("stream closed without an event: %v", scanner.Err())
+}
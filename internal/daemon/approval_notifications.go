@@ -0,0 +1,58 @@
+package daemon
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"control/internal/types"
+)
+
+// approvalPendingNotificationEvent describes a provider request that is blocked
+// until an operator responds. Session title and scope are filled in from the
+// stores when available so the notification is actionable on its own.
+func approvalPendingNotificationEvent(ctx context.Context, stores *Stores, sessionID string, requestID int, method string) types.NotificationEvent {
+	sessionID = strings.TrimSpace(sessionID)
+	event := types.NotificationEvent{
+		Trigger:    types.NotificationTriggerApprovalPending,
+		OccurredAt: time.Now().UTC().Format(time.RFC3339Nano),
+		SessionID:  sessionID,
+		Status:     "approval_required",
+		Source:     "approval_request:" + sessionID + ":" + strconv.Itoa(requestID),
+		Payload: map[string]any{
+			"kind":       "approval_required",
+			"request_id": requestID,
+			"method":     strings.TrimSpace(method),
+		},
+	}
+	if stores == nil {
+		return event
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if stores.Sessions != nil {
+		if record, ok, err := stores.Sessions.GetRecord(ctx, sessionID); err == nil && ok && record != nil && record.Session != nil {
+			event.Provider = strings.TrimSpace(record.Session.Provider)
+			event.Title = strings.TrimSpace(record.Session.Title)
+			event.Cwd = strings.TrimSpace(record.Session.Cwd)
+		}
+	}
+	if stores.SessionMeta != nil {
+		if meta, ok, err := stores.SessionMeta.Get(ctx, sessionID); err == nil && ok && meta != nil {
+			event.WorkspaceID = strings.TrimSpace(meta.WorkspaceID)
+			event.WorktreeID = strings.TrimSpace(meta.WorktreeID)
+		}
+	}
+	return event
+}
+
+// approvalResolvedNotificationEvent describes an approval the operator has
+// answered, so summaries stop listing the session as awaiting input.
+func approvalResolvedNotificationEvent(session *types.Session, requestID int, decision string) types.NotificationEvent {
+	event := notificationEventFromSession(session, types.NotificationTriggerApprovalResolved,
+		"approval_request:"+session.ID+":"+strconv.Itoa(requestID))
+	event.Status = strings.TrimSpace(decision)
+	return event
+}
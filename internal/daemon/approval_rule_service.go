@@ -0,0 +1,127 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"control/internal/store"
+	"control/internal/types"
+)
+
+type ApprovalRuleService struct {
+	rules      ApprovalRuleStore
+	workspaces WorkspaceStore
+}
+
+func NewApprovalRuleService(stores *Stores) *ApprovalRuleService {
+	service := &ApprovalRuleService{}
+	if stores != nil {
+		service.rules = stores.ApprovalRules
+		service.workspaces = stores.Workspaces
+	}
+	return service
+}
+
+// List returns every rule in evaluation order.
+func (s *ApprovalRuleService) List(ctx context.Context) ([]*types.ApprovalRule, error) {
+	if s.rules == nil {
+		return nil, unavailableError("approval rule store not available", nil)
+	}
+	rules, err := s.rules.List(ctx)
+	if err != nil {
+		return nil, unavailableError(err.Error(), err)
+	}
+	return rules, nil
+}
+
+func (s *ApprovalRuleService) Get(ctx context.Context, id string) (*types.ApprovalRule, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, invalidError("approval rule id is required", nil)
+	}
+	if s.rules == nil {
+		return nil, unavailableError("approval rule store not available", nil)
+	}
+	rule, ok, err := s.rules.Get(ctx, id)
+	if err != nil {
+		return nil, unavailableError(err.Error(), err)
+	}
+	if !ok || rule == nil {
+		return nil, notFoundError("approval rule not found", store.ErrApprovalRuleNotFound)
+	}
+	return rule, nil
+}
+
+func (s *ApprovalRuleService) Create(ctx context.Context, rule *types.ApprovalRule) (*types.ApprovalRule, error) {
+	if s.rules == nil {
+		return nil, unavailableError("approval rule store not available", nil)
+	}
+	if rule == nil {
+		return nil, invalidError("approval rule payload is required", nil)
+	}
+	candidate := *rule
+	candidate.ID = ""
+	if err := s.validate(ctx, &candidate); err != nil {
+		return nil, err
+	}
+	created, err := s.rules.Upsert(ctx, &candidate)
+	if err != nil {
+		return nil, unavailableError(err.Error(), err)
+	}
+	return created, nil
+}
+
+// Update replaces the editable fields of rule id with those of rule.
+func (s *ApprovalRuleService) Update(ctx context.Context, id string, rule *types.ApprovalRule) (*types.ApprovalRule, error) {
+	if rule == nil {
+		return nil, invalidError("approval rule payload is required", nil)
+	}
+	existing, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	candidate := *rule
+	candidate.ID = existing.ID
+	candidate.CreatedAt = existing.CreatedAt
+	if err := s.validate(ctx, &candidate); err != nil {
+		return nil, err
+	}
+	updated, err := s.rules.Upsert(ctx, &candidate)
+	if err != nil {
+		return nil, unavailableError(err.Error(), err)
+	}
+	return updated, nil
+}
+
+func (s *ApprovalRuleService) Delete(ctx context.Context, id string) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return invalidError("approval rule id is required", nil)
+	}
+	if s.rules == nil {
+		return unavailableError("approval rule store not available", nil)
+	}
+	if err := s.rules.Delete(ctx, id); err != nil {
+		if errors.Is(err, store.ErrApprovalRuleNotFound) {
+			return notFoundError("approval rule not found", err)
+		}
+		return unavailableError(err.Error(), err)
+	}
+	return nil
+}
+
+func (s *ApprovalRuleService) validate(ctx context.Context, rule *types.ApprovalRule) error {
+	if err := types.ValidateApprovalRule(rule); err != nil {
+		return invalidError(err.Error(), err)
+	}
+	if rule.Scope != types.ApprovalRuleScopeWorkspace || s.workspaces == nil {
+		return nil
+	}
+	if _, ok, err := s.workspaces.Get(ctx, rule.WorkspaceID); err != nil {
+		return unavailableError(err.Error(), err)
+	} else if !ok {
+		return notFoundError("workspace not found", store.ErrWorkspaceNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"control/internal/daemon/acp"
+	"control/internal/logging"
+	"control/internal/providers"
+	"control/internal/types"
+)
+
+const approvalRuleApplyTimeout = 30 * time.Second
+
+// approvalRuleResolver decides stored approvals from the user's approval
+// rules instead of asking.
+type approvalRuleResolver interface {
+	// Match returns the rule that decides approval, or nil when a human
+	// should.
+	Match(ctx context.Context, approval *types.Approval) *types.ApprovalRule
+	Apply(ctx context.Context, approval *types.Approval, rule *types.ApprovalRule) error
+}
+
+type approvalRuleApproveFunc func(ctx context.Context, sessionID string, requestID int, decision string, acceptSettings map[string]any) error
+
+type approvalRuleTranscriptWriter interface {
+	AppendItems(sessionID string, items []map[string]any) error
+}
+
+type approvalRuleEngine struct {
+	stores     *Stores
+	approve    approvalRuleApproveFunc
+	transcript approvalRuleTranscriptWriter
+	logger     logging.Logger
+}
+
+func newApprovalRuleEngine(stores *Stores, approve approvalRuleApproveFunc, transcript approvalRuleTranscriptWriter, logger logging.Logger) *approvalRuleEngine {
+	if logger == nil {
+		logger = logging.Nop()
+	}
+	return &approvalRuleEngine{stores: stores, approve: approve, transcript: transcript, logger: logger}
+}
+
+func (e *approvalRuleEngine) Match(ctx context.Context, approval *types.Approval) *types.ApprovalRule {
+	if e == nil || approval == nil || e.stores == nil || e.stores.ApprovalRules == nil {
+		return nil
+	}
+	rules, err := e.stores.ApprovalRules.List(ctx)
+	if err != nil {
+		e.logger.Warn("approval_rules_list_failed", logging.F("error", err))
+		return nil
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	rule := types.MatchApprovalRule(rules, e.subject(ctx, approval))
+	if rule == nil || rule.Decision == types.ApprovalRuleAsk {
+		return nil
+	}
+	return rule
+}
+
+// Apply sends the rule's decision to the provider and logs it in the
+// session transcript as a resolved approval.
+func (e *approvalRuleEngine) Apply(ctx context.Context, approval *types.Approval, rule *types.ApprovalRule) error {
+	if e == nil || e.approve == nil {
+		return unavailableError("approval rules cannot respond to providers", nil)
+	}
+	decision, acceptSettings := approvalRuleProviderDecision(e.sessionProvider(ctx, approval.SessionID), rule.Decision)
+	if err := e.approve(ctx, approval.SessionID, approval.RequestID, decision, acceptSettings); err != nil {
+		e.logger.Warn("approval_rule_apply_failed",
+			logging.F("session_id", approval.SessionID),
+			logging.F("request_id", approval.RequestID),
+			logging.F("rule_id", rule.ID),
+			logging.F("error", err),
+		)
+		return err
+	}
+	e.logger.Info("approval_rule_applied",
+		logging.F("session_id", approval.SessionID),
+		logging.F("request_id", approval.RequestID),
+		logging.F("method", approval.Method),
+		logging.F("rule_id", rule.ID),
+		logging.F("decision", string(rule.Decision)),
+	)
+	if e.transcript != nil {
+		item := approvalRuleTranscriptItem(approval, rule, decision)
+		if err := e.transcript.AppendItems(approval.SessionID, []map[string]any{item}); err != nil {
+			e.logger.Warn("approval_rule_transcript_failed",
+				logging.F("session_id", approval.SessionID),
+				logging.F("error", err),
+			)
+		}
+	}
+	return nil
+}
+
+func (e *approvalRuleEngine) sessionProvider(ctx context.Context, sessionID string) string {
+	if e.stores == nil || e.stores.Sessions == nil {
+		return ""
+	}
+	record, ok, err := e.stores.Sessions.GetRecord(ctx, sessionID)
+	if err != nil || !ok || record == nil || record.Session == nil {
+		return ""
+	}
+	return strings.TrimSpace(record.Session.Provider)
+}
+
+func (e *approvalRuleEngine) subject(ctx context.Context, approval *types.Approval) types.ApprovalRuleSubject {
+	params := map[string]any{}
+	if len(approval.Params) > 0 {
+		_ = json.Unmarshal(approval.Params, &params)
+	}
+	subject := approvalRuleSubjectFromParams(approval.Method, params)
+	subject.SessionID = approval.SessionID
+	subject.Provider = e.sessionProvider(ctx, approval.SessionID)
+	if e.stores.SessionMeta != nil {
+		if meta, ok, err := e.stores.SessionMeta.Get(ctx, approval.SessionID); err == nil && ok && meta != nil {
+			subject.WorkspaceID = strings.TrimSpace(meta.WorkspaceID)
+			if meta.RuntimeOptions != nil {
+				subject.Access, _ = types.NormalizeAccessLevel(meta.RuntimeOptions.Access)
+			}
+		}
+	}
+	return subject
+}
+
+// approvalRuleSubjectFromParams reads the kind, command and paths of an
+// approval from the provider request parameters.
+func approvalRuleSubjectFromParams(method string, params map[string]any) types.ApprovalRuleSubject {
+	subject := types.ApprovalRuleSubject{Method: strings.TrimSpace(method)}
+	metadata, _ := params["metadata"].(map[string]any)
+	sources := []map[string]any{params, metadata}
+	if method == acp.MethodRequestPermission {
+		toolCall, _ := params["toolCall"].(map[string]any)
+		rawInput, _ := toolCall["rawInput"].(map[string]any)
+		sources = []map[string]any{rawInput, toolCall}
+		switch strings.ToLower(strings.TrimSpace(asString(toolCall["kind"]))) {
+		case "execute":
+			subject.Kind = types.ApprovalKindCommand
+			if approvalRuleCommand(rawInput) == "" {
+				subject.Command = strings.TrimSpace(asString(toolCall["title"]))
+			}
+		case "edit", "delete", "move":
+			subject.Kind = types.ApprovalKindFileChange
+		}
+		if locations, ok := toolCall["locations"].([]any); ok {
+			for _, location := range locations {
+				if entry, ok := location.(map[string]any); ok {
+					subject.Paths = appendApprovalRulePath(subject.Paths, asString(entry["path"]))
+				}
+			}
+		}
+	}
+	switch method {
+	case "item/commandExecution/requestApproval":
+		subject.Kind = types.ApprovalKindCommand
+	case "item/fileChange/requestApproval":
+		subject.Kind = types.ApprovalKindFileChange
+	case "tool/requestUserInput":
+		subject.Kind = types.ApprovalKindUserInput
+	case types.ApprovalMethodClaudeExitPlanMode:
+		subject.Kind = types.ApprovalKindPlan
+	}
+	for _, source := range sources {
+		if source == nil {
+			continue
+		}
+		if subject.Command == "" {
+			subject.Command = approvalRuleCommand(source)
+		}
+		for _, key := range []string{"path", "file", "file_path", "filePath"} {
+			subject.Paths = appendApprovalRulePath(subject.Paths, asString(source[key]))
+		}
+		for _, key := range []string{"paths", "files", "changes"} {
+			subject.Paths = appendApprovalRulePaths(subject.Paths, source[key])
+		}
+	}
+	return subject
+}
+
+func approvalRuleCommand(source map[string]any) string {
+	for _, key := range []string{"command", "cmd", "parsedCmd"} {
+		switch value := source[key].(type) {
+		case string:
+			if command := strings.TrimSpace(value); command != "" {
+				return command
+			}
+		case []any:
+			parts := make([]string, 0, len(value))
+			for _, part := range value {
+				if text := strings.TrimSpace(asString(part)); text != "" {
+					parts = append(parts, text)
+				}
+			}
+			if len(parts) > 0 {
+				return strings.Join(parts, " ")
+			}
+		}
+	}
+	return ""
+}
+
+func appendApprovalRulePaths(paths []string, raw any) []string {
+	switch value := raw.(type) {
+	case []any:
+		for _, entry := range value {
+			switch typed := entry.(type) {
+			case string:
+				paths = appendApprovalRulePath(paths, typed)
+			case map[string]any:
+				paths = appendApprovalRulePath(paths, firstNonEmpty(asString(typed["path"]), asString(typed["file"])))
+			}
+		}
+	case map[string]any:
+		// Change sets keyed by path.
+		for path := range value {
+			paths = appendApprovalRulePath(paths, path)
+		}
+	}
+	return paths
+}
+
+func appendApprovalRulePath(paths []string, path string) []string {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return paths
+	}
+	for _, existing := range paths {
+		if existing == path {
+			return paths
+		}
+	}
+	return append(paths, path)
+}
+
+// approvalRuleProviderDecision maps a rule decision to the decision sent
+// through SessionService.Approve. Each runtime spells "allow for the rest of
+// the session" differently: Codex takes acceptForSession, OpenCode servers
+// reply always and ACP agents pick their allow_always option.
+func approvalRuleProviderDecision(provider string, decision types.ApprovalRuleDecision) (string, map[string]any) {
+	switch decision {
+	case types.ApprovalRuleDeny:
+		return "decline", nil
+	case types.ApprovalRuleAllowAlways:
+		def, _ := providers.Lookup(provider)
+		switch def.Runtime {
+		case providers.RuntimeCodex:
+			return "acceptForSession", nil
+		case providers.RuntimeOpenCodeServer:
+			return "always", nil
+		case providers.RuntimeACP:
+			return "allow_always", map[string]any{"always": true}
+		default:
+			return "accept", map[string]any{"always": true}
+		}
+	default:
+		return "accept", nil
+	}
+}
+
+func approvalRuleTranscriptItem(approval *types.Approval, rule *types.ApprovalRule, decision string) map[string]any {
+	status := "approved"
+	if decision == "decline" {
+		status = "declined"
+	}
+	params := map[string]any{}
+	if len(approval.Params) > 0 {
+		_ = json.Unmarshal(approval.Params, &params)
+	}
+	subject := approvalRuleSubjectFromParams(approval.Method, params)
+	summary := firstNonEmpty(strings.ReplaceAll(subject.Kind, "_", " "), approval.Method)
+	lines := []string{"Approval " + status + " by rule " + types.ApprovalRuleLabel(rule) + ": " + summary}
+	if subject.Command != "" {
+		lines = append(lines, "", subject.Command)
+	}
+	for _, path := range subject.Paths {
+		lines = append(lines, "Path: "+path)
+	}
+	return map[string]any{
+		"type":       "approval_resolved",
+		"role":       "approval_resolved",
+		"text":       strings.Join(lines, "\n"),
+		"request_id": approval.RequestID,
+		"method":     approval.Method,
+		"decision":   decision,
+		"rule_id":    rule.ID,
+	}
+}
@@ -0,0 +1,339 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"control/internal/daemon/acp"
+	"control/internal/store"
+	"control/internal/types"
+)
+
+type approvalRuleCall struct {
+	sessionID      string
+	requestID      int
+	decision       string
+	acceptSettings map[string]any
+}
+
+type captureApprovalRuleTranscript struct {
+	mu    sync.Mutex
+	items []map[string]any
+}
+
+func (c *captureApprovalRuleTranscript) AppendItems(_ string, items []map[string]any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = append(c.items, items...)
+	return nil
+}
+
+func (c *captureApprovalRuleTranscript) Items() []map[string]any {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]map[string]any(nil), c.items...)
+}
+
+func newApprovalRulesTestStores(t *testing.T) (*Stores, string) {
+	t.Helper()
+	stores := newNotesTestStores(t)
+	stores.Approvals = store.NewFileApprovalStore(filepath.Join(t.TempDir(), "approvals.json"))
+	stores.ApprovalRules = store.NewFileApprovalRuleStore(filepath.Join(t.TempDir(), "approval_rules.json"))
+	workspaceID := seedWorkspace(t, stores)
+	seedSession(t, stores, "s1", workspaceID)
+	return stores, workspaceID
+}
+
+func TestStoreApprovalStorageAppliesMatchingRule(t *testing.T) {
+	stores, workspaceID := newApprovalRulesTestStores(t)
+	ctx := context.Background()
+	if _, err := NewApprovalRuleService(stores).Create(ctx, &types.ApprovalRule{
+		Name:          "go-tests",
+		WorkspaceID:   workspaceID,
+		Method:        types.ApprovalKindCommand,
+		CommandPrefix: "go test",
+		Decision:      types.ApprovalRuleAllowAlways,
+	}); err != nil {
+		t.Fatalf("create rule: %v", err)
+	}
+	calls := make(chan approvalRuleCall, 2)
+	transcript := &captureApprovalRuleTranscript{}
+	engine := newApprovalRuleEngine(stores, func(_ context.Context, sessionID string, requestID int, decision string, acceptSettings map[string]any) error {
+		calls <- approvalRuleCall{sessionID: sessionID, requestID: requestID, decision: decision, acceptSettings: acceptSettings}
+		return nil
+	}, transcript, nil)
+	publisher := &captureNotificationPublisher{}
+	storage := NewStoreApprovalStorage(stores)
+	storage.SetNotificationPublisher(publisher)
+	storage.SetApprovalRuleResolver(engine)
+
+	params, _ := json.Marshal(map[string]any{"command": "go test ./..."})
+	if err := storage.StoreApproval(ctx, "s1", 3, "item/commandExecution/requestApproval", params); err != nil {
+		t.Fatalf("StoreApproval: %v", err)
+	}
+	select {
+	case call := <-calls:
+		if call.sessionID != "s1" || call.requestID != 3 || call.decision != "acceptForSession" {
+			t.Fatalf("unexpected approve call: %#v", call)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected the rule to approve the request")
+	}
+	items := transcript.Items()
+	if len(items) != 1 || items[0]["type"] != "approval_resolved" || !strings.Contains(items[0]["text"].(string), "Approval approved by rule go-tests: command") {
+		t.Fatalf("expected a resolved approval in the transcript, got %#v", items)
+	}
+
+	chained, _ := json.Marshal(map[string]any{"command": "go test ./... && rm -rf ~"})
+	if err := storage.StoreApproval(ctx, "s1", 4, "item/commandExecution/requestApproval", chained); err != nil {
+		t.Fatalf("StoreApproval: %v", err)
+	}
+	events := publisher.Events()
+	if len(events) != 1 || events[0].Source != "approval_request:s1:4" {
+		t.Fatalf("expected only the unmatched request to notify, got %#v", events)
+	}
+	select {
+	case call := <-calls:
+		t.Fatalf("unexpected approve call for chained command: %#v", call)
+	default:
+	}
+}
+
+func TestApprovalRuleAllowAlwaysMapsToProviderDecision(t *testing.T) {
+	cases := []struct {
+		provider string
+		decision string
+		check    func(t *testing.T, decision string, acceptSettings map[string]any)
+	}{
+		{
+			provider: "codex",
+			decision: "acceptForSession",
+		},
+		{
+			provider: "opencode",
+			decision: "always",
+			check: func(t *testing.T, decision string, _ map[string]any) {
+				if got := normalizeOpenCodePermissionResponse(decision); got != "always" {
+					t.Fatalf("expected opencode reply always, got %q", got)
+				}
+			},
+		},
+		{
+			provider: "hermes",
+			decision: "allow_always",
+			check: func(t *testing.T, decision string, acceptSettings map[string]any) {
+				options := []acp.PermissionOption{
+					{OptionID: "once", Name: "Allow once", Kind: "allow_once"},
+					{OptionID: "always", Name: "Always allow", Kind: "allow_always"},
+					{OptionID: "deny", Name: "Deny", Kind: "reject_once"},
+				}
+				outcome := hermesApprovalOutcome(options, map[string]any{"decision": decision, "acceptSettings": acceptSettings})
+				if outcome.Outcome != "selected" || outcome.OptionID != "always" {
+					t.Fatalf("expected the allow_always option, got %#v", outcome)
+				}
+			},
+		},
+		{
+			provider: "claude",
+			decision: "accept",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.provider, func(t *testing.T) {
+			stores, workspaceID := newApprovalRulesTestStores(t)
+			ctx := context.Background()
+			record, _, err := stores.Sessions.GetRecord(ctx, "s1")
+			if err != nil {
+				t.Fatalf("get session: %v", err)
+			}
+			record.Session.Provider = tc.provider
+			if _, err := stores.Sessions.UpsertRecord(ctx, record); err != nil {
+				t.Fatalf("update session: %v", err)
+			}
+			if _, err := NewApprovalRuleService(stores).Create(ctx, &types.ApprovalRule{
+				Name:        "always",
+				WorkspaceID: workspaceID,
+				Method:      types.ApprovalKindCommand,
+				Decision:    types.ApprovalRuleAllowAlways,
+			}); err != nil {
+				t.Fatalf("create rule: %v", err)
+			}
+			var call approvalRuleCall
+			engine := newApprovalRuleEngine(stores, func(_ context.Context, sessionID string, requestID int, decision string, acceptSettings map[string]any) error {
+				call = approvalRuleCall{sessionID: sessionID, requestID: requestID, decision: decision, acceptSettings: acceptSettings}
+				return nil
+			}, nil, nil)
+			params, _ := json.Marshal(map[string]any{"command": "make"})
+			approval := &types.Approval{SessionID: "s1", RequestID: 1, Method: "item/commandExecution/requestApproval", Params: params}
+			rule := engine.Match(ctx, approval)
+			if rule == nil {
+				t.Fatalf("expected the rule to match")
+			}
+			if err := engine.Apply(ctx, approval, rule); err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			if call.decision != tc.decision {
+				t.Fatalf("expected decision %q, got %#v", tc.decision, call)
+			}
+			if tc.check != nil {
+				tc.check(t, call.decision, call.acceptSettings)
+			}
+		})
+	}
+}
+
+func TestCodexApprovalRequestIsDecidedByRule(t *testing.T) {
+	stores, _ := newApprovalRulesTestStores(t)
+	ctx := context.Background()
+	if _, err := NewApprovalRuleService(stores).Create(ctx, &types.ApprovalRule{
+		Scope:         types.ApprovalRuleScopeProvider,
+		Provider:      "codex",
+		Method:        types.ApprovalKindCommand,
+		CommandPrefix: "go test",
+		Decision:      types.ApprovalRuleAllowOnce,
+	}); err != nil {
+		t.Fatalf("create rule: %v", err)
+	}
+	calls := make(chan approvalRuleCall, 2)
+	storage := NewStoreApprovalStorage(stores)
+	storage.SetApprovalRuleResolver(newApprovalRuleEngine(stores, func(_ context.Context, sessionID string, requestID int, decision string, acceptSettings map[string]any) error {
+		calls <- approvalRuleCall{sessionID: sessionID, requestID: requestID, decision: decision, acceptSettings: acceptSettings}
+		return nil
+	}, nil, nil))
+	manager := NewCodexLiveManager(stores, nil)
+	manager.SetApprovalStorage(storage)
+	ls := &codexLiveSession{
+		sessionID: "s1",
+		client:    &codexAppServer{},
+		hub:       newCodexSubscriberHub(),
+		stores:    stores,
+		approvals: manager.approvals,
+	}
+
+	requestID := 11
+	ls.handleRequest(rpcMessage{
+		ID:     &requestID,
+		Method: "item/commandExecution/requestApproval",
+		Params: json.RawMessage(`{"command":"go test ./..."}`),
+	})
+	select {
+	case call := <-calls:
+		if call.sessionID != "s1" || call.requestID != 11 || call.decision != "accept" {
+			t.Fatalf("unexpected approve call: %#v", call)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected the rule to approve the codex request")
+	}
+
+	// Requests found on resync go through the same storage.
+	resync := NewApprovalResyncService(stores, nil)
+	resync.SetApprovalStorage(storage)
+	if err := resync.reconcileSessionApprovals(ctx, "s1", []*types.Approval{{
+		RequestID: 12,
+		Method:    "item/commandExecution/requestApproval",
+		Params:    json.RawMessage(`{"command":"go test ./internal/..."}`),
+	}}, false); err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	select {
+	case call := <-calls:
+		if call.requestID != 12 {
+			t.Fatalf("unexpected resync approve call: %#v", call)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected the rule to approve the resynced request")
+	}
+}
+
+func TestStoreApprovalStorageAsksWhenRuleCannotRespond(t *testing.T) {
+	stores, _ := newApprovalRulesTestStores(t)
+	ctx := context.Background()
+	if _, err := NewApprovalRuleService(stores).Create(ctx, &types.ApprovalRule{
+		Scope:    types.ApprovalRuleScopeProvider,
+		Provider: "codex",
+		Method:   types.ApprovalKindFileChange,
+		Decision: types.ApprovalRuleDeny,
+	}); err != nil {
+		t.Fatalf("create rule: %v", err)
+	}
+	publisher := &captureNotificationPublisher{}
+	storage := NewStoreApprovalStorage(stores)
+	storage.SetNotificationPublisher(publisher)
+	storage.SetApprovalRuleResolver(newApprovalRuleEngine(stores, func(context.Context, string, int, string, map[string]any) error {
+		return errors.New("session is not live")
+	}, nil, nil))
+
+	if err := storage.StoreApproval(ctx, "s1", 9, "item/fileChange/requestApproval", nil); err != nil {
+		t.Fatalf("StoreApproval: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for len(publisher.Events()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	events := publisher.Events()
+	if len(events) != 1 || events[0].Source != "approval_request:s1:9" {
+		t.Fatalf("expected a pending notification after the rule failed, got %#v", events)
+	}
+}
+
+func TestApprovalRuleSubjectFromPermissionRequest(t *testing.T) {
+	subject := approvalRuleSubjectFromParams("session/request_permission", map[string]any{
+		"toolCall": map[string]any{
+			"kind":      "edit",
+			"rawInput":  map[string]any{"file_path": "docs/guide.md"},
+			"locations": []any{map[string]any{"path": "docs/index.md"}},
+		},
+	})
+	if subject.Kind != types.ApprovalKindFileChange || len(subject.Paths) != 2 || subject.Paths[0] != "docs/index.md" || subject.Paths[1] != "docs/guide.md" {
+		t.Fatalf("unexpected subject: %#v", subject)
+	}
+	subject = approvalRuleSubjectFromParams("session/request_permission", map[string]any{
+		"toolCall": map[string]any{"kind": "execute", "rawInput": map[string]any{"command": []any{"make", "lint"}}},
+	})
+	if subject.Kind != types.ApprovalKindCommand || subject.Command != "make lint" {
+		t.Fatalf("unexpected command subject: %#v", subject)
+	}
+}
+
+func TestApprovalRulesEndpointsCRUD(t *testing.T) {
+	stores, workspaceID := newApprovalRulesTestStores(t)
+	api := &API{Version: "test", Stores: stores}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/approval-rules", api.ApprovalRules)
+	mux.HandleFunc("/v1/approval-rules/", api.ApprovalRuleByID)
+	server := httptest.NewServer(TokenAuthMiddleware("token", mux))
+	defer server.Close()
+
+	doSnippetRequest(t, server, http.MethodPost, "/v1/approval-rules", types.ApprovalRule{Decision: types.ApprovalRuleAllowOnce}, http.StatusBadRequest, nil)
+	doSnippetRequest(t, server, http.MethodPost, "/v1/approval-rules", types.ApprovalRule{WorkspaceID: "missing", Decision: types.ApprovalRuleDeny}, http.StatusNotFound, nil)
+	var created types.ApprovalRule
+	doSnippetRequest(t, server, http.MethodPost, "/v1/approval-rules", types.ApprovalRule{WorkspaceID: workspaceID, CommandRegex: `^npm (test|run lint)$`, Decision: "allow"}, http.StatusCreated, &created)
+	if created.ID == "" || created.Scope != types.ApprovalRuleScopeWorkspace || created.Decision != types.ApprovalRuleAllowOnce {
+		t.Fatalf("unexpected created rule: %#v", created)
+	}
+
+	var updated types.ApprovalRule
+	doSnippetRequest(t, server, http.MethodPatch, "/v1/approval-rules/"+created.ID, map[string]any{"disabled": true}, http.StatusOK, &updated)
+	if !updated.Disabled || updated.CommandRegex != created.CommandRegex || !updated.CreatedAt.Equal(created.CreatedAt) {
+		t.Fatalf("expected patch to keep other fields, got %#v", updated)
+	}
+	doSnippetRequest(t, server, http.MethodPatch, "/v1/approval-rules/"+created.ID, map[string]any{"command_regex": "("}, http.StatusBadRequest, nil)
+
+	var listed struct {
+		Rules []*types.ApprovalRule `json:"rules"`
+	}
+	doSnippetRequest(t, server, http.MethodGet, "/v1/approval-rules", nil, http.StatusOK, &listed)
+	if len(listed.Rules) != 1 || listed.Rules[0].ID != created.ID {
+		t.Fatalf("unexpected list: %#v", listed.Rules)
+	}
+	doSnippetRequest(t, server, http.MethodDelete, "/v1/approval-rules/"+created.ID, nil, http.StatusOK, nil)
+	doSnippetRequest(t, server, http.MethodGet, "/v1/approval-rules/"+created.ID, nil, http.StatusNotFound, nil)
+}
@@ -14,28 +14,87 @@ type ApprovalStorage interface {
 	DeleteApproval(ctx context.Context, sessionID string, requestID int) error
 }
 
+// ApprovalRecordStorage persists approvals that arrive as complete records,
+// such as the pending requests a provider reports on resync.
+type ApprovalRecordStorage interface {
+	StoreApprovalRecord(ctx context.Context, approval *types.Approval) error
+}
+
 type StoreApprovalStorage struct {
-	stores *Stores
+	stores   *Stores
+	notifier NotificationPublisher
+	rules    approvalRuleResolver
 }
 
 func NewStoreApprovalStorage(stores *Stores) *StoreApprovalStorage {
 	return &StoreApprovalStorage{stores: stores}
 }
 
-func (s *StoreApprovalStorage) StoreApproval(ctx context.Context, sessionID string, requestID int, method string, params json.RawMessage) error {
-	if s.stores == nil || s.stores.Approvals == nil {
-		return nil
-	}
+func (s *StoreApprovalStorage) SetNotificationPublisher(notifier NotificationPublisher) {
+	s.notifier = notifier
+}
 
-	approval := &types.Approval{
+func (s *StoreApprovalStorage) SetApprovalRuleResolver(rules approvalRuleResolver) {
+	s.rules = rules
+}
+
+// StoreApproval persists a pending approval. The first time a request is seen
+// it is matched against the approval rules, and unless a rule decides it an
+// approval.pending notification is published; providers re-deliver pending
+// requests on reconnect and those repeats stay quiet.
+func (s *StoreApprovalStorage) StoreApproval(ctx context.Context, sessionID string, requestID int, method string, params json.RawMessage) error {
+	return s.StoreApprovalRecord(ctx, &types.Approval{
 		SessionID: sessionID,
 		RequestID: requestID,
 		Method:    method,
 		Params:    params,
 		CreatedAt: time.Now().UTC(),
+	})
+}
+
+// StoreApprovalRecord is StoreApproval for a complete record; a zero
+// CreatedAt is set to now.
+func (s *StoreApprovalStorage) StoreApprovalRecord(ctx context.Context, approval *types.Approval) error {
+	if s.stores == nil || s.stores.Approvals == nil || approval == nil {
+		return nil
+	}
+	_, existed, _ := s.stores.Approvals.Get(ctx, approval.SessionID, approval.RequestID)
+	if approval.CreatedAt.IsZero() {
+		approval.CreatedAt = time.Now().UTC()
+	}
+	if _, err := s.stores.Approvals.Upsert(ctx, approval); err != nil {
+		return err
+	}
+	if existed {
+		return nil
+	}
+	if s.rules != nil {
+		if rule := s.rules.Match(ctx, approval); rule != nil {
+			// Providers wait for StoreApproval before they listen for the
+			// response, so the decision is sent asynchronously.
+			go s.applyRule(approval, rule)
+			return nil
+		}
+	}
+	s.publishPending(ctx, approval)
+	return nil
+}
+
+// applyRule falls back to asking when the rule's decision cannot be
+// delivered.
+func (s *StoreApprovalStorage) applyRule(approval *types.Approval, rule *types.ApprovalRule) {
+	ctx, cancel := context.WithTimeout(context.Background(), approvalRuleApplyTimeout)
+	defer cancel()
+	if err := s.rules.Apply(ctx, approval, rule); err != nil {
+		s.publishPending(context.Background(), approval)
+	}
+}
+
+func (s *StoreApprovalStorage) publishPending(ctx context.Context, approval *types.Approval) {
+	if s.notifier == nil {
+		return
 	}
-	_, err := s.stores.Approvals.Upsert(ctx, approval)
-	return err
+	s.notifier.Publish(approvalPendingNotificationEvent(ctx, s.stores, approval.SessionID, approval.RequestID, approval.Method))
 }
 
 func (s *StoreApprovalStorage) GetApproval(ctx context.Context, sessionID string, requestID int) (*types.Approval, bool, error) {
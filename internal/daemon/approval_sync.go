@@ -29,6 +29,7 @@ type ApprovalResyncService struct {
 	stores    *Stores
 	logger    logging.Logger
 	providers map[string]ApprovalSyncProvider
+	approvals ApprovalRecordStorage
 }
 
 func NewApprovalResyncService(stores *Stores, logger logging.Logger, extra ...ApprovalSyncProvider) *ApprovalResyncService {
@@ -106,6 +107,15 @@ func (s *ApprovalResyncService) SyncAll(ctx context.Context) error {
 	return firstErr
 }
 
+// SetApprovalStorage routes the pending approvals found on resync through
+// storage, so approval rules and notifications apply to them as well.
+func (s *ApprovalResyncService) SetApprovalStorage(storage ApprovalRecordStorage) {
+	if s == nil {
+		return
+	}
+	s.approvals = storage
+}
+
 func (s *ApprovalResyncService) SyncSession(ctx context.Context, session *types.Session, meta *types.SessionMeta) error {
 	if s == nil || session == nil || strings.TrimSpace(session.ID) == "" {
 		return nil
@@ -151,6 +161,12 @@ func (s *ApprovalResyncService) reconcileSessionApprovals(ctx context.Context, s
 	}
 
 	for _, approval := range pendingByRequestID {
+		if s.approvals != nil {
+			if err := s.approvals.StoreApprovalRecord(ctx, approval); err != nil {
+				return err
+			}
+			continue
+		}
 		if _, err := s.stores.Approvals.Upsert(ctx, approval); err != nil {
 			return err
 		}
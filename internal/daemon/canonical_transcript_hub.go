@@ -90,6 +90,7 @@ type canonicalTranscriptHub struct {
 	nextSubscriber int
 
 	reconnectPolicy TranscriptReconnectPolicy
+	metrics         *daemonMetrics
 
 	lifecycleObserver CanonicalTranscriptHubLifecycleObserver
 	hubInstanceID     string
@@ -378,6 +379,7 @@ func (h *canonicalTranscriptHub) runLoop(
 				_ = h.emitHubStatus(projector, emit, transcriptdomain.StreamStatusClosed)
 				return
 			}
+			h.metrics.TranscriptHubReconnect(h.provider)
 			if !h.emitHubStatus(projector, emit, transcriptdomain.StreamStatusReconnecting) {
 				_ = h.emitHubStatus(projector, emit, transcriptdomain.StreamStatusClosed)
 				return
@@ -26,6 +26,7 @@ type defaultCanonicalTranscriptHubRegistry struct {
 	mapper           TranscriptMapper
 	projectorFactory TranscriptProjectorFactory
 	reconnectPolicy  TranscriptReconnectPolicy
+	metrics          *daemonMetrics
 	idleTTL          time.Duration
 	nextInstanceSeq  uint64
 
@@ -145,6 +146,7 @@ func (r *defaultCanonicalTranscriptHubRegistry) HubForSession(
 	if r.reconnectPolicy != nil {
 		hub.setReconnectPolicy(r.reconnectPolicy)
 	}
+	hub.metrics = r.metrics
 	r.nextInstanceSeq++
 	instanceID := fmt.Sprintf("%s/%d", sessionID, r.nextInstanceSeq)
 	hub.bindLifecycleObserver(r, instanceID)
@@ -0,0 +1,241 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"control/internal/logging"
+	"control/internal/types"
+)
+
+const checkpointRestoreLabelPrefix = "before restore of "
+
+// turnCheckpointSnapshotTimeout bounds the background pre-turn snapshot.
+// Trees that take longer to hash are not checkpointed.
+const turnCheckpointSnapshotTimeout = 5 * time.Second
+
+// snapshotTurnCheckpoint starts capturing the session's working tree before
+// a turn is sent. The snapshot is hashed in the background so the send
+// never waits on it; the agent rarely touches files before the model has
+// answered, which takes longer than hashing a typical tree. Sessions outside
+// a git repository are not checkpointed; failures and snapshots slower than
+// turnCheckpointSnapshotTimeout are logged and deliver nil.
+func (s *SessionService) snapshotTurnCheckpoint(ctx context.Context, session *types.Session) <-chan *gitCheckpointTree {
+	if session == nil || strings.TrimSpace(session.Cwd) == "" {
+		return nil
+	}
+	pending := make(chan *gitCheckpointTree, 1)
+	go func(ctx context.Context, sessionID, cwd string) {
+		ctx, cancel := context.WithTimeout(ctx, turnCheckpointSnapshotTimeout)
+		defer cancel()
+		snapshot, err := snapshotGitTree(ctx, cwd)
+		if err != nil && s.logger != nil && s.logger.Enabled(logging.Debug) {
+			s.logger.Debug("turn_checkpoint_skipped",
+				logging.F("session_id", sessionID),
+				logging.F("error", err),
+			)
+		}
+		pending <- snapshot
+	}(context.WithoutCancel(ctx), session.ID, session.Cwd)
+	return pending
+}
+
+// recordTurnCheckpoint commits the snapshot started by
+// snapshotTurnCheckpoint under the turn that was just started and drops the
+// session's checkpoints beyond the retention limits. It returns at once; the
+// commit and ref write happen once the snapshot is ready.
+func (s *SessionService) recordTurnCheckpoint(ctx context.Context, sessionID, turnID string, pending <-chan *gitCheckpointTree) {
+	if pending == nil {
+		return
+	}
+	if strings.TrimSpace(turnID) == "" {
+		turnID = "unknown"
+	}
+	go func(ctx context.Context) {
+		snapshot := <-pending
+		if snapshot == nil {
+			return
+		}
+		if _, err := commitGitCheckpoint(ctx, snapshot, sessionID, gitCheckpointTurnMessage(turnID)); err != nil {
+			if s.logger != nil {
+				s.logger.Warn("turn_checkpoint_failed",
+					logging.F("session_id", sessionID),
+					logging.F("turn_id", turnID),
+					logging.F("error", err),
+				)
+			}
+			return
+		}
+		s.pruneSessionCheckpoints(ctx, snapshot.root, sessionID)
+	}(context.WithoutCancel(ctx))
+}
+
+func (s *SessionService) pruneSessionCheckpoints(ctx context.Context, root, sessionID string) {
+	if err := pruneGitCheckpoints(ctx, root, sessionID, gitCheckpointRetention, gitCheckpointBackupRetention); err != nil && s.logger != nil {
+		s.logger.Warn("turn_checkpoint_prune_failed",
+			logging.F("session_id", sessionID),
+			logging.F("error", err),
+		)
+	}
+}
+
+// deleteSessionCheckpoints drops every checkpoint of a session that is
+// going away. Sessions without a git working tree have none.
+func (s *SessionService) deleteSessionCheckpoints(ctx context.Context, id string) {
+	session, err := s.Get(ctx, id)
+	if err != nil {
+		return
+	}
+	cwd, err := s.sessionWorkingDir(ctx, session)
+	if err != nil {
+		return
+	}
+	if _, err := os.Stat(cwd); err != nil {
+		return
+	}
+	if err := pruneGitCheckpoints(ctx, cwd, session.ID, 0, 0); err != nil && s.logger != nil && s.logger.Enabled(logging.Debug) {
+		s.logger.Debug("session_checkpoints_delete_skipped",
+			logging.F("session_id", session.ID),
+			logging.F("error", err),
+		)
+	}
+}
+
+// ListCheckpoints returns the working tree checkpoints of a session, oldest
+// first.
+func (s *SessionService) ListCheckpoints(ctx context.Context, id string) ([]*types.SessionCheckpoint, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, invalidError("session id is required", nil)
+	}
+	session, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	cwd, err := s.sessionWorkingDir(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	checkpoints, err := listGitCheckpoints(ctx, cwd, session.ID)
+	if err != nil {
+		return nil, invalidError(err.Error(), err)
+	}
+	return checkpoints, nil
+}
+
+// RestoreCheckpoint resets the session's working tree to a checkpoint. The
+// restore covers the whole repository, so it is refused while other active
+// sessions work in it unless req.Force is set. The tree is checkpointed
+// first so the restore can be undone. With req.Notify the session is told
+// what was reverted; a failed notification is reported in the result rather
+// than failing the restore.
+func (s *SessionService) RestoreCheckpoint(ctx context.Context, id, checkpointID string, req types.SessionCheckpointRestoreRequest) (*types.SessionCheckpointRestoreResult, error) {
+	if strings.TrimSpace(checkpointID) == "" {
+		return nil, invalidError("checkpoint id is required", nil)
+	}
+	checkpoints, err := s.ListCheckpoints(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var checkpoint *types.SessionCheckpoint
+	for _, candidate := range checkpoints {
+		if candidate.ID == checkpointID {
+			checkpoint = candidate
+			break
+		}
+	}
+	if checkpoint == nil {
+		return nil, notFoundError("checkpoint not found", nil)
+	}
+	if !req.Force {
+		if others := s.activeSessionsInRoot(ctx, checkpoint.SessionID, checkpoint.Root); len(others) > 0 {
+			return nil, conflictError(fmt.Sprintf("other active sessions work in %s and would lose their changes: %s; restore with force to proceed",
+				checkpoint.Root, strings.Join(others, ", ")), nil)
+		}
+	}
+	snapshot, err := snapshotGitTree(ctx, checkpoint.Root)
+	if err != nil {
+		return nil, invalidError(err.Error(), err)
+	}
+	backup, err := commitGitCheckpoint(ctx, snapshot, checkpoint.SessionID, checkpointRestoreLabelPrefix+checkpoint.ID)
+	if err != nil {
+		return nil, invalidError(err.Error(), err)
+	}
+	s.pruneSessionCheckpoints(ctx, checkpoint.Root, checkpoint.SessionID)
+	reverted, removed, err := restoreGitCheckpoint(ctx, checkpoint.Root, checkpoint.Commit)
+	if err != nil {
+		return nil, invalidError(err.Error(), err)
+	}
+	result := &types.SessionCheckpointRestoreResult{
+		Checkpoint: checkpoint,
+		BackupID:   backup.ID,
+		Reverted:   reverted,
+		Removed:    removed,
+	}
+	if s.logger != nil {
+		s.logger.Info("checkpoint_restored",
+			logging.F("session_id", checkpoint.SessionID),
+			logging.F("checkpoint_id", checkpoint.ID),
+			logging.F("backup_id", backup.ID),
+			logging.F("reverted", len(reverted)),
+			logging.F("removed", len(removed)),
+		)
+	}
+	if req.Notify && (len(reverted) > 0 || len(removed) > 0) {
+		input := []map[string]any{{"type": "text", "text": checkpointRestoreNotice(checkpoint, reverted, removed)}}
+		if _, err := s.SendMessage(ctx, checkpoint.SessionID, input); err != nil {
+			result.NotifyError = err.Error()
+		} else {
+			result.Notified = true
+		}
+	}
+	return result, nil
+}
+
+// activeSessionsInRoot returns the ids of sessions other than sessionID
+// that can still take turns (including idle, inactive ones) and whose
+// working directory lies inside root.
+func (s *SessionService) activeSessionsInRoot(ctx context.Context, sessionID, root string) []string {
+	sessions, _, err := s.ListWithMetaIncludingWorkflowOwned(ctx)
+	if err != nil {
+		return nil
+	}
+	root = canonicalScanPath(root)
+	var ids []string
+	for _, session := range sessions {
+		if session == nil || session.ID == sessionID {
+			continue
+		}
+		if !isActiveStatus(session.Status) && session.Status != types.SessionStatusInactive {
+			continue
+		}
+		cwd, err := s.sessionWorkingDir(ctx, session)
+		if err != nil {
+			continue
+		}
+		if pathMatchesWorkspace(canonicalScanPath(cwd), root) {
+			ids = append(ids, session.ID)
+		}
+	}
+	return ids
+}
+
+func checkpointRestoreNotice(checkpoint *types.SessionCheckpoint, reverted, removed []string) string {
+	var b strings.Builder
+	if checkpoint.Turn > 0 {
+		fmt.Fprintf(&b, "The user rolled the working tree back to the start of turn %d.", checkpoint.Turn)
+	} else {
+		fmt.Fprintf(&b, "The user rolled the working tree back to checkpoint %s.", checkpoint.ID)
+	}
+	b.WriteString(" Every change made since then has been undone.")
+	if len(reverted) > 0 {
+		fmt.Fprintf(&b, "\nRestored to their earlier content: %s.", strings.Join(reverted, ", "))
+	}
+	if len(removed) > 0 {
+		fmt.Fprintf(&b, "\nDeleted because they did not exist yet: %s.", strings.Join(removed, ", "))
+	}
+	b.WriteString("\nRe-read these files before relying on what you remember about them.")
+	return b.String()
+}
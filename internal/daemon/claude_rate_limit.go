@@ -1,8 +1,12 @@
 package daemon
 
 import (
+	"fmt"
 	"strings"
+	"sync"
 	"time"
+
+	"control/internal/types"
 )
 
 func parseClaudeRateLimitItem(payload map[string]any) (map[string]any, bool) {
@@ -44,3 +48,64 @@ func parseClaudeRateLimitItem(payload map[string]any) (map[string]any, bool) {
 func asStringAny(value any) string {
 	return strings.TrimSpace(asString(value))
 }
+
+// rateLimitNotifier publishes provider.rate_limited when a session records a
+// rate limit item. Providers repeat the same limit on every event until it
+// resets, so only a change of status, limit type or reset time is announced.
+type rateLimitNotifier struct {
+	manager   *SessionManager
+	sessionID string
+	provider  string
+
+	mu      sync.Mutex
+	lastKey string
+}
+
+func newRateLimitNotifier(manager *SessionManager, sessionID, provider string) *rateLimitNotifier {
+	return &rateLimitNotifier{
+		manager:   manager,
+		sessionID: strings.TrimSpace(sessionID),
+		provider:  strings.TrimSpace(provider),
+	}
+}
+
+func (n *rateLimitNotifier) ObserveItem(item map[string]any) {
+	if n == nil || n.manager == nil || asStringAny(item["type"]) != "rateLimit" {
+		return
+	}
+	status := asStringAny(item["status"])
+	limitType := asStringAny(item["limit_type"])
+	retryAt := asStringAny(item["retry_at"])
+	key := status + "|" + limitType + "|" + retryAt
+	n.mu.Lock()
+	if key == n.lastKey {
+		n.mu.Unlock()
+		return
+	}
+	n.lastKey = key
+	n.mu.Unlock()
+
+	publisher := n.manager.notificationPublisher()
+	if publisher == nil {
+		return
+	}
+	event := types.NotificationEvent{
+		Trigger:    types.NotificationTriggerProviderRateLimited,
+		OccurredAt: time.Now().UTC().Format(time.RFC3339Nano),
+		SessionID:  n.sessionID,
+		Provider:   firstNonEmpty(asStringAny(item["provider"]), n.provider),
+		Status:     status,
+		Source:     fmt.Sprintf("rate_limit:%s:%s", n.sessionID, key),
+		Payload: map[string]any{
+			"kind":       "rate_limited",
+			"status":     status,
+			"limit_type": limitType,
+			"retry_at":   retryAt,
+		},
+	}
+	if session, ok := n.manager.GetSession(n.sessionID); ok && session != nil {
+		event.Title = strings.TrimSpace(session.Title)
+		event.Cwd = strings.TrimSpace(session.Cwd)
+	}
+	publisher.Publish(event)
+}
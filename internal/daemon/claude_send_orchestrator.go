@@ -15,12 +15,10 @@ type claudeInputValidator interface {
 
 type defaultClaudeInputValidator struct{}
 
+// TextFromInput accepts input without text when it attaches files.
 func (defaultClaudeInputValidator) TextFromInput(input []map[string]any) (string, error) {
-	text := extractTextInput(input)
-	if strings.TrimSpace(text) == "" {
-		return "", invalidError("text input is required", nil)
-	}
-	return text, nil
+	text, _, err := inputTextOrAttachments(input)
+	return text, err
 }
 
 type claudeSendTransport interface {
@@ -225,7 +223,12 @@ func (o claudeSendOrchestrator) PrepareTurn(
 	if meta != nil {
 		runtimeOptions = types.CloneRuntimeOptions(meta.RuntimeOptions)
 	}
-	payload := buildClaudeUserPayloadWithRuntimeAndTurn(text, runtimeOptions, turnID)
+	attachments := types.InputAttachments(input)
+	attachmentBlocks, err := claudeAttachmentContentBlocks(attachments)
+	if err != nil {
+		return claudePreparedTurn{}, invalidError(err.Error(), err)
+	}
+	payload := buildClaudeUserPayloadWithAttachments(text, attachments, attachmentBlocks, runtimeOptions, turnID)
 	preSendCount := claudeCompletionProbeItemCount(o.completionReader, session.ID)
 
 	return claudePreparedTurn{
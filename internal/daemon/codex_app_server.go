@@ -114,10 +114,21 @@ type codexReasoningEffortDef struct {
 }
 
 func startCodexAppServer(ctx context.Context, cwd, codexHome string, logger logging.Logger) (*codexAppServer, error) {
-	return startCodexAppServerWithOptions(ctx, cwd, codexHome, logger, codexInitializeOptions{})
+	return startCodexAppServerProcess(ctx, cwd, codexHome, nil, nil, logger, codexInitializeOptions{})
+}
+
+// startCodexAppServerForSession starts the app server of a live session with
+// env, e.g. a workspace env profile, layered over the daemon's environment
+// and the process wrapped in the session's sandbox when one is set.
+func startCodexAppServerForSession(ctx context.Context, cwd, codexHome string, env []string, sandbox *sessionSandbox, logger logging.Logger) (*codexAppServer, error) {
+	return startCodexAppServerProcess(ctx, cwd, codexHome, env, sandbox, logger, codexInitializeOptions{})
 }
 
 func startCodexAppServerWithOptions(ctx context.Context, cwd, codexHome string, logger logging.Logger, initOpts codexInitializeOptions) (*codexAppServer, error) {
+	return startCodexAppServerProcess(ctx, cwd, codexHome, nil, nil, logger, initOpts)
+}
+
+func startCodexAppServerProcess(ctx context.Context, cwd, codexHome string, env []string, sandbox *sessionSandbox, logger logging.Logger, initOpts codexInitializeOptions) (*codexAppServer, error) {
 	if logger == nil {
 		logger = logging.Nop()
 	}
@@ -131,8 +142,12 @@ func startCodexAppServerWithOptions(ctx context.Context, cwd, codexHome string,
 		cmd.Dir = cwd
 	}
 	if strings.TrimSpace(codexHome) != "" {
-		cmd.Env = append(os.Environ(), "CODEX_HOME="+codexHome)
+		env = append(append([]string{}, env...), "CODEX_HOME="+codexHome)
+	}
+	if len(env) > 0 {
+		cmd.Env = mergeSessionEnv(os.Environ(), env)
 	}
+	sandbox.wrap(cmd)
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return nil, err
@@ -151,7 +166,7 @@ func startCodexAppServerWithOptions(ctx context.Context, cwd, codexHome string,
 	}
 	go func() { _, _ = io.Copy(io.Discard, stderr) }()
 
-	logger.Info("codex_start", logging.F("cmd", cmdName), logging.F("cwd", cwd), logging.F("codex_home", codexHome))
+	logger.Info("codex_start", logging.F("cmd", cmdName), logging.F("cwd", cwd), logging.F("codex_home", codexHome), logging.F("sandboxed", sandbox != nil))
 
 	client := &codexAppServer{
 		cmd:    cmd,
@@ -378,7 +393,7 @@ func (c *codexAppServer) StartThread(ctx context.Context, model, cwd string, run
 func (c *codexAppServer) StartTurn(ctx context.Context, threadID string, input []map[string]any, runtimeOptions *types.SessionRuntimeOptions, model string) (string, error) {
 	params := map[string]any{
 		"threadId": threadID,
-		"input":    input,
+		"input":    codexTurnInput(input),
 	}
 	if strings.TrimSpace(model) != "" {
 		params["model"] = strings.TrimSpace(model)
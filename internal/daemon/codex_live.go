@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -19,6 +18,10 @@ type CodexLiveManager struct {
 	stores    *Stores
 	logger    logging.Logger
 	notifier  NotificationPublisher
+	usage     SessionUsageRecorder
+	approvals ApprovalStorage
+	env       SessionEnvResolver
+	sandbox   SessionSandboxResolver
 	turnProbe turnActivityProbe
 }
 
@@ -51,6 +54,78 @@ func (m *CodexLiveManager) SetNotificationPublisher(notifier NotificationPublish
 	m.notifier = notifier
 }
 
+// SetApprovalStorage routes approval requests from Codex sessions through
+// storage, which applies the approval rules and notifies.
+func (m *CodexLiveManager) SetApprovalStorage(storage ApprovalStorage) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.approvals = storage
+}
+
+// SetEnvResolver applies the session's workspace env profile to the Codex
+// app-server processes started for live sessions.
+func (m *CodexLiveManager) SetEnvResolver(resolver SessionEnvResolver) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.env = resolver
+}
+
+// sessionEnv resolves the env profile of the session's workspace and
+// worktree.
+func (m *CodexLiveManager) sessionEnv(ctx context.Context, meta *types.SessionMeta) ([]string, error) {
+	m.mu.Lock()
+	resolver := m.env
+	m.mu.Unlock()
+	if resolver == nil || meta == nil {
+		return nil, nil
+	}
+	env, _, err := resolver.ResolveSessionEnv(ctx, meta.WorkspaceID, meta.WorktreeID)
+	return env, err
+}
+
+// SetSandboxResolver wraps the Codex app-server processes started for live
+// sessions in the session's sandbox.
+func (m *CodexLiveManager) SetSandboxResolver(resolver SessionSandboxResolver) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sandbox = resolver
+}
+
+// sessionSandbox builds the sandbox of the session, or nil when the sandbox
+// is disabled.
+func (m *CodexLiveManager) sessionSandbox(ctx context.Context, session *types.Session, meta *types.SessionMeta, codexHome string) (*sessionSandbox, error) {
+	m.mu.Lock()
+	resolver := m.sandbox
+	m.mu.Unlock()
+	if resolver == nil {
+		return nil, nil
+	}
+	return resolver.ResolveSessionSandbox(ctx, session, meta, codexHome)
+}
+
+// SetUsageRecorder routes the token usage reported by Codex sessions to
+// recorder.
+func (m *CodexLiveManager) SetUsageRecorder(recorder SessionUsageRecorder) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.usage = recorder
+	for _, ls := range m.sessions {
+		ls.setUsageRecorder(recorder)
+	}
+}
+
 func (m *CodexLiveManager) StartTurn(
 	ctx context.Context,
 	session *types.Session,
@@ -226,7 +301,17 @@ func (m *CodexLiveManager) ensure(ctx context.Context, session *types.Session, m
 
 	latestMeta := m.refreshSessionMeta(session.ID, meta)
 	runtimeOptions, model := codexRuntimeConfig(latestMeta)
-	client, err := startCodexAppServer(ctx, session.Cwd, codexHome, m.logger)
+	env, err := m.sessionEnv(ctx, latestMeta)
+	if err != nil {
+		m.logger.Error("codex_env_profile_error", logging.F("session_id", session.ID), logging.F("error", err))
+		return nil, err
+	}
+	sandbox, err := m.sessionSandbox(ctx, session, latestMeta, codexHome)
+	if err != nil {
+		m.logger.Error("codex_sandbox_error", logging.F("session_id", session.ID), logging.F("error", err))
+		return nil, err
+	}
+	client, err := startCodexAppServerForSession(ctx, session.Cwd, codexHome, env, sandbox, m.logger)
 	if err != nil {
 		m.logger.Error("codex_start_error", logging.F("session_id", session.ID), logging.F("error", err))
 		return nil, err
@@ -284,6 +369,10 @@ func (m *CodexLiveManager) ensure(ctx context.Context, session *types.Session, m
 		stores:    m.stores,
 		notifier:  m.notifier,
 	}
+	m.mu.Lock()
+	ls.usage = m.usage
+	ls.approvals = m.approvals
+	m.mu.Unlock()
 	ls.start()
 
 	m.mu.Lock()
@@ -368,6 +457,8 @@ type codexLiveSession struct {
 	hub        *codexSubscriberHub
 	stores     *Stores
 	notifier   NotificationPublisher
+	usage      SessionUsageRecorder
+	approvals  ApprovalStorage
 	activeTurn string
 	starting   bool
 	lastActive time.Time
@@ -405,6 +496,25 @@ func (s *codexLiveSession) SetNotificationPublisher(notifier NotificationPublish
 	s.notifier = notifier
 }
 
+func (s *codexLiveSession) setUsageRecorder(recorder SessionUsageRecorder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usage = recorder
+}
+
+func (s *codexLiveSession) recordUsage(event types.CodexEvent) {
+	report, ok := codexUsageReport(event)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	recorder := s.usage
+	s.mu.Unlock()
+	if recorder != nil {
+		recorder.RecordUsage(s.sessionID, "codex", report)
+	}
+}
+
 func (s *codexLiveSession) start() {
 	go func() {
 		notes := s.client.Notifications()
@@ -449,6 +559,7 @@ func (s *codexLiveSession) handleNote(msg rpcMessage) {
 		}
 	}
 	s.hub.Broadcast(event)
+	s.recordUsage(event)
 	if msg.Method == "turn/completed" {
 		var payload struct {
 			Turn struct {
@@ -513,6 +624,10 @@ func (s *codexLiveSession) handleRequest(msg rpcMessage) {
 		TS:     time.Now().UTC().Format(time.RFC3339Nano),
 	}
 	s.hub.Broadcast(event)
+	if msg.ID != nil && s.approvals != nil && isApprovalMethod(msg.Method) {
+		_ = s.approvals.StoreApproval(context.Background(), s.sessionID, *msg.ID, msg.Method, msg.Params)
+		return
+	}
 	if msg.ID != nil && s.stores != nil && s.stores.Approvals != nil && isApprovalMethod(msg.Method) {
 		approval := &types.Approval{
 			SessionID: s.sessionID,
@@ -530,34 +645,7 @@ func (s *codexLiveSession) publishApprovalRequiredNotification(msg rpcMessage) {
 	if s == nil || s.notifier == nil || msg.ID == nil || !isApprovalMethod(msg.Method) {
 		return
 	}
-	requestID := *msg.ID
-	source := "approval_request:" + strings.TrimSpace(s.sessionID) + ":" + strconv.Itoa(requestID)
-	event := types.NotificationEvent{
-		Trigger:    types.NotificationTriggerTurnCompleted,
-		OccurredAt: time.Now().UTC().Format(time.RFC3339Nano),
-		SessionID:  strings.TrimSpace(s.sessionID),
-		Status:     "approval_required",
-		Source:     source,
-		Payload: map[string]any{
-			"kind":       "approval_required",
-			"request_id": requestID,
-			"method":     strings.TrimSpace(msg.Method),
-		},
-	}
-	if s.stores != nil && s.stores.Sessions != nil {
-		if record, ok, err := s.stores.Sessions.GetRecord(context.Background(), s.sessionID); err == nil && ok && record != nil && record.Session != nil {
-			event.Provider = strings.TrimSpace(record.Session.Provider)
-			event.Title = strings.TrimSpace(record.Session.Title)
-			event.Cwd = strings.TrimSpace(record.Session.Cwd)
-		}
-	}
-	if s.stores != nil && s.stores.SessionMeta != nil {
-		if meta, ok, err := s.stores.SessionMeta.Get(context.Background(), s.sessionID); err == nil && ok && meta != nil {
-			event.WorkspaceID = strings.TrimSpace(meta.WorkspaceID)
-			event.WorktreeID = strings.TrimSpace(meta.WorktreeID)
-		}
-	}
-	s.notifier.Publish(event)
+	s.notifier.Publish(approvalPendingNotificationEvent(context.Background(), s.stores, s.sessionID, *msg.ID, msg.Method))
 }
 
 func (s *codexLiveSession) maybeClose() {
@@ -292,7 +292,7 @@ func TestCodexLiveSessionHandleRequestPublishesApprovalNotification(t *testing.T
 		t.Fatalf("expected one approval notification, got %d", len(notifier.events))
 	}
 	event := notifier.events[0]
-	if event.Trigger != types.NotificationTriggerTurnCompleted {
+	if event.Trigger != types.NotificationTriggerApprovalPending {
 		t.Fatalf("unexpected trigger: %q", event.Trigger)
 	}
 	if event.Status != "approval_required" {
@@ -0,0 +1,150 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"control/internal/providers"
+	"control/internal/types"
+)
+
+// providerCommitMessageGenerator drafts commit messages by running the
+// session's provider CLI once in non-interactive mode. Only providers with
+// such a mode are supported: claude --print and codex exec. The CLI runs with
+// the session's env profile and inside its sandbox, like the session itself.
+type providerCommitMessageGenerator struct {
+	meta    SessionMetaStore
+	env     SessionEnvResolver
+	sandbox SessionSandboxResolver
+}
+
+func newProviderCommitMessageGenerator(stores *Stores) *providerCommitMessageGenerator {
+	generator := &providerCommitMessageGenerator{
+		env:     newWorkspaceEnvResolver(stores),
+		sandbox: newWorkspaceSandboxResolver(stores),
+	}
+	if stores != nil {
+		generator.meta = stores.SessionMeta
+	}
+	return generator
+}
+
+func (g *providerCommitMessageGenerator) GenerateSessionCommitMessage(ctx context.Context, session *types.Session, dir, prompt string) (string, error) {
+	if session == nil {
+		return "", errors.New("session is required")
+	}
+	def, ok := providers.Lookup(session.Provider)
+	if !ok {
+		return "", fmt.Errorf("unknown provider %q", session.Provider)
+	}
+	scratch, err := os.MkdirTemp("", "archon-commit-message-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(scratch)
+	args, readOutput, err := providerCommitMessageArgs(def, prompt, scratch)
+	if err != nil {
+		return "", err
+	}
+	name, err := resolveProviderCommandName(def, session.Cmd)
+	if err != nil {
+		return "", err
+	}
+	meta, err := g.sessionMeta(ctx, session.ID)
+	if err != nil {
+		return "", err
+	}
+	codexHome := ""
+	if def.Runtime == providers.RuntimeCodex {
+		codexHome = resolveCodexHome(session.Cwd, resolveWorkspacePathFromMeta(meta))
+	}
+	env, err := g.sessionEnv(ctx, meta)
+	if err != nil {
+		return "", err
+	}
+	if codexHome != "" {
+		env = append(env, "CODEX_HOME="+codexHome)
+	}
+	sandbox, err := g.sessionSandbox(ctx, session, meta, codexHome)
+	if err != nil {
+		return "", err
+	}
+	if sandbox != nil {
+		sandbox.cwd = dir
+		sandbox.readOnly = append(sandbox.readOnly, dir)
+		sandbox.writable = append(sandbox.writable, scratch)
+	}
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = mergeSessionEnv(os.Environ(), env)
+	}
+	sandbox.wrap(cmd)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return "", fmt.Errorf("%s: %w: %s", def.Name, err, detail)
+		}
+		return "", fmt.Errorf("%s: %w", def.Name, err)
+	}
+	return readOutput(stdout.String())
+}
+
+func (g *providerCommitMessageGenerator) sessionMeta(ctx context.Context, sessionID string) (*types.SessionMeta, error) {
+	if g == nil || g.meta == nil || strings.TrimSpace(sessionID) == "" {
+		return nil, nil
+	}
+	meta, ok, err := g.meta.Get(ctx, sessionID)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// sessionEnv resolves the env profile of the session's workspace and
+// worktree.
+func (g *providerCommitMessageGenerator) sessionEnv(ctx context.Context, meta *types.SessionMeta) ([]string, error) {
+	if g == nil || g.env == nil || meta == nil {
+		return nil, nil
+	}
+	env, _, err := g.env.ResolveSessionEnv(ctx, meta.WorkspaceID, meta.WorktreeID)
+	return env, err
+}
+
+// sessionSandbox builds the sandbox of the session, or nil when the sandbox
+// is disabled.
+func (g *providerCommitMessageGenerator) sessionSandbox(ctx context.Context, session *types.Session, meta *types.SessionMeta, codexHome string) (*sessionSandbox, error) {
+	if g == nil || g.sandbox == nil {
+		return nil, nil
+	}
+	return g.sandbox.ResolveSessionSandbox(ctx, session, meta, codexHome)
+}
+
+// providerCommitMessageArgs returns the one-shot arguments for def and how
+// the message is read back from the run. scratch is a writable directory the
+// run may leave its output in.
+func providerCommitMessageArgs(def providers.Definition, prompt, scratch string) ([]string, func(string) (string, error), error) {
+	switch def.Runtime {
+	case providers.RuntimeClaude:
+		args := []string{"--print", "--output-format", "text", prompt}
+		return args, func(stdout string) (string, error) { return stdout, nil }, nil
+	case providers.RuntimeCodex:
+		outputPath := filepath.Join(scratch, "message.txt")
+		args := []string{"exec", "--skip-git-repo-check", "--sandbox", "read-only", "--color", "never", "--output-last-message", outputPath, prompt}
+		readOutput := func(string) (string, error) {
+			data, err := os.ReadFile(outputPath)
+			return string(data), err
+		}
+		return args, readOutput, nil
+	default:
+		return nil, nil, fmt.Errorf("provider %s cannot draft commit messages", def.Name)
+	}
+}
@@ -19,6 +19,7 @@ type CompositeLiveManager struct {
 	stores    *Stores
 	logger    logging.Logger
 	notifier  NotificationPublisher
+	usage     SessionUsageRecorder
 }
 
 type managedTurnStarter interface {
@@ -60,6 +61,17 @@ func (m *CompositeLiveManager) SetNotificationPublisher(notifier NotificationPub
 	}
 }
 
+func (m *CompositeLiveManager) SetUsageRecorder(recorder SessionUsageRecorder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.usage = recorder
+	for _, s := range m.sessions {
+		if us, ok := s.(UsageReportingSession); ok {
+			us.SetUsageRecorder(recorder)
+		}
+	}
+}
+
 func (m *CompositeLiveManager) StartTurn(ctx context.Context, session *types.Session, meta *types.SessionMeta, input []map[string]any, opts *types.SessionRuntimeOptions) (string, error) {
 	if session == nil {
 		return "", errors.New("session is required")
@@ -152,6 +164,9 @@ func (m *CompositeLiveManager) ensure(ctx context.Context, session *types.Sessio
 	if ns, ok := ls.(NotifiableSession); ok && m.notifier != nil {
 		ns.SetNotificationPublisher(m.notifier)
 	}
+	if us, ok := ls.(UsageReportingSession); ok && m.usage != nil {
+		us.SetUsageRecorder(m.usage)
+	}
 
 	m.sessions[session.ID] = ls
 	return ls, nil
@@ -9,7 +9,9 @@ import (
 
 	"control/internal/guidedworkflows"
 	"control/internal/logging"
+	"control/internal/metrics"
 	"control/internal/store"
+	"control/internal/tracing"
 	"control/internal/types"
 )
 
@@ -21,6 +23,11 @@ type Daemon struct {
 	manager *SessionManager
 	stores  *Stores
 	logger  logging.Logger
+
+	approvalEvents *approvalEventHub
+	metrics        *daemonMetrics
+	tracing        *daemonTracing
+	watchdog       *turnWatchdog
 }
 
 type Stores struct {
@@ -34,6 +41,8 @@ type Stores struct {
 	Sessions          SessionIndexStore
 	Approvals         ApprovalStore
 	Notes             NoteStore
+	Snippets          SnippetStore
+	ApprovalRules     ApprovalRuleStore
 }
 
 type WorkspaceStore interface {
@@ -92,6 +101,7 @@ type SessionIndexStore interface {
 }
 
 type ApprovalStore interface {
+	List(ctx context.Context) ([]*types.Approval, error)
 	ListBySession(ctx context.Context, sessionID string) ([]*types.Approval, error)
 	Get(ctx context.Context, sessionID string, requestID int) (*types.Approval, bool, error)
 	Upsert(ctx context.Context, approval *types.Approval) (*types.Approval, error)
@@ -106,6 +116,20 @@ type NoteStore interface {
 	Delete(ctx context.Context, id string) error
 }
 
+type SnippetStore interface {
+	List(ctx context.Context) ([]*types.Snippet, error)
+	Get(ctx context.Context, id string) (*types.Snippet, bool, error)
+	Upsert(ctx context.Context, snippet *types.Snippet) (*types.Snippet, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type ApprovalRuleStore interface {
+	List(ctx context.Context) ([]*types.ApprovalRule, error)
+	Get(ctx context.Context, id string) (*types.ApprovalRule, bool, error)
+	Upsert(ctx context.Context, rule *types.ApprovalRule) (*types.ApprovalRule, error)
+	Delete(ctx context.Context, id string) error
+}
+
 type guidedWorkflowRunCloser interface {
 	Close()
 }
@@ -113,18 +137,34 @@ type guidedWorkflowRunCloser interface {
 var newGuidedWorkflowRunServiceFn = newGuidedWorkflowRunService
 
 func New(addr, token, version string, manager *SessionManager, stores *Stores) *Daemon {
+	daemonMetrics := newDaemonMetrics(metrics.NewRegistry())
+	stores = instrumentStores(stores, daemonMetrics)
+	approvalEvents := newApprovalEventHub()
+	stores = withApprovalEvents(stores, approvalEvents)
 	if manager != nil && stores != nil && stores.SessionMeta != nil {
 		manager.SetMetaStore(stores.SessionMeta)
 	}
 	if manager != nil && stores != nil && stores.Sessions != nil {
 		manager.SetSessionStore(stores.Sessions)
 	}
+	if manager != nil {
+		if resolver := newWorkspaceEnvResolver(stores); resolver != nil {
+			manager.SetEnvResolver(resolver)
+		}
+		if resolver := newWorkspaceInstructionsResolver(stores); resolver != nil {
+			manager.SetInstructionsResolver(resolver)
+		}
+	}
 	return &Daemon{
 		addr:    addr,
 		token:   token,
 		version: version,
 		manager: manager,
 		stores:  stores,
+
+		approvalEvents: approvalEvents,
+		metrics:        daemonMetrics,
+		watchdog:       newTurnWatchdog(),
 	}
 }
 
@@ -133,6 +173,9 @@ func (d *Daemon) Run(ctx context.Context) error {
 	if d.logger == nil {
 		d.logger = logging.New(log.Writer(), logging.ParseLevel(coreCfg.LogLevel()))
 	}
+	traceProvider, stopTracing := startDaemonTracing(coreCfg, d.logger)
+	defer stopTracing()
+	d.tracing = newDaemonTracing(traceProvider)
 	cloudAuth, err := newCloudAuthRuntime(coreCfg, d.version)
 	if err != nil {
 		return err
@@ -150,8 +193,14 @@ func (d *Daemon) Run(ctx context.Context) error {
 		NewNotificationDispatcher(defaultNotificationSinks(), d.logger),
 		d.logger,
 	)
+	notifier.SetDigestTime(coreCfg.NotificationDigestTime())
+	notifier.SetTracing(d.tracing)
 	defer notifier.Close()
 	liveCodex := NewCodexLiveManager(d.stores, d.logger)
+	if resolver := newWorkspaceEnvResolver(d.stores); resolver != nil {
+		liveCodex.SetEnvResolver(resolver)
+	}
+	liveCodex.SetSandboxResolver(newWorkspaceSandboxResolver(d.stores))
 	guided := newGuidedWorkflowOrchestrator(coreCfg)
 	reconcileResult, reconcileErr := reconcileGuidedWorkflowRunSnapshots(
 		context.Background(),
@@ -173,7 +222,7 @@ func (d *Daemon) Run(ctx context.Context) error {
 	if err := compositeLive.ValidateLifecycleWiring("opencode", "kilocode"); err != nil {
 		return err
 	}
-	workflowRuns := newGuidedWorkflowRunServiceFn(coreCfg, d.stores, d.manager, compositeLive, d.logger)
+	workflowRuns := newGuidedWorkflowRunServiceFn(coreCfg, d.stores, d.manager, compositeLive, d.tracing, d.logger)
 	if closer, ok := any(workflowRuns).(guidedWorkflowRunCloser); ok {
 		defer closer.Close()
 	}
@@ -194,10 +243,20 @@ func (d *Daemon) Run(ctx context.Context) error {
 	if processor, ok := any(workflowRuns).(guidedworkflows.TurnEventProcessor); ok {
 		turnProcessor = processor
 	}
-	eventPublisher := NewGuidedWorkflowNotificationPublisher(notifier, guided, turnProcessor)
+	eventPublisher := newTracingNotificationPublisher(
+		newTurnWatchdogNotificationPublisher(
+			NewGuidedWorkflowNotificationPublisher(newMetricsNotificationPublisher(notifier, d.metrics), guided, turnProcessor),
+			d.watchdog,
+		),
+		d.tracing,
+	)
+	stopTurnWatchdog := d.watchdog.Run(eventPublisher, time.Duration(coreCfg.NotificationLongRunningTurnMinutes())*time.Minute)
+	defer stopTurnWatchdog()
+	usage := NewSessionUsageStore()
 	if d.manager != nil {
 		d.manager.SetNotificationPublisher(eventPublisher)
 		d.manager.SetMetadataEventPublisher(metadataEvents)
+		d.manager.SetUsageRecorder(usage)
 	}
 	if metadataAware, ok := workflowRuns.(interface {
 		SetMetadataEventPublisher(guidedworkflows.MetadataEventPublisher)
@@ -205,6 +264,8 @@ func (d *Daemon) Run(ctx context.Context) error {
 		metadataAware.SetMetadataEventPublisher(newGuidedWorkflowMetadataEventAdapter(metadataEvents))
 	}
 	api.Notifier = eventPublisher
+	api.NotificationQueue = notifier
+	api.NotificationTester = notifier
 	api.GuidedWorkflows = guided
 	api.WorkflowRuns = workflowRuns
 	api.WorkflowSessionVisibility = newWorkflowRunSessionVisibilitySyncService(d.stores, d.logger)
@@ -220,7 +281,15 @@ func (d *Daemon) Run(ctx context.Context) error {
 	api.WorkflowPolicy = newGuidedWorkflowPolicyResolver(coreCfg)
 	api.WorkflowDispatchDefaults = guidedWorkflowDispatchDefaultsFromCoreConfig(coreCfg)
 	api.TitleGeneration = titleGeneration
+	if generator, ok := titleGenerator.(CommitMessageGenerator); ok {
+		api.CommitMessages = generator
+	}
+	api.SessionCommitMessages = newProviderCommitMessageGenerator(d.stores)
 	api.MetadataEvents = metadataEvents
+	api.ApprovalEvents = d.approvalEvents
+	api.DaemonMetrics = d.metrics
+	api.DaemonTracing = d.tracing
+	api.TurnWatchdog = d.watchdog
 	api.FileSearches = NewFileSearchService(
 		NewDaemonFileSearchScopeResolver(d.manager, d.stores),
 		d.logger,
@@ -234,14 +303,26 @@ func (d *Daemon) Run(ctx context.Context) error {
 	api.LiveCodex.SetNotificationPublisher(eventPublisher)
 	compositeLive.SetNotificationPublisher(eventPublisher)
 	turnNotifier.SetNotificationPublisher(eventPublisher)
+	approvalStore.SetNotificationPublisher(eventPublisher)
+	liveCodex.SetUsageRecorder(usage)
+	compositeLive.SetUsageRecorder(usage)
+	api.Usage = usage
+	api.Symbols = NewSymbolIndex()
 	api.LiveManager = compositeLive
+	approvalStore.SetApprovalRuleResolver(newApprovalRuleEngine(d.stores, func(ctx context.Context, sessionID string, requestID int, decision string, acceptSettings map[string]any) error {
+		return api.newSessionService().Approve(ctx, sessionID, requestID, decision, nil, acceptSettings)
+	}, artifactRepository, d.logger))
+	api.ApprovalStorage = approvalStore
+	liveCodex.SetApprovalStorage(approvalStore)
 	approvalSync := NewApprovalResyncService(d.stores, d.logger)
+	approvalSync.SetApprovalStorage(approvalStore)
 
 	mux := http.NewServeMux()
 	api.RegisterRoutes(mux)
 
 	handler := TokenAuthMiddleware(d.token, mux)
 	handler = LoggingMiddleware(d.logger, handler)
+	handler = tracing.Middleware(d.tracing.Provider(), handler)
 	d.server = &http.Server{
 		Addr:    d.addr,
 		Handler: handler,
@@ -0,0 +1,183 @@
+package daemon
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"control/internal/metrics"
+	"control/internal/types"
+)
+
+var approvalWaitBuckets = []float64{1, 5, 15, 30, 60, 300, 900, 1800, 3600, 4 * 3600}
+
+// daemonMetrics owns the process-wide instruments exposed on /metrics. Values
+// that can be read from live services at scrape time (sessions, workflow run
+// counters, notification queue depth) are collected by the handler instead.
+type daemonMetrics struct {
+	registry       *metrics.Registry
+	turnDurations  *metrics.HistogramVec
+	turnsCompleted *metrics.CounterVec
+	approvalWait   *metrics.HistogramVec
+	hubReconnects  *metrics.CounterVec
+	sseSubscribers *metrics.GaugeVec
+	storeLatency   *metrics.HistogramVec
+	storeErrors    *metrics.CounterVec
+	turns          *pendingTurnTracker[string]
+}
+
+func newDaemonMetrics(registry *metrics.Registry) *daemonMetrics {
+	if registry == nil {
+		registry = metrics.NewRegistry()
+	}
+	return &daemonMetrics{
+		registry: registry,
+		turnDurations: registry.NewHistogramVec(
+			"archon_turn_duration_seconds",
+			"Time from a turn being sent to its completion event.",
+			metrics.DefaultLongDurationBuckets,
+			"provider",
+		),
+		turnsCompleted: registry.NewCounterVec(
+			"archon_turns_completed",
+			"Turn completion events observed by the daemon.",
+			"provider", "status",
+		),
+		approvalWait: registry.NewHistogramVec(
+			"archon_approval_wait_seconds",
+			"Time an approval request waited before a decision was sent.",
+			approvalWaitBuckets,
+			"provider", "method",
+		),
+		hubReconnects: registry.NewCounterVec(
+			"archon_transcript_hub_reconnects",
+			"Canonical transcript hub ingress reconnect attempts.",
+			"provider",
+		),
+		sseSubscribers: registry.NewGaugeVec(
+			"archon_sse_subscribers",
+			"Open server-sent event subscriptions by stream.",
+			"stream",
+		),
+		storeLatency: registry.NewHistogramVec(
+			"archon_store_operation_duration_seconds",
+			"Latency of persistent store operations.",
+			metrics.DefaultDurationBuckets,
+			"store", "operation",
+		),
+		storeErrors: registry.NewCounterVec(
+			"archon_store_operation_errors",
+			"Persistent store operations that returned an error.",
+			"store", "operation",
+		),
+		turns: newPendingTurnTracker[string](),
+	}
+}
+
+func (m *daemonMetrics) Registry() *metrics.Registry {
+	if m == nil {
+		return nil
+	}
+	return m.registry
+}
+
+func (m *daemonMetrics) TurnStarted(sessionID, turnID, provider string, at time.Time) {
+	if m == nil {
+		return
+	}
+	m.turns.Start(sessionID, turnID, provider, at)
+}
+
+func (m *daemonMetrics) TurnCompleted(sessionID, turnID, provider, status string, at time.Time) {
+	if m == nil {
+		return
+	}
+	startedProvider, started, ok := m.turns.Complete(sessionID, turnID)
+	provider = metricsLabel(provider)
+	if provider == "unknown" {
+		provider = metricsLabel(startedProvider)
+	}
+	m.turnsCompleted.Inc(provider, metricsLabel(status))
+	if !ok {
+		return
+	}
+	if elapsed := at.Sub(started); elapsed >= 0 {
+		m.turnDurations.Observe(elapsed.Seconds(), provider)
+	}
+}
+
+func (m *daemonMetrics) ApprovalResolved(provider, method string, createdAt, resolvedAt time.Time) {
+	if m == nil || createdAt.IsZero() {
+		return
+	}
+	if wait := resolvedAt.Sub(createdAt); wait >= 0 {
+		m.approvalWait.Observe(wait.Seconds(), metricsLabel(provider), metricsLabel(method))
+	}
+}
+
+func (m *daemonMetrics) TranscriptHubReconnect(provider string) {
+	if m == nil {
+		return
+	}
+	m.hubReconnects.Inc(metricsLabel(provider))
+}
+
+// TrackSSESubscriber marks an SSE subscription open and returns the function
+// that releases it.
+func (m *daemonMetrics) TrackSSESubscriber(stream string) func() {
+	if m == nil {
+		return func() {}
+	}
+	stream = metricsLabel(stream)
+	m.sseSubscribers.Inc(stream)
+	var once sync.Once
+	return func() {
+		once.Do(func() { m.sseSubscribers.Dec(stream) })
+	}
+}
+
+func (m *daemonMetrics) ObserveStoreOperation(store, operation string, elapsed time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.storeLatency.Observe(elapsed.Seconds(), store, operation)
+	if err != nil {
+		m.storeErrors.Inc(store, operation)
+	}
+}
+
+func metricsLabel(raw string) string {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
+
+// metricsNotificationPublisher observes turn completions on their way to the
+// notification pipeline.
+type metricsNotificationPublisher struct {
+	downstream NotificationPublisher
+	metrics    *daemonMetrics
+	now        func() time.Time
+}
+
+func newMetricsNotificationPublisher(downstream NotificationPublisher, m *daemonMetrics) NotificationPublisher {
+	if m == nil {
+		return downstream
+	}
+	return &metricsNotificationPublisher{
+		downstream: downstream,
+		metrics:    m,
+		now:        func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (p *metricsNotificationPublisher) Publish(event types.NotificationEvent) {
+	if event.Trigger == types.NotificationTriggerTurnCompleted {
+		p.metrics.TurnCompleted(event.SessionID, event.TurnID, event.Provider, event.Status, p.now())
+	}
+	if p.downstream != nil {
+		p.downstream.Publish(event)
+	}
+}
@@ -39,6 +39,7 @@ func TestDaemonRunClosesWorkflowRunServiceOnShutdown(t *testing.T) {
 		*Stores,
 		*SessionManager,
 		LiveManager,
+		*daemonTracing,
 		logging.Logger,
 	) guidedworkflows.RunService {
 		return trackedService
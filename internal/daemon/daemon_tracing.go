@@ -0,0 +1,152 @@
+package daemon
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"control/internal/config"
+	"control/internal/logging"
+	"control/internal/tracing"
+	"control/internal/types"
+)
+
+const tracingShutdownTimeout = 5 * time.Second
+
+// startDaemonTracing creates the OTLP trace provider when tracing is enabled
+// and returns it with the function that flushes it. The provider is nil when
+// tracing is disabled.
+func startDaemonTracing(cfg config.CoreConfig, logger logging.Logger) (*tracing.Provider, func()) {
+	if !cfg.TracingEnabled() {
+		return nil, func() {}
+	}
+	exporter := tracing.NewOTLPHTTPExporter(tracing.OTLPHTTPExporterOptions{
+		Endpoint:    cfg.TracingEndpoint(),
+		Headers:     cfg.TracingHeaders(),
+		ServiceName: cfg.TracingServiceName(),
+	})
+	provider := tracing.NewProvider(exporter, tracing.ProviderOptions{
+		OnExportError: func(err error) {
+			if logger != nil {
+				logger.Warn("tracing_export_failed", logging.F("error", err))
+			}
+		},
+	})
+	if logger != nil {
+		logger.Info("tracing_enabled",
+			logging.F("endpoint", cfg.TracingEndpoint()),
+			logging.F("service_name", cfg.TracingServiceName()),
+		)
+	}
+	return provider, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil && logger != nil {
+			logger.Warn("tracing_shutdown_failed", logging.F("error", err))
+		}
+	}
+}
+
+// daemonTracing opens the daemon's spans on its provider and keeps turn spans
+// open between the send that started a turn and the completion event that
+// ends it. A nil provider disables tracing.
+type daemonTracing struct {
+	provider *tracing.Provider
+	turns    *pendingTurnTracker[*tracing.Span]
+}
+
+func newDaemonTracing(provider *tracing.Provider) *daemonTracing {
+	return &daemonTracing{provider: provider, turns: newPendingTurnTracker[*tracing.Span]()}
+}
+
+// Provider returns the trace provider, or nil when tracing is disabled.
+func (t *daemonTracing) Provider() *tracing.Provider {
+	if t == nil {
+		return nil
+	}
+	return t.provider
+}
+
+// Start opens an internal span. When tracing is disabled it returns ctx
+// unchanged and a nil span.
+func (t *daemonTracing) Start(ctx context.Context, name string, attrs ...tracing.Attribute) (context.Context, *tracing.Span) {
+	return t.Provider().Start(ctx, name, tracing.SpanKindInternal, attrs...)
+}
+
+// StartClient opens a client span for a call out to a provider.
+func (t *daemonTracing) StartClient(ctx context.Context, name string, attrs ...tracing.Attribute) (context.Context, *tracing.Span) {
+	return t.Provider().Start(ctx, name, tracing.SpanKindClient, attrs...)
+}
+
+// TurnStarted holds span open until the turn completes. Without a tracker
+// the span is ended right away so it is not leaked.
+func (t *daemonTracing) TurnStarted(sessionID, turnID string, span *tracing.Span) {
+	if span == nil {
+		return
+	}
+	if t == nil {
+		span.End()
+		return
+	}
+	t.turns.Start(sessionID, turnID, span, time.Now().UTC())
+}
+
+// TurnCompleted ends the pending turn span for the event and returns its
+// traceparent so downstream work can join the same trace.
+func (t *daemonTracing) TurnCompleted(event types.NotificationEvent) string {
+	if t == nil {
+		return ""
+	}
+	span, _, ok := t.turns.Complete(event.SessionID, event.TurnID)
+	if !ok || span == nil {
+		return ""
+	}
+	status := strings.TrimSpace(event.Status)
+	span.SetAttributes(
+		tracing.String("turn.status", status),
+		tracing.String("turn.completion_source", strings.TrimSpace(event.Source)),
+	)
+	if turnID := strings.TrimSpace(event.TurnID); turnID != "" {
+		span.SetAttributes(tracing.String("turn.completed_id", turnID))
+	}
+	if status == "failed" || status == "error" {
+		span.SetStatus(tracing.StatusError, "turn "+status)
+	}
+	span.End()
+	return span.SpanContext().Traceparent()
+}
+
+// tracingNotificationPublisher closes turn spans and stamps the traceparent on
+// turn completion events before any other publisher sees them.
+type tracingNotificationPublisher struct {
+	downstream NotificationPublisher
+	tracing    *daemonTracing
+}
+
+func newTracingNotificationPublisher(downstream NotificationPublisher, t *daemonTracing) NotificationPublisher {
+	if t == nil {
+		return downstream
+	}
+	return &tracingNotificationPublisher{downstream: downstream, tracing: t}
+}
+
+func (p *tracingNotificationPublisher) Publish(event types.NotificationEvent) {
+	if event.Trigger == types.NotificationTriggerTurnCompleted {
+		if traceparent := p.tracing.TurnCompleted(event); traceparent != "" && event.TraceParent == "" {
+			event.TraceParent = traceparent
+		}
+	}
+	if p.downstream != nil {
+		p.downstream.Publish(event)
+	}
+}
+
+func sessionSpanAttributes(session *types.Session) []tracing.Attribute {
+	if session == nil {
+		return nil
+	}
+	return []tracing.Attribute{
+		tracing.String("session.id", session.ID),
+		tracing.String("session.provider", session.Provider),
+	}
+}
@@ -0,0 +1,105 @@
+package daemon
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"control/internal/guidedworkflows"
+	"control/internal/tracing"
+	"control/internal/types"
+)
+
+type recordingSpanExporter struct {
+	mu    sync.Mutex
+	spans []tracing.SpanData
+}
+
+func (e *recordingSpanExporter) Export(_ context.Context, spans []tracing.SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *recordingSpanExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+func (e *recordingSpanExporter) Spans() []tracing.SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]tracing.SpanData(nil), e.spans...)
+}
+
+func TestTracingNotificationPublisherEndsTurnSpanAndStampsTraceparent(t *testing.T) {
+	exporter := &recordingSpanExporter{}
+	provider := tracing.NewProvider(exporter, tracing.ProviderOptions{})
+	_, turnSpan := provider.Start(context.Background(), "session.turn", tracing.SpanKindInternal)
+
+	turns := newDaemonTracing(provider)
+	turns.TurnStarted("s1", "turn-1", turnSpan)
+	downstream := &captureNotificationPublisher{}
+	publisher := newTracingNotificationPublisher(downstream, turns)
+	publisher.Publish(types.NotificationEvent{
+		Trigger:   types.NotificationTriggerTurnCompleted,
+		SessionID: "s1",
+		Status:    "failed",
+	})
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	events := downstream.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected one forwarded event, got %d", len(events))
+	}
+	if got, want := events[0].TraceParent, turnSpan.SpanContext().Traceparent(); got != want {
+		t.Fatalf("expected traceparent %q, got %q", want, got)
+	}
+	spans := exporter.Spans()
+	if len(spans) != 1 || spans[0].Name != "session.turn" {
+		t.Fatalf("expected the turn span to be exported, got %#v", spans)
+	}
+	if spans[0].StatusCode != tracing.StatusError {
+		t.Fatalf("expected failed turn to mark the span as an error, got %#v", spans[0])
+	}
+}
+
+func TestGuidedWorkflowGateDispatchLinksTraceID(t *testing.T) {
+	exporter := &recordingSpanExporter{}
+	provider := tracing.NewProvider(exporter, tracing.ProviderOptions{})
+
+	gateway := &stubGuidedWorkflowSessionGateway{
+		sessions: []*types.Session{
+			{ID: "sess-1", Provider: "codex", Status: types.SessionStatusRunning},
+		},
+		meta: []*types.SessionMeta{
+			{SessionID: "sess-1", WorkspaceID: "ws-1", WorktreeID: "wt-1"},
+		},
+		turnID: "turn-gate",
+	}
+	dispatcher := &guidedWorkflowPromptDispatcher{sessions: gateway, tracing: newDaemonTracing(provider)}
+	result, err := dispatcher.DispatchGate(context.Background(), guidedworkflows.GateDispatchRequest{
+		RunID:       "run-1",
+		WorkspaceID: "ws-1",
+		WorktreeID:  "wt-1",
+		SessionID:   "sess-1",
+		GateID:      "gate-1",
+		GateKind:    guidedworkflows.WorkflowGateKindLLMJudge,
+		Prompt:      "judge prompt",
+	})
+	if err != nil {
+		t.Fatalf("DispatchGate: %v", err)
+	}
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	spans := exporter.Spans()
+	if len(spans) != 1 || spans[0].Name != "workflow.dispatch" {
+		t.Fatalf("expected a workflow dispatch span, got %#v", spans)
+	}
+	if result.TraceID == "" || result.TraceID != spans[0].SpanContext.TraceID.String() {
+		t.Fatalf("expected gate result trace id %q to match span, got %q", spans[0].SpanContext.TraceID, result.TraceID)
+	}
+}
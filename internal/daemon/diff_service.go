@@ -0,0 +1,99 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"control/internal/store"
+	"control/internal/types"
+)
+
+// FindWorktree looks a worktree up by id across all workspaces.
+func (s *WorkspaceService) FindWorktree(ctx context.Context, worktreeID string) (*types.Workspace, *types.Worktree, error) {
+	if s.workspaces == nil || s.worktrees == nil {
+		return nil, nil, unavailableError("workspace store not available", nil)
+	}
+	worktreeID = strings.TrimSpace(worktreeID)
+	if worktreeID == "" {
+		return nil, nil, invalidError("worktree id is required", nil)
+	}
+	workspaces, err := s.workspaces.List(ctx)
+	if err != nil {
+		return nil, nil, unavailableError(err.Error(), err)
+	}
+	for _, ws := range workspaces {
+		if ws == nil {
+			continue
+		}
+		worktrees, err := s.worktrees.ListWorktrees(ctx, ws.ID)
+		if err != nil {
+			if errors.Is(err, store.ErrWorkspaceNotFound) {
+				continue
+			}
+			return nil, nil, unavailableError(err.Error(), err)
+		}
+		for _, wt := range worktrees {
+			if wt != nil && wt.ID == worktreeID {
+				return ws, wt, nil
+			}
+		}
+	}
+	return nil, nil, notFoundError("worktree not found", store.ErrWorktreeNotFound)
+}
+
+// WorktreeDiff returns the working tree changes of a worktree and its linked
+// worktrees against base (HEAD when empty).
+func (s *WorkspaceService) WorktreeDiff(ctx context.Context, worktreeID, base string) (*types.GitDiff, error) {
+	ws, wt, err := s.FindWorktree(ctx, worktreeID)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := readLinkedGitDiff(ctx, wt.Path, base, worktreeLinkedRoots(ctx, ws, wt))
+	if err != nil {
+		return nil, invalidError(err.Error(), err)
+	}
+	return diff, nil
+}
+
+// Diff returns the working tree changes of the repository containing the
+// session's cwd and of the linked repos of its workspace or worktree against
+// base (HEAD when empty). Sessions without a recorded cwd fall back to their
+// workspace or worktree path.
+func (s *SessionService) Diff(ctx context.Context, id, base string) (*types.GitDiff, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, invalidError("session id is required", nil)
+	}
+	session, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	cwd, err := s.sessionWorkingDir(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := readLinkedGitDiff(ctx, cwd, base, s.sessionLinkedRoots(ctx, session.ID))
+	if err != nil {
+		return nil, invalidError(err.Error(), err)
+	}
+	return diff, nil
+}
+
+// sessionWorkingDir returns the session's cwd, falling back to its
+// workspace or worktree path for sessions without a recorded cwd.
+func (s *SessionService) sessionWorkingDir(ctx context.Context, session *types.Session) (string, error) {
+	cwd := strings.TrimSpace(session.Cwd)
+	if cwd == "" {
+		if meta := s.getSessionMeta(ctx, session.ID); meta != nil {
+			resolved, _, err := s.resolveWorktreePath(ctx, meta.WorkspaceID, meta.WorktreeID)
+			if err != nil {
+				return "", err
+			}
+			cwd = resolved
+		}
+	}
+	if cwd == "" {
+		return "", invalidError("session has no working directory", nil)
+	}
+	return cwd, nil
+}
@@ -0,0 +1,196 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"control/internal/types"
+)
+
+const envSecretCommandTimeout = 10 * time.Second
+
+// SessionEnvResolver returns the profile environment for a session's provider
+// process and the names of the variables whose values are secrets.
+type SessionEnvResolver interface {
+	ResolveSessionEnv(ctx context.Context, workspaceID, worktreeID string) ([]string, []string, error)
+}
+
+type workspaceEnvResolver struct {
+	workspaces WorkspaceStore
+	worktrees  WorktreeStore
+}
+
+func newWorkspaceEnvResolver(stores *Stores) SessionEnvResolver {
+	if stores == nil || stores.Workspaces == nil {
+		return nil
+	}
+	return &workspaceEnvResolver{workspaces: stores.Workspaces, worktrees: stores.Worktrees}
+}
+
+func (r *workspaceEnvResolver) ResolveSessionEnv(ctx context.Context, workspaceID, worktreeID string) ([]string, []string, error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		return nil, nil, nil
+	}
+	workspace, ok, err := r.workspaces.Get(ctx, workspaceID)
+	if err != nil || !ok || workspace == nil || len(workspace.EnvProfiles) == 0 {
+		return nil, nil, err
+	}
+	name := workspace.EnvProfile
+	if worktreeID = strings.TrimSpace(worktreeID); worktreeID != "" && r.worktrees != nil {
+		worktrees, err := r.worktrees.ListWorktrees(ctx, workspaceID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, wt := range worktrees {
+			if wt != nil && wt.ID == worktreeID && strings.TrimSpace(wt.EnvProfile) != "" {
+				name = wt.EnvProfile
+			}
+		}
+	}
+	if strings.TrimSpace(name) == "" {
+		return nil, nil, nil
+	}
+	profile, ok := types.FindEnvProfile(workspace.EnvProfiles, name)
+	if !ok {
+		return nil, nil, fmt.Errorf("env profile %q not found in workspace %s", name, workspace.Name)
+	}
+	return resolveEnvProfile(ctx, profile)
+}
+
+func resolveEnvProfile(ctx context.Context, profile *types.EnvProfile) ([]string, []string, error) {
+	env := make([]string, 0, len(profile.Vars))
+	var secrets []string
+	for _, v := range profile.Vars {
+		value, err := resolveEnvVar(ctx, v)
+		if err != nil {
+			return nil, nil, fmt.Errorf("env profile %q: %s: %w", profile.Name, v.Name, err)
+		}
+		env = append(env, v.Name+"="+value)
+		if v.Secret() {
+			secrets = append(secrets, v.Name)
+		}
+	}
+	return env, secrets, nil
+}
+
+func resolveEnvVar(ctx context.Context, v types.EnvVar) (string, error) {
+	switch v.Source {
+	case types.EnvSourceLiteral:
+		return v.Value, nil
+	case types.EnvSourceEnv:
+		value, ok := os.LookupEnv(v.Value)
+		if !ok {
+			return "", fmt.Errorf("daemon environment variable %s is not set", v.Value)
+		}
+		return value, nil
+	case types.EnvSourceFile:
+		path := v.Value
+		if rest, ok := strings.CutPrefix(path, "~/"); ok {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			path = filepath.Join(home, rest)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	case types.EnvSourceCommand:
+		ctx, cancel := context.WithTimeout(ctx, envSecretCommandTimeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, "sh", "-c", v.Value)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			if detail := strings.TrimSpace(stderr.String()); detail != "" {
+				return "", fmt.Errorf("command failed: %s", detail)
+			}
+			return "", fmt.Errorf("command failed: %w", err)
+		}
+		return strings.TrimRight(stdout.String(), "\r\n"), nil
+	default:
+		return "", fmt.Errorf("unknown source %q", v.Source)
+	}
+}
+
+// mergeSessionEnv appends override to base, replacing variables base already
+// defines.
+func mergeSessionEnv(base, override []string) []string {
+	if len(base) == 0 {
+		return append([]string{}, override...)
+	}
+	out := make([]string, 0, len(base)+len(override))
+	index := map[string]int{}
+	for _, list := range [][]string{base, override} {
+		for _, entry := range list {
+			name, _, _ := strings.Cut(entry, "=")
+			if i, ok := index[name]; ok {
+				out[i] = entry
+				continue
+			}
+			index[name] = len(out)
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
+func redactSessionEnv(env, secrets []string) []string {
+	out := append([]string{}, env...)
+	if len(secrets) == 0 {
+		return out
+	}
+	secret := map[string]bool{}
+	for _, name := range secrets {
+		secret[name] = true
+	}
+	for i, entry := range out {
+		if name, _, _ := strings.Cut(entry, "="); secret[name] {
+			out[i] = name + "=" + types.RedactedEnvValue
+		}
+	}
+	return out
+}
+
+// stripSessionEnvNames drops the entries of env for variables that owned also
+// sets.
+func stripSessionEnvNames(env, owned []string) []string {
+	if len(owned) == 0 {
+		return env
+	}
+	names := map[string]bool{}
+	for _, entry := range owned {
+		name, _, _ := strings.Cut(entry, "=")
+		names[name] = true
+	}
+	out := make([]string, 0, len(env))
+	for _, entry := range env {
+		if name, _, _ := strings.Cut(entry, "="); !names[name] {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
+// stripRedactedEnv drops placeholders left by redactSessionEnv so a resumed
+// session picks up freshly resolved secrets instead.
+func stripRedactedEnv(env []string) []string {
+	out := make([]string, 0, len(env))
+	for _, entry := range env {
+		if _, value, _ := strings.Cut(entry, "="); value == types.RedactedEnvValue {
+			continue
+		}
+		out = append(out, entry)
+	}
+	return out
+}
@@ -0,0 +1,199 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"control/internal/store"
+	"control/internal/types"
+)
+
+func TestSessionManagerInjectsWorkspaceEnvProfileAndRedactsSecrets(t *testing.T) {
+	ctx := context.Background()
+	base := t.TempDir()
+	workspaceStore := store.NewFileWorkspaceStore(filepath.Join(base, "workspaces.json"))
+	repo := filepath.Join(base, "repo")
+	if err := ensureDir(repo); err != nil {
+		t.Fatalf("ensureDir: %v", err)
+	}
+	writeTestFile(t, filepath.Join(base, "token"), "file-secret\n")
+	t.Setenv("ARCHON_TEST_DAEMON_SECRET", "env-secret")
+	profiles, err := types.ParseEnvProfiles("dev: MODE=dev; prod: MODE=prod, TOKEN=file:" + filepath.Join(base, "token") +
+		", PASS=cmd:printf cmd-secret, KEY=env:ARCHON_TEST_DAEMON_SECRET")
+	if err != nil {
+		t.Fatalf("ParseEnvProfiles: %v", err)
+	}
+	ws, err := workspaceStore.Add(ctx, &types.Workspace{RepoPath: repo, EnvProfiles: profiles, EnvProfile: "dev"})
+	if err != nil {
+		t.Fatalf("Add workspace: %v", err)
+	}
+	wt, err := workspaceStore.AddWorktree(ctx, ws.ID, &types.Worktree{Path: repo, EnvProfile: "prod"})
+	if err != nil {
+		t.Fatalf("AddWorktree: %v", err)
+	}
+
+	manager := newTestManager(t)
+	manager.SetEnvResolver(newWorkspaceEnvResolver(&Stores{Workspaces: workspaceStore, Worktrees: workspaceStore}))
+	out := filepath.Join(base, "env.txt")
+	session, err := manager.StartSession(StartSessionConfig{
+		Provider:    "custom",
+		Cmd:         "sh",
+		Args:        []string{"-c", `printf '%s %s %s %s %s' "$MODE" "$TOKEN" "$PASS" "$KEY" "$EXTRA" > "$0"`, out},
+		Env:         []string{"EXTRA=plain"},
+		Cwd:         repo,
+		WorkspaceID: ws.ID,
+		WorktreeID:  wt.ID,
+	})
+	if err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+	waitForStatus(t, manager, session.ID, types.SessionStatusExited, 2*time.Second)
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read env: %v", err)
+	}
+	if got := string(data); got != "prod file-secret cmd-secret env-secret plain" {
+		t.Fatalf("unexpected provider env %q", got)
+	}
+	want := "MODE=prod,TOKEN=[redacted],PASS=[redacted],KEY=[redacted],EXTRA=plain"
+	if got := strings.Join(session.Env, ","); got != want {
+		t.Fatalf("expected redacted session env %q, got %q", want, got)
+	}
+}
+
+func TestSessionManagerResumeUsesEditedEnvProfile(t *testing.T) {
+	ctx := context.Background()
+	base := t.TempDir()
+	workspaceStore := store.NewFileWorkspaceStore(filepath.Join(base, "workspaces.json"))
+	repo := filepath.Join(base, "repo")
+	if err := ensureDir(repo); err != nil {
+		t.Fatalf("ensureDir: %v", err)
+	}
+	profiles, err := types.ParseEnvProfiles("dev: MODE=before")
+	if err != nil {
+		t.Fatalf("ParseEnvProfiles: %v", err)
+	}
+	ws, err := workspaceStore.Add(ctx, &types.Workspace{RepoPath: repo, EnvProfiles: profiles, EnvProfile: "dev"})
+	if err != nil {
+		t.Fatalf("Add workspace: %v", err)
+	}
+	resolver := newWorkspaceEnvResolver(&Stores{Workspaces: workspaceStore, Worktrees: workspaceStore})
+	out := filepath.Join(base, "env.txt")
+	cfg := StartSessionConfig{
+		Provider:    "custom",
+		Cmd:         "sh",
+		Args:        []string{"-c", `printf '%s %s' "$MODE" "$EXTRA" > "$0"`, out},
+		Env:         []string{"EXTRA=plain"},
+		Cwd:         repo,
+		WorkspaceID: ws.ID,
+	}
+
+	manager := newTestManager(t)
+	manager.SetEnvResolver(resolver)
+	session, err := manager.StartSession(cfg)
+	if err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+	waitForStatus(t, manager, session.ID, types.SessionStatusExited, 2*time.Second)
+	if got := strings.Join(session.Env, ","); got != "MODE=before,EXTRA=plain" {
+		t.Fatalf("unexpected persisted env %q", got)
+	}
+
+	ws.EnvProfiles, err = types.ParseEnvProfiles("dev: MODE=after")
+	if err != nil {
+		t.Fatalf("ParseEnvProfiles: %v", err)
+	}
+	if _, err := workspaceStore.Update(ctx, ws); err != nil {
+		t.Fatalf("Update workspace: %v", err)
+	}
+	resumed := newTestManager(t)
+	resumed.SetEnvResolver(resolver)
+	cfg.Env = session.Env
+	if _, err := resumed.ResumeSession(cfg, session); err != nil {
+		t.Fatalf("ResumeSession: %v", err)
+	}
+	waitForStatus(t, resumed, session.ID, types.SessionStatusExited, 2*time.Second)
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read env: %v", err)
+	}
+	if got := string(data); got != "after plain" {
+		t.Fatalf("expected the resumed session to get the edited profile, got %q", got)
+	}
+}
+
+func TestEnvProfileSpecsRoundTrip(t *testing.T) {
+	raw := "dev: A=1, B=env:HOME_TOKEN; ci: C=cmd:pass show ci/token, D=file:~/.token"
+	profiles, err := types.ParseEnvProfiles(raw)
+	if err != nil {
+		t.Fatalf("ParseEnvProfiles: %v", err)
+	}
+	if len(profiles) != 2 || profiles[1].Vars[0].Source != types.EnvSourceCommand || profiles[1].Vars[0].Value != "pass show ci/token" {
+		t.Fatalf("unexpected profiles %#v", profiles)
+	}
+	if got := types.FormatEnvProfiles(profiles); got != raw {
+		t.Fatalf("expected round trip %q, got %q", raw, got)
+	}
+	if _, err := types.ParseEnvProfiles("dev: A=1; dev: B=2"); err == nil {
+		t.Fatalf("expected duplicate profile to be rejected")
+	}
+	if got := stripRedactedEnv([]string{"A=1", "B=" + types.RedactedEnvValue}); strings.Join(got, ",") != "A=1" {
+		t.Fatalf("unexpected stripped env %q", got)
+	}
+}
+
+func TestCodexLiveManagerAppliesWorkspaceEnvProfile(t *testing.T) {
+	ctx := context.Background()
+	base := t.TempDir()
+	home := filepath.Join(base, "home")
+	if err := os.MkdirAll(filepath.Join(home, ".archon"), 0o700); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	t.Setenv("HOME", home)
+	out := filepath.Join(base, "env.txt")
+	script := filepath.Join(base, "codex")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nprintf '%s' \"$MODE\" > '"+out+"'\n"), 0o755); err != nil {
+		t.Fatalf("write fake codex: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".archon", "config.toml"), []byte("[providers.codex]\ncommand = \""+script+"\"\n"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	stores := newTestStores(t)
+	repo := filepath.Join(base, "repo")
+	if err := ensureDir(repo); err != nil {
+		t.Fatalf("ensureDir: %v", err)
+	}
+	profiles, err := types.ParseEnvProfiles("ci: MODE=from-profile")
+	if err != nil {
+		t.Fatalf("ParseEnvProfiles: %v", err)
+	}
+	ws, err := stores.Workspaces.Add(ctx, &types.Workspace{RepoPath: repo, EnvProfiles: profiles, EnvProfile: "ci"})
+	if err != nil {
+		t.Fatalf("Add workspace: %v", err)
+	}
+	meta := &types.SessionMeta{SessionID: "s1", WorkspaceID: ws.ID}
+	if _, err := stores.SessionMeta.Upsert(ctx, meta); err != nil {
+		t.Fatalf("Upsert meta: %v", err)
+	}
+
+	manager := NewCodexLiveManager(stores, nil)
+	manager.SetEnvResolver(newWorkspaceEnvResolver(stores))
+	// The fake app server exits right away, so starting the session fails
+	// after the process has seen its environment.
+	if _, err := manager.ensure(ctx, &types.Session{ID: "s1", Provider: "codex", Cwd: repo}, meta, "", false); err == nil {
+		t.Fatalf("expected the fake app server to fail initialization")
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read env: %v", err)
+	}
+	if got := string(data); got != "from-profile" {
+		t.Fatalf("expected the app server to get the env profile, got %q", got)
+	}
+}
@@ -97,7 +97,12 @@ func (r daemonFileSearchRootResolver) ResolveRoots(ctx context.Context, scope ty
 		return roots, nil
 	}
 
-	dirs, err := workspacepaths.ResolveAdditionalDirectories(primary, context.workspace.AdditionalDirectories, r.checker)
+	var dirs []string
+	if context.worktree != nil && len(context.worktree.LinkedWorktrees) > 0 {
+		dirs, err = linkedAdditionalDirectories(context.workspace, context.worktree)
+	} else {
+		dirs, err = workspacepaths.ResolveAdditionalDirectories(primary, context.workspace.AdditionalDirectories, r.checker)
+	}
 	if err != nil {
 		return nil, invalidError(err.Error(), err)
 	}
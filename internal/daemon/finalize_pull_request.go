@@ -0,0 +1,125 @@
+package daemon
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"control/internal/guidedworkflows"
+)
+
+const (
+	finalizePullRequestSummaryRunes = 3000
+	finalizePullRequestTimeline     = 30
+)
+
+// renderFinalizePullRequest renders a Markdown pull request description from
+// the commit message, the task, the changed files, the run's steps and
+// timeline, and the agent's final summary. commit may be empty for drafts.
+func renderFinalizePullRequest(subject *finalizeSubject, message, commit string) string {
+	title, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", strings.TrimSpace(title))
+	if body = strings.TrimSpace(body); body != "" {
+		fmt.Fprintf(&b, "\n%s\n", body)
+	}
+
+	if task := finalizeTask(subject); task != "" {
+		b.WriteString("\n## Task\n\n")
+		for _, line := range strings.Split(task, "\n") {
+			b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+		}
+	}
+
+	diff := subject.diff
+	b.WriteString("\n## Changes\n\n")
+	b.WriteString("| File | Status | + | - |\n")
+	b.WriteString("| --- | --- | ---: | ---: |\n")
+	for _, file := range diff.Files {
+		name := "`" + file.Path + "`"
+		if file.OldPath != "" && file.OldPath != file.Path {
+			name = "`" + file.OldPath + "` → " + name
+		}
+		fmt.Fprintf(&b, "| %s | %s | %d | %d |\n", name, file.Status, file.Additions, file.Deletions)
+	}
+	for _, path := range diff.Untracked {
+		fmt.Fprintf(&b, "| `%s` | added | | |\n", path)
+	}
+	fmt.Fprintf(&b, "\n%d file(s) changed, +%d -%d", diff.Stats.Files+diff.Stats.Untracked, diff.Stats.Additions, diff.Stats.Deletions)
+	if diff.Stats.Untracked > 0 {
+		fmt.Fprintf(&b, " (%d new file(s) not counted)", diff.Stats.Untracked)
+	}
+	b.WriteString("\n")
+
+	if subject.run != nil {
+		renderFinalizeWorkflowRun(&b, subject.run, subject.timeline)
+	}
+
+	if summary := finalizeAgentSummary(subject); summary != "" {
+		b.WriteString("\n## Agent Summary\n\n")
+		b.WriteString(truncateRunes(summary, finalizePullRequestSummaryRunes) + "\n")
+	}
+
+	details := make([]string, 0, 3)
+	if subject.branch != "" {
+		details = append(details, "Branch `"+subject.branch+"`")
+	}
+	if commit != "" {
+		details = append(details, "Commit `"+shortCommit(commit)+"`")
+	}
+	details = append(details, "Session `"+subject.session.ID+"`")
+	fmt.Fprintf(&b, "\n---\n\n%s\n", strings.Join(details, " · "))
+	return b.String()
+}
+
+func renderFinalizeWorkflowRun(b *strings.Builder, run *guidedworkflows.WorkflowRun, timeline []guidedworkflows.RunTimelineEvent) {
+	b.WriteString("\n## Workflow\n\n")
+	name := strings.TrimSpace(run.TemplateName)
+	if name == "" {
+		name = run.TemplateID
+	}
+	fmt.Fprintf(b, "%s (`%s`, %s)\n\n", name, run.ID, run.Status)
+	for _, phase := range run.Phases {
+		fmt.Fprintf(b, "- %s %s\n", finalizeStatusMark(string(phase.Status)), phase.Name)
+		for _, step := range phase.Steps {
+			fmt.Fprintf(b, "  - %s %s\n", finalizeStatusMark(string(step.Status)), step.Name)
+		}
+	}
+	if len(timeline) == 0 {
+		return
+	}
+	b.WriteString("\n<details><summary>Timeline</summary>\n\n")
+	start := 0
+	if len(timeline) > finalizePullRequestTimeline {
+		start = len(timeline) - finalizePullRequestTimeline
+		fmt.Fprintf(b, "- … %d earlier event(s)\n", start)
+	}
+	for _, event := range timeline[start:] {
+		line := fmt.Sprintf("- %s `%s`", event.At.UTC().Format(time.RFC3339), event.Type)
+		if message := strings.TrimSpace(event.Message); message != "" {
+			line += " " + firstLine(message)
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("\n</details>\n")
+}
+
+func finalizeStatusMark(status string) string {
+	switch status {
+	case string(guidedworkflows.StepRunStatusCompleted):
+		return "[x]"
+	case string(guidedworkflows.StepRunStatusFailed):
+		return "[!]"
+	case string(guidedworkflows.StepRunStatusStopped):
+		return "[-]"
+	default:
+		return "[ ]"
+	}
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
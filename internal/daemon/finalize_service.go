@@ -0,0 +1,498 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"control/internal/daemon/transcriptdomain"
+	"control/internal/guidedworkflows"
+	"control/internal/logging"
+	"control/internal/types"
+)
+
+const (
+	finalizeTranscriptLines       = 400
+	finalizeCommitMessageTimeout  = 20 * time.Second
+	finalizeSessionMessageTimeout = 90 * time.Second
+	finalizePromptDiffLines       = 300
+	finalizeCommitBodyFiles       = 20
+	finalizeCommitSubjectMaxRunes = 72
+)
+
+// CommitMessageGenerator drafts commit messages. The configured title
+// generation provider implements it.
+type CommitMessageGenerator interface {
+	GenerateCommitMessage(ctx context.Context, prompt string) (string, error)
+}
+
+// SessionCommitMessageGenerator drafts commit messages with the provider of
+// the session being finalized, run in dir. It is used when no commit message
+// generator is configured or it fails.
+type SessionCommitMessageGenerator interface {
+	GenerateSessionCommitMessage(ctx context.Context, session *types.Session, dir, prompt string) (string, error)
+}
+
+// finalizeSubject is everything a finalize draft is built from.
+type finalizeSubject struct {
+	session    *types.Session
+	run        *guidedworkflows.WorkflowRun
+	timeline   []guidedworkflows.RunTimelineEvent
+	branch     string
+	diff       *types.GitDiff
+	transcript []transcriptdomain.Block
+}
+
+// FinalizeDraft gathers the pending changes of a session and drafts a commit
+// message and pull request description for them.
+func (s *SessionService) FinalizeDraft(ctx context.Context, id string) (*types.FinalizeDraft, error) {
+	subject, err := s.loadFinalizeSubject(ctx, id, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.draftFinalize(ctx, subject), nil
+}
+
+// FinalizeRunDraft is FinalizeDraft for the session of a workflow run; the
+// pull request description also covers the run's steps and timeline.
+func (s *SessionService) FinalizeRunDraft(ctx context.Context, run *guidedworkflows.WorkflowRun, timeline []guidedworkflows.RunTimelineEvent) (*types.FinalizeDraft, error) {
+	subject, err := s.loadFinalizeRunSubject(ctx, run, timeline)
+	if err != nil {
+		return nil, err
+	}
+	return s.draftFinalize(ctx, subject), nil
+}
+
+// Finalize commits the pending changes of a session on its current branch.
+func (s *SessionService) Finalize(ctx context.Context, id string, req types.FinalizeRequest) (*types.FinalizeResult, error) {
+	subject, err := s.loadFinalizeSubject(ctx, id, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.commitFinalize(ctx, subject, req)
+}
+
+// FinalizeRun commits the pending changes of a workflow run's session.
+func (s *SessionService) FinalizeRun(ctx context.Context, run *guidedworkflows.WorkflowRun, timeline []guidedworkflows.RunTimelineEvent, req types.FinalizeRequest) (*types.FinalizeResult, error) {
+	subject, err := s.loadFinalizeRunSubject(ctx, run, timeline)
+	if err != nil {
+		return nil, err
+	}
+	return s.commitFinalize(ctx, subject, req)
+}
+
+func (s *SessionService) loadFinalizeRunSubject(ctx context.Context, run *guidedworkflows.WorkflowRun, timeline []guidedworkflows.RunTimelineEvent) (*finalizeSubject, error) {
+	if run == nil {
+		return nil, notFoundError("workflow run not found", nil)
+	}
+	if strings.TrimSpace(run.SessionID) == "" {
+		return nil, invalidError("workflow run has no session", nil)
+	}
+	return s.loadFinalizeSubject(ctx, run.SessionID, run, timeline)
+}
+
+func (s *SessionService) loadFinalizeSubject(ctx context.Context, id string, run *guidedworkflows.WorkflowRun, timeline []guidedworkflows.RunTimelineEvent) (*finalizeSubject, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, invalidError("session id is required", nil)
+	}
+	session, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	cwd, err := s.sessionWorkingDir(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := readLinkedGitDiff(ctx, cwd, "", s.sessionLinkedRoots(ctx, session.ID))
+	if err != nil {
+		return nil, invalidError(err.Error(), err)
+	}
+	if len(diff.Files) == 0 && len(diff.Untracked) == 0 {
+		return nil, invalidError(errGitNothingToCommit.Error(), errGitNothingToCommit)
+	}
+	subject := &finalizeSubject{
+		session:  session,
+		run:      run,
+		timeline: timeline,
+		diff:     diff,
+	}
+	subject.branch, _ = gitCurrentBranch(ctx, diff.Root)
+	snapshot, err := s.GetTranscriptSnapshot(ctx, session.ID, finalizeTranscriptLines)
+	if err != nil {
+		if s.logger != nil && s.logger.Enabled(logging.Debug) {
+			s.logger.Debug("finalize_transcript_unavailable",
+				logging.F("session_id", session.ID),
+				logging.F("error", err),
+			)
+		}
+	} else {
+		subject.transcript = snapshot.Blocks
+	}
+	return subject, nil
+}
+
+func (s *SessionService) draftFinalize(ctx context.Context, subject *finalizeSubject) *types.FinalizeDraft {
+	draft := &types.FinalizeDraft{
+		SessionID: subject.session.ID,
+		Root:      subject.diff.Root,
+		Branch:    subject.branch,
+		Diff:      subject.diff,
+	}
+	if subject.run != nil {
+		draft.RunID = subject.run.ID
+	}
+	draft.Message, draft.MessageSource, draft.MessageError = s.draftCommitMessage(ctx, subject)
+	draft.PullRequest = renderFinalizePullRequest(subject, draft.Message, "")
+	return draft
+}
+
+// draftCommitMessage asks the commit message provider for a draft, then the
+// session's own provider, and falls back to one summarized from the task and
+// the changed files. The returned error text is that of the last provider
+// that failed.
+func (s *SessionService) draftCommitMessage(ctx context.Context, subject *finalizeSubject) (string, string, string) {
+	prompt := commitMessagePrompt(subject)
+	failure := ""
+	if s.commitMessages != nil {
+		message, err := generateCommitMessage(ctx, finalizeCommitMessageTimeout, func(ctx context.Context) (string, error) {
+			return s.commitMessages.GenerateCommitMessage(ctx, prompt)
+		})
+		if err == nil {
+			return message, types.FinalizeMessageSourceProvider, ""
+		}
+		s.logCommitMessageFailure(subject, "title_provider", err)
+		failure = err.Error()
+	}
+	if s.sessionCommitMessages != nil {
+		message, err := generateCommitMessage(ctx, finalizeSessionMessageTimeout, func(ctx context.Context) (string, error) {
+			return s.sessionCommitMessages.GenerateSessionCommitMessage(ctx, subject.session, subject.diff.Root, prompt)
+		})
+		if err == nil {
+			return message, types.FinalizeMessageSourceSession, ""
+		}
+		s.logCommitMessageFailure(subject, "session_provider", err)
+		failure = err.Error()
+	}
+	return summaryCommitMessage(subject), types.FinalizeMessageSourceSummary, failure
+}
+
+func generateCommitMessage(ctx context.Context, timeout time.Duration, generate func(context.Context) (string, error)) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	message, err := generate(ctx)
+	if err != nil {
+		return "", err
+	}
+	message = trimCommitMessageFences(message)
+	if message == "" {
+		return "", errors.New("provider returned an empty commit message")
+	}
+	return message, nil
+}
+
+func (s *SessionService) logCommitMessageFailure(subject *finalizeSubject, source string, err error) {
+	if s.logger == nil {
+		return
+	}
+	s.logger.Warn("commit_message_generation_failed",
+		logging.F("session_id", subject.session.ID),
+		logging.F("source", source),
+		logging.F("error", err),
+	)
+}
+
+func (s *SessionService) commitFinalize(ctx context.Context, subject *finalizeSubject, req types.FinalizeRequest) (*types.FinalizeResult, error) {
+	message := strings.TrimSpace(req.Message)
+	if message == "" {
+		message, _, _ = s.draftCommitMessage(ctx, subject)
+	}
+	commit, files, err := commitGitWorkingTree(ctx, subject.diff.Root, message)
+	if err != nil && !(errors.Is(err, errGitNothingToCommit) && len(subject.diff.Linked) > 0) {
+		return nil, invalidError(err.Error(), err)
+	}
+	linked, linkedErr := commitLinkedFinalize(ctx, subject.diff.Linked, message)
+	if commit == "" && !finalizeCommittedLinked(linked) {
+		// Nothing was committed anywhere, so there is no partial result to
+		// report.
+		if linkedErr != nil {
+			return nil, invalidError(linkedErr.Error(), linkedErr)
+		}
+		return nil, invalidError(errGitNothingToCommit.Error(), errGitNothingToCommit)
+	}
+	result := &types.FinalizeResult{
+		SessionID: subject.session.ID,
+		Root:      subject.diff.Root,
+		Branch:    subject.branch,
+		Commit:    commit,
+		Message:   message,
+		Files:     files,
+		Linked:    linked,
+	}
+	if subject.run != nil {
+		result.RunID = subject.run.ID
+	}
+	if req.PullRequest {
+		result.PullRequest = renderFinalizePullRequest(subject, message, commit)
+	}
+	if linkedErr != nil && s.logger != nil {
+		s.logger.Warn("session_finalize_linked_failed",
+			logging.F("session_id", result.SessionID),
+			logging.F("error", linkedErr),
+		)
+	}
+	if s.logger != nil {
+		s.logger.Info("session_finalized",
+			logging.F("session_id", result.SessionID),
+			logging.F("run_id", result.RunID),
+			logging.F("commit", commit),
+			logging.F("files", len(files)),
+			logging.F("linked", len(linked)),
+		)
+	}
+	return result, nil
+}
+
+// commitLinkedFinalize commits the pending changes of each linked repo with
+// message, skipping repos without changes. A repo that fails is reported on
+// its entry and the others are still committed; the returned error joins the
+// failures.
+func commitLinkedFinalize(ctx context.Context, repos []types.GitDiffRepo, message string) ([]types.FinalizeLinkedCommit, error) {
+	var (
+		out  []types.FinalizeLinkedCommit
+		errs error
+	)
+	for _, repo := range repos {
+		commit, files, err := commitGitWorkingTree(ctx, repo.Root, message)
+		if errors.Is(err, errGitNothingToCommit) {
+			continue
+		}
+		branch, _ := gitCurrentBranch(ctx, repo.Root)
+		entry := types.FinalizeLinkedCommit{Name: repo.Name, Root: repo.Root, Branch: branch, Commit: commit, Files: files}
+		if err != nil {
+			entry.Error = err.Error()
+			errs = errors.Join(errs, fmt.Errorf("%s: %w", repo.Name, err))
+		}
+		out = append(out, entry)
+	}
+	return out, errs
+}
+
+func finalizeCommittedLinked(linked []types.FinalizeLinkedCommit) bool {
+	for _, entry := range linked {
+		if entry.Commit != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// finalizeTask is the best available statement of what the work was for.
+func finalizeTask(subject *finalizeSubject) string {
+	if subject.run != nil {
+		if prompt := strings.TrimSpace(subject.run.DisplayUserPrompt); prompt != "" {
+			return prompt
+		}
+		if prompt := strings.TrimSpace(subject.run.UserPrompt); prompt != "" {
+			return prompt
+		}
+	}
+	for _, block := range subject.transcript {
+		if block.Role == "user" && strings.TrimSpace(block.Text) != "" {
+			return strings.TrimSpace(block.Text)
+		}
+	}
+	return strings.TrimSpace(subject.session.Title)
+}
+
+// finalizeAgentSummary is the last assistant message of the transcript.
+func finalizeAgentSummary(subject *finalizeSubject) string {
+	for i := len(subject.transcript) - 1; i >= 0; i-- {
+		block := subject.transcript[i]
+		if block.Role == "assistant" && strings.TrimSpace(block.Text) != "" {
+			return strings.TrimSpace(block.Text)
+		}
+	}
+	return ""
+}
+
+func finalizeChangedPaths(diff *types.GitDiff) []string {
+	paths := make([]string, 0, len(diff.Files)+len(diff.Untracked))
+	for _, file := range diff.Files {
+		paths = append(paths, file.Path)
+	}
+	return append(paths, diff.Untracked...)
+}
+
+func commitMessagePrompt(subject *finalizeSubject) string {
+	var b strings.Builder
+	if task := finalizeTask(subject); task != "" {
+		fmt.Fprintf(&b, "Task:\n%s\n\n", truncateRunes(task, 2000))
+	}
+	b.WriteString("Changed files:\n")
+	for _, file := range subject.diff.Files {
+		fmt.Fprintf(&b, "- %s %s (+%d -%d)\n", file.Status, file.Path, file.Additions, file.Deletions)
+	}
+	for _, path := range subject.diff.Untracked {
+		fmt.Fprintf(&b, "- added %s\n", path)
+	}
+	b.WriteString("\nDiff excerpt:\n")
+	lines := 0
+excerpt:
+	for _, file := range subject.diff.Files {
+		fmt.Fprintf(&b, "--- %s\n", file.Path)
+		for _, hunk := range file.Hunks {
+			b.WriteString(hunk.Header + "\n")
+			for _, line := range hunk.Lines {
+				if lines >= finalizePromptDiffLines {
+					b.WriteString("...\n")
+					break excerpt
+				}
+				prefix := " "
+				switch line.Kind {
+				case types.GitDiffLineAdded:
+					prefix = "+"
+				case types.GitDiffLineDeleted:
+					prefix = "-"
+				}
+				b.WriteString(prefix + line.Text + "\n")
+				lines++
+			}
+		}
+	}
+	if summary := finalizeAgentSummary(subject); summary != "" {
+		fmt.Fprintf(&b, "\nAgent summary:\n%s\n", truncateRunes(summary, 2000))
+	}
+	return b.String()
+}
+
+// summaryCommitMessage drafts a Conventional Commit message without a
+// provider: the type is inferred from the task and paths, the subject is the
+// first line of the task and the body lists the changed files.
+func summaryCommitMessage(subject *finalizeSubject) string {
+	paths := finalizeChangedPaths(subject.diff)
+	task := firstLine(finalizeTask(subject))
+	summary := strings.TrimRight(task, ".!:; ")
+	if summary == "" {
+		summary = fmt.Sprintf("update %d file(s)", len(paths))
+	}
+	header := conventionalCommitType(task, subject.diff) + ": "
+	summary = truncateRunes(lowerFirst(summary), finalizeCommitSubjectMaxRunes-len(header))
+
+	var b strings.Builder
+	b.WriteString(header + summary + "\n")
+	b.WriteString("\n")
+	for i, file := range subject.diff.Files {
+		if i == finalizeCommitBodyFiles {
+			break
+		}
+		fmt.Fprintf(&b, "- %s %s\n", file.Status, file.Path)
+	}
+	for i, path := range subject.diff.Untracked {
+		if len(subject.diff.Files)+i >= finalizeCommitBodyFiles {
+			break
+		}
+		fmt.Fprintf(&b, "- added %s\n", path)
+	}
+	if len(paths) > finalizeCommitBodyFiles {
+		fmt.Fprintf(&b, "- and %d more\n", len(paths)-finalizeCommitBodyFiles)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func conventionalCommitType(task string, diff *types.GitDiff) string {
+	paths := finalizeChangedPaths(diff)
+	if len(paths) > 0 && allPaths(paths, isDocsPath) {
+		return "docs"
+	}
+	if len(paths) > 0 && allPaths(paths, isTestPath) {
+		return "test"
+	}
+	lower := strings.ToLower(task)
+	switch {
+	case strings.Contains(lower, "fix") || strings.Contains(lower, "bug"):
+		return "fix"
+	case strings.Contains(lower, "refactor"):
+		return "refactor"
+	}
+	if len(diff.Untracked) > 0 {
+		return "feat"
+	}
+	for _, file := range diff.Files {
+		if file.Status == types.GitDiffFileAdded {
+			return "feat"
+		}
+	}
+	return "chore"
+}
+
+func allPaths(paths []string, match func(string) bool) bool {
+	for _, p := range paths {
+		if !match(p) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDocsPath(p string) bool {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".md", ".mdx", ".rst", ".txt", ".adoc":
+		return true
+	}
+	return strings.HasPrefix(p, "docs/")
+}
+
+func isTestPath(p string) bool {
+	base := path.Base(p)
+	return strings.HasSuffix(base, "_test.go") ||
+		strings.Contains(base, ".test.") ||
+		strings.Contains(base, ".spec.") ||
+		strings.HasPrefix(base, "test_") ||
+		strings.HasPrefix(p, "test/") || strings.HasPrefix(p, "tests/") ||
+		strings.Contains(p, "/test/") || strings.Contains(p, "/tests/")
+}
+
+// trimCommitMessageFences strips a Markdown code fence around a provider
+// reply.
+func trimCommitMessageFences(message string) string {
+	message = strings.TrimSpace(message)
+	if !strings.HasPrefix(message, "```") {
+		return message
+	}
+	message = strings.TrimPrefix(message, "```")
+	if newline := strings.Index(message, "\n"); newline >= 0 {
+		message = message[newline+1:]
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(message), "```"))
+}
+
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(line)
+}
+
+func lowerFirst(text string) string {
+	runes := []rune(text)
+	if len(runes) > 1 && strings.ToUpper(string(runes[1])) == string(runes[1]) {
+		// Keep acronyms such as "API" intact.
+		return text
+	}
+	if len(runes) > 0 {
+		runes[0] = []rune(strings.ToLower(string(runes[0])))[0]
+	}
+	return string(runes)
+}
+
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if limit <= 0 || len(runes) <= limit {
+		return text
+	}
+	if limit <= 3 {
+		return string(runes[:limit])
+	}
+	return strings.TrimSpace(string(runes[:limit-3])) + "..."
+}
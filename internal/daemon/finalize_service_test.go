@@ -0,0 +1,218 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"control/internal/types"
+)
+
+type stubCommitMessageGenerator struct {
+	prompts []string
+	message string
+}
+
+func (g *stubCommitMessageGenerator) GenerateCommitMessage(_ context.Context, prompt string) (string, error) {
+	g.prompts = append(g.prompts, prompt)
+	return g.message, nil
+}
+
+type stubSessionCommitMessageGenerator struct {
+	sessions []string
+	dirs     []string
+	message  string
+	err      error
+}
+
+func (g *stubSessionCommitMessageGenerator) GenerateSessionCommitMessage(_ context.Context, session *types.Session, dir, _ string) (string, error) {
+	g.sessions = append(g.sessions, session.ID)
+	g.dirs = append(g.dirs, dir)
+	return g.message, g.err
+}
+
+type failingCommitMessageGenerator struct{}
+
+func (failingCommitMessageGenerator) GenerateCommitMessage(context.Context, string) (string, error) {
+	return "", errors.New("title provider unavailable")
+}
+
+func TestDraftCommitMessageFallsBackToSessionProvider(t *testing.T) {
+	ctx := context.Background()
+	subject := &finalizeSubject{
+		session: &types.Session{ID: "s1", Provider: "claude", Title: "Support comments"},
+		diff: &types.GitDiff{Root: "/repo", Files: []types.GitDiffFile{
+			{Path: "parser.go", Status: types.GitDiffFileModified},
+		}},
+	}
+
+	generator := &stubSessionCommitMessageGenerator{message: "feat(parser): support comments\n"}
+	sessions := NewSessionService(nil, &Stores{}, nil, WithSessionCommitMessageGenerator(generator))
+	message, source, failure := sessions.draftCommitMessage(ctx, subject)
+	if message != "feat(parser): support comments" || source != types.FinalizeMessageSourceSession || failure != "" {
+		t.Fatalf("expected the session provider draft, got %q (%s, %q)", message, source, failure)
+	}
+	if len(generator.sessions) != 1 || generator.sessions[0] != "s1" || generator.dirs[0] != "/repo" {
+		t.Fatalf("expected the session provider to run in the repo root, got %#v", generator)
+	}
+
+	sessions = NewSessionService(nil, &Stores{}, nil,
+		WithCommitMessageGenerator(failingCommitMessageGenerator{}),
+		WithSessionCommitMessageGenerator(generator),
+	)
+	if _, source, _ := sessions.draftCommitMessage(ctx, subject); source != types.FinalizeMessageSourceSession {
+		t.Fatalf("expected a failed title provider to fall back to the session provider, got %s", source)
+	}
+
+	generator.message, generator.err = "", errors.New("claude: exit status 1")
+	message, source, failure = sessions.draftCommitMessage(ctx, subject)
+	if source != types.FinalizeMessageSourceSummary || failure != "claude: exit status 1" || !strings.HasPrefix(message, "chore: support comments") {
+		t.Fatalf("expected the summary fallback, got %q (%s, %q)", message, source, failure)
+	}
+}
+
+func TestSessionFinalizeCommitsEditedMessage(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+	repo := t.TempDir()
+	runTestGit(t, repo, "init", "-q", "-b", "main")
+	runTestGit(t, repo, "config", "user.name", "Test")
+	runTestGit(t, repo, "config", "user.email", "test@example.com")
+	runTestGit(t, repo, "config", "commit.gpgsign", "false")
+	writeTestFile(t, filepath.Join(repo, "parser.go"), "package parser\n")
+	runTestGit(t, repo, "add", ".")
+	runTestGit(t, repo, "commit", "-q", "-m", "init")
+
+	manager := newTestManager(t)
+	generator := &stubCommitMessageGenerator{message: "```\nfeat(parser): support comments\n\nSkip comment lines.\n```"}
+	sessions := NewSessionService(manager, &Stores{}, nil, WithCommitMessageGenerator(generator))
+	session, err := sessions.Start(ctx, StartSessionRequest{
+		Provider: "custom",
+		Cmd:      os.Args[0],
+		Args:     helperArgs("stdout=ok", "exit=0"),
+		Env:      []string{"GO_WANT_HELPER_PROCESS=1"},
+		Cwd:      repo,
+		Title:    "Support comments",
+	})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	waitForStatus(t, manager, session.ID, types.SessionStatusExited, 2*time.Second)
+
+	if _, err := sessions.FinalizeDraft(ctx, session.ID); err == nil || !strings.Contains(err.Error(), "no changes") {
+		t.Fatalf("expected a clean tree to be refused, got %v", err)
+	}
+	writeTestFile(t, filepath.Join(repo, "parser.go"), "package parser\n\n// Comments are skipped.\n")
+	writeTestFile(t, filepath.Join(repo, "comments.go"), "package parser\n")
+
+	draft, err := sessions.FinalizeDraft(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("draft: %v", err)
+	}
+	if draft.MessageSource != types.FinalizeMessageSourceProvider || draft.Message != "feat(parser): support comments\n\nSkip comment lines." {
+		t.Fatalf("unexpected drafted message %q (%s)", draft.Message, draft.MessageSource)
+	}
+	if len(generator.prompts) != 1 || !strings.Contains(generator.prompts[0], "+// Comments are skipped.") || !strings.Contains(generator.prompts[0], "added comments.go") {
+		t.Fatalf("expected the prompt to carry the diff, got %q", generator.prompts)
+	}
+	if draft.Branch != "main" || !strings.Contains(draft.PullRequest, "# feat(parser): support comments") {
+		t.Fatalf("unexpected draft %#v", draft)
+	}
+
+	result, err := sessions.Finalize(ctx, session.ID, types.FinalizeRequest{
+		Message:     "fix(parser): skip comment lines",
+		PullRequest: true,
+	})
+	if err != nil {
+		t.Fatalf("finalize: %v", err)
+	}
+	if strings.Join(result.Files, ",") != "comments.go,parser.go" || result.Commit == "" {
+		t.Fatalf("unexpected result %#v", result)
+	}
+	subject, err := runGitCommand(ctx, repo, nil, "log", "-1", "--format=%s")
+	if err != nil || strings.TrimSpace(subject) != "fix(parser): skip comment lines" {
+		t.Fatalf("expected edited message to be committed, got %q (%v)", subject, err)
+	}
+	for _, want := range []string{"# fix(parser): skip comment lines", "| `comments.go` | added |", "Commit `" + result.Commit[:12] + "`"} {
+		if !strings.Contains(result.PullRequest, want) {
+			t.Fatalf("expected %q in pull request:\n%s", want, result.PullRequest)
+		}
+	}
+}
+
+func TestSessionFinalizeReportsLinkedCommitFailure(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+	base := t.TempDir()
+	roots := map[string]string{}
+	for _, name := range []string{"app", "broken", "lib"} {
+		repo := filepath.Join(base, name)
+		runTestGit(t, base, "init", "-q", "-b", "main", repo)
+		runTestGit(t, repo, "config", "user.name", "Test")
+		runTestGit(t, repo, "config", "user.email", "test@example.com")
+		runTestGit(t, repo, "config", "commit.gpgsign", "false")
+		writeTestFile(t, filepath.Join(repo, "README.md"), "init\n")
+		runTestGit(t, repo, "add", ".")
+		runTestGit(t, repo, "commit", "-q", "-m", "init")
+		writeTestFile(t, filepath.Join(repo, "README.md"), "changed\n")
+		roots[name] = repo
+	}
+	hook := filepath.Join(roots["broken"], ".git", "hooks", "pre-commit")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\necho rejected >&2\nexit 1\n"), 0o755); err != nil {
+		t.Fatalf("write hook: %v", err)
+	}
+	subject := &finalizeSubject{
+		session: &types.Session{ID: "s1", Title: "Update readmes"},
+		branch:  "main",
+		diff: &types.GitDiff{Root: roots["app"], Linked: []types.GitDiffRepo{
+			{Name: "broken", Root: roots["broken"]},
+			{Name: "lib", Root: roots["lib"]},
+		}},
+	}
+
+	sessions := NewSessionService(nil, &Stores{}, nil)
+	result, err := sessions.commitFinalize(ctx, subject, types.FinalizeRequest{Message: "docs: update readmes"})
+	if err != nil {
+		t.Fatalf("expected the primary commit to be reported, got %v", err)
+	}
+	if result.Commit == "" || len(result.Linked) != 2 {
+		t.Fatalf("expected the primary commit and both linked results, got %#v", result)
+	}
+	if broken := result.Linked[0]; broken.Commit != "" || !strings.Contains(broken.Error, "rejected") {
+		t.Fatalf("expected the hook failure on the broken repo, got %#v", broken)
+	}
+	if lib := result.Linked[1]; lib.Commit == "" || lib.Error != "" {
+		t.Fatalf("expected the other linked repo to be committed, got %#v", lib)
+	}
+	if staged, _ := runGitCommand(ctx, roots["broken"], nil, "diff", "--cached", "--name-only"); staged != "" {
+		t.Fatalf("expected the failed commit to leave the index alone, got %q", staged)
+	}
+	if status, _ := runGitCommand(ctx, roots["lib"], nil, "status", "--porcelain"); status != "" {
+		t.Fatalf("expected the index to match the new commit, got %q", status)
+	}
+}
+
+func TestSummaryCommitMessageInfersType(t *testing.T) {
+	subject := &finalizeSubject{
+		session: &types.Session{ID: "s1", Title: "Fix crash when the config is empty."},
+		diff: &types.GitDiff{Files: []types.GitDiffFile{
+			{Path: "internal/config/load.go", Status: types.GitDiffFileModified},
+		}},
+	}
+	if got := summaryCommitMessage(subject); got != "fix: fix crash when the config is empty\n\n- modified internal/config/load.go" {
+		t.Fatalf("unexpected message %q", got)
+	}
+	subject.diff = &types.GitDiff{Untracked: []string{"docs/guide.md"}}
+	if got := firstLine(summaryCommitMessage(subject)); got != "docs: fix crash when the config is empty" {
+		t.Fatalf("expected docs type, got %q", got)
+	}
+}
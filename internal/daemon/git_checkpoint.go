@@ -0,0 +1,294 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"control/internal/types"
+)
+
+const (
+	gitCheckpointRefPrefix   = "refs/archon/checkpoints/"
+	gitCheckpointTurnSubject = "checkpoint turn "
+	gitCheckpointTimeout     = 30 * time.Second
+	// gitCheckpointRetention is how many turn checkpoints a session keeps;
+	// older ones are dropped as new turns are checkpointed.
+	gitCheckpointRetention = 50
+	// gitCheckpointBackupRetention is how many labelled checkpoints (the
+	// trees saved before a restore) a session keeps. They are capped on
+	// their own so restores never push turn checkpoints out.
+	gitCheckpointBackupRetention = 10
+)
+
+// gitCheckpointTree is a working tree snapshot that has been written to the
+// object database but not yet committed under a checkpoint ref.
+type gitCheckpointTree struct {
+	root   string
+	tree   string
+	parent string
+}
+
+// snapshotGitTree writes the working tree of the repository containing dir
+// (tracked and untracked, non-ignored files) to a tree object. It stages
+// into a temporary copy of the index so the user's index is left alone.
+func snapshotGitTree(ctx context.Context, dir string) (*gitCheckpointTree, error) {
+	ctx, cancel := context.WithTimeout(ctx, gitCheckpointTimeout)
+	defer cancel()
+	root, err := runGitCommand(ctx, dir, nil, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	indexFile, cleanup, err := gitTempIndex(ctx, root, true)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	env := []string{"GIT_INDEX_FILE=" + indexFile}
+	if _, err := runGitCommand(ctx, root, env, "add", "-A", "--", "."); err != nil {
+		return nil, err
+	}
+	tree, err := runGitCommand(ctx, root, env, "write-tree")
+	if err != nil {
+		return nil, err
+	}
+	parent, _ := runGitCommand(ctx, root, nil, "rev-parse", "--verify", "--quiet", "HEAD^{commit}")
+	return &gitCheckpointTree{root: root, tree: tree, parent: parent}, nil
+}
+
+// commitGitCheckpoint records snapshot as a hidden commit under
+// refs/archon/checkpoints/<sessionID>/<id> and returns the checkpoint.
+func commitGitCheckpoint(ctx context.Context, snapshot *gitCheckpointTree, sessionID, subject string) (*types.SessionCheckpoint, error) {
+	if snapshot == nil {
+		return nil, fmt.Errorf("snapshot is required")
+	}
+	ctx, cancel := context.WithTimeout(ctx, gitCheckpointTimeout)
+	defer cancel()
+	args := []string{"commit-tree", snapshot.tree, "-m", subject}
+	if snapshot.parent != "" {
+		args = append(args, "-p", snapshot.parent)
+	}
+	commit, err := runGitCommand(ctx, snapshot.root, gitCheckpointIdentityEnv(), args...)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	id := strconv.FormatInt(now.UnixNano(), 10)
+	if _, err := runGitCommand(ctx, snapshot.root, nil, "update-ref", gitCheckpointRef(sessionID, id), commit); err != nil {
+		return nil, err
+	}
+	checkpoint := &types.SessionCheckpoint{
+		ID:        id,
+		SessionID: sessionID,
+		Commit:    commit,
+		Root:      snapshot.root,
+		CreatedAt: now,
+	}
+	applyGitCheckpointSubject(checkpoint, subject)
+	return checkpoint, nil
+}
+
+// listGitCheckpoints returns the checkpoints of sessionID in the repository
+// containing dir, oldest first, with turn ordinals assigned.
+func listGitCheckpoints(ctx context.Context, dir, sessionID string) ([]*types.SessionCheckpoint, error) {
+	ctx, cancel := context.WithTimeout(ctx, gitCheckpointTimeout)
+	defer cancel()
+	root, err := runGitCommand(ctx, dir, nil, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	output, err := runGitCommand(ctx, root, nil, "for-each-ref", "--sort=refname",
+		"--format=%(refname:lstrip=-1)%09%(objectname)%09%(subject)",
+		gitCheckpointRef(sessionID, ""))
+	if err != nil {
+		return nil, err
+	}
+	checkpoints := []*types.SessionCheckpoint{}
+	turn := 0
+	for _, line := range splitGitOutputLines(output) {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		nanos, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		checkpoint := &types.SessionCheckpoint{
+			ID:        fields[0],
+			SessionID: sessionID,
+			Commit:    fields[1],
+			Root:      root,
+			CreatedAt: time.Unix(0, nanos).UTC(),
+		}
+		applyGitCheckpointSubject(checkpoint, fields[2])
+		if checkpoint.TurnID != "" {
+			turn++
+			checkpoint.Turn = turn
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	return checkpoints, nil
+}
+
+// pruneGitCheckpoints drops all but the newest keepTurns turn checkpoints
+// and the newest keepBackups labelled checkpoints of sessionID in the
+// repository containing dir. The commits themselves are left for git gc.
+func pruneGitCheckpoints(ctx context.Context, dir, sessionID string, keepTurns, keepBackups int) error {
+	ctx, cancel := context.WithTimeout(ctx, gitCheckpointTimeout)
+	defer cancel()
+	output, err := runGitCommand(ctx, dir, nil, "for-each-ref", "--sort=refname",
+		"--format=%(refname)%09%(subject)", gitCheckpointRef(sessionID, ""))
+	if err != nil {
+		return err
+	}
+	var turns, backups []string
+	for _, line := range splitGitOutputLines(output) {
+		ref, subject, _ := strings.Cut(line, "\t")
+		if strings.HasPrefix(subject, gitCheckpointTurnSubject) {
+			turns = append(turns, ref)
+		} else {
+			backups = append(backups, ref)
+		}
+	}
+	var drop []string
+	if len(turns) > keepTurns {
+		drop = append(drop, turns[:len(turns)-keepTurns]...)
+	}
+	if len(backups) > keepBackups {
+		drop = append(drop, backups[:len(backups)-keepBackups]...)
+	}
+	for _, ref := range drop {
+		if _, err := runGitCommand(ctx, dir, nil, "update-ref", "-d", ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreGitCheckpoint makes the working tree at root match commit: files
+// that changed since are rewritten, files created since are deleted. Ignored
+// files, the index and HEAD are not touched.
+func restoreGitCheckpoint(ctx context.Context, root, commit string) ([]string, []string, error) {
+	current, err := snapshotGitTree(ctx, root)
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, gitCheckpointTimeout)
+	defer cancel()
+	output, err := runGitCommand(ctx, root, nil,
+		"diff-tree", "-r", "--no-renames", "--name-status", current.tree, commit+"^{tree}")
+	if err != nil {
+		return nil, nil, err
+	}
+	var reverted, removed []string
+	for _, line := range splitGitOutputLines(output) {
+		status, path, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		if status == "D" {
+			removed = append(removed, path)
+		} else {
+			reverted = append(reverted, path)
+		}
+	}
+	for _, path := range removed {
+		if err := os.Remove(filepath.Join(root, filepath.FromSlash(path))); err != nil && !os.IsNotExist(err) {
+			return nil, nil, err
+		}
+	}
+	if len(reverted) > 0 {
+		indexFile, cleanup, err := gitTempIndex(ctx, root, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer cleanup()
+		env := []string{"GIT_INDEX_FILE=" + indexFile}
+		if _, err := runGitCommand(ctx, root, env, "read-tree", commit+"^{tree}"); err != nil {
+			return nil, nil, err
+		}
+		args := append([]string{"checkout-index", "-f", "--"}, reverted...)
+		if _, err := runGitCommand(ctx, root, env, args...); err != nil {
+			return nil, nil, err
+		}
+	}
+	return reverted, removed, nil
+}
+
+func gitCheckpointRef(sessionID, id string) string {
+	return gitCheckpointRefPrefix + sessionID + "/" + id
+}
+
+func gitCheckpointTurnMessage(turnID string) string {
+	return gitCheckpointTurnSubject + turnID
+}
+
+func applyGitCheckpointSubject(checkpoint *types.SessionCheckpoint, subject string) {
+	if turnID, ok := strings.CutPrefix(subject, gitCheckpointTurnSubject); ok {
+		checkpoint.TurnID = strings.TrimSpace(turnID)
+		return
+	}
+	checkpoint.Label = strings.TrimSpace(subject)
+}
+
+// gitCheckpointIdentityEnv gives checkpoint commits a fixed identity so they
+// work in repositories without user.name/user.email configured.
+func gitCheckpointIdentityEnv() []string {
+	return []string{
+		"GIT_AUTHOR_NAME=archon",
+		"GIT_AUTHOR_EMAIL=archon@localhost",
+		"GIT_COMMITTER_NAME=archon",
+		"GIT_COMMITTER_EMAIL=archon@localhost",
+	}
+}
+
+// gitTempIndex returns the path of a temporary index file, seeded
+// from the repository's index when seed is set so `git add` only rehashes
+// changed files. The returned cleanup removes it.
+func gitTempIndex(ctx context.Context, root string, seed bool) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "archon-index-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+	indexFile := filepath.Join(dir, "index")
+	if !seed {
+		return indexFile, cleanup, nil
+	}
+	indexPath, err := runGitCommand(ctx, root, nil, "rev-parse", "--git-path", "index")
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	if !filepath.IsAbs(indexPath) {
+		indexPath = filepath.Join(root, indexPath)
+	}
+	if err := copyGitCheckpointFile(indexPath, indexFile); err != nil && !os.IsNotExist(err) {
+		cleanup()
+		return "", nil, err
+	}
+	return indexFile, cleanup, nil
+}
+
+func copyGitCheckpointFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,216 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"control/internal/store"
+	"control/internal/types"
+)
+
+func TestGitCheckpointRestoreRevertsWorkingTree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+	repo := t.TempDir()
+	runTestGit(t, repo, "init", "-q", "-b", "main")
+	writeTestFile(t, filepath.Join(repo, "kept.txt"), "original\n")
+	writeTestFile(t, filepath.Join(repo, "deleted.txt"), "doomed\n")
+	writeTestFile(t, filepath.Join(repo, ".gitignore"), "ignored.log\n")
+	runTestGit(t, repo, "add", ".")
+	runTestGit(t, repo, "commit", "-q", "-m", "init")
+	writeTestFile(t, filepath.Join(repo, "draft.txt"), "untracked before turn\n")
+	head, err := runGitCommand(ctx, repo, nil, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatalf("rev-parse: %v", err)
+	}
+
+	snapshot, err := snapshotGitTree(ctx, repo)
+	if err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	first, err := commitGitCheckpoint(ctx, snapshot, "s1", gitCheckpointTurnMessage("turn-a"))
+	if err != nil {
+		t.Fatalf("commit checkpoint: %v", err)
+	}
+
+	writeTestFile(t, filepath.Join(repo, "kept.txt"), "agent rewrite\n")
+	writeTestFile(t, filepath.Join(repo, "draft.txt"), "agent edit\n")
+	writeTestFile(t, filepath.Join(repo, "created.txt"), "new file\n")
+	writeTestFile(t, filepath.Join(repo, "ignored.log"), "log\n")
+	if err := os.Remove(filepath.Join(repo, "deleted.txt")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	runTestGit(t, repo, "add", "created.txt")
+	snapshot, err = snapshotGitTree(ctx, repo)
+	if err != nil {
+		t.Fatalf("second snapshot: %v", err)
+	}
+	if _, err := commitGitCheckpoint(ctx, snapshot, "s1", gitCheckpointTurnMessage("turn-b")); err != nil {
+		t.Fatalf("commit second checkpoint: %v", err)
+	}
+
+	checkpoints, err := listGitCheckpoints(ctx, repo, "s1")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(checkpoints) != 2 || checkpoints[0].ID != first.ID || checkpoints[0].Turn != 1 || checkpoints[1].TurnID != "turn-b" || checkpoints[1].Turn != 2 {
+		t.Fatalf("unexpected checkpoints: %#v", checkpoints)
+	}
+
+	reverted, removed, err := restoreGitCheckpoint(ctx, first.Root, first.Commit)
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if strings.Join(reverted, ",") != "deleted.txt,draft.txt,kept.txt" || strings.Join(removed, ",") != "created.txt" {
+		t.Fatalf("unexpected restore result: reverted=%v removed=%v", reverted, removed)
+	}
+	for path, want := range map[string]string{
+		"kept.txt":    "original\n",
+		"deleted.txt": "doomed\n",
+		"draft.txt":   "untracked before turn\n",
+		"ignored.log": "log\n",
+	} {
+		data, err := os.ReadFile(filepath.Join(repo, path))
+		if err != nil || string(data) != want {
+			t.Fatalf("expected %s to contain %q, got %q (%v)", path, want, data, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(repo, "created.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected created.txt to be removed, got %v", err)
+	}
+	if now, _ := runGitCommand(ctx, repo, nil, "rev-parse", "HEAD"); now != head {
+		t.Fatalf("expected HEAD to stay at %s, got %s", head, now)
+	}
+	staged, _ := runGitCommand(ctx, repo, nil, "diff", "--cached", "--name-only")
+	if staged != "created.txt" {
+		t.Fatalf("expected the user's index to be left alone, got %q", staged)
+	}
+	if others, _ := listGitCheckpoints(ctx, repo, "s2"); len(others) != 0 {
+		t.Fatalf("expected checkpoints to be scoped per session, got %#v", others)
+	}
+}
+
+func TestPruneGitCheckpointsKeepsNewest(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+	repo := t.TempDir()
+	runTestGit(t, repo, "init", "-q", "-b", "main")
+	writeTestFile(t, filepath.Join(repo, "file.txt"), "v0\n")
+	var ids []string
+	for _, turn := range []string{"turn-a", "turn-b", "turn-c"} {
+		snapshot, err := snapshotGitTree(ctx, repo)
+		if err != nil {
+			t.Fatalf("snapshot: %v", err)
+		}
+		checkpoint, err := commitGitCheckpoint(ctx, snapshot, "s1", gitCheckpointTurnMessage(turn))
+		if err != nil {
+			t.Fatalf("commit checkpoint: %v", err)
+		}
+		ids = append(ids, checkpoint.ID)
+	}
+	snapshot, err := snapshotGitTree(ctx, repo)
+	if err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	if _, err := commitGitCheckpoint(ctx, snapshot, "s2", gitCheckpointTurnMessage("turn-x")); err != nil {
+		t.Fatalf("commit other session checkpoint: %v", err)
+	}
+	var backups []string
+	for _, restored := range ids[:2] {
+		backup, err := commitGitCheckpoint(ctx, snapshot, "s1", checkpointRestoreLabelPrefix+restored)
+		if err != nil {
+			t.Fatalf("commit backup checkpoint: %v", err)
+		}
+		backups = append(backups, backup.ID)
+	}
+
+	if err := pruneGitCheckpoints(ctx, repo, "s1", 2, 1); err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	checkpoints, err := listGitCheckpoints(ctx, repo, "s1")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(checkpoints) != 3 || checkpoints[0].ID != ids[1] || checkpoints[1].ID != ids[2] || checkpoints[2].ID != backups[1] {
+		t.Fatalf("expected the two newest turn checkpoints and the newest backup to remain, got %#v", checkpoints)
+	}
+
+	if err := pruneGitCheckpoints(ctx, repo, "s1", 0, 0); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if remaining, _ := listGitCheckpoints(ctx, repo, "s1"); len(remaining) != 0 {
+		t.Fatalf("expected every checkpoint to be deleted, got %#v", remaining)
+	}
+	if others, _ := listGitCheckpoints(ctx, repo, "s2"); len(others) != 1 {
+		t.Fatalf("expected other sessions to keep their checkpoints, got %#v", others)
+	}
+}
+
+func TestRestoreCheckpointRefusesWhileOtherSessionsShareRoot(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+	repo := t.TempDir()
+	runTestGit(t, repo, "init", "-q", "-b", "main")
+	writeTestFile(t, filepath.Join(repo, "file.txt"), "before\n")
+	if err := os.MkdirAll(filepath.Join(repo, "sub"), 0o755); err != nil {
+		t.Fatalf("mkdir sub: %v", err)
+	}
+	stores := newTestStores(t)
+	stores.Sessions = store.NewFileSessionIndexStore(filepath.Join(t.TempDir(), "sessions_index.json"))
+	for id, cwd := range map[string]string{"s1": repo, "s2": filepath.Join(repo, "sub")} {
+		if _, err := stores.Sessions.UpsertRecord(ctx, &types.SessionRecord{
+			Session: &types.Session{ID: id, Provider: "claude", Status: types.SessionStatusRunning, Cwd: cwd, CreatedAt: time.Now().UTC()},
+			Source:  sessionSourceInternal,
+		}); err != nil {
+			t.Fatalf("seed session %s: %v", id, err)
+		}
+	}
+	snapshot, err := snapshotGitTree(ctx, repo)
+	if err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	checkpoint, err := commitGitCheckpoint(ctx, snapshot, "s1", gitCheckpointTurnMessage("turn-a"))
+	if err != nil {
+		t.Fatalf("commit checkpoint: %v", err)
+	}
+	writeTestFile(t, filepath.Join(repo, "file.txt"), "after\n")
+	service := NewSessionService(nil, stores, nil)
+
+	_, err = service.RestoreCheckpoint(ctx, "s1", checkpoint.ID, types.SessionCheckpointRestoreRequest{})
+	var serviceErr *ServiceError
+	if !errors.As(err, &serviceErr) || serviceErr.Kind != ServiceErrorConflict || !strings.Contains(serviceErr.Message, "s2") {
+		t.Fatalf("expected a conflict naming the other session, got %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(repo, "file.txt")); string(data) != "after\n" {
+		t.Fatalf("expected the refused restore to leave the tree alone, got %q", data)
+	}
+
+	result, err := service.RestoreCheckpoint(ctx, "s1", checkpoint.ID, types.SessionCheckpointRestoreRequest{Force: true})
+	if err != nil {
+		t.Fatalf("forced restore: %v", err)
+	}
+	if len(result.Reverted) != 1 || result.Reverted[0] != "file.txt" {
+		t.Fatalf("unexpected forced restore result: %#v", result)
+	}
+}
+
+func TestCheckpointRestoreNoticeDescribesChanges(t *testing.T) {
+	notice := checkpointRestoreNotice(&types.SessionCheckpoint{ID: "1", Turn: 3}, []string{"a.go"}, []string{"b.go"})
+	for _, want := range []string{"start of turn 3", "Restored to their earlier content: a.go.", "Deleted because they did not exist yet: b.go."} {
+		if !strings.Contains(notice, want) {
+			t.Fatalf("expected %q in notice:\n%s", want, notice)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// gitCommandTimeout bounds every git call; it leaves room for hooks and for
+// pushes to slow remotes. Callers with tighter budgets pass a shorter ctx.
+const gitCommandTimeout = 2 * time.Minute
+
+// runGitCommand runs git in dir without a terminal, so credential prompts
+// fail instead of blocking. env is appended to the daemon's environment.
+// Only stdout is returned, with trailing newlines removed; stderr is kept
+// out of parsed output and only surfaces in the error.
+func runGitCommand(ctx context.Context, dir string, env []string, args ...string) (string, error) {
+	if strings.TrimSpace(dir) == "" {
+		return "", fmt.Errorf("path is required")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, gitCommandTimeout)
+	defer cancel()
+	full := append([]string{"-C", dir, "-c", "core.quotepath=false"}, args...)
+	cmd := exec.CommandContext(ctx, "git", full...)
+	cmd.Env = append(append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS="), env...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	// Keep leading whitespace: porcelain status lines start with it.
+	output := strings.TrimRight(stdout.String(), "\r\n")
+	if err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return output, fmt.Errorf("git %s failed: %s", args[0], message)
+	}
+	return output, nil
+}
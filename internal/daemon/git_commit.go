@@ -0,0 +1,55 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// gitCommitTimeout leaves room for pre-commit hooks.
+const gitCommitTimeout = 2 * time.Minute
+
+var errGitNothingToCommit = errors.New("no changes to commit")
+
+// commitGitWorkingTree stages every change in the repository at root
+// (including untracked, non-ignored files) and commits it with message. It
+// returns the new commit and the committed paths. Staging happens in a
+// temporary index, as checkpoints do, so a failed commit leaves the user's
+// index as it was; after a successful one the index is reset to the commit.
+func commitGitWorkingTree(ctx context.Context, root, message string) (string, []string, error) {
+	if strings.TrimSpace(message) == "" {
+		return "", nil, fmt.Errorf("commit message is required")
+	}
+	ctx, cancel := context.WithTimeout(ctx, gitCommitTimeout)
+	defer cancel()
+	indexFile, cleanup, err := gitTempIndex(ctx, root, true)
+	if err != nil {
+		return "", nil, err
+	}
+	defer cleanup()
+	env := []string{"GIT_INDEX_FILE=" + indexFile}
+	if _, err := runGitCommand(ctx, root, env, "add", "-A", "--", "."); err != nil {
+		return "", nil, err
+	}
+	staged, err := runGitCommand(ctx, root, env, "diff", "--cached", "--name-only")
+	if err != nil {
+		return "", nil, err
+	}
+	files := splitGitOutputLines(staged)
+	if len(files) == 0 {
+		return "", nil, errGitNothingToCommit
+	}
+	if _, err := runGitCommand(ctx, root, env, "commit", "-q", "--cleanup=whitespace", "-m", message); err != nil {
+		return "", nil, err
+	}
+	commit, err := runGitCommand(ctx, root, nil, "rev-parse", "HEAD")
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := runGitCommand(ctx, root, nil, "reset", "-q"); err != nil {
+		return "", nil, err
+	}
+	return strings.TrimSpace(commit), files, nil
+}
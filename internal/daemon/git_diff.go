@@ -0,0 +1,227 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"control/internal/types"
+)
+
+const (
+	gitDiffDefaultBase = "HEAD"
+	gitDiffMaxLines    = 20000
+	gitDiffTimeout     = 15 * time.Second
+)
+
+// readGitDiff compares the working tree at dir (staged and unstaged changes)
+// against base and lists untracked files.
+func readGitDiff(ctx context.Context, dir, base string) (*types.GitDiff, error) {
+	if strings.TrimSpace(dir) == "" {
+		return nil, fmt.Errorf("path is required")
+	}
+	base = strings.TrimSpace(base)
+	if base == "" {
+		base = gitDiffDefaultBase
+	}
+	if strings.HasPrefix(base, "-") {
+		return nil, fmt.Errorf("invalid base ref: %s", base)
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, gitDiffTimeout)
+	defer cancel()
+
+	root, err := runGitCommand(ctx, dir, nil, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	baseCommit, err := runGitCommand(ctx, dir, nil, "rev-parse", "--verify", "--quiet", base+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("unknown base ref: %s", base)
+	}
+	baseCommit = strings.TrimSpace(baseCommit)
+	patch, err := runGitCommand(ctx, dir, nil, "diff", "--no-color", "--no-ext-diff", "--find-renames", baseCommit, "--")
+	if err != nil {
+		return nil, err
+	}
+	// ls-files only lists paths under its cwd, and dir may be a subdirectory.
+	untracked, err := runGitCommand(ctx, strings.TrimSpace(root), nil, "ls-files", "--others", "--exclude-standard", "--full-name")
+	if err != nil {
+		return nil, err
+	}
+
+	diff := parseGitDiff(patch)
+	diff.Root = strings.TrimSpace(root)
+	diff.Base = base
+	diff.BaseCommit = baseCommit
+	for _, line := range strings.Split(untracked, "\n") {
+		if path := strings.TrimSpace(line); path != "" {
+			diff.Untracked = append(diff.Untracked, path)
+		}
+	}
+	diff.Stats.Untracked = len(diff.Untracked)
+	diff.GeneratedAt = time.Now().UTC().Format(time.RFC3339Nano)
+	return diff, nil
+}
+
+// parseGitDiff parses `git diff` unified output. Parsing stops after
+// gitDiffMaxLines hunk lines and marks the result truncated.
+func parseGitDiff(output string) *types.GitDiff {
+	diff := &types.GitDiff{Files: []types.GitDiffFile{}}
+	var file *types.GitDiffFile
+	var hunk *types.GitDiffHunk
+	lines := 0
+	flushHunk := func() {
+		if file != nil && hunk != nil {
+			file.Hunks = append(file.Hunks, *hunk)
+		}
+		hunk = nil
+	}
+	flushFile := func() {
+		flushHunk()
+		if file != nil && file.Path != "" {
+			diff.Files = append(diff.Files, *file)
+		}
+		file = nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "diff --git ") {
+			flushFile()
+			oldPath, newPath := parseGitDiffHeaderPaths(strings.TrimPrefix(line, "diff --git "))
+			file = &types.GitDiffFile{Path: newPath, OldPath: oldPath, Status: types.GitDiffFileModified}
+			continue
+		}
+		if file == nil {
+			continue
+		}
+		if hunk != nil {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				file.Additions++
+				lines++
+				if lines <= gitDiffMaxLines {
+					hunk.Lines = append(hunk.Lines, types.GitDiffLine{Kind: types.GitDiffLineAdded, Text: line[1:]})
+				}
+				continue
+			case strings.HasPrefix(line, "-"):
+				file.Deletions++
+				lines++
+				if lines <= gitDiffMaxLines {
+					hunk.Lines = append(hunk.Lines, types.GitDiffLine{Kind: types.GitDiffLineDeleted, Text: line[1:]})
+				}
+				continue
+			case strings.HasPrefix(line, " ") || line == "":
+				lines++
+				if lines <= gitDiffMaxLines {
+					hunk.Lines = append(hunk.Lines, types.GitDiffLine{Kind: types.GitDiffLineContext, Text: strings.TrimPrefix(line, " ")})
+				}
+				continue
+			case strings.HasPrefix(line, `\`):
+				continue
+			}
+		}
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			flushHunk()
+			hunk = parseGitDiffHunkHeader(line)
+		case strings.HasPrefix(line, "new file mode"):
+			file.Status = types.GitDiffFileAdded
+		case strings.HasPrefix(line, "deleted file mode"):
+			file.Status = types.GitDiffFileDeleted
+		case strings.HasPrefix(line, "rename from "):
+			file.Status = types.GitDiffFileRenamed
+			file.OldPath = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			file.Path = strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "copy from "):
+			file.Status = types.GitDiffFileCopied
+			file.OldPath = strings.TrimPrefix(line, "copy from ")
+		case strings.HasPrefix(line, "copy to "):
+			file.Path = strings.TrimPrefix(line, "copy to ")
+		case strings.HasPrefix(line, "Binary files ") || line == "GIT binary patch":
+			file.Binary = true
+		case strings.HasPrefix(line, "--- "):
+			if path := gitDiffMarkerPath(strings.TrimPrefix(line, "--- "), "a/"); path != "" {
+				file.OldPath = path
+			}
+		case strings.HasPrefix(line, "+++ "):
+			if path := gitDiffMarkerPath(strings.TrimPrefix(line, "+++ "), "b/"); path != "" {
+				file.Path = path
+			}
+		}
+	}
+	flushFile()
+	if lines > gitDiffMaxLines {
+		diff.Truncated = true
+	}
+
+	for i := range diff.Files {
+		file := &diff.Files[i]
+		if file.Status == types.GitDiffFileModified || file.OldPath == file.Path {
+			file.OldPath = ""
+		}
+		if file.Status == types.GitDiffFileDeleted && file.Path == "" {
+			file.Path = file.OldPath
+		}
+		diff.Stats.Additions += file.Additions
+		diff.Stats.Deletions += file.Deletions
+	}
+	diff.Stats.Files = len(diff.Files)
+	return diff
+}
+
+// parseGitDiffHeaderPaths splits "a/<old> b/<new>". Paths containing " b/"
+// are ambiguous here; the ---/+++ and rename lines that follow override them.
+func parseGitDiffHeaderPaths(raw string) (string, string) {
+	raw = strings.TrimSpace(raw)
+	idx := strings.LastIndex(raw, " b/")
+	if idx < 0 || !strings.HasPrefix(raw, "a/") {
+		return "", raw
+	}
+	return strings.TrimPrefix(raw[:idx], "a/"), raw[idx+len(" b/"):]
+}
+
+func gitDiffMarkerPath(raw, prefix string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(raw, prefix)
+}
+
+func parseGitDiffHunkHeader(line string) *types.GitDiffHunk {
+	hunk := &types.GitDiffHunk{Header: line}
+	body := strings.TrimPrefix(line, "@@")
+	end := strings.Index(body, "@@")
+	if end < 0 {
+		return hunk
+	}
+	for _, field := range strings.Fields(body[:end]) {
+		switch {
+		case strings.HasPrefix(field, "-"):
+			hunk.OldStart, hunk.OldLines = parseGitDiffRange(field[1:])
+		case strings.HasPrefix(field, "+"):
+			hunk.NewStart, hunk.NewLines = parseGitDiffRange(field[1:])
+		}
+	}
+	return hunk
+}
+
+func parseGitDiffRange(raw string) (int, int) {
+	startRaw, countRaw, hasCount := strings.Cut(raw, ",")
+	start, _ := strconv.Atoi(startRaw)
+	if !hasCount {
+		return start, 1
+	}
+	count, _ := strconv.Atoi(countRaw)
+	return start, count
+}
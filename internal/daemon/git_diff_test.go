@@ -0,0 +1,189 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"control/internal/types"
+)
+
+func TestParseGitDiff(t *testing.T) {
+	output := `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,4 @@ package main
+ package main
+-func old() {}
++func new() {}
++func extra() {}
+
+\ No newline at end of file
+diff --git a/old.txt b/renamed.txt
+similarity index 90%
+rename from old.txt
+rename to renamed.txt
+diff --git a/gone.txt b/gone.txt
+deleted file mode 100644
+index 3333333..0000000
+--- a/gone.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+diff --git a/logo.png b/logo.png
+new file mode 100644
+index 0000000..4444444
+Binary files /dev/null and b/logo.png differ
+`
+	diff := parseGitDiff(output)
+	if len(diff.Files) != 4 {
+		t.Fatalf("expected 4 files, got %#v", diff.Files)
+	}
+	main := diff.Files[0]
+	if main.Path != "main.go" || main.Status != types.GitDiffFileModified || main.Additions != 2 || main.Deletions != 1 {
+		t.Fatalf("unexpected main.go entry: %#v", main)
+	}
+	if len(main.Hunks) != 1 || main.Hunks[0].OldStart != 1 || main.Hunks[0].NewLines != 4 || len(main.Hunks[0].Lines) != 5 {
+		t.Fatalf("unexpected main.go hunk: %#v", main.Hunks)
+	}
+	if main.Hunks[0].Lines[2].Kind != types.GitDiffLineAdded || main.Hunks[0].Lines[2].Text != "func new() {}" {
+		t.Fatalf("unexpected hunk line: %#v", main.Hunks[0].Lines[2])
+	}
+	if renamed := diff.Files[1]; renamed.Status != types.GitDiffFileRenamed || renamed.Path != "renamed.txt" || renamed.OldPath != "old.txt" {
+		t.Fatalf("unexpected rename entry: %#v", renamed)
+	}
+	if gone := diff.Files[2]; gone.Status != types.GitDiffFileDeleted || gone.Path != "gone.txt" || gone.Deletions != 1 {
+		t.Fatalf("unexpected delete entry: %#v", gone)
+	}
+	if logo := diff.Files[3]; logo.Status != types.GitDiffFileAdded || !logo.Binary {
+		t.Fatalf("unexpected binary entry: %#v", logo)
+	}
+	if diff.Stats.Files != 4 || diff.Stats.Additions != 2 || diff.Stats.Deletions != 2 {
+		t.Fatalf("unexpected stats: %#v", diff.Stats)
+	}
+}
+
+func TestWorktreeDiffEndpoint(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repoDir := filepath.Join(t.TempDir(), "repo")
+	if err := os.MkdirAll(repoDir, 0o755); err != nil {
+		t.Fatalf("mkdir repo: %v", err)
+	}
+	runTestGit(t, repoDir, "init", "-q")
+	writeTestFile(t, filepath.Join(repoDir, "README.md"), "hello\n")
+	runTestGit(t, repoDir, "add", "README.md")
+	runTestGit(t, repoDir, "commit", "-q", "-m", "init")
+	writeTestFile(t, filepath.Join(repoDir, "README.md"), "hello\nworld\n")
+	writeTestFile(t, filepath.Join(repoDir, "notes.txt"), "draft\n")
+
+	stores := newTestStores(t)
+	ws, err := stores.Workspaces.Add(context.Background(), &types.Workspace{Name: "repo", RepoPath: repoDir})
+	if err != nil {
+		t.Fatalf("add workspace: %v", err)
+	}
+	wt, err := stores.Worktrees.AddWorktree(context.Background(), ws.ID, &types.Worktree{Name: "main", Path: repoDir})
+	if err != nil {
+		t.Fatalf("add worktree: %v", err)
+	}
+	api := &API{Version: "test", Stores: stores}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/worktrees/", api.WorktreeByID)
+	server := httptest.NewServer(TokenAuthMiddleware("token", mux))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/v1/worktrees/"+wt.ID+"/diff", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	defer closeTestCloser(t, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, string(body))
+	}
+	var diff types.GitDiff
+	if err := json.NewDecoder(resp.Body).Decode(&diff); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if diff.Base != "HEAD" || diff.BaseCommit == "" {
+		t.Fatalf("unexpected base: %q/%q", diff.Base, diff.BaseCommit)
+	}
+	if len(diff.Files) != 1 || diff.Files[0].Path != "README.md" || diff.Files[0].Additions != 1 {
+		t.Fatalf("unexpected files: %#v", diff.Files)
+	}
+	if len(diff.Untracked) != 1 || diff.Untracked[0] != "notes.txt" || diff.Stats.Untracked != 1 {
+		t.Fatalf("unexpected untracked files: %#v", diff.Untracked)
+	}
+
+	badReq, _ := http.NewRequest(http.MethodGet, server.URL+"/v1/worktrees/"+wt.ID+"/diff?base=no-such-ref", nil)
+	badReq.Header.Set("Authorization", "Bearer token")
+	badResp, err := http.DefaultClient.Do(badReq)
+	if err != nil {
+		t.Fatalf("diff bad base: %v", err)
+	}
+	defer closeTestCloser(t, badResp.Body)
+	if badResp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown base, got %d", badResp.StatusCode)
+	}
+
+	missingReq, _ := http.NewRequest(http.MethodGet, server.URL+"/v1/worktrees/missing/diff", nil)
+	missingReq.Header.Set("Authorization", "Bearer token")
+	missingResp, err := http.DefaultClient.Do(missingReq)
+	if err != nil {
+		t.Fatalf("diff missing: %v", err)
+	}
+	defer closeTestCloser(t, missingResp.Body)
+	if missingResp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown worktree, got %d", missingResp.StatusCode)
+	}
+}
+
+func TestReadGitDiffFromSubdirectoryListsAllUntracked(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repoDir := t.TempDir()
+	subDir := filepath.Join(repoDir, "pkg")
+	if err := os.MkdirAll(subDir, 0o755); err != nil {
+		t.Fatalf("mkdir pkg: %v", err)
+	}
+	runTestGit(t, repoDir, "init", "-q")
+	writeTestFile(t, filepath.Join(subDir, "lib.go"), "package pkg\n")
+	runTestGit(t, repoDir, "add", ".")
+	runTestGit(t, repoDir, "commit", "-q", "-m", "init")
+	writeTestFile(t, filepath.Join(repoDir, "notes.txt"), "draft\n")
+	writeTestFile(t, filepath.Join(subDir, "new.go"), "package pkg\n")
+
+	diff, err := readGitDiff(context.Background(), subDir, "")
+	if err != nil {
+		t.Fatalf("readGitDiff: %v", err)
+	}
+	if len(diff.Untracked) != 2 || diff.Untracked[0] != "notes.txt" || diff.Untracked[1] != "pkg/new.go" {
+		t.Fatalf("expected untracked files from the whole repo, got %#v", diff.Untracked)
+	}
+}
+
+func runTestGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	full := append([]string{"-C", dir, "-c", "user.name=archon", "-c", "user.email=archon@example.com", "-c", "commit.gpgsign=false"}, args...)
+	if output, err := exec.Command("git", full...).CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v: %s", args, err, output)
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
@@ -2,8 +2,8 @@ package daemon
 
 import (
 	"bufio"
+	"context"
 	"fmt"
-	"os/exec"
 	"strings"
 
 	"control/internal/types"
@@ -13,33 +13,187 @@ func listGitWorktrees(repoPath string) ([]*types.GitWorktree, error) {
 	if strings.TrimSpace(repoPath) == "" {
 		return nil, fmt.Errorf("repo path is required")
 	}
-	cmd := exec.Command("git", "-C", repoPath, "worktree", "list", "--porcelain")
-	output, err := cmd.CombinedOutput()
+	output, err := runGitCommand(context.Background(), repoPath, nil, "worktree", "list", "--porcelain")
 	if err != nil {
-		return nil, fmt.Errorf("git worktree list failed: %s", strings.TrimSpace(string(output)))
+		return nil, err
 	}
-	return parseGitWorktreeList(string(output)), nil
+	return parseGitWorktreeList(output), nil
 }
 
-func createGitWorktree(repoPath, path, branch string) error {
+func createGitWorktree(ctx context.Context, repoPath, path, branch string) error {
 	if strings.TrimSpace(repoPath) == "" {
 		return fmt.Errorf("repo path is required")
 	}
 	if strings.TrimSpace(path) == "" {
 		return fmt.Errorf("worktree path is required")
 	}
-	args := []string{"-C", repoPath, "worktree", "add", path}
+	args := []string{"worktree", "add", path}
 	if strings.TrimSpace(branch) != "" {
 		args = append(args, branch)
 	}
-	cmd := exec.Command("git", args...)
-	output, err := cmd.CombinedOutput()
+	if _, err := runGitCommand(ctx, repoPath, nil, args...); err != nil {
+		return err
+	}
+	return nil
+}
+
+func removeGitWorktree(ctx context.Context, repoPath, path string, force bool) error {
+	args := []string{"worktree", "remove"}
+	if force {
+		args = append(args, "--force")
+	}
+	args = append(args, path)
+	if _, err := runGitCommand(ctx, repoPath, nil, args...); err != nil {
+		return err
+	}
+	return nil
+}
+
+// pruneGitWorktrees drops administrative entries of worktrees whose
+// directories are gone and returns the path of each pruned entry. git only
+// describes pruned entries on stderr, so they are found by listing the
+// worktrees before and after.
+func pruneGitWorktrees(ctx context.Context, repoPath string) ([]string, error) {
+	before, err := listGitWorktrees(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := runGitCommand(ctx, repoPath, nil, "worktree", "prune"); err != nil {
+		return nil, err
+	}
+	after, err := listGitWorktrees(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	remaining := make(map[string]struct{}, len(after))
+	for _, wt := range after {
+		remaining[wt.Path] = struct{}{}
+	}
+	var pruned []string
+	for _, wt := range before {
+		if _, ok := remaining[wt.Path]; !ok {
+			pruned = append(pruned, "removed worktree "+wt.Path)
+		}
+	}
+	return pruned, nil
+}
+
+func gitDirtyFiles(ctx context.Context, path string) ([]string, error) {
+	output, err := runGitCommand(ctx, path, nil, "status", "--porcelain", "--untracked-files=normal")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, line := range splitGitOutputLines(output) {
+		if len(line) > 3 {
+			files = append(files, strings.TrimSpace(line[3:]))
+		}
+	}
+	return files, nil
+}
+
+func gitCurrentBranch(ctx context.Context, path string) (string, error) {
+	output, err := runGitCommand(ctx, path, nil, "symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil || output == "" {
+		return "", fmt.Errorf("%s is not on a branch", path)
+	}
+	return output, nil
+}
+
+func gitConflictFiles(ctx context.Context, path string) []string {
+	output, err := runGitCommand(ctx, path, nil, "diff", "--name-only", "--diff-filter=U")
 	if err != nil {
-		return fmt.Errorf("git worktree add failed: %s", strings.TrimSpace(string(output)))
+		return nil
+	}
+	return splitGitOutputLines(output)
+}
+
+// mergeGitBranch merges branch into the branch checked out at repoPath. A
+// conflicting merge is aborted and its conflicting paths are returned.
+func mergeGitBranch(ctx context.Context, repoPath, branch string) ([]string, string, error) {
+	output, err := runGitCommand(ctx, repoPath, nil, "merge", "--no-edit", branch)
+	if err == nil {
+		return nil, output, nil
+	}
+	conflicts := gitConflictFiles(ctx, repoPath)
+	if len(conflicts) == 0 {
+		return nil, output, err
+	}
+	_, _ = runGitCommand(ctx, repoPath, nil, "merge", "--abort")
+	return conflicts, output, nil
+}
+
+// rebaseGitBranch rebases the branch checked out at path onto target. A
+// conflicting rebase is aborted and its conflicting paths are returned.
+func rebaseGitBranch(ctx context.Context, path, target string) ([]string, string, error) {
+	output, err := runGitCommand(ctx, path, nil, "rebase", target)
+	if err == nil {
+		return nil, output, nil
+	}
+	conflicts := gitConflictFiles(ctx, path)
+	if len(conflicts) == 0 {
+		_, _ = runGitCommand(ctx, path, nil, "rebase", "--abort")
+		return nil, output, err
+	}
+	_, _ = runGitCommand(ctx, path, nil, "rebase", "--abort")
+	return conflicts, output, nil
+}
+
+// pushGitBranch pushes branch to remote and sets it as upstream. It reports
+// rejected=true when the remote refused the update (e.g. non-fast-forward).
+func pushGitBranch(ctx context.Context, path, remote, branch string, force bool) (bool, string, error) {
+	args := []string{"push", "--set-upstream", "--porcelain"}
+	if force {
+		args = append(args, "--force-with-lease")
+	}
+	args = append(args, remote, branch)
+	output, err := runGitCommand(ctx, path, nil, args...)
+	if err == nil {
+		return false, output, nil
+	}
+	if strings.Contains(output, "[rejected]") || strings.Contains(output, "[remote rejected]") {
+		return true, output, nil
+	}
+	return false, output, err
+}
+
+// createGitWorktreeBranch adds a worktree at path on a new branch started
+// from the commit checked out at repoPath.
+func createGitWorktreeBranch(ctx context.Context, repoPath, path, branch string) error {
+	if _, err := runGitCommand(ctx, repoPath, nil, "worktree", "add", "-b", branch, path); err != nil {
+		return err
 	}
 	return nil
 }
 
+// gitBranchMerged reports whether every commit of branch is reachable from
+// target.
+func gitBranchMerged(ctx context.Context, repoPath, branch, target string) bool {
+	_, err := runGitCommand(ctx, repoPath, nil, "merge-base", "--is-ancestor", branch, target)
+	return err == nil
+}
+
+func deleteGitBranch(ctx context.Context, repoPath, branch string, force bool) error {
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	if _, err := runGitCommand(ctx, repoPath, nil, "branch", flag, branch); err != nil {
+		return err
+	}
+	return nil
+}
+
+func splitGitOutputLines(output string) []string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, strings.TrimRight(line, "\r"))
+		}
+	}
+	return lines
+}
+
 func parseGitWorktreeList(output string) []*types.GitWorktree {
 	var out []*types.GitWorktree
 	scanner := bufio.NewScanner(strings.NewReader(output))
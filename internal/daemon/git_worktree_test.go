@@ -1,6 +1,11 @@
 package daemon
 
-import "testing"
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
 
 func TestParseGitWorktreeList(t *testing.T) {
 	output := `
@@ -30,3 +35,22 @@ detached
 		t.Fatalf("expected detached entry")
 	}
 }
+
+func TestGitDirtyFilesIgnoresStderr(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	runTestGit(t, repo, "init", "-q")
+	writeTestFile(t, filepath.Join(repo, "dirty.txt"), "x\n")
+	// GIT_TRACE makes git write trace lines to stderr on every call.
+	t.Setenv("GIT_TRACE", "1")
+
+	files, err := gitDirtyFiles(context.Background(), repo)
+	if err != nil {
+		t.Fatalf("gitDirtyFiles: %v", err)
+	}
+	if len(files) != 1 || files[0] != "dirty.txt" {
+		t.Fatalf("expected only dirty.txt, got %q", files)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"control/internal/logging"
 	"control/internal/providers"
 	"control/internal/store"
+	"control/internal/tracing"
 	"control/internal/types"
 )
 
@@ -57,6 +58,7 @@ func newGuidedWorkflowRunService(
 	stores *Stores,
 	manager *SessionManager,
 	liveManager LiveManager,
+	tracer *daemonTracing,
 	logger logging.Logger,
 ) guidedworkflows.RunService {
 	controls := guidedWorkflowsExecutionControlsFromCoreConfig(coreCfg)
@@ -87,7 +89,7 @@ func newGuidedWorkflowRunService(
 			opts = append(opts, guidedworkflows.WithMissingRunContextResolver(resolver))
 		}
 	}
-	if promptDispatcher := newGuidedWorkflowPromptDispatcher(coreCfg, manager, stores, liveManager, logger); promptDispatcher != nil {
+	if promptDispatcher := newGuidedWorkflowPromptDispatcher(coreCfg, manager, stores, liveManager, tracer, logger); promptDispatcher != nil {
 		opts = append(opts, guidedworkflows.WithStepPromptDispatcher(promptDispatcher))
 		opts = append(opts, guidedworkflows.WithGateDispatcher(NewLLMJudgeGateDispatcher(promptDispatcher)))
 	}
@@ -372,6 +374,7 @@ type guidedWorkflowPromptDispatcher struct {
 	defaults               guidedWorkflowDispatchDefaults
 	dispatchProviderPolicy guidedworkflows.DispatchProviderPolicy
 	dispatchTelemetry      dispatchTelemetryReporter
+	tracing                *daemonTracing
 	logger                 logging.Logger
 }
 
@@ -403,6 +406,23 @@ type dispatchTelemetryContext struct {
 	Blocked      bool
 }
 
+func (c dispatchTelemetryContext) spanAttributes() []tracing.Attribute {
+	attrs := []tracing.Attribute{}
+	for _, field := range []struct{ key, value string }{
+		{"workflow.run_id", c.RunID},
+		{"workflow.phase_id", c.PhaseID},
+		{"workflow.step_id", c.StepID},
+		{"workflow.gate_id", c.GateID},
+		{"workflow.gate_kind", c.GateKind},
+		{"workflow.boundary", c.Boundary},
+	} {
+		if value := strings.TrimSpace(field.value); value != "" {
+			attrs = append(attrs, tracing.String(field.key, value))
+		}
+	}
+	return attrs
+}
+
 type sessionTurnDispatchBridge interface {
 	dispatchSessionTurn(
 		ctx context.Context,
@@ -460,6 +480,7 @@ func newGuidedWorkflowPromptDispatcher(
 	manager *SessionManager,
 	stores *Stores,
 	liveManager LiveManager,
+	tracer *daemonTracing,
 	logger logging.Logger,
 ) guidedworkflows.StepPromptDispatcher {
 	if manager == nil || stores == nil {
@@ -469,12 +490,16 @@ func newGuidedWorkflowPromptDispatcher(
 	if liveManager != nil {
 		opts = append(opts, WithLiveManager(liveManager))
 	}
+	if tracer != nil {
+		opts = append(opts, WithDaemonTracing(tracer))
+	}
 	return &guidedWorkflowPromptDispatcher{
 		sessions:               NewSessionService(manager, stores, logger, opts...),
 		sessionMeta:            stores.SessionMeta,
 		defaults:               guidedWorkflowDispatchDefaultsFromCoreConfig(coreCfg),
 		dispatchProviderPolicy: guidedworkflows.DefaultDispatchProviderPolicy(),
 		dispatchTelemetry:      loggerDispatchTelemetryReporter{logger: logger},
+		tracing:                tracer,
 		logger:                 logger,
 	}
 }
@@ -534,6 +559,7 @@ func (d *LLMJudgeGateDispatcher) DispatchGate(
 		SignalID:   strings.TrimSpace(stepResult.TurnID),
 		Provider:   strings.TrimSpace(stepResult.Provider),
 		Model:      strings.TrimSpace(stepResult.Model),
+		TraceID:    strings.TrimSpace(stepResult.TraceID),
 	}, nil
 }
 
@@ -587,6 +613,19 @@ func (d *guidedWorkflowPromptDispatcher) dispatchSessionTurn(
 	if prompt == "" {
 		return guidedworkflows.StepPromptDispatchResult{}, fmt.Errorf("%w: prompt is empty", guidedworkflows.ErrStepDispatch)
 	}
+	ctx, span := d.tracing.Start(ctx, "workflow.dispatch", telemetry.spanAttributes()...)
+	defer span.End()
+	result, err := d.dispatchSessionTurnTraced(ctx, req, prompt, telemetry)
+	span.RecordError(err)
+	return result, err
+}
+
+func (d *guidedWorkflowPromptDispatcher) dispatchSessionTurnTraced(
+	ctx context.Context,
+	req guidedworkflows.StepPromptDispatchRequest,
+	prompt string,
+	telemetry dispatchTelemetryContext,
+) (guidedworkflows.StepPromptDispatchResult, error) {
 	sessionID, provider, model, err := d.resolveSession(ctx, req)
 	if err != nil {
 		return guidedworkflows.StepPromptDispatchResult{}, err
@@ -636,6 +675,7 @@ func (d *guidedWorkflowPromptDispatcher) dispatchSessionTurn(
 		TurnID:     strings.TrimSpace(turnID),
 		Provider:   strings.TrimSpace(provider),
 		Model:      effectiveModel,
+		TraceID:    tracing.TraceIDFromContext(ctx),
 	}
 	telemetry.SessionID = result.SessionID
 	telemetry.Provider = result.Provider
@@ -1577,7 +1617,8 @@ func (p *guidedWorkflowNotificationPublisher) Publish(event types.NotificationEv
 	if !readiness.ForProvider(event.Provider).AllowProgression(event, evidence) {
 		return
 	}
-	updatedRuns, err := p.turnProcessor.OnTurnCompleted(context.Background(), guidedworkflows.TurnSignal{
+	turnCtx := tracing.ContextWithTraceparent(context.Background(), event.TraceParent)
+	updatedRuns, err := p.turnProcessor.OnTurnCompleted(turnCtx, guidedworkflows.TurnSignal{
 		SessionID:   strings.TrimSpace(event.SessionID),
 		WorkspaceID: strings.TrimSpace(event.WorkspaceID),
 		WorktreeID:  strings.TrimSpace(event.WorktreeID),
@@ -1688,7 +1729,7 @@ func guidedWorkflowDecisionNotificationEvent(turnEvent *types.NotificationEvent,
 		"turn_id":         turnID,
 	}
 	notification := types.NotificationEvent{
-		Trigger:     types.NotificationTriggerTurnCompleted,
+		Trigger:     types.NotificationTriggerWorkflowDecisionNeeded,
 		OccurredAt:  time.Now().UTC().Format(time.RFC3339Nano),
 		SessionID:   sessionID,
 		WorkspaceID: workspaceID,
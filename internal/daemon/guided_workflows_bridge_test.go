@@ -396,7 +396,7 @@ func TestNewGuidedWorkflowNotificationPublisherSkipsWrapperWhenDisabled(t *testi
 
 func TestNewGuidedWorkflowRunServiceBuildsService(t *testing.T) {
 	cfg := config.DefaultCoreConfig()
-	service := newGuidedWorkflowRunService(cfg, nil, nil, nil, nil)
+	service := newGuidedWorkflowRunService(cfg, nil, nil, nil, nil, nil)
 	if service == nil {
 		t.Fatalf("expected run service")
 	}
@@ -416,7 +416,7 @@ func TestNewGuidedWorkflowRunServiceAppliesRolloutGuardrails(t *testing.T) {
 	cfg.GuidedWorkflows.Rollout.TelemetryEnabled = boolPtr(false)
 	cfg.GuidedWorkflows.Rollout.MaxActiveRuns = 1
 
-	service := newGuidedWorkflowRunService(cfg, nil, nil, nil, nil)
+	service := newGuidedWorkflowRunService(cfg, nil, nil, nil, nil, nil)
 	if service == nil {
 		t.Fatalf("expected run service")
 	}
@@ -451,7 +451,7 @@ func TestNewGuidedWorkflowRunServiceSupportsDependencyChaining(t *testing.T) {
 	cfg := config.DefaultCoreConfig()
 	cfg.GuidedWorkflows.Enabled = boolPtr(true)
 
-	service := newGuidedWorkflowRunService(cfg, nil, nil, nil, nil)
+	service := newGuidedWorkflowRunService(cfg, nil, nil, nil, nil, nil)
 	upstream, err := service.CreateRun(context.Background(), guidedworkflows.CreateRunRequest{
 		WorkspaceID: "ws-1",
 		WorktreeID:  "wt-upstream",
@@ -524,7 +524,7 @@ func TestNewGuidedWorkflowRunServiceDoesNotPerformReconciliationSideEffects(t *t
 	_ = newGuidedWorkflowRunService(cfg, &Stores{
 		SessionMeta:  metaStore,
 		WorkflowRuns: runStore,
-	}, nil, nil, nil)
+	}, nil, nil, nil, nil)
 	if runStore.upsertCalls != 0 {
 		t.Fatalf("expected no run-store writes during service construction, got %d", runStore.upsertCalls)
 	}
@@ -552,7 +552,7 @@ func TestNewGuidedWorkflowRunServiceWiresMissingRunContextResolverFromStores(t *
 	service := newGuidedWorkflowRunService(cfg, &Stores{
 		SessionMeta:  metaStore,
 		WorkflowRuns: runStore,
-	}, nil, nil, nil)
+	}, nil, nil, nil, nil)
 
 	dismissed, err := service.DismissRun(context.Background(), "gwf-missing")
 	if err != nil {
@@ -981,7 +981,7 @@ func TestNewGuidedWorkflowRunServiceLoadsTemplatesFromFileConfig(t *testing.T) {
 }`), 0o600); err != nil {
 		t.Fatalf("WriteFile workflow_templates.json: %v", err)
 	}
-	service := newGuidedWorkflowRunService(cfg, nil, nil, nil, nil)
+	service := newGuidedWorkflowRunService(cfg, nil, nil, nil, nil, nil)
 	run, err := service.CreateRun(context.Background(), guidedworkflows.CreateRunRequest{
 		TemplateID:  "custom_flow",
 		WorkspaceID: "ws-1",
@@ -2869,6 +2869,9 @@ func TestGuidedWorkflowNotificationPublisherEmitsDecisionNeededPayload(t *testin
 	if decisionEvent.Status != "decision_needed" {
 		t.Fatalf("expected decision_needed status, got %q", decisionEvent.Status)
 	}
+	if decisionEvent.Trigger != types.NotificationTriggerWorkflowDecisionNeeded {
+		t.Fatalf("expected workflow decision trigger, got %q", decisionEvent.Trigger)
+	}
 	if reason := asString(decisionEvent.Payload["reason"]); reason == "" {
 		t.Fatalf("expected reason in payload")
 	}
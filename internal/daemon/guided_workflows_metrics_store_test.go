@@ -137,7 +137,7 @@ func TestNewGuidedWorkflowRunServiceRestoresPersistedMetrics(t *testing.T) {
 	}
 	cfg := config.DefaultCoreConfig()
 	cfg.GuidedWorkflows.Enabled = boolPtr(true)
-	service := newGuidedWorkflowRunService(cfg, &Stores{AppState: appStateStore}, nil, nil, nil)
+	service := newGuidedWorkflowRunService(cfg, &Stores{AppState: appStateStore}, nil, nil, nil, nil)
 
 	metricsProvider, ok := any(service).(GuidedWorkflowRunMetricsService)
 	if !ok {
@@ -189,7 +189,7 @@ func TestNewGuidedWorkflowRunServiceRestoresPersistedRuns(t *testing.T) {
 	}
 	cfg := config.DefaultCoreConfig()
 	cfg.GuidedWorkflows.Enabled = boolPtr(true)
-	service := newGuidedWorkflowRunService(cfg, &Stores{WorkflowRuns: runStore}, nil, nil, nil)
+	service := newGuidedWorkflowRunService(cfg, &Stores{WorkflowRuns: runStore}, nil, nil, nil, nil)
 
 	run, err := service.GetRun(context.Background(), "gwf-restored")
 	if err != nil {
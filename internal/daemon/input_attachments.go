@@ -0,0 +1,255 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"control/internal/daemon/acp"
+	"control/internal/types"
+)
+
+// maxInputAttachmentBytes bounds the size of a single attached file. Images
+// and documents are inlined into provider requests, so larger files are
+// better referenced by path in the message text.
+const maxInputAttachmentBytes = 20 << 20
+
+// validateInputAttachments checks that every attachment of a send input
+// names a readable regular file of acceptable size.
+func validateInputAttachments(input []map[string]any) error {
+	for _, attachment := range types.InputAttachments(input) {
+		if !filepath.IsAbs(attachment.Path) {
+			return fmt.Errorf("attachment path must be absolute: %s", attachment.Path)
+		}
+		switch attachment.Kind {
+		case types.InputAttachmentImage, types.InputAttachmentFile:
+		default:
+			return fmt.Errorf("unsupported attachment kind %q", attachment.Kind)
+		}
+		info, err := os.Stat(attachment.Path)
+		if err != nil {
+			return fmt.Errorf("attachment %s: %w", attachment.DisplayName(), err)
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("attachment %s is not a regular file", attachment.DisplayName())
+		}
+		if info.Size() > maxInputAttachmentBytes {
+			return fmt.Errorf("attachment %s is larger than %d MB", attachment.DisplayName(), maxInputAttachmentBytes>>20)
+		}
+	}
+	return nil
+}
+
+// inputTextOrAttachments returns the text of a send input and its
+// attachments, failing when the input carries neither.
+func inputTextOrAttachments(input []map[string]any) (string, []types.InputAttachment, error) {
+	text := extractTextInput(input)
+	attachments := types.InputAttachments(input)
+	if text == "" && len(attachments) == 0 {
+		return "", nil, invalidError("text input is required", nil)
+	}
+	return text, attachments, nil
+}
+
+func readInputAttachment(attachment types.InputAttachment) ([]byte, error) {
+	data, err := os.ReadFile(attachment.Path)
+	if err != nil {
+		return nil, fmt.Errorf("attachment %s: %w", attachment.DisplayName(), err)
+	}
+	if len(data) > maxInputAttachmentBytes {
+		return nil, fmt.Errorf("attachment %s is larger than %d MB", attachment.DisplayName(), maxInputAttachmentBytes>>20)
+	}
+	return data, nil
+}
+
+// isTextAttachmentData reports whether a file attachment can be passed to a
+// provider as text.
+func isTextAttachmentData(data []byte) bool {
+	return utf8.Valid(data) && !bytes.ContainsRune(data, 0)
+}
+
+func attachmentFileURL(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// attachmentReferenceText lists attachments a provider cannot take natively,
+// so the agent can still read them with its own tools.
+func attachmentReferenceText(attachments []types.InputAttachment) string {
+	if len(attachments) == 0 {
+		return ""
+	}
+	lines := make([]string, 0, len(attachments)+1)
+	lines = append(lines, "Attached files:")
+	for _, attachment := range attachments {
+		lines = append(lines, "- "+attachment.Path)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// codexTurnInput maps attachment items to Codex user input: images become
+// localImage items, other files are listed in a trailing text item.
+func codexTurnInput(input []map[string]any) []map[string]any {
+	out := make([]map[string]any, 0, len(input))
+	var files []types.InputAttachment
+	for _, item := range input {
+		attachment, ok := types.InputAttachmentFromItem(item)
+		if !ok {
+			out = append(out, item)
+			continue
+		}
+		if attachment.Kind == types.InputAttachmentImage {
+			out = append(out, map[string]any{"type": "localImage", "path": attachment.Path})
+			continue
+		}
+		files = append(files, attachment)
+	}
+	if text := attachmentReferenceText(files); text != "" {
+		out = append(out, map[string]any{"type": "text", "text": text})
+	}
+	return out
+}
+
+// claudeAttachmentContentBlocks maps attachments to Claude message content
+// blocks: images and PDFs are inlined as base64 sources, text files as text
+// documents, and anything else is referenced by path.
+func claudeAttachmentContentBlocks(attachments []types.InputAttachment) ([]map[string]any, error) {
+	blocks := make([]map[string]any, 0, len(attachments))
+	var unsupported []types.InputAttachment
+	for _, attachment := range attachments {
+		data, err := readInputAttachment(attachment)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case attachment.Kind == types.InputAttachmentImage:
+			blocks = append(blocks, map[string]any{
+				"type": "image",
+				"source": map[string]any{
+					"type":       "base64",
+					"media_type": attachment.MimeType,
+					"data":       base64.StdEncoding.EncodeToString(data),
+				},
+			})
+		case attachment.MimeType == "application/pdf":
+			blocks = append(blocks, map[string]any{
+				"type":  "document",
+				"title": attachment.DisplayName(),
+				"source": map[string]any{
+					"type":       "base64",
+					"media_type": attachment.MimeType,
+					"data":       base64.StdEncoding.EncodeToString(data),
+				},
+			})
+		case isTextAttachmentData(data):
+			blocks = append(blocks, map[string]any{
+				"type":  "document",
+				"title": attachment.DisplayName(),
+				"source": map[string]any{
+					"type":       "text",
+					"media_type": "text/plain",
+					"data":       string(data),
+				},
+			})
+		default:
+			unsupported = append(unsupported, attachment)
+		}
+	}
+	if text := attachmentReferenceText(unsupported); text != "" {
+		blocks = append(blocks, map[string]any{"type": "text", "text": text})
+	}
+	return blocks, nil
+}
+
+// acpAttachmentContentBlocks maps attachments to ACP prompt content. Images
+// and text files are embedded when the agent advertises support for them;
+// everything else becomes a resource link, which all agents accept.
+func acpAttachmentContentBlocks(attachments []types.InputAttachment, caps *acp.PromptCapabilities) ([]acp.ContentBlock, error) {
+	blocks := make([]acp.ContentBlock, 0, len(attachments))
+	for _, attachment := range attachments {
+		uri := attachmentFileURL(attachment.Path)
+		embedImage := attachment.Kind == types.InputAttachmentImage && caps != nil && caps.Image
+		embedText := attachment.Kind == types.InputAttachmentFile && caps != nil && caps.EmbeddedContext
+		if !embedImage && !embedText {
+			blocks = append(blocks, acp.ContentBlock{
+				Type:     acp.ContentBlockResourceLink,
+				URI:      uri,
+				Name:     attachment.DisplayName(),
+				MimeType: attachment.MimeType,
+			})
+			continue
+		}
+		data, err := readInputAttachment(attachment)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case embedImage:
+			blocks = append(blocks, acp.ContentBlock{
+				Type:     acp.ContentBlockImage,
+				MimeType: attachment.MimeType,
+				Data:     base64.StdEncoding.EncodeToString(data),
+				URI:      uri,
+			})
+		case isTextAttachmentData(data):
+			blocks = append(blocks, acp.ContentBlock{
+				Type: acp.ContentBlockResource,
+				Resource: &acp.EmbeddedResource{
+					URI:      uri,
+					MimeType: attachment.MimeType,
+					Text:     string(data),
+				},
+			})
+		default:
+			blocks = append(blocks, acp.ContentBlock{
+				Type: acp.ContentBlockResource,
+				Resource: &acp.EmbeddedResource{
+					URI:      uri,
+					MimeType: attachment.MimeType,
+					Blob:     base64.StdEncoding.EncodeToString(data),
+				},
+			})
+		}
+	}
+	return blocks, nil
+}
+
+// openCodeAttachmentParts maps attachments to OpenCode file parts. Images
+// travel as data URLs; other files as file URLs the server reads itself.
+func openCodeAttachmentParts(attachments []types.InputAttachment) ([]map[string]any, error) {
+	parts := make([]map[string]any, 0, len(attachments))
+	for _, attachment := range attachments {
+		part := map[string]any{
+			"type":     "file",
+			"mime":     attachment.MimeType,
+			"filename": attachment.DisplayName(),
+			"url":      attachmentFileURL(attachment.Path),
+		}
+		if attachment.Kind == types.InputAttachmentImage {
+			data, err := readInputAttachment(attachment)
+			if err != nil {
+				return nil, err
+			}
+			part["url"] = "data:" + attachment.MimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+// userMessageContent is the content of the userMessage item persisted for a
+// turn, listing attachments after the text.
+func userMessageContent(text string, attachments []types.InputAttachment) []map[string]any {
+	content := make([]map[string]any, 0, len(attachments)+1)
+	if strings.TrimSpace(text) != "" {
+		content = append(content, map[string]any{"type": "text", "text": text})
+	}
+	for _, attachment := range attachments {
+		content = append(content, attachment.InputItem())
+	}
+	return content
+}
@@ -0,0 +1,156 @@
+package daemon
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"control/internal/daemon/acp"
+	"control/internal/types"
+)
+
+var testPNGData = []byte("\x89PNG\r\n\x1a\nimage-bytes")
+
+func writeAttachmentFixture(t *testing.T, name string, data []byte) types.InputAttachment {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	return types.NewInputAttachment(path)
+}
+
+func TestValidateInputAttachments(t *testing.T) {
+	image := writeAttachmentFixture(t, "screen.png", testPNGData)
+	if err := validateInputAttachments([]map[string]any{{"type": "text", "text": "hi"}, image.InputItem()}); err != nil {
+		t.Fatalf("expected valid attachment, got %v", err)
+	}
+	cases := map[string]map[string]any{
+		"relative": types.NewInputAttachment("screen.png").InputItem(),
+		"missing":  types.NewInputAttachment(filepath.Join(t.TempDir(), "gone.png")).InputItem(),
+		"dir":      {"type": types.InputItemTypeAttachment, "kind": "file", "path": t.TempDir()},
+		"kind":     {"type": types.InputItemTypeAttachment, "kind": "video", "path": image.Path},
+	}
+	for name, item := range cases {
+		if err := validateInputAttachments([]map[string]any{item}); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestInputTextOrAttachmentsRequiresOne(t *testing.T) {
+	if _, _, err := inputTextOrAttachments(nil); err == nil {
+		t.Fatalf("expected error for empty input")
+	}
+	image := types.NewInputAttachment("/tmp/screen.png")
+	text, attachments, err := inputTextOrAttachments([]map[string]any{image.InputItem()})
+	if err != nil || text != "" || len(attachments) != 1 {
+		t.Fatalf("unexpected result: %q %#v %v", text, attachments, err)
+	}
+}
+
+func TestCodexTurnInputMapsAttachments(t *testing.T) {
+	image := types.NewInputAttachment("/tmp/screen.png")
+	log := types.NewInputAttachment("/tmp/app.log")
+	input := codexTurnInput([]map[string]any{
+		{"type": "text", "text": "see attached"},
+		image.InputItem(),
+		log.InputItem(),
+	})
+	if len(input) != 3 {
+		t.Fatalf("expected 3 input items, got %#v", input)
+	}
+	if input[1]["type"] != "localImage" || input[1]["path"] != "/tmp/screen.png" {
+		t.Fatalf("expected localImage item, got %#v", input[1])
+	}
+	if text, _ := input[2]["text"].(string); !strings.Contains(text, "/tmp/app.log") {
+		t.Fatalf("expected file reference text, got %#v", input[2])
+	}
+}
+
+func TestClaudeAttachmentContentBlocks(t *testing.T) {
+	image := writeAttachmentFixture(t, "screen.png", testPNGData)
+	log := writeAttachmentFixture(t, "app.log", []byte("line one\nline two\n"))
+	binary := writeAttachmentFixture(t, "core.bin", []byte{0, 1, 2})
+	blocks, err := claudeAttachmentContentBlocks([]types.InputAttachment{image, log, binary})
+	if err != nil {
+		t.Fatalf("claudeAttachmentContentBlocks: %v", err)
+	}
+	if len(blocks) != 3 {
+		t.Fatalf("expected 3 blocks, got %#v", blocks)
+	}
+	source, _ := blocks[0]["source"].(map[string]any)
+	if blocks[0]["type"] != "image" || source["media_type"] != "image/png" || source["data"] != base64.StdEncoding.EncodeToString(testPNGData) {
+		t.Fatalf("unexpected image block: %#v", blocks[0])
+	}
+	source, _ = blocks[1]["source"].(map[string]any)
+	if blocks[1]["type"] != "document" || blocks[1]["title"] != "app.log" || source["type"] != "text" || source["data"] != "line one\nline two\n" {
+		t.Fatalf("unexpected text document block: %#v", blocks[1])
+	}
+	if text, _ := blocks[2]["text"].(string); blocks[2]["type"] != "text" || !strings.Contains(text, binary.Path) {
+		t.Fatalf("expected binary file reference, got %#v", blocks[2])
+	}
+}
+
+func TestClaudeUserPayloadCarriesAttachments(t *testing.T) {
+	image := types.NewInputAttachment("/tmp/screen.png")
+	blocks := []map[string]any{{"type": "image"}}
+	payload := buildClaudeUserPayloadWithAttachments("", []types.InputAttachment{image}, blocks, nil, "turn-1")
+	request, err := decodeClaudeSendRequest(payload)
+	if err != nil {
+		t.Fatalf("decodeClaudeSendRequest: %v", err)
+	}
+	if request.TurnID != "turn-1" || len(request.Attachments) != 1 || request.Attachments[0] != image {
+		t.Fatalf("unexpected request: %#v", request)
+	}
+	content, _ := request.Message["content"].([]any)
+	if len(content) != 1 {
+		t.Fatalf("expected only the attachment block without text, got %#v", content)
+	}
+}
+
+func TestACPAttachmentContentBlocksFollowCapabilities(t *testing.T) {
+	image := writeAttachmentFixture(t, "screen.png", testPNGData)
+	log := writeAttachmentFixture(t, "app.log", []byte("hello"))
+
+	blocks, err := acpAttachmentContentBlocks([]types.InputAttachment{image, log}, nil)
+	if err != nil {
+		t.Fatalf("acpAttachmentContentBlocks: %v", err)
+	}
+	for _, block := range blocks {
+		if block.Type != acp.ContentBlockResourceLink || !strings.HasPrefix(block.URI, "file://") {
+			t.Fatalf("expected resource links without capabilities, got %#v", block)
+		}
+	}
+
+	blocks, err = acpAttachmentContentBlocks([]types.InputAttachment{image, log}, &acp.PromptCapabilities{Image: true, EmbeddedContext: true})
+	if err != nil {
+		t.Fatalf("acpAttachmentContentBlocks: %v", err)
+	}
+	if blocks[0].Type != acp.ContentBlockImage || blocks[0].MimeType != "image/png" || blocks[0].Data == "" {
+		t.Fatalf("expected embedded image, got %#v", blocks[0])
+	}
+	if blocks[1].Type != acp.ContentBlockResource || blocks[1].Resource == nil || blocks[1].Resource.Text != "hello" {
+		t.Fatalf("expected embedded text resource, got %#v", blocks[1])
+	}
+}
+
+func TestOpenCodePromptPartsIncludeAttachments(t *testing.T) {
+	image := writeAttachmentFixture(t, "screen.png", testPNGData)
+	log := writeAttachmentFixture(t, "app.log", []byte("hello"))
+	parts, err := openCodePromptParts("look", []types.InputAttachment{image, log})
+	if err != nil {
+		t.Fatalf("openCodePromptParts: %v", err)
+	}
+	if len(parts) != 3 || parts[0]["type"] != "text" {
+		t.Fatalf("unexpected parts: %#v", parts)
+	}
+	if url, _ := parts[1]["url"].(string); parts[1]["type"] != "file" || !strings.HasPrefix(url, "data:image/png;base64,") {
+		t.Fatalf("expected image data url part, got %#v", parts[1])
+	}
+	if url, _ := parts[2]["url"].(string); parts[2]["filename"] != "app.log" || !strings.HasPrefix(url, "file://") {
+		t.Fatalf("expected file url part, got %#v", parts[2])
+	}
+}
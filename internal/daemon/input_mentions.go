@@ -0,0 +1,213 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"control/internal/guidedworkflows"
+	"control/internal/store"
+	"control/internal/types"
+)
+
+const (
+	defaultMentionSessionBlocks = 8
+	maxMentionSessionBlocks     = 50
+	maxMentionBlockChars        = 2000
+	mentionSymbolContextLines   = 40
+)
+
+// expandInputMentions replaces mention input items with the text they
+// reference. Each expansion is wrapped with types.MentionContext so
+// transcripts can collapse it back to the compact token.
+func (s *SessionService) expandInputMentions(ctx context.Context, input []map[string]any) ([]map[string]any, error) {
+	if len(types.InputMentions(input)) == 0 {
+		return input, nil
+	}
+	out := make([]map[string]any, 0, len(input))
+	for _, item := range input {
+		if typ, _ := item["type"].(string); typ != types.InputItemTypeMention {
+			out = append(out, item)
+			continue
+		}
+		mention, ok := types.InputMentionFromItem(item)
+		if !ok {
+			return nil, invalidError("mention kind and id are required", nil)
+		}
+		text, err := s.mentionText(ctx, mention)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, map[string]any{"type": "text", "text": types.MentionContext(mention.Token(), text)})
+	}
+	return out, nil
+}
+
+func (s *SessionService) mentionText(ctx context.Context, mention types.InputMention) (string, error) {
+	switch mention.Kind {
+	case types.InputMentionSession:
+		return s.sessionMentionText(ctx, mention)
+	case types.InputMentionNote:
+		return s.noteMentionText(ctx, mention)
+	case types.InputMentionRun:
+		return s.runMentionText(ctx, mention)
+	case types.InputMentionSymbol:
+		return symbolMentionText(mention)
+	}
+	return "", invalidError(fmt.Sprintf("unsupported mention kind %q", mention.Kind), nil)
+}
+
+// sessionMentionText summarizes another session by its title and its last
+// user and assistant messages.
+func (s *SessionService) sessionMentionText(ctx context.Context, mention types.InputMention) (string, error) {
+	session, _, err := s.getSessionRecord(ctx, mention.ID)
+	if err != nil {
+		return "", err
+	}
+	if session == nil {
+		return "", notFoundError("mentioned session not found", ErrSessionNotFound)
+	}
+	title := strings.TrimSpace(session.Title)
+	if meta := s.getSessionMeta(ctx, session.ID); meta != nil && strings.TrimSpace(meta.Title) != "" {
+		title = strings.TrimSpace(meta.Title)
+	}
+	if title == "" {
+		title = session.ID
+	}
+	blocks := mention.Blocks
+	if blocks <= 0 {
+		blocks = defaultMentionSessionBlocks
+	}
+	blocks = min(blocks, maxMentionSessionBlocks)
+	snapshot, err := s.GetTranscriptSnapshot(ctx, session.ID, 0)
+	if err != nil {
+		return "", err
+	}
+	var messages []string
+	for i := len(snapshot.Blocks) - 1; i >= 0 && len(messages) < blocks; i-- {
+		block := snapshot.Blocks[i]
+		if block.Role != "user" && block.Role != "assistant" {
+			continue
+		}
+		text := strings.TrimSpace(types.CollapseMentionContext(block.Text))
+		if text == "" {
+			continue
+		}
+		messages = append(messages, block.Role+": "+truncateMentionText(text, maxMentionBlockChars))
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Session %q (%s, %s)", title, session.Provider, session.Status)
+	if len(messages) == 0 {
+		b.WriteString("\n\nNo messages yet.")
+		return b.String(), nil
+	}
+	fmt.Fprintf(&b, "\n\nLast %d messages:", len(messages))
+	for i := len(messages) - 1; i >= 0; i-- {
+		b.WriteString("\n\n" + messages[i])
+	}
+	return b.String(), nil
+}
+
+func (s *SessionService) noteMentionText(ctx context.Context, mention types.InputMention) (string, error) {
+	if s.stores == nil || s.stores.Notes == nil {
+		return "", unavailableError("notes store not available", nil)
+	}
+	note, ok, err := s.stores.Notes.Get(ctx, mention.ID)
+	if err != nil {
+		return "", unavailableError(err.Error(), err)
+	}
+	if !ok || note == nil {
+		return "", notFoundError("mentioned note not found", store.ErrNoteNotFound)
+	}
+	title := strings.TrimSpace(note.Title)
+	if title == "" {
+		title = note.ID
+	}
+	return "Note " + title + ":\n\n" + strings.TrimSpace(note.Body), nil
+}
+
+func (s *SessionService) runMentionText(ctx context.Context, mention types.InputMention) (string, error) {
+	if s.stores == nil || s.stores.WorkflowRuns == nil {
+		return "", unavailableError("workflow run store not available", nil)
+	}
+	snapshots, err := s.stores.WorkflowRuns.ListWorkflowRuns(ctx)
+	if err != nil {
+		return "", unavailableError(err.Error(), err)
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.Run != nil && snapshot.Run.ID == mention.ID {
+			return workflowRunMentionText(snapshot.Run), nil
+		}
+	}
+	return "", notFoundError("mentioned workflow run not found", guidedworkflows.ErrRunNotFound)
+}
+
+func workflowRunMentionText(run *guidedworkflows.WorkflowRun) string {
+	var b strings.Builder
+	name := strings.TrimSpace(run.TemplateName)
+	if name == "" {
+		name = run.TemplateID
+	}
+	fmt.Fprintf(&b, "Workflow run %s (%s): %s", run.ID, name, run.Status)
+	if prompt := strings.TrimSpace(run.DisplayUserPrompt); prompt != "" {
+		b.WriteString("\nTask: " + prompt)
+	} else if prompt := strings.TrimSpace(run.UserPrompt); prompt != "" {
+		b.WriteString("\nTask: " + truncateMentionText(prompt, maxMentionBlockChars))
+	}
+	for _, phase := range run.Phases {
+		fmt.Fprintf(&b, "\n- phase %s: %s", phase.Name, phase.Status)
+		for _, step := range phase.Steps {
+			fmt.Fprintf(&b, "\n  - step %s: %s", step.Name, step.Status)
+			if outcome := strings.TrimSpace(step.Outcome); outcome != "" {
+				b.WriteString(" (" + outcome + ")")
+			}
+			if stepErr := strings.TrimSpace(step.Error); stepErr != "" {
+				b.WriteString(" error: " + stepErr)
+			}
+		}
+	}
+	if lastErr := strings.TrimSpace(run.LastError); lastErr != "" {
+		b.WriteString("\nLast error: " + lastErr)
+	}
+	return b.String()
+}
+
+// symbolMentionText quotes the source around a symbol definition.
+func symbolMentionText(mention types.InputMention) (string, error) {
+	path := strings.TrimSpace(mention.Path)
+	if !filepath.IsAbs(path) || mention.Line <= 0 {
+		return "", invalidError("symbol mention requires an absolute path and line", nil)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return "", invalidError("symbol source is not readable: "+path, err)
+	}
+	defer file.Close()
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSymbolIndexFileBytes)
+	for line := 1; scanner.Scan(); line++ {
+		if line < mention.Line {
+			continue
+		}
+		if line >= mention.Line+mentionSymbolContextLines {
+			break
+		}
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) == 0 {
+		return "", invalidError(fmt.Sprintf("symbol line %d is past the end of %s", mention.Line, path), nil)
+	}
+	return fmt.Sprintf("Symbol %s at %s:%d\n\n```\n%s\n```", mention.ID, path, mention.Line, strings.Join(lines, "\n")), nil
+}
+
+func truncateMentionText(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit]) + "…"
+}
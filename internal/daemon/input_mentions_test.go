@@ -0,0 +1,108 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"control/internal/daemon/transcriptdomain"
+	"control/internal/guidedworkflows"
+	"control/internal/types"
+)
+
+func TestExpandInputMentionsInlinesNoteRunAndSymbol(t *testing.T) {
+	ctx := context.Background()
+	stores := newNotesTestStores(t)
+	note, err := stores.Notes.Upsert(ctx, &types.Note{Kind: types.NoteKindNote, Scope: types.NoteScopeWorkspace, WorkspaceID: "ws1", Title: "plan", Body: "ship on friday"})
+	if err != nil {
+		t.Fatalf("seed note: %v", err)
+	}
+	stores.WorkflowRuns = &memoryWorkflowRunStore{snapshots: map[string]guidedworkflows.RunStatusSnapshot{
+		"run-1": {Run: &guidedworkflows.WorkflowRun{
+			ID:           "run-1",
+			TemplateName: "Bug fix",
+			Status:       guidedworkflows.WorkflowRunStatusRunning,
+			Phases: []guidedworkflows.PhaseRun{{
+				Name:  "implement",
+				Steps: []guidedworkflows.StepRun{{Name: "write tests", Status: guidedworkflows.StepRunStatusCompleted}},
+			}},
+		}},
+	}}
+	source := filepath.Join(t.TempDir(), "server.go")
+	if err := os.WriteFile(source, []byte("package main\n\nfunc Serve() {\n\treturn\n}\n"), 0o600); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	service := NewSessionService(nil, stores, nil)
+
+	input, err := service.expandInputMentions(ctx, []map[string]any{
+		{"type": "text", "text": "check @note:" + note.ID},
+		types.InputMention{Kind: types.InputMentionNote, ID: note.ID}.InputItem(),
+		types.InputMention{Kind: types.InputMentionRun, ID: "run-1"}.InputItem(),
+		types.InputMention{Kind: types.InputMentionSymbol, ID: "Serve", Path: source, Line: 3}.InputItem(),
+	})
+	if err != nil {
+		t.Fatalf("expandInputMentions: %v", err)
+	}
+	if len(input) != 4 {
+		t.Fatalf("expected 4 text items, got %#v", input)
+	}
+	for i, want := range []string{"ship on friday", "step write tests: completed", "func Serve() {"} {
+		item := input[i+1]
+		text, _ := item["text"].(string)
+		if item["type"] != "text" || !strings.Contains(text, want) || !strings.HasPrefix(text, "<archon-mention ref=") {
+			t.Fatalf("item %d: expected wrapped context containing %q, got %#v", i+1, want, item)
+		}
+	}
+	joined := input[0]["text"].(string) + "\n" + input[1]["text"].(string)
+	if got := types.CollapseMentionContext(joined); got != "check @note:"+note.ID {
+		t.Fatalf("expected expanded text to collapse to the typed message, got %q", got)
+	}
+}
+
+func TestExpandInputMentionsSummarizesSession(t *testing.T) {
+	ctx := context.Background()
+	manager := newTestManager(t)
+	manager.mu.Lock()
+	manager.sessions["s2"] = &sessionRuntime{session: &types.Session{ID: "s2", Provider: "codex", Status: types.SessionStatusInactive, Title: "refactor"}}
+	manager.mu.Unlock()
+	reader := &stubTranscriptSnapshotReader{snapshot: transcriptdomain.TranscriptSnapshot{Blocks: []transcriptdomain.Block{
+		{Role: "user", Text: "first question"},
+		{Role: "assistant", Text: "first answer"},
+		{Kind: "reasoning", Text: "thinking"},
+		{Role: "user", Text: "second question"},
+		{Role: "assistant", Text: "second answer"},
+	}}}
+	service := NewSessionService(manager, nil, nil, WithTranscriptSnapshotReader(reader))
+
+	input, err := service.expandInputMentions(ctx, []map[string]any{
+		types.InputMention{Kind: types.InputMentionSession, ID: "s2", Blocks: 2}.InputItem(),
+	})
+	if err != nil {
+		t.Fatalf("expandInputMentions: %v", err)
+	}
+	text, _ := input[0]["text"].(string)
+	if !strings.Contains(text, `Session "refactor"`) || !strings.Contains(text, "user: second question\n\nassistant: second answer") {
+		t.Fatalf("unexpected session summary: %q", text)
+	}
+	if strings.Contains(text, "first") {
+		t.Fatalf("expected only the last 2 blocks, got %q", text)
+	}
+}
+
+func TestExpandInputMentionsRejectsUnknownReferences(t *testing.T) {
+	service := NewSessionService(nil, newNotesTestStores(t), nil)
+	_, err := service.expandInputMentions(context.Background(), []map[string]any{
+		types.InputMention{Kind: types.InputMentionNote, ID: "missing"}.InputItem(),
+	})
+	if err == nil {
+		t.Fatalf("expected missing note to be rejected")
+	}
+	_, err = service.expandInputMentions(context.Background(), []map[string]any{
+		types.InputMention{Kind: types.InputMentionSymbol, ID: "Serve", Path: "relative.go", Line: 1}.InputItem(),
+	})
+	if err == nil {
+		t.Fatalf("expected relative symbol path to be rejected")
+	}
+}
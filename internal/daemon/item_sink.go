@@ -7,12 +7,19 @@ import (
 	"time"
 )
 
+// itemObserver is told about every persisted item, after subscribers have
+// received it.
+type itemObserver interface {
+	ObserveItem(item map[string]any)
+}
+
 type itemSink struct {
-	file    *os.File
-	mu      sync.Mutex
-	hub     *itemHub
-	metrics itemTimestampMetricsSink
-	debug   debugChunkSink
+	file     *os.File
+	mu       sync.Mutex
+	hub      *itemHub
+	metrics  itemTimestampMetricsSink
+	debug    debugChunkSink
+	observer itemObserver
 }
 
 func newItemSink(path string, hub *itemHub, metrics itemTimestampMetricsSink, debug debugChunkSink) (*itemSink, error) {
@@ -48,6 +55,9 @@ func (s *itemSink) Append(item map[string]any) {
 	if s.metrics != nil {
 		s.metrics.Record(classification)
 	}
+	if s.observer != nil {
+		s.observer.ObserveItem(prepared)
+	}
 }
 
 func (s *itemSink) Close() {
@@ -0,0 +1,286 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"control/internal/types"
+	"control/internal/workspacepaths"
+)
+
+// workspaceLinkedRepos returns the additional directories of the workspace
+// that are git repository roots. These are the workspace's linked repos:
+// worktrees of the workspace get a matching worktree in each of them and
+// diffs, commits and lifecycle operations span the whole set.
+func workspaceLinkedRepos(ctx context.Context, ws *types.Workspace) []string {
+	if ws == nil || len(ws.AdditionalDirectories) == 0 {
+		return nil
+	}
+	base, err := workspacepaths.ResolveSessionPath(ws.RepoPath, ws.SessionSubpath)
+	if err != nil {
+		return nil
+	}
+	dirs, err := workspacepaths.ResolveAdditionalDirectories(base, ws.AdditionalDirectories, nil)
+	if err != nil {
+		return nil
+	}
+	repo := canonicalScanPath(ws.RepoPath)
+	var out []string
+	for _, dir := range dirs {
+		canonical := canonicalScanPath(dir)
+		if canonical == repo {
+			continue
+		}
+		root, err := runGitCommand(ctx, dir, nil, "rev-parse", "--show-toplevel")
+		if err != nil || canonicalScanPath(root) != canonical {
+			continue
+		}
+		out = append(out, dir)
+	}
+	return out
+}
+
+// linkedRepoName is the prefix used for a linked repo's paths in combined
+// diffs and status listings.
+func linkedRepoName(repoPath string) string {
+	return filepath.Base(filepath.Clean(repoPath))
+}
+
+// linkedWorktreePath places a linked repo's worktree next to the primary
+// worktree at "<path>-<repo name>".
+func linkedWorktreePath(path, repoPath string) string {
+	return filepath.Clean(path) + "-" + linkedRepoName(repoPath)
+}
+
+func gitBranchExists(ctx context.Context, repoPath, branch string) bool {
+	_, err := runGitCommand(ctx, repoPath, nil, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	return err == nil
+}
+
+func gitRefExists(ctx context.Context, repoPath, ref string) bool {
+	_, err := runGitCommand(ctx, repoPath, nil, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	return err == nil
+}
+
+// createLinkedWorktrees checks branch out in a new worktree of every linked
+// repo, creating the branch from the repo's current commit when it does not
+// exist yet. On failure the worktrees created so far are removed again.
+func createLinkedWorktrees(ctx context.Context, repos []string, path, branch string) ([]types.LinkedWorktree, error) {
+	if len(repos) == 0 {
+		return nil, nil
+	}
+	if strings.TrimSpace(branch) == "" {
+		return nil, fmt.Errorf("a branch is required to create linked worktrees")
+	}
+	var created []types.LinkedWorktree
+	var newBranches []string
+	for _, repo := range repos {
+		linkedPath := linkedWorktreePath(path, repo)
+		var err error
+		if gitBranchExists(ctx, repo, branch) {
+			err = createGitWorktree(ctx, repo, linkedPath, branch)
+		} else {
+			err = createGitWorktreeBranch(ctx, repo, linkedPath, branch)
+			if err == nil {
+				newBranches = append(newBranches, repo)
+			}
+		}
+		if err != nil {
+			discardLinkedWorktrees(ctx, created, false)
+			for _, repo := range newBranches {
+				_ = deleteGitBranch(ctx, repo, branch, true)
+			}
+			return nil, fmt.Errorf("linked repo %s: %w", linkedRepoName(repo), err)
+		}
+		created = append(created, types.LinkedWorktree{RepoPath: repo, Path: linkedPath, Branch: branch})
+	}
+	return created, nil
+}
+
+// discardLinkedWorktrees force-removes linked worktrees, optionally deleting
+// their branches as well. Failures are ignored.
+func discardLinkedWorktrees(ctx context.Context, linked []types.LinkedWorktree, deleteBranches bool) {
+	for _, wt := range linked {
+		_ = removeGitWorktree(ctx, wt.RepoPath, wt.Path, true)
+		if deleteBranches && wt.Branch != "" {
+			_ = deleteGitBranch(ctx, wt.RepoPath, wt.Branch, true)
+		}
+	}
+}
+
+// linkedAdditionalDirectories resolves the workspace's additional
+// directories for a session in wt, substituting each linked repo with its
+// worktree.
+func linkedAdditionalDirectories(ws *types.Workspace, wt *types.Worktree) ([]string, error) {
+	base, err := workspacepaths.ResolveSessionPath(ws.RepoPath, ws.SessionSubpath)
+	if err != nil {
+		return nil, err
+	}
+	dirs, err := workspacepaths.ResolveAdditionalDirectories(base, ws.AdditionalDirectories, nil)
+	if err != nil {
+		return nil, err
+	}
+	linked := map[string]string{}
+	for _, entry := range wt.LinkedWorktrees {
+		linked[canonicalScanPath(entry.RepoPath)] = entry.Path
+	}
+	out := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		if path, ok := linked[canonicalScanPath(dir)]; ok {
+			dir = path
+		}
+		out = append(out, dir)
+	}
+	return out, nil
+}
+
+// worktreeLinkedRoots returns the checkouts of the linked repos that belong
+// with a session in wt, or in the workspace itself when wt is nil, named
+// after their repos.
+func worktreeLinkedRoots(ctx context.Context, ws *types.Workspace, wt *types.Worktree) []types.GitDiffRepo {
+	var roots []types.GitDiffRepo
+	if wt == nil {
+		for _, repo := range workspaceLinkedRepos(ctx, ws) {
+			roots = append(roots, types.GitDiffRepo{Name: linkedRepoName(repo), Root: repo})
+		}
+		return roots
+	}
+	for _, entry := range wt.LinkedWorktrees {
+		roots = append(roots, types.GitDiffRepo{Name: linkedRepoName(entry.RepoPath), Root: entry.Path})
+	}
+	return roots
+}
+
+// readLinkedGitDiff reads the diff of the repository at dir and merges in
+// the diffs of the linked repo checkouts.
+func readLinkedGitDiff(ctx context.Context, dir, base string, linked []types.GitDiffRepo) (*types.GitDiff, error) {
+	diff, err := readGitDiff(ctx, dir, base)
+	if err != nil {
+		return nil, err
+	}
+	for _, repo := range linked {
+		if canonicalScanPath(repo.Root) == canonicalScanPath(diff.Root) {
+			continue
+		}
+		linkedDiff, err := readGitDiff(ctx, repo.Root, base)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", repo.Name, err)
+		}
+		mergeLinkedGitDiff(diff, repo.Name, linkedDiff)
+	}
+	return diff, nil
+}
+
+func mergeLinkedGitDiff(diff *types.GitDiff, name string, linked *types.GitDiff) {
+	diff.Linked = append(diff.Linked, types.GitDiffRepo{Name: name, Root: linked.Root, BaseCommit: linked.BaseCommit})
+	for _, file := range linked.Files {
+		file.Path = name + "/" + file.Path
+		if file.OldPath != "" {
+			file.OldPath = name + "/" + file.OldPath
+		}
+		diff.Files = append(diff.Files, file)
+	}
+	for _, path := range linked.Untracked {
+		diff.Untracked = append(diff.Untracked, name+"/"+path)
+	}
+	diff.Stats.Files += linked.Stats.Files
+	diff.Stats.Additions += linked.Stats.Additions
+	diff.Stats.Deletions += linked.Stats.Deletions
+	diff.Stats.Untracked += linked.Stats.Untracked
+	diff.Truncated = diff.Truncated || linked.Truncated
+}
+
+// sessionLinkedRoots returns the linked repo checkouts of the session's
+// workspace or worktree.
+func (s *SessionService) sessionLinkedRoots(ctx context.Context, sessionID string) []types.GitDiffRepo {
+	meta := s.getSessionMeta(ctx, sessionID)
+	if meta == nil || strings.TrimSpace(meta.WorkspaceID) == "" {
+		return nil
+	}
+	ws, wt := s.sessionWorkspace(ctx, meta.WorkspaceID, meta.WorktreeID)
+	if ws == nil {
+		return nil
+	}
+	return worktreeLinkedRoots(ctx, ws, wt)
+}
+
+// sessionWorkspace looks up a workspace and, when worktreeID is set, one of
+// its worktrees. Missing records yield nil.
+func (s *SessionService) sessionWorkspace(ctx context.Context, workspaceID, worktreeID string) (*types.Workspace, *types.Worktree) {
+	if s.stores == nil || s.stores.Workspaces == nil || strings.TrimSpace(workspaceID) == "" {
+		return nil, nil
+	}
+	ws, ok, err := s.stores.Workspaces.Get(ctx, workspaceID)
+	if err != nil || !ok {
+		return nil, nil
+	}
+	if strings.TrimSpace(worktreeID) == "" || s.stores.Worktrees == nil {
+		return ws, nil
+	}
+	worktrees, err := s.stores.Worktrees.ListWorktrees(ctx, workspaceID)
+	if err != nil {
+		return ws, nil
+	}
+	for _, wt := range worktrees {
+		if wt != nil && wt.ID == worktreeID {
+			return ws, wt
+		}
+	}
+	return ws, nil
+}
+
+func newLinkedOperationResult(parent *types.WorktreeOperationResult, linked types.LinkedWorktree) *types.WorktreeOperationResult {
+	result := &types.WorktreeOperationResult{
+		Operation:   parent.Operation,
+		Status:      types.WorktreeOperationOK,
+		WorkspaceID: parent.WorkspaceID,
+		WorktreeID:  parent.WorktreeID,
+		Path:        linked.Path,
+		Branch:      linked.Branch,
+	}
+	parent.Linked = append(parent.Linked, result)
+	return result
+}
+
+// rollUpLinkedStatus reports the first non-ok status of a linked worktree on
+// the parent result when the primary worktree itself succeeded, and lists the
+// linked conflicts and dirty files there prefixed with their repo names.
+func rollUpLinkedStatus(result *types.WorktreeOperationResult, linked []types.LinkedWorktree) {
+	for i, entry := range result.Linked {
+		if i >= len(linked) {
+			break
+		}
+		name := linkedRepoName(linked[i].RepoPath)
+		for _, path := range entry.Conflicts {
+			result.Conflicts = append(result.Conflicts, name+"/"+path)
+		}
+		for _, path := range entry.DirtyFiles {
+			result.DirtyFiles = append(result.DirtyFiles, name+"/"+path)
+		}
+		if result.Status == types.WorktreeOperationOK && entry.Status != types.WorktreeOperationOK {
+			result.Status = entry.Status
+		}
+	}
+}
+
+// linkedDirtyFiles lists the uncommitted changes of existing linked
+// worktrees, prefixed with their repo names.
+func linkedDirtyFiles(ctx context.Context, linked []types.LinkedWorktree) ([]string, error) {
+	var dirty []string
+	for _, wt := range linked {
+		if _, err := os.Stat(wt.Path); err != nil {
+			continue
+		}
+		files, err := gitDirtyFiles(ctx, wt.Path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			dirty = append(dirty, linkedRepoName(wt.RepoPath)+"/"+file)
+		}
+	}
+	return dirty, nil
+}
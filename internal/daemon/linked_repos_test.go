@@ -0,0 +1,274 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"control/internal/types"
+)
+
+func TestWorkspaceLinkedReposWorktreeLifecycle(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	api := filepath.Join(root, "api")
+	client := filepath.Join(root, "client")
+	initCatalogTestRepo(t, api)
+	initCatalogTestRepo(t, client)
+	if err := ensureDir(filepath.Join(root, "notes")); err != nil {
+		t.Fatalf("ensureDir: %v", err)
+	}
+
+	ctx := context.Background()
+	stores := newTestStores(t)
+	service := NewWorkspaceService(stores)
+	ws, err := service.Create(ctx, &types.Workspace{
+		RepoPath:              api,
+		AdditionalDirectories: []string{"../client", "../notes"},
+	})
+	if err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	if repos := workspaceLinkedRepos(context.Background(), ws); len(repos) != 1 || repos[0] != client {
+		t.Fatalf("expected only the git repo to be linked, got %#v", repos)
+	}
+
+	path := filepath.Join(root, "feature")
+	wt, err := service.CreateWorktree(ctx, ws.ID, &CreateWorktreeRequest{Path: path})
+	if err != nil {
+		t.Fatalf("create worktree: %v", err)
+	}
+	if len(wt.LinkedWorktrees) != 1 {
+		t.Fatalf("expected a linked worktree, got %#v", wt.LinkedWorktrees)
+	}
+	linked := wt.LinkedWorktrees[0]
+	if linked.RepoPath != client || linked.Path != path+"-client" || linked.Branch != "feature" {
+		t.Fatalf("unexpected linked worktree %#v", linked)
+	}
+	if branch, err := gitCurrentBranch(context.Background(), linked.Path); err != nil || branch != "feature" {
+		t.Fatalf("expected linked worktree on branch feature, got %q (%v)", branch, err)
+	}
+
+	sessions := &SessionService{stores: stores}
+	dirs, err := sessions.resolveAdditionalDirectoriesForWorkspace(path, ws, wt)
+	if err != nil {
+		t.Fatalf("resolve additional directories: %v", err)
+	}
+	if len(dirs) != 2 || dirs[0] != linked.Path || dirs[1] != filepath.Join(root, "notes") {
+		t.Fatalf("expected the linked worktree in place of the linked repo, got %#v", dirs)
+	}
+
+	writeTestFile(t, filepath.Join(path, "README.md"), "api change\n")
+	writeTestFile(t, filepath.Join(linked.Path, "client.go"), "package client\n")
+	diff, err := service.WorktreeDiff(ctx, wt.ID, "")
+	if err != nil {
+		t.Fatalf("worktree diff: %v", err)
+	}
+	if len(diff.Files) != 1 || diff.Files[0].Path != "README.md" {
+		t.Fatalf("unexpected primary changes %#v", diff.Files)
+	}
+	if len(diff.Untracked) != 1 || diff.Untracked[0] != "client/client.go" || len(diff.Linked) != 1 || diff.Linked[0].Name != "client" {
+		t.Fatalf("expected linked changes to be merged into the diff, got %#v %#v", diff.Untracked, diff.Linked)
+	}
+
+	result, err := service.RemoveWorktree(ctx, ws.ID, wt.ID, types.WorktreeOperationRequest{})
+	if err != nil {
+		t.Fatalf("remove worktree: %v", err)
+	}
+	if result.Status != types.WorktreeOperationDirty || len(result.DirtyFiles) != 2 || result.DirtyFiles[1] != "client/client.go" {
+		t.Fatalf("expected dirty files across the set, got %#v", result)
+	}
+	result, err = service.RemoveWorktree(ctx, ws.ID, wt.ID, types.WorktreeOperationRequest{Force: true})
+	if err != nil {
+		t.Fatalf("force remove worktree: %v", err)
+	}
+	if result.Status != types.WorktreeOperationOK || len(result.Linked) != 1 {
+		t.Fatalf("unexpected remove result %#v", result)
+	}
+	for _, dir := range []string{path, linked.Path} {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Fatalf("expected %s to be removed, got %v", dir, err)
+		}
+	}
+}
+
+func TestLinkedWorktreeMergeAndRebaseTargets(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	api := filepath.Join(root, "api")
+	client := filepath.Join(root, "client")
+	initCatalogTestRepo(t, api)
+	initCatalogTestRepo(t, client)
+	runTestGit(t, client, "checkout", "-q", "-b", "trunk")
+
+	ctx := context.Background()
+	service := NewWorkspaceService(newTestStores(t))
+	ws, err := service.Create(ctx, &types.Workspace{RepoPath: api, AdditionalDirectories: []string{"../client"}})
+	if err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	path := filepath.Join(root, "feature")
+	wt, err := service.CreateWorktree(ctx, ws.ID, &CreateWorktreeRequest{Path: path})
+	if err != nil {
+		t.Fatalf("create worktree: %v", err)
+	}
+	linked := wt.LinkedWorktrees[0]
+	commit := func(dir, name string) {
+		writeTestFile(t, filepath.Join(dir, name), name+"\n")
+		runTestGit(t, dir, "add", name)
+		runTestGit(t, dir, "-c", "user.name=archon", "-c", "user.email=archon@example.com", "-c", "commit.gpgsign=false", "commit", "-q", "-m", name)
+	}
+	commit(path, "api.txt")
+	commit(linked.Path, "client.txt")
+
+	rebase, err := service.RebaseWorktree(ctx, ws.ID, wt.ID, types.WorktreeOperationRequest{Target: "main"})
+	if err != nil {
+		t.Fatalf("rebase: %v", err)
+	}
+	if rebase.Status != types.WorktreeOperationOK || len(rebase.Linked) != 1 || rebase.Linked[0].Target != "trunk" {
+		t.Fatalf("expected the linked worktree to rebase onto its repo's branch, got %#v", rebase.Linked)
+	}
+	rebase, err = service.RebaseWorktree(ctx, ws.ID, wt.ID, types.WorktreeOperationRequest{LinkedTargets: map[string]string{"client": "main"}})
+	if err != nil {
+		t.Fatalf("rebase with linked target: %v", err)
+	}
+	if rebase.Status != types.WorktreeOperationOK || rebase.Linked[0].Target != "main" {
+		t.Fatalf("expected the per-repo target to be used, got %#v", rebase.Linked)
+	}
+
+	if _, err := service.MergeWorktree(ctx, ws.ID, wt.ID, types.WorktreeOperationRequest{LinkedTargets: map[string]string{"client": "main"}}); err == nil {
+		t.Fatalf("expected a merge target that is not checked out to be refused")
+	}
+	writeTestFile(t, filepath.Join(client, "dirty.txt"), "dirty\n")
+	merge, err := service.MergeWorktree(ctx, ws.ID, wt.ID, types.WorktreeOperationRequest{})
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if merge.Status != types.WorktreeOperationDirty || len(merge.DirtyFiles) != 1 || merge.DirtyFiles[0] != "client/dirty.txt" {
+		t.Fatalf("expected the dirty linked repo to refuse the merge, got %#v", merge)
+	}
+	if _, err := os.Stat(filepath.Join(api, "api.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected the primary repo to be left untouched, got %v", err)
+	}
+
+	if err := os.Remove(filepath.Join(client, "dirty.txt")); err != nil {
+		t.Fatalf("remove dirty file: %v", err)
+	}
+	merge, err = service.MergeWorktree(ctx, ws.ID, wt.ID, types.WorktreeOperationRequest{})
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if merge.Status != types.WorktreeOperationOK || merge.Linked[0].Target != "trunk" {
+		t.Fatalf("unexpected merge result %#v", merge)
+	}
+	for _, file := range []string{filepath.Join(api, "api.txt"), filepath.Join(client, "client.txt")} {
+		if _, err := os.Stat(file); err != nil {
+			t.Fatalf("expected %s to be merged: %v", file, err)
+		}
+	}
+}
+
+func TestFailLinkedOperationKeepsPartialResult(t *testing.T) {
+	result := &types.WorktreeOperationResult{Operation: types.WorktreeOperationMerge, Status: types.WorktreeOperationOK}
+	linked := []types.LinkedWorktree{{RepoPath: "/src/client", Path: "/src/feature-client", Branch: "feature"}}
+	failLinkedOperation(newLinkedOperationResult(result, linked[0]), errors.New("merge failed"))
+	rollUpLinkedStatus(result, linked)
+	if result.Status != types.WorktreeOperationFailed || result.Linked[0].Output != "merge failed" {
+		t.Fatalf("expected the failure to be reported on the result, got %#v", result)
+	}
+}
+
+func TestCommitLinkedFinalizeSkipsCleanRepos(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	changed := filepath.Join(root, "client")
+	clean := filepath.Join(root, "docs")
+	initCatalogTestRepo(t, changed)
+	initCatalogTestRepo(t, clean)
+	runTestGit(t, changed, "config", "user.name", "archon")
+	runTestGit(t, changed, "config", "user.email", "archon@example.com")
+	runTestGit(t, changed, "config", "commit.gpgsign", "false")
+	writeTestFile(t, filepath.Join(changed, "client.go"), "package client\n")
+
+	commits, err := commitLinkedFinalize(context.Background(), []types.GitDiffRepo{
+		{Name: "client", Root: changed},
+		{Name: "docs", Root: clean},
+	}, "feat: add client")
+	if err != nil {
+		t.Fatalf("commit linked: %v", err)
+	}
+	if len(commits) != 1 || commits[0].Name != "client" || commits[0].Commit == "" || commits[0].Branch != "main" {
+		t.Fatalf("unexpected linked commits %#v", commits)
+	}
+	if len(commits[0].Files) != 1 || commits[0].Files[0] != "client.go" {
+		t.Fatalf("unexpected committed files %#v", commits[0].Files)
+	}
+}
+
+func TestLinkedWorktreePushAndRemoveKeepPartialResults(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	api := filepath.Join(root, "api")
+	client := filepath.Join(root, "client")
+	initCatalogTestRepo(t, api)
+	initCatalogTestRepo(t, client)
+	origin := filepath.Join(root, "origin.git")
+	runTestGit(t, root, "init", "-q", "--bare", origin)
+	runTestGit(t, api, "remote", "add", "origin", origin)
+
+	ctx := context.Background()
+	service := NewWorkspaceService(newTestStores(t))
+	ws, err := service.Create(ctx, &types.Workspace{RepoPath: api, AdditionalDirectories: []string{"../client"}})
+	if err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	path := filepath.Join(root, "feature")
+	wt, err := service.CreateWorktree(ctx, ws.ID, &CreateWorktreeRequest{Path: path})
+	if err != nil {
+		t.Fatalf("create worktree: %v", err)
+	}
+
+	// The client repo has no origin, so its push fails after the api branch
+	// was pushed.
+	result, err := service.PushWorktree(ctx, ws.ID, wt.ID, types.WorktreeOperationRequest{})
+	if err != nil {
+		t.Fatalf("push worktree: %v", err)
+	}
+	if result.Status != types.WorktreeOperationFailed || len(result.Linked) != 1 || result.Linked[0].Status != types.WorktreeOperationFailed {
+		t.Fatalf("expected the linked push failure on the result, got %#v", result)
+	}
+	runTestGit(t, origin, "rev-parse", "--verify", "refs/heads/feature")
+
+	// A linked worktree git no longer recognizes cannot be removed.
+	if err := os.Remove(filepath.Join(wt.LinkedWorktrees[0].Path, ".git")); err != nil {
+		t.Fatalf("remove linked .git: %v", err)
+	}
+	result, err = service.RemoveWorktree(ctx, ws.ID, wt.ID, types.WorktreeOperationRequest{Force: true})
+	if err != nil {
+		t.Fatalf("remove worktree: %v", err)
+	}
+	if result.Status != types.WorktreeOperationFailed || len(result.Linked) != 1 || result.Linked[0].Output == "" {
+		t.Fatalf("expected the linked remove failure on the result, got %#v", result)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected the primary worktree to be removed, got %v", err)
+	}
+	worktrees, err := service.ListWorktrees(ctx, ws.ID)
+	if err != nil {
+		t.Fatalf("list worktrees: %v", err)
+	}
+	if len(worktrees) != 0 {
+		t.Fatalf("expected the removed worktree to be forgotten, got %#v", worktrees)
+	}
+}
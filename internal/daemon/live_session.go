@@ -28,3 +28,9 @@ type NotifiableSession interface {
 	LiveSession
 	SetNotificationPublisher(notifier NotificationPublisher)
 }
+
+// UsageReportingSession is a live session whose provider reports token usage.
+type UsageReportingSession interface {
+	LiveSession
+	SetUsageRecorder(recorder SessionUsageRecorder)
+}
@@ -0,0 +1,154 @@
+package daemon
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"control/internal/types"
+)
+
+const notificationDigestMaxEntries = 500
+
+type notificationDigestEntry struct {
+	SessionID   string `json:"session_id"`
+	Title       string `json:"title,omitempty"`
+	Provider    string `json:"provider,omitempty"`
+	WorkspaceID string `json:"workspace_id,omitempty"`
+	WorktreeID  string `json:"worktree_id,omitempty"`
+	Count       int    `json:"count"`
+	LastAt      string `json:"last_at"`
+}
+
+// notificationDigest accumulates what completed, failed or is waiting on the
+// operator since the last digest, keyed by session, and produces one summary
+// event per day at the configured local time.
+type notificationDigest struct {
+	mu        sync.Mutex
+	minute    int
+	since     time.Time
+	lastSent  string
+	completed map[string]*notificationDigestEntry
+	failed    map[string]*notificationDigestEntry
+	awaiting  map[string]*notificationDigestEntry
+}
+
+// newNotificationDigest returns nil when clock is not a valid "HH:MM" time. A
+// daemon started after today's digest time waits for tomorrow's.
+func newNotificationDigest(clock string, now time.Time) *notificationDigest {
+	minute, ok := types.ParseNotificationClock(clock)
+	if !ok {
+		return nil
+	}
+	d := &notificationDigest{minute: minute}
+	d.reset(now)
+	if localMinute(now) >= minute {
+		d.lastSent = now.Format(time.DateOnly)
+	}
+	return d
+}
+
+func (d *notificationDigest) reset(now time.Time) {
+	d.since = now
+	d.completed = map[string]*notificationDigestEntry{}
+	d.failed = map[string]*notificationDigestEntry{}
+	d.awaiting = map[string]*notificationDigestEntry{}
+}
+
+func (d *notificationDigest) Record(event types.NotificationEvent, now time.Time) {
+	if d == nil || strings.TrimSpace(event.SessionID) == "" {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch event.Trigger {
+	case types.NotificationTriggerTurnCompleted:
+		delete(d.awaiting, event.SessionID)
+		if classifyTurnOutcome(event.Status, "").Failed {
+			d.add(d.failed, event, now)
+			return
+		}
+		d.add(d.completed, event, now)
+	case types.NotificationTriggerSessionFailed:
+		delete(d.awaiting, event.SessionID)
+		d.add(d.failed, event, now)
+	case types.NotificationTriggerApprovalPending, types.NotificationTriggerWorkflowDecisionNeeded:
+		d.add(d.awaiting, event, now)
+	case types.NotificationTriggerApprovalResolved:
+		if entry, ok := d.awaiting[event.SessionID]; ok {
+			entry.Count--
+			if entry.Count <= 0 {
+				delete(d.awaiting, event.SessionID)
+			}
+		}
+	}
+}
+
+func (d *notificationDigest) add(entries map[string]*notificationDigestEntry, event types.NotificationEvent, now time.Time) {
+	entry, ok := entries[event.SessionID]
+	if !ok {
+		if len(entries) >= notificationDigestMaxEntries {
+			return
+		}
+		entry = &notificationDigestEntry{SessionID: event.SessionID}
+		entries[event.SessionID] = entry
+	}
+	entry.Title = firstNonEmpty(event.Title, entry.Title)
+	entry.Provider = firstNonEmpty(event.Provider, entry.Provider)
+	entry.WorkspaceID = firstNonEmpty(event.WorkspaceID, entry.WorkspaceID)
+	entry.WorktreeID = firstNonEmpty(event.WorktreeID, entry.WorktreeID)
+	entry.Count++
+	entry.LastAt = now.UTC().Format(time.RFC3339Nano)
+}
+
+// Take returns the digest event when it is due and resets the accumulated
+// state. Days with nothing to report are skipped.
+func (d *notificationDigest) Take(now time.Time) (types.NotificationEvent, bool) {
+	if d == nil {
+		return types.NotificationEvent{}, false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	today := now.Format(time.DateOnly)
+	if d.lastSent == today || localMinute(now) < d.minute {
+		return types.NotificationEvent{}, false
+	}
+	d.lastSent = today
+	if len(d.completed) == 0 && len(d.failed) == 0 && len(d.awaiting) == 0 {
+		d.reset(now)
+		return types.NotificationEvent{}, false
+	}
+	event := types.NotificationEvent{
+		Trigger:    types.NotificationTriggerDigest,
+		OccurredAt: now.UTC().Format(time.RFC3339Nano),
+		Source:     "notification_digest:" + today,
+		Payload: map[string]any{
+			"kind":           "notification_digest",
+			"since":          d.since.UTC().Format(time.RFC3339Nano),
+			"completed":      sortedNotificationDigestEntries(d.completed),
+			"failed":         sortedNotificationDigestEntries(d.failed),
+			"awaiting_input": sortedNotificationDigestEntries(d.awaiting),
+		},
+	}
+	d.reset(now)
+	return event, true
+}
+
+func sortedNotificationDigestEntries(entries map[string]*notificationDigestEntry) []notificationDigestEntry {
+	out := make([]notificationDigestEntry, 0, len(entries))
+	for _, entry := range entries {
+		out = append(out, *entry)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].LastAt != out[j].LastAt {
+			return out[i].LastAt < out[j].LastAt
+		}
+		return out[i].SessionID < out[j].SessionID
+	})
+	return out
+}
+
+func localMinute(at time.Time) int {
+	return at.Hour()*60 + at.Minute()
+}
@@ -178,5 +178,6 @@ func defaultNotificationSinks() []NotificationSink {
 		dunstifySink{},
 		notifySendSink{},
 		bellSink{},
+		webhookSink{},
 	}
 }
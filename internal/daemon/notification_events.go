@@ -3,6 +3,7 @@ package daemon
 import (
 	"context"
 	"encoding/json"
+	"strconv"
 	"strings"
 	"time"
 
@@ -95,6 +96,18 @@ func notificationTitleBody(event types.NotificationEvent) (string, string) {
 	if provider == "" {
 		provider = "unknown"
 	}
+	switch event.Trigger {
+	case types.NotificationTriggerBatch:
+		count, _ := asInt(event.Payload["count"])
+		return "Archon: " + strconv.Itoa(count) + " notifications", strings.TrimSpace(event.Status)
+	case types.NotificationTriggerDigest:
+		parts := []string{
+			"completed: " + strconv.Itoa(notificationDigestCount(event.Payload, "completed")),
+			"failed: " + strconv.Itoa(notificationDigestCount(event.Payload, "failed")),
+			"awaiting input: " + strconv.Itoa(notificationDigestCount(event.Payload, "awaiting_input")),
+		}
+		return "Archon daily digest", strings.Join(parts, " | ")
+	}
 	if isGuidedWorkflowDecisionNotification(event) {
 		summary := "Archon workflow decision needed"
 		reason := notificationPayloadString(event.Payload, "reason")
@@ -140,6 +153,21 @@ func notificationTitleBody(event types.NotificationEvent) (string, string) {
 	case types.NotificationTriggerSessionExited:
 		summary = "Archon session exited"
 		body = name + " (" + provider + ")"
+	case types.NotificationTriggerProviderRateLimited:
+		summary = "Archon provider rate limited"
+		body = name + " (" + provider + ")"
+		if limitType := notificationPayloadString(event.Payload, "limit_type"); limitType != "" {
+			body += " | limit: " + limitType
+		}
+		if retryAt := notificationPayloadString(event.Payload, "retry_at"); retryAt != "" {
+			body += " | retry at: " + retryAt
+		}
+	case types.NotificationTriggerTurnLongRunning:
+		summary = "Archon turn still running"
+		body = name + " (" + provider + ")"
+		if minutes, ok := asInt(event.Payload["running_minutes"]); ok && minutes > 0 {
+			body += " | running for " + strconv.Itoa(minutes) + "m"
+		}
 	default:
 		summary = "Archon notification"
 		body = name + " (" + provider + ")"
@@ -151,6 +179,9 @@ func notificationTitleBody(event types.NotificationEvent) (string, string) {
 }
 
 func isGuidedWorkflowDecisionNotification(event types.NotificationEvent) bool {
+	if event.Trigger == types.NotificationTriggerWorkflowDecisionNeeded {
+		return true
+	}
 	if !strings.EqualFold(strings.TrimSpace(event.Status), "decision_needed") {
 		return false
 	}
@@ -158,12 +189,26 @@ func isGuidedWorkflowDecisionNotification(event types.NotificationEvent) bool {
 }
 
 func isApprovalRequiredNotification(event types.NotificationEvent) bool {
+	if event.Trigger == types.NotificationTriggerApprovalPending {
+		return true
+	}
 	if !strings.EqualFold(strings.TrimSpace(event.Status), "approval_required") {
 		return false
 	}
 	return strings.HasPrefix(strings.TrimSpace(event.Source), "approval_request:")
 }
 
+func notificationDigestCount(payload map[string]any, key string) int {
+	switch entries := payload[key].(type) {
+	case []notificationDigestEntry:
+		return len(entries)
+	case []any:
+		return len(entries)
+	default:
+		return 0
+	}
+}
+
 func notificationPayloadString(payload map[string]any, key string) string {
 	if len(payload) == 0 {
 		return ""
@@ -201,6 +246,7 @@ func normalizeNotificationEvent(event types.NotificationEvent) types.Notificatio
 	event.TurnID = strings.TrimSpace(event.TurnID)
 	event.Cwd = strings.TrimSpace(event.Cwd)
 	event.Source = strings.TrimSpace(event.Source)
+	event.TraceParent = strings.TrimSpace(event.TraceParent)
 	return event
 }
 
@@ -126,5 +126,25 @@ func notificationDefaultsFromCoreConfig(cfg config.CoreConfig) types.Notificatio
 	out.ScriptCommands = cfg.NotificationScriptCommands()
 	out.ScriptTimeoutSeconds = cfg.NotificationScriptTimeoutSeconds()
 	out.DedupeWindowSeconds = cfg.NotificationDedupeWindowSeconds()
+	for _, webhook := range cfg.NotificationWebhooks() {
+		out.Webhooks = append(out.Webhooks, types.NotificationWebhook{
+			URL:       webhook.URL,
+			Format:    types.NotificationWebhookFormat(webhook.Format),
+			Headers:   webhook.Headers,
+			Secret:    webhook.Secret,
+			SecretEnv: webhook.SecretEnv,
+		})
+	}
+	out.WebhookRetries = cfg.NotificationWebhookRetries()
+	out.WebhookTimeoutSeconds = cfg.NotificationWebhookTimeoutSeconds()
+	out.QuietHoursStart, out.QuietHoursEnd = cfg.NotificationQuietHours()
+	for _, limit := range cfg.NotificationRateLimits() {
+		out.RateLimits = append(out.RateLimits, types.NotificationRateLimit{
+			Trigger:       types.NotificationTrigger(limit.Trigger),
+			Max:           limit.Max,
+			WindowSeconds: limit.WindowSeconds,
+		})
+	}
+	out.BatchWindowSeconds = cfg.NotificationBatchWindowSeconds()
 	return types.NormalizeNotificationSettings(out)
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"control/internal/logging"
+	"control/internal/tracing"
 	"control/internal/types"
 )
 
@@ -15,20 +16,45 @@ type queuedNotification struct {
 	event types.NotificationEvent
 }
 
+// queuedWebhookDelivery is an event waiting for the webhook worker; settings
+// only list the webhook method.
+type queuedWebhookDelivery struct {
+	ctx      context.Context
+	event    types.NotificationEvent
+	settings types.NotificationSettings
+}
+
+const (
+	// notificationStopFlushTimeout bounds how long stopping the service waits
+	// for pending batches and webhook deliveries.
+	notificationStopFlushTimeout = 10 * time.Second
+	// notificationWebhookQueueSize bounds the events waiting for the webhook
+	// worker; further events skip their webhooks.
+	notificationWebhookQueueSize = 16
+)
+
 type NotificationService struct {
 	resolver   NotificationPolicyResolver
 	dispatcher NotificationDispatcher
 	dedupe     NotificationDedupePolicy
 	logger     logging.Logger
+	limiter    *notificationRateLimiter
+	batcher    *notificationBatcher
+	now        func() time.Time
+	tracing    *daemonTracing
 
-	mu       sync.RWMutex
-	events   chan queuedNotification
-	runCtx   context.Context
-	cancel   context.CancelFunc
-	wg       sync.WaitGroup
-	started  bool
-	stopping bool
-	closed   bool
+	mu          sync.RWMutex
+	digest      *notificationDigest
+	events      chan queuedNotification
+	webhooks    chan queuedWebhookDelivery
+	webhookStop chan context.Context
+	runCtx      context.Context
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+	webhookWG   sync.WaitGroup
+	started     bool
+	stopping    bool
+	closed      bool
 }
 
 func NewNotificationService(resolver NotificationPolicyResolver, dispatcher NotificationDispatcher, logger logging.Logger) *NotificationService {
@@ -40,12 +66,38 @@ func NewNotificationService(resolver NotificationPolicyResolver, dispatcher Noti
 		dispatcher: dispatcher,
 		dedupe:     newWindowNotificationDedupePolicy(),
 		logger:     logger,
+		limiter:    newNotificationRateLimiter(),
+		now:        time.Now,
 		events:     make(chan queuedNotification, 256),
+		webhooks:   make(chan queuedWebhookDelivery, notificationWebhookQueueSize),
 	}
+	svc.batcher = newNotificationBatcher(svc.flushBatch)
 	svc.Start()
 	return svc
 }
 
+// SetDigestTime enables the end-of-day digest at clock ("HH:MM", local time).
+// An empty or invalid clock disables it.
+func (s *NotificationService) SetDigestTime(clock string) {
+	if s == nil {
+		return
+	}
+	digest := newNotificationDigest(clock, s.now())
+	s.mu.Lock()
+	s.digest = digest
+	s.mu.Unlock()
+}
+
+// SetTracing opens notification dispatch spans on t.
+func (s *NotificationService) SetTracing(t *daemonTracing) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.tracing = t
+	s.mu.Unlock()
+}
+
 func (s *NotificationService) Start() {
 	if s == nil {
 		return
@@ -59,6 +111,11 @@ func (s *NotificationService) Start() {
 	s.started = true
 	s.wg.Add(1)
 	go s.run(s.runCtx)
+	if s.webhookStop == nil {
+		s.webhookStop = make(chan context.Context)
+		s.webhookWG.Add(1)
+		go s.runWebhooks(s.webhookStop)
+	}
 }
 
 func (s *NotificationService) Stop(ctx context.Context) error {
@@ -89,10 +146,51 @@ func (s *NotificationService) Stop(ctx context.Context) error {
 
 	select {
 	case <-done:
-		s.mu.Lock()
-		s.stopping = false
-		s.started = false
-		s.mu.Unlock()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	flushCtx, cancelFlush := context.WithTimeout(ctx, notificationStopFlushTimeout)
+	defer cancelFlush()
+	s.flushPendingBatches(flushCtx)
+	if err := s.stopWebhooks(flushCtx); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.stopping = false
+	s.started = false
+	s.mu.Unlock()
+	return nil
+}
+
+// flushPendingBatches delivers batched notifications that are still waiting
+// for their window.
+func (s *NotificationService) flushPendingBatches(ctx context.Context) {
+	if flushed := s.batcher.FlushAll(ctx); flushed > 0 && s.logger != nil {
+		s.logger.Debug("notification_batch_flushed_on_stop", logging.F("events", flushed))
+	}
+}
+
+// stopWebhooks lets the webhook worker deliver what is queued under ctx and
+// waits for it to exit.
+func (s *NotificationService) stopWebhooks(ctx context.Context) error {
+	s.mu.RLock()
+	stop := s.webhookStop
+	s.mu.RUnlock()
+	if stop == nil {
+		return nil
+	}
+	select {
+	case stop <- ctx:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	done := make(chan struct{})
+	go func() {
+		s.webhookWG.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
 		return nil
 	case <-ctx.Done():
 		return ctx.Err()
@@ -135,18 +233,90 @@ func (s *NotificationService) Publish(event types.NotificationEvent) {
 	}
 }
 
+// QueueDepth reports how many events are waiting to be dispatched.
+func (s *NotificationService) QueueDepth() int {
+	if s == nil {
+		return 0
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.events)
+}
+
 func (s *NotificationService) run(runCtx context.Context) {
 	defer s.wg.Done()
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-runCtx.Done():
 			return
 		case queued := <-s.events:
 			s.handle(queued.ctx, queued.event)
+		case <-ticker.C:
+			s.sendDigest(runCtx)
 		}
 	}
 }
 
+// runWebhooks delivers queued webhook notifications one at a time, so slow or
+// throttling endpoints never hold up the run loop and the other sinks.
+func (s *NotificationService) runWebhooks(stop <-chan context.Context) {
+	defer s.webhookWG.Done()
+	for {
+		select {
+		case delivery := <-s.webhooks:
+			s.deliverWebhooks(context.Background(), delivery)
+		case ctx := <-stop:
+			for {
+				select {
+				case delivery := <-s.webhooks:
+					s.deliverWebhooks(ctx, delivery)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// deliverWebhooks posts one event to its webhooks within
+// notificationWebhookDeliveryTimeout, keeping the trace of the dispatch that
+// queued it.
+func (s *NotificationService) deliverWebhooks(ctx context.Context, delivery queuedWebhookDelivery) {
+	ctx = tracing.ContextWithSpanContext(ctx, tracing.SpanContextFromContext(delivery.ctx))
+	ctx, cancel := context.WithTimeout(ctx, notificationWebhookDeliveryTimeout)
+	defer cancel()
+	if err := s.dispatcher.Dispatch(ctx, delivery.event, delivery.settings); err != nil && s.logger != nil {
+		s.logger.Warn("notification_webhook_failed",
+			logging.F("trigger", delivery.event.Trigger),
+			logging.F("session_id", delivery.event.SessionID),
+			logging.F("error", err),
+		)
+	}
+}
+
+// queueWebhooks hands event to the webhook worker. A full queue drops the
+// webhooks of the event rather than block dispatching.
+func (s *NotificationService) queueWebhooks(ctx context.Context, event types.NotificationEvent, settings types.NotificationSettings) {
+	select {
+	case s.webhooks <- queuedWebhookDelivery{ctx: ctx, event: event, settings: settings}:
+	default:
+		if s.logger != nil {
+			s.logger.Warn("notification_webhook_queue_full",
+				logging.F("trigger", event.Trigger),
+				logging.F("session_id", event.SessionID),
+			)
+		}
+	}
+}
+
+func (s *NotificationService) currentDigest() *notificationDigest {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.digest
+}
+
 func (s *NotificationService) handle(ctx context.Context, event types.NotificationEvent) {
 	if s == nil || s.resolver == nil || s.dispatcher == nil {
 		return
@@ -159,6 +329,12 @@ func (s *NotificationService) handle(ctx context.Context, event types.Notificati
 		ctx = context.Background()
 	}
 
+	now := s.now()
+	s.currentDigest().Record(event, now)
+	if event.Trigger == types.NotificationTriggerApprovalResolved {
+		return
+	}
+
 	settings := s.resolver.Resolve(ctx, event)
 	if !settings.Enabled {
 		return
@@ -169,20 +345,154 @@ func (s *NotificationService) handle(ctx context.Context, event types.Notificati
 	if s.shouldSuppress(event, settings) {
 		return
 	}
+	if types.NotificationQuietHoursActive(settings, now) {
+		if s.logger != nil {
+			s.logger.Debug("notification_quiet_hours",
+				logging.F("trigger", event.Trigger),
+				logging.F("session_id", event.SessionID),
+			)
+		}
+		return
+	}
+	if !s.limiter.Allow(event, settings, now) {
+		if s.logger != nil {
+			s.logger.Debug("notification_rate_limited",
+				logging.F("trigger", event.Trigger),
+				logging.F("session_id", event.SessionID),
+			)
+		}
+		return
+	}
+	if settings.BatchWindowSeconds > 0 {
+		s.batcher.Add(event, settings)
+		return
+	}
+	s.dispatch(ctx, event, settings)
+}
 
-	timeout := time.Duration(settings.ScriptTimeoutSeconds+2) * time.Second
-	if timeout < 5*time.Second {
-		timeout = 5 * time.Second
+// flushBatch delivers a closed batch window. Batches are flushed from timer
+// goroutines, so they dispatch with a background context.
+func (s *NotificationService) flushBatch(ctx context.Context, events []types.NotificationEvent, settings types.NotificationSettings) {
+	if len(events) == 0 {
+		return
+	}
+	event := events[0]
+	if len(events) > 1 {
+		event = notificationBatchEvent(events)
+	}
+	s.dispatch(ctx, event, settings)
+}
+
+// sendDigest delivers the daily digest when it is due, using the settings
+// that apply outside any session. Quiet hours and trigger filters do not
+// apply; the digest time is chosen explicitly.
+func (s *NotificationService) sendDigest(ctx context.Context) {
+	if s == nil || s.resolver == nil || s.dispatcher == nil {
+		return
+	}
+	event, ok := s.currentDigest().Take(s.now())
+	if !ok {
+		return
+	}
+	settings := s.resolver.Resolve(ctx, event)
+	if !settings.Enabled {
+		return
+	}
+	s.dispatch(ctx, event, settings)
+}
+
+func (s *NotificationService) dispatch(ctx context.Context, event types.NotificationEvent, settings types.NotificationSettings) {
+	s.mu.RLock()
+	tracer := s.tracing
+	s.mu.RUnlock()
+	ctx, span := tracer.Start(tracing.ContextWithTraceparent(ctx, event.TraceParent), "notification.dispatch",
+		tracing.String("notification.trigger", string(event.Trigger)),
+		tracing.String("session.id", event.SessionID),
+	)
+	defer span.End()
+	settings, webhooks, ok := splitNotificationWebhooks(settings)
+	if ok {
+		s.queueWebhooks(ctx, event, webhooks)
+		if len(settings.Methods) == 0 && len(settings.ScriptCommands) == 0 {
+			return
+		}
+	}
+	dispatchCtx, cancel := context.WithTimeout(ctx, notificationDispatchTimeout(settings))
+	defer cancel()
+	if err := s.dispatcher.Dispatch(dispatchCtx, event, settings); err != nil {
+		span.RecordError(err)
+		if s.logger != nil {
+			s.logger.Warn("notification_dispatch_failed",
+				logging.F("trigger", event.Trigger),
+				logging.F("session_id", event.SessionID),
+				logging.F("error", err),
+			)
+		}
+	}
+}
+
+// SendTestNotification resolves settings for the requested scope and delivers
+// a sample event synchronously, bypassing trigger filters and dedupe so the
+// caller sees delivery errors directly.
+func (s *NotificationService) SendTestNotification(ctx context.Context, req types.NotificationTestRequest) (types.NotificationTestResult, error) {
+	if s == nil || s.resolver == nil || s.dispatcher == nil {
+		return types.NotificationTestResult{}, unavailableError("notifications not available", nil)
+	}
+	trigger := types.NotificationTriggerTurnCompleted
+	if raw := strings.TrimSpace(string(req.Trigger)); raw != "" {
+		normalized, ok := types.NormalizeNotificationTrigger(raw)
+		if !ok {
+			return types.NotificationTestResult{}, invalidError("unknown notification trigger: "+raw, nil)
+		}
+		trigger = normalized
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	event := normalizeNotificationEvent(types.NotificationEvent{
+		Trigger:     trigger,
+		SessionID:   req.SessionID,
+		WorkspaceID: req.WorkspaceID,
+		WorktreeID:  req.WorktreeID,
+		Provider:    "archon",
+		Title:       "Archon test notification",
+		Status:      "test",
+		Source:      "notification_test",
+		TraceParent: tracing.TraceparentFromContext(ctx),
+	})
+	settings := s.resolver.Resolve(ctx, event)
+	result := types.NotificationTestResult{
+		Event:          event,
+		Methods:        append([]types.NotificationMethod{}, settings.Methods...),
+		Webhooks:       len(settings.Webhooks),
+		ScriptCommands: len(settings.ScriptCommands),
+	}
+	if !settings.Enabled {
+		result.Error = "notifications are disabled for this scope"
+		return result, nil
+	}
+	timeout := notificationDispatchTimeout(settings)
+	if _, _, ok := splitNotificationWebhooks(settings); ok && notificationWebhookDeliveryTimeout > timeout {
+		timeout = notificationWebhookDeliveryTimeout
 	}
 	dispatchCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
-	if err := s.dispatcher.Dispatch(dispatchCtx, event, settings); err != nil && s.logger != nil {
-		s.logger.Warn("notification_dispatch_failed",
-			logging.F("trigger", event.Trigger),
-			logging.F("session_id", event.SessionID),
-			logging.F("error", err),
-		)
+	if err := s.dispatcher.Dispatch(dispatchCtx, event, settings); err != nil {
+		result.Error = err.Error()
+		return result, nil
+	}
+	result.Delivered = true
+	return result, nil
+}
+
+// notificationDispatchTimeout bounds delivery to the local sinks and scripts;
+// webhooks have their own deadline.
+func notificationDispatchTimeout(settings types.NotificationSettings) time.Duration {
+	timeout := time.Duration(settings.ScriptTimeoutSeconds+2) * time.Second
+	if timeout < 5*time.Second {
+		timeout = 5 * time.Second
 	}
+	return timeout
 }
 
 func (s *NotificationService) shouldSuppress(event types.NotificationEvent, settings types.NotificationSettings) bool {
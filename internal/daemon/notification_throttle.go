@@ -0,0 +1,166 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"control/internal/types"
+)
+
+// notificationDeliveryKey identifies where a notification would be delivered.
+// Rate limits and batches are tracked per destination so that scopes routing
+// to different methods, scripts or webhooks do not share budgets.
+func notificationDeliveryKey(settings types.NotificationSettings) string {
+	data, err := json.Marshal(struct {
+		Methods        []types.NotificationMethod  `json:"methods"`
+		ScriptCommands []string                    `json:"script_commands"`
+		Webhooks       []types.NotificationWebhook `json:"webhooks"`
+	}{settings.Methods, settings.ScriptCommands, settings.Webhooks})
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+type notificationRateLimiter struct {
+	mu   sync.Mutex
+	sent map[string][]time.Time
+}
+
+func newNotificationRateLimiter() *notificationRateLimiter {
+	return &notificationRateLimiter{sent: map[string][]time.Time{}}
+}
+
+// Allow records a delivery and reports whether it fits the trigger's rate
+// limit. Triggers without a configured limit are always allowed.
+func (l *notificationRateLimiter) Allow(event types.NotificationEvent, settings types.NotificationSettings, now time.Time) bool {
+	if l == nil {
+		return true
+	}
+	limit, ok := types.NotificationRateLimitFor(settings, event.Trigger)
+	if !ok {
+		return true
+	}
+	window := time.Duration(limit.WindowSeconds) * time.Second
+	key := string(event.Trigger) + "\x00" + notificationDeliveryKey(settings)
+	cutoff := now.Add(-window)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	recent := l.sent[key][:0]
+	for _, at := range l.sent[key] {
+		if at.After(cutoff) {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent) >= limit.Max {
+		l.sent[key] = recent
+		return false
+	}
+	l.sent[key] = append(recent, now)
+	return true
+}
+
+type notificationBatch struct {
+	settings types.NotificationSettings
+	events   []types.NotificationEvent
+	timer    *time.Timer
+}
+
+// notificationBatcher holds events for settings.BatchWindowSeconds and then
+// hands them to flush together. A window that collected a single event flushes
+// it unchanged.
+type notificationBatcher struct {
+	mu      sync.Mutex
+	pending map[string]*notificationBatch
+	flush   func(ctx context.Context, events []types.NotificationEvent, settings types.NotificationSettings)
+}
+
+func newNotificationBatcher(flush func(ctx context.Context, events []types.NotificationEvent, settings types.NotificationSettings)) *notificationBatcher {
+	return &notificationBatcher{
+		pending: map[string]*notificationBatch{},
+		flush:   flush,
+	}
+}
+
+func (b *notificationBatcher) Add(event types.NotificationEvent, settings types.NotificationSettings) {
+	if b == nil {
+		return
+	}
+	key := notificationDeliveryKey(settings)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if batch, ok := b.pending[key]; ok {
+		batch.events = append(batch.events, event)
+		return
+	}
+	batch := &notificationBatch{settings: settings, events: []types.NotificationEvent{event}}
+	batch.timer = time.AfterFunc(time.Duration(settings.BatchWindowSeconds)*time.Second, func() {
+		b.flushKey(context.Background(), key)
+	})
+	b.pending[key] = batch
+}
+
+func (b *notificationBatcher) flushKey(ctx context.Context, key string) {
+	b.mu.Lock()
+	batch, ok := b.pending[key]
+	delete(b.pending, key)
+	b.mu.Unlock()
+	if !ok || b.flush == nil {
+		return
+	}
+	b.flush(ctx, batch.events, batch.settings)
+}
+
+// FlushAll delivers every pending batch immediately with ctx and returns how
+// many events were delivered.
+func (b *notificationBatcher) FlushAll(ctx context.Context) int {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	keys := make([]string, 0, len(b.pending))
+	flushed := 0
+	for key, batch := range b.pending {
+		batch.timer.Stop()
+		keys = append(keys, key)
+		flushed += len(batch.events)
+	}
+	b.mu.Unlock()
+	for _, key := range keys {
+		b.flushKey(ctx, key)
+	}
+	return flushed
+}
+
+func notificationBatchEvent(events []types.NotificationEvent) types.NotificationEvent {
+	counts := map[string]int{}
+	order := make([]string, 0, len(events))
+	for _, event := range events {
+		trigger := string(event.Trigger)
+		if _, seen := counts[trigger]; !seen {
+			order = append(order, trigger)
+		}
+		counts[trigger]++
+	}
+	summary := make([]string, 0, len(order))
+	for _, trigger := range order {
+		summary = append(summary, fmt.Sprintf("%s: %d", trigger, counts[trigger]))
+	}
+	return types.NotificationEvent{
+		Trigger:    types.NotificationTriggerBatch,
+		OccurredAt: time.Now().UTC().Format(time.RFC3339Nano),
+		Status:     strings.Join(summary, ", "),
+		Source:     "notification_batch",
+		Payload: map[string]any{
+			"kind":   "notification_batch",
+			"count":  len(events),
+			"counts": counts,
+			"events": events,
+		},
+	}
+}
@@ -0,0 +1,220 @@
+package daemon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"control/internal/logging"
+	"control/internal/types"
+)
+
+func TestNotificationRateLimiterCapsTriggerWithinWindow(t *testing.T) {
+	limiter := newNotificationRateLimiter()
+	settings := types.NotificationSettings{
+		Methods:    []types.NotificationMethod{types.NotificationMethodBell},
+		RateLimits: []types.NotificationRateLimit{{Trigger: types.NotificationTriggerTurnCompleted, Max: 2, WindowSeconds: 60}},
+	}
+	completed := types.NotificationEvent{Trigger: types.NotificationTriggerTurnCompleted, SessionID: "s1"}
+	failed := types.NotificationEvent{Trigger: types.NotificationTriggerSessionFailed, SessionID: "s1"}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if !limiter.Allow(completed, settings, now) || !limiter.Allow(completed, settings, now.Add(time.Second)) {
+		t.Fatalf("expected first two events to be allowed")
+	}
+	if limiter.Allow(completed, settings, now.Add(2*time.Second)) {
+		t.Fatalf("expected third event within the window to be dropped")
+	}
+	if !limiter.Allow(failed, settings, now.Add(2*time.Second)) {
+		t.Fatalf("expected triggers without a limit to be allowed")
+	}
+	if !limiter.Allow(completed, settings, now.Add(61*time.Second)) {
+		t.Fatalf("expected the window to slide")
+	}
+}
+
+func TestNotificationBatcherSummarizesEvents(t *testing.T) {
+	var flushed [][]types.NotificationEvent
+	batcher := newNotificationBatcher(func(_ context.Context, events []types.NotificationEvent, settings types.NotificationSettings) {
+		flushed = append(flushed, events)
+	})
+	settings := types.NotificationSettings{Methods: []types.NotificationMethod{types.NotificationMethodBell}, BatchWindowSeconds: 600}
+	batcher.Add(types.NotificationEvent{Trigger: types.NotificationTriggerTurnCompleted, SessionID: "s1"}, settings)
+	batcher.Add(types.NotificationEvent{Trigger: types.NotificationTriggerTurnCompleted, SessionID: "s2"}, settings)
+	batcher.Add(types.NotificationEvent{Trigger: types.NotificationTriggerSessionFailed, SessionID: "s3"}, settings)
+	if flushed := batcher.FlushAll(context.Background()); flushed != 3 {
+		t.Fatalf("expected three flushed events, got %d", flushed)
+	}
+
+	if len(flushed) != 1 || len(flushed[0]) != 3 {
+		t.Fatalf("expected one batch of three events, got %#v", flushed)
+	}
+	event := notificationBatchEvent(flushed[0])
+	if event.Trigger != types.NotificationTriggerBatch || event.Status != "turn.completed: 2, session.failed: 1" {
+		t.Fatalf("unexpected batch event: %#v", event)
+	}
+	summary, body := notificationTitleBody(event)
+	if summary != "Archon: 3 notifications" || body != event.Status {
+		t.Fatalf("unexpected batch title/body: %q / %q", summary, body)
+	}
+	if pending := len(batcher.pending); pending != 0 {
+		t.Fatalf("expected nothing pending after flush, got %d", pending)
+	}
+}
+
+func TestNotificationServiceDropsDuringQuietHours(t *testing.T) {
+	resolver := stubNotificationPolicyResolver{settings: types.NotificationSettings{
+		Enabled:         true,
+		Triggers:        []types.NotificationTrigger{types.NotificationTriggerTurnCompleted},
+		Methods:         []types.NotificationMethod{types.NotificationMethodBell},
+		QuietHoursStart: "22:00",
+		QuietHoursEnd:   "07:00",
+	}}
+	dispatcher := &stubNotificationDispatcher{}
+	service := NewNotificationService(resolver, dispatcher, logging.Nop())
+	defer service.Close()
+
+	event := types.NotificationEvent{Trigger: types.NotificationTriggerTurnCompleted, SessionID: "s1"}
+	service.now = func() time.Time { return time.Date(2026, 3, 1, 23, 0, 0, 0, time.Local) }
+	service.handle(context.Background(), event)
+	service.now = func() time.Time { return time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local) }
+	service.handle(context.Background(), event)
+
+	dispatcher.mu.Lock()
+	defer dispatcher.mu.Unlock()
+	if dispatcher.count != 1 {
+		t.Fatalf("expected only the event outside quiet hours to dispatch, got %d", dispatcher.count)
+	}
+}
+
+func TestNotificationServiceStopDeliversPendingBatches(t *testing.T) {
+	resolver := stubNotificationPolicyResolver{settings: types.NotificationSettings{
+		Enabled:            true,
+		Triggers:           []types.NotificationTrigger{types.NotificationTriggerTurnCompleted},
+		Methods:            []types.NotificationMethod{types.NotificationMethodBell},
+		BatchWindowSeconds: 600,
+	}}
+	dispatcher := &stubNotificationDispatcher{}
+	service := NewNotificationService(resolver, dispatcher, logging.Nop())
+
+	service.handle(context.Background(), types.NotificationEvent{Trigger: types.NotificationTriggerTurnCompleted, SessionID: "s1"})
+	service.handle(context.Background(), types.NotificationEvent{Trigger: types.NotificationTriggerTurnCompleted, SessionID: "s2"})
+	dispatcher.mu.Lock()
+	pending := dispatcher.count
+	dispatcher.mu.Unlock()
+	if pending != 0 {
+		t.Fatalf("expected events to wait for the batch window, got %d dispatches", pending)
+	}
+
+	if err := service.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	dispatcher.mu.Lock()
+	defer dispatcher.mu.Unlock()
+	if dispatcher.count != 1 {
+		t.Fatalf("expected the pending batch to be delivered on stop, got %d dispatches", dispatcher.count)
+	}
+}
+
+func TestNotificationServiceSlowWebhookDoesNotDelayDesktop(t *testing.T) {
+	release := make(chan struct{})
+	desktop := make(chan struct{}, 1)
+	webhookDone := make(chan struct{})
+	dispatcher := NewNotificationDispatcher([]NotificationSink{
+		stubNotificationSink{
+			method: types.NotificationMethodNotifySend,
+			notify: func(context.Context, types.NotificationEvent, types.NotificationSettings) error {
+				desktop <- struct{}{}
+				return nil
+			},
+		},
+		stubNotificationSink{
+			method: types.NotificationMethodWebhook,
+			notify: func(ctx context.Context, _ types.NotificationEvent, _ types.NotificationSettings) error {
+				defer close(webhookDone)
+				select {
+				case <-release:
+				case <-ctx.Done():
+				}
+				return nil
+			},
+		},
+	}, logging.Nop())
+	resolver := stubNotificationPolicyResolver{settings: types.NotificationSettings{
+		Enabled:  true,
+		Triggers: []types.NotificationTrigger{types.NotificationTriggerTurnCompleted},
+		Methods:  []types.NotificationMethod{types.NotificationMethodWebhook, types.NotificationMethodNotifySend},
+		Webhooks: []types.NotificationWebhook{{URL: "http://127.0.0.1:1/hook"}},
+	}}
+	service := NewNotificationService(resolver, dispatcher, logging.Nop())
+	defer service.Close()
+
+	service.Publish(types.NotificationEvent{Trigger: types.NotificationTriggerTurnCompleted, SessionID: "s1"})
+	select {
+	case <-desktop:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected the desktop sink to be notified while the webhook is pending")
+	}
+	close(release)
+	select {
+	case <-webhookDone:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected the webhook to be delivered")
+	}
+}
+
+func TestNotificationDigestSummarizesDayOnce(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local)
+	digest := newNotificationDigest("18:00", start)
+	if digest == nil {
+		t.Fatalf("expected digest for valid clock")
+	}
+	if newNotificationDigest("later", start) != nil {
+		t.Fatalf("expected invalid clock to disable the digest")
+	}
+	digest.Record(types.NotificationEvent{Trigger: types.NotificationTriggerApprovalPending, SessionID: "s1"}, start)
+	digest.Record(types.NotificationEvent{Trigger: types.NotificationTriggerTurnCompleted, SessionID: "s1", Status: "completed"}, start.Add(time.Hour))
+	digest.Record(types.NotificationEvent{Trigger: types.NotificationTriggerTurnCompleted, SessionID: "s2", Status: "failed"}, start.Add(time.Hour))
+	digest.Record(types.NotificationEvent{Trigger: types.NotificationTriggerWorkflowDecisionNeeded, SessionID: "s3"}, start.Add(2*time.Hour))
+
+	if _, ok := digest.Take(start.Add(8 * time.Hour)); ok {
+		t.Fatalf("expected no digest before the configured time")
+	}
+	event, ok := digest.Take(start.Add(9 * time.Hour))
+	if !ok {
+		t.Fatalf("expected digest at the configured time")
+	}
+	if event.Trigger != types.NotificationTriggerDigest {
+		t.Fatalf("unexpected digest trigger: %q", event.Trigger)
+	}
+	summary, body := notificationTitleBody(event)
+	if summary != "Archon daily digest" || body != "completed: 1 | failed: 1 | awaiting input: 1" {
+		t.Fatalf("unexpected digest title/body: %q / %q", summary, body)
+	}
+	if _, ok := digest.Take(start.Add(10 * time.Hour)); ok {
+		t.Fatalf("expected one digest per day")
+	}
+	if _, ok := digest.Take(start.Add(33 * time.Hour)); ok {
+		t.Fatalf("expected empty day to be skipped")
+	}
+}
+
+func TestNotificationDigestDropsResolvedApprovals(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local)
+	digest := newNotificationDigest("18:00", start)
+	digest.Record(types.NotificationEvent{Trigger: types.NotificationTriggerApprovalPending, SessionID: "s1"}, start)
+	digest.Record(types.NotificationEvent{Trigger: types.NotificationTriggerApprovalPending, SessionID: "s2"}, start)
+	digest.Record(types.NotificationEvent{Trigger: types.NotificationTriggerApprovalPending, SessionID: "s2"}, start)
+	digest.Record(types.NotificationEvent{Trigger: types.NotificationTriggerApprovalResolved, SessionID: "s1"}, start.Add(time.Minute))
+	digest.Record(types.NotificationEvent{Trigger: types.NotificationTriggerApprovalResolved, SessionID: "s2"}, start.Add(time.Minute))
+	digest.Record(types.NotificationEvent{Trigger: types.NotificationTriggerTurnCompleted, SessionID: "s3", Status: "completed"}, start.Add(time.Hour))
+
+	event, ok := digest.Take(start.Add(9 * time.Hour))
+	if !ok {
+		t.Fatalf("expected digest at the configured time")
+	}
+	awaiting, _ := event.Payload["awaiting_input"].([]notificationDigestEntry)
+	if len(awaiting) != 1 || awaiting[0].SessionID != "s2" || awaiting[0].Count != 1 {
+		t.Fatalf("expected only s2's unanswered approval to remain, got %#v", awaiting)
+	}
+}
@@ -0,0 +1,112 @@
+package daemon
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"control/internal/store"
+	"control/internal/types"
+)
+
+func TestStoreApprovalStoragePublishesApprovalPendingOnce(t *testing.T) {
+	stores := &Stores{Approvals: store.NewFileApprovalStore(filepath.Join(t.TempDir(), "approvals.json"))}
+	publisher := &captureNotificationPublisher{}
+	storage := NewStoreApprovalStorage(stores)
+	storage.SetNotificationPublisher(publisher)
+
+	for i := 0; i < 2; i++ {
+		if err := storage.StoreApproval(context.Background(), "sess-1", 7, "session/request_permission", nil); err != nil {
+			t.Fatalf("StoreApproval: %v", err)
+		}
+	}
+	events := publisher.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected one approval notification for a re-delivered request, got %d", len(events))
+	}
+	if events[0].Trigger != types.NotificationTriggerApprovalPending || events[0].Source != "approval_request:sess-1:7" {
+		t.Fatalf("unexpected approval event: %#v", events[0])
+	}
+	summary, _ := notificationTitleBody(events[0])
+	if summary != "Archon approval required" {
+		t.Fatalf("unexpected summary: %q", summary)
+	}
+}
+
+func TestRateLimitNotifierPublishesOnlyOnChange(t *testing.T) {
+	manager, err := NewSessionManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewSessionManager: %v", err)
+	}
+	publisher := &captureNotificationPublisher{}
+	manager.SetNotificationPublisher(publisher)
+	notifier := newRateLimitNotifier(manager, "sess-1", "claude")
+
+	limited := map[string]any{"type": "rateLimit", "provider": "claude", "status": "rejected", "limit_type": "five_hour", "retry_at": "2026-01-01T00:00:00Z"}
+	notifier.ObserveItem(map[string]any{"type": "agentMessage", "text": "hi"})
+	notifier.ObserveItem(limited)
+	notifier.ObserveItem(limited)
+	warning := map[string]any{"type": "rateLimit", "provider": "claude", "status": "allowed_warning", "limit_type": "five_hour"}
+	notifier.ObserveItem(warning)
+
+	events := publisher.Events()
+	if len(events) != 2 {
+		t.Fatalf("expected two rate limit notifications, got %#v", events)
+	}
+	if events[0].Trigger != types.NotificationTriggerProviderRateLimited || events[0].Status != "rejected" {
+		t.Fatalf("unexpected rate limit event: %#v", events[0])
+	}
+	_, body := notificationTitleBody(events[0])
+	if !strings.Contains(body, "limit: five_hour") || !strings.Contains(body, "retry at: 2026-01-01T00:00:00Z") {
+		t.Fatalf("unexpected rate limit body: %q", body)
+	}
+}
+
+func TestTurnWatchdogReportsLongRunningTurnsOnce(t *testing.T) {
+	watchdog := newTurnWatchdog()
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	watchdog.TurnStarted("sess-slow", "turn-1", "codex", start)
+	watchdog.TurnStarted("sess-fast", "turn-2", "claude", start)
+
+	downstream := &captureNotificationPublisher{}
+	publisher := newTurnWatchdogNotificationPublisher(downstream, watchdog)
+	publisher.Publish(types.NotificationEvent{Trigger: types.NotificationTriggerTurnCompleted, SessionID: "sess-fast", TurnID: "turn-2"})
+
+	watchdog.Check(downstream, 20*time.Minute, start.Add(10*time.Minute))
+	watchdog.Check(downstream, 20*time.Minute, start.Add(25*time.Minute))
+	watchdog.Check(downstream, 20*time.Minute, start.Add(30*time.Minute))
+
+	var longRunning []types.NotificationEvent
+	for _, event := range downstream.Events() {
+		if event.Trigger == types.NotificationTriggerTurnLongRunning {
+			longRunning = append(longRunning, event)
+		}
+	}
+	if len(longRunning) != 1 {
+		t.Fatalf("expected one long-running notification, got %#v", longRunning)
+	}
+	if longRunning[0].SessionID != "sess-slow" || longRunning[0].TurnID != "turn-1" {
+		t.Fatalf("unexpected long-running event: %#v", longRunning[0])
+	}
+	_, body := notificationTitleBody(longRunning[0])
+	if !strings.Contains(body, "running for 25m") {
+		t.Fatalf("unexpected long-running body: %q", body)
+	}
+}
+
+func TestAPISessionServiceUsesInjectedTurnInstruments(t *testing.T) {
+	api := &API{
+		DaemonMetrics: newDaemonMetrics(nil),
+		DaemonTracing: newDaemonTracing(nil),
+		TurnWatchdog:  newTurnWatchdog(),
+	}
+	service := api.newSessionService()
+	if service.metrics != api.DaemonMetrics || service.tracing != api.DaemonTracing || service.watchdog != api.TurnWatchdog {
+		t.Fatalf("expected session service to use the API's turn instruments")
+	}
+	if bare := (&API{}).newSessionService(); bare.metrics != nil || bare.tracing != nil || bare.watchdog != nil {
+		t.Fatalf("expected no turn instruments without injection")
+	}
+}
@@ -0,0 +1,274 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"control/internal/types"
+)
+
+const (
+	notificationWebhookSignatureHeader = "X-Archon-Signature"
+	notificationWebhookEventHeader     = "X-Archon-Event"
+	notificationWebhookBaseBackoff     = 500 * time.Millisecond
+	notificationWebhookMaxBackoff      = 8 * time.Second
+	notificationWebhookMaxRetryAfter   = 30 * time.Second
+	// notificationWebhookDeliveryTimeout bounds delivering one event to its
+	// webhooks, retries included.
+	notificationWebhookDeliveryTimeout = 60 * time.Second
+)
+
+// webhookSink posts notification events to every configured webhook. Targets
+// are delivered concurrently so one slow endpoint does not delay the others;
+// each target retries transient failures with exponential backoff.
+type webhookSink struct {
+	client    *http.Client
+	sleep     func(ctx context.Context, d time.Duration) error
+	lookupEnv func(string) (string, bool)
+}
+
+func (webhookSink) Method() types.NotificationMethod {
+	return types.NotificationMethodWebhook
+}
+
+func (s webhookSink) Notify(ctx context.Context, event types.NotificationEvent, settings types.NotificationSettings) error {
+	if len(settings.Webhooks) == 0 {
+		return errors.New("no notification webhooks configured")
+	}
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs error
+	)
+	for _, webhook := range settings.Webhooks {
+		wg.Add(1)
+		go func(webhook types.NotificationWebhook) {
+			defer wg.Done()
+			if err := s.deliver(ctx, webhook, event, settings); err != nil {
+				mu.Lock()
+				errs = errors.Join(errs, err)
+				mu.Unlock()
+			}
+		}(webhook)
+	}
+	wg.Wait()
+	return errs
+}
+
+func (s webhookSink) deliver(ctx context.Context, webhook types.NotificationWebhook, event types.NotificationEvent, settings types.NotificationSettings) error {
+	body, err := notificationWebhookBody(webhook.Format, event)
+	if err != nil {
+		return err
+	}
+	signature := ""
+	if secret := s.webhookSecret(webhook); secret != "" {
+		signature = notificationWebhookSignature(secret, body)
+	}
+	attempts := settings.WebhookRetries + 1
+	timeout := time.Duration(settings.WebhookTimeoutSeconds) * time.Second
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		retryAfter, retryable, err := s.post(ctx, webhook, event, body, signature, timeout)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retryable || attempt == attempts {
+			break
+		}
+		delay := notificationWebhookBackoff(attempt)
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+			// Waiting would outlast the delivery deadline; give up now.
+			break
+		}
+		if err := s.sleepOrDefault()(ctx, delay); err != nil {
+			return errors.Join(lastErr, err)
+		}
+	}
+	return fmt.Errorf("webhook %s failed: %w", redactWebhookURL(webhook.URL), lastErr)
+}
+
+func (s webhookSink) post(
+	ctx context.Context,
+	webhook types.NotificationWebhook,
+	event types.NotificationEvent,
+	body []byte,
+	signature string,
+	timeout time.Duration,
+) (time.Duration, bool, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "archon-notifications")
+	req.Header.Set(notificationWebhookEventHeader, string(event.Trigger))
+	if signature != "" {
+		req.Header.Set(notificationWebhookSignatureHeader, signature)
+	}
+	for key, value := range webhook.Headers {
+		req.Header.Set(key, value)
+	}
+	client := s.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return 0, true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return 0, false, nil
+	}
+	statusErr := fmt.Errorf("unexpected status %s", resp.Status)
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return parseRetryAfter(resp.Header.Get("Retry-After")), true, statusErr
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode >= 500:
+		return 0, true, statusErr
+	default:
+		return 0, false, statusErr
+	}
+}
+
+func (s webhookSink) webhookSecret(webhook types.NotificationWebhook) string {
+	if secret := strings.TrimSpace(webhook.Secret); secret != "" {
+		return secret
+	}
+	if webhook.SecretEnv == "" {
+		return ""
+	}
+	lookup := s.lookupEnv
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	value, _ := lookup(webhook.SecretEnv)
+	return strings.TrimSpace(value)
+}
+
+func (s webhookSink) sleepOrDefault() func(ctx context.Context, d time.Duration) error {
+	if s.sleep != nil {
+		return s.sleep
+	}
+	return func(ctx context.Context, d time.Duration) error {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		}
+	}
+}
+
+// notificationWebhookSignature returns the value of the X-Archon-Signature
+// header: "sha256=" followed by the hex HMAC-SHA256 of the request body.
+func notificationWebhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func notificationWebhookBackoff(attempt int) time.Duration {
+	delay := notificationWebhookBaseBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= notificationWebhookMaxBackoff {
+			return notificationWebhookMaxBackoff
+		}
+	}
+	return delay
+}
+
+// splitNotificationWebhooks separates the webhook method from settings. It
+// returns settings without it, settings that only deliver to the webhooks, and
+// whether the webhook method was listed.
+func splitNotificationWebhooks(settings types.NotificationSettings) (types.NotificationSettings, types.NotificationSettings, bool) {
+	local := settings
+	local.Methods = make([]types.NotificationMethod, 0, len(settings.Methods))
+	found := false
+	for _, method := range settings.Methods {
+		if method == types.NotificationMethodWebhook {
+			found = true
+			continue
+		}
+		local.Methods = append(local.Methods, method)
+	}
+	if !found {
+		return settings, types.NotificationSettings{}, false
+	}
+	webhooks := settings
+	webhooks.Methods = []types.NotificationMethod{types.NotificationMethodWebhook}
+	webhooks.ScriptCommands = nil
+	return local, webhooks, true
+}
+
+func parseRetryAfter(raw string) time.Duration {
+	seconds, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	delay := time.Duration(seconds) * time.Second
+	if delay > notificationWebhookMaxRetryAfter {
+		return notificationWebhookMaxRetryAfter
+	}
+	return delay
+}
+
+// redactWebhookURL keeps only scheme and host in errors; incoming-webhook URLs
+// for Slack and Discord embed their credentials in the path.
+func redactWebhookURL(raw string) string {
+	scheme, rest, ok := strings.Cut(raw, "://")
+	if !ok {
+		return "<invalid url>"
+	}
+	host, _, _ := strings.Cut(rest, "/")
+	return scheme + "://" + host
+}
+
+func notificationWebhookBody(format types.NotificationWebhookFormat, event types.NotificationEvent) ([]byte, error) {
+	switch format {
+	case types.NotificationWebhookFormatSlack:
+		title, body := notificationTitleBody(event)
+		return json.Marshal(map[string]any{
+			"text": "*" + title + "*\n" + body,
+		})
+	case types.NotificationWebhookFormatDiscord:
+		title, body := notificationTitleBody(event)
+		return json.Marshal(map[string]any{
+			"username": "Archon",
+			"content":  "**" + title + "**\n" + body,
+		})
+	default:
+		return json.Marshal(event)
+	}
+}
@@ -0,0 +1,242 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"control/internal/types"
+)
+
+type webhookRequest struct {
+	header http.Header
+	body   []byte
+}
+
+type webhookRecorder struct {
+	mu         sync.Mutex
+	requests   []webhookRequest
+	statuses   []int
+	retryAfter string
+}
+
+func (r *webhookRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, webhookRequest{header: req.Header.Clone(), body: body})
+	status := http.StatusNoContent
+	if len(r.statuses) > 0 {
+		status = r.statuses[0]
+		r.statuses = r.statuses[1:]
+	}
+	if status == http.StatusTooManyRequests && r.retryAfter != "" {
+		w.Header().Set("Retry-After", r.retryAfter)
+	}
+	w.WriteHeader(status)
+}
+
+func (r *webhookRecorder) Requests() []webhookRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]webhookRequest(nil), r.requests...)
+}
+
+func noWebhookSleep(context.Context, time.Duration) error { return nil }
+
+func TestWebhookSinkDeliversSignedEventJSON(t *testing.T) {
+	recorder := &webhookRecorder{}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	sink := webhookSink{
+		sleep: noWebhookSleep,
+		lookupEnv: func(key string) (string, bool) {
+			return map[string]string{"HOOK_SECRET": "s3cret"}[key], key == "HOOK_SECRET"
+		},
+	}
+	settings := types.NormalizeNotificationSettings(types.NotificationSettings{
+		Enabled:  true,
+		Methods:  []types.NotificationMethod{types.NotificationMethodWebhook},
+		Webhooks: []types.NotificationWebhook{{URL: server.URL, SecretEnv: "HOOK_SECRET", Headers: map[string]string{"X-Team": "core"}}},
+	})
+	event := types.NotificationEvent{Trigger: types.NotificationTriggerTurnCompleted, SessionID: "s1", Status: "completed"}
+	if err := sink.Notify(context.Background(), event, settings); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	requests := recorder.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("expected one delivery, got %d", len(requests))
+	}
+	req := requests[0]
+	if got := req.header.Get(notificationWebhookSignatureHeader); got != notificationWebhookSignature("s3cret", req.body) {
+		t.Fatalf("unexpected signature %q", got)
+	}
+	if req.header.Get("X-Team") != "core" || req.header.Get(notificationWebhookEventHeader) != "turn.completed" {
+		t.Fatalf("unexpected headers: %#v", req.header)
+	}
+	var decoded types.NotificationEvent
+	if err := json.Unmarshal(req.body, &decoded); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if decoded.SessionID != "s1" || decoded.Trigger != types.NotificationTriggerTurnCompleted {
+		t.Fatalf("unexpected event body: %#v", decoded)
+	}
+}
+
+func TestWebhookSinkRetriesTransientFailures(t *testing.T) {
+	recorder := &webhookRecorder{statuses: []int{http.StatusBadGateway, http.StatusTooManyRequests, http.StatusOK}}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	var delays []time.Duration
+	sink := webhookSink{sleep: func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}}
+	settings := types.NormalizeNotificationSettings(types.NotificationSettings{
+		Enabled:        true,
+		Webhooks:       []types.NotificationWebhook{{URL: server.URL}},
+		WebhookRetries: 3,
+	})
+	if err := sink.Notify(context.Background(), types.NotificationEvent{Trigger: types.NotificationTriggerSessionFailed}, settings); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if got := len(recorder.Requests()); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+	if len(delays) != 2 || delays[0] != 500*time.Millisecond || delays[1] != time.Second {
+		t.Fatalf("unexpected backoff delays: %#v", delays)
+	}
+}
+
+func TestWebhookSinkCapsRetryAfter(t *testing.T) {
+	recorder := &webhookRecorder{statuses: []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK}, retryAfter: "120"}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	var waited time.Duration
+	sink := webhookSink{sleep: func(_ context.Context, d time.Duration) error {
+		waited += d
+		return nil
+	}}
+	settings := types.NormalizeNotificationSettings(types.NotificationSettings{
+		Enabled:               true,
+		Webhooks:              []types.NotificationWebhook{{URL: server.URL}},
+		WebhookRetries:        2,
+		WebhookTimeoutSeconds: 5,
+	})
+	if err := sink.Notify(context.Background(), types.NotificationEvent{Trigger: types.NotificationTriggerSessionFailed}, settings); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if got := len(recorder.Requests()); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+	if waited != 2*notificationWebhookMaxRetryAfter {
+		t.Fatalf("expected Retry-After capped at %s per wait, waited %s", notificationWebhookMaxRetryAfter, waited)
+	}
+}
+
+func TestWebhookSinkGivesUpWhenRetryAfterOutlastsDeadline(t *testing.T) {
+	recorder := &webhookRecorder{statuses: []int{http.StatusTooManyRequests, http.StatusOK}, retryAfter: "20"}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	slept := false
+	sink := webhookSink{sleep: func(context.Context, time.Duration) error {
+		slept = true
+		return nil
+	}}
+	settings := types.NormalizeNotificationSettings(types.NotificationSettings{
+		Enabled:        true,
+		Webhooks:       []types.NotificationWebhook{{URL: server.URL}},
+		WebhookRetries: 2,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sink.Notify(ctx, types.NotificationEvent{Trigger: types.NotificationTriggerSessionFailed}, settings); err == nil {
+		t.Fatalf("expected the throttled delivery to fail")
+	}
+	if slept || len(recorder.Requests()) != 1 {
+		t.Fatalf("expected no wait past the deadline, slept=%v attempts=%d", slept, len(recorder.Requests()))
+	}
+}
+
+func TestWebhookSinkDoesNotRetryClientErrorsAndRedactsURL(t *testing.T) {
+	recorder := &webhookRecorder{statuses: []int{http.StatusForbidden}}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	sink := webhookSink{sleep: noWebhookSleep}
+	settings := types.NormalizeNotificationSettings(types.NotificationSettings{
+		Enabled:        true,
+		Webhooks:       []types.NotificationWebhook{{URL: server.URL + "/services/T000/B000/secret-token"}},
+		WebhookRetries: 3,
+	})
+	err := sink.Notify(context.Background(), types.NotificationEvent{Trigger: types.NotificationTriggerSessionFailed}, settings)
+	if err == nil {
+		t.Fatalf("expected delivery error")
+	}
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Fatalf("expected webhook path to be redacted, got %v", err)
+	}
+	if got := len(recorder.Requests()); got != 1 {
+		t.Fatalf("expected a single attempt for 403, got %d", got)
+	}
+}
+
+func TestNotificationWebhookBodyFormats(t *testing.T) {
+	event := types.NotificationEvent{Trigger: types.NotificationTriggerTurnCompleted, SessionID: "s1", Title: "Fix tests", Provider: "codex", Status: "completed"}
+	slack, err := notificationWebhookBody(types.NotificationWebhookFormatSlack, event)
+	if err != nil {
+		t.Fatalf("slack body: %v", err)
+	}
+	var slackPayload map[string]any
+	_ = json.Unmarshal(slack, &slackPayload)
+	if text, _ := slackPayload["text"].(string); !strings.HasPrefix(text, "*") || !strings.Contains(text, "Fix tests") {
+		t.Fatalf("unexpected slack payload: %s", slack)
+	}
+	discord, err := notificationWebhookBody(types.NotificationWebhookFormatDiscord, event)
+	if err != nil {
+		t.Fatalf("discord body: %v", err)
+	}
+	var discordPayload map[string]any
+	_ = json.Unmarshal(discord, &discordPayload)
+	if content, _ := discordPayload["content"].(string); !strings.HasPrefix(content, "**") || discordPayload["username"] != "Archon" {
+		t.Fatalf("unexpected discord payload: %s", discord)
+	}
+}
+
+func TestNotificationServiceSendTestNotificationReportsDeliveryErrors(t *testing.T) {
+	settings := types.NormalizeNotificationSettings(types.NotificationSettings{
+		Enabled: true,
+		Methods: []types.NotificationMethod{types.NotificationMethodWebhook},
+	})
+	service := NewNotificationService(
+		stubNotificationPolicyResolver{settings: settings},
+		NewNotificationDispatcher([]NotificationSink{webhookSink{}}, nil),
+		nil,
+	)
+	defer service.Close()
+
+	result, err := service.SendTestNotification(context.Background(), types.NotificationTestRequest{SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("SendTestNotification: %v", err)
+	}
+	if result.Delivered || !strings.Contains(result.Error, "no notification webhooks configured") {
+		t.Fatalf("expected missing webhook error, got %#v", result)
+	}
+	if result.Event.Trigger != types.NotificationTriggerTurnCompleted || result.Event.SessionID != "s1" {
+		t.Fatalf("unexpected sample event: %#v", result.Event)
+	}
+	if _, err := service.SendTestNotification(context.Background(), types.NotificationTestRequest{Trigger: "nope"}); err == nil {
+		t.Fatalf("expected invalid trigger error")
+	}
+}
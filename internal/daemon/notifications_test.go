@@ -360,8 +360,8 @@ func TestNotificationDispatcherAutoNoSinks(t *testing.T) {
 
 func TestDefaultNotificationSinks(t *testing.T) {
 	sinks := defaultNotificationSinks()
-	if len(sinks) != 3 {
-		t.Fatalf("expected 3 default sinks, got %d", len(sinks))
+	if len(sinks) != 4 {
+		t.Fatalf("expected 4 default sinks, got %d", len(sinks))
 	}
 	seen := map[types.NotificationMethod]bool{}
 	for _, sink := range sinks {
@@ -370,7 +370,7 @@ func TestDefaultNotificationSinks(t *testing.T) {
 		}
 		seen[sink.Method()] = true
 	}
-	if !seen[types.NotificationMethodDunstify] || !seen[types.NotificationMethodNotifySend] || !seen[types.NotificationMethodBell] {
+	if !seen[types.NotificationMethodDunstify] || !seen[types.NotificationMethodNotifySend] || !seen[types.NotificationMethodBell] || !seen[types.NotificationMethodWebhook] {
 		t.Fatalf("unexpected sink methods: %#v", seen)
 	}
 }
@@ -30,6 +30,7 @@ type openCodeLiveSession struct {
 	hub           *codexSubscriberHub
 	turnNotifier  TurnCompletionNotifier
 	approvalStore ApprovalStorage
+	usage         SessionUsageRecorder
 	artifactSync  TurnArtifactSynchronizer
 	payloads      TurnCompletionPayloadBuilder
 	freshness     TurnEvidenceFreshnessTracker
@@ -47,6 +48,7 @@ var (
 	_ TurnCapableSession     = (*openCodeLiveSession)(nil)
 	_ ApprovalCapableSession = (*openCodeLiveSession)(nil)
 	_ NotifiableSession      = (*openCodeLiveSession)(nil)
+	_ UsageReportingSession  = (*openCodeLiveSession)(nil)
 )
 
 func (s *openCodeLiveSession) Events() (<-chan types.CodexEvent, func()) {
@@ -77,9 +79,9 @@ func (s *openCodeLiveSession) SetSessionMeta(meta *types.SessionMeta) {
 }
 
 func (s *openCodeLiveSession) StartTurn(ctx context.Context, input []map[string]any, opts *types.SessionRuntimeOptions) (string, error) {
-	text := extractTextInput(input)
-	if text == "" {
-		return "", invalidError("text input is required", nil)
+	text, attachments, err := inputTextOrAttachments(input)
+	if err != nil {
+		return "", err
 	}
 
 	turnID := generateTurnID()
@@ -97,14 +99,12 @@ func (s *openCodeLiveSession) StartTurn(ctx context.Context, input []map[string]
 
 	s.persistItems([]map[string]any{
 		{
-			"type": "userMessage",
-			"content": []map[string]any{
-				{"type": "text", "text": text},
-			},
+			"type":    "userMessage",
+			"content": userMessageContent(text, attachments),
 		},
 	})
 
-	err := s.client.PromptAsync(turnCtx, s.providerID, text, opts, s.directory)
+	err = s.client.PromptAsync(turnCtx, s.providerID, text, attachments, opts, s.directory)
 	s.mu.Lock()
 	if s.activeTurn == turnID {
 		s.cancelTurn = nil
@@ -296,6 +296,7 @@ func (s *openCodeLiveSession) handleProviderEvent(event types.CodexEvent) {
 	}
 	s.hub.Broadcast(event)
 	s.persistEventItems(event)
+	s.recordUsage(event)
 
 	if s.lifecycle != nil {
 		s.lifecycle.ObserveEvent(event)
@@ -321,6 +322,25 @@ func (s *openCodeLiveSession) handleProviderEvent(event types.CodexEvent) {
 	}
 }
 
+func (s *openCodeLiveSession) SetUsageRecorder(recorder SessionUsageRecorder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usage = recorder
+}
+
+func (s *openCodeLiveSession) recordUsage(event types.CodexEvent) {
+	report, ok := openCodeUsageReport(event)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	recorder := s.usage
+	s.mu.Unlock()
+	if recorder != nil {
+		recorder.RecordUsage(s.sessionID, s.providerName, report)
+	}
+}
+
 func (s *openCodeLiveSession) reconnectEventStream() bool {
 	if s == nil {
 		return false
@@ -0,0 +1,114 @@
+package daemon
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	pendingTurnMaxEntries = 4096
+	pendingTurnMaxAge     = 24 * time.Hour
+)
+
+type pendingTurn[T any] struct {
+	value     T
+	startedAt time.Time
+}
+
+// pendingTurnTracker remembers per-turn state from send until the matching
+// completion event. Completion events do not always carry the turn id, so the
+// most recent pending turn for the session is used as a fallback.
+type pendingTurnTracker[T any] struct {
+	mu      sync.Mutex
+	pending map[string]pendingTurn[T]
+	latest  map[string]string
+}
+
+func newPendingTurnTracker[T any]() *pendingTurnTracker[T] {
+	return &pendingTurnTracker[T]{
+		pending: map[string]pendingTurn[T]{},
+		latest:  map[string]string{},
+	}
+}
+
+func (t *pendingTurnTracker[T]) Start(sessionID, turnID string, value T, at time.Time) {
+	sessionID = strings.TrimSpace(sessionID)
+	if t == nil || sessionID == "" {
+		return
+	}
+	key := pendingTurnKey(sessionID, turnID)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[key] = pendingTurn[T]{value: value, startedAt: at}
+	t.latest[sessionID] = key
+	if len(t.pending) > pendingTurnMaxEntries {
+		t.pruneLocked(at)
+	}
+}
+
+func (t *pendingTurnTracker[T]) Complete(sessionID, turnID string) (T, time.Time, bool) {
+	var zero T
+	sessionID = strings.TrimSpace(sessionID)
+	if t == nil || sessionID == "" {
+		return zero, time.Time{}, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := pendingTurnKey(sessionID, turnID)
+	entry, ok := t.pending[key]
+	if !ok {
+		key = t.latest[sessionID]
+		entry, ok = t.pending[key]
+	}
+	if !ok {
+		return zero, time.Time{}, false
+	}
+	delete(t.pending, key)
+	if t.latest[sessionID] == key {
+		delete(t.latest, sessionID)
+	}
+	return entry.value, entry.startedAt, true
+}
+
+// TakeStartedBefore removes and returns every pending turn that started before
+// cutoff.
+func (t *pendingTurnTracker[T]) TakeStartedBefore(cutoff time.Time) []T {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var out []T
+	for key, entry := range t.pending {
+		if !entry.startedAt.Before(cutoff) {
+			continue
+		}
+		out = append(out, entry.value)
+		delete(t.pending, key)
+	}
+	for sessionID, key := range t.latest {
+		if _, ok := t.pending[key]; !ok {
+			delete(t.latest, sessionID)
+		}
+	}
+	return out
+}
+
+func (t *pendingTurnTracker[T]) pruneLocked(now time.Time) {
+	cutoff := now.Add(-pendingTurnMaxAge)
+	for key, entry := range t.pending {
+		if entry.startedAt.Before(cutoff) {
+			delete(t.pending, key)
+		}
+	}
+	for sessionID, key := range t.latest {
+		if _, ok := t.pending[key]; !ok {
+			delete(t.latest, sessionID)
+		}
+	}
+}
+
+func pendingTurnKey(sessionID, turnID string) string {
+	return strings.TrimSpace(sessionID) + "\x00" + strings.TrimSpace(turnID)
+}
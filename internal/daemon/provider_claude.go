@@ -2,6 +2,7 @@ package daemon
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"errors"
 	"io"
@@ -25,7 +26,16 @@ type claudeRunner struct {
 	dirs    []string
 	sink    ProviderSink
 	items   ProviderItemSink
+	usage   SessionUsageRecorder
 	options *types.SessionRuntimeOptions
+	sandbox *sessionSandbox
+	// instructions are appended to the system prompt of every run, since
+	// resumed runs do not keep it.
+	instructions string
+
+	// archonSessionID names the session usage is recorded for; sessionID is
+	// Claude's own session id.
+	archonSessionID string
 
 	mu        sync.Mutex
 	sessionID string
@@ -72,15 +82,20 @@ func (p *claudeProvider) Start(cfg StartSessionConfig, sink ProviderSink, items
 	}
 
 	runner := &claudeRunner{
-		cmdName:   p.cmdName,
-		cwd:       cfg.Cwd,
-		env:       append([]string{}, cfg.Env...),
-		dirs:      append([]string{}, cfg.AdditionalDirectories...),
-		sink:      sink,
-		items:     items,
-		options:   types.CloneRuntimeOptions(cfg.RuntimeOptions),
-		sessionID: strings.TrimSpace(cfg.ProviderSessionID),
-		onSession: cfg.OnProviderSessionID,
+		cmdName:      p.cmdName,
+		cwd:          cfg.Cwd,
+		env:          append([]string{}, cfg.Env...),
+		dirs:         append([]string{}, cfg.AdditionalDirectories...),
+		sink:         sink,
+		items:        items,
+		usage:        cfg.Usage,
+		options:      types.CloneRuntimeOptions(cfg.RuntimeOptions),
+		sandbox:      cfg.Sandbox,
+		sessionID:    strings.TrimSpace(cfg.ProviderSessionID),
+		instructions: cfg.Instructions,
+		onSession:    cfg.OnProviderSessionID,
+
+		archonSessionID: strings.TrimSpace(cfg.SessionID),
 	}
 
 	done := make(chan struct{})
@@ -117,15 +132,18 @@ func (r *claudeRunner) Send(payload []byte) error {
 	if len(payload) == 0 {
 		return errors.New("payload is required")
 	}
-	text, runtimeOptions, turnID, err := extractClaudeSendRequest(payload)
+	request, err := decodeClaudeSendRequest(payload)
 	if err != nil {
 		return err
 	}
-	if strings.TrimSpace(text) == "" {
+	if strings.TrimSpace(request.Text) == "" && len(request.Attachments) == 0 {
 		return errors.New("text is required")
 	}
-	r.appendUserItem(text, turnID)
-	return r.run(text, runtimeOptions)
+	r.appendUserItem(request.Text, request.Attachments, request.TurnID)
+	if len(request.Attachments) > 0 {
+		return r.runMessage(request.Message, request.RuntimeOptions)
+	}
+	return r.run(request.Text, request.RuntimeOptions)
 }
 
 func (r *claudeRunner) SendUser(text string) error {
@@ -134,6 +152,22 @@ func (r *claudeRunner) SendUser(text string) error {
 }
 
 func (r *claudeRunner) run(text string, runtimeOptions *types.SessionRuntimeOptions) error {
+	return r.runCLI(runtimeOptions, []string{text}, nil)
+}
+
+// runMessage sends a user message with non-text content blocks, which the
+// CLI only accepts as stream-json input.
+func (r *claudeRunner) runMessage(message map[string]any, runtimeOptions *types.SessionRuntimeOptions) error {
+	line, err := json.Marshal(map[string]any{"type": "user", "message": message})
+	if err != nil {
+		return err
+	}
+	return r.runCLI(runtimeOptions, []string{"--input-format", "stream-json"}, append(line, '\n'))
+}
+
+// runCLI runs one CLI turn. tailArgs follow the common flags; stdin, when set,
+// is written to the process and closed.
+func (r *claudeRunner) runCLI(runtimeOptions *types.SessionRuntimeOptions, tailArgs []string, stdin []byte) error {
 	effectiveOptions := types.MergeRuntimeOptions(r.options, runtimeOptions)
 	args := []string{
 		"--print",
@@ -145,6 +179,9 @@ func (r *claudeRunner) run(text string, runtimeOptions *types.SessionRuntimeOpti
 		return err
 	}
 	args = append(args, additionalDirArgs...)
+	if instructions := strings.TrimSpace(r.instructions); instructions != "" {
+		args = append(args, "--append-system-prompt", instructions)
+	}
 	if effectiveOptions != nil {
 		if model := strings.TrimSpace(effectiveOptions.Model); model != "" {
 			args = append(args, "--model", model)
@@ -160,13 +197,17 @@ func (r *claudeRunner) run(text string, runtimeOptions *types.SessionRuntimeOpti
 	if sessionID != "" {
 		args = append(args, "--resume", sessionID)
 	}
-	args = append(args, text)
+	args = append(args, tailArgs...)
 
 	cmd := exec.Command(r.cmdName, args...)
 	if r.cwd != "" {
 		cmd.Dir = r.cwd
 	}
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
 	cmd.Env = claudeCommandEnv(r.env)
+	r.sandbox.wrap(cmd)
 
 	stdoutPipe, err := cmd.StdoutPipe()
 	if err != nil {
@@ -189,11 +230,11 @@ func (r *claudeRunner) run(text string, runtimeOptions *types.SessionRuntimeOpti
 	wg.Add(2)
 	go func() {
 		defer wg.Done()
-		_ = readClaudeStream(stdoutPipe, "provider_stdout_raw", r.sink, r.items, r.updateSessionID)
+		_ = readClaudeStream(stdoutPipe, "provider_stdout_raw", r.sink, r.items, r.updateSessionID, r.recordUsage)
 	}()
 	go func() {
 		defer wg.Done()
-		_ = readClaudeStream(stderrPipe, "provider_stderr_raw", r.sink, r.items, r.updateSessionID)
+		_ = readClaudeStream(r.sandbox.watch(stderrPipe, r.sink), "provider_stderr_raw", r.sink, r.items, r.updateSessionID, nil)
 	}()
 
 	err = cmd.Wait()
@@ -204,15 +245,13 @@ func (r *claudeRunner) run(text string, runtimeOptions *types.SessionRuntimeOpti
 	return err
 }
 
-func (r *claudeRunner) appendUserItem(text string, turnID string) {
+func (r *claudeRunner) appendUserItem(text string, attachments []types.InputAttachment, turnID string) {
 	if r == nil || r.items == nil {
 		return
 	}
 	item := map[string]any{
-		"type": "userMessage",
-		"content": []map[string]any{
-			{"type": "text", "text": text},
-		},
+		"type":    "userMessage",
+		"content": userMessageContent(text, attachments),
 	}
 	if turnID = strings.TrimSpace(turnID); turnID != "" {
 		item["turn_id"] = turnID
@@ -307,7 +346,14 @@ func interruptClaudeProcess(process *os.Process) {
 	}(process)
 }
 
-func readClaudeStream(r io.Reader, rawStream string, sink ProviderSink, items ProviderItemSink, onSessionID func(string)) error {
+func (r *claudeRunner) recordUsage(report sessionUsageReport) {
+	if r == nil || r.usage == nil {
+		return
+	}
+	r.usage.RecordUsage(r.archonSessionID, "claude", report)
+}
+
+func readClaudeStream(r io.Reader, rawStream string, sink ProviderSink, items ProviderItemSink, onSessionID func(string), onUsage func(sessionUsageReport)) error {
 	scanner := bufio.NewScanner(r)
 	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 	state := &ClaudeParseState{}
@@ -318,6 +364,11 @@ func readClaudeStream(r io.Reader, rawStream string, sink ProviderSink, items Pr
 		if line == "" {
 			continue
 		}
+		if onUsage != nil {
+			if report, ok := claudeUsageReport(line); ok {
+				onUsage(report)
+			}
+		}
 		parsedItems, sessionID, err := ParseClaudeLine(line, state)
 		if err != nil {
 			if items != nil {
@@ -367,16 +418,39 @@ func buildClaudeUserPayloadWithRuntime(text string, runtimeOptions *types.Sessio
 }
 
 func buildClaudeUserPayloadWithRuntimeAndTurn(text string, runtimeOptions *types.SessionRuntimeOptions, turnID string) []byte {
+	return buildClaudeUserPayloadWithAttachments(text, nil, nil, runtimeOptions, turnID)
+}
+
+// buildClaudeUserPayloadWithAttachments adds the content blocks of attached
+// files after the text. The attachments themselves ride along so the runner
+// can list them in the persisted user message.
+func buildClaudeUserPayloadWithAttachments(
+	text string,
+	attachments []types.InputAttachment,
+	attachmentBlocks []map[string]any,
+	runtimeOptions *types.SessionRuntimeOptions,
+	turnID string,
+) []byte {
 	text = strings.TrimSpace(text)
+	content := make([]map[string]any, 0, len(attachmentBlocks)+1)
+	if text != "" || len(attachmentBlocks) == 0 {
+		content = append(content, map[string]any{"type": "text", "text": text})
+	}
+	content = append(content, attachmentBlocks...)
 	payload := map[string]any{
 		"type": "user",
 		"message": map[string]any{
-			"role": "user",
-			"content": []map[string]any{
-				{"type": "text", "text": text},
-			},
+			"role":    "user",
+			"content": content,
 		},
 	}
+	if len(attachments) > 0 {
+		items := make([]map[string]any, 0, len(attachments))
+		for _, attachment := range attachments {
+			items = append(items, attachment.InputItem())
+		}
+		payload["attachments"] = items
+	}
 	if runtimeOptions != nil {
 		payload["runtime_options"] = runtimeOptions
 	}
@@ -395,29 +469,55 @@ func extractClaudeUserText(payload []byte) (string, error) {
 }
 
 func extractClaudeSendRequest(payload []byte) (string, *types.SessionRuntimeOptions, string, error) {
+	request, err := decodeClaudeSendRequest(payload)
+	if err != nil {
+		return "", nil, "", err
+	}
+	return request.Text, request.RuntimeOptions, request.TurnID, nil
+}
+
+// claudeSendRequest is a decoded user payload. Message is the raw user
+// message, kept for runs that need its non-text content blocks.
+type claudeSendRequest struct {
+	Text           string
+	Message        map[string]any
+	Attachments    []types.InputAttachment
+	RuntimeOptions *types.SessionRuntimeOptions
+	TurnID         string
+}
+
+func decodeClaudeSendRequest(payload []byte) (claudeSendRequest, error) {
 	var body map[string]any
 	if err := json.Unmarshal(payload, &body); err != nil {
-		return "", nil, "", err
+		return claudeSendRequest{}, err
 	}
 	if typ, _ := body["type"].(string); typ != "user" {
-		return "", nil, "", errors.New("unsupported payload type")
+		return claudeSendRequest{}, errors.New("unsupported payload type")
+	}
+	request := claudeSendRequest{Text: extractClaudeMessageText(body["message"])}
+	request.Message, _ = body["message"].(map[string]any)
+	if rawAttachments, ok := body["attachments"].([]any); ok {
+		for _, raw := range rawAttachments {
+			item, _ := raw.(map[string]any)
+			if attachment, ok := types.InputAttachmentFromItem(item); ok {
+				request.Attachments = append(request.Attachments, attachment)
+			}
+		}
 	}
-	text := extractClaudeMessageText(body["message"])
-	var runtimeOptions *types.SessionRuntimeOptions
 	if raw, ok := body["runtime_options"]; ok && raw != nil {
 		data, err := json.Marshal(raw)
 		if err == nil {
 			var parsed types.SessionRuntimeOptions
 			if err := json.Unmarshal(data, &parsed); err == nil {
-				runtimeOptions = &parsed
+				request.RuntimeOptions = &parsed
 			}
 		}
 	}
-	turnID := strings.TrimSpace(firstNonEmpty(
+	request.TurnID = strings.TrimSpace(firstNonEmpty(
 		asString(body["turn_id"]),
 		asString(body["turnID"]),
 	))
-	return text, runtimeOptions, turnID, nil
+	return request, nil
 }
 
 func claudeAccessToPermissionMode(level types.AccessLevel) string {
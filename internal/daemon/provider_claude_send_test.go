@@ -92,7 +92,7 @@ func TestReadClaudeStreamParseErrorAndSessionID(t *testing.T) {
 		"{\"type\":\"system\",\"subtype\":\"init\",\"session_id\":\"abc123\"}\n")
 	if err := readClaudeStream(stream, "provider_stdout_raw", logSink, itemSink, func(id string) {
 		sessionID = id
-	}); err != nil {
+	}, nil); err != nil {
 		t.Fatalf("readClaudeStream: %v", err)
 	}
 
@@ -126,7 +126,7 @@ func TestBuildClaudeUserPayloadAndExtractText(t *testing.T) {
 func TestClaudeRunnerAppendUserItem(t *testing.T) {
 	items := &testItemSink{}
 	runner := &claudeRunner{items: items}
-	runner.appendUserItem("hello", "turn-1")
+	runner.appendUserItem("hello", nil, "turn-1")
 	if items.Len() != 1 {
 		t.Fatalf("expected one item, got %d", items.Len())
 	}
@@ -66,6 +66,7 @@ func (p *codexProvider) Start(cfg StartSessionConfig, sink ProviderSink, items P
 	if len(env) > 0 {
 		cmd.Env = env
 	}
+	cfg.Sandbox.wrap(cmd)
 
 	stdoutPipe, err := cmd.StdoutPipe()
 	if err != nil {
@@ -85,7 +86,7 @@ func (p *codexProvider) Start(cfg StartSessionConfig, sink ProviderSink, items P
 	}
 
 	go func() {
-		_, _ = io.Copy(sink.StderrWriter(), stderrPipe)
+		_, _ = io.Copy(sink.StderrWriter(), cfg.Sandbox.watch(stderrPipe, sink))
 	}()
 
 	controller := newCodexController(stdinPipe, stdoutPipe, sink)
@@ -105,7 +106,7 @@ func (p *codexProvider) Start(cfg StartSessionConfig, sink ProviderSink, items P
 			model = override
 		}
 	}
-	threadID, err := controller.startThread(ctx, model, cfg.Cwd, cfg.RuntimeOptions)
+	threadID, err := controller.startThread(ctx, model, cfg.Cwd, cfg.Instructions, cfg.RuntimeOptions)
 	if err != nil {
 		sink.Write("stderr", []byte(fmt.Sprintf("codex thread/start failed: model=%s error=%v\n", model, err)))
 		_ = cmd.Process.Kill()
@@ -302,13 +303,16 @@ func (c *codexController) initialize(ctx context.Context) error {
 	return c.notify("initialized", map[string]any{})
 }
 
-func (c *codexController) startThread(ctx context.Context, model, cwd string, runtimeOptions *types.SessionRuntimeOptions) (string, error) {
+func (c *codexController) startThread(ctx context.Context, model, cwd, instructions string, runtimeOptions *types.SessionRuntimeOptions) (string, error) {
 	params := map[string]any{
 		"model": model,
 	}
 	if cwd != "" {
 		params["cwd"] = cwd
 	}
+	if instructions != "" {
+		params["developerInstructions"] = instructions
+	}
 	if opts := codexThreadOptions(runtimeOptions); len(opts) > 0 {
 		for key, value := range opts {
 			params[key] = value
@@ -74,7 +74,7 @@ func TestCodexControllerFlow(t *testing.T) {
 	if err := controller.initialize(ctx); err != nil {
 		t.Fatalf("initialize: %v", err)
 	}
-	threadID, err := controller.startThread(ctx, "gpt-5.1-codex", "", nil)
+	threadID, err := controller.startThread(ctx, "gpt-5.1-codex", "", "", nil)
 	if err != nil {
 		t.Fatalf("thread start: %v", err)
 	}
@@ -7,9 +7,11 @@ import (
 	"os"
 	"os/exec"
 	"strings"
-	"sync"
+	"time"
 )
 
+const execProviderWaitDelay = 2 * time.Second
+
 type execProvider struct {
 	providerName string
 	cmdName      string
@@ -49,40 +51,25 @@ func (p *execProvider) Start(cfg StartSessionConfig, sink ProviderSink, items Pr
 	if len(cfg.Env) > 0 {
 		cmd.Env = append(os.Environ(), cfg.Env...)
 	}
+	cfg.Sandbox.wrap(cmd)
 
-	stdoutPipe, err := cmd.StdoutPipe()
-	if err != nil {
-		return nil, err
-	}
-	stderrPipe, err := cmd.StderrPipe()
-	if err != nil {
-		return nil, err
+	// Letting exec copy the output makes Wait return only once it has been
+	// fully written; WaitDelay bounds that when a child keeps the pipes open.
+	stderr := sink.StderrWriter()
+	if violations := cfg.Sandbox.violationWriter(sink); violations != nil {
+		stderr = io.MultiWriter(stderr, violations)
 	}
+	cmd.Stdout = sink.StdoutWriter()
+	cmd.Stderr = stderr
+	cmd.WaitDelay = execProviderWaitDelay
 
 	if err := cmd.Start(); err != nil {
 		return nil, err
 	}
 
-	var copyWG sync.WaitGroup
-	copyWG.Add(2)
-	go func() {
-		defer copyWG.Done()
-		_, _ = io.Copy(sink.StdoutWriter(), stdoutPipe)
-	}()
-	go func() {
-		defer copyWG.Done()
-		_, _ = io.Copy(sink.StderrWriter(), stderrPipe)
-	}()
-
-	waitFn := func() error {
-		err := cmd.Wait()
-		copyWG.Wait()
-		return err
-	}
-
 	return &providerProcess{
 		Process: cmd.Process,
-		Wait:    waitFn,
+		Wait:    cmd.Wait,
 	}, nil
 }
 
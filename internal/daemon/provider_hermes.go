@@ -47,6 +47,7 @@ type hermesRuntime struct {
 	sessionID string
 	cwd       string
 	sink      ProviderSink
+	usage     SessionUsageRecorder
 
 	client   *acp.Client
 	process  *os.Process
@@ -166,6 +167,7 @@ func (p *hermesProvider) Start(cfg StartSessionConfig, sink ProviderSink, _ Prov
 		sessionID:       sessionID,
 		cwd:             strings.TrimSpace(cfg.Cwd),
 		sink:            sink,
+		usage:           cfg.Usage,
 		client:          client,
 		process:         client.Process(),
 		hub:             newCodexSubscriberHub(),
@@ -237,10 +239,22 @@ func (r *hermesRuntime) notificationLoop(sub <-chan acp.Notification) {
 		switch note.Method {
 		case acp.MethodSessionUpdate:
 			r.broadcast(note.Method, nil, note.Params)
+			if update, err := acp.DecodeSessionUpdate(note.Params); err == nil {
+				if report, ok := hermesUsageReport(update); ok {
+					r.recordUsage(report)
+				}
+			}
 		}
 	}
 }
 
+func (r *hermesRuntime) recordUsage(report sessionUsageReport) {
+	if r.usage == nil {
+		return
+	}
+	r.usage.RecordUsage(r.sessionID, "hermes", report)
+}
+
 func (r *hermesRuntime) waitLoop() {
 	err := r.client.Wait()
 	r.finishWait(err)
@@ -303,11 +317,11 @@ func (r *hermesRuntime) Send(payload []byte) error {
 	if text == "" {
 		return errors.New("text is required")
 	}
-	_, err = r.StartTurn(context.Background(), defaultTurnIDGenerator{}.NewTurnID("hermes"), text)
+	_, err = r.StartTurn(context.Background(), defaultTurnIDGenerator{}.NewTurnID("hermes"), text, nil)
 	return err
 }
 
-func (r *hermesRuntime) StartTurn(ctx context.Context, turnID, text string) (string, error) {
+func (r *hermesRuntime) StartTurn(ctx context.Context, turnID, text string, attachments []types.InputAttachment) (string, error) {
 	if r == nil {
 		return "", errors.New("hermes runtime is not initialized")
 	}
@@ -316,9 +330,20 @@ func (r *hermesRuntime) StartTurn(ctx context.Context, turnID, text string) (str
 		turnID = defaultTurnIDGenerator{}.NewTurnID("hermes")
 	}
 	text = strings.TrimSpace(text)
-	if text == "" {
+	if text == "" && len(attachments) == 0 {
 		return "", errors.New("text is required")
 	}
+	prompt := make([]acp.ContentBlock, 0, len(attachments)+1)
+	if text != "" {
+		prompt = append(prompt, acp.ContentBlock{Type: acp.ContentBlockText, Text: text})
+	}
+	if len(attachments) > 0 {
+		blocks, err := acpAttachmentContentBlocks(attachments, r.client.AgentCapabilities().PromptCapabilities)
+		if err != nil {
+			return "", invalidError(err.Error(), err)
+		}
+		prompt = append(prompt, blocks...)
+	}
 
 	r.mu.Lock()
 	if r.closed {
@@ -329,11 +354,11 @@ func (r *hermesRuntime) StartTurn(ctx context.Context, turnID, text string) (str
 		r.mu.Unlock()
 		return "", errors.New("hermes turn already in progress")
 	}
-	prompt := &hermesPromptState{
+	pending := &hermesPromptState{
 		turnID: turnID,
 		done:   make(chan error, 1),
 	}
-	r.pendingPrompt = prompt
+	r.pendingPrompt = pending
 	r.activeTurn = turnID
 	r.mu.Unlock()
 
@@ -345,9 +370,7 @@ func (r *hermesRuntime) StartTurn(ctx context.Context, turnID, text string) (str
 		var result acp.PromptResult
 		err := r.client.Call(ctx, acp.MethodSessionPrompt, acp.PromptParams{
 			SessionID: r.threadID,
-			Prompt: []acp.ContentBlock{
-				{Type: "text", Text: text},
-			},
+			Prompt:    prompt,
 		}, &result)
 
 		if err != nil {
@@ -355,10 +378,13 @@ func (r *hermesRuntime) StartTurn(ctx context.Context, turnID, text string) (str
 				"turnId": turnID,
 				"error":  strings.TrimSpace(err.Error()),
 			}))
-			r.completePrompt(prompt, err)
+			r.completePrompt(pending, err)
 			return
 		}
 
+		if report, ok := hermesPromptUsageReport(result); ok {
+			r.recordUsage(report)
+		}
 		method := "turn/completed"
 		if strings.EqualFold(strings.TrimSpace(result.StopReason), acp.StopReasonCancelled) {
 			method = "turn/failed"
@@ -371,7 +397,7 @@ func (r *hermesRuntime) StartTurn(ctx context.Context, turnID, text string) (str
 			payload["error"] = "turn cancelled"
 		}
 		r.broadcast(method, nil, mustMarshalJSON(payload))
-		r.completePrompt(prompt, nil)
+		r.completePrompt(pending, nil)
 	}()
 
 	return turnID, nil
@@ -623,16 +649,16 @@ func (s *hermesLiveSession) SetSessionMeta(meta *types.SessionMeta) {
 }
 
 func (s *hermesLiveSession) StartTurn(ctx context.Context, input []map[string]any, _ *types.SessionRuntimeOptions) (string, error) {
-	text := extractTextInput(input)
-	if text == "" {
-		return "", invalidError("text input is required", nil)
+	text, attachments, err := inputTextOrAttachments(input)
+	if err != nil {
+		return "", err
 	}
 	runtime := s.runtime()
 	if runtime == nil {
 		return "", unavailableError("hermes session ended", errHermesSessionEnded)
 	}
 	turnID := defaultTurnIDGenerator{}.NewTurnID("hermes")
-	return runtime.StartTurn(ctx, turnID, text)
+	return runtime.StartTurn(ctx, turnID, text, attachments)
 }
 
 func (s *hermesLiveSession) Interrupt(ctx context.Context) error {
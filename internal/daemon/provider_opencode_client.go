@@ -209,17 +209,42 @@ func (c *openCodeClient) Prompt(ctx context.Context, sessionID, text string, run
 	if err := c.validateRuntimeModel(ctx, runtimeOptions); err != nil {
 		return "", err
 	}
-	return c.promptService.Prompt(ctx, sessionID, text, runtimeOptions, directory)
+	parts, err := openCodePromptParts(text, nil)
+	if err != nil {
+		return "", err
+	}
+	return c.promptService.Prompt(ctx, sessionID, parts, runtimeOptions, directory)
 }
 
-func (c *openCodeClient) PromptAsync(ctx context.Context, sessionID, text string, runtimeOptions *types.SessionRuntimeOptions, directory string) error {
+func (c *openCodeClient) PromptAsync(ctx context.Context, sessionID, text string, attachments []types.InputAttachment, runtimeOptions *types.SessionRuntimeOptions, directory string) error {
 	if c == nil || c.promptService == nil {
 		return errors.New("prompt service is required")
 	}
 	if err := c.validateRuntimeModel(ctx, runtimeOptions); err != nil {
 		return err
 	}
-	return c.promptService.PromptAsync(ctx, sessionID, text, runtimeOptions, directory)
+	parts, err := openCodePromptParts(text, attachments)
+	if err != nil {
+		return err
+	}
+	return c.promptService.PromptAsync(ctx, sessionID, parts, runtimeOptions, directory)
+}
+
+// openCodePromptParts builds the message parts of a prompt: the text, then
+// one file part per attachment.
+func openCodePromptParts(text string, attachments []types.InputAttachment) ([]map[string]any, error) {
+	parts := []map[string]any{}
+	if text = strings.TrimSpace(text); text != "" {
+		parts = append(parts, map[string]any{
+			"type": "text",
+			"text": text,
+		})
+	}
+	fileParts, err := openCodeAttachmentParts(attachments)
+	if err != nil {
+		return nil, err
+	}
+	return append(parts, fileParts...), nil
 }
 
 func (c *openCodeClient) resolveRuntimeModel(ctx context.Context, runtimeOptions *types.SessionRuntimeOptions) map[string]string {
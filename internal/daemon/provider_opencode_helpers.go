@@ -202,11 +202,14 @@ func (openCodeMessageEventMapper) Map(
 ) []types.CodexEvent {
 	switch eventType {
 	case "message.updated":
-		delta := openCodeEventMessageDelta(properties)
-		if delta == "" {
-			return nil
+		var events []types.CodexEvent
+		if usage, ok := openCodeMessageUsagePayload(properties); ok {
+			events = append(events, build("thread/tokenUsage/updated", nil, usage))
+		}
+		if delta := openCodeEventMessageDelta(properties); delta != "" {
+			events = append(events, build("item/agentMessage/delta", nil, map[string]any{"delta": delta}))
 		}
-		return []types.CodexEvent{build("item/agentMessage/delta", nil, map[string]any{"delta": delta})}
+		return events
 	case "message.part.updated":
 		part, _ := properties["part"].(map[string]any)
 		if part == nil {
@@ -1411,6 +1414,10 @@ func normalizeApprovalDecision(raw string) string {
 }
 
 func normalizeOpenCodePermissionResponse(raw string) string {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "always", "allow_always", "acceptforsession":
+		return "always"
+	}
 	switch normalizeApprovalDecision(raw) {
 	case "accept":
 		return "once"
@@ -309,7 +309,7 @@ func (s *openCodeSessionService) waitForAssistantReply(ctx context.Context, sess
 	return ""
 }
 
-func (s *openCodePromptService) Prompt(ctx context.Context, sessionID, text string, runtimeOptions *types.SessionRuntimeOptions, directory string) (string, error) {
+func (s *openCodePromptService) Prompt(ctx context.Context, sessionID string, parts []map[string]any, runtimeOptions *types.SessionRuntimeOptions, directory string) (string, error) {
 	if s == nil || s.requester == nil || s.sessions == nil {
 		return "", errors.New("prompt service dependencies are required")
 	}
@@ -317,17 +317,11 @@ func (s *openCodePromptService) Prompt(ctx context.Context, sessionID, text stri
 	if sessionID == "" {
 		return "", fmt.Errorf("session id is required")
 	}
-	text = strings.TrimSpace(text)
-	if text == "" {
+	if len(parts) == 0 {
 		return "", fmt.Errorf("text is required")
 	}
 	body := map[string]any{
-		"parts": []map[string]any{
-			{
-				"type": "text",
-				"text": text,
-			},
-		},
+		"parts": parts,
 	}
 	if s.modelProvider != nil {
 		if model := s.modelProvider.resolveRuntimeModel(ctx, runtimeOptions); len(model) > 0 {
@@ -384,7 +378,7 @@ func (s *openCodePromptService) Prompt(ctx context.Context, sessionID, text stri
 	return "", lastErr
 }
 
-func (s *openCodePromptService) PromptAsync(ctx context.Context, sessionID, text string, runtimeOptions *types.SessionRuntimeOptions, directory string) error {
+func (s *openCodePromptService) PromptAsync(ctx context.Context, sessionID string, parts []map[string]any, runtimeOptions *types.SessionRuntimeOptions, directory string) error {
 	if s == nil || s.requester == nil {
 		return errors.New("prompt service dependencies are required")
 	}
@@ -392,17 +386,11 @@ func (s *openCodePromptService) PromptAsync(ctx context.Context, sessionID, text
 	if sessionID == "" {
 		return fmt.Errorf("session id is required")
 	}
-	text = strings.TrimSpace(text)
-	if text == "" {
+	if len(parts) == 0 {
 		return fmt.Errorf("text is required")
 	}
 	body := map[string]any{
-		"parts": []map[string]any{
-			{
-				"type": "text",
-				"text": text,
-			},
-		},
+		"parts": parts,
 	}
 	if s.modelProvider != nil {
 		if model := s.modelProvider.resolveRuntimeModel(ctx, runtimeOptions); len(model) > 0 {
@@ -415,7 +403,7 @@ func (s *openCodePromptService) PromptAsync(ctx context.Context, sessionID, text
 	path := appendOpenCodeDirectoryQuery(fmt.Sprintf("/session/%s/prompt_async", url.PathEscape(sessionID)), directory)
 	if err := s.requester.doJSON(sendCtx, http.MethodPost, path, body, nil); err != nil {
 		if openCodeShouldFallbackLegacy(err) {
-			_, promptErr := s.Prompt(ctx, sessionID, text, runtimeOptions, directory)
+			_, promptErr := s.Prompt(ctx, sessionID, parts, runtimeOptions, directory)
 			return promptErr
 		}
 		return err
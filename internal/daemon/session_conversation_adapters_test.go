@@ -1667,6 +1667,10 @@ type stubApprovalStore struct {
 	}
 }
 
+func (s *stubApprovalStore) List(context.Context) ([]*types.Approval, error) {
+	return nil, nil
+}
+
 func (s *stubApprovalStore) ListBySession(context.Context, string) ([]*types.Approval, error) {
 	return nil, nil
 }
@@ -0,0 +1,75 @@
+package daemon
+
+import (
+	"context"
+	"strings"
+
+	"control/internal/providers"
+	"control/internal/types"
+)
+
+const defaultForkInstruction = "Continue this work from where the previous session left off."
+
+type ForkSessionRequest struct {
+	Title  string `json:"title,omitempty"`
+	Text   string `json:"text,omitempty"`
+	Blocks int    `json:"blocks,omitempty"`
+}
+
+// Fork starts a new session with the provider, directory and runtime
+// options of session id. Its first message quotes the parent's recent
+// messages the same way a session mention does, followed by req.Text.
+func (s *SessionService) Fork(ctx context.Context, id string, req ForkSessionRequest) (*types.Session, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, invalidError("session id is required", nil)
+	}
+	parent, _, err := s.getSessionRecord(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		return nil, notFoundError("session not found", ErrSessionNotFound)
+	}
+	meta := s.getSessionMeta(ctx, id)
+	mention := types.InputMention{Kind: types.InputMentionSession, ID: parent.ID, Blocks: req.Blocks}
+	quoted, err := s.sessionMentionText(ctx, mention)
+	if err != nil {
+		return nil, err
+	}
+	return s.Start(ctx, forkStartRequest(parent, meta, mention, quoted, req))
+}
+
+func forkStartRequest(parent *types.Session, meta *types.SessionMeta, mention types.InputMention, quoted string, req ForkSessionRequest) StartSessionRequest {
+	instruction := strings.TrimSpace(req.Text)
+	if instruction == "" {
+		instruction = defaultForkInstruction
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		parentTitle := strings.TrimSpace(parent.Title)
+		if meta != nil && strings.TrimSpace(meta.Title) != "" {
+			parentTitle = strings.TrimSpace(meta.Title)
+		}
+		if parentTitle == "" {
+			parentTitle = parent.ID
+		}
+		title = "Fork of " + parentTitle
+	}
+	start := StartSessionRequest{
+		Provider: parent.Provider,
+		Cwd:      parent.Cwd,
+		Title:    title,
+		Text:     "Forked from " + mention.Token() + ".\n\n" + types.MentionContext(mention.Token(), quoted) + "\n\n" + instruction,
+	}
+	if def, ok := providers.Lookup(parent.Provider); ok && def.Runtime == providers.RuntimeCustom {
+		start.Cmd = parent.Cmd
+		start.Env = append([]string(nil), parent.Env...)
+	}
+	if meta != nil {
+		start.WorkspaceID = meta.WorkspaceID
+		start.WorktreeID = meta.WorktreeID
+		start.RuntimeOptions = types.CloneRuntimeOptions(meta.RuntimeOptions)
+	}
+	return start
+}
@@ -0,0 +1,63 @@
+package daemon
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"control/internal/types"
+)
+
+func TestForkStartRequestCarriesParentContext(t *testing.T) {
+	parent := &types.Session{ID: "s1", Provider: "codex", Cwd: "/repo", Cmd: "codex", Title: "raw"}
+	meta := &types.SessionMeta{
+		SessionID:      "s1",
+		Title:          "Auth refactor",
+		WorkspaceID:    "ws1",
+		WorktreeID:     "wt1",
+		RuntimeOptions: &types.SessionRuntimeOptions{Model: "gpt-5.1-codex"},
+	}
+	mention := types.InputMention{Kind: types.InputMentionSession, ID: "s1"}
+
+	req := forkStartRequest(parent, meta, mention, "user: hi", ForkSessionRequest{Text: "try the other approach"})
+
+	if req.Provider != "codex" || req.Cwd != "/repo" || req.WorkspaceID != "ws1" || req.WorktreeID != "wt1" {
+		t.Fatalf("expected parent location and provider, got %#v", req)
+	}
+	if req.Cmd != "" {
+		t.Fatalf("expected command to be resolved by the provider, got %q", req.Cmd)
+	}
+	if req.RuntimeOptions == nil || req.RuntimeOptions.Model != "gpt-5.1-codex" {
+		t.Fatalf("expected runtime options copied, got %#v", req.RuntimeOptions)
+	}
+	if req.Title != "Fork of Auth refactor" {
+		t.Fatalf("unexpected title %q", req.Title)
+	}
+	if got := types.CollapseMentionContext(req.Text); got != "Forked from @session:s1.\n\ntry the other approach" {
+		t.Fatalf("unexpected collapsed text %q", got)
+	}
+	if !strings.Contains(req.Text, "user: hi") {
+		t.Fatalf("expected quoted transcript in text, got %q", req.Text)
+	}
+
+	custom := forkStartRequest(&types.Session{ID: "c1", Provider: "custom", Cmd: "/bin/agent"}, nil, mention, "", ForkSessionRequest{})
+	if custom.Cmd != "/bin/agent" || custom.Title != "Fork of c1" || !strings.HasSuffix(custom.Text, defaultForkInstruction) {
+		t.Fatalf("unexpected custom fork request %#v", custom)
+	}
+}
+
+func TestAPISessionForkUnknownSession(t *testing.T) {
+	server := newTestServer(t, newTestManager(t))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/v1/sessions/missing/fork", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("fork: %v", err)
+	}
+	defer closeTestCloser(t, resp.Body)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+}
@@ -42,6 +42,11 @@ type StartSessionConfig struct {
 	ProviderSessionID     string
 	NotificationOverrides *types.NotificationSettingsPatch
 	OnProviderSessionID   func(string)
+	Sandbox               *sessionSandbox
+	Instructions          string
+	InstructionsFile      string
+	// Usage receives the token usage the provider reports.
+	Usage SessionUsageRecorder
 }
 
 type SessionManager struct {
@@ -49,9 +54,12 @@ type SessionManager struct {
 	baseDir      string
 	sessions     map[string]*sessionRuntime
 	metaStore    SessionMetaStore
+	envResolver  SessionEnvResolver
+	instructions SessionInstructionsResolver
 	sessionStore SessionIndexStore
 	notifier     NotificationPublisher
 	metadata     MetadataEventPublisher
+	usage        SessionUsageRecorder
 	emitter      SessionLifecycleEmitter
 	defaultEmit  bool
 	logger       logging.Logger
@@ -121,6 +129,7 @@ func (m *SessionManager) buildSessionRuntime(sessionID, provider string) (*sessi
 			debugSink.Close()
 			return nil, err
 		}
+		items.observer = newRateLimitNotifier(m, sessionID, provider)
 	}
 
 	state := &sessionRuntime{
@@ -189,6 +198,99 @@ func (m *SessionManager) SetMetaStore(store SessionMetaStore) {
 	}
 }
 
+func (m *SessionManager) SetEnvResolver(resolver SessionEnvResolver) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.envResolver = resolver
+}
+
+// applyProfileEnv injects the session's env profile into cfg.Env and returns
+// the environment to persist, with secret values redacted. sessionID is set
+// when resuming a session.
+func (m *SessionManager) applyProfileEnv(cfg *StartSessionConfig, sessionID string) ([]string, error) {
+	m.mu.Lock()
+	resolver := m.envResolver
+	metaStore := m.metaStore
+	m.mu.Unlock()
+	requested := stripRedactedEnv(cfg.Env)
+	if resolver == nil {
+		cfg.Env = requested
+		return append([]string{}, requested...), nil
+	}
+	workspaceID, worktreeID := cfg.WorkspaceID, cfg.WorktreeID
+	if strings.TrimSpace(workspaceID) == "" && metaStore != nil && sessionID != "" {
+		if meta, ok, err := metaStore.Get(context.Background(), sessionID); err == nil && ok && meta != nil {
+			workspaceID, worktreeID = meta.WorkspaceID, meta.WorktreeID
+		}
+	}
+	profileEnv, secrets, err := resolver.ResolveSessionEnv(context.Background(), workspaceID, worktreeID)
+	if err != nil {
+		return nil, err
+	}
+	if sessionID != "" {
+		// The persisted env of a resumed session carries the profile values
+		// it started with; the current profile replaces them.
+		requested = stripSessionEnvNames(requested, profileEnv)
+	}
+	cfg.Env = mergeSessionEnv(profileEnv, requested)
+	return redactSessionEnv(cfg.Env, secrets), nil
+}
+
+func (m *SessionManager) SetInstructionsResolver(resolver SessionInstructionsResolver) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.instructions = resolver
+}
+
+// applyWorkspaceInstructions hands the workspace's instructions to the
+// provider through its instruction adapter. Prompt prefixes are only applied
+// when a session starts; a resumed conversation already carries them.
+func (m *SessionManager) applyWorkspaceInstructions(cfg *StartSessionConfig, sessionID string, resume bool) (*types.SessionInstructions, error) {
+	m.mu.Lock()
+	resolver := m.instructions
+	metaStore := m.metaStore
+	baseDir := m.baseDir
+	m.mu.Unlock()
+	if resolver == nil {
+		return nil, nil
+	}
+	workspaceID := cfg.WorkspaceID
+	if strings.TrimSpace(workspaceID) == "" && metaStore != nil && sessionID != "" {
+		if meta, ok, err := metaStore.Get(context.Background(), sessionID); err == nil && ok && meta != nil {
+			workspaceID = meta.WorkspaceID
+		}
+	}
+	text, applied, err := resolver.ResolveSessionInstructions(context.Background(), workspaceID)
+	if err != nil || applied == nil {
+		return nil, err
+	}
+	adapter := instructionAdapterForProvider(cfg.Provider)
+	applied.Strategy = adapter.Strategy()
+	if text == "" || (resume && applied.Strategy == types.InstructionStrategyPromptPrefix) {
+		return applied, nil
+	}
+	if err := adapter.Apply(cfg, text, filepath.Join(baseDir, sessionID), applied); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+func (m *SessionManager) upsertSessionInstructions(sessionID string, applied *types.SessionInstructions) {
+	if applied == nil {
+		return
+	}
+	m.mu.Lock()
+	store := m.metaStore
+	m.mu.Unlock()
+	if store == nil {
+		return
+	}
+	_, _ = store.Upsert(context.Background(), &types.SessionMeta{
+		SessionID:    sessionID,
+		Instructions: types.CloneSessionInstructions(applied),
+	})
+}
+
 func (m *SessionManager) SetSessionStore(store SessionIndexStore) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -203,6 +305,26 @@ func (m *SessionManager) SetNotificationPublisher(notifier NotificationPublisher
 	m.defaultEmit = true
 }
 
+func (m *SessionManager) notificationPublisher() NotificationPublisher {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.notifier
+}
+
+// SetUsageRecorder routes the token usage reported by the providers of
+// started sessions to recorder.
+func (m *SessionManager) SetUsageRecorder(recorder SessionUsageRecorder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.usage = recorder
+}
+
+func (m *SessionManager) usageRecorder() SessionUsageRecorder {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.usage
+}
+
 func (m *SessionManager) SetMetadataEventPublisher(publisher MetadataEventPublisher) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -236,6 +358,18 @@ func (m *SessionManager) StartSession(cfg StartSessionConfig) (*types.Session, e
 		return nil, err
 	}
 
+	persistedEnv, err := m.applyProfileEnv(&cfg, "")
+	if err != nil {
+		return nil, err
+	}
+	instructions, err := m.applyWorkspaceInstructions(&cfg, sessionID, false)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Sandbox, err = newSessionSandbox(loadCoreConfigOrDefault(), cfg); err != nil {
+		return nil, err
+	}
+
 	runtimeState, err := m.buildSessionRuntime(sessionID, cfg.Provider)
 	if err != nil {
 		return nil, err
@@ -248,7 +382,7 @@ func (m *SessionManager) StartSession(cfg StartSessionConfig) (*types.Session, e
 		Cwd:       cfg.Cwd,
 		Cmd:       provider.Command(),
 		Args:      append([]string{}, cfg.Args...),
-		Env:       append([]string{}, cfg.Env...),
+		Env:       persistedEnv,
 		Status:    types.SessionStatusCreated,
 		CreatedAt: now,
 		Title:     cfg.Title,
@@ -268,6 +402,7 @@ func (m *SessionManager) StartSession(cfg StartSessionConfig) (*types.Session, e
 	}
 	cfg.SessionID = sessionID
 	caps := providers.CapabilitiesFor(cfg.Provider)
+	cfg.Usage = m.usageRecorder()
 	proc, err := provider.Start(cfg, runtimeState.sink, runtimeState.items)
 	if err != nil {
 		m.mu.Lock()
@@ -303,6 +438,7 @@ func (m *SessionManager) StartSession(cfg StartSessionConfig) (*types.Session, e
 	m.mu.Unlock()
 
 	m.upsertSessionMeta(cfg, session.ID, session.Status)
+	m.upsertSessionInstructions(session.ID, instructions)
 	m.upsertSessionThreadID(session.ID, proc.ThreadID)
 	m.upsertSessionProviderID(cfg.Provider, session.ID, proc.ThreadID)
 	m.upsertSessionRecord(session, sessionSourceInternal)
@@ -383,6 +519,15 @@ func (m *SessionManager) ResumeSession(cfg StartSessionConfig, session *types.Se
 	if err != nil {
 		return nil, err
 	}
+	if _, err := m.applyProfileEnv(&cfg, session.ID); err != nil {
+		return nil, err
+	}
+	if _, err := m.applyWorkspaceInstructions(&cfg, session.ID, true); err != nil {
+		return nil, err
+	}
+	if cfg.Sandbox, err = newSessionSandbox(loadCoreConfigOrDefault(), cfg); err != nil {
+		return nil, err
+	}
 
 	runtimeState, err := m.buildSessionRuntime(session.ID, cfg.Provider)
 	if err != nil {
@@ -397,6 +542,7 @@ func (m *SessionManager) ResumeSession(cfg StartSessionConfig, session *types.Se
 
 	caps := providers.CapabilitiesFor(cfg.Provider)
 	cfg.SessionID = session.ID
+	cfg.Usage = m.usageRecorder()
 	proc, err := provider.Start(cfg, runtimeState.sink, runtimeState.items)
 	if err != nil {
 		m.mu.Lock()
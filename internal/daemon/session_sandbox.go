@@ -0,0 +1,360 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"control/internal/config"
+	"control/internal/providers"
+	"control/internal/types"
+	"control/internal/workspacepaths"
+)
+
+const sandboxViolationStream = "sandbox_violation"
+
+// sandboxSystemPaths are exposed read-only inside every sandbox when they
+// exist on the host.
+var sandboxSystemPaths = []string{
+	"/usr", "/bin", "/sbin", "/lib", "/lib32", "/lib64", "/etc", "/opt",
+	"/nix/store", "/run/current-system", "/run/systemd/resolve",
+}
+
+// sandboxProviderConfigPaths are the config and credential paths under $HOME
+// each provider runtime reads and updates (sessions, token refreshes). They
+// are bound writable into the sandbox of a session of that provider.
+var sandboxProviderConfigPaths = map[providers.Runtime][]string{
+	providers.RuntimeCodex:  {"~/.codex"},
+	providers.RuntimeClaude: {"~/.claude", "~/.claude.json"},
+}
+
+// sandboxViolationMarkers are stderr fragments that indicate a process ran
+// into the sandbox's filesystem or network restrictions.
+var sandboxViolationMarkers = []string{
+	"Read-only file system",
+	"Permission denied",
+	"Operation not permitted",
+	"Network is unreachable",
+	"Temporary failure in name resolution",
+	"Could not resolve host",
+	"EROFS",
+	"EACCES",
+	"ENETUNREACH",
+}
+
+// sessionSandbox runs a provider process under bubblewrap with only the
+// session's directories and read-only system paths visible. The access level
+// decides whether the directories are writable and whether the network is
+// available.
+type sessionSandbox struct {
+	command  string
+	cwd      string
+	network  bool
+	readOnly []string
+	writable []string
+}
+
+func newSessionSandbox(core config.CoreConfig, cfg StartSessionConfig) (*sessionSandbox, error) {
+	if !core.SandboxEnabled() {
+		return nil, nil
+	}
+	if runtime.GOOS != "linux" {
+		return nil, errors.New("sandbox is only supported on linux")
+	}
+	command, err := exec.LookPath(core.SandboxCommand())
+	if err != nil {
+		return nil, fmt.Errorf("sandbox command %s not found: %w", core.SandboxCommand(), err)
+	}
+	access := types.AccessOnRequest
+	if cfg.RuntimeOptions != nil {
+		if normalized, ok := types.NormalizeAccessLevel(cfg.RuntimeOptions.Access); ok && normalized != "" {
+			access = normalized
+		}
+	}
+	sandbox := &sessionSandbox{
+		command: command,
+		cwd:     strings.TrimSpace(cfg.Cwd),
+		network: access != types.AccessReadOnly || core.SandboxAllowNetwork(),
+	}
+	dirs := make([]string, 0, 1+len(cfg.AdditionalDirectories))
+	for _, dir := range append([]string{cfg.Cwd}, cfg.AdditionalDirectories...) {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	if access == types.AccessReadOnly {
+		sandbox.readOnly = append(sandbox.readOnly, dirs...)
+	} else {
+		sandbox.writable = append(sandbox.writable, dirs...)
+	}
+	if def, ok := providers.Lookup(cfg.Provider); ok {
+		for _, path := range sandboxProviderConfigPaths[def.Runtime] {
+			sandbox.writable = append(sandbox.writable, expandSandboxPath(path))
+		}
+	}
+	if home := strings.TrimSpace(cfg.CodexHome); home != "" {
+		sandbox.writable = append(sandbox.writable, home)
+	}
+	if file := strings.TrimSpace(cfg.InstructionsFile); file != "" {
+		sandbox.readOnly = append(sandbox.readOnly, file)
+	}
+	for _, path := range core.SandboxReadOnlyPaths() {
+		sandbox.readOnly = append(sandbox.readOnly, expandSandboxPath(path))
+	}
+	for _, path := range core.SandboxWritablePaths() {
+		sandbox.writable = append(sandbox.writable, expandSandboxPath(path))
+	}
+	return sandbox, nil
+}
+
+// SessionSandboxResolver builds the sandbox of a provider process started
+// outside the session manager, e.g. the Codex app server behind a live
+// session. It returns nil when the sandbox is disabled.
+type SessionSandboxResolver interface {
+	ResolveSessionSandbox(ctx context.Context, session *types.Session, meta *types.SessionMeta, codexHome string) (*sessionSandbox, error)
+}
+
+type workspaceSandboxResolver struct {
+	stores *Stores
+	core   func() config.CoreConfig
+}
+
+func newWorkspaceSandboxResolver(stores *Stores) SessionSandboxResolver {
+	return &workspaceSandboxResolver{stores: stores, core: loadCoreConfigOrDefault}
+}
+
+func (r *workspaceSandboxResolver) ResolveSessionSandbox(ctx context.Context, session *types.Session, meta *types.SessionMeta, codexHome string) (*sessionSandbox, error) {
+	core := r.core()
+	if !core.SandboxEnabled() || session == nil {
+		return nil, nil
+	}
+	cfg := StartSessionConfig{Provider: session.Provider, Cwd: session.Cwd, CodexHome: codexHome}
+	if meta != nil {
+		cfg.RuntimeOptions = meta.RuntimeOptions
+		dirs, err := r.additionalDirectories(ctx, session.Cwd, meta)
+		if err != nil {
+			return nil, err
+		}
+		cfg.AdditionalDirectories = dirs
+	}
+	return newSessionSandbox(core, cfg)
+}
+
+// additionalDirectories resolves the additional directories of the session's
+// workspace, or the linked worktrees of its worktree.
+func (r *workspaceSandboxResolver) additionalDirectories(ctx context.Context, cwd string, meta *types.SessionMeta) ([]string, error) {
+	if r.stores == nil || r.stores.Workspaces == nil || strings.TrimSpace(meta.WorkspaceID) == "" {
+		return nil, nil
+	}
+	ws, ok, err := r.stores.Workspaces.Get(ctx, meta.WorkspaceID)
+	if err != nil || !ok || ws == nil || len(ws.AdditionalDirectories) == 0 {
+		return nil, err
+	}
+	if worktreeID := strings.TrimSpace(meta.WorktreeID); worktreeID != "" && r.stores.Worktrees != nil {
+		worktrees, err := r.stores.Worktrees.ListWorktrees(ctx, ws.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, wt := range worktrees {
+			if wt != nil && wt.ID == worktreeID && len(wt.LinkedWorktrees) > 0 {
+				return linkedAdditionalDirectories(ws, wt)
+			}
+		}
+	}
+	return workspacepaths.ResolveAdditionalDirectories(cwd, ws.AdditionalDirectories, nil)
+}
+
+func expandSandboxPath(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+// wrap rewrites cmd to run its program inside the sandbox. It is a no-op on a
+// nil sandbox.
+func (s *sessionSandbox) wrap(cmd *exec.Cmd) {
+	if s == nil || cmd == nil {
+		return
+	}
+	argv := append([]string{s.command}, s.args(cmd.Path)...)
+	if len(cmd.Args) > 1 {
+		argv = append(argv, cmd.Args[1:]...)
+	}
+	cmd.Path = s.command
+	cmd.Args = argv
+}
+
+// args returns the bubblewrap arguments up to and including the program.
+// Later mounts shadow earlier ones, so writable session paths nested in a
+// read-only path stay writable.
+func (s *sessionSandbox) args(program string) []string {
+	args := []string{
+		"--die-with-parent",
+		"--new-session",
+		"--unshare-user",
+		"--unshare-pid",
+		"--unshare-ipc",
+		"--unshare-uts",
+		"--unshare-cgroup-try",
+	}
+	if !s.network {
+		args = append(args, "--unshare-net")
+	}
+	for _, path := range sandboxSystemPaths {
+		args = append(args, "--ro-bind-try", path, path)
+	}
+	args = append(args, "--proc", "/proc", "--dev", "/dev", "--tmpfs", "/tmp")
+	for _, dir := range sandboxProgramDirs(program) {
+		args = append(args, "--ro-bind-try", dir, dir)
+	}
+	for _, path := range s.readOnly {
+		args = append(args, "--ro-bind-try", path, path)
+	}
+	for _, path := range s.writable {
+		args = append(args, "--bind-try", path, path)
+	}
+	if s.cwd != "" {
+		args = append(args, "--chdir", s.cwd)
+	}
+	return append(args, "--", program)
+}
+
+// sandboxProgramDirs returns the directories program needs to exec: its own,
+// that of its symlink target (npm and nvm install bins as links into
+// lib/node_modules), and those of the interpreter named by its shebang.
+func sandboxProgramDirs(program string) []string {
+	if !filepath.IsAbs(program) {
+		return nil
+	}
+	var dirs []string
+	seen := map[string]bool{}
+	add := func(path string) {
+		for _, candidate := range []string{path, resolveSandboxSymlinks(path)} {
+			if dir := filepath.Dir(candidate); candidate != "" && !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	add(program)
+	if interpreter := sandboxInterpreter(resolveSandboxSymlinks(program)); interpreter != "" {
+		add(interpreter)
+	}
+	return dirs
+}
+
+func resolveSandboxSymlinks(path string) string {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return ""
+	}
+	return resolved
+}
+
+// sandboxInterpreter returns the absolute path of the interpreter named by the
+// shebang of the script at path, looking "#!/usr/bin/env name" up in PATH. It
+// returns "" for binaries and unknown interpreters.
+func sandboxInterpreter(path string) string {
+	if path == "" {
+		return ""
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	head := make([]byte, 256)
+	n, _ := io.ReadFull(file, head)
+	line, ok := bytes.CutPrefix(head[:n], []byte("#!"))
+	if !ok {
+		return ""
+	}
+	if end := bytes.IndexByte(line, '\n'); end >= 0 {
+		line = line[:end]
+	}
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 || !filepath.IsAbs(fields[0]) {
+		return ""
+	}
+	if filepath.Base(fields[0]) != "env" {
+		return fields[0]
+	}
+	for _, arg := range fields[1:] {
+		if strings.HasPrefix(arg, "-") || strings.Contains(arg, "=") {
+			continue
+		}
+		resolved, err := exec.LookPath(arg)
+		if err != nil || !filepath.IsAbs(resolved) {
+			return ""
+		}
+		return resolved
+	}
+	return ""
+}
+
+// violationWriter returns a writer that reports lines matching a sandbox
+// violation to the provider's debug stream, or nil without a sandbox.
+func (s *sessionSandbox) violationWriter(sink ProviderDebugSink) io.Writer {
+	if s == nil || sink == nil {
+		return nil
+	}
+	return &sandboxViolationWriter{sink: sink}
+}
+
+// watch tees r into violationWriter.
+func (s *sessionSandbox) watch(r io.Reader, sink ProviderDebugSink) io.Reader {
+	violations := s.violationWriter(sink)
+	if violations == nil {
+		return r
+	}
+	return io.TeeReader(r, violations)
+}
+
+type sandboxViolationWriter struct {
+	mu      sync.Mutex
+	sink    ProviderDebugSink
+	pending []byte
+}
+
+func (w *sandboxViolationWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending = append(w.pending, p...)
+	for {
+		idx := bytes.IndexByte(w.pending, '\n')
+		if idx < 0 {
+			break
+		}
+		w.inspect(w.pending[:idx])
+		w.pending = w.pending[idx+1:]
+	}
+	if len(w.pending) > 64*1024 {
+		w.inspect(w.pending)
+		w.pending = nil
+	}
+	return len(p), nil
+}
+
+func (w *sandboxViolationWriter) inspect(line []byte) {
+	text := strings.TrimSpace(string(line))
+	if text == "" {
+		return
+	}
+	for _, marker := range sandboxViolationMarkers {
+		if strings.Contains(text, marker) {
+			writeProviderDebug(w.sink, sandboxViolationStream, []byte(text+"\n"))
+			return
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"control/internal/config"
+	"control/internal/types"
+)
+
+// writeFakeSandbox writes a bwrap stand-in that records its arguments and
+// runs the program after "--" without isolating it.
+func writeFakeSandbox(t *testing.T) (string, string) {
+	t.Helper()
+	if runtime.GOOS != "linux" {
+		t.Skip("sandbox is linux only")
+	}
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "args.txt")
+	script := filepath.Join(dir, "fake-bwrap")
+	body := "#!/bin/sh\nprintf '%s\\n' \"$@\" > '" + argsFile + "'\nwhile [ \"$1\" != \"--\" ]; do shift; done\nshift\nexec \"$@\"\n"
+	if err := os.WriteFile(script, []byte(body), 0o755); err != nil {
+		t.Fatalf("write fake sandbox: %v", err)
+	}
+	return script, argsFile
+}
+
+func sandboxTestConfig(command string) config.CoreConfig {
+	core := config.DefaultCoreConfig()
+	enabled := true
+	core.Sandbox.Enabled = &enabled
+	core.Sandbox.Command = command
+	return core
+}
+
+func TestSessionSandboxDefaultKeepsProviderConfigAndNetwork(t *testing.T) {
+	script, _ := writeFakeSandbox(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	core := sandboxTestConfig(script)
+
+	sandbox, err := newSessionSandbox(core, StartSessionConfig{Provider: "claude", Cwd: "/work/repo"})
+	if err != nil {
+		t.Fatalf("newSessionSandbox: %v", err)
+	}
+	args := strings.Join(sandbox.args("/usr/bin/claude"), " ")
+	claudeDir := filepath.Join(home, ".claude")
+	claudeFile := filepath.Join(home, ".claude.json")
+	for _, want := range []string{"--bind-try /work/repo /work/repo", "--bind-try " + claudeDir + " " + claudeDir, "--bind-try " + claudeFile + " " + claudeFile} {
+		if !strings.Contains(args, want) {
+			t.Fatalf("expected %q in default sandbox args %q", want, args)
+		}
+	}
+	if strings.Contains(args, "--unshare-net") || strings.Contains(args, ".codex") {
+		t.Fatalf("expected network and only claude's config in default sandbox args %q", args)
+	}
+
+	sandbox, _ = newSessionSandbox(core, StartSessionConfig{Provider: "codex", Cwd: "/work/repo", RuntimeOptions: &types.SessionRuntimeOptions{Access: types.AccessReadOnly}})
+	codexDir := filepath.Join(home, ".codex")
+	if args = strings.Join(sandbox.args("/usr/bin/codex"), " "); !strings.Contains(args, "--bind-try "+codexDir+" "+codexDir) || !strings.Contains(args, "--unshare-net") {
+		t.Fatalf("expected read-only codex sandbox to keep its config and go offline by default, got %q", args)
+	}
+}
+
+func TestSessionSandboxMapsAccessLevels(t *testing.T) {
+	script, _ := writeFakeSandbox(t)
+	core := sandboxTestConfig(script)
+	cfg := StartSessionConfig{Cwd: "/work/repo", AdditionalDirectories: []string{"/work/shared"}}
+
+	cfg.RuntimeOptions = &types.SessionRuntimeOptions{Access: types.AccessReadOnly}
+	sandbox, err := newSessionSandbox(core, cfg)
+	if err != nil {
+		t.Fatalf("newSessionSandbox: %v", err)
+	}
+	args := strings.Join(sandbox.args("/usr/bin/agent"), " ")
+	for _, want := range []string{"--unshare-net", "--ro-bind-try /work/repo /work/repo", "--ro-bind-try /work/shared /work/shared", "--chdir /work/repo -- /usr/bin/agent"} {
+		if !strings.Contains(args, want) {
+			t.Fatalf("expected %q in read-only sandbox args %q", want, args)
+		}
+	}
+
+	online := true
+	core.Sandbox.AllowNetwork = &online
+	sandbox, _ = newSessionSandbox(core, cfg)
+	if args = strings.Join(sandbox.args("/usr/bin/agent"), " "); strings.Contains(args, "--unshare-net") {
+		t.Fatalf("expected allow_network to keep the network for read-only access, got %q", args)
+	}
+	core.Sandbox.AllowNetwork = nil
+
+	cfg.RuntimeOptions = nil
+	sandbox, _ = newSessionSandbox(core, cfg)
+	args = strings.Join(sandbox.args("/usr/bin/agent"), " ")
+	if !strings.Contains(args, "--bind-try /work/repo /work/repo") || strings.Contains(args, "--unshare-net") {
+		t.Fatalf("expected writable sandbox with network on request, got %q", args)
+	}
+
+	cfg.RuntimeOptions = &types.SessionRuntimeOptions{Access: types.AccessFull}
+	sandbox, _ = newSessionSandbox(core, cfg)
+	if args = strings.Join(sandbox.args("/usr/bin/agent"), " "); strings.Contains(args, "--unshare-net") {
+		t.Fatalf("expected full access to keep the network, got %q", args)
+	}
+
+	if sandbox, err := newSessionSandbox(config.DefaultCoreConfig(), cfg); err != nil || sandbox != nil {
+		t.Fatalf("expected no sandbox when disabled, got %#v (%v)", sandbox, err)
+	}
+	core.Sandbox.Command = "definitely-missing-sandbox-123456"
+	if _, err := newSessionSandbox(core, cfg); err == nil {
+		t.Fatalf("expected missing sandbox command to fail")
+	}
+}
+
+func TestSessionSandboxBindsSymlinkTargetAndInterpreter(t *testing.T) {
+	base := t.TempDir()
+	nodeDir := filepath.Join(base, "node", "bin")
+	pkgDir := filepath.Join(base, "lib", "node_modules", "agent")
+	binDir := filepath.Join(base, "bin")
+	for _, dir := range []string{nodeDir, pkgDir, binDir} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+	}
+	writeTestFile(t, filepath.Join(nodeDir, "node"), "#!/bin/sh\n")
+	if err := os.Chmod(filepath.Join(nodeDir, "node"), 0o755); err != nil {
+		t.Fatalf("Chmod: %v", err)
+	}
+	writeTestFile(t, filepath.Join(pkgDir, "cli.js"), "#!/usr/bin/env node\n")
+	program := filepath.Join(binDir, "agent")
+	if err := os.Symlink(filepath.Join(pkgDir, "cli.js"), program); err != nil {
+		t.Fatalf("Symlink: %v", err)
+	}
+	t.Setenv("PATH", nodeDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	dirs := strings.Join(sandboxProgramDirs(program), "\n")
+	for _, want := range []string{binDir, pkgDir, nodeDir} {
+		resolved, err := filepath.EvalSymlinks(want)
+		if err != nil {
+			t.Fatalf("EvalSymlinks: %v", err)
+		}
+		if !strings.Contains(dirs, want) && !strings.Contains(dirs, resolved) {
+			t.Fatalf("expected %s to be bound, got:\n%s", want, dirs)
+		}
+	}
+}
+
+func TestExecProviderRunsInSandboxAndReportsViolations(t *testing.T) {
+	script, argsFile := writeFakeSandbox(t)
+	cwd := t.TempDir()
+	cfg := StartSessionConfig{
+		Cwd:            cwd,
+		Args:           []string{"-c", "echo ok; echo \"touch: cannot touch '/etc/x': Read-only file system\" >&2"},
+		RuntimeOptions: &types.SessionRuntimeOptions{Access: types.AccessReadOnly},
+	}
+	sandbox, err := newSessionSandbox(sandboxTestConfig(script), cfg)
+	if err != nil {
+		t.Fatalf("newSessionSandbox: %v", err)
+	}
+	cfg.Sandbox = sandbox
+	provider, err := newExecProvider("custom", "sh", nil)
+	if err != nil {
+		t.Fatalf("newExecProvider: %v", err)
+	}
+	sink := &testProviderLogSink{}
+	proc, err := provider.Start(cfg, sink, nil)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := proc.Wait(); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	recorded, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("read sandbox args: %v", err)
+	}
+	if !strings.Contains(string(recorded), "--ro-bind-try\n"+cwd+"\n"+cwd+"\n") {
+		t.Fatalf("expected cwd to be bound read-only, got:\n%s", recorded)
+	}
+	if !strings.Contains(sink.stdoutString(), "ok") {
+		t.Fatalf("expected wrapped command output, got %q", sink.stdoutString())
+	}
+	sink.mu.Lock()
+	debug := sink.debug.String()
+	sink.mu.Unlock()
+	if !strings.Contains(debug, "Read-only file system") {
+		t.Fatalf("expected violation in debug stream, got %q", debug)
+	}
+}
+
+func TestCodexLiveManagerRunsAppServerInSandbox(t *testing.T) {
+	sandboxScript, argsFile := writeFakeSandbox(t)
+	ctx := context.Background()
+	base := t.TempDir()
+	home := filepath.Join(base, "home")
+	if err := os.MkdirAll(filepath.Join(home, ".archon"), 0o700); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	t.Setenv("HOME", home)
+	codex := filepath.Join(base, "codex")
+	if err := os.WriteFile(codex, []byte("#!/bin/sh\nexit 0\n"), 0o755); err != nil {
+		t.Fatalf("write fake codex: %v", err)
+	}
+	configBody := "[providers.codex]\ncommand = \"" + codex + "\"\n\n[sandbox]\nenabled = true\ncommand = \"" + sandboxScript + "\"\n"
+	if err := os.WriteFile(filepath.Join(home, ".archon", "config.toml"), []byte(configBody), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	stores := newTestStores(t)
+	repo := filepath.Join(base, "repo")
+	if err := ensureDir(repo); err != nil {
+		t.Fatalf("ensureDir: %v", err)
+	}
+	meta := &types.SessionMeta{SessionID: "s1", RuntimeOptions: &types.SessionRuntimeOptions{Access: types.AccessReadOnly}}
+	if _, err := stores.SessionMeta.Upsert(ctx, meta); err != nil {
+		t.Fatalf("Upsert meta: %v", err)
+	}
+
+	manager := NewCodexLiveManager(stores, nil)
+	manager.SetSandboxResolver(newWorkspaceSandboxResolver(stores))
+	// The fake app server exits right away, so starting the session fails
+	// after the sandbox has recorded its arguments.
+	if _, err := manager.ensure(ctx, &types.Session{ID: "s1", Provider: "codex", Cwd: repo}, meta, "", false); err == nil {
+		t.Fatalf("expected the fake app server to fail initialization")
+	}
+	recorded, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("expected the app server to start in the sandbox: %v", err)
+	}
+	args := string(recorded)
+	for _, want := range []string{"--ro-bind-try\n" + repo + "\n" + repo + "\n", "--\n" + codex + "\napp-server\n"} {
+		if !strings.Contains(args, want) {
+			t.Fatalf("expected %q in sandbox args:\n%s", want, args)
+		}
+	}
+}
+
+func TestProviderCommitMessageGeneratorUsesSessionEnvAndSandbox(t *testing.T) {
+	sandboxScript, argsFile := writeFakeSandbox(t)
+	ctx := context.Background()
+	base := t.TempDir()
+	home := filepath.Join(base, "home")
+	if err := os.MkdirAll(filepath.Join(home, ".archon"), 0o700); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	t.Setenv("HOME", home)
+	claude := filepath.Join(base, "claude")
+	if err := os.WriteFile(claude, []byte("#!/bin/sh\nprintf 'Update %s' \"$MODE\"\n"), 0o755); err != nil {
+		t.Fatalf("write fake claude: %v", err)
+	}
+	configBody := "[providers.claude]\ncommand = \"" + claude + "\"\n\n[sandbox]\nenabled = true\ncommand = \"" + sandboxScript + "\"\n"
+	if err := os.WriteFile(filepath.Join(home, ".archon", "config.toml"), []byte(configBody), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	stores := newTestStores(t)
+	repo := filepath.Join(base, "repo")
+	if err := ensureDir(repo); err != nil {
+		t.Fatalf("ensureDir: %v", err)
+	}
+	profiles, err := types.ParseEnvProfiles("ci: MODE=from-profile")
+	if err != nil {
+		t.Fatalf("ParseEnvProfiles: %v", err)
+	}
+	ws, err := stores.Workspaces.Add(ctx, &types.Workspace{RepoPath: repo, EnvProfiles: profiles, EnvProfile: "ci"})
+	if err != nil {
+		t.Fatalf("Add workspace: %v", err)
+	}
+	if _, err := stores.SessionMeta.Upsert(ctx, &types.SessionMeta{SessionID: "s1", WorkspaceID: ws.ID}); err != nil {
+		t.Fatalf("Upsert meta: %v", err)
+	}
+
+	generator := newProviderCommitMessageGenerator(stores)
+	message, err := generator.GenerateSessionCommitMessage(ctx, &types.Session{ID: "s1", Provider: "claude", Cwd: repo}, repo, "draft")
+	if err != nil {
+		t.Fatalf("GenerateSessionCommitMessage: %v", err)
+	}
+	if message != "Update from-profile" {
+		t.Fatalf("expected the provider to get the env profile, got %q", message)
+	}
+	recorded, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("expected the provider to run in the sandbox: %v", err)
+	}
+	if args := string(recorded); !strings.Contains(args, "--\n"+claude+"\n--print\n") {
+		t.Fatalf("expected the provider after the sandbox arguments:\n%s", args)
+	}
+}
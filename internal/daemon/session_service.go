@@ -12,6 +12,7 @@ import (
 	"control/internal/logging"
 	"control/internal/providers"
 	"control/internal/store"
+	"control/internal/tracing"
 	"control/internal/types"
 	"control/internal/workspacepaths"
 )
@@ -21,6 +22,8 @@ type SessionService struct {
 	stores                    *Stores
 	liveManager               LiveManager
 	titleGeneration           TitleGenerationQueue
+	commitMessages            CommitMessageGenerator
+	sessionCommitMessages     SessionCommitMessageGenerator
 	logger                    logging.Logger
 	paths                     WorkspacePathResolver
 	notifier                  NotificationPublisher
@@ -37,6 +40,9 @@ type SessionService struct {
 	transcriptFollowOpen      TranscriptFollowOpener
 	transcriptHubRegistry     CanonicalTranscriptHubRegistry
 	transcriptMu              sync.Mutex
+	metrics                   *daemonMetrics
+	tracing                   *daemonTracing
+	watchdog                  *turnWatchdog
 }
 
 type SendMessageOptions struct {
@@ -111,6 +117,33 @@ func WithTitleGenerationQueue(queue TitleGenerationQueue) SessionServiceOption {
 	}
 }
 
+func WithCommitMessageGenerator(generator CommitMessageGenerator) SessionServiceOption {
+	return func(s *SessionService) {
+		if s == nil || generator == nil {
+			return
+		}
+		s.commitMessages = generator
+	}
+}
+
+func WithSessionCommitMessageGenerator(generator SessionCommitMessageGenerator) SessionServiceOption {
+	return func(s *SessionService) {
+		if s == nil || generator == nil {
+			return
+		}
+		s.sessionCommitMessages = generator
+	}
+}
+
+func WithApprovalStorage(storage ApprovalRecordStorage) SessionServiceOption {
+	return func(s *SessionService) {
+		if s == nil || storage == nil || s.approvalSync == nil {
+			return
+		}
+		s.approvalSync.SetApprovalStorage(storage)
+	}
+}
+
 func WithTranscriptMapper(mapper TranscriptMapper) SessionServiceOption {
 	return func(s *SessionService) {
 		if s == nil || mapper == nil {
@@ -183,6 +216,38 @@ func WithCanonicalTranscriptHubRegistry(registry CanonicalTranscriptHubRegistry)
 	}
 }
 
+// WithDaemonMetrics records turn, approval and transcript hub metrics on m.
+// Without it the service records nothing.
+func WithDaemonMetrics(m *daemonMetrics) SessionServiceOption {
+	return func(s *SessionService) {
+		if s == nil || m == nil {
+			return
+		}
+		s.metrics = m
+	}
+}
+
+// WithDaemonTracing opens the service's spans on t and keeps turn spans open
+// until the turn completes.
+func WithDaemonTracing(t *daemonTracing) SessionServiceOption {
+	return func(s *SessionService) {
+		if s == nil || t == nil {
+			return
+		}
+		s.tracing = t
+	}
+}
+
+// WithTurnWatchdog reports turns that run past the watchdog threshold.
+func WithTurnWatchdog(w *turnWatchdog) SessionServiceOption {
+	return func(s *SessionService) {
+		if s == nil || w == nil {
+			return
+		}
+		s.watchdog = w
+	}
+}
+
 func NewSessionService(manager *SessionManager, stores *Stores, logger logging.Logger, opts ...SessionServiceOption) *SessionService {
 	if logger == nil {
 		logger = logging.Nop()
@@ -598,6 +663,30 @@ func (s *SessionService) Start(ctx context.Context, req StartSessionRequest) (*t
 		return nil, invalidError("provider is required", nil)
 	}
 
+	cwd := strings.TrimSpace(req.Cwd)
+	var isolated *types.Worktree
+	if cwd == "" && strings.TrimSpace(req.WorktreeID) == "" && s.stores != nil {
+		label := req.Title
+		if strings.TrimSpace(label) == "" {
+			label = strings.Join(req.Args, " ")
+		}
+		wt, err := s.workspaceService().ProvisionIsolatedWorktree(ctx, req.WorkspaceID, label)
+		if err != nil {
+			return nil, err
+		}
+		if wt != nil {
+			isolated = wt
+			req.WorktreeID = wt.ID
+		}
+	}
+	session, err := s.start(ctx, req)
+	if err != nil && isolated != nil {
+		s.workspaceService().ReleaseIsolatedWorktree(context.Background(), req.WorkspaceID, isolated)
+	}
+	return session, err
+}
+
+func (s *SessionService) start(ctx context.Context, req StartSessionRequest) (*types.Session, error) {
 	cwd := strings.TrimSpace(req.Cwd)
 	workspacePath := ""
 	var workspace *types.Workspace
@@ -621,7 +710,11 @@ func (s *SessionService) Start(ctx context.Context, req StartSessionRequest) (*t
 			workspace = ws
 		}
 	}
-	additionalDirectories, err := s.resolveAdditionalDirectoriesForWorkspace(cwd, workspace)
+	var worktree *types.Worktree
+	if workspace != nil && strings.TrimSpace(req.WorktreeID) != "" {
+		_, worktree = s.sessionWorkspace(ctx, workspace.ID, req.WorktreeID)
+	}
+	additionalDirectories, err := s.resolveAdditionalDirectoriesForWorkspace(cwd, workspace, worktree)
 	if err != nil {
 		return nil, invalidError(err.Error(), err)
 	}
@@ -740,12 +833,19 @@ func (s *SessionService) SendMessageWithOptions(ctx context.Context, id string,
 	if len(input) == 0 {
 		return "", invalidError("input is required", nil)
 	}
+	if err := validateInputAttachments(input); err != nil {
+		return "", invalidError(err.Error(), err)
+	}
 	s.logger.Info("send_lookup", logging.F("session_id", id))
 	session, _, err := s.getSessionRecord(ctx, id)
 	if session == nil {
 		s.logger.Warn("send_not_found", logging.F("session_id", id), logging.F("error", err))
 		return "", notFoundError("session not found", ErrSessionNotFound)
 	}
+	input, err = s.expandInputMentions(ctx, input)
+	if err != nil {
+		return "", err
+	}
 	meta := s.getSessionMeta(ctx, session.ID)
 	effectiveMeta := meta
 	var mergedRuntimeOptions *types.SessionRuntimeOptions
@@ -769,11 +869,27 @@ func (s *SessionService) SendMessageWithOptions(ctx context.Context, id string,
 			effectiveMeta.RuntimeOptions = mergedRuntimeOptions
 		}
 	}
+	input, instructionsDelivered := s.applyPendingInstructions(ctx, effectiveMeta, input)
 	s.ensureSessionCwd(ctx, session, effectiveMeta)
-	turnID, sendErr := s.sender(session.Provider).SendMessage(ctx, s.sendDeps(), session, effectiveMeta, input)
+	checkpoint := s.snapshotTurnCheckpoint(ctx, session)
+	ctx, turnSpan := s.tracing.Start(ctx, "session.turn", sessionSpanAttributes(session)...)
+	sendCtx, sendSpan := s.tracing.StartClient(ctx, "provider.send", sessionSpanAttributes(session)...)
+	turnID, sendErr := s.sender(session.Provider).SendMessage(sendCtx, s.sendDeps(), session, effectiveMeta, input)
+	sendSpan.RecordError(sendErr)
+	sendSpan.End()
 	if sendErr != nil {
+		turnSpan.RecordError(sendErr)
+		turnSpan.End()
 		return "", sendErr
 	}
+	turnSpan.SetAttributes(tracing.String("turn.id", turnID))
+	s.tracing.TurnStarted(session.ID, turnID, turnSpan)
+	s.metrics.TurnStarted(session.ID, turnID, session.Provider, time.Now().UTC())
+	s.watchdog.TurnStarted(session.ID, turnID, session.Provider, time.Now().UTC())
+	s.recordTurnCheckpoint(ctx, session.ID, turnID, checkpoint)
+	if instructionsDelivered {
+		s.markInstructionsDelivered(ctx, session.ID, effectiveMeta.Instructions)
+	}
 	if options.PersistRuntimeOption && mergedRuntimeOptions != nil {
 		if persistErr := s.persistRuntimeOptionsAfterSend(ctx, session.ID, mergedRuntimeOptions); persistErr != nil {
 			if s.logger != nil {
@@ -838,7 +954,34 @@ func (s *SessionService) Approve(ctx context.Context, id string, requestID int,
 	}
 	meta := s.getSessionMeta(ctx, session.ID)
 	s.ensureSessionCwd(ctx, session, meta)
-	return s.approver(session.Provider).Approve(ctx, s.approvalDeps(), session, meta, requestID, decision, responses, acceptSettings)
+	pending := s.pendingApproval(ctx, session.ID, requestID)
+	ctx, span := s.tracing.StartClient(ctx, "provider.approve", append(sessionSpanAttributes(session),
+		tracing.Int("approval.request_id", requestID),
+		tracing.String("approval.decision", decision),
+	)...)
+	defer span.End()
+	if err := s.approver(session.Provider).Approve(ctx, s.approvalDeps(), session, meta, requestID, decision, responses, acceptSettings); err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if pending != nil {
+		s.metrics.ApprovalResolved(session.Provider, pending.Method, pending.CreatedAt, time.Now().UTC())
+	}
+	if s.notifier != nil {
+		s.notifier.Publish(approvalResolvedNotificationEvent(session, requestID, decision))
+	}
+	return nil
+}
+
+func (s *SessionService) pendingApproval(ctx context.Context, sessionID string, requestID int) *types.Approval {
+	if s == nil || s.stores == nil || s.stores.Approvals == nil {
+		return nil
+	}
+	approval, ok, err := s.stores.Approvals.Get(ctx, sessionID, requestID)
+	if err != nil || !ok {
+		return nil
+	}
+	return approval
 }
 
 func (s *SessionService) ListApprovals(ctx context.Context, id string) ([]*types.Approval, error) {
@@ -884,7 +1027,11 @@ func (s *SessionService) InterruptTurn(ctx context.Context, id string) error {
 	}
 	meta := s.getSessionMeta(ctx, session.ID)
 	s.ensureSessionCwd(ctx, session, meta)
-	return s.interrupter(session.Provider).Interrupt(ctx, s.interruptDeps(), session, meta)
+	ctx, span := s.tracing.StartClient(ctx, "provider.interrupt", sessionSpanAttributes(session)...)
+	defer span.End()
+	err = s.interrupter(session.Provider).Interrupt(ctx, s.interruptDeps(), session, meta)
+	span.RecordError(err)
+	return err
 }
 
 func (s *SessionService) Get(ctx context.Context, id string) (*types.Session, error) {
@@ -1030,7 +1177,12 @@ func (s *SessionService) MarkExited(ctx context.Context, id string) error {
 }
 
 func (s *SessionService) Dismiss(ctx context.Context, id string) error {
-	return s.setSessionVisibility(ctx, id, true)
+	if err := s.setSessionVisibility(ctx, id, true); err != nil {
+		return err
+	}
+	s.deleteSessionCheckpoints(ctx, id)
+	s.cleanupIsolatedSessionWorktree(ctx, id)
+	return nil
 }
 
 func (s *SessionService) Undismiss(ctx context.Context, id string) error {
@@ -1337,6 +1489,27 @@ func (s *SessionService) ensureSessionCwd(ctx context.Context, session *types.Se
 	}
 }
 
+// SessionCwd returns the working directory of a session, falling back to
+// its worktree path when the session record has none.
+func (s *SessionService) SessionCwd(ctx context.Context, id string) (string, error) {
+	if strings.TrimSpace(id) == "" {
+		return "", invalidError("session id is required", nil)
+	}
+	session, _, err := s.getSessionRecord(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if session == nil {
+		return "", notFoundError("session not found", ErrSessionNotFound)
+	}
+	s.ensureSessionCwd(ctx, session, s.getSessionMeta(ctx, session.ID))
+	cwd := strings.TrimSpace(session.Cwd)
+	if cwd == "" {
+		return "", invalidError("session has no working directory", nil)
+	}
+	return cwd, nil
+}
+
 func (s *SessionService) SubscribeItems(ctx context.Context, id string) (<-chan map[string]any, func(), error) {
 	if s.manager == nil {
 		return nil, nil, unavailableError("session manager not available", nil)
@@ -1460,10 +1633,16 @@ func (s *SessionService) getSessionRecord(ctx context.Context, id string) (*type
 	return nil, "", notFoundError("session not found", ErrSessionNotFound)
 }
 
-func (s *SessionService) resolveAdditionalDirectoriesForWorkspace(cwd string, workspace *types.Workspace) ([]string, error) {
+// resolveAdditionalDirectoriesForWorkspace resolves the workspace's
+// additional directories for a session at cwd. Sessions in a worktree with
+// linked worktrees get those in place of the linked repos.
+func (s *SessionService) resolveAdditionalDirectoriesForWorkspace(cwd string, workspace *types.Workspace, worktree *types.Worktree) ([]string, error) {
 	if workspace == nil || len(workspace.AdditionalDirectories) == 0 {
 		return nil, nil
 	}
+	if worktree != nil && len(worktree.LinkedWorktrees) > 0 {
+		return linkedAdditionalDirectories(workspace, worktree)
+	}
 	return workspacepaths.ResolveAdditionalDirectories(cwd, workspace.AdditionalDirectories, nil)
 }
 
@@ -1478,6 +1657,7 @@ func (s *SessionService) resolveAdditionalDirectoriesForSession(ctx context.Cont
 	if err != nil || !ok || ws == nil {
 		return nil, err
 	}
+	_, wt := s.sessionWorkspace(ctx, ws.ID, meta.WorktreeID)
 	cwd := ""
 	if session != nil {
 		cwd = strings.TrimSpace(session.Cwd)
@@ -1487,7 +1667,7 @@ func (s *SessionService) resolveAdditionalDirectoriesForSession(ctx context.Cont
 			cwd = resolved
 		}
 	}
-	return s.resolveAdditionalDirectoriesForWorkspace(cwd, ws)
+	return s.resolveAdditionalDirectoriesForWorkspace(cwd, ws, wt)
 }
 
 func (s *SessionService) getSessionMeta(ctx context.Context, sessionID string) *types.SessionMeta {
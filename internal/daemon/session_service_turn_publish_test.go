@@ -1,9 +1,12 @@
 package daemon
 
 import (
+	"context"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"control/internal/store"
 	"control/internal/types"
 )
 
@@ -68,3 +71,36 @@ func TestSessionServicePublishTurnCompletedWithoutPayload(t *testing.T) {
 		t.Fatalf("expected nil payload for publishTurnCompleted wrapper, got %#v", publisher.events[0].Payload)
 	}
 }
+
+type stubConversationApprover struct{}
+
+func (stubConversationApprover) Provider() string { return "custom" }
+
+func (stubConversationApprover) Approve(context.Context, approvalDeps, *types.Session, *types.SessionMeta, int, string, []string, map[string]any) error {
+	return nil
+}
+
+func TestSessionServiceApprovePublishesApprovalResolved(t *testing.T) {
+	ctx := context.Background()
+	stores := &Stores{Sessions: store.NewFileSessionIndexStore(filepath.Join(t.TempDir(), "sessions_index.json"))}
+	if _, err := stores.Sessions.UpsertRecord(ctx, &types.SessionRecord{
+		Session: &types.Session{ID: "sess-1", Provider: "custom", Status: types.SessionStatusRunning, CreatedAt: time.Now().UTC()},
+		Source:  sessionSourceInternal,
+	}); err != nil {
+		t.Fatalf("seed session: %v", err)
+	}
+	publisher := &captureSessionServiceNotificationPublisher{}
+	service := NewSessionService(nil, stores, nil)
+	service.notifier = publisher
+	service.adapters = newConversationAdapterRegistry(stubConversationApprover{})
+
+	if err := service.Approve(ctx, "sess-1", 7, "accept", nil, nil); err != nil {
+		t.Fatalf("approve: %v", err)
+	}
+	if len(publisher.events) != 1 {
+		t.Fatalf("expected one event, got %d", len(publisher.events))
+	}
+	if event := publisher.events[0]; event.Trigger != types.NotificationTriggerApprovalResolved || event.SessionID != "sess-1" || event.Status != "accept" {
+		t.Fatalf("unexpected event: %#v", event)
+	}
+}
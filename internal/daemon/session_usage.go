@@ -0,0 +1,179 @@
+package daemon
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"control/internal/types"
+)
+
+// sessionUsageScope says how a usage report combines with what was reported
+// before.
+type sessionUsageScope string
+
+const (
+	// sessionUsageScopeSession reports running session totals that replace
+	// the previous ones.
+	sessionUsageScopeSession sessionUsageScope = "session"
+	// sessionUsageScopeTurn reports the usage of one turn, added to the
+	// session totals.
+	sessionUsageScopeTurn sessionUsageScope = "turn"
+	// sessionUsageScopeMessage reports the usage of one message. Reports for
+	// the same message replace each other; the totals sum all messages.
+	sessionUsageScopeMessage sessionUsageScope = "message"
+)
+
+// sessionUsageReport is one usage observation parsed from provider output.
+// Nil fields were not part of the report and leave the session's values
+// alone.
+type sessionUsageReport struct {
+	Scope         sessionUsageScope
+	MessageID     string
+	Tokens        *types.SessionTokenUsage
+	CostUSD       *float64
+	ContextTokens *int64
+	ContextWindow *int64
+}
+
+// SessionUsageRecorder receives the usage reports of provider sessions.
+type SessionUsageRecorder interface {
+	RecordUsage(sessionID, provider string, report sessionUsageReport)
+}
+
+// SessionUsageReader serves the usage snapshot of a session.
+type SessionUsageReader interface {
+	SessionUsage(sessionID string) (*types.SessionUsage, bool)
+}
+
+type sessionUsageState struct {
+	usage    types.SessionUsage
+	messages map[string]sessionUsageMessage
+}
+
+type sessionUsageMessage struct {
+	tokens  types.SessionTokenUsage
+	costUSD *float64
+}
+
+// SessionUsageStore keeps the usage of every session seen since the daemon
+// started. Providers that report running totals recover them on their next
+// report after a restart.
+type SessionUsageStore struct {
+	mu       sync.Mutex
+	sessions map[string]*sessionUsageState
+	now      func() time.Time
+}
+
+func NewSessionUsageStore() *SessionUsageStore {
+	return &SessionUsageStore{
+		sessions: map[string]*sessionUsageState{},
+		now:      func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (s *SessionUsageStore) RecordUsage(sessionID, provider string, report sessionUsageReport) {
+	sessionID = strings.TrimSpace(sessionID)
+	if s == nil || sessionID == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.sessions[sessionID]
+	if state == nil {
+		state = &sessionUsageState{usage: types.SessionUsage{SessionID: sessionID}}
+		s.sessions[sessionID] = state
+	}
+	if provider = strings.TrimSpace(provider); provider != "" {
+		state.usage.Provider = provider
+	}
+	switch report.Scope {
+	case sessionUsageScopeTurn:
+		if report.Tokens != nil {
+			state.usage.Tokens = addSessionTokenUsage(state.usage.Tokens, *report.Tokens)
+		}
+		if report.CostUSD != nil {
+			total := *report.CostUSD
+			if state.usage.CostUSD != nil {
+				total += *state.usage.CostUSD
+			}
+			state.usage.CostUSD = &total
+		}
+	case sessionUsageScopeMessage:
+		messageID := strings.TrimSpace(report.MessageID)
+		if messageID != "" && (report.Tokens != nil || report.CostUSD != nil) {
+			if state.messages == nil {
+				state.messages = map[string]sessionUsageMessage{}
+			}
+			message := state.messages[messageID]
+			if report.Tokens != nil {
+				message.tokens = *report.Tokens
+			}
+			if report.CostUSD != nil {
+				message.costUSD = report.CostUSD
+			}
+			state.messages[messageID] = message
+			state.sumMessages()
+		}
+	default:
+		if report.Tokens != nil {
+			state.usage.Tokens = *report.Tokens
+		}
+		if report.CostUSD != nil {
+			cost := *report.CostUSD
+			state.usage.CostUSD = &cost
+		}
+	}
+	if report.ContextTokens != nil {
+		state.usage.ContextTokens = *report.ContextTokens
+	}
+	if report.ContextWindow != nil && *report.ContextWindow > 0 {
+		state.usage.ContextWindow = *report.ContextWindow
+	}
+	state.usage.UpdatedAt = s.now()
+}
+
+func (s *SessionUsageStore) SessionUsage(sessionID string) (*types.SessionUsage, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.sessions[strings.TrimSpace(sessionID)]
+	if state == nil {
+		return nil, false
+	}
+	usage := state.usage
+	if usage.CostUSD != nil {
+		cost := *usage.CostUSD
+		usage.CostUSD = &cost
+	}
+	return &usage, true
+}
+
+func (s *sessionUsageState) sumMessages() {
+	tokens := types.SessionTokenUsage{}
+	var cost *float64
+	for _, message := range s.messages {
+		tokens = addSessionTokenUsage(tokens, message.tokens)
+		if message.costUSD != nil {
+			total := *message.costUSD
+			if cost != nil {
+				total += *cost
+			}
+			cost = &total
+		}
+	}
+	s.usage.Tokens = tokens
+	s.usage.CostUSD = cost
+}
+
+func addSessionTokenUsage(a, b types.SessionTokenUsage) types.SessionTokenUsage {
+	return types.SessionTokenUsage{
+		Input:       a.Input + b.Input,
+		CachedInput: a.CachedInput + b.CachedInput,
+		Output:      a.Output + b.Output,
+		Reasoning:   a.Reasoning + b.Reasoning,
+		Total:       a.Total + b.Total,
+	}
+}
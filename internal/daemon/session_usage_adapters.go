@@ -0,0 +1,300 @@
+package daemon
+
+import (
+	"encoding/json"
+	"strings"
+
+	"control/internal/daemon/acp"
+	"control/internal/types"
+)
+
+// Each provider reports usage in its own shape and at its own granularity.
+// The adapters below turn those reports into sessionUsageReports for the
+// SessionUsageStore.
+
+type codexTokenUsageBreakdown struct {
+	TotalTokens           int64 `json:"totalTokens"`
+	InputTokens           int64 `json:"inputTokens"`
+	CachedInputTokens     int64 `json:"cachedInputTokens"`
+	OutputTokens          int64 `json:"outputTokens"`
+	ReasoningOutputTokens int64 `json:"reasoningOutputTokens"`
+}
+
+type codexLegacyTokenUsageBreakdown struct {
+	TotalTokens           int64 `json:"total_tokens"`
+	InputTokens           int64 `json:"input_tokens"`
+	CachedInputTokens     int64 `json:"cached_input_tokens"`
+	OutputTokens          int64 `json:"output_tokens"`
+	ReasoningOutputTokens int64 `json:"reasoning_output_tokens"`
+}
+
+// codexUsageReport reads the running totals of thread/tokenUsage/updated
+// notifications and of the legacy codex/event/token_count events. The last
+// request's total is what currently occupies the context window.
+func codexUsageReport(event types.CodexEvent) (sessionUsageReport, bool) {
+	if len(event.Params) == 0 {
+		return sessionUsageReport{}, false
+	}
+	switch normalizeCodexUsageMethod(event.Method) {
+	case "thread/tokenusage/updated":
+		var params struct {
+			TokenUsage *struct {
+				Total              codexTokenUsageBreakdown  `json:"total"`
+				Last               *codexTokenUsageBreakdown `json:"last"`
+				ModelContextWindow int64                     `json:"modelContextWindow"`
+			} `json:"tokenUsage"`
+		}
+		if json.Unmarshal(event.Params, &params) != nil || params.TokenUsage == nil {
+			return sessionUsageReport{}, false
+		}
+		usage := params.TokenUsage
+		report := sessionUsageReport{
+			Scope:  sessionUsageScopeSession,
+			Tokens: codexSessionTokenUsage(usage.Total.InputTokens, usage.Total.CachedInputTokens, usage.Total.OutputTokens, usage.Total.ReasoningOutputTokens, usage.Total.TotalTokens),
+		}
+		if usage.Last != nil {
+			report.ContextTokens = int64Ptr(usage.Last.TotalTokens)
+		}
+		if usage.ModelContextWindow > 0 {
+			report.ContextWindow = int64Ptr(usage.ModelContextWindow)
+		}
+		return report, true
+	case "codex/event/token_count":
+		var params struct {
+			Msg struct {
+				Info *struct {
+					Total              codexLegacyTokenUsageBreakdown  `json:"total_token_usage"`
+					Last               *codexLegacyTokenUsageBreakdown `json:"last_token_usage"`
+					ModelContextWindow int64                           `json:"model_context_window"`
+				} `json:"info"`
+			} `json:"msg"`
+		}
+		if json.Unmarshal(event.Params, &params) != nil || params.Msg.Info == nil {
+			return sessionUsageReport{}, false
+		}
+		info := params.Msg.Info
+		report := sessionUsageReport{
+			Scope:  sessionUsageScopeSession,
+			Tokens: codexSessionTokenUsage(info.Total.InputTokens, info.Total.CachedInputTokens, info.Total.OutputTokens, info.Total.ReasoningOutputTokens, info.Total.TotalTokens),
+		}
+		if info.Last != nil {
+			report.ContextTokens = int64Ptr(info.Last.TotalTokens)
+		}
+		if info.ModelContextWindow > 0 {
+			report.ContextWindow = int64Ptr(info.ModelContextWindow)
+		}
+		return report, true
+	default:
+		return sessionUsageReport{}, false
+	}
+}
+
+func normalizeCodexUsageMethod(method string) string {
+	return strings.ToLower(strings.TrimSpace(method))
+}
+
+func codexSessionTokenUsage(input, cached, output, reasoning, total int64) *types.SessionTokenUsage {
+	if total == 0 {
+		total = input + output
+	}
+	return &types.SessionTokenUsage{
+		Input:       input,
+		CachedInput: cached,
+		Output:      output,
+		Reasoning:   reasoning,
+		Total:       total,
+	}
+}
+
+type claudeUsagePayload struct {
+	InputTokens              int64 `json:"input_tokens"`
+	CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+	OutputTokens             int64 `json:"output_tokens"`
+}
+
+func (u claudeUsagePayload) input() int64 {
+	return u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+}
+
+// claudeUsageReport reads a stream-json line. Assistant messages carry the
+// usage of one request, which is what sits in the context window; result
+// messages close a run with its usage and cost. Every turn is its own run,
+// so result usage adds up.
+func claudeUsageReport(line string) (sessionUsageReport, bool) {
+	if !strings.Contains(line, "usage") {
+		return sessionUsageReport{}, false
+	}
+	var payload struct {
+		Type    string `json:"type"`
+		Message *struct {
+			Usage *claudeUsagePayload `json:"usage"`
+		} `json:"message"`
+		Usage        *claudeUsagePayload `json:"usage"`
+		TotalCostUSD *float64            `json:"total_cost_usd"`
+		ModelUsage   map[string]struct {
+			ContextWindow int64 `json:"contextWindow"`
+		} `json:"modelUsage"`
+	}
+	if json.Unmarshal([]byte(line), &payload) != nil {
+		return sessionUsageReport{}, false
+	}
+	switch payload.Type {
+	case "assistant":
+		if payload.Message == nil || payload.Message.Usage == nil {
+			return sessionUsageReport{}, false
+		}
+		usage := payload.Message.Usage
+		return sessionUsageReport{
+			Scope:         sessionUsageScopeTurn,
+			ContextTokens: int64Ptr(usage.input() + usage.OutputTokens),
+		}, true
+	case "result":
+		if payload.Usage == nil && payload.TotalCostUSD == nil {
+			return sessionUsageReport{}, false
+		}
+		report := sessionUsageReport{Scope: sessionUsageScopeTurn, CostUSD: payload.TotalCostUSD}
+		if usage := payload.Usage; usage != nil {
+			report.Tokens = &types.SessionTokenUsage{
+				Input:       usage.input(),
+				CachedInput: usage.CacheReadInputTokens,
+				Output:      usage.OutputTokens,
+				Total:       usage.input() + usage.OutputTokens,
+			}
+		}
+		window := int64(0)
+		for _, model := range payload.ModelUsage {
+			window = max(window, model.ContextWindow)
+		}
+		if window > 0 {
+			report.ContextWindow = int64Ptr(window)
+		}
+		return report, true
+	default:
+		return sessionUsageReport{}, false
+	}
+}
+
+// openCodeUsageReport reads the token usage events the OpenCode event mapper
+// derives from assistant message updates. OpenCode keeps usage per message,
+// so reports replace the message's previous figures.
+func openCodeUsageReport(event types.CodexEvent) (sessionUsageReport, bool) {
+	if normalizeCodexUsageMethod(event.Method) != "thread/tokenusage/updated" || len(event.Params) == 0 {
+		return sessionUsageReport{}, false
+	}
+	var params struct {
+		MessageID string   `json:"messageId"`
+		Cost      *float64 `json:"cost"`
+		Tokens    *struct {
+			Input     int64 `json:"input"`
+			Output    int64 `json:"output"`
+			Reasoning int64 `json:"reasoning"`
+			Cache     struct {
+				Read  int64 `json:"read"`
+				Write int64 `json:"write"`
+			} `json:"cache"`
+		} `json:"tokens"`
+	}
+	if json.Unmarshal(event.Params, &params) != nil || strings.TrimSpace(params.MessageID) == "" || params.Tokens == nil {
+		return sessionUsageReport{}, false
+	}
+	tokens := params.Tokens
+	input := tokens.Input + tokens.Cache.Read + tokens.Cache.Write
+	output := tokens.Output + tokens.Reasoning
+	report := sessionUsageReport{
+		Scope:     sessionUsageScopeMessage,
+		MessageID: strings.TrimSpace(params.MessageID),
+		Tokens: &types.SessionTokenUsage{
+			Input:       input,
+			CachedInput: tokens.Cache.Read,
+			Output:      output,
+			Reasoning:   tokens.Reasoning,
+			Total:       input + output,
+		},
+		CostUSD: params.Cost,
+	}
+	if input+output > 0 {
+		report.ContextTokens = int64Ptr(input + output)
+	}
+	return report, true
+}
+
+// openCodeMessageUsagePayload builds the token usage event payload for an
+// assistant message of a message.updated event.
+func openCodeMessageUsagePayload(properties map[string]any) (map[string]any, bool) {
+	info, _ := properties["info"].(map[string]any)
+	if info == nil {
+		return nil, false
+	}
+	role := strings.ToLower(strings.TrimSpace(asString(info["role"])))
+	if role != "assistant" {
+		return nil, false
+	}
+	messageID := strings.TrimSpace(asString(info["id"]))
+	tokens, _ := info["tokens"].(map[string]any)
+	if messageID == "" || tokens == nil {
+		return nil, false
+	}
+	payload := map[string]any{
+		"messageId": messageID,
+		"tokens":    tokens,
+	}
+	if cost, ok := info["cost"].(float64); ok {
+		payload["cost"] = cost
+	}
+	return payload, true
+}
+
+// hermesUsageReport reads ACP usage_update session updates, which carry the
+// context window state and the session's cumulative cost.
+func hermesUsageReport(update acp.SessionUpdateNotification) (sessionUsageReport, bool) {
+	if update.SessionUpdate != acp.SessionUpdateUsage || len(update.Raw) == 0 {
+		return sessionUsageReport{}, false
+	}
+	var payload struct {
+		Used int64 `json:"used"`
+		Size int64 `json:"size"`
+		Cost *struct {
+			Amount   float64 `json:"amount"`
+			Currency string  `json:"currency"`
+		} `json:"cost"`
+	}
+	if json.Unmarshal(update.Raw, &payload) != nil {
+		return sessionUsageReport{}, false
+	}
+	report := sessionUsageReport{Scope: sessionUsageScopeSession, ContextTokens: int64Ptr(payload.Used)}
+	if payload.Size > 0 {
+		report.ContextWindow = int64Ptr(payload.Size)
+	}
+	if payload.Cost != nil && strings.EqualFold(strings.TrimSpace(payload.Cost.Currency), "USD") {
+		report.CostUSD = &payload.Cost.Amount
+	}
+	return report, true
+}
+
+// hermesPromptUsageReport reads the usage of one prompt turn from an ACP
+// prompt result.
+func hermesPromptUsageReport(result acp.PromptResult) (sessionUsageReport, bool) {
+	usage := result.Usage
+	if usage == nil {
+		return sessionUsageReport{}, false
+	}
+	total := usage.TotalTokens
+	if total == 0 {
+		total = usage.InputTokens + usage.OutputTokens
+	}
+	return sessionUsageReport{
+		Scope: sessionUsageScopeTurn,
+		Tokens: &types.SessionTokenUsage{
+			Input:       usage.InputTokens,
+			CachedInput: usage.CachedReadTokens,
+			Output:      usage.OutputTokens,
+			Reasoning:   usage.ThoughtTokens,
+			Total:       total,
+		},
+	}, true
+}
+
+func int64Ptr(value int64) *int64 {
+	return &value
+}
@@ -0,0 +1,120 @@
+package daemon
+
+import (
+	"encoding/json"
+	"testing"
+
+	"control/internal/daemon/acp"
+	"control/internal/types"
+)
+
+func TestCodexUsageReportReadsTokenUsageNotifications(t *testing.T) {
+	event := types.CodexEvent{
+		Method: "thread/tokenUsage/updated",
+		Params: json.RawMessage(`{"threadId":"t1","turnId":"u1","tokenUsage":{"total":{"totalTokens":5000,"inputTokens":4000,"cachedInputTokens":1000,"outputTokens":1000,"reasoningOutputTokens":200},"last":{"totalTokens":3000},"modelContextWindow":10000}}`),
+	}
+	report, ok := codexUsageReport(event)
+	if !ok {
+		t.Fatalf("expected usage report")
+	}
+	if report.Scope != sessionUsageScopeSession || report.Tokens.Total != 5000 || report.Tokens.CachedInput != 1000 {
+		t.Fatalf("unexpected report %#v", report)
+	}
+	if report.ContextTokens == nil || *report.ContextTokens != 3000 || report.ContextWindow == nil || *report.ContextWindow != 10000 {
+		t.Fatalf("unexpected context %#v", report)
+	}
+
+	legacy := types.CodexEvent{
+		Method: "codex/event/token_count",
+		Params: json.RawMessage(`{"msg":{"type":"token_count","info":{"total_token_usage":{"input_tokens":100,"output_tokens":50,"total_tokens":150},"last_token_usage":{"total_tokens":80},"model_context_window":1000}}}`),
+	}
+	report, ok = codexUsageReport(legacy)
+	if !ok || report.Tokens.Total != 150 || *report.ContextTokens != 80 || *report.ContextWindow != 1000 {
+		t.Fatalf("unexpected legacy report %#v %v", report, ok)
+	}
+
+	if _, ok := codexUsageReport(types.CodexEvent{Method: "codex/event/token_count", Params: json.RawMessage(`{"msg":{"info":null}}`)}); ok {
+		t.Fatalf("expected token_count without info to be ignored")
+	}
+}
+
+func TestClaudeUsageReportReadsAssistantAndResultMessages(t *testing.T) {
+	report, ok := claudeUsageReport(`{"type":"assistant","message":{"usage":{"input_tokens":10,"cache_read_input_tokens":900,"output_tokens":90}}}`)
+	if !ok || report.ContextTokens == nil || *report.ContextTokens != 1000 || report.Tokens != nil {
+		t.Fatalf("unexpected assistant report %#v %v", report, ok)
+	}
+
+	report, ok = claudeUsageReport(`{"type":"result","total_cost_usd":0.42,"usage":{"input_tokens":20,"cache_creation_input_tokens":30,"cache_read_input_tokens":50,"output_tokens":10},"modelUsage":{"claude-a":{"contextWindow":200000},"claude-b":{"contextWindow":100000}}}`)
+	if !ok {
+		t.Fatalf("expected result report")
+	}
+	if report.Scope != sessionUsageScopeTurn || report.Tokens.Input != 100 || report.Tokens.CachedInput != 50 || report.Tokens.Total != 110 {
+		t.Fatalf("unexpected result tokens %#v", report.Tokens)
+	}
+	if report.CostUSD == nil || *report.CostUSD != 0.42 || report.ContextWindow == nil || *report.ContextWindow != 200000 {
+		t.Fatalf("unexpected result cost or window %#v", report)
+	}
+
+	if _, ok := claudeUsageReport(`{"type":"system","subtype":"init"}`); ok {
+		t.Fatalf("expected system message to be ignored")
+	}
+}
+
+func TestOpenCodeUsageReportReadsMessageTokens(t *testing.T) {
+	raw, _ := json.Marshal(map[string]any{
+		"type": "message.updated",
+		"properties": map[string]any{
+			"info": map[string]any{
+				"id":        "msg_1",
+				"role":      "assistant",
+				"sessionID": "ses_test",
+				"cost":      0.03,
+				"tokens": map[string]any{
+					"input":     100,
+					"output":    40,
+					"reasoning": 10,
+					"cache":     map[string]any{"read": 500, "write": 0},
+				},
+			},
+		},
+	})
+	events := mapOpenCodeEventToCodex(string(raw), "ses_test", nil)
+	if len(events) != 1 || events[0].Method != "thread/tokenUsage/updated" {
+		t.Fatalf("expected a token usage event, got %#v", events)
+	}
+	report, ok := openCodeUsageReport(events[0])
+	if !ok {
+		t.Fatalf("expected usage report")
+	}
+	if report.Scope != sessionUsageScopeMessage || report.MessageID != "msg_1" {
+		t.Fatalf("unexpected report %#v", report)
+	}
+	if report.Tokens.Input != 600 || report.Tokens.Output != 50 || report.Tokens.Total != 650 || *report.ContextTokens != 650 {
+		t.Fatalf("unexpected tokens %#v", report.Tokens)
+	}
+	if report.CostUSD == nil || *report.CostUSD != 0.03 {
+		t.Fatalf("unexpected cost %v", report.CostUSD)
+	}
+}
+
+func TestHermesUsageReportReadsUsageUpdates(t *testing.T) {
+	update := acp.SessionUpdateNotification{
+		SessionUpdate: acp.SessionUpdateUsage,
+		Raw:           json.RawMessage(`{"sessionUpdate":"usage_update","used":53000,"size":200000,"cost":{"amount":0.045,"currency":"USD"}}`),
+	}
+	report, ok := hermesUsageReport(update)
+	if !ok {
+		t.Fatalf("expected usage report")
+	}
+	if *report.ContextTokens != 53000 || *report.ContextWindow != 200000 || report.CostUSD == nil || *report.CostUSD != 0.045 {
+		t.Fatalf("unexpected report %#v", report)
+	}
+
+	report, ok = hermesPromptUsageReport(acp.PromptResult{Usage: &acp.PromptUsage{InputTokens: 30, OutputTokens: 12}})
+	if !ok || report.Scope != sessionUsageScopeTurn || report.Tokens.Total != 42 {
+		t.Fatalf("unexpected prompt report %#v %v", report, ok)
+	}
+	if _, ok := hermesPromptUsageReport(acp.PromptResult{}); ok {
+		t.Fatalf("expected prompt result without usage to be ignored")
+	}
+}
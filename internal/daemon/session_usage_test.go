@@ -0,0 +1,119 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"control/internal/types"
+)
+
+func TestSessionUsageStoreCombinesReportScopes(t *testing.T) {
+	store := NewSessionUsageStore()
+	cost := func(value float64) *float64 { return &value }
+
+	store.RecordUsage("s1", "claude", sessionUsageReport{
+		Scope:   sessionUsageScopeTurn,
+		Tokens:  &types.SessionTokenUsage{Input: 100, Output: 20, Total: 120},
+		CostUSD: cost(0.25),
+	})
+	store.RecordUsage("s1", "claude", sessionUsageReport{
+		Scope:         sessionUsageScopeTurn,
+		Tokens:        &types.SessionTokenUsage{Input: 200, Output: 30, Total: 230},
+		CostUSD:       cost(0.5),
+		ContextWindow: int64Ptr(1000),
+	})
+	store.RecordUsage("s1", "claude", sessionUsageReport{Scope: sessionUsageScopeTurn, ContextTokens: int64Ptr(230)})
+	usage, ok := store.SessionUsage("s1")
+	if !ok {
+		t.Fatalf("expected usage for s1")
+	}
+	if usage.Tokens.Total != 350 || usage.Tokens.Input != 300 || usage.Tokens.Output != 50 {
+		t.Fatalf("expected turn usage to add up, got %#v", usage.Tokens)
+	}
+	if usage.CostUSD == nil || *usage.CostUSD != 0.75 {
+		t.Fatalf("expected summed cost, got %v", usage.CostUSD)
+	}
+	if percent, ok := usage.ContextUsedPercent(); !ok || percent != 23 {
+		t.Fatalf("expected 23%% context used, got %v %v", percent, ok)
+	}
+
+	store.RecordUsage("s2", "opencode", sessionUsageReport{
+		Scope:     sessionUsageScopeMessage,
+		MessageID: "m1",
+		Tokens:    &types.SessionTokenUsage{Total: 10},
+		CostUSD:   cost(0.1),
+	})
+	store.RecordUsage("s2", "opencode", sessionUsageReport{
+		Scope:     sessionUsageScopeMessage,
+		MessageID: "m1",
+		Tokens:    &types.SessionTokenUsage{Total: 40},
+		CostUSD:   cost(0.2),
+	})
+	store.RecordUsage("s2", "opencode", sessionUsageReport{
+		Scope:     sessionUsageScopeMessage,
+		MessageID: "m2",
+		Tokens:    &types.SessionTokenUsage{Total: 5},
+	})
+	usage, _ = store.SessionUsage("s2")
+	if usage.Tokens.Total != 45 {
+		t.Fatalf("expected message usage to replace then sum, got %d", usage.Tokens.Total)
+	}
+	if usage.CostUSD == nil || *usage.CostUSD != 0.2 {
+		t.Fatalf("expected message cost 0.2, got %v", usage.CostUSD)
+	}
+
+	store.RecordUsage("s3", "codex", sessionUsageReport{Scope: sessionUsageScopeSession, Tokens: &types.SessionTokenUsage{Total: 10}})
+	store.RecordUsage("s3", "codex", sessionUsageReport{Scope: sessionUsageScopeSession, Tokens: &types.SessionTokenUsage{Total: 25}})
+	usage, _ = store.SessionUsage("s3")
+	if usage.Tokens.Total != 25 || usage.Provider != "codex" {
+		t.Fatalf("expected session totals to replace, got %#v", usage)
+	}
+	if _, ok := store.SessionUsage("missing"); ok {
+		t.Fatalf("expected no usage for unknown session")
+	}
+}
+
+func TestSessionUsageEndpoint(t *testing.T) {
+	store := NewSessionUsageStore()
+	store.RecordUsage("s1", "codex", sessionUsageReport{
+		Scope:         sessionUsageScopeSession,
+		Tokens:        &types.SessionTokenUsage{Total: 1200},
+		ContextTokens: int64Ptr(600),
+		ContextWindow: int64Ptr(2000),
+	})
+	api := &API{Usage: store}
+
+	rec := httptest.NewRecorder()
+	api.sessionUsage(rec, httptest.NewRequest(http.MethodGet, "/v1/sessions/s1/usage", nil), "s1")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var usage types.SessionUsage
+	if err := json.Unmarshal(rec.Body.Bytes(), &usage); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if usage.Tokens.Total != 1200 || usage.ContextTokens != 600 || usage.ContextWindow != 2000 {
+		t.Fatalf("unexpected usage %#v", usage)
+	}
+
+	rec = httptest.NewRecorder()
+	api.sessionUsage(rec, httptest.NewRequest(http.MethodGet, "/v1/sessions/s2/usage", nil), "s2")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for a session without usage, got %d", rec.Code)
+	}
+	usage = types.SessionUsage{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &usage); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if usage.SessionID != "s2" || usage.Tokens.Total != 0 {
+		t.Fatalf("expected empty usage for s2, got %#v", usage)
+	}
+
+	rec = httptest.NewRecorder()
+	api.sessionUsage(rec, httptest.NewRequest(http.MethodPost, "/v1/sessions/s1/usage", nil), "s1")
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,272 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"control/internal/guidedworkflows"
+	"control/internal/store"
+	"control/internal/types"
+)
+
+// workflowSnippetIDPrefix marks snippets that mirror the built-in workflow
+// template prompt definitions. They are read-only.
+const workflowSnippetIDPrefix = "workflow:"
+
+type SnippetService struct {
+	snippets   SnippetStore
+	workspaces WorkspaceStore
+	prompts    func() map[string]string
+}
+
+type RenderSnippetRequest struct {
+	Values map[string]string `json:"values,omitempty"`
+}
+
+type RenderSnippetResponse struct {
+	Text    string   `json:"text"`
+	Missing []string `json:"missing,omitempty"`
+}
+
+func NewSnippetService(stores *Stores) *SnippetService {
+	service := &SnippetService{prompts: guidedworkflows.DefaultWorkflowPrompts}
+	if stores != nil {
+		service.snippets = stores.Snippets
+		service.workspaces = stores.Workspaces
+	}
+	return service
+}
+
+// List returns the snippets available in a workspace. Workspace snippets
+// shadow global ones of the same name, and both shadow workflow prompts.
+// Without a workspace every stored snippet is listed.
+func (s *SnippetService) List(ctx context.Context, workspaceID string) ([]*types.Snippet, error) {
+	if s.snippets == nil {
+		return nil, unavailableError("snippet store not available", nil)
+	}
+	stored, err := s.snippets.List(ctx)
+	if err != nil {
+		return nil, unavailableError(err.Error(), err)
+	}
+	workspaceID = strings.TrimSpace(workspaceID)
+	names := map[string]struct{}{}
+	out := make([]*types.Snippet, 0, len(stored))
+	add := func(snippet *types.Snippet) {
+		names[snippet.Name] = struct{}{}
+		out = append(out, withSnippetVariables(snippet))
+	}
+	if workspaceID != "" {
+		for _, snippet := range stored {
+			if snippet.Scope == types.SnippetScopeWorkspace && snippet.WorkspaceID == workspaceID {
+				add(snippet)
+			}
+		}
+	}
+	for _, snippet := range stored {
+		if snippet.Scope == types.SnippetScopeWorkspace {
+			if workspaceID == "" {
+				out = append(out, withSnippetVariables(snippet))
+			}
+			continue
+		}
+		if _, shadowed := names[snippet.Name]; !shadowed {
+			add(snippet)
+		}
+	}
+	for _, snippet := range s.workflowSnippets() {
+		if _, shadowed := names[snippet.Name]; !shadowed {
+			add(snippet)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func (s *SnippetService) Get(ctx context.Context, id string) (*types.Snippet, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, invalidError("snippet id is required", nil)
+	}
+	if strings.HasPrefix(id, workflowSnippetIDPrefix) {
+		for _, snippet := range s.workflowSnippets() {
+			if snippet.ID == id {
+				return withSnippetVariables(snippet), nil
+			}
+		}
+		return nil, notFoundError("snippet not found", store.ErrSnippetNotFound)
+	}
+	if s.snippets == nil {
+		return nil, unavailableError("snippet store not available", nil)
+	}
+	snippet, ok, err := s.snippets.Get(ctx, id)
+	if err != nil {
+		return nil, unavailableError(err.Error(), err)
+	}
+	if !ok || snippet == nil {
+		return nil, notFoundError("snippet not found", store.ErrSnippetNotFound)
+	}
+	return withSnippetVariables(snippet), nil
+}
+
+func (s *SnippetService) Create(ctx context.Context, snippet *types.Snippet) (*types.Snippet, error) {
+	if s.snippets == nil {
+		return nil, unavailableError("snippet store not available", nil)
+	}
+	if snippet == nil {
+		return nil, invalidError("snippet payload is required", nil)
+	}
+	candidate := *snippet
+	candidate.ID = ""
+	if err := s.validate(ctx, &candidate); err != nil {
+		return nil, err
+	}
+	created, err := s.snippets.Upsert(ctx, &candidate)
+	if err != nil {
+		return nil, unavailableError(err.Error(), err)
+	}
+	return withSnippetVariables(created), nil
+}
+
+func (s *SnippetService) Update(ctx context.Context, id string, patch *types.Snippet) (*types.Snippet, error) {
+	if strings.HasPrefix(strings.TrimSpace(id), workflowSnippetIDPrefix) {
+		return nil, invalidError("workflow prompt snippets are read-only; create a snippet with the same name to override it", nil)
+	}
+	if patch == nil {
+		return nil, invalidError("snippet payload is required", nil)
+	}
+	existing, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	merged := *existing
+	if name := strings.TrimSpace(patch.Name); name != "" {
+		merged.Name = name
+	}
+	if patch.Description != "" {
+		merged.Description = strings.TrimSpace(patch.Description)
+	}
+	if strings.TrimSpace(patch.Body) != "" {
+		merged.Body = patch.Body
+	}
+	if patch.Scope != "" {
+		merged.Scope = patch.Scope
+		if patch.Scope == types.SnippetScopeGlobal {
+			merged.WorkspaceID = ""
+		}
+	}
+	if workspaceID := strings.TrimSpace(patch.WorkspaceID); workspaceID != "" {
+		merged.WorkspaceID = workspaceID
+	}
+	if err := s.validate(ctx, &merged); err != nil {
+		return nil, err
+	}
+	updated, err := s.snippets.Upsert(ctx, &merged)
+	if err != nil {
+		return nil, unavailableError(err.Error(), err)
+	}
+	return withSnippetVariables(updated), nil
+}
+
+func (s *SnippetService) Delete(ctx context.Context, id string) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return invalidError("snippet id is required", nil)
+	}
+	if strings.HasPrefix(id, workflowSnippetIDPrefix) {
+		return invalidError("workflow prompt snippets are read-only", nil)
+	}
+	if s.snippets == nil {
+		return unavailableError("snippet store not available", nil)
+	}
+	if err := s.snippets.Delete(ctx, id); err != nil {
+		if errors.Is(err, store.ErrSnippetNotFound) {
+			return notFoundError("snippet not found", err)
+		}
+		return unavailableError(err.Error(), err)
+	}
+	return nil
+}
+
+func (s *SnippetService) Render(ctx context.Context, id string, values map[string]string) (*RenderSnippetResponse, error) {
+	snippet, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	text, missing := types.RenderSnippet(snippet.Body, values)
+	return &RenderSnippetResponse{Text: text, Missing: missing}, nil
+}
+
+func (s *SnippetService) validate(ctx context.Context, snippet *types.Snippet) error {
+	snippet.Name = strings.TrimSpace(snippet.Name)
+	snippet.Description = strings.TrimSpace(snippet.Description)
+	snippet.WorkspaceID = strings.TrimSpace(snippet.WorkspaceID)
+	snippet.Source = types.SnippetSourceUser
+	if snippet.Name == "" {
+		return invalidError("snippet name is required", nil)
+	}
+	if strings.ContainsAny(snippet.Name, " \t\r\n") {
+		return invalidError("snippet name cannot contain whitespace", nil)
+	}
+	if strings.TrimSpace(snippet.Body) == "" {
+		return invalidError("snippet body is required", nil)
+	}
+	if snippet.Scope == "" {
+		snippet.Scope = types.SnippetScopeGlobal
+		if snippet.WorkspaceID != "" {
+			snippet.Scope = types.SnippetScopeWorkspace
+		}
+	}
+	switch snippet.Scope {
+	case types.SnippetScopeGlobal:
+		snippet.WorkspaceID = ""
+	case types.SnippetScopeWorkspace:
+		if snippet.WorkspaceID == "" {
+			return invalidError("workspace_id is required for workspace snippets", nil)
+		}
+		if s.workspaces != nil {
+			if _, ok, err := s.workspaces.Get(ctx, snippet.WorkspaceID); err != nil {
+				return unavailableError(err.Error(), err)
+			} else if !ok {
+				return notFoundError("workspace not found", store.ErrWorkspaceNotFound)
+			}
+		}
+	default:
+		return invalidError("invalid scope", nil)
+	}
+	stored, err := s.snippets.List(ctx)
+	if err != nil {
+		return unavailableError(err.Error(), err)
+	}
+	for _, other := range stored {
+		if other.ID != snippet.ID && other.Name == snippet.Name && other.Scope == snippet.Scope && other.WorkspaceID == snippet.WorkspaceID {
+			return conflictError("a snippet named "+snippet.Name+" already exists in this scope", nil)
+		}
+	}
+	return nil
+}
+
+func (s *SnippetService) workflowSnippets() []*types.Snippet {
+	if s.prompts == nil {
+		return nil
+	}
+	prompts := s.prompts()
+	out := make([]*types.Snippet, 0, len(prompts))
+	for id, body := range prompts {
+		out = append(out, &types.Snippet{
+			ID:     workflowSnippetIDPrefix + id,
+			Name:   id,
+			Body:   body,
+			Scope:  types.SnippetScopeGlobal,
+			Source: types.SnippetSourceWorkflow,
+		})
+	}
+	return out
+}
+
+func withSnippetVariables(snippet *types.Snippet) *types.Snippet {
+	out := *snippet
+	out.Variables = types.SnippetVariables(snippet.Body)
+	return &out
+}
@@ -0,0 +1,321 @@
+package daemon
+
+import (
+	"context"
+	"time"
+
+	"control/internal/guidedworkflows"
+	"control/internal/store"
+	"control/internal/types"
+)
+
+// instrumentStores wraps every configured store so operation latency and
+// errors are recorded on the daemon metrics registry.
+func instrumentStores(stores *Stores, m *daemonMetrics) *Stores {
+	if stores == nil || m == nil {
+		return stores
+	}
+	out := *stores
+	if stores.Workspaces != nil {
+		out.Workspaces = &metricsWorkspaceStore{next: stores.Workspaces, metrics: m}
+	}
+	if stores.Worktrees != nil {
+		out.Worktrees = &metricsWorktreeStore{next: stores.Worktrees, metrics: m}
+	}
+	if stores.Groups != nil {
+		out.Groups = &metricsWorkspaceGroupStore{next: stores.Groups, metrics: m}
+	}
+	if stores.WorkflowTemplates != nil {
+		out.WorkflowTemplates = &metricsWorkflowTemplateStore{next: stores.WorkflowTemplates, metrics: m}
+	}
+	if stores.WorkflowRuns != nil {
+		out.WorkflowRuns = &metricsWorkflowRunStore{next: stores.WorkflowRuns, metrics: m}
+	}
+	if stores.AppState != nil {
+		out.AppState = &metricsAppStateStore{next: stores.AppState, metrics: m}
+	}
+	if stores.SessionMeta != nil {
+		out.SessionMeta = &metricsSessionMetaStore{next: stores.SessionMeta, metrics: m}
+	}
+	if stores.Sessions != nil {
+		out.Sessions = &metricsSessionIndexStore{next: stores.Sessions, metrics: m}
+	}
+	if stores.Approvals != nil {
+		out.Approvals = &metricsApprovalStore{next: stores.Approvals, metrics: m}
+	}
+	if stores.Notes != nil {
+		out.Notes = &metricsNoteStore{next: stores.Notes, metrics: m}
+	}
+	if stores.Snippets != nil {
+		out.Snippets = &metricsSnippetStore{next: stores.Snippets, metrics: m}
+	}
+	if stores.ApprovalRules != nil {
+		out.ApprovalRules = &metricsApprovalRuleStore{next: stores.ApprovalRules, metrics: m}
+	}
+	return &out
+}
+
+func observeStoreCall[T any](m *daemonMetrics, storeName, operation string, fn func() (T, error)) (T, error) {
+	started := time.Now()
+	out, err := fn()
+	m.ObserveStoreOperation(storeName, operation, time.Since(started), err)
+	return out, err
+}
+
+func observeStoreLookup[T any](m *daemonMetrics, storeName, operation string, fn func() (T, bool, error)) (T, bool, error) {
+	started := time.Now()
+	out, ok, err := fn()
+	m.ObserveStoreOperation(storeName, operation, time.Since(started), err)
+	return out, ok, err
+}
+
+func observeStoreErr(m *daemonMetrics, storeName, operation string, fn func() error) error {
+	started := time.Now()
+	err := fn()
+	m.ObserveStoreOperation(storeName, operation, time.Since(started), err)
+	return err
+}
+
+type metricsWorkspaceStore struct {
+	next    WorkspaceStore
+	metrics *daemonMetrics
+}
+
+func (s *metricsWorkspaceStore) List(ctx context.Context) ([]*types.Workspace, error) {
+	return observeStoreCall(s.metrics, "workspaces", "list", func() ([]*types.Workspace, error) { return s.next.List(ctx) })
+}
+
+func (s *metricsWorkspaceStore) Get(ctx context.Context, id string) (*types.Workspace, bool, error) {
+	return observeStoreLookup(s.metrics, "workspaces", "get", func() (*types.Workspace, bool, error) { return s.next.Get(ctx, id) })
+}
+
+func (s *metricsWorkspaceStore) Add(ctx context.Context, workspace *types.Workspace) (*types.Workspace, error) {
+	return observeStoreCall(s.metrics, "workspaces", "add", func() (*types.Workspace, error) { return s.next.Add(ctx, workspace) })
+}
+
+func (s *metricsWorkspaceStore) Update(ctx context.Context, workspace *types.Workspace) (*types.Workspace, error) {
+	return observeStoreCall(s.metrics, "workspaces", "update", func() (*types.Workspace, error) { return s.next.Update(ctx, workspace) })
+}
+
+func (s *metricsWorkspaceStore) Delete(ctx context.Context, id string) error {
+	return observeStoreErr(s.metrics, "workspaces", "delete", func() error { return s.next.Delete(ctx, id) })
+}
+
+type metricsWorktreeStore struct {
+	next    WorktreeStore
+	metrics *daemonMetrics
+}
+
+func (s *metricsWorktreeStore) ListWorktrees(ctx context.Context, workspaceID string) ([]*types.Worktree, error) {
+	return observeStoreCall(s.metrics, "worktrees", "list", func() ([]*types.Worktree, error) { return s.next.ListWorktrees(ctx, workspaceID) })
+}
+
+func (s *metricsWorktreeStore) AddWorktree(ctx context.Context, workspaceID string, worktree *types.Worktree) (*types.Worktree, error) {
+	return observeStoreCall(s.metrics, "worktrees", "add", func() (*types.Worktree, error) { return s.next.AddWorktree(ctx, workspaceID, worktree) })
+}
+
+func (s *metricsWorktreeStore) UpdateWorktree(ctx context.Context, workspaceID string, worktree *types.Worktree) (*types.Worktree, error) {
+	return observeStoreCall(s.metrics, "worktrees", "update", func() (*types.Worktree, error) { return s.next.UpdateWorktree(ctx, workspaceID, worktree) })
+}
+
+func (s *metricsWorktreeStore) DeleteWorktree(ctx context.Context, workspaceID, worktreeID string) error {
+	return observeStoreErr(s.metrics, "worktrees", "delete", func() error { return s.next.DeleteWorktree(ctx, workspaceID, worktreeID) })
+}
+
+type metricsWorkspaceGroupStore struct {
+	next    WorkspaceGroupStore
+	metrics *daemonMetrics
+}
+
+func (s *metricsWorkspaceGroupStore) ListGroups(ctx context.Context) ([]*types.WorkspaceGroup, error) {
+	return observeStoreCall(s.metrics, "workspace_groups", "list", func() ([]*types.WorkspaceGroup, error) { return s.next.ListGroups(ctx) })
+}
+
+func (s *metricsWorkspaceGroupStore) GetGroup(ctx context.Context, id string) (*types.WorkspaceGroup, bool, error) {
+	return observeStoreLookup(s.metrics, "workspace_groups", "get", func() (*types.WorkspaceGroup, bool, error) { return s.next.GetGroup(ctx, id) })
+}
+
+func (s *metricsWorkspaceGroupStore) AddGroup(ctx context.Context, group *types.WorkspaceGroup) (*types.WorkspaceGroup, error) {
+	return observeStoreCall(s.metrics, "workspace_groups", "add", func() (*types.WorkspaceGroup, error) { return s.next.AddGroup(ctx, group) })
+}
+
+func (s *metricsWorkspaceGroupStore) UpdateGroup(ctx context.Context, group *types.WorkspaceGroup) (*types.WorkspaceGroup, error) {
+	return observeStoreCall(s.metrics, "workspace_groups", "update", func() (*types.WorkspaceGroup, error) { return s.next.UpdateGroup(ctx, group) })
+}
+
+func (s *metricsWorkspaceGroupStore) DeleteGroup(ctx context.Context, id string) error {
+	return observeStoreErr(s.metrics, "workspace_groups", "delete", func() error { return s.next.DeleteGroup(ctx, id) })
+}
+
+type metricsWorkflowTemplateStore struct {
+	next    WorkflowTemplateStore
+	metrics *daemonMetrics
+}
+
+func (s *metricsWorkflowTemplateStore) ListWorkflowTemplates(ctx context.Context) ([]guidedworkflows.WorkflowTemplate, error) {
+	return observeStoreCall(s.metrics, "workflow_templates", "list", func() ([]guidedworkflows.WorkflowTemplate, error) {
+		return s.next.ListWorkflowTemplates(ctx)
+	})
+}
+
+type metricsWorkflowRunStore struct {
+	next    WorkflowRunStore
+	metrics *daemonMetrics
+}
+
+func (s *metricsWorkflowRunStore) ListWorkflowRuns(ctx context.Context) ([]guidedworkflows.RunStatusSnapshot, error) {
+	return observeStoreCall(s.metrics, "workflow_runs", "list", func() ([]guidedworkflows.RunStatusSnapshot, error) {
+		return s.next.ListWorkflowRuns(ctx)
+	})
+}
+
+func (s *metricsWorkflowRunStore) UpsertWorkflowRun(ctx context.Context, snapshot guidedworkflows.RunStatusSnapshot) error {
+	return observeStoreErr(s.metrics, "workflow_runs", "upsert", func() error { return s.next.UpsertWorkflowRun(ctx, snapshot) })
+}
+
+type metricsAppStateStore struct {
+	next    AppStateStore
+	metrics *daemonMetrics
+}
+
+func (s *metricsAppStateStore) Load(ctx context.Context) (*types.AppState, error) {
+	return observeStoreCall(s.metrics, "app_state", "load", func() (*types.AppState, error) { return s.next.Load(ctx) })
+}
+
+func (s *metricsAppStateStore) Save(ctx context.Context, state *types.AppState) error {
+	return observeStoreErr(s.metrics, "app_state", "save", func() error { return s.next.Save(ctx, state) })
+}
+
+type metricsSessionMetaStore struct {
+	next    SessionMetaStore
+	metrics *daemonMetrics
+}
+
+func (s *metricsSessionMetaStore) List(ctx context.Context) ([]*types.SessionMeta, error) {
+	return observeStoreCall(s.metrics, "session_meta", "list", func() ([]*types.SessionMeta, error) { return s.next.List(ctx) })
+}
+
+func (s *metricsSessionMetaStore) Get(ctx context.Context, sessionID string) (*types.SessionMeta, bool, error) {
+	return observeStoreLookup(s.metrics, "session_meta", "get", func() (*types.SessionMeta, bool, error) { return s.next.Get(ctx, sessionID) })
+}
+
+func (s *metricsSessionMetaStore) Upsert(ctx context.Context, meta *types.SessionMeta) (*types.SessionMeta, error) {
+	return observeStoreCall(s.metrics, "session_meta", "upsert", func() (*types.SessionMeta, error) { return s.next.Upsert(ctx, meta) })
+}
+
+func (s *metricsSessionMetaStore) Delete(ctx context.Context, sessionID string) error {
+	return observeStoreErr(s.metrics, "session_meta", "delete", func() error { return s.next.Delete(ctx, sessionID) })
+}
+
+type metricsSessionIndexStore struct {
+	next    SessionIndexStore
+	metrics *daemonMetrics
+}
+
+func (s *metricsSessionIndexStore) ListRecords(ctx context.Context) ([]*types.SessionRecord, error) {
+	return observeStoreCall(s.metrics, "session_index", "list", func() ([]*types.SessionRecord, error) { return s.next.ListRecords(ctx) })
+}
+
+func (s *metricsSessionIndexStore) GetRecord(ctx context.Context, sessionID string) (*types.SessionRecord, bool, error) {
+	return observeStoreLookup(s.metrics, "session_index", "get", func() (*types.SessionRecord, bool, error) { return s.next.GetRecord(ctx, sessionID) })
+}
+
+func (s *metricsSessionIndexStore) UpsertRecord(ctx context.Context, record *types.SessionRecord) (*types.SessionRecord, error) {
+	return observeStoreCall(s.metrics, "session_index", "upsert", func() (*types.SessionRecord, error) { return s.next.UpsertRecord(ctx, record) })
+}
+
+func (s *metricsSessionIndexStore) DeleteRecord(ctx context.Context, sessionID string) error {
+	return observeStoreErr(s.metrics, "session_index", "delete", func() error { return s.next.DeleteRecord(ctx, sessionID) })
+}
+
+type metricsApprovalStore struct {
+	next    ApprovalStore
+	metrics *daemonMetrics
+}
+
+func (s *metricsApprovalStore) List(ctx context.Context) ([]*types.Approval, error) {
+	return observeStoreCall(s.metrics, "approvals", "list_all", func() ([]*types.Approval, error) { return s.next.List(ctx) })
+}
+
+func (s *metricsApprovalStore) ListBySession(ctx context.Context, sessionID string) ([]*types.Approval, error) {
+	return observeStoreCall(s.metrics, "approvals", "list", func() ([]*types.Approval, error) { return s.next.ListBySession(ctx, sessionID) })
+}
+
+func (s *metricsApprovalStore) Get(ctx context.Context, sessionID string, requestID int) (*types.Approval, bool, error) {
+	return observeStoreLookup(s.metrics, "approvals", "get", func() (*types.Approval, bool, error) { return s.next.Get(ctx, sessionID, requestID) })
+}
+
+func (s *metricsApprovalStore) Upsert(ctx context.Context, approval *types.Approval) (*types.Approval, error) {
+	return observeStoreCall(s.metrics, "approvals", "upsert", func() (*types.Approval, error) { return s.next.Upsert(ctx, approval) })
+}
+
+func (s *metricsApprovalStore) Delete(ctx context.Context, sessionID string, requestID int) error {
+	return observeStoreErr(s.metrics, "approvals", "delete", func() error { return s.next.Delete(ctx, sessionID, requestID) })
+}
+
+func (s *metricsApprovalStore) DeleteSession(ctx context.Context, sessionID string) error {
+	return observeStoreErr(s.metrics, "approvals", "delete_session", func() error { return s.next.DeleteSession(ctx, sessionID) })
+}
+
+type metricsNoteStore struct {
+	next    NoteStore
+	metrics *daemonMetrics
+}
+
+func (s *metricsNoteStore) List(ctx context.Context, filter store.NoteFilter) ([]*types.Note, error) {
+	return observeStoreCall(s.metrics, "notes", "list", func() ([]*types.Note, error) { return s.next.List(ctx, filter) })
+}
+
+func (s *metricsNoteStore) Get(ctx context.Context, id string) (*types.Note, bool, error) {
+	return observeStoreLookup(s.metrics, "notes", "get", func() (*types.Note, bool, error) { return s.next.Get(ctx, id) })
+}
+
+func (s *metricsNoteStore) Upsert(ctx context.Context, note *types.Note) (*types.Note, error) {
+	return observeStoreCall(s.metrics, "notes", "upsert", func() (*types.Note, error) { return s.next.Upsert(ctx, note) })
+}
+
+func (s *metricsNoteStore) Delete(ctx context.Context, id string) error {
+	return observeStoreErr(s.metrics, "notes", "delete", func() error { return s.next.Delete(ctx, id) })
+}
+
+type metricsSnippetStore struct {
+	next    SnippetStore
+	metrics *daemonMetrics
+}
+
+func (s *metricsSnippetStore) List(ctx context.Context) ([]*types.Snippet, error) {
+	return observeStoreCall(s.metrics, "snippets", "list", func() ([]*types.Snippet, error) { return s.next.List(ctx) })
+}
+
+func (s *metricsSnippetStore) Get(ctx context.Context, id string) (*types.Snippet, bool, error) {
+	return observeStoreLookup(s.metrics, "snippets", "get", func() (*types.Snippet, bool, error) { return s.next.Get(ctx, id) })
+}
+
+func (s *metricsSnippetStore) Upsert(ctx context.Context, snippet *types.Snippet) (*types.Snippet, error) {
+	return observeStoreCall(s.metrics, "snippets", "upsert", func() (*types.Snippet, error) { return s.next.Upsert(ctx, snippet) })
+}
+
+func (s *metricsSnippetStore) Delete(ctx context.Context, id string) error {
+	return observeStoreErr(s.metrics, "snippets", "delete", func() error { return s.next.Delete(ctx, id) })
+}
+
+type metricsApprovalRuleStore struct {
+	next    ApprovalRuleStore
+	metrics *daemonMetrics
+}
+
+func (s *metricsApprovalRuleStore) List(ctx context.Context) ([]*types.ApprovalRule, error) {
+	return observeStoreCall(s.metrics, "approval_rules", "list", func() ([]*types.ApprovalRule, error) { return s.next.List(ctx) })
+}
+
+func (s *metricsApprovalRuleStore) Get(ctx context.Context, id string) (*types.ApprovalRule, bool, error) {
+	return observeStoreLookup(s.metrics, "approval_rules", "get", func() (*types.ApprovalRule, bool, error) { return s.next.Get(ctx, id) })
+}
+
+func (s *metricsApprovalRuleStore) Upsert(ctx context.Context, rule *types.ApprovalRule) (*types.ApprovalRule, error) {
+	return observeStoreCall(s.metrics, "approval_rules", "upsert", func() (*types.ApprovalRule, error) { return s.next.Upsert(ctx, rule) })
+}
+
+func (s *metricsApprovalRuleStore) Delete(ctx context.Context, id string) error {
+	return observeStoreErr(s.metrics, "approval_rules", "delete", func() error { return s.next.Delete(ctx, id) })
+}
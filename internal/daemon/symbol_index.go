@@ -0,0 +1,234 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"control/internal/types"
+)
+
+const (
+	defaultSymbolSearchLimit = 20
+	maxSymbolSearchLimit     = 100
+	symbolIndexTTL           = 30 * time.Second
+	maxSymbolIndexFiles      = 20000
+	maxSymbolIndexFileBytes  = 1 << 20
+)
+
+// SymbolSearcher finds code symbols defined under a directory.
+type SymbolSearcher interface {
+	SearchSymbols(ctx context.Context, root, query string, limit int) ([]types.CodeSymbol, error)
+}
+
+// SymbolIndex is a ctags-style index of Go and TypeScript definitions. Each
+// root is scanned with line patterns on first use and rescanned once its
+// index is older than symbolIndexTTL.
+type SymbolIndex struct {
+	mu    sync.Mutex
+	roots map[string]*symbolIndexRoot
+	now   func() time.Time
+}
+
+type symbolIndexRoot struct {
+	builtAt time.Time
+	symbols []types.CodeSymbol
+}
+
+func NewSymbolIndex() *SymbolIndex {
+	return &SymbolIndex{roots: map[string]*symbolIndexRoot{}, now: time.Now}
+}
+
+func (i *SymbolIndex) SearchSymbols(ctx context.Context, root, query string, limit int) ([]types.CodeSymbol, error) {
+	root = strings.TrimSpace(root)
+	if root == "" {
+		return nil, invalidError("symbol search root is required", nil)
+	}
+	if limit <= 0 {
+		limit = defaultSymbolSearchLimit
+	}
+	limit = min(limit, maxSymbolSearchLimit)
+	symbols, err := i.symbols(ctx, filepath.Clean(root))
+	if err != nil {
+		return nil, err
+	}
+	return rankSymbols(symbols, query, limit), nil
+}
+
+func (i *SymbolIndex) symbols(ctx context.Context, root string) ([]types.CodeSymbol, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if entry, ok := i.roots[root]; ok && i.now().Sub(entry.builtAt) < symbolIndexTTL {
+		return entry.symbols, nil
+	}
+	symbols, err := scanSymbols(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	i.roots[root] = &symbolIndexRoot{builtAt: i.now(), symbols: symbols}
+	return symbols, nil
+}
+
+var skippedSymbolDirs = map[string]struct{}{
+	"node_modules": {},
+	"vendor":       {},
+	"dist":         {},
+	"build":        {},
+}
+
+func scanSymbols(ctx context.Context, root string) ([]types.CodeSymbol, error) {
+	info, err := os.Stat(root)
+	if err != nil || !info.IsDir() {
+		return nil, invalidError("symbol search root is not a directory", err)
+	}
+	var symbols []types.CodeSymbol
+	files := 0
+	walkErr := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if entry.IsDir() {
+			name := entry.Name()
+			if path != root && strings.HasPrefix(name, ".") {
+				return filepath.SkipDir
+			}
+			if _, skip := skippedSymbolDirs[name]; skip {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		patterns := symbolPatternsForFile(entry.Name())
+		if patterns == nil {
+			return nil
+		}
+		if files++; files > maxSymbolIndexFiles {
+			return filepath.SkipAll
+		}
+		symbols = append(symbols, scanFileSymbols(path, patterns)...)
+		return nil
+	})
+	if walkErr != nil {
+		return nil, unavailableError("symbol index scan failed", walkErr)
+	}
+	return symbols, nil
+}
+
+type symbolPattern struct {
+	kind    string
+	pattern *regexp.Regexp
+}
+
+var goSymbolPatterns = []symbolPattern{
+	{kind: "method", pattern: regexp.MustCompile(`^func\s+\(\s*\w*\s*\*?\s*(\w+)(?:\[[^\]]*\])?\s*\)\s*(\w+)`)},
+	{kind: "func", pattern: regexp.MustCompile(`^func\s+(\w+)`)},
+	{kind: "type", pattern: regexp.MustCompile(`^type\s+(\w+)`)},
+	{kind: "const", pattern: regexp.MustCompile(`^const\s+(\w+)`)},
+	{kind: "var", pattern: regexp.MustCompile(`^var\s+(\w+)`)},
+}
+
+var typeScriptSymbolPatterns = []symbolPattern{
+	{kind: "class", pattern: regexp.MustCompile(`^\s*(?:export\s+)?(?:default\s+)?(?:declare\s+)?(?:abstract\s+)?class\s+([A-Za-z_$][\w$]*)`)},
+	{kind: "function", pattern: regexp.MustCompile(`^\s*(?:export\s+)?(?:default\s+)?(?:async\s+)?function\*?\s+([A-Za-z_$][\w$]*)`)},
+	{kind: "interface", pattern: regexp.MustCompile(`^\s*(?:export\s+)?(?:declare\s+)?interface\s+([A-Za-z_$][\w$]*)`)},
+	{kind: "type", pattern: regexp.MustCompile(`^\s*(?:export\s+)?(?:declare\s+)?type\s+([A-Za-z_$][\w$]*)\s*(?:<[^=]*>)?\s*=`)},
+	{kind: "enum", pattern: regexp.MustCompile(`^\s*(?:export\s+)?(?:declare\s+)?(?:const\s+)?enum\s+([A-Za-z_$][\w$]*)`)},
+	{kind: "const", pattern: regexp.MustCompile(`^export\s+(?:const|let|var)\s+([A-Za-z_$][\w$]*)`)},
+}
+
+func symbolPatternsForFile(name string) []symbolPattern {
+	switch {
+	case strings.HasSuffix(name, ".go"):
+		return goSymbolPatterns
+	case strings.HasSuffix(name, ".d.ts"):
+		return nil
+	case strings.HasSuffix(name, ".ts"), strings.HasSuffix(name, ".tsx"):
+		return typeScriptSymbolPatterns
+	}
+	return nil
+}
+
+func scanFileSymbols(path string, patterns []symbolPattern) []types.CodeSymbol {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+	if info, err := file.Stat(); err != nil || info.Size() > maxSymbolIndexFileBytes {
+		return nil
+	}
+	var symbols []types.CodeSymbol
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSymbolIndexFileBytes)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		for _, pattern := range patterns {
+			match := pattern.pattern.FindStringSubmatch(text)
+			if match == nil {
+				continue
+			}
+			symbol := types.CodeSymbol{Name: match[1], Kind: pattern.kind, Path: path, Line: line}
+			if len(match) > 2 {
+				symbol.Container = match[1]
+				symbol.Name = match[2]
+			}
+			symbols = append(symbols, symbol)
+			break
+		}
+	}
+	return symbols
+}
+
+// rankSymbols orders matches by exact name, then name prefix, then
+// substring of the qualified name, preferring shorter names.
+func rankSymbols(symbols []types.CodeSymbol, query string, limit int) []types.CodeSymbol {
+	query = strings.ToLower(strings.TrimSpace(query))
+	type ranked struct {
+		symbol types.CodeSymbol
+		rank   int
+	}
+	var matches []ranked
+	for _, symbol := range symbols {
+		name := strings.ToLower(symbol.Name)
+		rank := -1
+		switch {
+		case query == "" || name == query:
+			rank = 0
+		case strings.HasPrefix(name, query):
+			rank = 1
+		case strings.Contains(strings.ToLower(symbol.QualifiedName()), query):
+			rank = 2
+		}
+		if rank >= 0 {
+			matches = append(matches, ranked{symbol: symbol, rank: rank})
+		}
+	}
+	sort.SliceStable(matches, func(a, b int) bool {
+		if matches[a].rank != matches[b].rank {
+			return matches[a].rank < matches[b].rank
+		}
+		if len(matches[a].symbol.Name) != len(matches[b].symbol.Name) {
+			return len(matches[a].symbol.Name) < len(matches[b].symbol.Name)
+		}
+		return matches[a].symbol.Path < matches[b].symbol.Path
+	})
+	out := make([]types.CodeSymbol, 0, min(limit, len(matches)))
+	for _, match := range matches {
+		if len(out) == limit {
+			break
+		}
+		out = append(out, match.symbol)
+	}
+	return out
+}
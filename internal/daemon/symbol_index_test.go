@@ -0,0 +1,73 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeSymbolFixture(t *testing.T, root, name, content string) string {
+	t.Helper()
+	path := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	return path
+}
+
+func TestSymbolIndexFindsGoAndTypeScriptDefinitions(t *testing.T) {
+	root := t.TempDir()
+	goPath := writeSymbolFixture(t, root, "server/server.go", "package server\n\ntype Server struct{}\n\nfunc (s *Server) Serve() error {\n\treturn nil\n}\n\nfunc NewServer() *Server { return nil }\n")
+	tsPath := writeSymbolFixture(t, root, "web/app.ts", "export class AppShell {}\nexport interface ServeOptions {}\nexport async function serveStatic() {}\nexport const DEFAULT_PORT = 3000\n")
+	writeSymbolFixture(t, root, "node_modules/lib/index.ts", "export function serveVendored() {}\n")
+	writeSymbolFixture(t, root, "web/types.d.ts", "export interface ServeDeclared {}\n")
+
+	symbols, err := NewSymbolIndex().SearchSymbols(context.Background(), root, "serve", 10)
+	if err != nil {
+		t.Fatalf("SearchSymbols: %v", err)
+	}
+	var names []string
+	for _, symbol := range symbols {
+		names = append(names, symbol.QualifiedName())
+	}
+	want := []string{"Server.Serve", "Server", "serveStatic", "ServeOptions", "NewServer"}
+	if len(names) != len(want) {
+		t.Fatalf("expected %v, got %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, names)
+		}
+	}
+	if symbols[0].Path != goPath || symbols[0].Line != 5 || symbols[0].Kind != "method" {
+		t.Fatalf("unexpected method location: %#v", symbols[0])
+	}
+	if symbols[2].Path != tsPath || symbols[2].Line != 3 {
+		t.Fatalf("unexpected function location: %#v", symbols[2])
+	}
+}
+
+func TestSymbolIndexRescansAfterTTL(t *testing.T) {
+	root := t.TempDir()
+	writeSymbolFixture(t, root, "a.go", "package a\n\nfunc Alpha() {}\n")
+	now := time.Now()
+	index := NewSymbolIndex()
+	index.now = func() time.Time { return now }
+
+	if symbols, _ := index.SearchSymbols(context.Background(), root, "beta", 0); len(symbols) != 0 {
+		t.Fatalf("expected no beta symbol yet, got %#v", symbols)
+	}
+	writeSymbolFixture(t, root, "b.go", "package a\n\nfunc Beta() {}\n")
+	if symbols, _ := index.SearchSymbols(context.Background(), root, "beta", 0); len(symbols) != 0 {
+		t.Fatalf("expected cached index before TTL, got %#v", symbols)
+	}
+	now = now.Add(symbolIndexTTL)
+	if symbols, _ := index.SearchSymbols(context.Background(), root, "beta", 0); len(symbols) != 1 {
+		t.Fatalf("expected rescan after TTL, got %#v", symbols)
+	}
+}
@@ -127,6 +127,8 @@ func (e *titleProviderError) Error() string {
 		return provider + " returned an invalid response payload"
 	case "empty_title":
 		return provider + " returned an empty title"
+	case "empty_message":
+		return provider + " returned an empty commit message"
 	default:
 		return provider + " request failed"
 	}
@@ -145,6 +147,22 @@ type openRouterChatRequestMessage struct {
 }
 
 func (g *openRouterTitleGenerator) GenerateTitle(ctx context.Context, prompt string) (string, error) {
+	return g.complete(ctx,
+		"Generate a concise, descriptive title for a coding conversation. Return title text only.",
+		prompt, 32, "empty_title")
+}
+
+// GenerateCommitMessage drafts a Conventional Commit message for the change
+// described by prompt.
+func (g *openRouterTitleGenerator) GenerateCommitMessage(ctx context.Context, prompt string) (string, error) {
+	return g.complete(ctx,
+		"Write a git commit message in the Conventional Commits format for the described change: "+
+			"a `type(scope): summary` subject under 72 characters, a blank line, then a short body. "+
+			"Return the commit message only, without code fences.",
+		prompt, 400, "empty_message")
+}
+
+func (g *openRouterTitleGenerator) complete(ctx context.Context, system, prompt string, maxTokens int, emptyKind string) (string, error) {
 	if g == nil {
 		return "", errors.New("title generator is nil")
 	}
@@ -163,7 +181,7 @@ func (g *openRouterTitleGenerator) GenerateTitle(ctx context.Context, prompt str
 		Messages: []openRouterChatRequestMessage{
 			{
 				Role:    "system",
-				Content: "Generate a concise, descriptive title for a coding conversation. Return title text only.",
+				Content: system,
 			},
 			{
 				Role:    "user",
@@ -171,7 +189,7 @@ func (g *openRouterTitleGenerator) GenerateTitle(ctx context.Context, prompt str
 			},
 		},
 		Temperature: 0.2,
-		MaxTokens:   32,
+		MaxTokens:   maxTokens,
 	}
 	payload, err := json.Marshal(body)
 	if err != nil {
@@ -206,14 +224,14 @@ func (g *openRouterTitleGenerator) GenerateTitle(ctx context.Context, prompt str
 			Kind:     "invalid_response",
 		}
 	}
-	title := strings.TrimSpace(extractOpenRouterTitleFromPayload(parsed))
-	if title == "" {
+	text := strings.TrimSpace(extractOpenRouterTitleFromPayload(parsed))
+	if text == "" {
 		return "", &titleProviderError{
 			Provider: "openrouter",
-			Kind:     "empty_title",
+			Kind:     emptyKind,
 		}
 	}
-	return title, nil
+	return text, nil
 }
 
 func extractOpenRouterTitleFromPayload(parsed map[string]any) string {
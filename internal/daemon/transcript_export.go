@@ -0,0 +1,189 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"control/internal/daemon/transcriptdomain"
+	"control/internal/transcriptexport"
+	"control/internal/types"
+)
+
+const defaultTranscriptExportLines = 5000
+
+// ExportTranscript builds an export document from the canonical transcript
+// snapshot of session id. Pending approvals are placed in the timeline by the
+// time they were requested.
+func (s *SessionService) ExportTranscript(ctx context.Context, id string, options transcriptexport.Options, lines int) (transcriptexport.Document, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return transcriptexport.Document{}, invalidError("session id is required", nil)
+	}
+	session, _, err := s.getSessionRecord(ctx, id)
+	if err != nil {
+		return transcriptexport.Document{}, err
+	}
+	if session == nil {
+		return transcriptexport.Document{}, notFoundError("session not found", ErrSessionNotFound)
+	}
+	if lines <= 0 {
+		lines = defaultTranscriptExportLines
+	}
+	snapshot, err := s.GetTranscriptSnapshot(ctx, id, lines)
+	if err != nil {
+		return transcriptexport.Document{}, err
+	}
+	var approvals []*types.Approval
+	if options.Approvals {
+		approvals, err = s.ListApprovals(ctx, id)
+		if err != nil {
+			return transcriptexport.Document{}, err
+		}
+	}
+	doc := buildTranscriptExport(session, snapshot, approvals, options)
+	if meta := s.getSessionMeta(ctx, id); meta != nil && strings.TrimSpace(meta.Title) != "" {
+		doc.Title = strings.TrimSpace(meta.Title)
+	}
+	return doc, nil
+}
+
+func buildTranscriptExport(session *types.Session, snapshot transcriptdomain.TranscriptSnapshot, approvals []*types.Approval, options transcriptexport.Options) transcriptexport.Document {
+	doc := transcriptexport.Document{
+		SessionID:  session.ID,
+		Title:      strings.TrimSpace(session.Title),
+		Provider:   session.Provider,
+		Cwd:        session.Cwd,
+		ExportedAt: time.Now().UTC(),
+		Entries:    []transcriptexport.Entry{},
+	}
+	pending := pendingExportApprovals(snapshot, approvals)
+	flushPending := func(before *time.Time) {
+		for len(pending) > 0 {
+			approval := pending[0]
+			if before != nil && (approval.CreatedAt.IsZero() || !approval.CreatedAt.Before(*before)) {
+				return
+			}
+			doc.Entries = append(doc.Entries, approvalExportEntry(approval, options))
+			pending = pending[1:]
+		}
+	}
+	for _, block := range snapshot.Blocks {
+		entry, ok := transcriptExportEntry(block)
+		if !ok || !transcriptExportIncludes(entry.Kind, options) {
+			continue
+		}
+		createdAt := transcriptBlockCreatedAt(block)
+		if createdAt != nil {
+			flushPending(createdAt)
+		}
+		if options.Timestamps {
+			entry.CreatedAt = createdAt
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	flushPending(nil)
+	return doc
+}
+
+// pendingExportApprovals returns the approvals that are still open, oldest
+// first: stored approvals the snapshot already shows as resolved are left
+// out. Approvals without a request time sort last, by request id.
+func pendingExportApprovals(snapshot transcriptdomain.TranscriptSnapshot, approvals []*types.Approval) []*types.Approval {
+	resolved := map[int]bool{}
+	for _, block := range snapshot.Blocks {
+		if !strings.EqualFold(strings.TrimSpace(block.Kind), "approval_resolved") && asString(block.Meta["type"]) != "approval_resolved" {
+			continue
+		}
+		if requestID, ok := asInt(block.Meta["request_id"]); ok {
+			resolved[requestID] = true
+		}
+	}
+	pending := make([]*types.Approval, 0, len(approvals))
+	for _, approval := range approvals {
+		if approval != nil && !resolved[approval.RequestID] {
+			pending = append(pending, approval)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		a, b := pending[i].CreatedAt, pending[j].CreatedAt
+		if a.IsZero() != b.IsZero() {
+			return b.IsZero()
+		}
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return pending[i].RequestID < pending[j].RequestID
+	})
+	return pending
+}
+
+func approvalExportEntry(approval *types.Approval, options transcriptexport.Options) transcriptexport.Entry {
+	entry := transcriptexport.Entry{
+		Kind: transcriptexport.EntryApproval,
+		Type: approval.Method,
+		Text: approvalExportText(approval),
+	}
+	if options.Timestamps && !approval.CreatedAt.IsZero() {
+		createdAt := approval.CreatedAt.UTC()
+		entry.CreatedAt = &createdAt
+	}
+	return entry
+}
+
+func transcriptExportEntry(block transcriptdomain.Block) (transcriptexport.Entry, bool) {
+	text := strings.TrimSpace(types.CollapseMentionContext(block.Text))
+	if text == "" {
+		return transcriptexport.Entry{}, false
+	}
+	entry := transcriptexport.Entry{Role: strings.TrimSpace(block.Role), Type: strings.TrimSpace(block.Kind), Text: text}
+	kind := strings.ToLower(entry.Type)
+	switch {
+	case strings.Contains(kind, "approval"):
+		entry.Kind = transcriptexport.EntryApproval
+	case entry.Role == "reasoning" || kind == "reasoning" || strings.EqualFold(block.Variant, "reasoning"):
+		entry.Kind = transcriptexport.EntryReasoning
+	case entry.Role == "user" || entry.Role == "assistant":
+		entry.Kind = transcriptexport.EntryMessage
+	default:
+		entry.Kind = transcriptexport.EntryTool
+	}
+	return entry, true
+}
+
+func transcriptExportIncludes(kind transcriptexport.EntryKind, options transcriptexport.Options) bool {
+	switch kind {
+	case transcriptexport.EntryReasoning:
+		return options.Reasoning
+	case transcriptexport.EntryTool:
+		return options.ToolCalls
+	case transcriptexport.EntryApproval:
+		return options.Approvals
+	}
+	return true
+}
+
+func transcriptBlockCreatedAt(block transcriptdomain.Block) *time.Time {
+	createdAt := resolveItemCreatedAt(block.Meta)
+	if createdAt.IsZero() {
+		return nil
+	}
+	createdAt = createdAt.UTC()
+	return &createdAt
+}
+
+func approvalExportText(approval *types.Approval) string {
+	lines := []string{"Requested: " + approval.Method}
+	var params map[string]any
+	if len(approval.Params) > 0 && json.Unmarshal(approval.Params, &params) == nil {
+		for _, key := range []string{"command", "cmd", "reason", "message", "title"} {
+			if value := strings.TrimSpace(asString(params[key])); value != "" {
+				lines = append(lines, strings.ToUpper(key[:1])+key[1:]+": "+value)
+			}
+		}
+	}
+	lines = append(lines, "Status: pending")
+	return strings.Join(lines, "\n")
+}
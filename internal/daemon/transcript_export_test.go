@@ -0,0 +1,105 @@
+package daemon
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"control/internal/daemon/transcriptdomain"
+	"control/internal/transcriptexport"
+	"control/internal/types"
+)
+
+func TestBuildTranscriptExportFiltersEntries(t *testing.T) {
+	session := &types.Session{ID: "s1", Provider: "codex", Title: "raw", Cwd: "/repo"}
+	snapshot := transcriptdomain.TranscriptSnapshot{Blocks: []transcriptdomain.Block{
+		{Kind: "userMessage", Role: "user", Text: "fix it", Meta: map[string]any{"created_at": "2026-03-04T05:06:07Z"}},
+		{Kind: "reasoning", Role: "reasoning", Text: "thinking"},
+		{Kind: "commandExecution", Text: "go test ./..."},
+		{Kind: "agentMessage", Role: "assistant", Text: "Done."},
+	}}
+	approvals := []*types.Approval{{
+		SessionID: "s1",
+		RequestID: 2,
+		Method:    "item/commandExecution/requestApproval",
+		Params:    []byte(`{"command":"rm -rf build"}`),
+		CreatedAt: time.Date(2026, 3, 4, 5, 7, 0, 0, time.UTC),
+	}}
+
+	doc := buildTranscriptExport(session, snapshot, approvals, transcriptexport.DefaultOptions())
+
+	if len(doc.Entries) != 4 {
+		t.Fatalf("expected reasoning to be left out by default, got %#v", doc.Entries)
+	}
+	if doc.Entries[0].CreatedAt == nil || doc.Entries[0].CreatedAt.Format(time.RFC3339) != "2026-03-04T05:06:07Z" {
+		t.Fatalf("expected message timestamp, got %#v", doc.Entries[0].CreatedAt)
+	}
+	if doc.Entries[1].Kind != transcriptexport.EntryTool || doc.Entries[2].Kind != transcriptexport.EntryMessage {
+		t.Fatalf("unexpected entry kinds %#v", doc.Entries)
+	}
+	last := doc.Entries[3]
+	if last.Kind != transcriptexport.EntryApproval || last.Text != "Requested: item/commandExecution/requestApproval\nCommand: rm -rf build\nStatus: pending" {
+		t.Fatalf("unexpected approval entry %#v", last)
+	}
+
+	doc = buildTranscriptExport(session, snapshot, nil, transcriptexport.Options{Reasoning: true})
+	if len(doc.Entries) != 3 || doc.Entries[1].Kind != transcriptexport.EntryReasoning || doc.Entries[0].CreatedAt != nil {
+		t.Fatalf("expected messages and reasoning without timestamps, got %#v", doc.Entries)
+	}
+}
+
+func TestBuildTranscriptExportPlacesPendingApprovalsInTimeline(t *testing.T) {
+	session := &types.Session{ID: "s1", Provider: "codex"}
+	snapshot := transcriptdomain.TranscriptSnapshot{Blocks: []transcriptdomain.Block{
+		{Kind: "userMessage", Role: "user", Text: "clean up", Meta: map[string]any{"created_at": "2026-03-04T05:00:00Z"}},
+		{Kind: "approval_resolved", Role: "approval_resolved", Text: "Approval accepted by rule", Meta: map[string]any{"type": "approval_resolved", "request_id": 1, "created_at": "2026-03-04T05:01:00Z"}},
+		{Kind: "agentMessage", Role: "assistant", Text: "Waiting for approval.", Meta: map[string]any{"created_at": "2026-03-04T05:03:00Z"}},
+	}}
+	approvals := []*types.Approval{
+		{SessionID: "s1", RequestID: 3, Method: "item/fileChange/requestApproval", CreatedAt: time.Date(2026, 3, 4, 5, 4, 0, 0, time.UTC)},
+		{SessionID: "s1", RequestID: 1, Method: "item/commandExecution/requestApproval", CreatedAt: time.Date(2026, 3, 4, 5, 0, 30, 0, time.UTC)},
+		{SessionID: "s1", RequestID: 2, Method: "item/commandExecution/requestApproval", CreatedAt: time.Date(2026, 3, 4, 5, 2, 0, 0, time.UTC)},
+	}
+
+	doc := buildTranscriptExport(session, snapshot, approvals, transcriptexport.DefaultOptions())
+
+	var got []string
+	for _, entry := range doc.Entries {
+		got = append(got, string(entry.Kind)+":"+entry.Type)
+	}
+	want := []string{
+		"message:userMessage",
+		"approval:approval_resolved",
+		"approval:item/commandExecution/requestApproval",
+		"message:agentMessage",
+		"approval:item/fileChange/requestApproval",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected entry order:\n got %v\nwant %v", got, want)
+	}
+	if doc.Entries[2].CreatedAt == nil || !doc.Entries[2].CreatedAt.Equal(approvals[2].CreatedAt) {
+		t.Fatalf("expected the still pending request 2 in the timeline, got %#v", doc.Entries[2])
+	}
+}
+
+func TestAPITranscriptExportErrors(t *testing.T) {
+	server := newTestServer(t, newTestManager(t))
+	defer server.Close()
+
+	for path, want := range map[string]int{
+		"/v1/sessions/missing/transcript/export?format=md":  http.StatusNotFound,
+		"/v1/sessions/missing/transcript/export?format=pdf": http.StatusBadRequest,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		req.Header.Set("Authorization", "Bearer token")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("export: %v", err)
+		}
+		closeTestCloser(t, resp.Body)
+		if resp.StatusCode != want {
+			t.Fatalf("%s: expected %d, got %d", path, want, resp.StatusCode)
+		}
+	}
+}
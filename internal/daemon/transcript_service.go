@@ -64,12 +64,15 @@ func (s *SessionService) transcriptFollowOpenerOrDefault() TranscriptFollowOpene
 	defer s.transcriptMu.Unlock()
 	if s.transcriptFollowOpen == nil {
 		if s.transcriptHubRegistry == nil {
-			s.transcriptHubRegistry = NewDefaultCanonicalTranscriptHubRegistryWithReconnectPolicy(
+			registry := newDefaultCanonicalTranscriptHubRegistryWithPolicies(
 				s.transcriptIngressFactoryOrDefault(),
 				s.transcriptMapperOrDefault(),
 				s.transcriptProjectorFactoryOrDefault(),
+				defaultCanonicalTranscriptHubIdleTTL,
 				s.transcriptReconnectPolicy,
 			)
+			registry.metrics = s.metrics
+			s.transcriptHubRegistry = registry
 		}
 		s.transcriptFollowOpen = NewCanonicalTranscriptFollowService(s.transcriptHubRegistry)
 	}
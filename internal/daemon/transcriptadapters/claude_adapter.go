@@ -5,6 +5,7 @@ import (
 
 	"control/internal/daemon/transcriptdomain"
 	"control/internal/providers"
+	"control/internal/types"
 )
 
 type claudeTranscriptAdapter struct {
@@ -134,7 +135,7 @@ func extractItemText(item map[string]any) string {
 			}
 		}
 	}
-	parts := make([]string, 0, len(content))
+	parts := make([]string, 0, len(content)+1)
 	for _, block := range content {
 		if block == nil {
 			continue
@@ -147,5 +148,8 @@ func extractItemText(item map[string]any) string {
 			parts = append(parts, text)
 		}
 	}
-	return strings.TrimSpace(strings.Join(parts, "\n"))
+	if note := userMessageAttachmentNote(content); note != "" {
+		parts = append(parts, note)
+	}
+	return strings.TrimSpace(types.CollapseMentionContext(strings.Join(parts, "\n")))
 }
@@ -2,6 +2,7 @@ package transcriptadapters
 
 import (
 	"encoding/json"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -297,6 +298,16 @@ func blockFromItemWithExtractor(item map[string]any, extractor TranscriptTextExt
 			item["result"],
 		)
 	}
+	if strings.EqualFold(kind, "userMessage") {
+		text = types.CollapseMentionContext(text)
+		if note := userMessageAttachmentNote(item["content"]); note != "" {
+			if transcriptdomain.IsSemanticallyEmpty(text) {
+				text = note
+			} else {
+				text += "\n" + note
+			}
+		}
+	}
 	if transcriptdomain.IsSemanticallyEmpty(text) {
 		return transcriptdomain.Block{}, false
 	}
@@ -333,6 +344,36 @@ func blockFromItemWithExtractor(item map[string]any, extractor TranscriptTextExt
 	return block, true
 }
 
+// userMessageAttachmentNote names the attachments of user message content:
+// Codex echoes attached images as localImage items, other providers keep
+// the attachment input items.
+func userMessageAttachmentNote(raw any) string {
+	var content []map[string]any
+	switch typed := raw.(type) {
+	case []map[string]any:
+		content = typed
+	case []any:
+		for _, entry := range typed {
+			if m, ok := entry.(map[string]any); ok {
+				content = append(content, m)
+			}
+		}
+	}
+	var names []string
+	for _, entry := range content {
+		if attachment, ok := types.InputAttachmentFromItem(entry); ok {
+			names = append(names, attachment.DisplayName())
+			continue
+		}
+		if strings.TrimSpace(asString(entry["type"])) == "localImage" {
+			if path := strings.TrimSpace(asString(entry["path"])); path != "" {
+				names = append(names, filepath.Base(path))
+			}
+		}
+	}
+	return types.AttachmentNote(names)
+}
+
 func transcriptMetaFromCodexEventParams(params map[string]any) map[string]any {
 	if len(params) == 0 {
 		return nil
@@ -349,6 +349,41 @@ func TestBlockFromItem(t *testing.T) {
 	}
 }
 
+func TestBlockFromItemNotesUserMessageAttachments(t *testing.T) {
+	block, ok := BlockFromItem(map[string]any{
+		"id":   "u1",
+		"type": "userMessage",
+		"content": []any{
+			map[string]any{"type": "text", "text": "see this"},
+			map[string]any{"type": "localImage", "path": "/tmp/screen.png"},
+			map[string]any{"type": "attachment", "kind": "file", "path": "/tmp/app.log"},
+		},
+	})
+	if !ok {
+		t.Fatal("expected block conversion")
+	}
+	if block.Text != "see this\n[attached: screen.png, app.log]" {
+		t.Fatalf("unexpected user message text: %q", block.Text)
+	}
+}
+
+func TestBlockFromItemCollapsesUserMessageMentionContext(t *testing.T) {
+	block, ok := BlockFromItem(map[string]any{
+		"id":   "u1",
+		"type": "userMessage",
+		"content": []any{
+			map[string]any{"type": "text", "text": "compare with @note:n1"},
+			map[string]any{"type": "text", "text": types.MentionContext("@note:n1", "Note plan:\n\nship it")},
+		},
+	})
+	if !ok {
+		t.Fatal("expected block conversion")
+	}
+	if block.Text != "compare with @note:n1" {
+		t.Fatalf("expected mention context collapsed, got %q", block.Text)
+	}
+}
+
 func TestBlockFromItemPreservesWhitespace(t *testing.T) {
 	block, ok := BlockFromItem(map[string]any{
 		"id":   "m1",
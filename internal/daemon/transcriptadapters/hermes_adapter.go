@@ -113,7 +113,9 @@ func hermesBlockFromUpdate(update acp.SessionUpdateNotification) (transcriptdoma
 	case acp.SessionUpdateAgentMessageChunk:
 		return hermesTextBlock("message", "assistant", "", []acp.ContentBlock{update.AgentMessageChunk.Content}, nil), true
 	case acp.SessionUpdateUserMessageChunk:
-		return hermesTextBlock("message", "user", "", []acp.ContentBlock{update.UserMessageChunk.Content}, nil), true
+		block := hermesTextBlock("message", "user", "", []acp.ContentBlock{update.UserMessageChunk.Content}, nil)
+		block.Text = types.CollapseMentionContext(block.Text)
+		return block, true
 	case acp.SessionUpdateAgentThoughtChunk:
 		return hermesTextBlock("thinking", "assistant", "thinking", []acp.ContentBlock{update.AgentThoughtChunk.Content}, nil), true
 	case acp.SessionUpdateToolCall:
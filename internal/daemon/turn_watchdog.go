@@ -0,0 +1,116 @@
+package daemon
+
+import (
+	"strings"
+	"time"
+
+	"control/internal/types"
+)
+
+const turnWatchdogInterval = 30 * time.Second
+
+type turnWatchdogEntry struct {
+	sessionID string
+	turnID    string
+	provider  string
+	startedAt time.Time
+}
+
+// turnWatchdog reports turns that are still running after the configured
+// threshold. Each turn is reported at most once; a turn that completes first
+// is forgotten without a notification.
+type turnWatchdog struct {
+	turns *pendingTurnTracker[turnWatchdogEntry]
+}
+
+func newTurnWatchdog() *turnWatchdog {
+	return &turnWatchdog{turns: newPendingTurnTracker[turnWatchdogEntry]()}
+}
+
+func (w *turnWatchdog) TurnStarted(sessionID, turnID, provider string, at time.Time) {
+	if w == nil {
+		return
+	}
+	w.turns.Start(sessionID, turnID, turnWatchdogEntry{
+		sessionID: strings.TrimSpace(sessionID),
+		turnID:    strings.TrimSpace(turnID),
+		provider:  strings.TrimSpace(provider),
+		startedAt: at,
+	}, at)
+}
+
+func (w *turnWatchdog) TurnCompleted(sessionID, turnID string) {
+	if w == nil {
+		return
+	}
+	w.turns.Complete(sessionID, turnID)
+}
+
+// Check publishes turn.long_running for every turn started more than threshold
+// before now.
+func (w *turnWatchdog) Check(publisher NotificationPublisher, threshold time.Duration, now time.Time) {
+	if w == nil || publisher == nil || threshold <= 0 {
+		return
+	}
+	for _, entry := range w.turns.TakeStartedBefore(now.Add(-threshold)) {
+		publisher.Publish(types.NotificationEvent{
+			Trigger:    types.NotificationTriggerTurnLongRunning,
+			OccurredAt: now.Format(time.RFC3339Nano),
+			SessionID:  entry.sessionID,
+			TurnID:     entry.turnID,
+			Provider:   entry.provider,
+			Status:     "running",
+			Source:     "turn_watchdog",
+			Payload: map[string]any{
+				"kind":            "turn_long_running",
+				"started_at":      entry.startedAt.Format(time.RFC3339Nano),
+				"running_minutes": int(now.Sub(entry.startedAt) / time.Minute),
+			},
+		})
+	}
+}
+
+// Run checks for long-running turns until the returned stop function is
+// called. A non-positive threshold disables the watchdog.
+func (w *turnWatchdog) Run(publisher NotificationPublisher, threshold time.Duration) func() {
+	if w == nil || publisher == nil || threshold <= 0 {
+		return func() {}
+	}
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(turnWatchdogInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				w.Check(publisher, threshold, now.UTC())
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// turnWatchdogNotificationPublisher clears watched turns when their completion
+// event is published.
+type turnWatchdogNotificationPublisher struct {
+	downstream NotificationPublisher
+	watchdog   *turnWatchdog
+}
+
+func newTurnWatchdogNotificationPublisher(downstream NotificationPublisher, w *turnWatchdog) NotificationPublisher {
+	if w == nil {
+		return downstream
+	}
+	return &turnWatchdogNotificationPublisher{downstream: downstream, watchdog: w}
+}
+
+func (p *turnWatchdogNotificationPublisher) Publish(event types.NotificationEvent) {
+	if event.Trigger == types.NotificationTriggerTurnCompleted {
+		p.watchdog.TurnCompleted(event.SessionID, event.TurnID)
+	}
+	if p.downstream != nil {
+		p.downstream.Publish(event)
+	}
+}
@@ -257,7 +257,7 @@ func newWorkflowIntegrationServer(t *testing.T) (*httptest.Server, *SessionManag
 	)
 
 	// Build workflow RunService with a real prompt dispatcher and the test template.
-	dispatcher := newGuidedWorkflowPromptDispatcher(coreCfg, manager, stores, compositeLive, logger)
+	dispatcher := newGuidedWorkflowPromptDispatcher(coreCfg, manager, stores, compositeLive, nil, logger)
 	workflowRuns := guidedworkflows.NewRunService(
 		guidedworkflows.Config{Enabled: true},
 		guidedworkflows.WithTemplate(workflowE2ETemplate),
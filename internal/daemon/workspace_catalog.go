@@ -0,0 +1,499 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"control/internal/types"
+)
+
+const (
+	defaultWorkspaceScanDepth = 4
+	maxWorkspaceScanDepth     = 12
+)
+
+// workspaceScanSkipDirs are never descended into while scanning; hidden
+// directories are skipped as well.
+var workspaceScanSkipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"dist":         true,
+	"build":        true,
+}
+
+// WorkspaceCatalogService discovers repositories to register as workspaces
+// and moves workspaces, worktrees and groups in and out of the daemon as a
+// types.WorkspaceExport.
+type WorkspaceCatalogService struct {
+	workspaces *WorkspaceSyncService
+	groups     *WorkspaceGroupService
+}
+
+func NewWorkspaceCatalogService(stores *Stores, syncer SessionSyncer) *WorkspaceCatalogService {
+	return &WorkspaceCatalogService{
+		workspaces: NewWorkspaceSyncService(stores, syncer),
+		groups:     NewWorkspaceGroupService(stores),
+	}
+}
+
+// Scan walks req.Root for git repositories. Repositories are not descended
+// into, so nested checkouts and linked worktrees are reported through the
+// worktree list of their main repository.
+func (s *WorkspaceCatalogService) Scan(ctx context.Context, req types.WorkspaceScanRequest) (*types.WorkspaceScanResult, error) {
+	root := strings.TrimSpace(req.Root)
+	if root == "" {
+		return nil, invalidError("scan root is required", nil)
+	}
+	root, err := filepath.Abs(expandHomePath(root))
+	if err != nil {
+		return nil, invalidError(err.Error(), err)
+	}
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return nil, invalidError(fmt.Sprintf("scan root %s is not a directory", root), err)
+	}
+	depth := req.MaxDepth
+	if depth <= 0 {
+		depth = defaultWorkspaceScanDepth
+	}
+	depth = min(depth, maxWorkspaceScanDepth)
+
+	workspaces, err := s.workspaces.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	registered := map[string]string{}
+	for _, ws := range workspaces {
+		if ws != nil {
+			registered[canonicalScanPath(ws.RepoPath)] = ws.ID
+		}
+	}
+	groups := map[string]string{}
+	if existing, err := s.groups.List(ctx); err == nil {
+		for _, group := range existing {
+			if group != nil {
+				groups[strings.ToLower(group.Name)] = group.ID
+			}
+		}
+	}
+
+	result := &types.WorkspaceScanResult{Root: root}
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, walkErr error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if walkErr != nil || !entry.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		if path != root {
+			name := entry.Name()
+			if strings.HasPrefix(name, ".") || workspaceScanSkipDirs[name] {
+				return filepath.SkipDir
+			}
+		}
+		info, err := os.Lstat(filepath.Join(path, ".git"))
+		if err == nil {
+			if info.IsDir() {
+				result.Candidates = append(result.Candidates, newWorkspaceScanCandidate(root, path, registered, groups))
+			}
+			return filepath.SkipDir
+		}
+		if rel != "." && len(strings.Split(rel, string(filepath.Separator))) >= depth {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, invalidError(err.Error(), err)
+	}
+	suggestWorkspaceNames(result.Candidates)
+	return result, nil
+}
+
+func newWorkspaceScanCandidate(root, repoPath string, registered, groups map[string]string) *types.WorkspaceScanCandidate {
+	candidate := &types.WorkspaceScanCandidate{
+		Name:                filepath.Base(repoPath),
+		RepoPath:            repoPath,
+		ExistingWorkspaceID: registered[canonicalScanPath(repoPath)],
+	}
+	if parent := filepath.Dir(repoPath); repoPath != root && parent != root {
+		candidate.Group = filepath.Base(parent)
+		candidate.GroupID = groups[strings.ToLower(candidate.Group)]
+	}
+	worktrees, err := listGitWorktrees(repoPath)
+	if err != nil {
+		return candidate
+	}
+	for _, wt := range worktrees {
+		if wt == nil || canonicalScanPath(wt.Path) == canonicalScanPath(repoPath) {
+			continue
+		}
+		if _, err := os.Stat(wt.Path); err != nil {
+			continue
+		}
+		candidate.Worktrees = append(candidate.Worktrees, wt)
+	}
+	return candidate
+}
+
+// suggestWorkspaceNames qualifies repeated directory names with their group,
+// e.g. two "api" checkouts become "backend/api" and "legacy/api".
+func suggestWorkspaceNames(candidates []*types.WorkspaceScanCandidate) {
+	counts := map[string]int{}
+	for _, candidate := range candidates {
+		counts[candidate.Name]++
+	}
+	for _, candidate := range candidates {
+		if counts[candidate.Name] > 1 && candidate.Group != "" {
+			candidate.Name = candidate.Group + "/" + candidate.Name
+		}
+	}
+}
+
+func canonicalScanPath(path string) string {
+	path = filepath.Clean(path)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+// Export returns every workspace with its groups and registered worktrees.
+// Isolated worktrees belong to a single session and are left out.
+func (s *WorkspaceCatalogService) Export(ctx context.Context) (*types.WorkspaceExport, error) {
+	workspaces, err := s.workspaces.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.groups.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := &types.WorkspaceExport{Version: types.WorkspaceExportVersion, Groups: groups}
+	for _, ws := range workspaces {
+		if ws == nil {
+			continue
+		}
+		entry := *ws
+		entry.RepoPath = collapseHomePath(ws.RepoPath)
+		entry.AdditionalDirectories = make([]string, 0, len(ws.AdditionalDirectories))
+		for _, dir := range ws.AdditionalDirectories {
+			entry.AdditionalDirectories = append(entry.AdditionalDirectories, collapseHomePath(dir))
+		}
+		out.Workspaces = append(out.Workspaces, &entry)
+		worktrees, err := s.workspaces.ListWorktrees(ctx, ws.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, wt := range worktrees {
+			if wt == nil || wt.Isolated {
+				continue
+			}
+			entry := *wt
+			entry.Path = collapseHomePath(wt.Path)
+			entry.NotificationOverrides = exportNotificationOverrides(wt.NotificationOverrides)
+			entry.LinkedWorktrees = nil
+			for _, linked := range wt.LinkedWorktrees {
+				linked.RepoPath = collapseHomePath(linked.RepoPath)
+				linked.Path = collapseHomePath(linked.Path)
+				entry.LinkedWorktrees = append(entry.LinkedWorktrees, linked)
+			}
+			out.Worktrees = append(out.Worktrees, &entry)
+		}
+	}
+	return out, nil
+}
+
+// Import registers the contents of an export. Groups are matched by name and
+// workspaces by repo path; matches are reused rather than duplicated, so
+// importing the same export twice only adds what is new. Entries that cannot
+// be registered, e.g. because a path does not exist on this machine, are
+// skipped and reported. Environment variables read from a command and
+// notification script commands would run on this machine; instructions,
+// context files, additional directories, the session subpath and environment
+// variables read from a file or the daemon's environment would steer agents
+// or expose local paths to them. They are only imported when allowLocalData
+// is set and are dropped and reported otherwise.
+func (s *WorkspaceCatalogService) Import(ctx context.Context, in *types.WorkspaceExport, allowLocalData bool) (*types.WorkspaceImportResult, error) {
+	if in == nil {
+		return nil, invalidError("workspace export payload is required", nil)
+	}
+	if in.Version > types.WorkspaceExportVersion {
+		return nil, invalidError(fmt.Sprintf("unsupported workspace export version %d", in.Version), nil)
+	}
+	result := &types.WorkspaceImportResult{}
+
+	existingGroups, err := s.groups.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groupsByName := map[string]string{}
+	for _, group := range existingGroups {
+		if group != nil {
+			groupsByName[strings.ToLower(group.Name)] = group.ID
+		}
+	}
+	groupIDs := map[string]string{}
+	for _, group := range in.Groups {
+		if group == nil || strings.TrimSpace(group.Name) == "" {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(group.Name))
+		if id, ok := groupsByName[key]; ok {
+			groupIDs[group.ID] = id
+			continue
+		}
+		created, err := s.groups.Create(ctx, &types.WorkspaceGroup{Name: group.Name})
+		if err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("group %s: %v", group.Name, err))
+			continue
+		}
+		groupsByName[key] = created.ID
+		groupIDs[group.ID] = created.ID
+		result.Groups = append(result.Groups, created)
+	}
+
+	existingWorkspaces, err := s.workspaces.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	workspacesByPath := map[string]string{}
+	for _, ws := range existingWorkspaces {
+		if ws != nil {
+			workspacesByPath[canonicalScanPath(ws.RepoPath)] = ws.ID
+		}
+	}
+	workspaceIDs := map[string]string{}
+	for _, ws := range in.Workspaces {
+		if ws == nil {
+			continue
+		}
+		repoPath := expandHomePath(ws.RepoPath)
+		if id, ok := workspacesByPath[canonicalScanPath(repoPath)]; ok {
+			workspaceIDs[ws.ID] = id
+			result.Skipped = append(result.Skipped, fmt.Sprintf("workspace %s: %s is already registered", ws.Name, repoPath))
+			continue
+		}
+		req := *ws
+		req.ID = ""
+		req.RepoPath = repoPath
+		req.SessionSubpath, req.AdditionalDirectories = importWorkspacePaths("workspace "+ws.Name, ws, allowLocalData, result)
+		req.GroupIDs = nil
+		for _, id := range ws.GroupIDs {
+			if mapped, ok := groupIDs[id]; ok {
+				req.GroupIDs = append(req.GroupIDs, mapped)
+			}
+		}
+		req.EnvProfiles = importEnvProfiles("workspace "+ws.Name, ws.EnvProfiles, allowLocalData, result)
+		req.Instructions, req.ContextFiles = importWorkspaceInstructions("workspace "+ws.Name, ws, allowLocalData, result)
+		req.CreatedAt, req.UpdatedAt = time.Time{}, time.Time{}
+		created, err := s.workspaces.Create(ctx, &req)
+		if err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("workspace %s: %v", ws.Name, err))
+			continue
+		}
+		workspacesByPath[canonicalScanPath(created.RepoPath)] = created.ID
+		workspaceIDs[ws.ID] = created.ID
+		result.Workspaces = append(result.Workspaces, created)
+	}
+
+	registered := map[string]map[string]bool{}
+	for _, wt := range in.Worktrees {
+		if wt == nil {
+			continue
+		}
+		workspaceID, ok := workspaceIDs[wt.WorkspaceID]
+		if !ok {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("worktree %s: workspace was not imported", wt.Name))
+			continue
+		}
+		if registered[workspaceID] == nil {
+			registered[workspaceID] = map[string]bool{}
+			if worktrees, err := s.workspaces.ListWorktrees(ctx, workspaceID); err == nil {
+				for _, existing := range worktrees {
+					registered[workspaceID][canonicalScanPath(existing.Path)] = true
+				}
+			}
+		}
+		path := expandHomePath(wt.Path)
+		var linked []types.LinkedWorktree
+		for _, entry := range wt.LinkedWorktrees {
+			entry.RepoPath = expandHomePath(entry.RepoPath)
+			entry.Path = expandHomePath(entry.Path)
+			linked = append(linked, entry)
+		}
+		if registered[workspaceID][canonicalScanPath(path)] {
+			continue
+		}
+		created, err := s.workspaces.AddWorktree(ctx, workspaceID, &types.Worktree{
+			Name:                  wt.Name,
+			Path:                  path,
+			Branch:                wt.Branch,
+			NotificationOverrides: importNotificationOverrides("worktree "+wt.Name, wt.NotificationOverrides, allowLocalData, result),
+			EnvProfile:            wt.EnvProfile,
+			LinkedWorktrees:       linked,
+		})
+		if err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("worktree %s: %v", wt.Name, err))
+			continue
+		}
+		registered[workspaceID][canonicalScanPath(created.Path)] = true
+		result.Worktrees = append(result.Worktrees, created)
+	}
+	return result, nil
+}
+
+// exportRedactedWebhookPath replaces the path of exported webhook URLs.
+const exportRedactedWebhookPath = "/[redacted]"
+
+// importGateHint tells how to keep an entry dropped by the import gate.
+const importGateHint = "import with local data allowed to keep it"
+
+// exportNotificationOverrides drops webhook secrets and headers, which
+// commonly carry credentials, and redacts webhook URLs, which do for Slack
+// and Discord.
+func exportNotificationOverrides(in *types.NotificationSettingsPatch) *types.NotificationSettingsPatch {
+	out := types.CloneNotificationSettingsPatch(in)
+	if out == nil {
+		return nil
+	}
+	for i := range out.Webhooks {
+		out.Webhooks[i].URL = redactWebhookURL(out.Webhooks[i].URL) + exportRedactedWebhookPath
+		out.Webhooks[i].Secret = ""
+		out.Webhooks[i].Headers = nil
+	}
+	return out
+}
+
+func importEnvProfiles(owner string, profiles []types.EnvProfile, allowLocalData bool, result *types.WorkspaceImportResult) []types.EnvProfile {
+	out := types.CloneEnvProfiles(profiles)
+	for i := range out {
+		vars := out[i].Vars[:0]
+		for _, v := range out[i].Vars {
+			entry := fmt.Sprintf("%s env profile %s: %s", owner, out[i].Name, v.Spec())
+			switch v.Source {
+			case types.EnvSourceCommand:
+				if !allowLocalData {
+					result.Skipped = append(result.Skipped, entry+" runs a command; "+importGateHint)
+					continue
+				}
+				result.Commands = append(result.Commands, entry)
+			case types.EnvSourceFile, types.EnvSourceEnv:
+				if !allowLocalData {
+					result.Skipped = append(result.Skipped, entry+" reads local data; "+importGateHint)
+					continue
+				}
+				result.LocalReads = append(result.LocalReads, entry)
+			}
+			vars = append(vars, v)
+		}
+		out[i].Vars = vars
+	}
+	return out
+}
+
+// importWorkspaceInstructions gates the instructions and context files of an
+// imported workspace, which are injected into every session's prompt.
+func importWorkspaceInstructions(owner string, ws *types.Workspace, allowLocalData bool, result *types.WorkspaceImportResult) (string, []string) {
+	instructions := ws.Instructions
+	if strings.TrimSpace(instructions) != "" && !allowLocalData {
+		result.Skipped = append(result.Skipped, owner+" instructions are injected into agent prompts; "+importGateHint)
+		instructions = ""
+	}
+	var files []string
+	for _, file := range ws.ContextFiles {
+		entry := fmt.Sprintf("%s context file: %s", owner, file)
+		if !allowLocalData {
+			result.Skipped = append(result.Skipped, entry+" reads local data; "+importGateHint)
+			continue
+		}
+		result.LocalReads = append(result.LocalReads, entry)
+		files = append(files, file)
+	}
+	return instructions, files
+}
+
+// importWorkspacePaths gates the session subpath and additional directories
+// of an imported workspace, which agents and the sandbox are given access to.
+func importWorkspacePaths(owner string, ws *types.Workspace, allowLocalData bool, result *types.WorkspaceImportResult) (string, []string) {
+	subpath := ws.SessionSubpath
+	if strings.TrimSpace(subpath) != "" {
+		entry := fmt.Sprintf("%s session subpath: %s", owner, subpath)
+		if allowLocalData {
+			result.LocalReads = append(result.LocalReads, entry)
+		} else {
+			result.Skipped = append(result.Skipped, entry+" exposes a local path; "+importGateHint)
+			subpath = ""
+		}
+	}
+	var dirs []string
+	for _, dir := range ws.AdditionalDirectories {
+		entry := fmt.Sprintf("%s additional directory: %s", owner, dir)
+		if !allowLocalData {
+			result.Skipped = append(result.Skipped, entry+" exposes a local path; "+importGateHint)
+			continue
+		}
+		result.LocalReads = append(result.LocalReads, entry)
+		dirs = append(dirs, expandHomePath(dir))
+	}
+	return subpath, dirs
+}
+
+func importNotificationOverrides(owner string, in *types.NotificationSettingsPatch, allowLocalData bool, result *types.WorkspaceImportResult) *types.NotificationSettingsPatch {
+	out := types.CloneNotificationSettingsPatch(in)
+	if out == nil {
+		return nil
+	}
+	for _, command := range out.ScriptCommands {
+		entry := fmt.Sprintf("%s notification script: %s", owner, command)
+		if !allowLocalData {
+			result.Skipped = append(result.Skipped, entry+" runs a command; "+importGateHint)
+			continue
+		}
+		result.Commands = append(result.Commands, entry)
+	}
+	if !allowLocalData {
+		out.ScriptCommands = nil
+	}
+	webhooks := out.Webhooks[:0]
+	for _, webhook := range out.Webhooks {
+		if strings.HasSuffix(webhook.URL, exportRedactedWebhookPath) {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s webhook %s: the URL was redacted on export; add it again", owner, webhook.URL))
+			continue
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	out.Webhooks = webhooks
+	return out
+}
+
+func expandHomePath(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+func collapseHomePath(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" || home == string(filepath.Separator) {
+		return path
+	}
+	if path == home {
+		return "~"
+	}
+	if rest, ok := strings.CutPrefix(path, home+string(filepath.Separator)); ok {
+		return "~/" + filepath.ToSlash(rest)
+	}
+	return path
+}
@@ -0,0 +1,229 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"control/internal/providers"
+	"control/internal/types"
+)
+
+const (
+	maxContextFileBytes = 64 * 1024
+	// instructionsFileEnv names the variable that points file-strategy
+	// providers at the materialized instructions.
+	instructionsFileEnv  = "ARCHON_INSTRUCTIONS_FILE"
+	instructionsFileName = "instructions.md"
+)
+
+// SessionInstructionsResolver returns the standing instructions and context
+// files of a workspace as one text, with a record of what was included.
+type SessionInstructionsResolver interface {
+	ResolveSessionInstructions(ctx context.Context, workspaceID string) (string, *types.SessionInstructions, error)
+}
+
+type workspaceInstructionsResolver struct {
+	workspaces WorkspaceStore
+}
+
+func newWorkspaceInstructionsResolver(stores *Stores) SessionInstructionsResolver {
+	if stores == nil || stores.Workspaces == nil {
+		return nil
+	}
+	return &workspaceInstructionsResolver{workspaces: stores.Workspaces}
+}
+
+func (r *workspaceInstructionsResolver) ResolveSessionInstructions(ctx context.Context, workspaceID string) (string, *types.SessionInstructions, error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		return "", nil, nil
+	}
+	workspace, ok, err := r.workspaces.Get(ctx, workspaceID)
+	if err != nil || !ok || workspace == nil {
+		return "", nil, err
+	}
+	text, applied := composeWorkspaceInstructions(workspace)
+	return text, applied, nil
+}
+
+// composeWorkspaceInstructions joins the workspace instructions and the
+// contents of its context files. Relative context file paths are resolved
+// against the workspace repo path; files that cannot be read are recorded
+// as missing rather than failing the session.
+func composeWorkspaceInstructions(workspace *types.Workspace) (string, *types.SessionInstructions) {
+	applied := &types.SessionInstructions{}
+	var sections []string
+	if text := strings.TrimSpace(workspace.Instructions); text != "" {
+		applied.Instructions = true
+		sections = append(sections, text)
+	}
+	for _, file := range workspace.ContextFiles {
+		path := expandHomePath(file)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(workspace.RepoPath, path)
+		}
+		content, err := readContextFile(path)
+		if err != nil {
+			applied.Missing = append(applied.Missing, file)
+			continue
+		}
+		applied.ContextFiles = append(applied.ContextFiles, file)
+		sections = append(sections, fmt.Sprintf("Context file %s:\n\n%s", file, content))
+	}
+	if len(sections) == 0 {
+		if len(applied.Missing) == 0 {
+			return "", nil
+		}
+		return "", applied
+	}
+	return strings.Join(sections, "\n\n"), applied
+}
+
+func readContextFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxContextFileBytes+1))
+	if err != nil {
+		return "", err
+	}
+	content := strings.TrimSpace(string(data))
+	if len(data) > maxContextFileBytes {
+		content = strings.TrimSpace(string(data[:maxContextFileBytes])) + "\n[truncated]"
+	}
+	if content == "" {
+		return "", errors.New("context file is empty")
+	}
+	return content, nil
+}
+
+// instructionAdapter hands composed instructions to a provider. Apply updates
+// the start config and records how the instructions were delivered.
+type instructionAdapter interface {
+	Strategy() types.InstructionStrategy
+	Apply(cfg *StartSessionConfig, text, sessionDir string, applied *types.SessionInstructions) error
+}
+
+// instructionNativeAdapter leaves delivery to the provider, which passes
+// cfg.Instructions through its system prompt option.
+type instructionNativeAdapter struct{}
+
+func (instructionNativeAdapter) Strategy() types.InstructionStrategy {
+	return types.InstructionStrategyNativeFlag
+}
+
+func (instructionNativeAdapter) Apply(cfg *StartSessionConfig, text, _ string, _ *types.SessionInstructions) error {
+	cfg.Instructions = text
+	return nil
+}
+
+type instructionFileAdapter struct{}
+
+func (instructionFileAdapter) Strategy() types.InstructionStrategy {
+	return types.InstructionStrategyFile
+}
+
+func (instructionFileAdapter) Apply(cfg *StartSessionConfig, text, sessionDir string, applied *types.SessionInstructions) error {
+	if strings.TrimSpace(sessionDir) == "" {
+		return errors.New("session directory is required to write instructions")
+	}
+	if err := os.MkdirAll(sessionDir, 0o700); err != nil {
+		return err
+	}
+	path := filepath.Join(sessionDir, instructionsFileName)
+	if err := os.WriteFile(path, []byte(text+"\n"), 0o600); err != nil {
+		return err
+	}
+	cfg.Env = append(cfg.Env, instructionsFileEnv+"="+path)
+	cfg.InstructionsFile = path
+	applied.Path = path
+	return nil
+}
+
+// instructionPromptPrefixAdapter prepends the instructions to the initial
+// text when the provider sends it at start; otherwise the first message sent
+// to the session carries them.
+type instructionPromptPrefixAdapter struct{}
+
+func (instructionPromptPrefixAdapter) Strategy() types.InstructionStrategy {
+	return types.InstructionStrategyPromptPrefix
+}
+
+func (instructionPromptPrefixAdapter) Apply(cfg *StartSessionConfig, text, _ string, applied *types.SessionInstructions) error {
+	if strings.TrimSpace(cfg.InitialText) == "" {
+		applied.Pending = true
+		return nil
+	}
+	cfg.InitialText = prefixInstructions(text, cfg.InitialText)
+	return nil
+}
+
+func prefixInstructions(instructions, text string) string {
+	return "<workspace-instructions>\n" + instructions + "\n</workspace-instructions>\n\n" + text
+}
+
+var instructionAdapters = map[string]instructionAdapter{
+	"codex":  instructionNativeAdapter{},
+	"claude": instructionNativeAdapter{},
+}
+
+func instructionAdapterForProvider(provider string) instructionAdapter {
+	if adapter, ok := instructionAdapters[providers.Normalize(provider)]; ok {
+		return adapter
+	}
+	if def, ok := providers.Lookup(provider); ok {
+		switch def.Runtime {
+		case providers.RuntimeExec, providers.RuntimeCustom:
+			return instructionFileAdapter{}
+		}
+	}
+	return instructionPromptPrefixAdapter{}
+}
+
+// applyPendingInstructions prefixes the first text item of input with the
+// workspace instructions when the session is still waiting for them.
+func (s *SessionService) applyPendingInstructions(ctx context.Context, meta *types.SessionMeta, input []map[string]any) ([]map[string]any, bool) {
+	if meta == nil || meta.Instructions == nil || !meta.Instructions.Pending {
+		return input, false
+	}
+	resolver := newWorkspaceInstructionsResolver(s.stores)
+	if resolver == nil {
+		return input, false
+	}
+	text, _, err := resolver.ResolveSessionInstructions(ctx, meta.WorkspaceID)
+	if err != nil || text == "" {
+		return input, false
+	}
+	out := make([]map[string]any, len(input))
+	copy(out, input)
+	for i, item := range out {
+		if item["type"] != "text" {
+			continue
+		}
+		value, _ := item["text"].(string)
+		prefixed := make(map[string]any, len(item))
+		for key, v := range item {
+			prefixed[key] = v
+		}
+		prefixed["text"] = prefixInstructions(text, value)
+		out[i] = prefixed
+		return out, true
+	}
+	return input, false
+}
+
+func (s *SessionService) markInstructionsDelivered(ctx context.Context, sessionID string, applied *types.SessionInstructions) {
+	if s.stores == nil || s.stores.SessionMeta == nil || applied == nil {
+		return
+	}
+	delivered := types.CloneSessionInstructions(applied)
+	delivered.Pending = false
+	_, _ = s.stores.SessionMeta.Upsert(ctx, &types.SessionMeta{SessionID: sessionID, Instructions: delivered})
+}
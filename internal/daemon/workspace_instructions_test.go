@@ -0,0 +1,133 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"control/internal/store"
+	"control/internal/types"
+)
+
+func TestComposeWorkspaceInstructionsIncludesContextFiles(t *testing.T) {
+	repo := t.TempDir()
+	if err := ensureDir(filepath.Join(repo, "docs")); err != nil {
+		t.Fatalf("ensureDir: %v", err)
+	}
+	writeTestFile(t, filepath.Join(repo, "docs", "ARCH.md"), "# Architecture\n")
+	text, applied := composeWorkspaceInstructions(&types.Workspace{
+		RepoPath:     repo,
+		Instructions: "Run make test.",
+		ContextFiles: []string{"docs/ARCH.md", "MISSING.md"},
+	})
+	if text != "Run make test.\n\nContext file docs/ARCH.md:\n\n# Architecture" {
+		t.Fatalf("unexpected instructions %q", text)
+	}
+	if !applied.Instructions || len(applied.ContextFiles) != 1 || len(applied.Missing) != 1 || applied.Missing[0] != "MISSING.md" {
+		t.Fatalf("unexpected applied record %#v", applied)
+	}
+
+	if text, applied := composeWorkspaceInstructions(&types.Workspace{RepoPath: repo}); text != "" || applied != nil {
+		t.Fatalf("expected nothing for a workspace without instructions, got %q %#v", text, applied)
+	}
+}
+
+func TestInstructionAdapterForProviderStrategies(t *testing.T) {
+	cases := map[string]types.InstructionStrategy{
+		"claude":   types.InstructionStrategyNativeFlag,
+		"codex":    types.InstructionStrategyNativeFlag,
+		"gemini":   types.InstructionStrategyFile,
+		"custom":   types.InstructionStrategyFile,
+		"opencode": types.InstructionStrategyPromptPrefix,
+		"hermes":   types.InstructionStrategyPromptPrefix,
+	}
+	for provider, want := range cases {
+		if got := instructionAdapterForProvider(provider).Strategy(); got != want {
+			t.Fatalf("%s: expected %s, got %s", provider, want, got)
+		}
+	}
+}
+
+func TestSessionManagerMaterializesInstructionsForExecProviders(t *testing.T) {
+	ctx := context.Background()
+	base := t.TempDir()
+	workspaceStore := store.NewFileWorkspaceStore(filepath.Join(base, "workspaces.json"))
+	metaStore := store.NewFileSessionMetaStore(filepath.Join(base, "sessions_meta.json"))
+	repo := filepath.Join(base, "repo")
+	if err := ensureDir(repo); err != nil {
+		t.Fatalf("ensureDir: %v", err)
+	}
+	writeTestFile(t, filepath.Join(repo, "CONVENTIONS.md"), "Use tabs.\n")
+	ws, err := workspaceStore.Add(ctx, &types.Workspace{RepoPath: repo, Instructions: "Be brief.", ContextFiles: []string{"CONVENTIONS.md"}})
+	if err != nil {
+		t.Fatalf("Add workspace: %v", err)
+	}
+
+	manager := newTestManager(t)
+	manager.SetMetaStore(metaStore)
+	manager.SetInstructionsResolver(newWorkspaceInstructionsResolver(&Stores{Workspaces: workspaceStore}))
+	out := filepath.Join(base, "instructions.txt")
+	session, err := manager.StartSession(StartSessionConfig{
+		Provider:    "custom",
+		Cmd:         "sh",
+		Args:        []string{"-c", `cat "$ARCHON_INSTRUCTIONS_FILE" > "$0"`, out},
+		Cwd:         repo,
+		WorkspaceID: ws.ID,
+	})
+	if err != nil {
+		t.Fatalf("StartSession: %v", err)
+	}
+	waitForStatus(t, manager, session.ID, types.SessionStatusExited, 2*time.Second)
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read instructions: %v", err)
+	}
+	if got := string(data); !strings.HasPrefix(got, "Be brief.") || !strings.Contains(got, "Use tabs.") {
+		t.Fatalf("unexpected materialized instructions %q", got)
+	}
+	meta, ok, err := metaStore.Get(ctx, session.ID)
+	if err != nil || !ok || meta.Instructions == nil {
+		t.Fatalf("expected applied instructions in meta, got %#v (%v)", meta, err)
+	}
+	if meta.Instructions.Strategy != types.InstructionStrategyFile || meta.Instructions.Path == "" || meta.Instructions.ContextFiles[0] != "CONVENTIONS.md" {
+		t.Fatalf("unexpected applied instructions %#v", meta.Instructions)
+	}
+}
+
+func TestApplyPendingInstructionsPrefixesFirstMessage(t *testing.T) {
+	ctx := context.Background()
+	stores := newTestStores(t)
+	repo := t.TempDir()
+	ws, err := stores.Workspaces.Add(ctx, &types.Workspace{RepoPath: repo, Instructions: "Be brief."})
+	if err != nil {
+		t.Fatalf("Add workspace: %v", err)
+	}
+	service := &SessionService{stores: stores}
+	input := []map[string]any{{"type": "text", "text": "Fix the bug"}}
+
+	out, prefixed := service.applyPendingInstructions(ctx, &types.SessionMeta{WorkspaceID: ws.ID, Instructions: &types.SessionInstructions{}}, input)
+	if prefixed || out[0]["text"] != "Fix the bug" {
+		t.Fatalf("expected delivered instructions to be left alone, got %#v", out)
+	}
+
+	meta := &types.SessionMeta{SessionID: "s1", WorkspaceID: ws.ID, Instructions: &types.SessionInstructions{
+		Strategy: types.InstructionStrategyPromptPrefix, Instructions: true, Pending: true,
+	}}
+	out, prefixed = service.applyPendingInstructions(ctx, meta, input)
+	if !prefixed || out[0]["text"] != prefixInstructions("Be brief.", "Fix the bug") {
+		t.Fatalf("expected prefixed first message, got %#v", out)
+	}
+	if input[0]["text"] != "Fix the bug" {
+		t.Fatalf("expected caller input to be left unmodified")
+	}
+
+	service.markInstructionsDelivered(ctx, "s1", meta.Instructions)
+	stored, ok, err := stores.SessionMeta.Get(ctx, "s1")
+	if err != nil || !ok || stored.Instructions == nil || stored.Instructions.Pending {
+		t.Fatalf("expected instructions to be marked delivered, got %#v (%v)", stored, err)
+	}
+}
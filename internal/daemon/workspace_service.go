@@ -136,6 +136,12 @@ func applyWorkspacePatch(id string, existing *types.Workspace, req *types.Worksp
 		SessionSubpath:        existing.SessionSubpath,
 		AdditionalDirectories: append([]string(nil), existing.AdditionalDirectories...),
 		GroupIDs:              append([]string(nil), existing.GroupIDs...),
+		IsolateSessions:       existing.IsolateSessions,
+		CleanupIsolated:       existing.CleanupIsolated,
+		EnvProfiles:           types.CloneEnvProfiles(existing.EnvProfiles),
+		EnvProfile:            existing.EnvProfile,
+		Instructions:          existing.Instructions,
+		ContextFiles:          append([]string(nil), existing.ContextFiles...),
 	}
 	merged.RepoPath = resolveWorkspacePatchRepoPath(existing.RepoPath, req.RepoPath)
 	merged.Name = resolveWorkspacePatchName(existing.Name, merged.RepoPath, req.Name)
@@ -149,6 +155,27 @@ func applyWorkspacePatch(id string, existing *types.Workspace, req *types.Worksp
 	if req.GroupIDs != nil {
 		merged.GroupIDs = append([]string(nil), (*req.GroupIDs)...)
 	}
+	if req.IsolateSessions != nil {
+		merged.IsolateSessions = *req.IsolateSessions
+	}
+	if req.CleanupIsolated != nil {
+		merged.CleanupIsolated = *req.CleanupIsolated
+	}
+	if req.EnvProfiles != nil {
+		merged.EnvProfiles = types.CloneEnvProfiles(*req.EnvProfiles)
+	}
+	if req.EnvProfile != nil {
+		merged.EnvProfile = strings.TrimSpace(*req.EnvProfile)
+	}
+	if req.Instructions != nil {
+		merged.Instructions = strings.TrimSpace(*req.Instructions)
+	}
+	if req.ContextFiles != nil {
+		merged.ContextFiles = types.NormalizeContextFiles(*req.ContextFiles)
+	}
+	if err := types.ValidateEnvProfiles(merged.EnvProfiles, merged.EnvProfile); err != nil {
+		return nil, false, err
+	}
 	return merged, shouldValidate, nil
 }
 
@@ -277,7 +304,11 @@ func (s *WorkspaceService) UpdateWorktree(ctx context.Context, workspaceID, work
 		ID:                    worktreeID,
 		Name:                  name,
 		Path:                  path,
+		Branch:                existing.Branch,
+		Isolated:              existing.Isolated,
 		NotificationOverrides: mergeWorktreeNotificationOverrides(existing.NotificationOverrides, req.NotificationOverrides),
+		EnvProfile:            mergeWorktreeEnvProfile(existing.EnvProfile, req.EnvProfile),
+		LinkedWorktrees:       existing.LinkedWorktrees,
 	})
 	if err != nil {
 		if errors.Is(err, store.ErrWorkspaceNotFound) {
@@ -298,6 +329,19 @@ func mergeWorktreeNotificationOverrides(existing, incoming *types.NotificationSe
 	return types.CloneNotificationSettingsPatch(incoming)
 }
 
+// mergeWorktreeEnvProfile keeps the existing selection when incoming is
+// empty; "-" clears it so the workspace's profile applies again.
+func mergeWorktreeEnvProfile(existing, incoming string) string {
+	switch incoming = strings.TrimSpace(incoming); incoming {
+	case "":
+		return existing
+	case "-":
+		return ""
+	default:
+		return incoming
+	}
+}
+
 func (s *WorkspaceService) DeleteWorktree(ctx context.Context, workspaceID, worktreeID string) error {
 	if s.worktrees == nil {
 		return unavailableError("worktree store not available", nil)
@@ -366,13 +410,30 @@ func (s *WorkspaceService) CreateWorktree(ctx context.Context, workspaceID strin
 	if err != nil {
 		return nil, invalidError(err.Error(), err)
 	}
-	if err := createGitWorktree(ws.RepoPath, path, req.Branch); err != nil {
+	if err := createGitWorktree(ctx, ws.RepoPath, path, req.Branch); err != nil {
 		return nil, invalidError(err.Error(), err)
 	}
-	return s.AddWorktree(ctx, workspaceID, &types.Worktree{
-		Name: strings.TrimSpace(req.Name),
-		Path: path,
+	var linked []types.LinkedWorktree
+	if repos := workspaceLinkedRepos(ctx, ws); len(repos) > 0 {
+		branch, err := gitCurrentBranch(ctx, path)
+		if err == nil {
+			linked, err = createLinkedWorktrees(ctx, repos, path, branch)
+		}
+		if err != nil {
+			_ = removeGitWorktree(ctx, ws.RepoPath, path, true)
+			return nil, invalidError(err.Error(), err)
+		}
+	}
+	wt, err := s.AddWorktree(ctx, workspaceID, &types.Worktree{
+		Name:            strings.TrimSpace(req.Name),
+		Path:            path,
+		LinkedWorktrees: linked,
 	})
+	if err != nil {
+		discardLinkedWorktrees(ctx, linked, false)
+		return nil, err
+	}
+	return wt, nil
 }
 
 func validateWorkspacePath(path string) error {
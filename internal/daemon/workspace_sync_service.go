@@ -83,6 +83,26 @@ func (s *WorkspaceSyncService) CreateWorktree(ctx context.Context, workspaceID s
 	return wt, nil
 }
 
+func (s *WorkspaceSyncService) RemoveWorktree(ctx context.Context, workspaceID, worktreeID string, req types.WorktreeOperationRequest) (*types.WorktreeOperationResult, error) {
+	return s.base.RemoveWorktree(ctx, workspaceID, worktreeID, req)
+}
+
+func (s *WorkspaceSyncService) PruneWorktrees(ctx context.Context, workspaceID string) (*types.WorktreeOperationResult, error) {
+	return s.base.PruneWorktrees(ctx, workspaceID)
+}
+
+func (s *WorkspaceSyncService) MergeWorktree(ctx context.Context, workspaceID, worktreeID string, req types.WorktreeOperationRequest) (*types.WorktreeOperationResult, error) {
+	return s.base.MergeWorktree(ctx, workspaceID, worktreeID, req)
+}
+
+func (s *WorkspaceSyncService) RebaseWorktree(ctx context.Context, workspaceID, worktreeID string, req types.WorktreeOperationRequest) (*types.WorktreeOperationResult, error) {
+	return s.base.RebaseWorktree(ctx, workspaceID, worktreeID, req)
+}
+
+func (s *WorkspaceSyncService) PushWorktree(ctx context.Context, workspaceID, worktreeID string, req types.WorktreeOperationRequest) (*types.WorktreeOperationResult, error) {
+	return s.base.PushWorktree(ctx, workspaceID, worktreeID, req)
+}
+
 func (s *WorkspaceSyncService) syncWorkspace(ws *types.Workspace) {
 	if ws == nil || ws.ID == "" || s.syncer == nil {
 		return
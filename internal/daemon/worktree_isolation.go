@@ -0,0 +1,298 @@
+package daemon
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"path/filepath"
+	"strings"
+
+	"control/internal/guidedworkflows"
+	"control/internal/logging"
+	"control/internal/store"
+	"control/internal/types"
+)
+
+const (
+	isolatedWorktreeBranchPrefix = "archon/"
+	isolatedWorktreeDirSuffix    = "-worktrees"
+	isolatedWorktreeSlugMaxLen   = 40
+)
+
+// ProvisionIsolatedWorktree creates a fresh git worktree and branch for a
+// new session or workflow run when the workspace isolates sessions. It
+// returns nil without error when isolation is disabled. Worktrees live next
+// to the repository in "<repo>-worktrees/<slug>" on branch "archon/<slug>",
+// where slug is derived from label plus a random suffix.
+func (s *WorkspaceService) ProvisionIsolatedWorktree(ctx context.Context, workspaceID, label string) (*types.Worktree, error) {
+	if s.workspaces == nil || s.worktrees == nil {
+		return nil, nil
+	}
+	if strings.TrimSpace(workspaceID) == "" {
+		return nil, nil
+	}
+	ws, ok, err := s.workspaces.Get(ctx, workspaceID)
+	if err != nil {
+		return nil, unavailableError(err.Error(), err)
+	}
+	if !ok {
+		return nil, notFoundError("workspace not found", store.ErrWorkspaceNotFound)
+	}
+	if !ws.IsolateSessions {
+		return nil, nil
+	}
+	slug, err := isolatedWorktreeSlug(label)
+	if err != nil {
+		return nil, unavailableError(err.Error(), err)
+	}
+	repo := filepath.Clean(ws.RepoPath)
+	path := filepath.Join(filepath.Dir(repo), filepath.Base(repo)+isolatedWorktreeDirSuffix, slug)
+	branch := isolatedWorktreeBranchPrefix + slug
+	if err := createGitWorktreeBranch(ctx, ws.RepoPath, path, branch); err != nil {
+		return nil, invalidError(err.Error(), err)
+	}
+	linked, err := createLinkedWorktrees(ctx, workspaceLinkedRepos(ctx, ws), path, branch)
+	if err != nil {
+		s.discardIsolatedWorktree(ctx, ws.RepoPath, path, branch)
+		return nil, invalidError(err.Error(), err)
+	}
+	wt, err := s.worktrees.AddWorktree(ctx, workspaceID, &types.Worktree{
+		Name:            slug,
+		Path:            path,
+		Branch:          branch,
+		Isolated:        true,
+		LinkedWorktrees: linked,
+	})
+	if err != nil {
+		s.discardIsolatedWorktree(ctx, ws.RepoPath, path, branch)
+		discardLinkedWorktrees(ctx, linked, true)
+		return nil, invalidError(err.Error(), err)
+	}
+	return wt, nil
+}
+
+// ReleaseIsolatedWorktree removes an isolated worktree that never got used,
+// e.g. because the session it was provisioned for failed to start.
+func (s *WorkspaceService) ReleaseIsolatedWorktree(ctx context.Context, workspaceID string, wt *types.Worktree) {
+	if wt == nil || !wt.Isolated || s.workspaces == nil {
+		return
+	}
+	ws, ok, err := s.workspaces.Get(ctx, workspaceID)
+	if err != nil || !ok {
+		return
+	}
+	s.discardIsolatedWorktree(ctx, ws.RepoPath, wt.Path, wt.Branch)
+	discardLinkedWorktrees(ctx, wt.LinkedWorktrees, true)
+	_ = s.DeleteWorktree(ctx, workspaceID, wt.ID)
+}
+
+func (s *WorkspaceService) discardIsolatedWorktree(ctx context.Context, repoPath, path, branch string) {
+	_ = removeGitWorktree(ctx, repoPath, path, true)
+	if branch != "" {
+		_ = deleteGitBranch(ctx, repoPath, branch, true)
+	}
+}
+
+// CleanupIsolatedWorktree removes an isolated worktree and its branch once
+// the branch has been merged into the workspace's main branch, provided the
+// workspace opted into cleanup and the worktree has no uncommitted changes.
+// It reports whether the worktree was removed.
+func (s *WorkspaceService) CleanupIsolatedWorktree(ctx context.Context, workspaceID, worktreeID string) (bool, error) {
+	if strings.TrimSpace(workspaceID) == "" || strings.TrimSpace(worktreeID) == "" {
+		return false, nil
+	}
+	ws, wt, err := s.worktreeForOperation(ctx, workspaceID, worktreeID)
+	if err != nil {
+		return false, err
+	}
+	if !ws.CleanupIsolated || !wt.Isolated || wt.Branch == "" {
+		return false, nil
+	}
+	target, err := gitCurrentBranch(ctx, ws.RepoPath)
+	if err != nil || target == wt.Branch {
+		return false, nil
+	}
+	if !gitBranchMerged(ctx, ws.RepoPath, wt.Branch, target) {
+		return false, nil
+	}
+	dirty, err := gitDirtyFiles(ctx, wt.Path)
+	if err != nil || len(dirty) > 0 {
+		return false, nil
+	}
+	for _, linked := range wt.LinkedWorktrees {
+		linkedTarget, err := gitCurrentBranch(ctx, linked.RepoPath)
+		if err != nil || linkedTarget == linked.Branch || !gitBranchMerged(ctx, linked.RepoPath, linked.Branch, linkedTarget) {
+			return false, nil
+		}
+		if dirty, err := gitDirtyFiles(ctx, linked.Path); err != nil || len(dirty) > 0 {
+			return false, nil
+		}
+	}
+	if err := removeGitWorktree(ctx, ws.RepoPath, wt.Path, false); err != nil {
+		return false, invalidError(err.Error(), err)
+	}
+	if err := deleteGitBranch(ctx, ws.RepoPath, wt.Branch, false); err != nil {
+		return false, invalidError(err.Error(), err)
+	}
+	for _, linked := range wt.LinkedWorktrees {
+		if err := removeGitWorktree(ctx, linked.RepoPath, linked.Path, false); err != nil {
+			return false, invalidError(err.Error(), err)
+		}
+		if err := deleteGitBranch(ctx, linked.RepoPath, linked.Branch, false); err != nil {
+			return false, invalidError(err.Error(), err)
+		}
+	}
+	if err := s.DeleteWorktree(ctx, workspaceID, worktreeID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func isolatedWorktreeSlug(label string) (string, error) {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(label)) {
+		switch {
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			b.WriteRune(r)
+			dash = false
+		case b.Len() > 0 && !dash:
+			b.WriteByte('-')
+			dash = true
+		}
+		if b.Len() >= isolatedWorktreeSlugMaxLen {
+			break
+		}
+	}
+	base := strings.Trim(b.String(), "-")
+	if base == "" {
+		base = "session"
+	}
+	buf := make([]byte, 3)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base + "-" + hex.EncodeToString(buf), nil
+}
+
+func (s *SessionService) workspaceService() *WorkspaceService {
+	return NewWorkspaceServiceWithPathResolver(s.stores, s.paths)
+}
+
+// cleanupIsolatedSessionWorktree removes the isolated worktree of a
+// dismissed session when CleanupIsolatedWorktree allows it and no other
+// session still uses it. Failures are logged; dismissal itself already
+// succeeded.
+func (s *SessionService) cleanupIsolatedSessionWorktree(ctx context.Context, sessionID string) {
+	if s == nil || s.stores == nil {
+		return
+	}
+	meta := s.getSessionMeta(ctx, sessionID)
+	if meta == nil || strings.TrimSpace(meta.WorktreeID) == "" {
+		return
+	}
+	if others := s.sessionsUsingWorktree(ctx, sessionID, meta.WorkspaceID, meta.WorktreeID); len(others) > 0 {
+		if s.logger != nil {
+			s.logger.Info("isolated_worktree_cleanup_skipped",
+				logging.F("session_id", sessionID),
+				logging.F("worktree_id", meta.WorktreeID),
+				logging.F("used_by", strings.Join(others, ",")),
+			)
+		}
+		return
+	}
+	removed, err := s.workspaceService().CleanupIsolatedWorktree(ctx, meta.WorkspaceID, meta.WorktreeID)
+	if err != nil {
+		if s.logger != nil {
+			s.logger.Warn("isolated_worktree_cleanup_failed",
+				logging.F("session_id", sessionID),
+				logging.F("worktree_id", meta.WorktreeID),
+				logging.F("error", err),
+			)
+		}
+		return
+	}
+	if removed && s.logger != nil {
+		s.logger.Info("isolated_worktree_removed",
+			logging.F("session_id", sessionID),
+			logging.F("worktree_id", meta.WorktreeID),
+		)
+	}
+}
+
+// sessionsUsingWorktree returns the ids of sessions other than sessionID
+// that have not been dismissed and are bound to the worktree or run inside
+// its directory.
+func (s *SessionService) sessionsUsingWorktree(ctx context.Context, sessionID, workspaceID, worktreeID string) []string {
+	path, _, err := s.resolveWorktreePath(ctx, workspaceID, worktreeID)
+	if err != nil {
+		path = ""
+	}
+	sessions, metas, err := s.ListWithMetaIncludingWorkflowOwned(ctx)
+	if err != nil {
+		return nil
+	}
+	worktreeBySession := make(map[string]string, len(metas))
+	for _, meta := range metas {
+		if meta != nil {
+			worktreeBySession[meta.SessionID] = strings.TrimSpace(meta.WorktreeID)
+		}
+	}
+	var ids []string
+	for _, session := range sessions {
+		if session == nil || session.ID == sessionID {
+			continue
+		}
+		if worktreeBySession[session.ID] == worktreeID ||
+			(path != "" && pathMatchesWorkspace(canonicalScanPath(session.Cwd), canonicalScanPath(path))) {
+			ids = append(ids, session.ID)
+		}
+	}
+	return ids
+}
+
+// provisionIsolatedWorkflowWorktree gives a new workflow run its own
+// worktree when the run targets a workspace (not an existing worktree or
+// session) that isolates sessions. req.WorktreeID is updated in place.
+func (a *API) provisionIsolatedWorkflowWorktree(ctx context.Context, req *CreateWorkflowRunRequest) (*types.Worktree, error) {
+	if a == nil || a.Stores == nil || req == nil {
+		return nil, nil
+	}
+	if strings.TrimSpace(req.WorktreeID) != "" || strings.TrimSpace(req.SessionID) != "" {
+		return nil, nil
+	}
+	label := req.UserPrompt
+	if strings.TrimSpace(label) == "" {
+		label = req.TemplateID
+	}
+	wt, err := NewWorkspaceService(a.Stores).ProvisionIsolatedWorktree(ctx, strings.TrimSpace(req.WorkspaceID), label)
+	if err != nil || wt == nil {
+		return nil, err
+	}
+	req.WorktreeID = wt.ID
+	return wt, nil
+}
+
+func (a *API) cleanupIsolatedWorkflowWorktree(ctx context.Context, run *guidedworkflows.WorkflowRun) {
+	if a == nil || a.Stores == nil || run == nil {
+		return
+	}
+	removed, err := NewWorkspaceService(a.Stores).CleanupIsolatedWorktree(ctx, run.WorkspaceID, run.WorktreeID)
+	if a.Logger == nil {
+		return
+	}
+	if err != nil {
+		a.Logger.Warn("isolated_worktree_cleanup_failed",
+			logging.F("run_id", run.ID),
+			logging.F("worktree_id", run.WorktreeID),
+			logging.F("error", err),
+		)
+		return
+	}
+	if removed {
+		a.Logger.Info("isolated_worktree_removed",
+			logging.F("run_id", run.ID),
+			logging.F("worktree_id", run.WorktreeID),
+		)
+	}
+}
@@ -0,0 +1,181 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"control/internal/store"
+	"control/internal/types"
+)
+
+func TestProvisionIsolatedWorktreeRequiresWorkspaceSetting(t *testing.T) {
+	service, ws, _, _ := newWorktreeLifecycleFixture(t)
+
+	wt, err := service.ProvisionIsolatedWorktree(context.Background(), ws.ID, "Fix the login bug")
+	if err != nil {
+		t.Fatalf("provision: %v", err)
+	}
+	if wt != nil {
+		t.Fatalf("expected no worktree while isolation is disabled, got %#v", wt)
+	}
+}
+
+func TestIsolatedWorktreeProvisionAndCleanupAfterMerge(t *testing.T) {
+	service, ws, _, repoDir := newWorktreeLifecycleFixture(t)
+	ctx := context.Background()
+	enabled := true
+	if _, err := service.Update(ctx, ws.ID, &types.WorkspacePatch{IsolateSessions: &enabled, CleanupIsolated: &enabled}); err != nil {
+		t.Fatalf("enable isolation: %v", err)
+	}
+
+	wt, err := service.ProvisionIsolatedWorktree(ctx, ws.ID, "Fix the login bug!")
+	if err != nil {
+		t.Fatalf("provision: %v", err)
+	}
+	if wt == nil || !wt.Isolated {
+		t.Fatalf("expected isolated worktree, got %#v", wt)
+	}
+	if !strings.HasPrefix(wt.Name, "fix-the-login-bug-") || wt.Branch != "archon/"+wt.Name {
+		t.Fatalf("unexpected worktree name/branch: %q %q", wt.Name, wt.Branch)
+	}
+	if want := filepath.Join(filepath.Dir(repoDir), "repo-worktrees", wt.Name); wt.Path != want {
+		t.Fatalf("expected worktree at %q, got %q", want, wt.Path)
+	}
+	if branch, err := gitCurrentBranch(context.Background(), wt.Path); err != nil || branch != wt.Branch {
+		t.Fatalf("expected worktree on %q, got %q (%v)", wt.Branch, branch, err)
+	}
+
+	writeTestFile(t, filepath.Join(wt.Path, "fix.txt"), "fixed\n")
+	runTestGit(t, wt.Path, "add", "fix.txt")
+	runTestGit(t, wt.Path, "commit", "-q", "-m", "fix")
+	removed, err := service.CleanupIsolatedWorktree(ctx, ws.ID, wt.ID)
+	if err != nil || removed {
+		t.Fatalf("expected unmerged worktree to be kept, removed=%v err=%v", removed, err)
+	}
+
+	runTestGit(t, repoDir, "merge", "-q", wt.Branch)
+	removed, err = service.CleanupIsolatedWorktree(ctx, ws.ID, wt.ID)
+	if err != nil || !removed {
+		t.Fatalf("expected merged worktree to be removed, removed=%v err=%v", removed, err)
+	}
+	if _, err := os.Stat(wt.Path); !os.IsNotExist(err) {
+		t.Fatalf("expected worktree directory to be removed, got %v", err)
+	}
+	worktrees, err := service.ListWorktrees(ctx, ws.ID)
+	if err != nil {
+		t.Fatalf("list worktrees: %v", err)
+	}
+	for _, candidate := range worktrees {
+		if candidate.ID == wt.ID {
+			t.Fatalf("expected isolated worktree record to be forgotten")
+		}
+	}
+}
+
+func TestCleanupIsolatedWorktreeIgnoresManualWorktrees(t *testing.T) {
+	service, ws, wt, repoDir := newWorktreeLifecycleFixture(t)
+	ctx := context.Background()
+	enabled := true
+	if _, err := service.Update(ctx, ws.ID, &types.WorkspacePatch{CleanupIsolated: &enabled}); err != nil {
+		t.Fatalf("enable cleanup: %v", err)
+	}
+	runTestGit(t, repoDir, "merge", "-q", "feature")
+
+	removed, err := service.CleanupIsolatedWorktree(ctx, ws.ID, wt.ID)
+	if err != nil || removed {
+		t.Fatalf("expected manually added worktree to be kept, removed=%v err=%v", removed, err)
+	}
+}
+
+func TestDismissKeepsIsolatedWorktreeUsedByAnotherSession(t *testing.T) {
+	service, ws, _, _ := newWorktreeLifecycleFixture(t)
+	ctx := context.Background()
+	enabled := true
+	if _, err := service.Update(ctx, ws.ID, &types.WorkspacePatch{IsolateSessions: &enabled, CleanupIsolated: &enabled}); err != nil {
+		t.Fatalf("enable isolation: %v", err)
+	}
+	wt, err := service.ProvisionIsolatedWorktree(ctx, ws.ID, "Shared worktree")
+	if err != nil || wt == nil {
+		t.Fatalf("provision: %#v %v", wt, err)
+	}
+	base := t.TempDir()
+	stores := &Stores{
+		Workspaces:  service.workspaces,
+		Worktrees:   service.worktrees,
+		Sessions:    store.NewFileSessionIndexStore(filepath.Join(base, "sessions_index.json")),
+		SessionMeta: store.NewFileSessionMetaStore(filepath.Join(base, "sessions_meta.json")),
+	}
+	for _, id := range []string{"s1", "s2"} {
+		if _, err := stores.Sessions.UpsertRecord(ctx, &types.SessionRecord{
+			Session: &types.Session{ID: id, Provider: "claude", Status: types.SessionStatusInactive, Cwd: wt.Path, CreatedAt: time.Now().UTC()},
+			Source:  sessionSourceInternal,
+		}); err != nil {
+			t.Fatalf("seed session %s: %v", id, err)
+		}
+		if _, err := stores.SessionMeta.Upsert(ctx, &types.SessionMeta{SessionID: id, WorkspaceID: ws.ID, WorktreeID: wt.ID}); err != nil {
+			t.Fatalf("seed meta %s: %v", id, err)
+		}
+	}
+	sessions := NewSessionService(nil, stores, nil)
+
+	if err := sessions.Dismiss(ctx, "s1"); err != nil {
+		t.Fatalf("dismiss s1: %v", err)
+	}
+	if _, err := os.Stat(wt.Path); err != nil {
+		t.Fatalf("expected the worktree to stay while s2 uses it: %v", err)
+	}
+	if err := sessions.Dismiss(ctx, "s2"); err != nil {
+		t.Fatalf("dismiss s2: %v", err)
+	}
+	if _, err := os.Stat(wt.Path); !os.IsNotExist(err) {
+		t.Fatalf("expected the worktree to be removed with its last session, got %v", err)
+	}
+}
+
+func TestSessionStartProvisionsIsolatedWorktree(t *testing.T) {
+	service, ws, _, _ := newWorktreeLifecycleFixture(t)
+	ctx := context.Background()
+	enabled := true
+	if _, err := service.Update(ctx, ws.ID, &types.WorkspacePatch{IsolateSessions: &enabled}); err != nil {
+		t.Fatalf("enable isolation: %v", err)
+	}
+	manager := newTestManager(t)
+	sessions := NewSessionService(manager, &Stores{
+		Workspaces: service.workspaces,
+		Worktrees:  service.worktrees,
+	}, nil)
+
+	session, err := sessions.Start(ctx, StartSessionRequest{
+		Provider:    "custom",
+		Cmd:         os.Args[0],
+		Args:        helperArgs("stdout=ok", "exit=0"),
+		Env:         []string{"GO_WANT_HELPER_PROCESS=1"},
+		Title:       "Refactor parser",
+		WorkspaceID: ws.ID,
+	})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	waitForStatus(t, manager, session.ID, types.SessionStatusExited, 2*time.Second)
+
+	worktrees, err := service.ListWorktrees(ctx, ws.ID)
+	if err != nil {
+		t.Fatalf("list worktrees: %v", err)
+	}
+	var isolated *types.Worktree
+	for _, wt := range worktrees {
+		if wt.Isolated {
+			isolated = wt
+		}
+	}
+	if isolated == nil || !strings.HasPrefix(isolated.Name, "refactor-parser-") {
+		t.Fatalf("expected isolated worktree named from the title, got %#v", worktrees)
+	}
+	if session.Cwd != isolated.Path {
+		t.Fatalf("expected session cwd %q, got %q", isolated.Path, session.Cwd)
+	}
+}
@@ -0,0 +1,446 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+
+	"control/internal/store"
+	"control/internal/types"
+)
+
+const defaultWorktreePushRemote = "origin"
+
+func (s *WorkspaceService) worktreeForOperation(ctx context.Context, workspaceID, worktreeID string) (*types.Workspace, *types.Worktree, error) {
+	if s.workspaces == nil || s.worktrees == nil {
+		return nil, nil, unavailableError("workspace store not available", nil)
+	}
+	if strings.TrimSpace(workspaceID) == "" {
+		return nil, nil, invalidError("workspace id is required", nil)
+	}
+	if strings.TrimSpace(worktreeID) == "" {
+		return nil, nil, invalidError("worktree id is required", nil)
+	}
+	ws, ok, err := s.workspaces.Get(ctx, workspaceID)
+	if err != nil {
+		return nil, nil, unavailableError(err.Error(), err)
+	}
+	if !ok {
+		return nil, nil, notFoundError("workspace not found", store.ErrWorkspaceNotFound)
+	}
+	worktrees, err := s.worktrees.ListWorktrees(ctx, workspaceID)
+	if err != nil {
+		return nil, nil, unavailableError(err.Error(), err)
+	}
+	for _, wt := range worktrees {
+		if wt != nil && wt.ID == worktreeID {
+			return ws, wt, nil
+		}
+	}
+	return nil, nil, notFoundError("worktree not found", store.ErrWorktreeNotFound)
+}
+
+func newWorktreeOperationResult(op types.WorktreeOperation, ws *types.Workspace, wt *types.Worktree) *types.WorktreeOperationResult {
+	result := &types.WorktreeOperationResult{Operation: op, Status: types.WorktreeOperationOK}
+	if ws != nil {
+		result.WorkspaceID = ws.ID
+	}
+	if wt != nil {
+		result.WorktreeID = wt.ID
+		result.Path = wt.Path
+	}
+	return result
+}
+
+// RemoveWorktree deletes the worktree's checkout with `git worktree remove`
+// and forgets it, together with its linked worktrees. Worktrees with
+// uncommitted changes in any repo of the set are left alone unless req.Force
+// is set. A linked worktree that cannot be removed once the primary one is
+// gone is reported on its linked result.
+func (s *WorkspaceService) RemoveWorktree(ctx context.Context, workspaceID, worktreeID string, req types.WorktreeOperationRequest) (*types.WorktreeOperationResult, error) {
+	ws, wt, err := s.worktreeForOperation(ctx, workspaceID, worktreeID)
+	if err != nil {
+		return nil, err
+	}
+	result := newWorktreeOperationResult(types.WorktreeOperationRemove, ws, wt)
+	if !req.Force {
+		var dirty []string
+		if _, statErr := os.Stat(wt.Path); statErr == nil {
+			if dirty, err = gitDirtyFiles(ctx, wt.Path); err != nil {
+				return nil, invalidError(err.Error(), err)
+			}
+		}
+		linkedDirty, err := linkedDirtyFiles(ctx, wt.LinkedWorktrees)
+		if err != nil {
+			return nil, invalidError(err.Error(), err)
+		}
+		if dirty = append(dirty, linkedDirty...); len(dirty) > 0 {
+			result.Status = types.WorktreeOperationDirty
+			result.DirtyFiles = dirty
+			return result, nil
+		}
+	}
+	if err := removeOrPruneGitWorktree(ctx, ws.RepoPath, wt.Path, req.Force); err != nil {
+		return nil, invalidError(err.Error(), err)
+	}
+	for _, linked := range wt.LinkedWorktrees {
+		linkedResult := newLinkedOperationResult(result, linked)
+		if err := removeOrPruneGitWorktree(ctx, linked.RepoPath, linked.Path, req.Force); err != nil {
+			failLinkedOperation(linkedResult, err)
+		}
+	}
+	// The primary checkout is gone, so the record is forgotten even when a
+	// linked worktree failed; its result keeps the path to clean up.
+	if err := s.DeleteWorktree(ctx, workspaceID, worktreeID); err != nil {
+		return nil, err
+	}
+	rollUpLinkedStatus(result, wt.LinkedWorktrees)
+	return result, nil
+}
+
+func removeOrPruneGitWorktree(ctx context.Context, repoPath, path string, force bool) error {
+	if _, err := os.Stat(path); err == nil {
+		return removeGitWorktree(ctx, repoPath, path, force)
+	}
+	_, err := pruneGitWorktrees(ctx, repoPath)
+	return err
+}
+
+// PruneWorktrees prunes stale git worktree entries of the workspace's
+// repository and its linked repos and forgets worktrees whose directories no longer exist.
+func (s *WorkspaceService) PruneWorktrees(ctx context.Context, workspaceID string) (*types.WorktreeOperationResult, error) {
+	if s.workspaces == nil || s.worktrees == nil {
+		return nil, unavailableError("workspace store not available", nil)
+	}
+	if strings.TrimSpace(workspaceID) == "" {
+		return nil, invalidError("workspace id is required", nil)
+	}
+	ws, ok, err := s.workspaces.Get(ctx, workspaceID)
+	if err != nil {
+		return nil, unavailableError(err.Error(), err)
+	}
+	if !ok {
+		return nil, notFoundError("workspace not found", store.ErrWorkspaceNotFound)
+	}
+	result := newWorktreeOperationResult(types.WorktreeOperationPrune, ws, nil)
+	pruned, err := pruneGitWorktrees(ctx, ws.RepoPath)
+	if err != nil {
+		return nil, invalidError(err.Error(), err)
+	}
+	result.Pruned = pruned
+	for _, repo := range workspaceLinkedRepos(ctx, ws) {
+		linkedPruned, err := pruneGitWorktrees(ctx, repo)
+		if err != nil {
+			return nil, invalidError(linkedRepoName(repo)+": "+err.Error(), err)
+		}
+		for _, entry := range linkedPruned {
+			result.Pruned = append(result.Pruned, linkedRepoName(repo)+": "+entry)
+		}
+	}
+	worktrees, err := s.worktrees.ListWorktrees(ctx, workspaceID)
+	if err != nil {
+		return nil, unavailableError(err.Error(), err)
+	}
+	for _, wt := range worktrees {
+		if wt == nil {
+			continue
+		}
+		if _, statErr := os.Stat(wt.Path); !errors.Is(statErr, os.ErrNotExist) {
+			continue
+		}
+		if err := s.DeleteWorktree(ctx, workspaceID, wt.ID); err != nil {
+			return nil, err
+		}
+		result.Pruned = append(result.Pruned, "forgot worktree "+wt.Name+": "+wt.Path+" no longer exists")
+	}
+	return result, nil
+}
+
+// MergeWorktree merges the worktree's branch into the workspace's main
+// branch (the branch checked out at the workspace repo path, or req.Target).
+// Linked worktrees are checked before the primary one is touched; once the
+// primary merge succeeds each linked worktree's branch is merged into its
+// target (see linkedOperationTarget).
+func (s *WorkspaceService) MergeWorktree(ctx context.Context, workspaceID, worktreeID string, req types.WorktreeOperationRequest) (*types.WorktreeOperationResult, error) {
+	ws, wt, err := s.worktreeForOperation(ctx, workspaceID, worktreeID)
+	if err != nil {
+		return nil, err
+	}
+	result := newWorktreeOperationResult(types.WorktreeOperationMerge, ws, wt)
+	branch, err := gitCurrentBranch(ctx, wt.Path)
+	if err != nil {
+		return nil, invalidError(err.Error(), err)
+	}
+	target, err := gitCurrentBranch(ctx, ws.RepoPath)
+	if err != nil {
+		return nil, invalidError(err.Error(), err)
+	}
+	if requested := strings.TrimSpace(req.Target); requested != "" && requested != target {
+		return nil, invalidError("merge target "+requested+" is not checked out at "+ws.RepoPath, nil)
+	}
+	result.Branch = branch
+	result.Target = target
+	if branch == target {
+		return nil, invalidError("worktree is on the main branch "+target, nil)
+	}
+	dirty, err := gitDirtyFiles(ctx, ws.RepoPath)
+	if err != nil {
+		return nil, invalidError(err.Error(), err)
+	}
+	if len(dirty) > 0 {
+		result.Status = types.WorktreeOperationDirty
+		result.DirtyFiles = dirty
+		return result, nil
+	}
+	linkedResults, err := preflightLinkedWorktrees(ctx, result, wt.LinkedWorktrees, req)
+	if err != nil {
+		return nil, err
+	}
+	if refuseDirtyLinkedWorktrees(result, linkedResults, wt.LinkedWorktrees) {
+		return result, nil
+	}
+	conflicts, output, err := mergeGitBranch(ctx, ws.RepoPath, branch)
+	if err != nil {
+		return nil, invalidError(err.Error(), err)
+	}
+	result.Output = output
+	if len(conflicts) > 0 {
+		result.Status = types.WorktreeOperationConflict
+		result.Conflicts = conflicts
+		return result, nil
+	}
+	result.Linked = linkedResults
+	for i, linked := range wt.LinkedWorktrees {
+		if err := mergeLinkedWorktree(ctx, linkedResults[i], linked); err != nil {
+			failLinkedOperation(linkedResults[i], err)
+		}
+	}
+	rollUpLinkedStatus(result, wt.LinkedWorktrees)
+	return result, nil
+}
+
+// mergeLinkedWorktree merges a preflighted linked worktree into its target.
+func mergeLinkedWorktree(ctx context.Context, result *types.WorktreeOperationResult, linked types.LinkedWorktree) error {
+	conflicts, output, err := mergeGitBranch(ctx, linked.RepoPath, linked.Branch)
+	if err != nil {
+		return err
+	}
+	result.Output = output
+	if len(conflicts) > 0 {
+		result.Status = types.WorktreeOperationConflict
+		result.Conflicts = conflicts
+	}
+	return nil
+}
+
+// RebaseWorktree rebases the worktree's branch onto the workspace's main
+// branch (or req.Target). Linked worktrees are checked before the primary one
+// is touched and rebased onto their targets (see linkedOperationTarget) once
+// the primary rebase succeeds.
+func (s *WorkspaceService) RebaseWorktree(ctx context.Context, workspaceID, worktreeID string, req types.WorktreeOperationRequest) (*types.WorktreeOperationResult, error) {
+	ws, wt, err := s.worktreeForOperation(ctx, workspaceID, worktreeID)
+	if err != nil {
+		return nil, err
+	}
+	result := newWorktreeOperationResult(types.WorktreeOperationRebase, ws, wt)
+	branch, err := gitCurrentBranch(ctx, wt.Path)
+	if err != nil {
+		return nil, invalidError(err.Error(), err)
+	}
+	target := strings.TrimSpace(req.Target)
+	if target == "" {
+		if target, err = gitCurrentBranch(ctx, ws.RepoPath); err != nil {
+			return nil, invalidError(err.Error(), err)
+		}
+	}
+	if strings.HasPrefix(target, "-") {
+		return nil, invalidError("invalid target: "+target, nil)
+	}
+	result.Branch = branch
+	result.Target = target
+	dirty, err := gitDirtyFiles(ctx, wt.Path)
+	if err != nil {
+		return nil, invalidError(err.Error(), err)
+	}
+	if len(dirty) > 0 {
+		result.Status = types.WorktreeOperationDirty
+		result.DirtyFiles = dirty
+		return result, nil
+	}
+	linkedResults, err := preflightLinkedWorktrees(ctx, result, wt.LinkedWorktrees, req)
+	if err != nil {
+		return nil, err
+	}
+	if refuseDirtyLinkedWorktrees(result, linkedResults, wt.LinkedWorktrees) {
+		return result, nil
+	}
+	conflicts, output, err := rebaseGitBranch(ctx, wt.Path, target)
+	if err != nil {
+		return nil, invalidError(err.Error(), err)
+	}
+	result.Output = output
+	if len(conflicts) > 0 {
+		result.Status = types.WorktreeOperationConflict
+		result.Conflicts = conflicts
+		return result, nil
+	}
+	result.Linked = linkedResults
+	for i, linked := range wt.LinkedWorktrees {
+		if err := rebaseLinkedWorktree(ctx, linkedResults[i], linked); err != nil {
+			failLinkedOperation(linkedResults[i], err)
+		}
+	}
+	rollUpLinkedStatus(result, wt.LinkedWorktrees)
+	return result, nil
+}
+
+// rebaseLinkedWorktree rebases a preflighted linked worktree onto its target.
+func rebaseLinkedWorktree(ctx context.Context, result *types.WorktreeOperationResult, linked types.LinkedWorktree) error {
+	conflicts, output, err := rebaseGitBranch(ctx, linked.Path, result.Target)
+	if err != nil {
+		return err
+	}
+	result.Output = output
+	if len(conflicts) > 0 {
+		result.Status = types.WorktreeOperationConflict
+		result.Conflicts = conflicts
+	}
+	return nil
+}
+
+// linkedOperationTarget is the branch a linked worktree is merged into or
+// rebased onto: req.LinkedTargets for its repo name when set, else the branch
+// checked out in its linked repo. req.Target only applies to the primary
+// worktree.
+func linkedOperationTarget(ctx context.Context, linked types.LinkedWorktree, req types.WorktreeOperationRequest) (string, error) {
+	if target := strings.TrimSpace(req.LinkedTargets[linkedRepoName(linked.RepoPath)]); target != "" {
+		if strings.HasPrefix(target, "-") {
+			return "", errors.New("invalid target: " + target)
+		}
+		return target, nil
+	}
+	return gitCurrentBranch(ctx, linked.RepoPath)
+}
+
+// preflightLinkedWorktrees checks every linked worktree of a merge or rebase
+// before the primary worktree is touched: the target must resolve, a merge
+// target must be checked out in the linked repo, and the branch must exist.
+// It returns one result per linked worktree, with its target and any dirty
+// files; they are not attached to parent.
+func preflightLinkedWorktrees(ctx context.Context, parent *types.WorktreeOperationResult, linked []types.LinkedWorktree, req types.WorktreeOperationRequest) ([]*types.WorktreeOperationResult, error) {
+	scratch := *parent
+	scratch.Linked = nil
+	for _, entry := range linked {
+		result := newLinkedOperationResult(&scratch, entry)
+		if err := preflightLinkedWorktree(ctx, result, entry, req); err != nil {
+			return nil, invalidError(linkedRepoName(entry.RepoPath)+": "+err.Error(), err)
+		}
+	}
+	return scratch.Linked, nil
+}
+
+func preflightLinkedWorktree(ctx context.Context, result *types.WorktreeOperationResult, linked types.LinkedWorktree, req types.WorktreeOperationRequest) error {
+	target, err := linkedOperationTarget(ctx, linked, req)
+	if err != nil {
+		return err
+	}
+	result.Target = target
+	if !gitBranchExists(ctx, linked.RepoPath, linked.Branch) {
+		return errors.New("branch " + linked.Branch + " does not exist")
+	}
+	dirtyPath := linked.Path
+	if result.Operation == types.WorktreeOperationMerge {
+		current, err := gitCurrentBranch(ctx, linked.RepoPath)
+		if err != nil {
+			return err
+		}
+		if target != current {
+			return errors.New("merge target " + target + " is not checked out at " + linked.RepoPath)
+		}
+		if linked.Branch == target {
+			return errors.New("linked worktree is on the main branch " + target)
+		}
+		dirtyPath = linked.RepoPath
+	} else if !gitRefExists(ctx, linked.Path, target) {
+		return errors.New("target " + target + " does not exist")
+	}
+	dirty, err := gitDirtyFiles(ctx, dirtyPath)
+	if err != nil {
+		return err
+	}
+	if len(dirty) > 0 {
+		result.Status = types.WorktreeOperationDirty
+		result.DirtyFiles = dirty
+	}
+	return nil
+}
+
+// refuseDirtyLinkedWorktrees reports the operation as dirty when a linked
+// worktree has uncommitted changes, before anything was changed.
+func refuseDirtyLinkedWorktrees(result *types.WorktreeOperationResult, linkedResults []*types.WorktreeOperationResult, linked []types.LinkedWorktree) bool {
+	for _, entry := range linkedResults {
+		if entry.Status == types.WorktreeOperationDirty {
+			result.Linked = linkedResults
+			rollUpLinkedStatus(result, linked)
+			return true
+		}
+	}
+	return false
+}
+
+// failLinkedOperation records err on a linked worktree whose operation failed
+// after the primary worktree was already changed, so the caller still learns
+// what happened to the rest of the set.
+func failLinkedOperation(result *types.WorktreeOperationResult, err error) {
+	result.Status = types.WorktreeOperationFailed
+	result.Output = err.Error()
+}
+
+// PushWorktree pushes the worktree's branch and the branches of its linked
+// worktrees to req.Remote (origin by default) and sets them as upstreams. A
+// linked push that fails once the primary branch was pushed is reported on
+// its linked result.
+func (s *WorkspaceService) PushWorktree(ctx context.Context, workspaceID, worktreeID string, req types.WorktreeOperationRequest) (*types.WorktreeOperationResult, error) {
+	ws, wt, err := s.worktreeForOperation(ctx, workspaceID, worktreeID)
+	if err != nil {
+		return nil, err
+	}
+	result := newWorktreeOperationResult(types.WorktreeOperationPush, ws, wt)
+	branch, err := gitCurrentBranch(ctx, wt.Path)
+	if err != nil {
+		return nil, invalidError(err.Error(), err)
+	}
+	remote := strings.TrimSpace(req.Remote)
+	if remote == "" {
+		remote = defaultWorktreePushRemote
+	}
+	if strings.HasPrefix(remote, "-") {
+		return nil, invalidError("invalid remote: "+remote, nil)
+	}
+	result.Branch = branch
+	result.Target = remote
+	rejected, output, err := pushGitBranch(ctx, wt.Path, remote, branch, req.Force)
+	if err != nil {
+		return nil, invalidError(err.Error(), err)
+	}
+	result.Output = output
+	if rejected {
+		result.Status = types.WorktreeOperationRejected
+	}
+	for _, linked := range wt.LinkedWorktrees {
+		linkedResult := newLinkedOperationResult(result, linked)
+		linkedResult.Target = remote
+		rejected, output, err := pushGitBranch(ctx, linked.Path, remote, linked.Branch, req.Force)
+		if err != nil {
+			failLinkedOperation(linkedResult, err)
+			continue
+		}
+		linkedResult.Output = output
+		if rejected {
+			linkedResult.Status = types.WorktreeOperationRejected
+		}
+	}
+	rollUpLinkedStatus(result, wt.LinkedWorktrees)
+	return result, nil
+}
@@ -0,0 +1,172 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"control/internal/types"
+)
+
+func newWorktreeLifecycleFixture(t *testing.T) (*WorkspaceService, *types.Workspace, *types.Worktree, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	root := t.TempDir()
+	repoDir := filepath.Join(root, "repo")
+	if err := os.MkdirAll(repoDir, 0o755); err != nil {
+		t.Fatalf("mkdir repo: %v", err)
+	}
+	runTestGit(t, repoDir, "init", "-q", "-b", "main")
+	// Merges and rebases run through the daemon's git helpers, which do not
+	// pass identity overrides, so the repository needs its own.
+	runTestGit(t, repoDir, "config", "user.name", "archon")
+	runTestGit(t, repoDir, "config", "user.email", "archon@example.com")
+	runTestGit(t, repoDir, "config", "commit.gpgsign", "false")
+	writeTestFile(t, filepath.Join(repoDir, "README.md"), "hello\n")
+	runTestGit(t, repoDir, "add", "README.md")
+	runTestGit(t, repoDir, "commit", "-q", "-m", "init")
+	wtDir := filepath.Join(root, "feature")
+	runTestGit(t, repoDir, "worktree", "add", "-q", "-b", "feature", wtDir)
+
+	stores := newTestStores(t)
+	ws, err := stores.Workspaces.Add(context.Background(), &types.Workspace{Name: "repo", RepoPath: repoDir})
+	if err != nil {
+		t.Fatalf("add workspace: %v", err)
+	}
+	wt, err := stores.Worktrees.AddWorktree(context.Background(), ws.ID, &types.Worktree{Name: "feature", Path: wtDir})
+	if err != nil {
+		t.Fatalf("add worktree: %v", err)
+	}
+	return NewWorkspaceService(stores), ws, wt, repoDir
+}
+
+func TestWorktreeRebaseAndMergeIntoMain(t *testing.T) {
+	service, ws, wt, repoDir := newWorktreeLifecycleFixture(t)
+	ctx := context.Background()
+
+	writeTestFile(t, filepath.Join(repoDir, "main.txt"), "main\n")
+	runTestGit(t, repoDir, "add", "main.txt")
+	runTestGit(t, repoDir, "commit", "-q", "-m", "main change")
+	writeTestFile(t, filepath.Join(wt.Path, "feature.txt"), "feature\n")
+	runTestGit(t, wt.Path, "add", "feature.txt")
+	runTestGit(t, wt.Path, "commit", "-q", "-m", "feature change")
+
+	rebase, err := service.RebaseWorktree(ctx, ws.ID, wt.ID, types.WorktreeOperationRequest{})
+	if err != nil {
+		t.Fatalf("rebase: %v", err)
+	}
+	if rebase.Status != types.WorktreeOperationOK || rebase.Branch != "feature" || rebase.Target != "main" {
+		t.Fatalf("unexpected rebase result: %#v", rebase)
+	}
+	if _, err := os.Stat(filepath.Join(wt.Path, "main.txt")); err != nil {
+		t.Fatalf("expected rebased worktree to contain main's change: %v", err)
+	}
+
+	merge, err := service.MergeWorktree(ctx, ws.ID, wt.ID, types.WorktreeOperationRequest{})
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if merge.Status != types.WorktreeOperationOK {
+		t.Fatalf("unexpected merge result: %#v", merge)
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, "feature.txt")); err != nil {
+		t.Fatalf("expected main to contain the merged feature change: %v", err)
+	}
+}
+
+func TestWorktreeMergeReportsConflictsAndAborts(t *testing.T) {
+	service, ws, wt, repoDir := newWorktreeLifecycleFixture(t)
+	ctx := context.Background()
+
+	writeTestFile(t, filepath.Join(repoDir, "README.md"), "main\n")
+	runTestGit(t, repoDir, "commit", "-q", "-am", "main edit")
+	writeTestFile(t, filepath.Join(wt.Path, "README.md"), "feature\n")
+	runTestGit(t, wt.Path, "commit", "-q", "-am", "feature edit")
+
+	result, err := service.MergeWorktree(ctx, ws.ID, wt.ID, types.WorktreeOperationRequest{})
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if result.Status != types.WorktreeOperationConflict || len(result.Conflicts) != 1 || result.Conflicts[0] != "README.md" {
+		t.Fatalf("expected README.md conflict, got %#v", result)
+	}
+	dirty, err := gitDirtyFiles(context.Background(), repoDir)
+	if err != nil || len(dirty) != 0 {
+		t.Fatalf("expected aborted merge to leave main clean, got %v (%v)", dirty, err)
+	}
+
+	rebase, err := service.RebaseWorktree(ctx, ws.ID, wt.ID, types.WorktreeOperationRequest{})
+	if err != nil {
+		t.Fatalf("rebase: %v", err)
+	}
+	if rebase.Status != types.WorktreeOperationConflict || len(rebase.Conflicts) != 1 {
+		t.Fatalf("expected rebase conflict, got %#v", rebase)
+	}
+}
+
+func TestWorktreeRemoveRefusesDirtyTreeUnlessForced(t *testing.T) {
+	service, ws, wt, _ := newWorktreeLifecycleFixture(t)
+	ctx := context.Background()
+	writeTestFile(t, filepath.Join(wt.Path, "scratch.txt"), "wip\n")
+
+	result, err := service.RemoveWorktree(ctx, ws.ID, wt.ID, types.WorktreeOperationRequest{})
+	if err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if result.Status != types.WorktreeOperationDirty || len(result.DirtyFiles) != 1 || result.DirtyFiles[0] != "scratch.txt" {
+		t.Fatalf("expected dirty refusal, got %#v", result)
+	}
+	if _, err := os.Stat(wt.Path); err != nil {
+		t.Fatalf("expected worktree to remain on disk: %v", err)
+	}
+
+	result, err = service.RemoveWorktree(ctx, ws.ID, wt.ID, types.WorktreeOperationRequest{Force: true})
+	if err != nil {
+		t.Fatalf("forced remove: %v", err)
+	}
+	if result.Status != types.WorktreeOperationOK {
+		t.Fatalf("unexpected forced remove result: %#v", result)
+	}
+	if _, err := os.Stat(wt.Path); !os.IsNotExist(err) {
+		t.Fatalf("expected worktree directory to be removed, got %v", err)
+	}
+	worktrees, err := service.ListWorktrees(ctx, ws.ID)
+	if err != nil || len(worktrees) != 0 {
+		t.Fatalf("expected worktree record to be forgotten, got %#v (%v)", worktrees, err)
+	}
+}
+
+func TestPruneWorktreesForgetsMissingDirectories(t *testing.T) {
+	service, ws, wt, _ := newWorktreeLifecycleFixture(t)
+	if err := os.RemoveAll(wt.Path); err != nil {
+		t.Fatalf("remove worktree dir: %v", err)
+	}
+
+	result, err := service.PruneWorktrees(context.Background(), ws.ID)
+	if err != nil {
+		t.Fatalf("prune: %v", err)
+	}
+	if result.Status != types.WorktreeOperationOK || len(result.Pruned) < 2 {
+		t.Fatalf("expected git entry and record to be pruned, got %#v", result)
+	}
+	worktrees, err := service.ListWorktrees(context.Background(), ws.ID)
+	if err != nil || len(worktrees) != 0 {
+		t.Fatalf("expected stale record to be forgotten, got %#v (%v)", worktrees, err)
+	}
+}
+
+func TestWorktreeGitCommandsStopWhenRequestIsCanceled(t *testing.T) {
+	_, _, wt, _ := newWorktreeLifecycleFixture(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := gitDirtyFiles(ctx, wt.Path); err == nil {
+		t.Fatalf("expected git status with a canceled request to fail")
+	}
+	if files, err := gitDirtyFiles(context.Background(), wt.Path); err != nil || len(files) != 0 {
+		t.Fatalf("expected clean worktree, got %v (%v)", files, err)
+	}
+}
@@ -473,6 +473,7 @@ type StepPromptDispatchResult struct {
 	TurnID     string `json:"turn_id,omitempty"`
 	Provider   string `json:"provider,omitempty"`
 	Model      string `json:"model,omitempty"`
+	TraceID    string `json:"trace_id,omitempty"`
 }
 
 type GateDispatchResult struct {
@@ -482,6 +483,7 @@ type GateDispatchResult struct {
 	SignalID   string `json:"signal_id,omitempty"`
 	Provider   string `json:"provider,omitempty"`
 	Model      string `json:"model,omitempty"`
+	TraceID    string `json:"trace_id,omitempty"`
 }
 
 type StepPromptDispatcher interface {
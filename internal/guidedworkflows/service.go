@@ -3205,7 +3205,7 @@ func recordStepExecutionDispatch(
 	step.ExecutionState = StepExecutionStateLinked
 	step.ExecutionMessage = ""
 	execution := StepExecutionRef{
-		TraceID:        stepTraceID(run, phase, step, step.Attempts),
+		TraceID:        firstNonEmpty(strings.TrimSpace(result.TraceID), stepTraceID(run, phase, step, step.Attempts)),
 		SessionID:      strings.TrimSpace(result.SessionID),
 		SessionScope:   runSessionScope(run),
 		Provider:       strings.TrimSpace(result.Provider),
@@ -3333,7 +3333,7 @@ func recordGateExecutionDispatch(
 	gate.ExecutionState = GateExecutionStateLinked
 	gate.ExecutionMessage = ""
 	execution := GateExecutionRef{
-		TraceID:        gateTraceID(run, phase, gate, max(1, len(gate.ExecutionAttempts)+1)),
+		TraceID:        firstNonEmpty(strings.TrimSpace(result.TraceID), gateTraceID(run, phase, gate, max(1, len(gate.ExecutionAttempts)+1))),
 		Transport:      strings.TrimSpace(result.Transport),
 		SessionID:      strings.TrimSpace(result.SessionID),
 		SessionScope:   runSessionScope(run),
@@ -4871,6 +4871,50 @@ func TestRunLifecyclePromptDispatchCapturesProviderAndModel(t *testing.T) {
 	}
 }
 
+func TestRunLifecyclePromptDispatchPrefersDispatcherTraceID(t *testing.T) {
+	template := WorkflowTemplate{
+		ID:   "prompted_with_trace",
+		Name: "Prompted with trace",
+		Phases: []WorkflowTemplatePhase{
+			{
+				ID:   "phase",
+				Name: "phase",
+				Steps: []WorkflowTemplateStep{
+					{ID: "step_1", Name: "step 1", Prompt: "prompt 1"},
+				},
+			},
+		},
+	}
+	dispatcher := &stubStepPromptDispatcher{
+		responses: []StepPromptDispatchResult{
+			{Dispatched: true, SessionID: "sess-1", TurnID: "turn-a", Provider: "codex", TraceID: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		},
+	}
+	service := NewRunService(
+		Config{Enabled: true},
+		WithTemplate(template),
+		WithStepPromptDispatcher(dispatcher),
+		WithGateDispatcher(dispatcher),
+	)
+	run, err := service.CreateRun(context.Background(), CreateRunRequest{
+		TemplateID:  "prompted_with_trace",
+		WorkspaceID: "ws-1",
+		WorktreeID:  "wt-1",
+		SessionID:   "sess-1",
+	})
+	if err != nil {
+		t.Fatalf("CreateRun: %v", err)
+	}
+	run, err = service.StartRun(context.Background(), run.ID)
+	if err != nil {
+		t.Fatalf("StartRun: %v", err)
+	}
+	step := run.Phases[0].Steps[0]
+	if step.Execution == nil || step.Execution.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("expected dispatcher trace id to be recorded, got %#v", step.Execution)
+	}
+}
+
 func TestRunLifecyclePromptDispatchIncludesStepRuntimeOptions(t *testing.T) {
 	stepRuntimeOptions := &types.SessionRuntimeOptions{
 		Model:     "gpt-5.4-codex",
@@ -11,6 +11,7 @@ import (
 
 type ParsedWorkflowTemplateCatalog struct {
 	Version   int
+	Prompts   map[string]string
 	Templates []WorkflowTemplate
 }
 
@@ -198,6 +199,7 @@ func ParseWorkflowTemplateCatalogJSON(raw []byte) (ParsedWorkflowTemplateCatalog
 	}
 	return ParsedWorkflowTemplateCatalog{
 		Version:   file.Version,
+		Prompts:   defs.prompts,
 		Templates: expanded,
 	}, nil
 }
@@ -12,15 +12,27 @@ var (
 
 	defaultWorkflowTemplatesOnce sync.Once
 	defaultWorkflowTemplates     []WorkflowTemplate
+	defaultWorkflowPrompts       map[string]string
 )
 
 func DefaultWorkflowTemplates() []WorkflowTemplate {
 	defaultWorkflowTemplatesOnce.Do(func() {
-		defaultWorkflowTemplates = mustParseDefaultWorkflowTemplates(defaultWorkflowTemplatesJSON)
+		defaultWorkflowTemplates, defaultWorkflowPrompts = mustParseDefaultWorkflowTemplates(defaultWorkflowTemplatesJSON)
 	})
 	return cloneWorkflowTemplateSlice(defaultWorkflowTemplates)
 }
 
+// DefaultWorkflowPrompts returns the shared prompt definitions of the
+// built-in template catalog keyed by prompt id.
+func DefaultWorkflowPrompts() map[string]string {
+	DefaultWorkflowTemplates()
+	out := make(map[string]string, len(defaultWorkflowPrompts))
+	for id, prompt := range defaultWorkflowPrompts {
+		out[id] = prompt
+	}
+	return out
+}
+
 func defaultWorkflowTemplateByID(id string) (WorkflowTemplate, bool) {
 	id = strings.TrimSpace(id)
 	if id == "" {
@@ -36,7 +48,7 @@ func defaultWorkflowTemplateByID(id string) (WorkflowTemplate, bool) {
 	return WorkflowTemplate{}, false
 }
 
-func mustParseDefaultWorkflowTemplates(raw []byte) []WorkflowTemplate {
+func mustParseDefaultWorkflowTemplates(raw []byte) ([]WorkflowTemplate, map[string]string) {
 	parsed, err := ParseWorkflowTemplateCatalogJSON(raw)
 	if err != nil {
 		panic("guidedworkflows: failed to parse default workflow templates JSON: " + err.Error())
@@ -66,7 +78,7 @@ func mustParseDefaultWorkflowTemplates(raw []byte) []WorkflowTemplate {
 	if len(out) == 0 {
 		panic("guidedworkflows: default workflow templates JSON has no valid templates")
 	}
-	return out
+	return out, parsed.Prompts
 }
 
 func cloneWorkflowTemplateSlice(in []WorkflowTemplate) []WorkflowTemplate {
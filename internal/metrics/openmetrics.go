@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// WriteOpenMetrics encodes families in the OpenMetrics text exposition format,
+// terminated by the mandatory "# EOF" marker.
+func WriteOpenMetrics(w io.Writer, families []Family) error {
+	buf := bufio.NewWriter(w)
+	for _, family := range families {
+		if strings.TrimSpace(family.Name) == "" {
+			continue
+		}
+		writeFamily(buf, family)
+	}
+	_, _ = buf.WriteString("# EOF\n")
+	return buf.Flush()
+}
+
+func writeFamily(buf *bufio.Writer, family Family) {
+	kind := family.Kind
+	if kind == "" {
+		kind = KindGauge
+	}
+	_, _ = buf.WriteString("# TYPE " + family.Name + " " + string(kind) + "\n")
+	if help := strings.TrimSpace(family.Help); help != "" {
+		_, _ = buf.WriteString("# HELP " + family.Name + " " + escapeHelp(help) + "\n")
+	}
+	for _, sample := range family.Samples {
+		switch kind {
+		case KindCounter:
+			writeSample(buf, family.Name+"_total", sample.Labels, formatFloat(sample.Value))
+		case KindHistogram:
+			for _, bucket := range sample.Buckets {
+				labels := append(append([]Label(nil), sample.Labels...), Label{Name: "le", Value: formatFloat(bucket.UpperBound)})
+				writeSample(buf, family.Name+"_bucket", labels, strconv.FormatUint(bucket.Count, 10))
+			}
+			infLabels := append(append([]Label(nil), sample.Labels...), Label{Name: "le", Value: "+Inf"})
+			writeSample(buf, family.Name+"_bucket", infLabels, strconv.FormatUint(sample.Count, 10))
+			writeSample(buf, family.Name+"_sum", sample.Labels, formatFloat(sample.Sum))
+			writeSample(buf, family.Name+"_count", sample.Labels, strconv.FormatUint(sample.Count, 10))
+		default:
+			writeSample(buf, family.Name, sample.Labels, formatFloat(sample.Value))
+		}
+	}
+}
+
+func writeSample(buf *bufio.Writer, name string, labels []Label, value string) {
+	_, _ = buf.WriteString(name)
+	if len(labels) > 0 {
+		_ = buf.WriteByte('{')
+		for i, label := range labels {
+			if i > 0 {
+				_ = buf.WriteByte(',')
+			}
+			_, _ = buf.WriteString(label.Name + "=\"" + escapeLabelValue(label.Value) + "\"")
+		}
+		_ = buf.WriteByte('}')
+	}
+	_ = buf.WriteByte(' ')
+	_, _ = buf.WriteString(value)
+	_ = buf.WriteByte('\n')
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	if value == math.Trunc(value) && math.Abs(value) < 1e15 {
+		return strconv.FormatFloat(value, 'f', 1, 64)
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// OpenMetrics uses the same escaping rules for label values and HELP text.
+var textEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeLabelValue(value string) string {
+	return textEscaper.Replace(value)
+}
+
+func escapeHelp(value string) string {
+	return textEscaper.Replace(value)
+}
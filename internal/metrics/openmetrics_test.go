@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteOpenMetricsEncodesAllKinds(t *testing.T) {
+	registry := NewRegistry()
+	sessions := registry.NewGaugeVec("archon_sessions", "Sessions by provider.", "provider")
+	reconnects := registry.NewCounterVec("archon_reconnects", "Reconnect attempts.", "provider")
+	durations := registry.NewHistogramVec("archon_turn_duration_seconds", "Turn durations.", []float64{1, 5}, "provider")
+
+	sessions.Set(2, "codex")
+	sessions.Inc("claude")
+	reconnects.Inc("codex")
+	reconnects.Add(2, "codex")
+	durations.Observe(0.5, "codex")
+	durations.Observe(3, "codex")
+	durations.Observe(9, "codex")
+
+	var out bytes.Buffer
+	if err := WriteOpenMetrics(&out, registry.Gather()); err != nil {
+		t.Fatalf("WriteOpenMetrics: %v", err)
+	}
+	text := out.String()
+	for _, want := range []string{
+		"# TYPE archon_sessions gauge\n",
+		"# HELP archon_sessions Sessions by provider.\n",
+		"archon_sessions{provider=\"claude\"} 1.0\n",
+		"archon_sessions{provider=\"codex\"} 2.0\n",
+		"# TYPE archon_reconnects counter\n",
+		"archon_reconnects_total{provider=\"codex\"} 3.0\n",
+		"# TYPE archon_turn_duration_seconds histogram\n",
+		"archon_turn_duration_seconds_bucket{provider=\"codex\",le=\"1.0\"} 1\n",
+		"archon_turn_duration_seconds_bucket{provider=\"codex\",le=\"5.0\"} 2\n",
+		"archon_turn_duration_seconds_bucket{provider=\"codex\",le=\"+Inf\"} 3\n",
+		"archon_turn_duration_seconds_sum{provider=\"codex\"} 12.5\n",
+		"archon_turn_duration_seconds_count{provider=\"codex\"} 3\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected output to contain %q, got:\n%s", want, text)
+		}
+	}
+	if !strings.HasSuffix(text, "# EOF\n") {
+		t.Fatalf("expected EOF marker, got:\n%s", text)
+	}
+	if strings.Index(text, "claude") > strings.Index(text, "provider=\"codex\"} 2.0") {
+		t.Fatalf("expected samples sorted by label values, got:\n%s", text)
+	}
+}
+
+func TestWriteOpenMetricsEscapesLabelValues(t *testing.T) {
+	var out bytes.Buffer
+	err := WriteOpenMetrics(&out, []Family{{
+		Name:    "archon_info",
+		Kind:    KindGauge,
+		Samples: []Sample{{Labels: []Label{{Name: "title", Value: "a \"b\"\\c\nd"}}, Value: 1}},
+	}})
+	if err != nil {
+		t.Fatalf("WriteOpenMetrics: %v", err)
+	}
+	want := `archon_info{title="a \"b\"\\c\nd"} 1.0`
+	if !strings.Contains(out.String(), want) {
+		t.Fatalf("expected escaped label %q, got %q", want, out.String())
+	}
+}
+
+func TestCounterIgnoresNegativeDeltas(t *testing.T) {
+	registry := NewRegistry()
+	counter := registry.NewCounterVec("archon_events", "")
+	counter.Add(-5)
+	counter.Inc()
+	families := registry.Gather()
+	if len(families) != 1 || len(families[0].Samples) != 1 {
+		t.Fatalf("unexpected families: %#v", families)
+	}
+	if got := families[0].Samples[0].Value; got != 1 {
+		t.Fatalf("expected counter value 1, got %v", got)
+	}
+}
+
+func TestGaugeIncDec(t *testing.T) {
+	registry := NewRegistry()
+	gauge := registry.NewGaugeVec("archon_subscribers", "", "stream")
+	gauge.Inc("transcript")
+	gauge.Inc("transcript")
+	gauge.Dec("transcript")
+	families := registry.Gather()
+	if got := families[0].Samples[0].Value; got != 1 {
+		t.Fatalf("expected gauge value 1, got %v", got)
+	}
+}
@@ -0,0 +1,314 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type Kind string
+
+const (
+	KindCounter   Kind = "counter"
+	KindGauge     Kind = "gauge"
+	KindHistogram Kind = "histogram"
+)
+
+var DefaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+var DefaultLongDurationBuckets = []float64{1, 5, 15, 30, 60, 120, 300, 600, 1200, 1800, 3600}
+
+type Label struct {
+	Name  string
+	Value string
+}
+
+type Sample struct {
+	Labels []Label
+	Value  float64
+
+	// Histogram-only fields.
+	Buckets []BucketCount
+	Count   uint64
+	Sum     float64
+}
+
+type BucketCount struct {
+	UpperBound float64
+	Count      uint64
+}
+
+type Family struct {
+	Name    string
+	Help    string
+	Kind    Kind
+	Samples []Sample
+}
+
+type Registry struct {
+	mu       sync.RWMutex
+	order    []string
+	families map[string]familySource
+}
+
+type familySource interface {
+	snapshot() Family
+}
+
+func NewRegistry() *Registry {
+	return &Registry{families: map[string]familySource{}}
+}
+
+func (r *Registry) register(name string, source familySource) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.families[name]; !exists {
+		r.order = append(r.order, name)
+	}
+	r.families[name] = source
+}
+
+func (r *Registry) Gather() []Family {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	sources := make([]familySource, 0, len(r.order))
+	for _, name := range r.order {
+		sources = append(sources, r.families[name])
+	}
+	r.mu.RUnlock()
+	out := make([]Family, 0, len(sources))
+	for _, source := range sources {
+		out = append(out, source.snapshot())
+	}
+	return out
+}
+
+type vecBase struct {
+	name       string
+	help       string
+	labelNames []string
+}
+
+func (v vecBase) labelsFor(values []string) []Label {
+	labels := make([]Label, len(v.labelNames))
+	for i, name := range v.labelNames {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		labels[i] = Label{Name: name, Value: value}
+	}
+	return labels
+}
+
+func seriesKey(values []string) string {
+	return strings.Join(values, "\xff")
+}
+
+type scalarSeries struct {
+	labels []Label
+	value  float64
+}
+
+type scalarVec struct {
+	vecBase
+	kind   Kind
+	mu     sync.Mutex
+	series map[string]*scalarSeries
+}
+
+func (v *scalarVec) add(delta float64, values []string) {
+	if v == nil {
+		return
+	}
+	key := seriesKey(values)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	series, ok := v.series[key]
+	if !ok {
+		series = &scalarSeries{labels: v.labelsFor(values)}
+		v.series[key] = series
+	}
+	series.value += delta
+}
+
+func (v *scalarVec) set(value float64, values []string) {
+	if v == nil {
+		return
+	}
+	key := seriesKey(values)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	series, ok := v.series[key]
+	if !ok {
+		series = &scalarSeries{labels: v.labelsFor(values)}
+		v.series[key] = series
+	}
+	series.value = value
+}
+
+func (v *scalarVec) snapshot() Family {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	family := Family{Name: v.name, Help: v.help, Kind: v.kind}
+	for _, series := range v.series {
+		family.Samples = append(family.Samples, Sample{
+			Labels: append([]Label(nil), series.labels...),
+			Value:  series.value,
+		})
+	}
+	SortSamples(family.Samples)
+	return family
+}
+
+type CounterVec struct {
+	vec *scalarVec
+}
+
+func (r *Registry) NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	vec := &scalarVec{
+		vecBase: vecBase{name: name, help: help, labelNames: append([]string(nil), labelNames...)},
+		kind:    KindCounter,
+		series:  map[string]*scalarSeries{},
+	}
+	r.register(name, vec)
+	return &CounterVec{vec: vec}
+}
+
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if c == nil || delta < 0 || math.IsNaN(delta) {
+		return
+	}
+	c.vec.add(delta, labelValues)
+}
+
+type GaugeVec struct {
+	vec *scalarVec
+}
+
+func (r *Registry) NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	vec := &scalarVec{
+		vecBase: vecBase{name: name, help: help, labelNames: append([]string(nil), labelNames...)},
+		kind:    KindGauge,
+		series:  map[string]*scalarSeries{},
+	}
+	r.register(name, vec)
+	return &GaugeVec{vec: vec}
+}
+
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	if g == nil {
+		return
+	}
+	g.vec.set(value, labelValues)
+}
+
+func (g *GaugeVec) Inc(labelValues ...string) {
+	g.Add(1, labelValues...)
+}
+
+func (g *GaugeVec) Dec(labelValues ...string) {
+	g.Add(-1, labelValues...)
+}
+
+func (g *GaugeVec) Add(delta float64, labelValues ...string) {
+	if g == nil {
+		return
+	}
+	g.vec.add(delta, labelValues)
+}
+
+type histogramSeries struct {
+	labels []Label
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+type HistogramVec struct {
+	vecBase
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	bounds := append([]float64(nil), buckets...)
+	sort.Float64s(bounds)
+	if len(bounds) == 0 {
+		bounds = append(bounds, DefaultDurationBuckets...)
+	}
+	vec := &HistogramVec{
+		vecBase: vecBase{name: name, help: help, labelNames: append([]string(nil), labelNames...)},
+		buckets: bounds,
+		series:  map[string]*histogramSeries{},
+	}
+	r.register(name, vec)
+	return vec
+}
+
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	if h == nil || math.IsNaN(value) {
+		return
+	}
+	key := seriesKey(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	series, ok := h.series[key]
+	if !ok {
+		series = &histogramSeries{
+			labels: h.labelsFor(labelValues),
+			counts: make([]uint64, len(h.buckets)),
+		}
+		h.series[key] = series
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			series.counts[i]++
+		}
+	}
+	series.count++
+	series.sum += value
+}
+
+func (h *HistogramVec) snapshot() Family {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	family := Family{Name: h.name, Help: h.help, Kind: KindHistogram}
+	for _, series := range h.series {
+		buckets := make([]BucketCount, 0, len(h.buckets))
+		for i, bound := range h.buckets {
+			buckets = append(buckets, BucketCount{UpperBound: bound, Count: series.counts[i]})
+		}
+		family.Samples = append(family.Samples, Sample{
+			Labels:  append([]Label(nil), series.labels...),
+			Buckets: buckets,
+			Count:   series.count,
+			Sum:     series.sum,
+		})
+	}
+	SortSamples(family.Samples)
+	return family
+}
+
+// SortSamples orders samples by their label values so exposition output is stable.
+func SortSamples(samples []Sample) {
+	sort.SliceStable(samples, func(i, j int) bool {
+		return labelSortKey(samples[i].Labels) < labelSortKey(samples[j].Labels)
+	})
+}
+
+func labelSortKey(labels []Label) string {
+	parts := make([]string, 0, len(labels))
+	for _, label := range labels {
+		parts = append(parts, label.Name+"="+label.Value)
+	}
+	return strings.Join(parts, "\xff")
+}
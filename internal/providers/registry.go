@@ -9,6 +9,7 @@ type Capabilities struct {
 	SupportsApprovals              bool
 	SupportsInterrupt              bool
 	SupportsFileSearch             bool
+	SupportsSlashCommands          bool
 	NoProcess                      bool
 }
 
@@ -60,6 +61,7 @@ var registry = []Definition{
 			UsesItems:                      true,
 			SupportsApprovals:              true,
 			SupportsInterrupt:              true,
+			SupportsSlashCommands:          true,
 			NoProcess:                      true,
 		},
 		Bootstrap: defaultBootstrapProfile(),
@@ -90,6 +92,7 @@ var registry = []Definition{
 			SupportsEvents:                 true,
 			SupportsApprovals:              true,
 			SupportsInterrupt:              true,
+			SupportsSlashCommands:          true,
 			SupportsFileSearch:             true,
 			NoProcess:                      true,
 		},
@@ -105,6 +108,7 @@ var registry = []Definition{
 			SupportsEvents:                 true,
 			SupportsApprovals:              true,
 			SupportsInterrupt:              true,
+			SupportsSlashCommands:          true,
 			SupportsFileSearch:             true,
 			NoProcess:                      true,
 		},
@@ -38,6 +38,7 @@ func TestProviderRegistryDefinitions(t *testing.T) {
 				UsesItems:                      true,
 				SupportsApprovals:              true,
 				SupportsInterrupt:              true,
+				SupportsSlashCommands:          true,
 				NoProcess:                      true,
 			},
 			bootstrap: defaultBootstrapProfile(),
@@ -52,6 +53,7 @@ func TestProviderRegistryDefinitions(t *testing.T) {
 				SupportsEvents:                 true,
 				SupportsApprovals:              true,
 				SupportsInterrupt:              true,
+				SupportsSlashCommands:          true,
 				SupportsFileSearch:             true,
 				NoProcess:                      true,
 			},
@@ -67,6 +69,7 @@ func TestProviderRegistryDefinitions(t *testing.T) {
 				SupportsEvents:                 true,
 				SupportsApprovals:              true,
 				SupportsInterrupt:              true,
+				SupportsSlashCommands:          true,
 				SupportsFileSearch:             true,
 				NoProcess:                      true,
 			},
@@ -0,0 +1,190 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"control/internal/types"
+)
+
+var ErrApprovalRuleNotFound = errors.New("approval rule not found")
+
+const approvalRuleSchemaVersion = 1
+
+type ApprovalRuleStore interface {
+	List(ctx context.Context) ([]*types.ApprovalRule, error)
+	Get(ctx context.Context, id string) (*types.ApprovalRule, bool, error)
+	Upsert(ctx context.Context, rule *types.ApprovalRule) (*types.ApprovalRule, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type FileApprovalRuleStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+type approvalRuleFile struct {
+	Version int                   `json:"version"`
+	Rules   []*types.ApprovalRule `json:"rules"`
+}
+
+func NewFileApprovalRuleStore(path string) *FileApprovalRuleStore {
+	return &FileApprovalRuleStore{path: path}
+}
+
+func (s *FileApprovalRuleStore) List(ctx context.Context) ([]*types.ApprovalRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*types.ApprovalRule, 0, len(file.Rules))
+	for _, rule := range file.Rules {
+		out = append(out, cloneApprovalRule(rule))
+	}
+	types.SortApprovalRules(out)
+	return out, nil
+}
+
+func (s *FileApprovalRuleStore) Get(ctx context.Context, id string) (*types.ApprovalRule, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := s.load()
+	if err != nil {
+		return nil, false, err
+	}
+	for _, rule := range file.Rules {
+		if rule.ID == id {
+			return cloneApprovalRule(rule), true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (s *FileApprovalRuleStore) Upsert(ctx context.Context, rule *types.ApprovalRule) (*types.ApprovalRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rule == nil {
+		return nil, errors.New("approval rule is required")
+	}
+	file, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	var existing *types.ApprovalRule
+	index := -1
+	for i, item := range file.Rules {
+		if item.ID == rule.ID {
+			existing = item
+			index = i
+			break
+		}
+	}
+	normalized := normalizeApprovalRule(rule, existing)
+	if index >= 0 {
+		file.Rules[index] = normalized
+	} else {
+		file.Rules = append(file.Rules, normalized)
+	}
+	if err := s.save(file); err != nil {
+		return nil, err
+	}
+	return cloneApprovalRule(normalized), nil
+}
+
+func (s *FileApprovalRuleStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := s.load()
+	if err != nil {
+		return err
+	}
+	filtered := file.Rules[:0]
+	found := false
+	for _, rule := range file.Rules {
+		if rule.ID == id {
+			found = true
+			continue
+		}
+		filtered = append(filtered, rule)
+	}
+	if !found {
+		return ErrApprovalRuleNotFound
+	}
+	file.Rules = filtered
+	return s.save(file)
+}
+
+func (s *FileApprovalRuleStore) load() (*approvalRuleFile, error) {
+	file := &approvalRuleFile{Version: approvalRuleSchemaVersion, Rules: []*types.ApprovalRule{}}
+	if err := readJSON(s.path, file); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &approvalRuleFile{Version: approvalRuleSchemaVersion, Rules: []*types.ApprovalRule{}}, nil
+		}
+		return nil, err
+	}
+	if file.Rules == nil {
+		file.Rules = []*types.ApprovalRule{}
+	}
+	// Stored rules were validated on write; validating again compiles their
+	// matchers once per load instead of on every match.
+	for _, rule := range file.Rules {
+		if rule != nil {
+			_ = types.ValidateApprovalRule(rule)
+		}
+	}
+	return file, nil
+}
+
+func (s *FileApprovalRuleStore) save(file *approvalRuleFile) error {
+	file.Version = approvalRuleSchemaVersion
+	return writeJSONAtomic(s.path, file)
+}
+
+func normalizeApprovalRule(rule *types.ApprovalRule, existing *types.ApprovalRule) *types.ApprovalRule {
+	normalized := cloneApprovalRule(rule)
+	if strings.TrimSpace(normalized.ID) == "" {
+		normalized.ID = newApprovalRuleID()
+	}
+	now := time.Now().UTC()
+	if existing != nil {
+		normalized.CreatedAt = existing.CreatedAt
+		normalized.UpdatedAt = now
+	} else if normalized.CreatedAt.IsZero() {
+		normalized.CreatedAt = now
+	}
+	if normalized.UpdatedAt.IsZero() {
+		normalized.UpdatedAt = normalized.CreatedAt
+	}
+	return normalized
+}
+
+func cloneApprovalRule(rule *types.ApprovalRule) *types.ApprovalRule {
+	if rule == nil {
+		return nil
+	}
+	copy := *rule
+	if rule.PathGlobs != nil {
+		copy.PathGlobs = append([]string(nil), rule.PathGlobs...)
+	}
+	return &copy
+}
+
+func newApprovalRuleID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "rule" + time.Now().UTC().Format("20060102150405")
+	}
+	return "rule_" + hex.EncodeToString(buf)
+}
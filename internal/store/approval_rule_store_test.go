@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"control/internal/types"
+)
+
+func TestApprovalRuleStoresCRUDInPrecedenceOrder(t *testing.T) {
+	dir := t.TempDir()
+	bboltRepo, err := NewBboltRepository(filepath.Join(dir, "archon.db"))
+	if err != nil {
+		t.Fatalf("open bbolt: %v", err)
+	}
+	defer func() { _ = bboltRepo.Close() }()
+	stores := map[string]ApprovalRuleStore{
+		"file":  NewFileApprovalRuleStore(filepath.Join(dir, "approval_rules.json")),
+		"bbolt": bboltRepo.ApprovalRules(),
+	}
+	for name, rules := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			global, err := rules.Upsert(ctx, &types.ApprovalRule{
+				Scope:         types.ApprovalRuleScopeGlobal,
+				CommandPrefix: "go test",
+				Decision:      types.ApprovalRuleAllowOnce,
+			})
+			if err != nil {
+				t.Fatalf("create global: %v", err)
+			}
+			if global.ID == "" || global.CreatedAt.IsZero() {
+				t.Fatalf("expected id and timestamps, got %#v", global)
+			}
+			session, err := rules.Upsert(ctx, &types.ApprovalRule{
+				Scope:     types.ApprovalRuleScopeSession,
+				SessionID: "s1",
+				PathGlobs: []string{"docs/**"},
+				Decision:  types.ApprovalRuleDeny,
+			})
+			if err != nil {
+				t.Fatalf("create session: %v", err)
+			}
+			list, err := rules.List(ctx)
+			if err != nil || len(list) != 2 || list[0].ID != session.ID || list[1].ID != global.ID {
+				t.Fatalf("expected session rule first, got %#v err=%v", list, err)
+			}
+			global.Disabled = true
+			if _, err := rules.Upsert(ctx, global); err != nil {
+				t.Fatalf("update: %v", err)
+			}
+			got, ok, err := rules.Get(ctx, global.ID)
+			if err != nil || !ok || !got.Disabled || !got.CreatedAt.Equal(global.CreatedAt) {
+				t.Fatalf("unexpected get: %#v ok=%v err=%v", got, ok, err)
+			}
+			if err := rules.Delete(ctx, global.ID); err != nil {
+				t.Fatalf("delete: %v", err)
+			}
+			if err := rules.Delete(ctx, global.ID); !errors.Is(err, ErrApprovalRuleNotFound) {
+				t.Fatalf("expected not found on second delete, got %v", err)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ var ErrApprovalNotFound = errors.New("approval not found")
 const approvalSchemaVersion = 1
 
 type ApprovalStore interface {
+	List(ctx context.Context) ([]*types.Approval, error)
 	ListBySession(ctx context.Context, sessionID string) ([]*types.Approval, error)
 	Get(ctx context.Context, sessionID string, requestID int) (*types.Approval, bool, error)
 	Upsert(ctx context.Context, approval *types.Approval) (*types.Approval, error)
@@ -37,6 +38,32 @@ func NewFileApprovalStore(path string) *FileApprovalStore {
 	return &FileApprovalStore{path: path}
 }
 
+// List returns every stored approval, oldest first.
+func (s *FileApprovalStore) List(ctx context.Context) ([]*types.Approval, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := s.load()
+	if err != nil {
+		if errors.Is(err, ErrApprovalNotFound) {
+			return []*types.Approval{}, nil
+		}
+		return nil, err
+	}
+	out := make([]*types.Approval, 0, len(file.Approvals))
+	for _, approval := range file.Approvals {
+		if approval == nil {
+			continue
+		}
+		copy := *approval
+		out = append(out, &copy)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].CreatedAt.Before(out[j].CreatedAt)
+	})
+	return out, nil
+}
+
 func (s *FileApprovalStore) ListBySession(ctx context.Context, sessionID string) ([]*types.Approval, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
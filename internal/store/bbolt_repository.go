@@ -29,6 +29,8 @@ var (
 	bucketWorkflowRuns      = []byte("workflow_runs")
 	bucketApprovals         = []byte("approvals")
 	bucketNotes             = []byte("notes")
+	bucketSnippets          = []byte("snippets")
+	bucketApprovalRules     = []byte("approval_rules")
 	keyAppState             = []byte("state")
 )
 
@@ -44,6 +46,8 @@ type bboltRepository struct {
 	sessions          SessionIndexStore
 	approvals         ApprovalStore
 	notes             NoteStore
+	snippets          SnippetStore
+	approvalRules     ApprovalRuleStore
 }
 
 func NewBboltRepository(path string) (Repository, error) {
@@ -74,6 +78,8 @@ func NewBboltRepository(path string) (Repository, error) {
 	repo.sessions = &bboltSessionIndexStore{db: db}
 	repo.approvals = &bboltApprovalStore{db: db}
 	repo.notes = &bboltNoteStore{db: db}
+	repo.snippets = &bboltSnippetStore{db: db}
+	repo.approvalRules = &bboltApprovalRuleStore{db: db}
 	return repo, nil
 }
 
@@ -117,6 +123,14 @@ func (r *bboltRepository) Notes() NoteStore {
 	return r.notes
 }
 
+func (r *bboltRepository) Snippets() SnippetStore {
+	return r.snippets
+}
+
+func (r *bboltRepository) ApprovalRules() ApprovalRuleStore {
+	return r.approvalRules
+}
+
 func (r *bboltRepository) Backend() string {
 	return RepositoryBackendBbolt
 }
@@ -160,6 +174,12 @@ func initBboltSchema(db *bolt.DB) error {
 		if _, err := tx.CreateBucketIfNotExists(bucketNotes); err != nil {
 			return err
 		}
+		if _, err := tx.CreateBucketIfNotExists(bucketSnippets); err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucketIfNotExists(bucketApprovalRules); err != nil {
+			return err
+		}
 		return nil
 	})
 }
@@ -614,6 +634,31 @@ type bboltApprovalStore struct {
 	mu sync.Mutex
 }
 
+func (s *bboltApprovalStore) List(ctx context.Context) ([]*types.Approval, error) {
+	out := make([]*types.Approval, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketApprovals)
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, v []byte) error {
+			var approval types.Approval
+			if err := json.Unmarshal(v, &approval); err != nil {
+				return err
+			}
+			out = append(out, cloneApproval(&approval))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].CreatedAt.Before(out[j].CreatedAt)
+	})
+	return out, nil
+}
+
 func (s *bboltApprovalStore) ListBySession(ctx context.Context, sessionID string) ([]*types.Approval, error) {
 	out := make([]*types.Approval, 0)
 	err := s.db.View(func(tx *bolt.Tx) error {
@@ -1223,6 +1268,208 @@ func (s *bboltNoteStore) Delete(ctx context.Context, id string) error {
 	})
 }
 
+type bboltSnippetStore struct {
+	db *bolt.DB
+	mu sync.Mutex
+}
+
+func (s *bboltSnippetStore) List(ctx context.Context) ([]*types.Snippet, error) {
+	out := make([]*types.Snippet, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketSnippets)
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, v []byte) error {
+			var snippet types.Snippet
+			if err := json.Unmarshal(v, &snippet); err != nil {
+				return err
+			}
+			out = append(out, cloneSnippet(&snippet))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortSnippets(out)
+	return out, nil
+}
+
+func (s *bboltSnippetStore) Get(ctx context.Context, id string) (*types.Snippet, bool, error) {
+	var (
+		snippet *types.Snippet
+		ok      bool
+	)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketSnippets)
+		if b == nil {
+			return nil
+		}
+		raw := b.Get([]byte(id))
+		if len(raw) == 0 {
+			return nil
+		}
+		var item types.Snippet
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return err
+		}
+		snippet = cloneSnippet(&item)
+		ok = true
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return snippet, ok, nil
+}
+
+func (s *bboltSnippetStore) Upsert(ctx context.Context, snippet *types.Snippet) (*types.Snippet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if snippet == nil {
+		return nil, errors.New("snippet is required")
+	}
+	existing, _, err := s.Get(ctx, snippet.ID)
+	if err != nil {
+		return nil, err
+	}
+	normalized := normalizeSnippet(snippet, existing)
+	raw, err := json.Marshal(normalized)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketSnippets)
+		if b == nil {
+			return errors.New("snippets bucket missing")
+		}
+		return b.Put([]byte(normalized.ID), raw)
+	}); err != nil {
+		return nil, err
+	}
+	return cloneSnippet(normalized), nil
+}
+
+func (s *bboltSnippetStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketSnippets)
+		if b == nil {
+			return errors.New("snippets bucket missing")
+		}
+		key := []byte(id)
+		if b.Get(key) == nil {
+			return ErrSnippetNotFound
+		}
+		return b.Delete(key)
+	})
+}
+
+type bboltApprovalRuleStore struct {
+	db *bolt.DB
+	mu sync.Mutex
+}
+
+func (s *bboltApprovalRuleStore) List(ctx context.Context) ([]*types.ApprovalRule, error) {
+	out := make([]*types.ApprovalRule, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketApprovalRules)
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, v []byte) error {
+			var rule types.ApprovalRule
+			if err := json.Unmarshal(v, &rule); err != nil {
+				return err
+			}
+			out = append(out, cloneApprovalRule(&rule))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	types.SortApprovalRules(out)
+	return out, nil
+}
+
+func (s *bboltApprovalRuleStore) Get(ctx context.Context, id string) (*types.ApprovalRule, bool, error) {
+	var (
+		rule *types.ApprovalRule
+		ok   bool
+	)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketApprovalRules)
+		if b == nil {
+			return nil
+		}
+		raw := b.Get([]byte(id))
+		if len(raw) == 0 {
+			return nil
+		}
+		var item types.ApprovalRule
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return err
+		}
+		rule = cloneApprovalRule(&item)
+		ok = true
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return rule, ok, nil
+}
+
+func (s *bboltApprovalRuleStore) Upsert(ctx context.Context, rule *types.ApprovalRule) (*types.ApprovalRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rule == nil {
+		return nil, errors.New("approval rule is required")
+	}
+	existing, _, err := s.Get(ctx, rule.ID)
+	if err != nil {
+		return nil, err
+	}
+	normalized := normalizeApprovalRule(rule, existing)
+	raw, err := json.Marshal(normalized)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketApprovalRules)
+		if b == nil {
+			return errors.New("approval_rules bucket missing")
+		}
+		return b.Put([]byte(normalized.ID), raw)
+	}); err != nil {
+		return nil, err
+	}
+	return cloneApprovalRule(normalized), nil
+}
+
+func (s *bboltApprovalRuleStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketApprovalRules)
+		if b == nil {
+			return errors.New("approval_rules bucket missing")
+		}
+		key := []byte(id)
+		if b.Get(key) == nil {
+			return ErrApprovalRuleNotFound
+		}
+		return b.Delete(key)
+	})
+}
+
 func cloneWorkspace(workspace *types.Workspace) *types.Workspace {
 	if workspace == nil {
 		return nil
@@ -1234,6 +1481,10 @@ func cloneWorkspace(workspace *types.Workspace) *types.Workspace {
 	if len(workspace.GroupIDs) > 0 {
 		copy.GroupIDs = append([]string(nil), workspace.GroupIDs...)
 	}
+	copy.EnvProfiles = types.CloneEnvProfiles(workspace.EnvProfiles)
+	if len(workspace.ContextFiles) > 0 {
+		copy.ContextFiles = append([]string(nil), workspace.ContextFiles...)
+	}
 	return &copy
 }
 
@@ -1243,6 +1494,7 @@ func cloneWorktree(worktree *types.Worktree) *types.Worktree {
 	}
 	copy := *worktree
 	copy.NotificationOverrides = types.CloneNotificationSettingsPatch(worktree.NotificationOverrides)
+	copy.LinkedWorktrees = types.CloneLinkedWorktrees(worktree.LinkedWorktrees)
 	return &copy
 }
 
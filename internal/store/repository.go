@@ -24,6 +24,8 @@ type Repository interface {
 	SessionIndex() SessionIndexStore
 	Approvals() ApprovalStore
 	Notes() NoteStore
+	Snippets() SnippetStore
+	ApprovalRules() ApprovalRuleStore
 	Backend() string
 	Close() error
 }
@@ -37,6 +39,8 @@ type RepositoryPaths struct {
 	SessionIndexPath      string
 	ApprovalsPath         string
 	NotesPath             string
+	SnippetsPath          string
+	ApprovalRulesPath     string
 	DBPath                string
 }
 
@@ -51,6 +55,8 @@ type fileRepository struct {
 	sessions          SessionIndexStore
 	approvals         ApprovalStore
 	notes             NoteStore
+	snippets          SnippetStore
+	approvalRules     ApprovalRuleStore
 }
 
 func NewFileRepository(paths RepositoryPaths) Repository {
@@ -66,6 +72,8 @@ func NewFileRepository(paths RepositoryPaths) Repository {
 		sessions:          NewFileSessionIndexStore(paths.SessionIndexPath),
 		approvals:         NewFileApprovalStore(paths.ApprovalsPath),
 		notes:             NewFileNoteStore(paths.NotesPath),
+		snippets:          NewFileSnippetStore(paths.SnippetsPath),
+		approvalRules:     NewFileApprovalRuleStore(paths.ApprovalRulesPath),
 	}
 }
 
@@ -109,6 +117,14 @@ func (r *fileRepository) Notes() NoteStore {
 	return r.notes
 }
 
+func (r *fileRepository) Snippets() SnippetStore {
+	return r.snippets
+}
+
+func (r *fileRepository) ApprovalRules() ApprovalRuleStore {
+	return r.approvalRules
+}
+
 func (r *fileRepository) Backend() string {
 	return RepositoryBackendFile
 }
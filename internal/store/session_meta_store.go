@@ -208,6 +208,9 @@ func normalizeSessionMeta(meta *types.SessionMeta, existing *types.SessionMeta)
 		if normalized.NotificationOverrides == nil {
 			normalized.NotificationOverrides = types.CloneNotificationSettingsPatch(existing.NotificationOverrides)
 		}
+		if normalized.Instructions == nil {
+			normalized.Instructions = types.CloneSessionInstructions(existing.Instructions)
+		}
 	}
 	if normalized.LastActiveAt == nil {
 		now := time.Now().UTC()
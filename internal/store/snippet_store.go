@@ -0,0 +1,194 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"control/internal/types"
+)
+
+var ErrSnippetNotFound = errors.New("snippet not found")
+
+const snippetSchemaVersion = 1
+
+type SnippetStore interface {
+	List(ctx context.Context) ([]*types.Snippet, error)
+	Get(ctx context.Context, id string) (*types.Snippet, bool, error)
+	Upsert(ctx context.Context, snippet *types.Snippet) (*types.Snippet, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type FileSnippetStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+type snippetFile struct {
+	Version  int              `json:"version"`
+	Snippets []*types.Snippet `json:"snippets"`
+}
+
+func NewFileSnippetStore(path string) *FileSnippetStore {
+	return &FileSnippetStore{path: path}
+}
+
+func (s *FileSnippetStore) List(ctx context.Context) ([]*types.Snippet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*types.Snippet, 0, len(file.Snippets))
+	for _, snippet := range file.Snippets {
+		out = append(out, cloneSnippet(snippet))
+	}
+	sortSnippets(out)
+	return out, nil
+}
+
+func (s *FileSnippetStore) Get(ctx context.Context, id string) (*types.Snippet, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := s.load()
+	if err != nil {
+		return nil, false, err
+	}
+	for _, snippet := range file.Snippets {
+		if snippet.ID == id {
+			return cloneSnippet(snippet), true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (s *FileSnippetStore) Upsert(ctx context.Context, snippet *types.Snippet) (*types.Snippet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if snippet == nil {
+		return nil, errors.New("snippet is required")
+	}
+	file, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	var existing *types.Snippet
+	index := -1
+	for i, item := range file.Snippets {
+		if item.ID == snippet.ID {
+			existing = item
+			index = i
+			break
+		}
+	}
+	normalized := normalizeSnippet(snippet, existing)
+	if index >= 0 {
+		file.Snippets[index] = normalized
+	} else {
+		file.Snippets = append(file.Snippets, normalized)
+	}
+	if err := s.save(file); err != nil {
+		return nil, err
+	}
+	return cloneSnippet(normalized), nil
+}
+
+func (s *FileSnippetStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := s.load()
+	if err != nil {
+		return err
+	}
+	filtered := file.Snippets[:0]
+	found := false
+	for _, snippet := range file.Snippets {
+		if snippet.ID == id {
+			found = true
+			continue
+		}
+		filtered = append(filtered, snippet)
+	}
+	if !found {
+		return ErrSnippetNotFound
+	}
+	file.Snippets = filtered
+	return s.save(file)
+}
+
+func (s *FileSnippetStore) load() (*snippetFile, error) {
+	file := &snippetFile{Version: snippetSchemaVersion, Snippets: []*types.Snippet{}}
+	if err := readJSON(s.path, file); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &snippetFile{Version: snippetSchemaVersion, Snippets: []*types.Snippet{}}, nil
+		}
+		return nil, err
+	}
+	if file.Snippets == nil {
+		file.Snippets = []*types.Snippet{}
+	}
+	return file, nil
+}
+
+func (s *FileSnippetStore) save(file *snippetFile) error {
+	file.Version = snippetSchemaVersion
+	return writeJSONAtomic(s.path, file)
+}
+
+func normalizeSnippet(snippet *types.Snippet, existing *types.Snippet) *types.Snippet {
+	normalized := cloneSnippet(snippet)
+	normalized.Variables = nil
+	if strings.TrimSpace(normalized.ID) == "" {
+		normalized.ID = newSnippetID()
+	}
+	now := time.Now().UTC()
+	if existing != nil {
+		normalized.CreatedAt = existing.CreatedAt
+		normalized.UpdatedAt = now
+	} else if normalized.CreatedAt.IsZero() {
+		normalized.CreatedAt = now
+	}
+	if normalized.UpdatedAt.IsZero() {
+		normalized.UpdatedAt = normalized.CreatedAt
+	}
+	return normalized
+}
+
+func cloneSnippet(snippet *types.Snippet) *types.Snippet {
+	if snippet == nil {
+		return nil
+	}
+	copy := *snippet
+	if snippet.Variables != nil {
+		copy.Variables = append([]string(nil), snippet.Variables...)
+	}
+	return &copy
+}
+
+func sortSnippets(snippets []*types.Snippet) {
+	sort.SliceStable(snippets, func(i, j int) bool {
+		if snippets[i].Name != snippets[j].Name {
+			return snippets[i].Name < snippets[j].Name
+		}
+		return snippets[i].ID < snippets[j].ID
+	})
+}
+
+func newSnippetID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "snip" + time.Now().UTC().Format("20060102150405")
+	}
+	return "snip_" + hex.EncodeToString(buf)
+}
@@ -0,0 +1,56 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"control/internal/types"
+)
+
+func TestSnippetStoresCRUD(t *testing.T) {
+	dir := t.TempDir()
+	bboltRepo, err := NewBboltRepository(filepath.Join(dir, "archon.db"))
+	if err != nil {
+		t.Fatalf("open bbolt: %v", err)
+	}
+	defer func() { _ = bboltRepo.Close() }()
+	stores := map[string]SnippetStore{
+		"file":  NewFileSnippetStore(filepath.Join(dir, "snippets.json")),
+		"bbolt": bboltRepo.Snippets(),
+	}
+	for name, snippets := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if list, err := snippets.List(ctx); err != nil || len(list) != 0 {
+				t.Fatalf("expected empty list, got %#v err=%v", list, err)
+			}
+			created, err := snippets.Upsert(ctx, &types.Snippet{
+				Name:  "review",
+				Body:  "Review {{file}}",
+				Scope: types.SnippetScopeGlobal,
+			})
+			if err != nil {
+				t.Fatalf("create: %v", err)
+			}
+			if created.ID == "" || created.CreatedAt.IsZero() {
+				t.Fatalf("expected id and timestamps, got %#v", created)
+			}
+			created.Body = "Review {{file}} carefully"
+			if _, err := snippets.Upsert(ctx, created); err != nil {
+				t.Fatalf("update: %v", err)
+			}
+			got, ok, err := snippets.Get(ctx, created.ID)
+			if err != nil || !ok || got.Body != "Review {{file}} carefully" || !got.CreatedAt.Equal(created.CreatedAt) {
+				t.Fatalf("unexpected get: %#v ok=%v err=%v", got, ok, err)
+			}
+			if err := snippets.Delete(ctx, created.ID); err != nil {
+				t.Fatalf("delete: %v", err)
+			}
+			if err := snippets.Delete(ctx, created.ID); !errors.Is(err, ErrSnippetNotFound) {
+				t.Fatalf("expected not found on second delete, got %v", err)
+			}
+		})
+	}
+}
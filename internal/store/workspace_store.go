@@ -512,6 +512,12 @@ func normalizeWorkspace(workspace *types.Workspace) (*types.Workspace, error) {
 		SessionSubpath:        sessionSubpath,
 		AdditionalDirectories: additionalDirectories,
 		GroupIDs:              normalizeGroupIDs(workspace.GroupIDs),
+		IsolateSessions:       workspace.IsolateSessions,
+		CleanupIsolated:       workspace.CleanupIsolated,
+		EnvProfiles:           types.CloneEnvProfiles(workspace.EnvProfiles),
+		EnvProfile:            strings.TrimSpace(workspace.EnvProfile),
+		Instructions:          strings.TrimSpace(workspace.Instructions),
+		ContextFiles:          types.NormalizeContextFiles(workspace.ContextFiles),
 		CreatedAt:             workspace.CreatedAt,
 		UpdatedAt:             workspace.UpdatedAt,
 	}
@@ -566,6 +572,10 @@ func cloneWorkspaceRecord(workspace *types.Workspace) *types.Workspace {
 	if len(workspace.GroupIDs) > 0 {
 		copy.GroupIDs = append([]string(nil), workspace.GroupIDs...)
 	}
+	copy.EnvProfiles = types.CloneEnvProfiles(workspace.EnvProfiles)
+	if len(workspace.ContextFiles) > 0 {
+		copy.ContextFiles = append([]string(nil), workspace.ContextFiles...)
+	}
 	return &copy
 }
 
@@ -619,7 +629,11 @@ func normalizeWorktree(workspaceID string, worktree *types.Worktree) (*types.Wor
 		WorkspaceID:           workspaceID,
 		Name:                  name,
 		Path:                  path,
+		Branch:                strings.TrimSpace(worktree.Branch),
+		Isolated:              worktree.Isolated,
 		NotificationOverrides: types.CloneNotificationSettingsPatch(worktree.NotificationOverrides),
+		EnvProfile:            strings.TrimSpace(worktree.EnvProfile),
+		LinkedWorktrees:       types.CloneLinkedWorktrees(worktree.LinkedWorktrees),
 		CreatedAt:             worktree.CreatedAt,
 		UpdatedAt:             worktree.UpdatedAt,
 	}
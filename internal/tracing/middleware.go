@@ -0,0 +1,72 @@
+package tracing
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Middleware opens a server span for every request, continuing any trace the
+// caller propagated via traceparent. Span names use the route prefix rather
+// than the full path so ids do not explode span cardinality. A nil provider
+// passes requests through untraced.
+func Middleware(provider *Provider, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if provider == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx := Extract(r.Context(), r.Header)
+		ctx, span := provider.Start(ctx, r.Method+" "+routeName(r.URL.Path), SpanKindServer,
+			String("http.request.method", r.Method),
+			String("url.path", r.URL.Path),
+		)
+		defer span.End()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+		span.SetAttributes(Int("http.response.status_code", rec.status))
+		if rec.status >= http.StatusInternalServerError {
+			span.SetStatus(StatusError, http.StatusText(rec.status))
+		}
+	})
+}
+
+func routeName(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) >= 2 && parts[0] == "v1" {
+		return "/v1/" + parts[1]
+	}
+	if len(parts) > 0 && parts[0] != "" {
+		return "/" + parts[0]
+	}
+	return "/"
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	wrote  bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wrote {
+		r.status = status
+		r.wrote = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	r.wrote = true
+	return r.ResponseWriter.Write(p)
+}
+
+// Flush keeps server-sent event handlers working behind the middleware.
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,205 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultOTLPEndpoint = "http://127.0.0.1:4318/v1/traces"
+	instrumentationName = "control/internal/tracing"
+)
+
+type OTLPHTTPExporterOptions struct {
+	Endpoint    string
+	Headers     map[string]string
+	ServiceName string
+	Client      *http.Client
+}
+
+// OTLPHTTPExporter posts spans to an OTLP/HTTP collector using the JSON
+// encoding, which keeps the daemon free of protobuf dependencies.
+type OTLPHTTPExporter struct {
+	endpoint    string
+	headers     map[string]string
+	serviceName string
+	client      *http.Client
+}
+
+func NewOTLPHTTPExporter(opts OTLPHTTPExporterOptions) *OTLPHTTPExporter {
+	endpoint := strings.TrimSpace(opts.Endpoint)
+	if endpoint == "" {
+		endpoint = DefaultOTLPEndpoint
+	}
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	headers := map[string]string{}
+	for key, value := range opts.Headers {
+		if key = strings.TrimSpace(key); key != "" {
+			headers[key] = value
+		}
+	}
+	return &OTLPHTTPExporter{
+		endpoint:    endpoint,
+		headers:     headers,
+		serviceName: strings.TrimSpace(opts.ServiceName),
+		client:      client,
+	}
+}
+
+func (e *OTLPHTTPExporter) Export(ctx context.Context, spans []SpanData) error {
+	if e == nil || len(spans) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(e.payload(spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("otlp export failed: %s", resp.Status)
+	}
+	return nil
+}
+
+func (e *OTLPHTTPExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+type otlpTracePayload struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func (e *OTLPHTTPExporter) payload(spans []SpanData) otlpTracePayload {
+	var resourceAttrs []otlpKeyValue
+	if e.serviceName != "" {
+		resourceAttrs = append(resourceAttrs, otlpAttribute(String("service.name", e.serviceName)))
+	}
+	out := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		item := otlpSpan{
+			TraceID:           span.SpanContext.TraceID.String(),
+			SpanID:            span.SpanContext.SpanID.String(),
+			Name:              span.Name,
+			Kind:              otlpSpanKind(span.Kind),
+			StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+			Status:            otlpStatus{Code: int(span.StatusCode), Message: span.StatusMessage},
+		}
+		if span.Parent.IsValid() {
+			item.ParentSpanID = span.Parent.SpanID.String()
+		}
+		for _, attr := range span.Attributes {
+			item.Attributes = append(item.Attributes, otlpAttribute(attr))
+		}
+		out = append(out, item)
+	}
+	return otlpTracePayload{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: resourceAttrs},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: instrumentationName}, Spans: out}},
+	}}}
+}
+
+// otlpSpanKind maps onto the OTLP enum, where 1 is internal, 2 server and 3
+// client.
+func otlpSpanKind(kind SpanKind) int {
+	switch kind {
+	case SpanKindServer:
+		return 2
+	case SpanKindClient:
+		return 3
+	default:
+		return 1
+	}
+}
+
+func otlpAttribute(attr Attribute) otlpKeyValue {
+	kv := otlpKeyValue{Key: attr.Key}
+	switch value := attr.Value.(type) {
+	case string:
+		kv.Value.StringValue = &value
+	case bool:
+		kv.Value.BoolValue = &value
+	case int64:
+		text := strconv.FormatInt(value, 10)
+		kv.Value.IntValue = &text
+	case int:
+		text := strconv.Itoa(value)
+		kv.Value.IntValue = &text
+	case float64:
+		kv.Value.DoubleValue = &value
+	default:
+		text := fmt.Sprint(value)
+		kv.Value.StringValue = &text
+	}
+	return kv
+}
@@ -0,0 +1,168 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const TraceparentHeader = "traceparent"
+
+type TraceID [16]byte
+
+type SpanID [8]byte
+
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		_, _ = rand.Read(id[:])
+	}
+	return id
+}
+
+// SpanContext identifies a span within a trace, locally or across a process
+// boundary.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+	Remote  bool
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Traceparent formats the span context as a W3C trace-context header value.
+func (sc SpanContext) Traceparent() string {
+	if !sc.IsValid() {
+		return ""
+	}
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", sc.TraceID, sc.SpanID, flags)
+}
+
+func ParseTraceparent(raw string) (SpanContext, bool) {
+	parts := strings.Split(strings.TrimSpace(raw), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return SpanContext{}, false
+	}
+	if parts[0] == "00" && len(parts) != 4 {
+		return SpanContext{}, false
+	}
+	var sc SpanContext
+	if !decodeHexInto(sc.TraceID[:], parts[1]) || !decodeHexInto(sc.SpanID[:], parts[2]) {
+		return SpanContext{}, false
+	}
+	flags := make([]byte, 1)
+	if !decodeHexInto(flags, parts[3]) {
+		return SpanContext{}, false
+	}
+	sc.Sampled = flags[0]&0x01 == 0x01
+	sc.Remote = true
+	if !sc.IsValid() {
+		return SpanContext{}, false
+	}
+	return sc, true
+}
+
+func decodeHexInto(dst []byte, raw string) bool {
+	if len(raw) != len(dst)*2 || strings.ToLower(raw) != raw {
+		return false
+	}
+	_, err := hex.Decode(dst, []byte(raw))
+	return err == nil
+}
+
+type spanContextKey struct{}
+
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !sc.IsValid() {
+		return ctx
+	}
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// ContextWithTraceparent attaches a remote parent parsed from a traceparent
+// value. Invalid values leave the context unchanged.
+func ContextWithTraceparent(ctx context.Context, traceparent string) context.Context {
+	sc, ok := ParseTraceparent(traceparent)
+	if !ok {
+		if ctx == nil {
+			return context.Background()
+		}
+		return ctx
+	}
+	return ContextWithSpanContext(ctx, sc)
+}
+
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if ctx == nil {
+		return SpanContext{}
+	}
+	sc, _ := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc
+}
+
+// TraceIDFromContext returns the hex trace id of the span carried by ctx, or
+// an empty string when the context is not traced.
+func TraceIDFromContext(ctx context.Context) string {
+	sc := SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return ""
+	}
+	return sc.TraceID.String()
+}
+
+func TraceparentFromContext(ctx context.Context) string {
+	return SpanContextFromContext(ctx).Traceparent()
+}
+
+func Inject(ctx context.Context, header http.Header) {
+	if header == nil {
+		return
+	}
+	if value := TraceparentFromContext(ctx); value != "" {
+		header.Set(TraceparentHeader, value)
+	}
+}
+
+func Extract(ctx context.Context, header http.Header) context.Context {
+	if header == nil {
+		return ctx
+	}
+	return ContextWithTraceparent(ctx, header.Get(TraceparentHeader))
+}
@@ -0,0 +1,311 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = iota
+	SpanKindServer
+	SpanKindClient
+)
+
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = iota
+	StatusOK
+	StatusError
+)
+
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+func String(key, value string) Attribute { return Attribute{Key: key, Value: value} }
+
+func Int(key string, value int) Attribute { return Attribute{Key: key, Value: int64(value)} }
+
+func Bool(key string, value bool) Attribute { return Attribute{Key: key, Value: value} }
+
+// SpanData is the immutable snapshot of a finished span handed to exporters.
+type SpanData struct {
+	Name          string
+	Kind          SpanKind
+	SpanContext   SpanContext
+	Parent        SpanContext
+	Start         time.Time
+	End           time.Time
+	Attributes    []Attribute
+	StatusCode    StatusCode
+	StatusMessage string
+}
+
+type Exporter interface {
+	Export(ctx context.Context, spans []SpanData) error
+	Shutdown(ctx context.Context) error
+}
+
+// Span is a single timed operation. A nil *Span is valid and records nothing,
+// so call sites never need to check whether tracing is enabled.
+type Span struct {
+	provider *Provider
+	mu       sync.Mutex
+	data     SpanData
+	ended    bool
+}
+
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.data.SpanContext
+}
+
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil || len(attrs) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended {
+		return
+	}
+	s.data.Attributes = append(s.data.Attributes, attrs...)
+}
+
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended {
+		return
+	}
+	s.data.StatusCode = StatusError
+	s.data.StatusMessage = err.Error()
+}
+
+func (s *Span) SetStatus(code StatusCode, message string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended {
+		return
+	}
+	s.data.StatusCode = code
+	s.data.StatusMessage = message
+}
+
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = s.provider.now()
+	data := s.data
+	data.Attributes = append([]Attribute(nil), s.data.Attributes...)
+	s.mu.Unlock()
+	s.provider.enqueue(data)
+}
+
+type ProviderOptions struct {
+	BatchSize     int
+	FlushInterval time.Duration
+	QueueSize     int
+	OnExportError func(error)
+}
+
+const (
+	defaultBatchSize     = 128
+	defaultFlushInterval = 2 * time.Second
+	defaultQueueSize     = 2048
+)
+
+// Provider batches finished spans and hands them to an exporter from a single
+// background goroutine. Spans are dropped rather than blocking callers when the
+// queue is full.
+type Provider struct {
+	exporter Exporter
+	opts     ProviderOptions
+	queue    chan SpanData
+	flushReq chan chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+	dropped  atomic.Int64
+	now      func() time.Time
+}
+
+func NewProvider(exporter Exporter, opts ProviderOptions) *Provider {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaultFlushInterval
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultQueueSize
+	}
+	p := &Provider{
+		exporter: exporter,
+		opts:     opts,
+		queue:    make(chan SpanData, opts.QueueSize),
+		flushReq: make(chan chan struct{}),
+		done:     make(chan struct{}),
+		now:      func() time.Time { return time.Now().UTC() },
+	}
+	go p.loop()
+	return p
+}
+
+func (p *Provider) Dropped() int64 {
+	if p == nil {
+		return 0
+	}
+	return p.dropped.Load()
+}
+
+func (p *Provider) enqueue(data SpanData) {
+	if p == nil {
+		return
+	}
+	select {
+	case <-p.done:
+		p.dropped.Add(1)
+		return
+	default:
+	}
+	select {
+	case p.queue <- data:
+	default:
+		p.dropped.Add(1)
+	}
+}
+
+func (p *Provider) loop() {
+	ticker := time.NewTicker(p.opts.FlushInterval)
+	defer ticker.Stop()
+	batch := make([]SpanData, 0, p.opts.BatchSize)
+	flush := func() {
+		if len(batch) == 0 || p.exporter == nil {
+			batch = batch[:0]
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err := p.exporter.Export(ctx, batch)
+		cancel()
+		if err != nil && p.opts.OnExportError != nil {
+			p.opts.OnExportError(err)
+		}
+		batch = make([]SpanData, 0, p.opts.BatchSize)
+	}
+	drain := func() {
+		for {
+			select {
+			case data := <-p.queue:
+				batch = append(batch, data)
+				if len(batch) >= p.opts.BatchSize {
+					flush()
+				}
+			default:
+				return
+			}
+		}
+	}
+	for {
+		select {
+		case data := <-p.queue:
+			batch = append(batch, data)
+			if len(batch) >= p.opts.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case ack := <-p.flushReq:
+			drain()
+			flush()
+			close(ack)
+		case <-p.done:
+			drain()
+			flush()
+			return
+		}
+	}
+}
+
+// ForceFlush exports every span finished before the call.
+func (p *Provider) ForceFlush(ctx context.Context) error {
+	if p == nil {
+		return nil
+	}
+	ack := make(chan struct{})
+	select {
+	case p.flushReq <- ack:
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-ack:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *Provider) Shutdown(ctx context.Context) error {
+	if p == nil {
+		return nil
+	}
+	if err := p.ForceFlush(ctx); err != nil {
+		return err
+	}
+	p.stopOnce.Do(func() { close(p.done) })
+	if p.exporter != nil {
+		return p.exporter.Shutdown(ctx)
+	}
+	return nil
+}
+
+func (p *Provider) Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, *Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if p == nil {
+		return ctx, nil
+	}
+	parent := SpanContextFromContext(ctx)
+	sc := SpanContext{SpanID: newSpanID(), Sampled: true}
+	if parent.IsValid() {
+		sc.TraceID = parent.TraceID
+	} else {
+		sc.TraceID = newTraceID()
+	}
+	span := &Span{
+		provider: p,
+		data: SpanData{
+			Name:        name,
+			Kind:        kind,
+			SpanContext: sc,
+			Parent:      parent,
+			Start:       p.now(),
+			Attributes:  append([]Attribute(nil), attrs...),
+		},
+	}
+	return ContextWithSpanContext(ctx, sc), span
+}
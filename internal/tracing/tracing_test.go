@@ -0,0 +1,180 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	raw := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, ok := ParseTraceparent(raw)
+	if !ok {
+		t.Fatalf("expected traceparent to parse")
+	}
+	if !sc.Sampled || !sc.Remote {
+		t.Fatalf("unexpected flags: %#v", sc)
+	}
+	if got := sc.Traceparent(); got != raw {
+		t.Fatalf("expected %q, got %q", raw, got)
+	}
+}
+
+func TestParseTraceparentRejectsInvalidValues(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	} {
+		if _, ok := ParseTraceparent(raw); ok {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+}
+
+func TestStartWithoutProviderIsNoop(t *testing.T) {
+	var provider *Provider
+	ctx, span := provider.Start(context.Background(), "noop", SpanKindInternal)
+	if span != nil {
+		t.Fatalf("expected nil span when tracing is disabled")
+	}
+	span.SetAttributes(String("k", "v"))
+	span.RecordError(errors.New("boom"))
+	span.End()
+	if TraceIDFromContext(ctx) != "" {
+		t.Fatalf("expected untraced context")
+	}
+}
+
+func TestChildSpansShareTraceAndExportParent(t *testing.T) {
+	exporter := &recordingExporter{}
+	provider := NewProvider(exporter, ProviderOptions{})
+	parentCtx, parent := provider.Start(context.Background(), "parent", SpanKindInternal)
+	_, child := provider.Start(parentCtx, "child", SpanKindClient, String("provider", "codex"))
+	child.RecordError(errors.New("send failed"))
+	child.End()
+	parent.End()
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	spans := exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[0].SpanContext.TraceID != spans[1].SpanContext.TraceID {
+		t.Fatalf("expected child to share the parent trace")
+	}
+	if spans[0].Parent.SpanID != parent.SpanContext().SpanID {
+		t.Fatalf("expected child parent span id to match")
+	}
+	if spans[0].StatusCode != StatusError || spans[0].StatusMessage != "send failed" {
+		t.Fatalf("expected error status, got %#v", spans[0])
+	}
+}
+
+func TestOTLPHTTPExporterPostsJSON(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		payload otlpTracePayload
+		auth    string
+	)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		auth = r.Header.Get("Authorization")
+		_ = json.Unmarshal(body, &payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	exporter := NewOTLPHTTPExporter(OTLPHTTPExporterOptions{
+		Endpoint:    collector.URL,
+		Headers:     map[string]string{"Authorization": "Bearer abc"},
+		ServiceName: "archon-test",
+	})
+	provider := NewProvider(exporter, ProviderOptions{})
+	ctx, span := provider.Start(context.Background(), "session.turn", SpanKindInternal, Int("attempt", 2), Bool("retry", true))
+	span.End()
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if auth != "Bearer abc" {
+		t.Fatalf("expected configured headers to be sent, got %q", auth)
+	}
+	if len(payload.ResourceSpans) != 1 || len(payload.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("unexpected payload shape: %#v", payload)
+	}
+	resource := payload.ResourceSpans[0].Resource.Attributes
+	if len(resource) != 1 || resource[0].Key != "service.name" || *resource[0].Value.StringValue != "archon-test" {
+		t.Fatalf("unexpected resource attributes: %#v", resource)
+	}
+	spans := payload.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 1 || spans[0].Name != "session.turn" {
+		t.Fatalf("unexpected spans: %#v", spans)
+	}
+	if spans[0].TraceID != TraceIDFromContext(ctx) {
+		t.Fatalf("expected exported trace id %q, got %q", TraceIDFromContext(ctx), spans[0].TraceID)
+	}
+	if spans[0].Attributes[0].Value.IntValue == nil || *spans[0].Attributes[0].Value.IntValue != "2" {
+		t.Fatalf("expected int attribute to be encoded as string, got %#v", spans[0].Attributes[0])
+	}
+}
+
+func TestMiddlewareContinuesIncomingTrace(t *testing.T) {
+	exporter := &recordingExporter{}
+	provider := NewProvider(exporter, ProviderOptions{})
+
+	var handlerTrace string
+	handler := Middleware(provider, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerTrace = TraceIDFromContext(r.Context())
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/s1/send", nil)
+	req.Header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	if handlerTrace != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("expected handler to see incoming trace, got %q", handlerTrace)
+	}
+	spans := exporter.Spans()
+	if len(spans) != 1 || spans[0].Name != "POST /v1/sessions" || spans[0].Kind != SpanKindServer {
+		t.Fatalf("unexpected server span: %#v", spans)
+	}
+}
+
+type recordingExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+func (e *recordingExporter) Export(_ context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+func (e *recordingExporter) Spans() []SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]SpanData(nil), e.spans...)
+}
@@ -0,0 +1,192 @@
+package transcriptexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatMarkdown Format = "md"
+	FormatHTML     Format = "html"
+	FormatJSON     Format = "json"
+)
+
+func ParseFormat(raw string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", "md", "markdown":
+		return FormatMarkdown, nil
+	case "html", "htm":
+		return FormatHTML, nil
+	case "json":
+		return FormatJSON, nil
+	}
+	return "", fmt.Errorf("unsupported export format %q: use md, html or json", raw)
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	case FormatJSON:
+		return "application/json"
+	}
+	return "text/markdown; charset=utf-8"
+}
+
+func (f Format) Extension() string {
+	if f == FormatMarkdown {
+		return ".md"
+	}
+	return "." + string(f)
+}
+
+// Options selects which parts of a transcript are exported. Messages are
+// always included.
+type Options struct {
+	Reasoning  bool
+	ToolCalls  bool
+	Approvals  bool
+	Timestamps bool
+}
+
+func DefaultOptions() Options {
+	return Options{ToolCalls: true, Approvals: true, Timestamps: true}
+}
+
+type EntryKind string
+
+const (
+	EntryMessage   EntryKind = "message"
+	EntryReasoning EntryKind = "reasoning"
+	EntryTool      EntryKind = "tool"
+	EntryApproval  EntryKind = "approval"
+)
+
+type Entry struct {
+	Kind      EntryKind  `json:"kind"`
+	Role      string     `json:"role,omitempty"`
+	Type      string     `json:"type,omitempty"`
+	Text      string     `json:"text"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// Document is a session transcript prepared for export. It is the body of
+// the json format and the input of the other renderers.
+type Document struct {
+	SessionID  string    `json:"session_id"`
+	Title      string    `json:"title,omitempty"`
+	Provider   string    `json:"provider,omitempty"`
+	Cwd        string    `json:"cwd,omitempty"`
+	ExportedAt time.Time `json:"exported_at"`
+	Entries    []Entry   `json:"entries"`
+}
+
+func (d Document) DisplayTitle() string {
+	if title := strings.TrimSpace(d.Title); title != "" {
+		return title
+	}
+	return "Session " + d.SessionID
+}
+
+func Render(doc Document, format Format, theme Theme) ([]byte, error) {
+	switch format {
+	case FormatMarkdown:
+		return []byte(Markdown(doc)), nil
+	case FormatHTML:
+		return []byte(HTML(doc, theme)), nil
+	case FormatJSON:
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+func Markdown(doc Document) string {
+	var b strings.Builder
+	b.WriteString("# " + doc.DisplayTitle() + "\n\n")
+	for _, line := range headerLines(doc) {
+		b.WriteString("- " + line + "\n")
+	}
+	for _, entry := range doc.Entries {
+		b.WriteString("\n## " + entryHeading(entry))
+		if entry.CreatedAt != nil {
+			b.WriteString(" · " + formatTimestamp(*entry.CreatedAt))
+		}
+		b.WriteString("\n\n")
+		text := strings.TrimSpace(entry.Text)
+		switch entry.Kind {
+		case EntryMessage:
+			b.WriteString(text + "\n")
+		case EntryReasoning:
+			for _, line := range strings.Split(text, "\n") {
+				b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+			}
+		default:
+			fence := markdownFence(text)
+			b.WriteString(fence + "\n" + text + "\n" + fence + "\n")
+		}
+	}
+	return b.String()
+}
+
+func headerLines(doc Document) []string {
+	lines := []string{"Session: " + doc.SessionID}
+	if doc.Provider != "" {
+		lines = append(lines, "Provider: "+doc.Provider)
+	}
+	if doc.Cwd != "" {
+		lines = append(lines, "Directory: "+doc.Cwd)
+	}
+	if !doc.ExportedAt.IsZero() {
+		lines = append(lines, "Exported: "+formatTimestamp(doc.ExportedAt))
+	}
+	return lines
+}
+
+func entryHeading(entry Entry) string {
+	switch entry.Kind {
+	case EntryReasoning:
+		return "Reasoning"
+	case EntryTool:
+		if entry.Type != "" {
+			return "Tool · " + entry.Type
+		}
+		return "Tool"
+	case EntryApproval:
+		return "Approval"
+	}
+	switch entry.Role {
+	case "user":
+		return "User"
+	case "assistant":
+		return "Assistant"
+	case "":
+		return "Message"
+	}
+	return strings.ToUpper(entry.Role[:1]) + entry.Role[1:]
+}
+
+// markdownFence returns a code fence longer than any backtick run in text.
+func markdownFence(text string) string {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+			continue
+		}
+		run = 0
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+func formatTimestamp(when time.Time) string {
+	return when.UTC().Format("2006-01-02 15:04:05 UTC")
+}
@@ -0,0 +1,80 @@
+package transcriptexport
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testDocument() Document {
+	at := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	return Document{
+		SessionID: "s1",
+		Title:     "Auth refactor",
+		Provider:  "codex",
+		Entries: []Entry{
+			{Kind: EntryMessage, Role: "user", Text: "fix <login>", CreatedAt: &at},
+			{Kind: EntryReasoning, Role: "reasoning", Text: "check the handler\nthen the store"},
+			{Kind: EntryTool, Type: "commandExecution", Text: "go test ./...\n```ok```"},
+			{Kind: EntryMessage, Role: "assistant", Text: "Done."},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for raw, want := range map[string]Format{"": FormatMarkdown, "markdown": FormatMarkdown, "HTML": FormatHTML, "json": FormatJSON} {
+		got, err := ParseFormat(raw)
+		if err != nil || got != want {
+			t.Fatalf("%q: got %q, %v", raw, got, err)
+		}
+	}
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Fatalf("expected error for pdf")
+	}
+}
+
+func TestMarkdownRendersEntriesByKind(t *testing.T) {
+	out := Markdown(testDocument())
+
+	for _, want := range []string{
+		"# Auth refactor\n",
+		"- Provider: codex\n",
+		"## User · 2026-03-04 05:06:07 UTC\n\nfix <login>\n",
+		"## Reasoning\n\n> check the handler\n> then the store\n",
+		"## Tool · commandExecution\n\n````\ngo test ./...\n```ok```\n````\n",
+		"## Assistant\n\nDone.\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in markdown:\n%s", want, out)
+		}
+	}
+}
+
+func TestHTMLIsSelfContainedAndEscaped(t *testing.T) {
+	out := HTML(testDocument(), Theme{Background: "#2e3440"})
+
+	if !strings.Contains(out, "fix &lt;login&gt;") || strings.Contains(out, "<login>") {
+		t.Fatalf("expected escaped message text")
+	}
+	if !strings.Contains(out, "background: #2e3440") || !strings.Contains(out, DefaultTheme().Approval) {
+		t.Fatalf("expected theme colors with defaults filled in")
+	}
+	if strings.Contains(out, "<link") || strings.Contains(out, "<script") {
+		t.Fatalf("expected no external resources")
+	}
+}
+
+func TestRenderJSONRoundTrips(t *testing.T) {
+	data, err := Render(testDocument(), FormatJSON, Theme{})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if doc.SessionID != "s1" || len(doc.Entries) != 4 || doc.Entries[0].CreatedAt == nil {
+		t.Fatalf("unexpected document %#v", doc)
+	}
+}
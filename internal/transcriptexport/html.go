@@ -0,0 +1,140 @@
+package transcriptexport
+
+import (
+	"html/template"
+	"strings"
+)
+
+// Theme holds the CSS colors of an HTML export. Values are "#rrggbb".
+type Theme struct {
+	Background  string
+	Surface     string
+	UserSurface string
+	Text        string
+	Muted       string
+	Accent      string
+	Border      string
+	User        string
+	Assistant   string
+	Reasoning   string
+	Tool        string
+	Approval    string
+}
+
+// DefaultTheme matches the UI's default theme.
+func DefaultTheme() Theme {
+	return Theme{
+		Background:  "#1c1c1c",
+		Surface:     "#262626",
+		UserSurface: "#303030",
+		Text:        "#d0d0d0",
+		Muted:       "#808080",
+		Accent:      "#5f5fff",
+		Border:      "#444444",
+		User:        "#585858",
+		Assistant:   "#444444",
+		Reasoning:   "#3a3a3a",
+		Tool:        "#3a3a3a",
+		Approval:    "#d7af5f",
+	}
+}
+
+func (t Theme) withDefaults() Theme {
+	defaults := DefaultTheme()
+	fill := func(value *string, fallback string) {
+		if strings.TrimSpace(*value) == "" {
+			*value = fallback
+		}
+	}
+	fill(&t.Background, defaults.Background)
+	fill(&t.Surface, defaults.Surface)
+	fill(&t.UserSurface, defaults.UserSurface)
+	fill(&t.Text, defaults.Text)
+	fill(&t.Muted, defaults.Muted)
+	fill(&t.Accent, defaults.Accent)
+	fill(&t.Border, defaults.Border)
+	fill(&t.User, defaults.User)
+	fill(&t.Assistant, defaults.Assistant)
+	fill(&t.Reasoning, defaults.Reasoning)
+	fill(&t.Tool, defaults.Tool)
+	fill(&t.Approval, defaults.Approval)
+	return t
+}
+
+type htmlEntry struct {
+	Class   string
+	Heading string
+	Time    string
+	Text    string
+}
+
+type htmlPage struct {
+	Title   string
+	Header  []string
+	Entries []htmlEntry
+	Theme   Theme
+}
+
+var htmlTemplate = template.Must(template.New("transcript").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { margin: 0; padding: 2rem 1rem; background: {{.Theme.Background}}; color: {{.Theme.Text}}; font: 15px/1.5 -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; }
+main { max-width: 52rem; margin: 0 auto; }
+h1 { color: {{.Theme.Accent}}; font-size: 1.5rem; margin: 0 0 .5rem; }
+.meta { color: {{.Theme.Muted}}; font-size: .85rem; margin: 0 0 1.5rem; padding: 0; list-style: none; }
+.entry { background: {{.Theme.Surface}}; border: 1px solid {{.Theme.Border}}; border-left: 4px solid {{.Theme.Assistant}}; border-radius: 6px; margin: 0 0 1rem; padding: .75rem 1rem; }
+.entry header { color: {{.Theme.Muted}}; font-size: .8rem; margin-bottom: .4rem; display: flex; justify-content: space-between; gap: 1rem; }
+.entry .text { white-space: pre-wrap; overflow-wrap: anywhere; margin: 0; font: inherit; }
+.user { background: {{.Theme.UserSurface}}; border-left-color: {{.Theme.User}}; }
+.reasoning { border-left-color: {{.Theme.Reasoning}}; color: {{.Theme.Muted}}; font-style: italic; }
+.tool { border-left-color: {{.Theme.Tool}}; }
+.tool .text, .approval .text { font: 13px/1.45 ui-monospace, SFMono-Regular, Menlo, monospace; }
+.approval { border-left-color: {{.Theme.Approval}}; }
+.approval header { color: {{.Theme.Approval}}; }
+</style>
+</head>
+<body>
+<main>
+<h1>{{.Title}}</h1>
+<ul class="meta">{{range .Header}}<li>{{.}}</li>{{end}}</ul>
+{{range .Entries}}<section class="entry {{.Class}}">
+<header><span>{{.Heading}}</span>{{if .Time}}<time>{{.Time}}</time>{{end}}</header>
+<pre class="text">{{.Text}}</pre>
+</section>
+{{end}}</main>
+</body>
+</html>
+`))
+
+// HTML renders doc as a single self-contained page styled with theme.
+func HTML(doc Document, theme Theme) string {
+	page := htmlPage{
+		Title:  doc.DisplayTitle(),
+		Header: headerLines(doc),
+		Theme:  theme.withDefaults(),
+	}
+	for _, entry := range doc.Entries {
+		class := string(entry.Kind)
+		if entry.Kind == EntryMessage && entry.Role == "user" {
+			class = "user"
+		}
+		item := htmlEntry{
+			Class:   class,
+			Heading: entryHeading(entry),
+			Text:    strings.TrimSpace(entry.Text),
+		}
+		if entry.CreatedAt != nil {
+			item.Time = formatTimestamp(*entry.CreatedAt)
+		}
+		page.Entries = append(page.Entries, item)
+	}
+	var b strings.Builder
+	if err := htmlTemplate.Execute(&b, page); err != nil {
+		return ""
+	}
+	return b.String()
+}
@@ -13,6 +13,7 @@ type AppState struct {
 	NotesPanelWidth                int                               `json:"notes_panel_width,omitempty"`
 	DebugPanelWidth                int                               `json:"debug_panel_width,omitempty"`
 	ContextPanelWidth              int                               `json:"context_panel_width,omitempty"`
+	DiffPanelWidth                 int                               `json:"diff_panel_width,omitempty"`
 	DebugStreamsEnabled            bool                              `json:"debug_streams_enabled,omitempty"`
 	ContextPanelHidden             bool                              `json:"context_panel_hidden,omitempty"`
 	SidebarWorkspaceExpanded       map[string]bool                   `json:"sidebar_workspace_expanded,omitempty"`
@@ -28,6 +29,7 @@ type AppState struct {
 	ProviderBadges                 map[string]*ProviderBadgeConfig   `json:"provider_badges,omitempty"`
 	Recents                        *AppStateRecents                  `json:"recents,omitempty"`
 	GuidedWorkflowTelemetry        *GuidedWorkflowTelemetryState     `json:"guided_workflow_telemetry,omitempty"`
+	Tiles                          *AppStateTiles                    `json:"tiles,omitempty"`
 }
 
 type AppStateSplitPreference struct {
@@ -35,6 +37,12 @@ type AppStateSplitPreference struct {
 	Ratio   float64 `json:"ratio,omitempty"`
 }
 
+type AppStateTiles struct {
+	SessionIDs []string `json:"session_ids,omitempty"`
+	Columns    int      `json:"columns,omitempty"`
+	FocusedID  string   `json:"focused_id,omitempty"`
+}
+
 type ProviderBadgeConfig struct {
 	Prefix string `json:"prefix,omitempty"`
 	Color  string `json:"color,omitempty"`
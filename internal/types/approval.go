@@ -12,3 +12,15 @@ type Approval struct {
 	Params    json.RawMessage `json:"params,omitempty"`
 	CreatedAt time.Time       `json:"created_at"`
 }
+
+// PendingApproval is an approval request listed across sessions together
+// with the session it blocks.
+type PendingApproval struct {
+	Approval
+	Provider      string `json:"provider,omitempty"`
+	SessionTitle  string `json:"session_title,omitempty"`
+	WorkspaceID   string `json:"workspace_id,omitempty"`
+	WorkspaceName string `json:"workspace_name,omitempty"`
+	// Preview is the command, the changed paths or the approval kind.
+	Preview string `json:"preview,omitempty"`
+}
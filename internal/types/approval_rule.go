@@ -0,0 +1,378 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type ApprovalRuleScope string
+
+const (
+	ApprovalRuleScopeGlobal    ApprovalRuleScope = "global"
+	ApprovalRuleScopeProvider  ApprovalRuleScope = "provider"
+	ApprovalRuleScopeWorkspace ApprovalRuleScope = "workspace"
+	ApprovalRuleScopeSession   ApprovalRuleScope = "session"
+)
+
+type ApprovalRuleDecision string
+
+const (
+	ApprovalRuleAllowOnce   ApprovalRuleDecision = "allow_once"
+	ApprovalRuleAllowAlways ApprovalRuleDecision = "allow_always"
+	ApprovalRuleDeny        ApprovalRuleDecision = "deny"
+	ApprovalRuleAsk         ApprovalRuleDecision = "ask"
+)
+
+// Approval kinds group provider approval methods so that one rule can match
+// the same kind of request from every provider.
+const (
+	ApprovalKindCommand    = "command"
+	ApprovalKindFileChange = "file_change"
+	ApprovalKindUserInput  = "user_input"
+	ApprovalKindPlan       = "plan"
+)
+
+// ApprovalRule decides approval requests without asking. Every matcher that
+// is set must match; the first matching rule wins, see SortApprovalRules.
+type ApprovalRule struct {
+	ID            string               `json:"id"`
+	Name          string               `json:"name,omitempty"`
+	Scope         ApprovalRuleScope    `json:"scope"`
+	WorkspaceID   string               `json:"workspace_id,omitempty"`
+	Provider      string               `json:"provider,omitempty"`
+	SessionID     string               `json:"session_id,omitempty"`
+	Method        string               `json:"method,omitempty"`
+	CommandPrefix string               `json:"command_prefix,omitempty"`
+	CommandRegex  string               `json:"command_regex,omitempty"`
+	PathGlobs     []string             `json:"path_globs,omitempty"`
+	Access        AccessLevel          `json:"access,omitempty"`
+	Decision      ApprovalRuleDecision `json:"decision"`
+	Disabled      bool                 `json:"disabled,omitempty"`
+	CreatedAt     time.Time            `json:"created_at,omitempty"`
+	UpdatedAt     time.Time            `json:"updated_at,omitempty"`
+
+	// commandPattern and pathPatterns cache the compiled matchers of a
+	// validated rule.
+	commandPattern *regexp.Regexp
+	pathPatterns   []*regexp.Regexp
+}
+
+// ApprovalRuleSubject is the approval request a rule is matched against.
+type ApprovalRuleSubject struct {
+	WorkspaceID string
+	Provider    string
+	SessionID   string
+	Method      string
+	Kind        string
+	Command     string
+	Paths       []string
+	Access      AccessLevel
+}
+
+func NormalizeApprovalRuleDecision(raw ApprovalRuleDecision) (ApprovalRuleDecision, bool) {
+	value := strings.ToLower(strings.TrimSpace(string(raw)))
+	value = strings.ReplaceAll(value, "-", "_")
+	switch value {
+	case "allow_once", "allow", "approve", "accept":
+		return ApprovalRuleAllowOnce, true
+	case "allow_always", "always":
+		return ApprovalRuleAllowAlways, true
+	case "deny", "decline", "reject":
+		return ApprovalRuleDeny, true
+	case "ask":
+		return ApprovalRuleAsk, true
+	default:
+		return "", false
+	}
+}
+
+// ApprovalRuleLabel returns the name a rule is shown by.
+func ApprovalRuleLabel(rule *ApprovalRule) string {
+	if rule == nil {
+		return ""
+	}
+	if name := strings.TrimSpace(rule.Name); name != "" {
+		return name
+	}
+	return rule.ID
+}
+
+// ValidateApprovalRule normalizes rule in place and reports the first
+// problem. The scope is inferred from the id that is set when empty.
+func ValidateApprovalRule(rule *ApprovalRule) error {
+	if rule == nil {
+		return errors.New("rule is required")
+	}
+	rule.Name = strings.TrimSpace(rule.Name)
+	rule.WorkspaceID = strings.TrimSpace(rule.WorkspaceID)
+	rule.Provider = strings.ToLower(strings.TrimSpace(rule.Provider))
+	rule.SessionID = strings.TrimSpace(rule.SessionID)
+	rule.Method = strings.TrimSpace(rule.Method)
+	rule.CommandPrefix = strings.TrimSpace(rule.CommandPrefix)
+	rule.CommandRegex = strings.TrimSpace(rule.CommandRegex)
+	globs := rule.PathGlobs[:0:0]
+	for _, glob := range rule.PathGlobs {
+		if glob = strings.TrimSpace(glob); glob != "" {
+			globs = append(globs, glob)
+		}
+	}
+	rule.PathGlobs = globs
+
+	decision, ok := NormalizeApprovalRuleDecision(rule.Decision)
+	if !ok {
+		return fmt.Errorf("invalid decision %q: use allow_once, allow_always, deny or ask", rule.Decision)
+	}
+	rule.Decision = decision
+	access, ok := NormalizeAccessLevel(rule.Access)
+	if !ok {
+		return fmt.Errorf("invalid access level %q", rule.Access)
+	}
+	rule.Access = access
+	rule.commandPattern, rule.pathPatterns = nil, nil
+	if rule.CommandRegex != "" {
+		pattern, err := regexp.Compile(rule.CommandRegex)
+		if err != nil {
+			return fmt.Errorf("invalid command regex: %w", err)
+		}
+		rule.commandPattern = pattern
+	}
+	patterns := make([]*regexp.Regexp, 0, len(rule.PathGlobs))
+	for _, glob := range rule.PathGlobs {
+		pattern, err := pathGlobPattern(glob)
+		if err != nil {
+			return fmt.Errorf("invalid path glob %q: %w", glob, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	rule.pathPatterns = patterns
+
+	if rule.Scope == "" {
+		switch {
+		case rule.SessionID != "":
+			rule.Scope = ApprovalRuleScopeSession
+		case rule.WorkspaceID != "":
+			rule.Scope = ApprovalRuleScopeWorkspace
+		case rule.Provider != "":
+			rule.Scope = ApprovalRuleScopeProvider
+		default:
+			rule.Scope = ApprovalRuleScopeGlobal
+		}
+	}
+	rule.Scope = ApprovalRuleScope(strings.ToLower(strings.TrimSpace(string(rule.Scope))))
+	switch rule.Scope {
+	case ApprovalRuleScopeGlobal:
+		rule.WorkspaceID, rule.Provider, rule.SessionID = "", "", ""
+	case ApprovalRuleScopeProvider:
+		if rule.Provider == "" {
+			return errors.New("provider is required for provider rules")
+		}
+		rule.WorkspaceID, rule.SessionID = "", ""
+	case ApprovalRuleScopeWorkspace:
+		if rule.WorkspaceID == "" {
+			return errors.New("workspace_id is required for workspace rules")
+		}
+		rule.Provider, rule.SessionID = "", ""
+	case ApprovalRuleScopeSession:
+		if rule.SessionID == "" {
+			return errors.New("session_id is required for session rules")
+		}
+		rule.WorkspaceID, rule.Provider = "", ""
+	default:
+		return fmt.Errorf("invalid scope %q", rule.Scope)
+	}
+	if rule.Scope == ApprovalRuleScopeGlobal && rule.Decision != ApprovalRuleAsk && !rule.hasMatchers() {
+		return errors.New("global rules need at least one matcher")
+	}
+	return nil
+}
+
+func (r *ApprovalRule) hasMatchers() bool {
+	return r.Method != "" || r.CommandPrefix != "" || r.CommandRegex != "" || len(r.PathGlobs) > 0 || r.Access != ""
+}
+
+// Matches reports whether the rule applies to subject. Disabled rules never
+// match. Path globs of a deny rule match when any path matches, so touching
+// a denied file is enough; other rules need every path to match. Rules that
+// passed ValidateApprovalRule reuse their compiled matchers.
+func (r *ApprovalRule) Matches(subject ApprovalRuleSubject) bool {
+	if r == nil || r.Disabled {
+		return false
+	}
+	switch r.Scope {
+	case ApprovalRuleScopeProvider:
+		if !strings.EqualFold(r.Provider, strings.TrimSpace(subject.Provider)) {
+			return false
+		}
+	case ApprovalRuleScopeWorkspace:
+		if r.WorkspaceID != strings.TrimSpace(subject.WorkspaceID) {
+			return false
+		}
+	case ApprovalRuleScopeSession:
+		if r.SessionID != strings.TrimSpace(subject.SessionID) {
+			return false
+		}
+	}
+	if r.Method != "" && !strings.EqualFold(r.Method, subject.Method) && !strings.EqualFold(r.Method, subject.Kind) {
+		return false
+	}
+	if r.Access != "" && r.Access != subject.Access {
+		return false
+	}
+	command := strings.TrimSpace(subject.Command)
+	if r.CommandPrefix != "" && !commandHasPrefix(command, r.CommandPrefix) {
+		return false
+	}
+	if r.CommandRegex != "" {
+		pattern := r.commandPattern
+		if pattern == nil {
+			var err error
+			if pattern, err = regexp.Compile(r.CommandRegex); err != nil {
+				return false
+			}
+		}
+		if command == "" || !pattern.MatchString(command) {
+			return false
+		}
+	}
+	if len(r.PathGlobs) > 0 {
+		patterns := r.pathPatterns
+		if len(patterns) != len(r.PathGlobs) {
+			patterns = compilePathGlobs(r.PathGlobs)
+		}
+		if !pathsMatchPatterns(subject.Paths, patterns, r.Decision != ApprovalRuleDeny) {
+			return false
+		}
+	}
+	return true
+}
+
+// commandHasPrefix matches whole words of a simple command. Commands that
+// chain, pipe, redirect or substitute never match a prefix, so "go test"
+// does not allow "go test ./... && rm -rf ~"; use a regex for those.
+func commandHasPrefix(command, prefix string) bool {
+	if command == "" || strings.ContainsAny(command, ";&|`<>\n") || strings.Contains(command, "$(") {
+		return false
+	}
+	if !strings.HasPrefix(command, prefix) {
+		return false
+	}
+	rest := command[len(prefix):]
+	return rest == "" || unicode.IsSpace(rune(rest[0])) || strings.HasSuffix(prefix, " ")
+}
+
+// pathsMatchPatterns reports whether every path, or with all unset any
+// path, matches at least one pattern.
+func pathsMatchPatterns(paths []string, patterns []*regexp.Regexp, all bool) bool {
+	if len(paths) == 0 {
+		return false
+	}
+	for _, path := range paths {
+		matched := false
+		for _, pattern := range patterns {
+			if pattern.MatchString(strings.TrimSpace(path)) {
+				matched = true
+				break
+			}
+		}
+		if matched && !all {
+			return true
+		}
+		if !matched && all {
+			return false
+		}
+	}
+	return all
+}
+
+// compilePathGlobs compiles the globs of a rule that skipped validation,
+// dropping invalid ones.
+func compilePathGlobs(globs []string) []*regexp.Regexp {
+	patterns := make([]*regexp.Regexp, 0, len(globs))
+	for _, glob := range globs {
+		if pattern, err := pathGlobPattern(glob); err == nil {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// pathGlobPattern compiles a path glob: "*" and "?" stay within one path
+// segment and "**" spans segments. Globs without a slash match the base
+// name in any directory.
+func pathGlobPattern(glob string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	if !strings.Contains(glob, "/") {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+				continue
+			}
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+func approvalRuleScopeRank(scope ApprovalRuleScope) int {
+	switch scope {
+	case ApprovalRuleScopeSession:
+		return 0
+	case ApprovalRuleScopeWorkspace:
+		return 1
+	case ApprovalRuleScopeProvider:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// SortApprovalRules orders rules by evaluation precedence: session rules
+// first, then workspace, provider and global rules, oldest first within a
+// scope.
+func SortApprovalRules(rules []*ApprovalRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		left, right := approvalRuleScopeRank(rules[i].Scope), approvalRuleScopeRank(rules[j].Scope)
+		if left != right {
+			return left < right
+		}
+		if !rules[i].CreatedAt.Equal(rules[j].CreatedAt) {
+			return rules[i].CreatedAt.Before(rules[j].CreatedAt)
+		}
+		return rules[i].ID < rules[j].ID
+	})
+}
+
+// MatchApprovalRule returns the first rule in precedence order that matches
+// subject. A matching ask rule is returned too; it stops broader rules from
+// deciding.
+func MatchApprovalRule(rules []*ApprovalRule, subject ApprovalRuleSubject) *ApprovalRule {
+	sorted := append([]*ApprovalRule(nil), rules...)
+	SortApprovalRules(sorted)
+	for _, rule := range sorted {
+		if rule.Matches(subject) {
+			return rule
+		}
+	}
+	return nil
+}
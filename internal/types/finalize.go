@@ -0,0 +1,54 @@
+package types
+
+const (
+	FinalizeMessageSourceProvider = "provider"
+	FinalizeMessageSourceSession  = "session"
+	FinalizeMessageSourceSummary  = "summary"
+)
+
+// FinalizeDraft is what committing a finished session or workflow run would
+// record: the pending changes, a drafted Conventional Commit message and a
+// Markdown pull request description.
+type FinalizeDraft struct {
+	SessionID     string   `json:"session_id,omitempty"`
+	RunID         string   `json:"run_id,omitempty"`
+	Root          string   `json:"root"`
+	Branch        string   `json:"branch,omitempty"`
+	Diff          *GitDiff `json:"diff"`
+	Message       string   `json:"message"`
+	MessageSource string   `json:"message_source"`
+	MessageError  string   `json:"message_error,omitempty"`
+	PullRequest   string   `json:"pull_request"`
+}
+
+// FinalizeRequest commits the pending changes. An empty Message uses a fresh
+// draft. With PullRequest the result carries the rendered description.
+type FinalizeRequest struct {
+	Message     string `json:"message,omitempty"`
+	PullRequest bool   `json:"pull_request,omitempty"`
+}
+
+type FinalizeResult struct {
+	SessionID   string   `json:"session_id,omitempty"`
+	RunID       string   `json:"run_id,omitempty"`
+	Root        string   `json:"root"`
+	Branch      string   `json:"branch,omitempty"`
+	Commit      string   `json:"commit"`
+	Message     string   `json:"message"`
+	Files       []string `json:"files"`
+	PullRequest string   `json:"pull_request,omitempty"`
+	// Linked lists the commits made in linked repositories. Commit is empty
+	// when only linked repositories had changes.
+	Linked []FinalizeLinkedCommit `json:"linked,omitempty"`
+}
+
+// FinalizeLinkedCommit is the outcome in one linked repository. Error is set
+// and Commit empty when committing there failed.
+type FinalizeLinkedCommit struct {
+	Name   string   `json:"name"`
+	Root   string   `json:"root"`
+	Branch string   `json:"branch,omitempty"`
+	Commit string   `json:"commit"`
+	Files  []string `json:"files"`
+	Error  string   `json:"error,omitempty"`
+}
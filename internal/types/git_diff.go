@@ -0,0 +1,74 @@
+package types
+
+type GitDiffFileStatus string
+
+const (
+	GitDiffFileAdded    GitDiffFileStatus = "added"
+	GitDiffFileModified GitDiffFileStatus = "modified"
+	GitDiffFileDeleted  GitDiffFileStatus = "deleted"
+	GitDiffFileRenamed  GitDiffFileStatus = "renamed"
+	GitDiffFileCopied   GitDiffFileStatus = "copied"
+)
+
+type GitDiffLineKind string
+
+const (
+	GitDiffLineContext GitDiffLineKind = "context"
+	GitDiffLineAdded   GitDiffLineKind = "added"
+	GitDiffLineDeleted GitDiffLineKind = "deleted"
+)
+
+// GitDiff is the working tree of a repository compared against Base (HEAD
+// unless a base ref was requested). Untracked files are listed separately and
+// carry no hunks. Changes of linked repositories are merged in with their
+// paths prefixed by the linked repository's name; Linked lists those
+// repositories.
+type GitDiff struct {
+	Root        string        `json:"root"`
+	Base        string        `json:"base"`
+	BaseCommit  string        `json:"base_commit,omitempty"`
+	Files       []GitDiffFile `json:"files"`
+	Untracked   []string      `json:"untracked,omitempty"`
+	Stats       GitDiffStats  `json:"stats"`
+	Truncated   bool          `json:"truncated,omitempty"`
+	Linked      []GitDiffRepo `json:"linked,omitempty"`
+	GeneratedAt string        `json:"generated_at"`
+}
+
+// GitDiffRepo is a linked repository whose changes are part of a GitDiff.
+type GitDiffRepo struct {
+	Name       string `json:"name"`
+	Root       string `json:"root"`
+	BaseCommit string `json:"base_commit,omitempty"`
+}
+
+type GitDiffStats struct {
+	Files     int `json:"files"`
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+	Untracked int `json:"untracked"`
+}
+
+type GitDiffFile struct {
+	Path      string            `json:"path"`
+	OldPath   string            `json:"old_path,omitempty"`
+	Status    GitDiffFileStatus `json:"status"`
+	Binary    bool              `json:"binary,omitempty"`
+	Additions int               `json:"additions"`
+	Deletions int               `json:"deletions"`
+	Hunks     []GitDiffHunk     `json:"hunks,omitempty"`
+}
+
+type GitDiffHunk struct {
+	Header   string        `json:"header"`
+	OldStart int           `json:"old_start"`
+	OldLines int           `json:"old_lines"`
+	NewStart int           `json:"new_start"`
+	NewLines int           `json:"new_lines"`
+	Lines    []GitDiffLine `json:"lines"`
+}
+
+type GitDiffLine struct {
+	Kind GitDiffLineKind `json:"kind"`
+	Text string          `json:"text"`
+}
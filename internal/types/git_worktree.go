@@ -6,3 +6,57 @@ type GitWorktree struct {
 	Head     string `json:"head,omitempty"`
 	Detached bool   `json:"detached,omitempty"`
 }
+
+type WorktreeOperation string
+
+const (
+	WorktreeOperationRemove WorktreeOperation = "remove"
+	WorktreeOperationPrune  WorktreeOperation = "prune"
+	WorktreeOperationMerge  WorktreeOperation = "merge"
+	WorktreeOperationRebase WorktreeOperation = "rebase"
+	WorktreeOperationPush   WorktreeOperation = "push"
+)
+
+type WorktreeOperationStatus string
+
+const (
+	WorktreeOperationOK       WorktreeOperationStatus = "ok"
+	WorktreeOperationConflict WorktreeOperationStatus = "conflict"
+	WorktreeOperationDirty    WorktreeOperationStatus = "dirty"
+	WorktreeOperationRejected WorktreeOperationStatus = "rejected"
+	WorktreeOperationFailed   WorktreeOperationStatus = "failed"
+)
+
+// WorktreeOperationRequest carries the options of a worktree lifecycle
+// operation. Force removes dirty worktrees or pushes with --force-with-lease;
+// Target overrides the workspace's main branch for merge and rebase.
+// Linked worktrees use the branch checked out in their linked repo, or
+// LinkedTargets keyed by linked repo name.
+type WorktreeOperationRequest struct {
+	Force         bool              `json:"force,omitempty"`
+	Remote        string            `json:"remote,omitempty"`
+	Target        string            `json:"target,omitempty"`
+	LinkedTargets map[string]string `json:"linked_targets,omitempty"`
+}
+
+// WorktreeOperationResult reports the outcome of a worktree lifecycle
+// operation. Merges and rebases that hit conflicts are aborted and list the
+// conflicting paths; operations refused because of uncommitted changes list
+// the dirty paths. Linked holds the outcome for each linked worktree; Status
+// is the first non-ok status across the set. A linked worktree that failed
+// after the primary one succeeded is reported as failed with the error in
+// Output.
+type WorktreeOperationResult struct {
+	Operation   WorktreeOperation          `json:"operation"`
+	Status      WorktreeOperationStatus    `json:"status"`
+	WorkspaceID string                     `json:"workspace_id"`
+	WorktreeID  string                     `json:"worktree_id,omitempty"`
+	Path        string                     `json:"path,omitempty"`
+	Branch      string                     `json:"branch,omitempty"`
+	Target      string                     `json:"target,omitempty"`
+	Conflicts   []string                   `json:"conflicts,omitempty"`
+	DirtyFiles  []string                   `json:"dirty_files,omitempty"`
+	Pruned      []string                   `json:"pruned,omitempty"`
+	Output      string                     `json:"output,omitempty"`
+	Linked      []*WorktreeOperationResult `json:"linked,omitempty"`
+}
@@ -0,0 +1,133 @@
+package types
+
+import (
+	"mime"
+	"path/filepath"
+	"strings"
+)
+
+// InputItemTypeAttachment is the type of send input items that attach a
+// local file to a message. The daemon maps them to each provider's native
+// image or file input.
+const InputItemTypeAttachment = "attachment"
+
+type InputAttachmentKind string
+
+const (
+	InputAttachmentImage InputAttachmentKind = "image"
+	InputAttachmentFile  InputAttachmentKind = "file"
+)
+
+// InputAttachment is a local file attached to a message. Path is absolute;
+// the daemon reads it when the message is sent.
+type InputAttachment struct {
+	Kind     InputAttachmentKind `json:"kind"`
+	Path     string              `json:"path"`
+	Name     string              `json:"name,omitempty"`
+	MimeType string              `json:"mime_type,omitempty"`
+}
+
+// NewInputAttachment describes the file at path, guessing its MIME type from
+// the extension. Files with an image MIME type are attached as images.
+func NewInputAttachment(path string) InputAttachment {
+	path = strings.TrimSpace(path)
+	mimeType := attachmentMimeType(path)
+	kind := InputAttachmentFile
+	if strings.HasPrefix(mimeType, "image/") {
+		kind = InputAttachmentImage
+	}
+	return InputAttachment{
+		Kind:     kind,
+		Path:     path,
+		Name:     filepath.Base(path),
+		MimeType: mimeType,
+	}
+}
+
+func attachmentMimeType(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	switch ext {
+	case "":
+		return "text/plain"
+	case ".md", ".log", ".txt":
+		return "text/plain"
+	}
+	mimeType := mime.TypeByExtension(ext)
+	if mimeType == "" {
+		return "application/octet-stream"
+	}
+	if base, _, ok := strings.Cut(mimeType, ";"); ok {
+		mimeType = base
+	}
+	return strings.TrimSpace(mimeType)
+}
+
+// DisplayName is the attachment's label in chips and transcripts.
+func (a InputAttachment) DisplayName() string {
+	if name := strings.TrimSpace(a.Name); name != "" {
+		return name
+	}
+	return filepath.Base(strings.TrimSpace(a.Path))
+}
+
+// AttachmentNote is the line transcripts show for the attachments of a user
+// message.
+func AttachmentNote(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	return "[attached: " + strings.Join(names, ", ") + "]"
+}
+
+// InputItem encodes the attachment as a send input item.
+func (a InputAttachment) InputItem() map[string]any {
+	item := map[string]any{
+		"type": InputItemTypeAttachment,
+		"kind": string(a.Kind),
+		"path": strings.TrimSpace(a.Path),
+	}
+	if name := strings.TrimSpace(a.Name); name != "" {
+		item["name"] = name
+	}
+	if mimeType := strings.TrimSpace(a.MimeType); mimeType != "" {
+		item["mime_type"] = mimeType
+	}
+	return item
+}
+
+// InputAttachmentFromItem decodes an attachment input item.
+func InputAttachmentFromItem(item map[string]any) (InputAttachment, bool) {
+	if item == nil {
+		return InputAttachment{}, false
+	}
+	if typ, _ := item["type"].(string); typ != InputItemTypeAttachment {
+		return InputAttachment{}, false
+	}
+	path, _ := item["path"].(string)
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return InputAttachment{}, false
+	}
+	attachment := NewInputAttachment(path)
+	if kind, _ := item["kind"].(string); strings.TrimSpace(kind) != "" {
+		attachment.Kind = InputAttachmentKind(strings.TrimSpace(kind))
+	}
+	if name, _ := item["name"].(string); strings.TrimSpace(name) != "" {
+		attachment.Name = strings.TrimSpace(name)
+	}
+	if mimeType, _ := item["mime_type"].(string); strings.TrimSpace(mimeType) != "" {
+		attachment.MimeType = strings.TrimSpace(mimeType)
+	}
+	return attachment, true
+}
+
+// InputAttachments returns the attachments among send input items, in order.
+func InputAttachments(input []map[string]any) []InputAttachment {
+	var out []InputAttachment
+	for _, item := range input {
+		if attachment, ok := InputAttachmentFromItem(item); ok {
+			out = append(out, attachment)
+		}
+	}
+	return out
+}
@@ -0,0 +1,52 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewInputAttachmentClassifiesByExtension(t *testing.T) {
+	image := NewInputAttachment("/tmp/shots/screen.png")
+	if image.Kind != InputAttachmentImage || image.MimeType != "image/png" || image.Name != "screen.png" {
+		t.Fatalf("unexpected image attachment: %#v", image)
+	}
+	log := NewInputAttachment("/var/log/app.log")
+	if log.Kind != InputAttachmentFile || log.MimeType != "text/plain" {
+		t.Fatalf("unexpected log attachment: %#v", log)
+	}
+	unknown := NewInputAttachment("/tmp/blob.zzz")
+	if unknown.Kind != InputAttachmentFile || unknown.MimeType != "application/octet-stream" {
+		t.Fatalf("unexpected unknown attachment: %#v", unknown)
+	}
+}
+
+func TestInputAttachmentItemRoundTripsThroughJSON(t *testing.T) {
+	want := NewInputAttachment("/tmp/shots/screen.png")
+	payload, err := json.Marshal([]map[string]any{
+		{"type": "text", "text": "look"},
+		want.InputItem(),
+	})
+	if err != nil {
+		t.Fatalf("marshal input: %v", err)
+	}
+	var input []map[string]any
+	if err := json.Unmarshal(payload, &input); err != nil {
+		t.Fatalf("unmarshal input: %v", err)
+	}
+	got := InputAttachments(input)
+	if len(got) != 1 || got[0] != want {
+		t.Fatalf("unexpected attachments: %#v", got)
+	}
+	if _, ok := InputAttachmentFromItem(map[string]any{"type": InputItemTypeAttachment}); ok {
+		t.Fatalf("expected attachment without path to be rejected")
+	}
+}
+
+func TestAttachmentNote(t *testing.T) {
+	if got := AttachmentNote(nil); got != "" {
+		t.Fatalf("expected empty note, got %q", got)
+	}
+	if got := AttachmentNote([]string{"a.png", "b.log"}); got != "[attached: a.png, b.log]" {
+		t.Fatalf("unexpected note: %q", got)
+	}
+}
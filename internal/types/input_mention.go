@@ -0,0 +1,155 @@
+package types
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// InputItemTypeMention is the type of send input items that reference a
+// session, note, workflow run or code symbol. The daemon expands them into
+// text context before the message reaches the provider.
+const InputItemTypeMention = "mention"
+
+type InputMentionKind string
+
+const (
+	InputMentionSession InputMentionKind = "session"
+	InputMentionNote    InputMentionKind = "note"
+	InputMentionRun     InputMentionKind = "run"
+	InputMentionSymbol  InputMentionKind = "symbol"
+)
+
+// InputMention is a structured @-mention. ID names the session, note or run;
+// for symbols it is the symbol name and Path and Line locate its definition.
+// Blocks limits how many recent transcript blocks a session mention pulls in.
+type InputMention struct {
+	Kind   InputMentionKind `json:"kind"`
+	ID     string           `json:"id"`
+	Label  string           `json:"label,omitempty"`
+	Path   string           `json:"path,omitempty"`
+	Line   int              `json:"line,omitempty"`
+	Blocks int              `json:"blocks,omitempty"`
+}
+
+// InputMentionPrefix is the word typed after @ for a mention kind.
+func InputMentionPrefix(kind InputMentionKind) string {
+	if kind == InputMentionSymbol {
+		return "sym"
+	}
+	return string(kind)
+}
+
+// InputMentionKindForPrefix is the inverse of InputMentionPrefix.
+func InputMentionKindForPrefix(prefix string) (InputMentionKind, bool) {
+	switch strings.ToLower(strings.TrimSpace(prefix)) {
+	case "session":
+		return InputMentionSession, true
+	case "note":
+		return InputMentionNote, true
+	case "run":
+		return InputMentionRun, true
+	case "sym":
+		return InputMentionSymbol, true
+	}
+	return "", false
+}
+
+// Token is the compact reference left in the message text, e.g.
+// "@session:abc123".
+func (m InputMention) Token() string {
+	return "@" + InputMentionPrefix(m.Kind) + ":" + strings.TrimSpace(m.ID)
+}
+
+// InputItem encodes the mention as a send input item.
+func (m InputMention) InputItem() map[string]any {
+	item := map[string]any{
+		"type": InputItemTypeMention,
+		"kind": string(m.Kind),
+		"id":   strings.TrimSpace(m.ID),
+	}
+	if label := strings.TrimSpace(m.Label); label != "" {
+		item["label"] = label
+	}
+	if path := strings.TrimSpace(m.Path); path != "" {
+		item["path"] = path
+	}
+	if m.Line > 0 {
+		item["line"] = m.Line
+	}
+	if m.Blocks > 0 {
+		item["blocks"] = m.Blocks
+	}
+	return item
+}
+
+// InputMentionFromItem decodes a mention input item.
+func InputMentionFromItem(item map[string]any) (InputMention, bool) {
+	if item == nil {
+		return InputMention{}, false
+	}
+	if typ, _ := item["type"].(string); typ != InputItemTypeMention {
+		return InputMention{}, false
+	}
+	kind, _ := item["kind"].(string)
+	id, _ := item["id"].(string)
+	mention := InputMention{
+		Kind: InputMentionKind(strings.TrimSpace(kind)),
+		ID:   strings.TrimSpace(id),
+	}
+	if mention.Kind == "" || mention.ID == "" {
+		return InputMention{}, false
+	}
+	if label, _ := item["label"].(string); strings.TrimSpace(label) != "" {
+		mention.Label = strings.TrimSpace(label)
+	}
+	if path, _ := item["path"].(string); strings.TrimSpace(path) != "" {
+		mention.Path = strings.TrimSpace(path)
+	}
+	mention.Line = inputItemInt(item["line"])
+	mention.Blocks = inputItemInt(item["blocks"])
+	return mention, true
+}
+
+// InputMentions returns the mentions among send input items, in order.
+func InputMentions(input []map[string]any) []InputMention {
+	var out []InputMention
+	for _, item := range input {
+		if mention, ok := InputMentionFromItem(item); ok {
+			out = append(out, mention)
+		}
+	}
+	return out
+}
+
+func inputItemInt(raw any) int {
+	switch value := raw.(type) {
+	case int:
+		return value
+	case int64:
+		return int(value)
+	case float64:
+		return int(value)
+	case string:
+		parsed, _ := strconv.Atoi(strings.TrimSpace(value))
+		return parsed
+	}
+	return 0
+}
+
+// MentionContext wraps the expanded text of a mention so transcripts can
+// collapse it back to the token the user typed.
+func MentionContext(token, text string) string {
+	return "<archon-mention ref=\"" + token + "\">\n" + strings.TrimSpace(text) + "\n</archon-mention>"
+}
+
+var mentionContextPattern = regexp.MustCompile(`(?s)\s*<archon-mention ref="[^"]*">.*?</archon-mention>`)
+
+// CollapseMentionContext removes expanded mention context from message text,
+// leaving the compact tokens of the typed message.
+func CollapseMentionContext(text string) string {
+	if !strings.Contains(text, "<archon-mention ") {
+		return text
+	}
+	return strings.TrimSpace(mentionContextPattern.ReplaceAllString(text, ""))
+}
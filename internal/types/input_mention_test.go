@@ -0,0 +1,53 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestInputMentionItemRoundTripsThroughJSON(t *testing.T) {
+	want := InputMention{Kind: InputMentionSymbol, ID: "Serve", Label: "func Serve", Path: "/repo/server.go", Line: 42}
+	payload, err := json.Marshal([]map[string]any{
+		{"type": "text", "text": "see @sym:Serve"},
+		want.InputItem(),
+	})
+	if err != nil {
+		t.Fatalf("marshal input: %v", err)
+	}
+	var input []map[string]any
+	if err := json.Unmarshal(payload, &input); err != nil {
+		t.Fatalf("unmarshal input: %v", err)
+	}
+	got := InputMentions(input)
+	if len(got) != 1 || got[0] != want {
+		t.Fatalf("unexpected mentions: %#v", got)
+	}
+	if got[0].Token() != "@sym:Serve" {
+		t.Fatalf("unexpected token: %q", got[0].Token())
+	}
+	if _, ok := InputMentionFromItem(map[string]any{"type": InputItemTypeMention, "kind": "note"}); ok {
+		t.Fatalf("expected mention without id to be rejected")
+	}
+}
+
+func TestInputMentionKindForPrefix(t *testing.T) {
+	for _, kind := range []InputMentionKind{InputMentionSession, InputMentionNote, InputMentionRun, InputMentionSymbol} {
+		got, ok := InputMentionKindForPrefix(InputMentionPrefix(kind))
+		if !ok || got != kind {
+			t.Fatalf("prefix %q: got %q %v", InputMentionPrefix(kind), got, ok)
+		}
+	}
+	if _, ok := InputMentionKindForPrefix("file"); ok {
+		t.Fatalf("expected unknown prefix to be rejected")
+	}
+}
+
+func TestCollapseMentionContext(t *testing.T) {
+	text := "compare with @note:n1\n" + MentionContext("@note:n1", "note body\nsecond line") + "\n" + MentionContext("@run:r1", "status: done")
+	if got := CollapseMentionContext(text); got != "compare with @note:n1" {
+		t.Fatalf("unexpected collapsed text: %q", got)
+	}
+	if got := CollapseMentionContext("plain text"); got != "plain text" {
+		t.Fatalf("expected plain text unchanged, got %q", got)
+	}
+}
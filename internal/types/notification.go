@@ -1,6 +1,11 @@
 package types
 
-import "strings"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type NotificationTrigger string
 
@@ -9,6 +14,20 @@ const (
 	NotificationTriggerSessionExited NotificationTrigger = "session.exited"
 	NotificationTriggerSessionFailed NotificationTrigger = "session.failed"
 	NotificationTriggerSessionKilled NotificationTrigger = "session.killed"
+
+	NotificationTriggerApprovalPending        NotificationTrigger = "approval.pending"
+	NotificationTriggerWorkflowDecisionNeeded NotificationTrigger = "workflow.decision_needed"
+	NotificationTriggerProviderRateLimited    NotificationTrigger = "provider.rate_limited"
+	NotificationTriggerTurnLongRunning        NotificationTrigger = "turn.long_running"
+
+	// Summary triggers are produced by the notification service itself and are
+	// delivered regardless of the configured trigger list.
+	NotificationTriggerBatch  NotificationTrigger = "notification.batch"
+	NotificationTriggerDigest NotificationTrigger = "notification.digest"
+
+	// NotificationTriggerApprovalResolved marks an answered approval. It only
+	// updates the notification service's own state and is never delivered.
+	NotificationTriggerApprovalResolved NotificationTrigger = "approval.resolved"
 )
 
 type NotificationMethod string
@@ -18,24 +37,68 @@ const (
 	NotificationMethodNotifySend NotificationMethod = "notify-send"
 	NotificationMethodDunstify   NotificationMethod = "dunstify"
 	NotificationMethodBell       NotificationMethod = "bell"
+	NotificationMethodWebhook    NotificationMethod = "webhook"
 )
 
+type NotificationWebhookFormat string
+
+const (
+	NotificationWebhookFormatJSON    NotificationWebhookFormat = "json"
+	NotificationWebhookFormatSlack   NotificationWebhookFormat = "slack"
+	NotificationWebhookFormatDiscord NotificationWebhookFormat = "discord"
+)
+
+// NotificationWebhook is one outgoing webhook target. Secret signs the body
+// with HMAC-SHA256; SecretEnv names an environment variable holding the
+// secret so it does not have to be stored alongside the settings.
+type NotificationWebhook struct {
+	URL       string                    `json:"url"`
+	Format    NotificationWebhookFormat `json:"format,omitempty"`
+	Headers   map[string]string         `json:"headers,omitempty"`
+	Secret    string                    `json:"secret,omitempty"`
+	SecretEnv string                    `json:"secret_env,omitempty"`
+}
+
+// NotificationRateLimit caps how many notifications one trigger may deliver
+// within a rolling window; events over the cap are dropped.
+type NotificationRateLimit struct {
+	Trigger       NotificationTrigger `json:"trigger"`
+	Max           int                 `json:"max"`
+	WindowSeconds int                 `json:"window_seconds,omitempty"`
+}
+
 type NotificationSettings struct {
-	Enabled              bool                  `json:"enabled"`
-	Triggers             []NotificationTrigger `json:"triggers,omitempty"`
-	Methods              []NotificationMethod  `json:"methods,omitempty"`
-	ScriptCommands       []string              `json:"script_commands,omitempty"`
-	ScriptTimeoutSeconds int                   `json:"script_timeout_seconds,omitempty"`
-	DedupeWindowSeconds  int                   `json:"dedupe_window_seconds,omitempty"`
+	Enabled               bool                  `json:"enabled"`
+	Triggers              []NotificationTrigger `json:"triggers,omitempty"`
+	Methods               []NotificationMethod  `json:"methods,omitempty"`
+	ScriptCommands        []string              `json:"script_commands,omitempty"`
+	ScriptTimeoutSeconds  int                   `json:"script_timeout_seconds,omitempty"`
+	DedupeWindowSeconds   int                   `json:"dedupe_window_seconds,omitempty"`
+	Webhooks              []NotificationWebhook `json:"webhooks,omitempty"`
+	WebhookRetries        int                   `json:"webhook_retries,omitempty"`
+	WebhookTimeoutSeconds int                   `json:"webhook_timeout_seconds,omitempty"`
+	// QuietHoursStart and QuietHoursEnd are "HH:MM" in the daemon's local time.
+	// The window may wrap midnight; events inside it are not delivered.
+	QuietHoursStart    string                  `json:"quiet_hours_start,omitempty"`
+	QuietHoursEnd      string                  `json:"quiet_hours_end,omitempty"`
+	RateLimits         []NotificationRateLimit `json:"rate_limits,omitempty"`
+	BatchWindowSeconds int                     `json:"batch_window_seconds,omitempty"`
 }
 
 type NotificationSettingsPatch struct {
-	Enabled              *bool                 `json:"enabled,omitempty"`
-	Triggers             []NotificationTrigger `json:"triggers,omitempty"`
-	Methods              []NotificationMethod  `json:"methods,omitempty"`
-	ScriptCommands       []string              `json:"script_commands,omitempty"`
-	ScriptTimeoutSeconds *int                  `json:"script_timeout_seconds,omitempty"`
-	DedupeWindowSeconds  *int                  `json:"dedupe_window_seconds,omitempty"`
+	Enabled               *bool                   `json:"enabled,omitempty"`
+	Triggers              []NotificationTrigger   `json:"triggers,omitempty"`
+	Methods               []NotificationMethod    `json:"methods,omitempty"`
+	ScriptCommands        []string                `json:"script_commands,omitempty"`
+	ScriptTimeoutSeconds  *int                    `json:"script_timeout_seconds,omitempty"`
+	DedupeWindowSeconds   *int                    `json:"dedupe_window_seconds,omitempty"`
+	Webhooks              []NotificationWebhook   `json:"webhooks,omitempty"`
+	WebhookRetries        *int                    `json:"webhook_retries,omitempty"`
+	WebhookTimeoutSeconds *int                    `json:"webhook_timeout_seconds,omitempty"`
+	QuietHoursStart       *string                 `json:"quiet_hours_start,omitempty"`
+	QuietHoursEnd         *string                 `json:"quiet_hours_end,omitempty"`
+	RateLimits            []NotificationRateLimit `json:"rate_limits,omitempty"`
+	BatchWindowSeconds    *int                    `json:"batch_window_seconds,omitempty"`
 }
 
 type NotificationEvent struct {
@@ -50,6 +113,7 @@ type NotificationEvent struct {
 	TurnID      string              `json:"turn_id,omitempty"`
 	Cwd         string              `json:"cwd,omitempty"`
 	Source      string              `json:"source,omitempty"`
+	TraceParent string              `json:"traceparent,omitempty"`
 	Payload     map[string]any      `json:"payload,omitempty"`
 }
 
@@ -61,10 +125,15 @@ func DefaultNotificationSettings() NotificationSettings {
 			NotificationTriggerSessionFailed,
 			NotificationTriggerSessionKilled,
 			NotificationTriggerSessionExited,
+			NotificationTriggerApprovalPending,
+			NotificationTriggerWorkflowDecisionNeeded,
+			NotificationTriggerProviderRateLimited,
 		},
-		Methods:              []NotificationMethod{NotificationMethodAuto},
-		ScriptTimeoutSeconds: 10,
-		DedupeWindowSeconds:  5,
+		Methods:               []NotificationMethod{NotificationMethodAuto},
+		ScriptTimeoutSeconds:  10,
+		DedupeWindowSeconds:   5,
+		WebhookRetries:        3,
+		WebhookTimeoutSeconds: 10,
 	}
 }
 
@@ -79,6 +148,12 @@ func CloneNotificationSettings(in NotificationSettings) NotificationSettings {
 	if in.ScriptCommands != nil {
 		out.ScriptCommands = append([]string{}, in.ScriptCommands...)
 	}
+	if in.Webhooks != nil {
+		out.Webhooks = cloneNotificationWebhooks(in.Webhooks)
+	}
+	if in.RateLimits != nil {
+		out.RateLimits = append([]NotificationRateLimit{}, in.RateLimits...)
+	}
 	return out
 }
 
@@ -108,9 +183,50 @@ func CloneNotificationSettingsPatch(in *NotificationSettingsPatch) *Notification
 		v := *in.DedupeWindowSeconds
 		out.DedupeWindowSeconds = &v
 	}
+	if in.Webhooks != nil {
+		out.Webhooks = cloneNotificationWebhooks(in.Webhooks)
+	}
+	if in.WebhookRetries != nil {
+		v := *in.WebhookRetries
+		out.WebhookRetries = &v
+	}
+	if in.WebhookTimeoutSeconds != nil {
+		v := *in.WebhookTimeoutSeconds
+		out.WebhookTimeoutSeconds = &v
+	}
+	if in.QuietHoursStart != nil {
+		v := *in.QuietHoursStart
+		out.QuietHoursStart = &v
+	}
+	if in.QuietHoursEnd != nil {
+		v := *in.QuietHoursEnd
+		out.QuietHoursEnd = &v
+	}
+	if in.RateLimits != nil {
+		out.RateLimits = append([]NotificationRateLimit{}, in.RateLimits...)
+	}
+	if in.BatchWindowSeconds != nil {
+		v := *in.BatchWindowSeconds
+		out.BatchWindowSeconds = &v
+	}
 	return &out
 }
 
+func cloneNotificationWebhooks(in []NotificationWebhook) []NotificationWebhook {
+	out := make([]NotificationWebhook, 0, len(in))
+	for _, webhook := range in {
+		copy := webhook
+		if webhook.Headers != nil {
+			copy.Headers = make(map[string]string, len(webhook.Headers))
+			for key, value := range webhook.Headers {
+				copy.Headers[key] = value
+			}
+		}
+		out = append(out, copy)
+	}
+	return out
+}
+
 func MergeNotificationSettings(base NotificationSettings, patch *NotificationSettingsPatch) NotificationSettings {
 	out := CloneNotificationSettings(base)
 	if patch == nil {
@@ -134,6 +250,27 @@ func MergeNotificationSettings(base NotificationSettings, patch *NotificationSet
 	if patch.DedupeWindowSeconds != nil {
 		out.DedupeWindowSeconds = *patch.DedupeWindowSeconds
 	}
+	if patch.Webhooks != nil {
+		out.Webhooks = cloneNotificationWebhooks(patch.Webhooks)
+	}
+	if patch.WebhookRetries != nil {
+		out.WebhookRetries = *patch.WebhookRetries
+	}
+	if patch.WebhookTimeoutSeconds != nil {
+		out.WebhookTimeoutSeconds = *patch.WebhookTimeoutSeconds
+	}
+	if patch.QuietHoursStart != nil {
+		out.QuietHoursStart = *patch.QuietHoursStart
+	}
+	if patch.QuietHoursEnd != nil {
+		out.QuietHoursEnd = *patch.QuietHoursEnd
+	}
+	if patch.RateLimits != nil {
+		out.RateLimits = append([]NotificationRateLimit{}, patch.RateLimits...)
+	}
+	if patch.BatchWindowSeconds != nil {
+		out.BatchWindowSeconds = *patch.BatchWindowSeconds
+	}
 	return NormalizeNotificationSettings(out)
 }
 
@@ -154,6 +291,135 @@ func NormalizeNotificationSettings(in NotificationSettings) NotificationSettings
 	if out.DedupeWindowSeconds <= 0 {
 		out.DedupeWindowSeconds = DefaultNotificationSettings().DedupeWindowSeconds
 	}
+	out.Webhooks = normalizeNotificationWebhooks(in.Webhooks)
+	if out.WebhookRetries < 0 {
+		out.WebhookRetries = 0
+	}
+	if out.WebhookRetries > maxNotificationWebhookRetries {
+		out.WebhookRetries = maxNotificationWebhookRetries
+	}
+	if out.WebhookTimeoutSeconds <= 0 {
+		out.WebhookTimeoutSeconds = DefaultNotificationSettings().WebhookTimeoutSeconds
+	}
+	out.QuietHoursStart, out.QuietHoursEnd = normalizeNotificationQuietHours(in.QuietHoursStart, in.QuietHoursEnd)
+	out.RateLimits = normalizeNotificationRateLimits(in.RateLimits)
+	if out.BatchWindowSeconds < 0 {
+		out.BatchWindowSeconds = 0
+	}
+	if out.BatchWindowSeconds > maxNotificationBatchWindowSeconds {
+		out.BatchWindowSeconds = maxNotificationBatchWindowSeconds
+	}
+	return out
+}
+
+const (
+	maxNotificationWebhookRetries             = 10
+	maxNotificationBatchWindowSeconds         = 3600
+	defaultNotificationRateLimitWindowSeconds = 3600
+)
+
+// ParseNotificationClock parses an "HH:MM" time of day into minutes after
+// midnight.
+func ParseNotificationClock(raw string) (int, bool) {
+	hours, minutes, ok := strings.Cut(strings.TrimSpace(raw), ":")
+	if !ok || len(hours) == 0 || len(hours) > 2 || len(minutes) != 2 {
+		return 0, false
+	}
+	h, errH := strconv.Atoi(hours)
+	m, errM := strconv.Atoi(minutes)
+	if errH != nil || errM != nil || h < 0 || h > 23 || m < 0 || m > 59 {
+		return 0, false
+	}
+	return h*60 + m, true
+}
+
+// NotificationQuietHoursActive reports whether at falls inside the quiet-hour
+// window of settings, interpreted in at's location.
+func NotificationQuietHoursActive(settings NotificationSettings, at time.Time) bool {
+	start, okStart := ParseNotificationClock(settings.QuietHoursStart)
+	end, okEnd := ParseNotificationClock(settings.QuietHoursEnd)
+	if !okStart || !okEnd || start == end {
+		return false
+	}
+	minute := at.Hour()*60 + at.Minute()
+	if start < end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+func normalizeNotificationQuietHours(start, end string) (string, string) {
+	startMinute, okStart := ParseNotificationClock(start)
+	endMinute, okEnd := ParseNotificationClock(end)
+	if !okStart || !okEnd || startMinute == endMinute {
+		return "", ""
+	}
+	return formatNotificationClock(startMinute), formatNotificationClock(endMinute)
+}
+
+func formatNotificationClock(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
+
+func normalizeNotificationRateLimits(values []NotificationRateLimit) []NotificationRateLimit {
+	if len(values) == 0 {
+		return nil
+	}
+	index := map[NotificationTrigger]int{}
+	out := make([]NotificationRateLimit, 0, len(values))
+	for _, value := range values {
+		trigger, ok := NormalizeNotificationTrigger(string(value.Trigger))
+		if !ok || value.Max <= 0 {
+			continue
+		}
+		value.Trigger = trigger
+		if value.WindowSeconds <= 0 {
+			value.WindowSeconds = defaultNotificationRateLimitWindowSeconds
+		}
+		if existing, ok := index[trigger]; ok {
+			out[existing] = value
+			continue
+		}
+		index[trigger] = len(out)
+		out = append(out, value)
+	}
+	return out
+}
+
+// NotificationRateLimitFor returns the rate limit configured for trigger.
+func NotificationRateLimitFor(settings NotificationSettings, trigger NotificationTrigger) (NotificationRateLimit, bool) {
+	for _, limit := range settings.RateLimits {
+		if limit.Trigger == trigger {
+			return limit, true
+		}
+	}
+	return NotificationRateLimit{}, false
+}
+
+func normalizeNotificationWebhooks(values []NotificationWebhook) []NotificationWebhook {
+	if len(values) == 0 {
+		return nil
+	}
+	seen := map[string]struct{}{}
+	out := make([]NotificationWebhook, 0, len(values))
+	for _, value := range cloneNotificationWebhooks(values) {
+		value.URL = strings.TrimSpace(value.URL)
+		if value.URL == "" {
+			continue
+		}
+		format, ok := NormalizeNotificationWebhookFormat(string(value.Format))
+		if !ok {
+			format = NotificationWebhookFormatJSON
+		}
+		value.Format = format
+		value.SecretEnv = strings.TrimSpace(value.SecretEnv)
+		key := value.URL + "\x00" + string(value.Format)
+		if _, exists := seen[key]; exists {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, value)
+	}
 	return out
 }
 
@@ -227,6 +493,14 @@ func NormalizeNotificationTrigger(raw string) (NotificationTrigger, bool) {
 		return NotificationTriggerSessionFailed, true
 	case "session.killed", "session_killed", "session-killed":
 		return NotificationTriggerSessionKilled, true
+	case "approval.pending", "approval_pending", "approval-pending", "approval.required", "approval_required":
+		return NotificationTriggerApprovalPending, true
+	case "workflow.decision_needed", "workflow_decision_needed", "workflow-decision-needed", "decision_needed":
+		return NotificationTriggerWorkflowDecisionNeeded, true
+	case "provider.rate_limited", "provider_rate_limited", "rate_limited", "rate-limited":
+		return NotificationTriggerProviderRateLimited, true
+	case "turn.long_running", "turn_long_running", "turn-long-running", "turn.idle", "idle":
+		return NotificationTriggerTurnLongRunning, true
 	default:
 		return "", false
 	}
@@ -242,6 +516,21 @@ func NormalizeNotificationMethod(raw string) (NotificationMethod, bool) {
 		return NotificationMethodDunstify, true
 	case "bell", "terminal-bell", "terminal_bell":
 		return NotificationMethodBell, true
+	case "webhook", "webhooks":
+		return NotificationMethodWebhook, true
+	default:
+		return "", false
+	}
+}
+
+func NormalizeNotificationWebhookFormat(raw string) (NotificationWebhookFormat, bool) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", "json", "event":
+		return NotificationWebhookFormatJSON, true
+	case "slack":
+		return NotificationWebhookFormatSlack, true
+	case "discord":
+		return NotificationWebhookFormatDiscord, true
 	default:
 		return "", false
 	}
@@ -255,3 +544,21 @@ func NotificationTriggerEnabled(settings NotificationSettings, trigger Notificat
 	}
 	return false
 }
+
+// NotificationTestRequest asks the daemon to deliver a sample event using the
+// settings that would apply to the given scope.
+type NotificationTestRequest struct {
+	Trigger     NotificationTrigger `json:"trigger,omitempty"`
+	WorkspaceID string              `json:"workspace_id,omitempty"`
+	WorktreeID  string              `json:"worktree_id,omitempty"`
+	SessionID   string              `json:"session_id,omitempty"`
+}
+
+type NotificationTestResult struct {
+	Delivered      bool                 `json:"delivered"`
+	Event          NotificationEvent    `json:"event"`
+	Methods        []NotificationMethod `json:"methods,omitempty"`
+	Webhooks       int                  `json:"webhooks,omitempty"`
+	ScriptCommands int                  `json:"script_commands,omitempty"`
+	Error          string               `json:"error,omitempty"`
+}
@@ -1,6 +1,9 @@
 package types
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestMergeNotificationSettingsAppliesPatch(t *testing.T) {
 	base := DefaultNotificationSettings()
@@ -54,3 +57,104 @@ func TestNormalizeNotificationSettingsFallsBackToDefaults(t *testing.T) {
 		t.Fatalf("expected default dedupe window")
 	}
 }
+
+func TestMergeNotificationSettingsReplacesWebhooks(t *testing.T) {
+	base := DefaultNotificationSettings()
+	base.Webhooks = []NotificationWebhook{{URL: "https://global.example.com/hook"}}
+	retries := 1
+	got := MergeNotificationSettings(base, &NotificationSettingsPatch{
+		Methods: []NotificationMethod{"webhooks"},
+		Webhooks: []NotificationWebhook{
+			{URL: " https://hooks.slack.com/services/x ", Format: "Slack", Headers: map[string]string{"X-Team": "core"}},
+			{URL: "https://hooks.slack.com/services/x", Format: "slack"},
+			{URL: "   "},
+			{URL: "https://example.com/raw", Format: "bogus"},
+		},
+		WebhookRetries: &retries,
+	})
+	if len(got.Methods) != 1 || got.Methods[0] != NotificationMethodWebhook {
+		t.Fatalf("unexpected methods: %#v", got.Methods)
+	}
+	if len(got.Webhooks) != 2 {
+		t.Fatalf("expected deduped webhooks, got %#v", got.Webhooks)
+	}
+	if got.Webhooks[0].URL != "https://hooks.slack.com/services/x" || got.Webhooks[0].Format != NotificationWebhookFormatSlack {
+		t.Fatalf("unexpected slack webhook: %#v", got.Webhooks[0])
+	}
+	if got.Webhooks[1].Format != NotificationWebhookFormatJSON {
+		t.Fatalf("expected unknown format to fall back to json, got %#v", got.Webhooks[1])
+	}
+	if got.WebhookRetries != 1 || got.WebhookTimeoutSeconds != 10 {
+		t.Fatalf("unexpected webhook retry settings: %d/%d", got.WebhookRetries, got.WebhookTimeoutSeconds)
+	}
+	if base.Webhooks[0].URL != "https://global.example.com/hook" {
+		t.Fatalf("expected base settings to stay untouched")
+	}
+}
+
+func TestNormalizeNotificationTriggerAcceptsAttentionTriggers(t *testing.T) {
+	cases := map[string]NotificationTrigger{
+		"approval_pending":         NotificationTriggerApprovalPending,
+		"workflow.decision_needed": NotificationTriggerWorkflowDecisionNeeded,
+		"rate-limited":             NotificationTriggerProviderRateLimited,
+		"idle":                     NotificationTriggerTurnLongRunning,
+	}
+	for raw, want := range cases {
+		got, ok := NormalizeNotificationTrigger(raw)
+		if !ok || got != want {
+			t.Fatalf("NormalizeNotificationTrigger(%q) = %q, %v; want %q", raw, got, ok, want)
+		}
+	}
+	if NotificationTriggerEnabled(DefaultNotificationSettings(), NotificationTriggerTurnLongRunning) {
+		t.Fatalf("expected long-running turn notifications to be opt-in")
+	}
+}
+
+func TestNotificationQuietHoursActiveWrapsMidnight(t *testing.T) {
+	start := " 22:00"
+	end := "7:30"
+	settings := MergeNotificationSettings(DefaultNotificationSettings(), &NotificationSettingsPatch{
+		QuietHoursStart: &start,
+		QuietHoursEnd:   &end,
+	})
+	if settings.QuietHoursStart != "22:00" || settings.QuietHoursEnd != "07:30" {
+		t.Fatalf("expected canonical quiet hours, got %q-%q", settings.QuietHoursStart, settings.QuietHoursEnd)
+	}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 3, 1, hour, minute, 0, 0, time.Local)
+	}
+	if !NotificationQuietHoursActive(settings, at(23, 15)) || !NotificationQuietHoursActive(settings, at(6, 0)) {
+		t.Fatalf("expected quiet hours to span midnight")
+	}
+	if NotificationQuietHoursActive(settings, at(7, 30)) || NotificationQuietHoursActive(settings, at(12, 0)) {
+		t.Fatalf("expected notifications outside quiet hours")
+	}
+	invalid := "25:00"
+	settings = MergeNotificationSettings(settings, &NotificationSettingsPatch{QuietHoursStart: &invalid})
+	if settings.QuietHoursStart != "" || NotificationQuietHoursActive(settings, at(23, 15)) {
+		t.Fatalf("expected invalid quiet hours to disable the window, got %#v", settings)
+	}
+}
+
+func TestMergeNotificationSettingsNormalizesRateLimitsAndBatchWindow(t *testing.T) {
+	batch := 99999
+	got := MergeNotificationSettings(DefaultNotificationSettings(), &NotificationSettingsPatch{
+		RateLimits: []NotificationRateLimit{
+			{Trigger: "turn_completed", Max: 5},
+			{Trigger: "bogus", Max: 1},
+			{Trigger: NotificationTriggerSessionFailed, Max: 0},
+			{Trigger: NotificationTriggerTurnCompleted, Max: 2, WindowSeconds: 60},
+		},
+		BatchWindowSeconds: &batch,
+	})
+	if len(got.RateLimits) != 1 {
+		t.Fatalf("expected one valid rate limit, got %#v", got.RateLimits)
+	}
+	limit, ok := NotificationRateLimitFor(got, NotificationTriggerTurnCompleted)
+	if !ok || limit.Max != 2 || limit.WindowSeconds != 60 {
+		t.Fatalf("expected last turn.completed limit to win, got %#v (%v)", limit, ok)
+	}
+	if got.BatchWindowSeconds != 3600 {
+		t.Fatalf("expected batch window clamp, got %d", got.BatchWindowSeconds)
+	}
+}
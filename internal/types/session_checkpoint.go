@@ -0,0 +1,41 @@
+package types
+
+import "time"
+
+// SessionCheckpoint is a snapshot of a session's working tree taken when a
+// turn starts. Snapshots are hidden commits under refs/archon/checkpoints in
+// the session's repository; they never move the checked-out branch or the
+// user's index. Turn is the 1-based ordinal of the turn within the session;
+// checkpoints taken before a restore have no turn and carry a Label instead.
+type SessionCheckpoint struct {
+	ID        string    `json:"id"`
+	SessionID string    `json:"session_id"`
+	TurnID    string    `json:"turn_id,omitempty"`
+	Turn      int       `json:"turn,omitempty"`
+	Label     string    `json:"label,omitempty"`
+	Commit    string    `json:"commit"`
+	Root      string    `json:"root"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SessionCheckpointRestoreRequest controls a checkpoint restore. Notify
+// sends the session a message describing what was reverted. Force restores
+// even when other active sessions work in the same repository, whose
+// changes the restore would also undo.
+type SessionCheckpointRestoreRequest struct {
+	Notify bool `json:"notify,omitempty"`
+	Force  bool `json:"force,omitempty"`
+}
+
+// SessionCheckpointRestoreResult reports a restore. Reverted lists files
+// rewritten to their checkpoint content, Removed lists files that did not
+// exist at the checkpoint. BackupID names the checkpoint of the tree as it
+// was before the restore, so the restore itself can be undone.
+type SessionCheckpointRestoreResult struct {
+	Checkpoint  *SessionCheckpoint `json:"checkpoint"`
+	BackupID    string             `json:"backup_id,omitempty"`
+	Reverted    []string           `json:"reverted,omitempty"`
+	Removed     []string           `json:"removed,omitempty"`
+	Notified    bool               `json:"notified,omitempty"`
+	NotifyError string             `json:"notify_error,omitempty"`
+}
@@ -17,4 +17,5 @@ type SessionMeta struct {
 	RuntimeOptions        *SessionRuntimeOptions     `json:"runtime_options,omitempty"`
 	NotificationOverrides *NotificationSettingsPatch `json:"notification_overrides,omitempty"`
 	LastActiveAt          *time.Time                 `json:"last_active_at,omitempty"`
+	Instructions          *SessionInstructions       `json:"instructions,omitempty"`
 }
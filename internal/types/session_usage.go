@@ -0,0 +1,37 @@
+package types
+
+import "time"
+
+// SessionTokenUsage counts the tokens a session consumed. Input includes
+// CachedInput; Output includes Reasoning.
+type SessionTokenUsage struct {
+	Input       int64 `json:"input,omitempty"`
+	CachedInput int64 `json:"cached_input,omitempty"`
+	Output      int64 `json:"output,omitempty"`
+	Reasoning   int64 `json:"reasoning,omitempty"`
+	Total       int64 `json:"total,omitempty"`
+}
+
+// SessionUsage is the daemon's running account of a session's token use and
+// spend, as reported by its provider. ContextTokens is what the last request
+// put into the model's context window and ContextWindow the size of that
+// window; either is zero when the provider did not report it. CostUSD is nil
+// for providers that do not report spend.
+type SessionUsage struct {
+	SessionID     string            `json:"session_id"`
+	Provider      string            `json:"provider,omitempty"`
+	Tokens        SessionTokenUsage `json:"tokens"`
+	ContextTokens int64             `json:"context_tokens,omitempty"`
+	ContextWindow int64             `json:"context_window,omitempty"`
+	CostUSD       *float64          `json:"cost_usd,omitempty"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+// ContextUsedPercent returns how full the context window is, or false when
+// the provider did not report the window.
+func (u *SessionUsage) ContextUsedPercent() (float64, bool) {
+	if u == nil || u.ContextWindow <= 0 || u.ContextTokens <= 0 {
+		return 0, false
+	}
+	return float64(u.ContextTokens) * 100 / float64(u.ContextWindow), true
+}
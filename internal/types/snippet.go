@@ -0,0 +1,84 @@
+package types
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+type SnippetScope string
+
+const (
+	SnippetScopeGlobal    SnippetScope = "global"
+	SnippetScopeWorkspace SnippetScope = "workspace"
+)
+
+type SnippetSource string
+
+const (
+	SnippetSourceUser     SnippetSource = "user"
+	SnippetSourceWorkflow SnippetSource = "workflow"
+)
+
+// Snippet is a reusable prompt. Its body may contain {{var}} placeholders
+// that are filled in when the snippet is inserted.
+type Snippet struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Body        string        `json:"body"`
+	Scope       SnippetScope  `json:"scope"`
+	WorkspaceID string        `json:"workspace_id,omitempty"`
+	Source      SnippetSource `json:"source,omitempty"`
+	Variables   []string      `json:"variables,omitempty"`
+	CreatedAt   time.Time     `json:"created_at,omitempty"`
+	UpdatedAt   time.Time     `json:"updated_at,omitempty"`
+}
+
+var snippetVariablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.-]*)\s*\}\}`)
+
+// SnippetVariables returns the placeholder names in body in order of first
+// use.
+func SnippetVariables(body string) []string {
+	var out []string
+	seen := map[string]struct{}{}
+	for _, match := range snippetVariablePattern.FindAllStringSubmatch(body, -1) {
+		name := match[1]
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		out = append(out, name)
+	}
+	return out
+}
+
+// RenderSnippet fills placeholders in body from values. Placeholders
+// without a value are left in place and reported as missing.
+func RenderSnippet(body string, values map[string]string) (string, []string) {
+	var missing []string
+	seen := map[string]struct{}{}
+	rendered := snippetVariablePattern.ReplaceAllStringFunc(body, func(placeholder string) string {
+		name := snippetVariablePattern.FindStringSubmatch(placeholder)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			missing = append(missing, name)
+		}
+		return placeholder
+	})
+	return rendered, missing
+}
+
+// SnippetLabel returns the name a snippet is picked by.
+func SnippetLabel(snippet *Snippet) string {
+	if snippet == nil {
+		return ""
+	}
+	if name := strings.TrimSpace(snippet.Name); name != "" {
+		return name
+	}
+	return snippet.ID
+}
@@ -0,0 +1,24 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSnippetVariablesInFirstUseOrder(t *testing.T) {
+	got := SnippetVariables("Review {{ file }} for {{concern}}, then fix {{file}}. {{ not a var }}")
+	want := []string{"file", "concern"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestRenderSnippetReportsMissingValues(t *testing.T) {
+	text, missing := RenderSnippet("Audit {{target}} against {{rules}} and {{rules}}", map[string]string{"target": "api.go"})
+	if text != "Audit api.go against {{rules}} and {{rules}}" {
+		t.Fatalf("unexpected render: %q", text)
+	}
+	if !reflect.DeepEqual(missing, []string{"rules"}) {
+		t.Fatalf("unexpected missing: %v", missing)
+	}
+}
@@ -3,20 +3,32 @@ package types
 import "time"
 
 type Workspace struct {
-	ID                    string    `json:"id"`
-	Name                  string    `json:"name"`
-	RepoPath              string    `json:"repo_path"`
-	SessionSubpath        string    `json:"session_subpath,omitempty"`
-	AdditionalDirectories []string  `json:"additional_directories,omitempty"`
-	GroupIDs              []string  `json:"group_ids,omitempty"`
-	CreatedAt             time.Time `json:"created_at"`
-	UpdatedAt             time.Time `json:"updated_at"`
+	ID                    string       `json:"id"`
+	Name                  string       `json:"name"`
+	RepoPath              string       `json:"repo_path"`
+	SessionSubpath        string       `json:"session_subpath,omitempty"`
+	AdditionalDirectories []string     `json:"additional_directories,omitempty"`
+	GroupIDs              []string     `json:"group_ids,omitempty"`
+	IsolateSessions       bool         `json:"isolate_sessions,omitempty"`
+	CleanupIsolated       bool         `json:"cleanup_isolated,omitempty"`
+	EnvProfiles           []EnvProfile `json:"env_profiles,omitempty"`
+	EnvProfile            string       `json:"env_profile,omitempty"`
+	Instructions          string       `json:"instructions,omitempty"`
+	ContextFiles          []string     `json:"context_files,omitempty"`
+	CreatedAt             time.Time    `json:"created_at"`
+	UpdatedAt             time.Time    `json:"updated_at"`
 }
 
 type WorkspacePatch struct {
-	Name                  *string   `json:"name,omitempty"`
-	RepoPath              *string   `json:"repo_path,omitempty"`
-	SessionSubpath        *string   `json:"session_subpath,omitempty"`
-	AdditionalDirectories *[]string `json:"additional_directories,omitempty"`
-	GroupIDs              *[]string `json:"group_ids,omitempty"`
+	Name                  *string       `json:"name,omitempty"`
+	RepoPath              *string       `json:"repo_path,omitempty"`
+	SessionSubpath        *string       `json:"session_subpath,omitempty"`
+	AdditionalDirectories *[]string     `json:"additional_directories,omitempty"`
+	GroupIDs              *[]string     `json:"group_ids,omitempty"`
+	IsolateSessions       *bool         `json:"isolate_sessions,omitempty"`
+	CleanupIsolated       *bool         `json:"cleanup_isolated,omitempty"`
+	EnvProfiles           *[]EnvProfile `json:"env_profiles,omitempty"`
+	EnvProfile            *string       `json:"env_profile,omitempty"`
+	Instructions          *string       `json:"instructions,omitempty"`
+	ContextFiles          *[]string     `json:"context_files,omitempty"`
 }
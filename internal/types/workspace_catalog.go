@@ -0,0 +1,108 @@
+package types
+
+import (
+	"sort"
+	"strings"
+)
+
+// WorkspaceExportVersion is the current version of the workspace export
+// format.
+const WorkspaceExportVersion = 1
+
+// WorkspaceExport is a shareable snapshot of workspaces, their worktrees and
+// groups. IDs only link records within the export; importing assigns new IDs
+// and matches groups by name and workspaces by repo path. Paths under the
+// exporting user's home directory are written as "~/...". Webhook secrets and
+// headers are left out of an export; secret_env references are kept.
+type WorkspaceExport struct {
+	Version    int               `json:"version"`
+	Groups     []*WorkspaceGroup `json:"groups,omitempty"`
+	Workspaces []*Workspace      `json:"workspaces,omitempty"`
+	Worktrees  []*Worktree       `json:"worktrees,omitempty"`
+}
+
+// WorkspaceImportResult lists the records an import created and the entries
+// it skipped, with the reason for each. Commands lists the imported entries
+// that run a shell command on this machine: cmd: environment variables and
+// notification scripts.
+type WorkspaceImportResult struct {
+	Groups     []*WorkspaceGroup `json:"groups,omitempty"`
+	Workspaces []*Workspace      `json:"workspaces,omitempty"`
+	Worktrees  []*Worktree       `json:"worktrees,omitempty"`
+	Commands   []string          `json:"commands,omitempty"`
+	LocalReads []string          `json:"local_reads,omitempty"`
+	Skipped    []string          `json:"skipped,omitempty"`
+}
+
+type WorkspaceScanRequest struct {
+	Root     string `json:"root"`
+	MaxDepth int    `json:"max_depth,omitempty"`
+}
+
+// WorkspaceScanCandidate is a git repository found by a scan, proposed as a
+// workspace. Group is the name of the repository's parent directory when it
+// is below the scan root; GroupID is set when a group of that name exists.
+// ExistingWorkspaceID is set when the repository is already registered.
+type WorkspaceScanCandidate struct {
+	Name                string         `json:"name"`
+	RepoPath            string         `json:"repo_path"`
+	Group               string         `json:"group,omitempty"`
+	GroupID             string         `json:"group_id,omitempty"`
+	ExistingWorkspaceID string         `json:"existing_workspace_id,omitempty"`
+	Worktrees           []*GitWorktree `json:"worktrees,omitempty"`
+}
+
+type WorkspaceScanResult struct {
+	Root       string                    `json:"root"`
+	Candidates []*WorkspaceScanCandidate `json:"candidates,omitempty"`
+}
+
+// Export converts the candidates that are not registered yet into an export
+// that can be imported as is.
+func (r *WorkspaceScanResult) Export() *WorkspaceExport {
+	out := &WorkspaceExport{Version: WorkspaceExportVersion}
+	if r == nil {
+		return out
+	}
+	groups := map[string]string{}
+	for _, candidate := range r.Candidates {
+		if candidate == nil || candidate.ExistingWorkspaceID != "" {
+			continue
+		}
+		ws := &Workspace{ID: candidate.RepoPath, Name: candidate.Name, RepoPath: candidate.RepoPath}
+		if name := strings.TrimSpace(candidate.Group); name != "" {
+			id, ok := groups[name]
+			if !ok {
+				id = "group:" + name
+				groups[name] = id
+				out.Groups = append(out.Groups, &WorkspaceGroup{ID: id, Name: name})
+			}
+			ws.GroupIDs = []string{id}
+		}
+		out.Workspaces = append(out.Workspaces, ws)
+		for _, wt := range candidate.Worktrees {
+			if wt == nil {
+				continue
+			}
+			out.Worktrees = append(out.Worktrees, &Worktree{
+				WorkspaceID: ws.ID,
+				Name:        worktreeCandidateName(wt),
+				Path:        wt.Path,
+				Branch:      wt.Branch,
+			})
+		}
+	}
+	sort.SliceStable(out.Groups, func(i, j int) bool { return out.Groups[i].Name < out.Groups[j].Name })
+	return out
+}
+
+func worktreeCandidateName(wt *GitWorktree) string {
+	if branch := strings.TrimSpace(wt.Branch); branch != "" {
+		return branch
+	}
+	path := strings.TrimRight(wt.Path, `/\`)
+	if idx := strings.LastIndexAny(path, `/\`); idx >= 0 {
+		return path[idx+1:]
+	}
+	return path
+}
@@ -0,0 +1,58 @@
+package types
+
+import "strings"
+
+// InstructionStrategy is how a provider receives a workspace's standing
+// instructions and context files.
+type InstructionStrategy string
+
+const (
+	// InstructionStrategyNativeFlag passes the instructions through the
+	// provider's own system prompt option.
+	InstructionStrategyNativeFlag InstructionStrategy = "native_flag"
+	// InstructionStrategyFile writes the instructions to a file and points
+	// the provider process at it.
+	InstructionStrategyFile InstructionStrategy = "file"
+	// InstructionStrategyPromptPrefix prepends the instructions to the first
+	// turn of the session.
+	InstructionStrategyPromptPrefix InstructionStrategy = "prompt_prefix"
+)
+
+// SessionInstructions records the workspace instructions applied to a
+// session. ContextFiles lists the files included, Missing those that could
+// not be read. Pending is set while a prompt-prefix strategy waits for the
+// session's first turn.
+type SessionInstructions struct {
+	Strategy     InstructionStrategy `json:"strategy"`
+	Instructions bool                `json:"instructions,omitempty"`
+	ContextFiles []string            `json:"context_files,omitempty"`
+	Missing      []string            `json:"missing,omitempty"`
+	Path         string              `json:"path,omitempty"`
+	Pending      bool                `json:"pending,omitempty"`
+}
+
+func CloneSessionInstructions(in *SessionInstructions) *SessionInstructions {
+	if in == nil {
+		return nil
+	}
+	out := *in
+	out.ContextFiles = append([]string(nil), in.ContextFiles...)
+	out.Missing = append([]string(nil), in.Missing...)
+	return &out
+}
+
+// NormalizeContextFiles trims the entries of a workspace's context file list
+// and drops empty and repeated ones, keeping the original order.
+func NormalizeContextFiles(files []string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, file := range files {
+		file = strings.TrimSpace(file)
+		if file == "" || seen[file] {
+			continue
+		}
+		seen[file] = true
+		out = append(out, file)
+	}
+	return out
+}
@@ -7,7 +7,26 @@ type Worktree struct {
 	WorkspaceID           string                     `json:"workspace_id"`
 	Name                  string                     `json:"name"`
 	Path                  string                     `json:"path"`
+	Branch                string                     `json:"branch,omitempty"`
+	Isolated              bool                       `json:"isolated,omitempty"`
 	NotificationOverrides *NotificationSettingsPatch `json:"notification_overrides,omitempty"`
+	EnvProfile            string                     `json:"env_profile,omitempty"`
+	LinkedWorktrees       []LinkedWorktree           `json:"linked_worktrees,omitempty"`
 	CreatedAt             time.Time                  `json:"created_at"`
 	UpdatedAt             time.Time                  `json:"updated_at"`
 }
+
+// LinkedWorktree is the checkout of a workspace's linked repository that was
+// created alongside a worktree, on the same branch.
+type LinkedWorktree struct {
+	RepoPath string `json:"repo_path"`
+	Path     string `json:"path"`
+	Branch   string `json:"branch,omitempty"`
+}
+
+func CloneLinkedWorktrees(in []LinkedWorktree) []LinkedWorktree {
+	if len(in) == 0 {
+		return nil
+	}
+	return append([]LinkedWorktree(nil), in...)
+}